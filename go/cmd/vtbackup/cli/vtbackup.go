@@ -396,6 +396,7 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 		Stats:                backupstats.BackupStats(),
 		UpgradeSafe:          upgradeSafe,
 		MysqlShutdownTimeout: mysqlShutdownTimeout,
+		DbName:               dbName,
 	}
 	// In initial_backup mode, just take a backup of this empty database.
 	if initialBackup {
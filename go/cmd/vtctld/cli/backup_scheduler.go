@@ -0,0 +1,54 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"time"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vtctl/backupscheduler"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+)
+
+var (
+	backupSchedulerEnabled bool
+	backupSchedulerConfig  = backupscheduler.Config{
+		CheckInterval: time.Minute,
+		RetryInterval: 15 * time.Minute,
+		BackupTimeout: 12 * time.Hour,
+	}
+)
+
+func init() {
+	Main.Flags().BoolVar(&backupSchedulerEnabled, "backup-scheduler-enabled", backupSchedulerEnabled, "Take the backups of the keyspaces and shards that have a backup schedule. Several vtctlds can run the backup scheduler.")
+	Main.Flags().DurationVar(&backupSchedulerConfig.CheckInterval, "backup-scheduler-check-interval", backupSchedulerConfig.CheckInterval, "How often the backup scheduler checks which backups are due. This value must be positive; if zero or lower, the default of 1m is used.")
+	Main.Flags().DurationVar(&backupSchedulerConfig.RetryInterval, "backup-scheduler-retry-interval", backupSchedulerConfig.RetryInterval, "How long the backup scheduler waits before retrying a failed backup.")
+	Main.Flags().DurationVar(&backupSchedulerConfig.BackupTimeout, "backup-scheduler-backup-timeout", backupSchedulerConfig.BackupTimeout, "How long a scheduled backup can run before it is canceled and considered failed.")
+}
+
+func initBackupScheduler() {
+	if !backupSchedulerEnabled {
+		return
+	}
+	if backupSchedulerConfig.CheckInterval <= 0 {
+		backupSchedulerConfig.CheckInterval = time.Minute
+	}
+
+	scheduler := backupscheduler.New(ts, tmclient.NewTabletManagerClient(), backupSchedulerConfig)
+	scheduler.Open()
+	servenv.OnClose(scheduler.Close)
+}
@@ -19,12 +19,16 @@ package cli
 import (
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+
+	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
 )
 
 func init() {
 	servenv.OnRun(func() {
 		if servenv.GRPCCheckServiceMap("vtctld") {
-			grpcvtctldserver.StartServer(servenv.GRPCServer, env, ts)
+			server := grpcvtctldserver.NewVtctldServer(env, ts)
+			vtctlservicepb.RegisterVtctldServer(servenv.GRPCServer, server)
+			initWorkflowProgressSampler(server.WorkflowServer())
 		}
 	})
 }
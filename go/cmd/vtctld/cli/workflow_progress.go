@@ -0,0 +1,47 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"time"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vtctl/workflow"
+)
+
+var (
+	workflowProgressSamplingInterval = time.Minute
+	workflowProgressRetention        = 10 * time.Minute
+)
+
+func init() {
+	Main.Flags().DurationVar(&workflowProgressSamplingInterval, "workflow-progress-sampling-interval", workflowProgressSamplingInterval, "How often the progress of the workflows is sampled to forecast their completion. If zero or lower, the progress is only sampled when the status of a workflow is requested.")
+	Main.Flags().DurationVar(&workflowProgressRetention, "workflow-progress-retention", workflowProgressRetention, "How long the progress samples of the workflows are kept. The rates and trends of a workflow are computed over this period, which is at least twice the sampling interval.")
+}
+
+func initWorkflowProgressSampler(ws *workflow.Server) {
+	if workflowProgressSamplingInterval <= 0 {
+		return
+	}
+	if workflowProgressRetention < 2*workflowProgressSamplingInterval {
+		workflowProgressRetention = 2 * workflowProgressSamplingInterval
+	}
+
+	sampler := workflow.NewProgressSampler(ws, workflowProgressSamplingInterval, workflowProgressRetention)
+	sampler.Open()
+	servenv.OnClose(sampler.Close)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// DeleteBackupSchedule makes a DeleteBackupSchedule gRPC call to a vtctld.
+	DeleteBackupSchedule = &cobra.Command{
+		Use:                   "DeleteBackupSchedule <keyspace | keyspace/shard>",
+		Short:                 "Deletes the backup schedule of a keyspace, or the backup schedule override of a shard.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandDeleteBackupSchedule,
+	}
+	// GetBackupSchedules makes a GetBackupSchedules gRPC call to a vtctld.
+	GetBackupSchedules = &cobra.Command{
+		Use:   "GetBackupSchedules [<keyspace>]",
+		Short: "Outputs a JSON structure that contains the backup schedules, and the outcome of the scheduled backups of every shard.",
+		Long: `Outputs a JSON structure that contains the backup schedules, and the outcome of the scheduled backups of every shard.
+
+The outcome of a shard includes the last full and incremental backups started by the vtctld backup scheduler, the tablet
+they were taken on, and their error if they failed. A backup without a finish time is still running.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MaximumNArgs(1),
+		RunE:                  commandGetBackupSchedules,
+	}
+	// SetBackupSchedule makes a SetBackupSchedule gRPC call to a vtctld.
+	SetBackupSchedule = &cobra.Command{
+		Use:   "SetBackupSchedule [--full-backup-interval <duration>] [--incremental-backup-interval <duration>] [--tablet-type <type>] [--cell <cell>] [--max-concurrent-backups-per-cell <n>] [--concurrency <n>] [--backup-engine <engine>] [--paused] <keyspace | keyspace/shard>",
+		Short: "Creates or replaces the backup schedule of a keyspace, or the backup schedule override of a shard.",
+		Long: `Creates or replaces the backup schedule of a keyspace, or the backup schedule override of a shard.
+
+The backups are taken by vtctlds running with --backup-scheduler-enabled. The schedule of a keyspace applies to all its
+shards, unless a shard has its own schedule. Backups are taken on healthy REPLICA, RDONLY or SPARE tablets, except on a
+REPLICA or RDONLY tablet that is the only serving tablet of its type in its cell.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandSetBackupSchedule,
+	}
+)
+
+// parseBackupScheduleTarget parses a keyspace, or a keyspace/shard.
+func parseBackupScheduleTarget(arg string) (keyspace string, shard string, err error) {
+	if !strings.Contains(arg, "/") {
+		return arg, "", nil
+	}
+	return topoproto.ParseKeyspaceShard(arg)
+}
+
+func commandDeleteBackupSchedule(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := parseBackupScheduleTarget(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	_, err = client.DeleteBackupSchedule(commandCtx, &vtctldatapb.DeleteBackupScheduleRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+	})
+	return err
+}
+
+func commandGetBackupSchedules(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetBackupSchedules(commandCtx, &vtctldatapb.GetBackupSchedulesRequest{
+		Keyspace: cmd.Flags().Arg(0),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+var setBackupScheduleOptions = struct {
+	FullBackupInterval          time.Duration
+	IncrementalBackupInterval   time.Duration
+	TabletType                  topodatapb.TabletType
+	Cell                        string
+	MaxConcurrentBackupsPerCell int32
+	Concurrency                 int32
+	BackupEngine                string
+	Paused                      bool
+}{}
+
+func commandSetBackupSchedule(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := parseBackupScheduleTarget(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	schedule := &topodatapb.BackupSchedule{
+		TabletType:                  setBackupScheduleOptions.TabletType,
+		Cell:                        setBackupScheduleOptions.Cell,
+		MaxConcurrentBackupsPerCell: setBackupScheduleOptions.MaxConcurrentBackupsPerCell,
+		Concurrency:                 setBackupScheduleOptions.Concurrency,
+		BackupEngine:                setBackupScheduleOptions.BackupEngine,
+		Paused:                      setBackupScheduleOptions.Paused,
+	}
+	if setBackupScheduleOptions.FullBackupInterval != 0 {
+		schedule.FullBackupInterval = protoutil.DurationToProto(setBackupScheduleOptions.FullBackupInterval)
+	}
+	if setBackupScheduleOptions.IncrementalBackupInterval != 0 {
+		schedule.IncrementalBackupInterval = protoutil.DurationToProto(setBackupScheduleOptions.IncrementalBackupInterval)
+	}
+
+	_, err = client.SetBackupSchedule(commandCtx, &vtctldatapb.SetBackupScheduleRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+		Schedule: schedule,
+	})
+	return err
+}
+
+func init() {
+	Root.AddCommand(DeleteBackupSchedule)
+
+	Root.AddCommand(GetBackupSchedules)
+
+	SetBackupSchedule.Flags().DurationVar(&setBackupScheduleOptions.FullBackupInterval, "full-backup-interval", 0, "Time between the starts of two full backups of a shard. Full backups are not scheduled if not set.")
+	SetBackupSchedule.Flags().DurationVar(&setBackupScheduleOptions.IncrementalBackupInterval, "incremental-backup-interval", 0, "Time between the start of the last backup of a shard, full or incremental, and the next incremental backup. Incremental backups are not scheduled if not set.")
+	SetBackupSchedule.Flags().Var((*topoproto.TabletTypeFlag)(&setBackupScheduleOptions.TabletType), "tablet-type", "Preferred type of tablet to take the backups on (replica, rdonly or spare). Other types are used if no tablet of this type is available.")
+	SetBackupSchedule.Flags().StringVar(&setBackupScheduleOptions.Cell, "cell", "", "Preferred cell of the tablets to take the backups on. Other cells are used if no tablet of this cell is available.")
+	SetBackupSchedule.Flags().Int32Var(&setBackupScheduleOptions.MaxConcurrentBackupsPerCell, "max-concurrent-backups-per-cell", 0, "Maximum number of scheduled backups of the shards of the keyspace running at the same time in a cell. 0 means unlimited.")
+	SetBackupSchedule.Flags().Int32Var(&setBackupScheduleOptions.Concurrency, "concurrency", 0, "Number of files backed up in parallel. Defaults to the default of the Backup command.")
+	SetBackupSchedule.Flags().StringVar(&setBackupScheduleOptions.BackupEngine, "backup-engine", "", "Backup engine to use. Defaults to the backup engine of the tablets.")
+	SetBackupSchedule.Flags().BoolVar(&setBackupScheduleOptions.Paused, "paused", false, "Do not schedule backups until the schedule is set again without this flag.")
+	Root.AddCommand(SetBackupSchedule)
+}
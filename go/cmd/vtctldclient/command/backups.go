@@ -52,6 +52,18 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandBackupShard,
 	}
+	// GetBackupProgress makes a GetBackupProgress gRPC call to a vtctld.
+	GetBackupProgress = &cobra.Command{
+		Use:   "GetBackupProgress <tablet_alias>",
+		Short: "Outputs a JSON structure that contains the progress of the running, or latest, backup or restore of the given tablet.",
+		Long: `Outputs a JSON structure that contains the progress of the running, or latest, backup or restore of the given tablet.
+
+The progress includes the current phase, the bytes done for every file copied so far, and the estimated time until
+all the bytes are copied. Nothing is output if the tablet has not run a backup or restore since it started.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandGetBackupProgress,
+	}
 	// GetBackups makes a GetBackups gRPC call to a vtctld.
 	GetBackups = &cobra.Command{
 		Use:                   "GetBackups [--limit <limit>] [--json] <keyspace/shard>",
@@ -173,6 +185,34 @@ var getBackupsOptions = struct {
 	OutputJSON bool
 }{}
 
+func commandGetBackupProgress(cmd *cobra.Command, args []string) error {
+	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetBackupProgress(commandCtx, &vtctldatapb.GetBackupProgressRequest{
+		TabletAlias: alias,
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.Progress == nil {
+		return nil
+	}
+
+	data, err := cli.MarshalJSON(resp.Progress)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 func commandGetBackups(cmd *cobra.Command, args []string) error {
 	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
@@ -308,6 +348,8 @@ func init() {
 	BackupShard.Flags().DurationVar(&backupShardOptions.MysqlShutdownTimeout, "mysql-shutdown-timeout", mysqlctl.DefaultShutdownTimeout, "Timeout to use when MySQL is being shut down.")
 	Root.AddCommand(BackupShard)
 
+	Root.AddCommand(GetBackupProgress)
+
 	GetBackups.Flags().Uint32VarP(&getBackupsOptions.Limit, "limit", "l", 0, "Retrieve only the most recent N backups.")
 	GetBackups.Flags().BoolVarP(&getBackupsOptions.OutputJSON, "json", "j", false, "Output backup info in JSON format rather than a list of backups.")
 	Root.AddCommand(GetBackups)
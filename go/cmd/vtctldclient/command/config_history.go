@@ -0,0 +1,229 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+const configKindHelp = "KIND is one of vschema, routing_rules, shard_routing_rules, keyspace_routing_rules or mirror_rules. The vschema kind requires --keyspace."
+
+var (
+	// DiffConfigVersions makes a DiffConfigVersions gRPC call to a vtctld.
+	DiffConfigVersions = &cobra.Command{
+		Use:   "DiffConfigVersions [--keyspace KEYSPACE] <kind> <from_version> [<to_version>]",
+		Short: "Displays the differences between two versions in the history of a VSchema or of routing rules.",
+		Long: `Displays the differences between two versions in the history of a VSchema or of routing rules, as a unified diff.
+If <to_version> is omitted, <from_version> is compared to the current configuration.
+
+` + configKindHelp,
+		Example:               "DiffConfigVersions --keyspace commerce vschema 3 5",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.RangeArgs(2, 3),
+		RunE:                  commandDiffConfigVersions,
+	}
+	// GetConfigHistory makes a GetConfigHistory gRPC call to a vtctld.
+	GetConfigHistory = &cobra.Command{
+		Use:   "GetConfigHistory [--keyspace KEYSPACE] [--limit N] [--include-contents] <kind>",
+		Short: "Displays the history of changes to a VSchema or to routing rules.",
+		Long: `Displays the history of changes to a VSchema or to routing rules, oldest first.
+Each version records who made the change, when, and its diff from the previous version.
+
+` + configKindHelp,
+		Example:               "GetConfigHistory --keyspace commerce --limit 10 vschema",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandGetConfigHistory,
+	}
+	// RollbackConfig makes a RollbackConfig gRPC call to a vtctld.
+	RollbackConfig = &cobra.Command{
+		Use:   "RollbackConfig [--keyspace KEYSPACE] [--cells=c1,c2,...] [--skip-rebuild] <kind> <version>",
+		Short: "Restores a VSchema or routing rules to a version from their history.",
+		Long: `Restores a VSchema or routing rules to a version from their history.
+The rollback fails, rather than overwriting it, if the configuration is changed concurrently. It is itself recorded as a new version.
+
+` + configKindHelp,
+		Example:               "RollbackConfig routing_rules 4",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandRollbackConfig,
+	}
+)
+
+// parseConfigKind parses the name of a topodatapb.ConfigKind, case
+// insensitively.
+func parseConfigKind(name string) (topodatapb.ConfigKind, error) {
+	kind, ok := topodatapb.ConfigKind_value[strings.ToUpper(strings.ReplaceAll(name, "-", "_"))]
+	if !ok {
+		return 0, fmt.Errorf("invalid config kind %q", name)
+	}
+	return topodatapb.ConfigKind(kind), nil
+}
+
+// parseConfigVersion parses a version in a config history.
+func parseConfigVersion(s string) (int64, error) {
+	version, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid version %q: must be a positive integer", s)
+	}
+	return version, nil
+}
+
+var diffConfigVersionsOptions = struct {
+	Keyspace string
+}{}
+
+func commandDiffConfigVersions(cmd *cobra.Command, args []string) error {
+	kind, err := parseConfigKind(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+	fromVersion, err := parseConfigVersion(cmd.Flags().Arg(1))
+	if err != nil {
+		return err
+	}
+	var toVersion int64
+	if len(args) == 3 {
+		if toVersion, err = parseConfigVersion(cmd.Flags().Arg(2)); err != nil {
+			return err
+		}
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.DiffConfigVersions(commandCtx, &vtctldatapb.DiffConfigVersionsRequest{
+		Kind:        kind,
+		Keyspace:    diffConfigVersionsOptions.Keyspace,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.Diff == "" {
+		fmt.Println("No differences found.")
+		return nil
+	}
+	fmt.Print(resp.Diff)
+
+	return nil
+}
+
+var getConfigHistoryOptions = struct {
+	Keyspace        string
+	Limit           int32
+	IncludeContents bool
+}{}
+
+func commandGetConfigHistory(cmd *cobra.Command, args []string) error {
+	kind, err := parseConfigKind(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetConfigHistory(commandCtx, &vtctldatapb.GetConfigHistoryRequest{
+		Kind:     kind,
+		Keyspace: getConfigHistoryOptions.Keyspace,
+		Limit:    getConfigHistoryOptions.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !getConfigHistoryOptions.IncludeContents {
+		for _, entry := range resp.Entries {
+			entry.Contents = nil
+		}
+	}
+
+	data, err := cli.MarshalJSON(resp.Entries)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+var rollbackConfigOptions = struct {
+	Keyspace    string
+	Cells       []string
+	SkipRebuild bool
+}{}
+
+func commandRollbackConfig(cmd *cobra.Command, args []string) error {
+	kind, err := parseConfigKind(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+	version, err := parseConfigVersion(cmd.Flags().Arg(1))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.RollbackConfig(commandCtx, &vtctldatapb.RollbackConfigRequest{
+		Kind:         kind,
+		Keyspace:     rollbackConfigOptions.Keyspace,
+		Version:      version,
+		SkipRebuild:  rollbackConfigOptions.SkipRebuild,
+		RebuildCells: rollbackConfigOptions.Cells,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Rolled back to version %d, recorded as version %d.\n", version, resp.Entry.GetVersion())
+	if diff := resp.Entry.GetDiff(); diff != "" {
+		fmt.Print(diff)
+	}
+
+	if rollbackConfigOptions.SkipRebuild {
+		fmt.Println("Skipping rebuild of VSchema graph, will need to run RebuildVSchemaGraph for changes to take effect.")
+	}
+
+	return nil
+}
+
+func init() {
+	DiffConfigVersions.Flags().StringVar(&diffConfigVersionsOptions.Keyspace, "keyspace", "", "The keyspace of the VSchema.")
+	Root.AddCommand(DiffConfigVersions)
+
+	GetConfigHistory.Flags().StringVar(&getConfigHistoryOptions.Keyspace, "keyspace", "", "The keyspace of the VSchema.")
+	GetConfigHistory.Flags().Int32Var(&getConfigHistoryOptions.Limit, "limit", 0, "Only display the most recent N versions. 0 displays all of them.")
+	GetConfigHistory.Flags().BoolVar(&getConfigHistoryOptions.IncludeContents, "include-contents", false, "Include the full serialized contents of each version in the output.")
+	Root.AddCommand(GetConfigHistory)
+
+	RollbackConfig.Flags().StringVar(&rollbackConfigOptions.Keyspace, "keyspace", "", "The keyspace of the VSchema.")
+	RollbackConfig.Flags().StringSliceVarP(&rollbackConfigOptions.Cells, "cells", "c", nil, "Limit the VSchema graph rebuilding to the specified cells. Ignored if --skip-rebuild is specified.")
+	RollbackConfig.Flags().BoolVar(&rollbackConfigOptions.SkipRebuild, "skip-rebuild", false, "Skip rebuilding the SrvVSchema objects.")
+	Root.AddCommand(RollbackConfig)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// AddRecoverySilence makes an AddRecoverySilence gRPC call to a vtctld.
+	AddRecoverySilence = &cobra.Command{
+		Use:   "AddRecoverySilence [--keyspace <keyspace> [--shard <shard>]] [--tablet-alias <alias>] [--analysis-codes <code1,code2,...>] [--start-time <time>] --duration <duration> --reason <reason>",
+		Short: "Prevents VTOrc from running the recoveries of a keyspace, a shard or a tablet until the silence expires.",
+		Long: `Prevents VTOrc from running the recoveries of a keyspace, a shard or a tablet until the silence expires.
+
+The silence applies to the analyzed tablets that match all of --keyspace, --shard and --tablet-alias that are set. At
+least --keyspace or --tablet-alias is required. With --analysis-codes, only the recoveries of these analyses, like
+ReplicationStopped or DeadPrimary, are silenced. The silence starts now, or at --start-time for a scheduled maintenance,
+and lasts for --duration. The added silence is printed, with the id to remove it with RemoveRecoverySilence.`,
+		Example: `AddRecoverySilence --keyspace commerce --shard 0 --duration 2h --reason "replacing the primary host"
+AddRecoverySilence --tablet-alias zone1-101 --analysis-codes ReplicationStopped --start-time 2025-06-01T22:00:00Z --duration 1h --reason "backup restore"`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandAddRecoverySilence,
+	}
+	// GetRecoverySilences makes a GetRecoverySilences gRPC call to a vtctld.
+	GetRecoverySilences = &cobra.Command{
+		Use:                   "GetRecoverySilences",
+		Short:                 "Displays the VTOrc recovery silences that haven't expired.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandGetRecoverySilences,
+	}
+	// RemoveRecoverySilence makes a RemoveRecoverySilence gRPC call to a vtctld.
+	RemoveRecoverySilence = &cobra.Command{
+		Use:                   "RemoveRecoverySilence <id>",
+		Short:                 "Removes a VTOrc recovery silence, so the recoveries it silenced run again.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandRemoveRecoverySilence,
+	}
+)
+
+var addRecoverySilenceOptions = struct {
+	Keyspace      string
+	Shard         string
+	TabletAlias   string
+	AnalysisCodes []string
+	StartTime     string
+	Duration      time.Duration
+	Reason        string
+}{}
+
+func commandAddRecoverySilence(cmd *cobra.Command, args []string) error {
+	silence := &topodatapb.RecoverySilence{
+		Keyspace:      addRecoverySilenceOptions.Keyspace,
+		Shard:         addRecoverySilenceOptions.Shard,
+		AnalysisCodes: addRecoverySilenceOptions.AnalysisCodes,
+		Reason:        addRecoverySilenceOptions.Reason,
+	}
+	if addRecoverySilenceOptions.TabletAlias != "" {
+		alias, err := topoproto.ParseTabletAlias(addRecoverySilenceOptions.TabletAlias)
+		if err != nil {
+			return err
+		}
+		silence.TabletAlias = alias
+	}
+	if addRecoverySilenceOptions.Duration <= 0 {
+		return fmt.Errorf("--duration must be positive")
+	}
+	start := time.Now()
+	if addRecoverySilenceOptions.StartTime != "" {
+		var err error
+		start, err = time.Parse(time.RFC3339, addRecoverySilenceOptions.StartTime)
+		if err != nil {
+			return fmt.Errorf("invalid --start-time %v: %w", addRecoverySilenceOptions.StartTime, err)
+		}
+		silence.StartTime = protoutil.TimeToProto(start)
+	}
+	silence.ExpireTime = protoutil.TimeToProto(start.Add(addRecoverySilenceOptions.Duration))
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.AddRecoverySilence(commandCtx, &vtctldatapb.AddRecoverySilenceRequest{
+		Silence: silence,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSONPretty(resp.Silence)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func commandGetRecoverySilences(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetRecoverySilences(commandCtx, &vtctldatapb.GetRecoverySilencesRequest{})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSONPretty(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func commandRemoveRecoverySilence(cmd *cobra.Command, args []string) error {
+	id := cmd.Flags().Arg(0)
+
+	cli.FinishedParsing(cmd)
+
+	_, err := client.RemoveRecoverySilence(commandCtx, &vtctldatapb.RemoveRecoverySilenceRequest{
+		Id: id,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully removed recovery silence %s\n", id)
+
+	return nil
+}
+
+func init() {
+	AddRecoverySilence.Flags().StringVar(&addRecoverySilenceOptions.Keyspace, "keyspace", "", "Keyspace whose recoveries to silence.")
+	AddRecoverySilence.Flags().StringVar(&addRecoverySilenceOptions.Shard, "shard", "", "Shard of --keyspace whose recoveries to silence.")
+	AddRecoverySilence.Flags().StringVar(&addRecoverySilenceOptions.TabletAlias, "tablet-alias", "", "Alias of the tablet whose recoveries to silence.")
+	AddRecoverySilence.Flags().StringSliceVar(&addRecoverySilenceOptions.AnalysisCodes, "analysis-codes", nil, "VTOrc analysis codes whose recoveries to silence, like ReplicationStopped. Defaults to all the recoveries.")
+	AddRecoverySilence.Flags().StringVar(&addRecoverySilenceOptions.StartTime, "start-time", "", "When the silence starts, in RFC3339 format. Defaults to now.")
+	AddRecoverySilence.Flags().DurationVar(&addRecoverySilenceOptions.Duration, "duration", 0, "How long the silence lasts.")
+	AddRecoverySilence.Flags().StringVar(&addRecoverySilenceOptions.Reason, "reason", "", "Why the recoveries are silenced, shown by VTOrc.")
+	AddRecoverySilence.MarkFlagRequired("duration")
+	AddRecoverySilence.MarkFlagRequired("reason")
+	Root.AddCommand(AddRecoverySilence)
+
+	Root.AddCommand(GetRecoverySilences)
+
+	Root.AddCommand(RemoveRecoverySilence)
+}
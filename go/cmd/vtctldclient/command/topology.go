@@ -17,19 +17,40 @@ limitations under the License.
 package command
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/json2"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/helpers"
 
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 var (
+	// DiffTopologySnapshots compares two topology snapshot files.
+	DiffTopologySnapshots = &cobra.Command{
+		Use:   "DiffTopologySnapshots [--cells <cell1,cell2,...>] [--keyspaces <keyspace1,keyspace2,...>] [--file-types <type1,type2,...>] <from-file> <to-file>",
+		Short: "Prints the differences between two topology snapshots, taken with SnapshotTopology.",
+		Long: `Prints the differences between two topology snapshots, taken with SnapshotTopology, as unified diffs of the files
+that were created, updated or deleted from the first snapshot to the second one. The files are printed as JSON when
+their type is known.
+
+This command does not connect to a vtctld.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandDiffTopologySnapshots,
+		Annotations: map[string]string{
+			skipClientCreationKey: "true",
+		},
+	}
 	// GetTopologyPath makes a GetTopologyPath gRPC call to a vtctld.
 	GetTopologyPath = &cobra.Command{
 		Use:                   "GetTopologyPath <path>",
@@ -39,6 +60,45 @@ var (
 		RunE:                  commandGetTopologyPath,
 	}
 
+	// RestoreTopology makes a RestoreTopology gRPC call to a vtctld.
+	RestoreTopology = &cobra.Command{
+		Use:   "RestoreTopology [--cells <cell1,cell2,...>] [--keyspaces <keyspace1,keyspace2,...>] [--file-types <type1,type2,...>] [--prune] [--dry-run] <file>",
+		Short: "Restores the topology, or a part of it, from a snapshot taken with SnapshotTopology.",
+		Long: `Restores the topology, or a part of it, from a snapshot taken with SnapshotTopology.
+
+The files of the snapshot selected by --cells, --keyspaces and --file-types that are missing from the topology are
+created, and the ones that differ are updated. The restore fails, before anything is written, if any of them changed
+since it was read. With --prune, the selected files that are not in the snapshot are deleted as well. The changes are
+printed, from the current topology to the snapshot.
+
+The files are written in a single transaction only if all the restored cells use the same etcd cluster, and there are
+no more of them than --topo_etcd_max_txn_ops of the vtctld. Otherwise they are written one at a time, and the ones
+already written are reverted if a write fails, but a vtctld that stops in the middle leaves the restore partially
+applied: restore one cell, or fewer files, at a time to avoid it.
+
+The cells must exist in the topology: when restoring a lost topology, restore the global cell, which has the cell
+definitions, first. Restored VSchemas are only served once RebuildVSchemaGraph is run.`,
+		Example:               `RestoreTopology --keyspaces commerce --file-types SrvKeyspace,VSchema --dry-run topo.json`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandRestoreTopology,
+	}
+	// SnapshotTopology makes a SnapshotTopology gRPC call to a vtctld.
+	SnapshotTopology = &cobra.Command{
+		Use:   "SnapshotTopology [--cells <cell1,cell2,...>] <file>",
+		Short: "Writes a snapshot of the files of the global topology and of cells to a file.",
+		Long: `Writes a snapshot of the files of the global topology and of cells, except locks and other ephemeral files, to a
+file. The snapshot is written as JSON if the file name ends with .json, or is - for standard output, and as binary
+protobuf otherwise.
+
+The cells are read one after the other, so the snapshot is only consistent if the topology doesn't change meanwhile.`,
+		Example: `SnapshotTopology topo.json
+SnapshotTopology --cells global,zone1 topo.pb`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandSnapshotTopology,
+	}
+
 	// WriteTopologyPath writes the contents of a local file to a path
 	// in the topology server.
 	WriteTopologyPath = &cobra.Command{
@@ -96,6 +156,118 @@ func commandGetTopologyPath(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// readTopoSnapshot reads a snapshot written by SnapshotTopology, as JSON
+// or binary protobuf.
+func readTopoSnapshot(file string) (*topodatapb.TopoSnapshot, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %v", file, err)
+	}
+	snapshot := &topodatapb.TopoSnapshot{}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		err = json2.UnmarshalPB(data, snapshot)
+	} else {
+		err = proto.Unmarshal(data, snapshot)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse topology snapshot %s: %v", file, err)
+	}
+	return snapshot, nil
+}
+
+var topoSnapshotFilterOptions = helpers.SnapshotFilter{}
+
+func commandDiffTopologySnapshots(cmd *cobra.Command, args []string) error {
+	from, err := readTopoSnapshot(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+	to, err := readTopoSnapshot(cmd.Flags().Arg(1))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	for _, change := range helpers.DiffTopoSnapshots(from, to, &topoSnapshotFilterOptions) {
+		fmt.Print(change.Diff)
+	}
+
+	return nil
+}
+
+var restoreTopologyOptions = struct {
+	Prune  bool
+	DryRun bool
+}{}
+
+func commandRestoreTopology(cmd *cobra.Command, args []string) error {
+	snapshot, err := readTopoSnapshot(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.RestoreTopology(commandCtx, &vtctldatapb.RestoreTopologyRequest{
+		Snapshot:  snapshot,
+		Cells:     topoSnapshotFilterOptions.Cells,
+		Keyspaces: topoSnapshotFilterOptions.Keyspaces,
+		FileTypes: topoSnapshotFilterOptions.FileTypes,
+		Prune:     restoreTopologyOptions.Prune,
+		DryRun:    restoreTopologyOptions.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSONPretty(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+var snapshotTopologyOptions = struct {
+	Cells []string
+}{}
+
+func commandSnapshotTopology(cmd *cobra.Command, args []string) error {
+	file := cmd.Flags().Arg(0)
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.SnapshotTopology(commandCtx, &vtctldatapb.SnapshotTopologyRequest{
+		Cells: snapshotTopologyOptions.Cells,
+	})
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if file == "-" || strings.HasSuffix(file, ".json") {
+		data, err = cli.MarshalJSONPretty(resp.Snapshot)
+	} else {
+		data, err = proto.Marshal(resp.Snapshot)
+	}
+	if err != nil {
+		return err
+	}
+
+	if file == "-" {
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write file %s: %v", file, err)
+	}
+
+	return nil
+}
+
 var writeTopologyPathOptions = struct {
 	// The cell to use for the copy. Defaults to the global cell.
 	cell string
@@ -127,10 +299,24 @@ func commandWriteTopologyPath(cmd *cobra.Command, args []string) error {
 }
 
 func init() {
+	for _, cmd := range []*cobra.Command{DiffTopologySnapshots, RestoreTopology} {
+		cmd.Flags().StringSliceVar(&topoSnapshotFilterOptions.Cells, "cells", nil, "Cells to include, global for the global topology. Defaults to all the cells of the snapshot.")
+		cmd.Flags().StringSliceVar(&topoSnapshotFilterOptions.Keyspaces, "keyspaces", nil, "Keyspaces whose files to include. Defaults to all the files.")
+		cmd.Flags().StringSliceVar(&topoSnapshotFilterOptions.FileTypes, "file-types", nil, "Types of files to include, like SrvKeyspace or VSchema. Defaults to all the files.")
+	}
+	Root.AddCommand(DiffTopologySnapshots)
+
 	GetTopologyPath.Flags().Int64Var(&getTopologyPathOptions.version, "version", getTopologyPathOptions.version, "The version of the path's key to get. If not specified, the latest version is returned.")
 	GetTopologyPath.Flags().BoolVar(&getTopologyPathOptions.dataAsJSON, "data-as-json", getTopologyPathOptions.dataAsJSON, "If true, only the data is output and it is in JSON format rather than prototext.")
 	Root.AddCommand(GetTopologyPath)
 
+	RestoreTopology.Flags().BoolVar(&restoreTopologyOptions.Prune, "prune", false, "Delete the selected files that are not in the snapshot.")
+	RestoreTopology.Flags().BoolVar(&restoreTopologyOptions.DryRun, "dry-run", false, "Print the changes without applying them.")
+	Root.AddCommand(RestoreTopology)
+
+	SnapshotTopology.Flags().StringSliceVar(&snapshotTopologyOptions.Cells, "cells", nil, "Cells to snapshot, global for the global topology. Defaults to the global topology and all the cells.")
+	Root.AddCommand(SnapshotTopology)
+
 	WriteTopologyPath.Flags().StringVar(&writeTopologyPathOptions.cell, "cell", topo.GlobalCell, "Topology server cell to copy the file to.")
 	Root.AddCommand(WriteTopologyPath)
 }
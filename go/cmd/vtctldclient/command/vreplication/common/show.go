@@ -27,8 +27,9 @@ import (
 )
 
 var ShowOptions = struct {
-	IncludeLogs bool
-	Shards      []string
+	IncludeLogs        bool
+	IncludeSchemaDrift bool
+	Shards             []string
 }{}
 
 func GetShowCommand(opts *SubCommandsOpts) *cobra.Command {
@@ -45,14 +46,21 @@ func GetShowCommand(opts *SubCommandsOpts) *cobra.Command {
 	return cmd
 }
 
+// AddSchemaDriftFlag adds the flag that includes the schema drift of the
+// workflow to the show command of the workflows that copy tables as is.
+func AddSchemaDriftFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&ShowOptions.IncludeSchemaDrift, "include-schema-drift", false, "Compare the definitions of the source and target tables, and include the ones that differ.")
+}
+
 func commandShow(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	req := &vtctldatapb.GetWorkflowsRequest{
-		Keyspace:    BaseOptions.TargetKeyspace,
-		Workflow:    BaseOptions.Workflow,
-		IncludeLogs: ShowOptions.IncludeLogs,
-		Shards:      ShowOptions.Shards,
+		Keyspace:           BaseOptions.TargetKeyspace,
+		Workflow:           BaseOptions.Workflow,
+		IncludeLogs:        ShowOptions.IncludeLogs,
+		IncludeSchemaDrift: ShowOptions.IncludeSchemaDrift,
+		Shards:             ShowOptions.Shards,
 	}
 	resp, err := GetClient().GetWorkflows(GetCommandCtx(), req)
 	if err != nil {
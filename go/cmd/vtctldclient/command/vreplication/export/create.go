@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	createOptions = struct {
+		AllTables        bool
+		IncludeTables    []string
+		ExcludeTables    []string
+		Storage          string
+		Directory        string
+		Format           string
+		MaxBatchRows     int64
+		MaxBatchInterval time.Duration
+	}{}
+
+	// create makes an ExportCreate gRPC call to a vtctld.
+	create = &cobra.Command{
+		Use:     "create",
+		Short:   "Create and optionally run an Export VReplication workflow.",
+		Example: `vtctldclient --server localhost:15999 export --workflow commerce_export --target-keyspace commerce create --tables corder,customer --storage backup --directory exports/commerce`,
+		Long: `Export writes the rows of the tables of a keyspace to files, rather than to the tables
+of a target keyspace. Its streams run on the primary tablets of the keyspace, and every stream
+exports the rows of its own shard: first a snapshot of the existing rows, and then the changes
+made to them. The files are written to the given directory, either of the tablet hosts or of
+the backup storage of the tablets, as newline-delimited JSON objects. The files of a table are
+partitioned by date and by shard:
+
+  <directory>/<table>/dt=<yyyy-mm-dd>/shard=<shard>/batch=<batch>/data.ndjson
+
+A batch of files is written once it has max-batch-rows rows or is max-batch-interval old, and
+is then committed by writing its checkpoint, which records the GTID position of the stream and
+lists the files of the batch:
+
+  <directory>/_checkpoints/shard=<shard>/<batch>/checkpoint.json
+
+The files of a batch that is not committed are removed when the stream restarts from its last
+checkpoint, so that every row is written exactly once to the files of the committed batches.
+Canceling the workflow does not remove the files.`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Create"},
+		Args:                  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Either specific tables or the all tables flags are required.
+			if !cmd.Flags().Lookup("tables").Changed && !cmd.Flags().Lookup("all-tables").Changed {
+				return fmt.Errorf("tables or all-tables are required to specify which tables to export")
+			}
+			if createOptions.MaxBatchInterval < time.Second {
+				return fmt.Errorf("max-batch-interval must be at least one second")
+			}
+			if err := common.ParseCells(cmd); err != nil {
+				return err
+			}
+			if err := common.ParseTabletTypes(cmd); err != nil {
+				return err
+			}
+			return nil
+		},
+		RunE: commandCreate,
+	}
+)
+
+func commandCreate(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	tsp := common.GetTabletSelectionPreference(cmd)
+	cli.FinishedParsing(cmd)
+
+	configOverrides, err := common.ParseConfigOverrides(common.CreateOptions.ConfigOverrides)
+	if err != nil {
+		return err
+	}
+
+	req := &vtctldatapb.ExportCreateRequest{
+		Workflow:                  common.BaseOptions.Workflow,
+		Keyspace:                  common.BaseOptions.TargetKeyspace,
+		Cells:                     common.CreateOptions.Cells,
+		TabletTypes:               common.CreateOptions.TabletTypes,
+		TabletSelectionPreference: tsp,
+		AllTables:                 createOptions.AllTables,
+		IncludeTables:             createOptions.IncludeTables,
+		ExcludeTables:             createOptions.ExcludeTables,
+		Sink: &binlogdatapb.SinkSettings{
+			Storage:         createOptions.Storage,
+			Directory:       createOptions.Directory,
+			Format:          createOptions.Format,
+			MaxBatchRows:    createOptions.MaxBatchRows,
+			MaxBatchSeconds: int64(createOptions.MaxBatchInterval / time.Second),
+		},
+		StopAfterCopy: common.CreateOptions.StopAfterCopy,
+		AutoStart:     common.CreateOptions.AutoStart,
+		WorkflowOptions: &vtctldatapb.WorkflowOptions{
+			Config: configOverrides,
+		},
+	}
+
+	resp, err := common.GetClient().ExportCreate(common.GetCommandCtx(), req)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		jsonText, err := cli.MarshalJSONPretty(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonText))
+	} else {
+		fmt.Println(resp.Summary)
+	}
+
+	return nil
+}
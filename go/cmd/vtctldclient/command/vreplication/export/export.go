@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+)
+
+var (
+	// base is the base command for all actions related to Export.
+	base = &cobra.Command{
+		Use:                   "Export --workflow <workflow> --target-keyspace <keyspace> [command] [command-flags]",
+		Short:                 "Perform commands related to exporting the tables of a keyspace to files.",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"export"},
+		Args:                  cobra.ExactArgs(1),
+	}
+)
+
+func registerCommands(root *cobra.Command) {
+	common.AddCommonFlags(base)
+	root.AddCommand(base)
+
+	create.Flags().StringSliceVarP(&common.CreateOptions.Cells, "cells", "c", nil, "Cells and/or CellAliases to copy table data from.")
+	create.Flags().BoolVarP(&common.CreateOptions.AllCells, "all-cells", "a", false, "Copy table data from any existing cell.")
+	create.Flags().Var((*topoproto.TabletTypeListFlag)(&common.CreateOptions.TabletTypes), "tablet-types", "Source tablet types to replicate table data from (e.g. PRIMARY,REPLICA,RDONLY).")
+	create.Flags().BoolVar(&common.CreateOptions.TabletTypesInPreferenceOrder, "tablet-types-in-preference-order", true, "When performing source tablet selection, look for candidates in the type order as they are listed in the tablet-types flag.")
+	create.Flags().BoolVar(&common.CreateOptions.AutoStart, "auto-start", true, "Start the workflow after creating it.")
+	create.Flags().BoolVar(&common.CreateOptions.StopAfterCopy, "stop-after-copy", false, "Stop the workflow after it's finished exporting the existing rows and before it starts exporting changes.")
+	create.Flags().StringSliceVar(&common.CreateOptions.ConfigOverrides, "config-overrides", []string{}, "Specify one or more VReplication config flags to override as a comma-separated list of key=value pairs.")
+	create.Flags().BoolVar(&createOptions.AllTables, "all-tables", false, "Export all tables of the keyspace.")
+	create.Flags().StringSliceVar(&createOptions.IncludeTables, "tables", nil, "Tables to export.")
+	create.Flags().StringSliceVar(&createOptions.ExcludeTables, "exclude-tables", nil, "Tables to exclude from the export.")
+	create.Flags().StringVar(&createOptions.Storage, "storage", "local", "Where the files are written: 'local' for a directory of the tablet hosts, or 'backup' for the backup storage of the tablets.")
+	create.Flags().StringVar(&createOptions.Directory, "directory", "", "Directory of the storage that the files are written to.")
+	create.MarkFlagRequired("directory")
+	create.Flags().StringVar(&createOptions.Format, "format", "ndjson", "Format of the files. Only 'ndjson' (newline-delimited JSON) is supported.")
+	create.Flags().Int64Var(&createOptions.MaxBatchRows, "max-batch-rows", 10000, "Number of rows after which a batch of files is written.")
+	create.Flags().DurationVar(&createOptions.MaxBatchInterval, "max-batch-interval", time.Minute, "Time after which a batch of files is written.")
+	base.AddCommand(create)
+
+	// Generic workflow commands.
+	opts := &common.SubCommandsOpts{
+		SubCommand: "Export",
+		Workflow:   "commerce_export",
+	}
+	base.AddCommand(common.GetCancelCommand(opts))
+	base.AddCommand(common.GetShowCommand(opts))
+	base.AddCommand(common.GetStartCommand(opts))
+	base.AddCommand(common.GetStopCommand(opts))
+}
+
+func init() {
+	common.RegisterCommandHandler("Export", registerCommands)
+}
@@ -51,7 +51,13 @@ in the target-keyspace to store the results in. The second required key is 'sour
 and its value is the select query to run against the source table. An optional key/value pair
 can also be specified for 'create_ddl' which provides the DDL to create the target table if it
 does not exist -- you can alternatively specify a value of 'copy' if the target table schema
-should be copied as-is from the source keyspace. Here's an example value for table-settings:
+should be copied as-is from the source keyspace. The source_expression can also be an inner
+join of tables in the source keyspace, in which case it must select the primary key columns of
+every joined table, and those must be part of the primary key of the target table, whose
+create_ddl must be provided. Aggregations can use count(*), count(col), sum(col), min(col), max(col)
+and avg(col), grouped by columns or expressions of the select list. The target table of an
+'avg(col) as c' must also have the c_vt_sum and c_vt_count columns, which can be declared as
+INVISIBLE. Here's an example value for table-settings:
 [
   {
     "target_table": "customer_one_email",
@@ -66,6 +72,11 @@ should be copied as-is from the source keyspace. Here's an example value for tab
     "target_table": "sales_by_sku",
     "source_expression": "select sku, count(*) as orders, sum(price) as revenue from corder group by sku",
     "create_ddl": "create table sales_by_sku (sku varbinary(128) not null primary key, orders bigint, revenue bigint)"
+  },
+  {
+    "target_table": "order_customers",
+    "source_expression": "select o.order_id, c.customer_id, c.email from corder as o join customer as c on o.customer_id = c.customer_id",
+    "create_ddl": "create table order_customers (order_id bigint, customer_id bigint, email varbinary(128), primary key (order_id, customer_id))"
   }
 ]
 `,
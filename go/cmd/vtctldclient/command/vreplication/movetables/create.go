@@ -25,6 +25,7 @@ import (
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
 	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
@@ -42,6 +43,10 @@ var (
 		WorkflowOptions     vtctldatapb.WorkflowOptions
 		// This maps to a WorkflowOptions.ShardedAutoIncrementHandling ENUM value.
 		ShardedAutoIncrementHandlingStr string
+		Bidirectional                   bool
+		// This maps to a BidirectionalSettings.ConflictResolution ENUM value.
+		ConflictResolutionStr   string
+		ConflictTimestampColumn string
 	}{}
 
 	// create makes a MoveTablesCreate gRPC call to a vtctld.
@@ -103,6 +108,17 @@ var (
 				fmt.Println("WARNING: no global-keyspace value provided so all sequence table references not fully qualified must be created manually before switching traffic")
 			}
 
+			if !createOptions.Bidirectional && (cmd.Flags().Lookup("conflict-resolution").Changed || cmd.Flags().Lookup("conflict-timestamp-column").Changed) {
+				return fmt.Errorf("--conflict-resolution and --conflict-timestamp-column require --bidirectional")
+			}
+			createOptions.ConflictResolutionStr = strings.ToUpper(strings.ReplaceAll(createOptions.ConflictResolutionStr, "-", "_"))
+			if _, ok := binlogdatapb.ConflictResolution_value[createOptions.ConflictResolutionStr]; !ok {
+				return fmt.Errorf("invalid value provided for --conflict-resolution, valid values are: %s", conflictResolutionStrOptions)
+			}
+			if createOptions.ConflictResolutionStr == binlogdatapb.ConflictResolution_LAST_WRITER_WINS.String() && createOptions.ConflictTimestampColumn == "" {
+				return fmt.Errorf("--conflict-timestamp-column is required with --conflict-resolution=%s", binlogdatapb.ConflictResolution_LAST_WRITER_WINS)
+			}
+
 			return nil
 		},
 		RunE: commandCreate,
@@ -143,6 +159,12 @@ func commandCreate(cmd *cobra.Command, args []string) error {
 		AtomicCopy:                createOptions.AtomicCopy,
 		WorkflowOptions:           &createOptions.WorkflowOptions,
 	}
+	if createOptions.Bidirectional {
+		req.Bidirectional = &binlogdatapb.BidirectionalSettings{
+			ConflictResolution: binlogdatapb.ConflictResolution(binlogdatapb.ConflictResolution_value[createOptions.ConflictResolutionStr]),
+			TimestampColumn:    createOptions.ConflictTimestampColumn,
+		}
+	}
 
 	resp, err := common.GetClient().MoveTablesCreate(common.GetCommandCtx(), req)
 	if err != nil {
@@ -25,6 +25,7 @@ import (
 	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
@@ -42,6 +43,7 @@ var (
 		Args:                  cobra.ExactArgs(1),
 	}
 	shardedAutoIncHandlingStrOptions string
+	conflictResolutionStrOptions     string
 )
 
 func registerCommands(root *cobra.Command) {
@@ -64,6 +66,11 @@ func registerCommands(root *cobra.Command) {
 	create.Flags().StringVar(&createOptions.ShardedAutoIncrementHandlingStr, "sharded-auto-increment-handling", vtctldatapb.ShardedAutoIncrementHandling_REMOVE.String(),
 		fmt.Sprintf("If moving the table(s) to a sharded keyspace, remove any MySQL auto_increment clauses when copying the schema to the target as sharded keyspaces should rely on either user/application generated values or Vitess sequences to ensure uniqueness. If REPLACE is specified then they are automatically replaced by Vitess sequence definitions. (options are: %s)",
 			shardedAutoIncHandlingStrOptions))
+	create.Flags().BoolVar(&createOptions.Bidirectional, "bidirectional", false, "(EXPERIMENTAL) Keep replicating the changes in both directions after traffic is switched, so that both the source and target keyspaces can take writes.")
+	create.Flags().StringVar(&createOptions.ConflictResolutionStr, "conflict-resolution", binlogdatapb.ConflictResolution_SOURCE_WINS.String(),
+		fmt.Sprintf("How conflicting writes are resolved by a bidirectional workflow. LAST_WRITER_WINS requires --conflict-timestamp-column. (options are: %s)",
+			conflictResolutionStrOptions))
+	create.Flags().StringVar(&createOptions.ConflictTimestampColumn, "conflict-timestamp-column", "", "The column of the moved tables holding the time of the last write of a row, used to resolve conflicts with LAST_WRITER_WINS.")
 	base.AddCommand(create)
 
 	opts := &common.SubCommandsOpts{
@@ -72,6 +79,7 @@ func registerCommands(root *cobra.Command) {
 	}
 	showCommand := common.GetShowCommand(opts)
 	common.AddShardSubsetFlag(showCommand, &common.ShowOptions.Shards)
+	common.AddSchemaDriftFlag(showCommand)
 	base.AddCommand(showCommand)
 
 	statusCommand := common.GetStatusCommand(opts)
@@ -129,4 +137,10 @@ func init() {
 		sb.WriteString(v)
 	}
 	shardedAutoIncHandlingStrOptions = sb.String()
+
+	strvals = make([]string, len(binlogdatapb.ConflictResolution_name))
+	for enumval, strval := range binlogdatapb.ConflictResolution_name {
+		strvals[enumval] = strval
+	}
+	conflictResolutionStrOptions = strings.Join(strvals, ",")
 }
@@ -42,7 +42,9 @@ func registerReshardCommands(root *cobra.Command) {
 		SubCommand: "Reshard",
 		Workflow:   "cust2cust",
 	}
-	reshard.AddCommand(common.GetShowCommand(opts))
+	showCommand := common.GetShowCommand(opts)
+	common.AddSchemaDriftFlag(showCommand)
+	reshard.AddCommand(showCommand)
 	reshard.AddCommand(common.GetStatusCommand(opts))
 
 	reshard.AddCommand(common.GetStartCommand(opts))
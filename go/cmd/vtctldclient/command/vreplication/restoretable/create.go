@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restoretable
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	createOptions = struct {
+		RestoreKeyspace    string
+		Tables             []string
+		TargetTableSuffix  string
+		Filter             string
+		BackupTimestamp    string
+		RestoreToPos       string
+		RestoreToTimestamp string
+		SkipRestore        bool
+	}{}
+
+	// create makes a RestoreTableCreate gRPC call to a vtctld.
+	create = &cobra.Command{
+		Use:     "create",
+		Short:   "Restore a backup onto the tablets of a snapshot keyspace and copy tables from there into the target keyspace.",
+		Example: `vtctldclient --server localhost:15999 RestoreTable --workflow restore_corder --target-keyspace customer create --restore-keyspace customer_restore --tables corder --filter "customer_id = 1" --restore-to-timestamp 2025-01-02T15:04:05Z`,
+		Long: `RestoreTable restores the data of one or more tables, as it was at the time of a backup or of a
+point-in-time recovery, into new tables in a live keyspace. It is useful to recover rows that were
+accidentally deleted or modified.
+
+The restore target is the set of tablets of the restore-keyspace, which must be a SNAPSHOT keyspace
+whose base keyspace holds the backups to restore. Its tablets that match the cells and tablet-types
+are first restored from backup; PRIMARY tablets cannot be restored. Then a workflow copies every
+table, optionally limited to the rows matching the filter, into a table in the target-keyspace
+whose name is the table name followed by target-table-suffix. The workflow stops once the copy is
+done, after which it can be canceled and the restore keyspace deleted.`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Create"},
+		Args:                  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := common.ParseCells(cmd); err != nil {
+				return err
+			}
+			if createOptions.RestoreToPos != "" && createOptions.RestoreToTimestamp != "" {
+				return fmt.Errorf("--restore-to-pos and --restore-to-timestamp are mutually exclusive")
+			}
+			return nil
+		},
+		RunE: commandCreate,
+	}
+)
+
+func commandCreate(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	req := &vtctldatapb.RestoreTableCreateRequest{
+		Workflow:          common.BaseOptions.Workflow,
+		TargetKeyspace:    common.BaseOptions.TargetKeyspace,
+		RestoreKeyspace:   createOptions.RestoreKeyspace,
+		Tables:            createOptions.Tables,
+		TargetTableSuffix: createOptions.TargetTableSuffix,
+		Filter:            createOptions.Filter,
+		RestoreToPos:      createOptions.RestoreToPos,
+		SkipRestore:       createOptions.SkipRestore,
+		Cells:             common.CreateOptions.Cells,
+		TabletTypes:       common.CreateOptions.TabletTypes,
+		AutoStart:         common.CreateOptions.AutoStart,
+	}
+	if createOptions.BackupTimestamp != "" {
+		t, err := time.Parse(mysqlctl.BackupTimestampFormat, createOptions.BackupTimestamp)
+		if err != nil {
+			return err
+		}
+		req.BackupTime = protoutil.TimeToProto(t)
+	}
+	if createOptions.RestoreToTimestamp != "" {
+		t, err := mysqlctl.ParseRFC3339(createOptions.RestoreToTimestamp)
+		if err != nil {
+			return err
+		}
+		req.RestoreToTimestamp = protoutil.TimeToProto(t)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().RestoreTableCreate(common.GetCommandCtx(), req)
+	if err != nil {
+		return err
+	}
+	return common.OutputStatusResponse(resp, format)
+}
+
+func registerCreateCommand(root *cobra.Command) {
+	create.Flags().StringVar(&createOptions.RestoreKeyspace, "restore-keyspace", "", "SNAPSHOT keyspace whose tablets are restored from backup and used as the source of the copy.")
+	create.MarkFlagRequired("restore-keyspace")
+	create.Flags().StringSliceVar(&createOptions.Tables, "tables", nil, "Tables to restore.")
+	create.MarkFlagRequired("tables")
+	create.Flags().StringVar(&createOptions.TargetTableSuffix, "target-table-suffix", "_restored", "Suffix appended to the name of each table to get the name of the table it is restored into in the target keyspace.")
+	create.Flags().StringVar(&createOptions.Filter, "filter", "", "Optional WHERE clause expression that limits the rows that are restored, e.g. \"customer_id = 1\".")
+	create.Flags().StringVarP(&createOptions.BackupTimestamp, "backup-timestamp", "t", "", "Use the backup taken at, or closest before, this timestamp. Omit to use the snapshot time of the restore keyspace. Timestamp format is \"YYYY-mm-DD.HHMMSS\".")
+	create.Flags().StringVar(&createOptions.RestoreToPos, "restore-to-pos", "", "Run a point in time recovery that ends with the given position.")
+	create.Flags().StringVar(&createOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`).")
+	create.Flags().BoolVar(&createOptions.SkipRestore, "skip-restore", false, "Skip restoring the tablets of the restore keyspace, e.g. because a previous attempt already restored them.")
+	create.Flags().StringSliceVarP(&common.CreateOptions.Cells, "cells", "c", nil, "Cells and/or CellAliases of the restore keyspace tablets to restore and copy table data from.")
+	create.Flags().BoolVarP(&common.CreateOptions.AllCells, "all-cells", "a", false, "Restore and copy table data from the restore keyspace tablets in any existing cell.")
+	create.Flags().Var((*topoproto.TabletTypeListFlag)(&common.CreateOptions.TabletTypes), "tablet-types", "Tablet types of the restore keyspace to restore and copy table data from (default REPLICA,RDONLY).")
+	create.Flags().BoolVar(&common.CreateOptions.AutoStart, "auto-start", true, "Start the workflow after creating it.")
+	root.AddCommand(create)
+}
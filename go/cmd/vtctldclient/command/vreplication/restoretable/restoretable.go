@@ -0,0 +1,56 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restoretable
+
+import (
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+)
+
+var (
+	// base is the base command for all actions related to RestoreTable.
+	base = &cobra.Command{
+		Use:                   "RestoreTable --workflow <workflow> --target-keyspace <keyspace> [command] [command-flags]",
+		Short:                 "Perform commands related to restoring tables from a backup into a live keyspace.",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"restoretable"},
+		Args:                  cobra.ExactArgs(1),
+	}
+)
+
+func registerCommands(root *cobra.Command) {
+	common.AddCommonFlags(base)
+	root.AddCommand(base)
+
+	registerCreateCommand(base)
+
+	// Generic workflow commands.
+	opts := &common.SubCommandsOpts{
+		SubCommand: "RestoreTable",
+		Workflow:   "restore_corder",
+	}
+	base.AddCommand(common.GetCancelCommand(opts))
+	base.AddCommand(common.GetShowCommand(opts))
+	base.AddCommand(common.GetStatusCommand(opts))
+	base.AddCommand(common.GetStartCommand(opts))
+	base.AddCommand(common.GetStopCommand(opts))
+}
+
+func init() {
+	common.RegisterCommandHandler("RestoreTable", registerCommands)
+}
@@ -22,6 +22,7 @@ import (
 	"io"
 	"math"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -69,12 +70,23 @@ var (
 		MaxDiffDuration             time.Duration
 		RowDiffColumnTruncateAt     int64
 		AutoStart                   bool
+		Checksum                    bool
+		ChecksumChunkSize           int64
+		Repair                      bool
+		ContinuousInterval          time.Duration
 	}{}
 
 	deleteOptions = struct {
 		Arg string
 	}{}
 
+	repairOptions = struct {
+		UUID         uuid.UUID
+		TargetShards []string
+		Apply        bool
+		Limit        int64
+	}{}
+
 	resumeOptions = struct {
 		UUID         uuid.UUID
 		TargetShards []string
@@ -127,6 +139,14 @@ var (
 		if createOptions.MaxExtraRowsToCompare < 0 {
 			return fmt.Errorf("--max-extra-rows-to-compare must not be a negative value")
 		}
+		if createOptions.ContinuousInterval != 0 {
+			if createOptions.ContinuousInterval < time.Second {
+				return fmt.Errorf("--continuous-interval must be at least 1s")
+			}
+			if createOptions.Wait {
+				return fmt.Errorf("--wait cannot be used with --continuous-interval, as a continuous vdiff runs until it is stopped")
+			}
+		}
 		return nil
 	}
 
@@ -178,6 +198,35 @@ vtctldclient --server localhost:15999 vdiff --workflow commerce2customer --targe
 		RunE: commandDelete,
 	}
 
+	// repair makes a VDiffRepair gRPC call to a vtctld.
+	repair = &cobra.Command{
+		Use:   "repair",
+		Short: "Show, or apply, the statements that repair the differences found by a VDiff created with --repair.",
+		Long: `Show, or apply, the statements that repair the differences found by a VDiff created with --repair.
+Without --apply, the pending statements are shown for review. With --apply, they are applied on each target shard primary in
+batches that wait for the tablet throttler, while the workflow's streams are stopped. Each row is read again by primary key
+first: statements whose source row changed since the diff are marked stale, and those whose target row already matches the
+source are marked unneeded, and neither is applied. You should create a new VDiff afterwards to verify the result.`,
+		Example: `vtctldclient --server localhost:15999 vdiff --workflow commerce2customer --target-keyspace customer repair a037a9e2-5628-11ee-8c99-0242ac120002
+vtctldclient --server localhost:15999 vdiff --workflow commerce2customer --target-keyspace customer repair a037a9e2-5628-11ee-8c99-0242ac120002 --apply`,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Repair"},
+		Args:                  cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			uuid, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid UUID provided: %v", err)
+			}
+			repairOptions.UUID = uuid
+			if repairOptions.Limit < 0 {
+				return fmt.Errorf("--limit must not be a negative value")
+			}
+
+			return common.ValidateShards(repairOptions.TargetShards)
+		},
+		RunE: commandRepair,
+	}
+
 	// resume makes a VDiffResume gRPC call to a vtctld.
 	resume = &cobra.Command{
 		Use:                   "resume",
@@ -300,6 +349,10 @@ func commandCreate(cmd *cobra.Command, args []string) error {
 		MaxDiffDuration:             protoutil.DurationToProto(createOptions.MaxDiffDuration),
 		RowDiffColumnTruncateAt:     createOptions.RowDiffColumnTruncateAt,
 		AutoStart:                   &createOptions.AutoStart,
+		Checksum:                    createOptions.Checksum,
+		ChecksumChunkSize:           createOptions.ChecksumChunkSize,
+		Repair:                      createOptions.Repair,
+		ContinuousInterval:          protoutil.DurationToProto(createOptions.ContinuousInterval),
 	})
 
 	if err != nil {
@@ -372,6 +425,120 @@ func commandDelete(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// repairStatementListing is a pending repair statement of a shard.
+type repairStatementListing struct {
+	Shard, Table, Type, Statement string
+}
+
+// repairSummaryListing is the number of repair statements of a shard for a
+// table, type, and state.
+type repairSummaryListing struct {
+	Shard, Table, Type, State, Statements string
+}
+
+// displayRepairResponse displays the pending repair statements, or the
+// summary of the repair statements when they were applied.
+func displayRepairResponse(out io.Writer, format string, apply bool, resp *vtctldatapb.VDiffRepairResponse) error {
+	shards := make([]string, 0, len(resp.TabletResponses))
+	for shard := range resp.TabletResponses {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+	var (
+		statements []*repairStatementListing
+		summaries  []*repairSummaryListing
+	)
+	for _, shard := range shards {
+		tabletResp := resp.TabletResponses[shard]
+		if tabletResp == nil || tabletResp.Output == nil {
+			continue
+		}
+		for _, row := range sqltypes.Proto3ToResult(tabletResp.Output).Named().Rows {
+			if apply {
+				summaries = append(summaries, &repairSummaryListing{
+					Shard:      shard,
+					Table:      row.AsString("table_name", ""),
+					Type:       row.AsString("type", ""),
+					State:      row.AsString("state", ""),
+					Statements: row.AsString("statements", ""),
+				})
+				continue
+			}
+			statements = append(statements, &repairStatementListing{
+				Shard:     shard,
+				Table:     row.AsString("table_name", ""),
+				Type:      row.AsString("type", ""),
+				Statement: row.AsString("statement", ""),
+			})
+		}
+	}
+
+	if format == "json" {
+		var listings any = statements
+		if apply {
+			listings = summaries
+		}
+		jsonText, err := cli.MarshalJSONPretty(listings)
+		if err != nil {
+			return err
+		}
+		output := string(jsonText)
+		if output == "null" {
+			output = "[]"
+		}
+		fmt.Fprintln(out, output)
+		return nil
+	}
+	if apply {
+		if len(summaries) == 0 {
+			fmt.Fprintln(out, "No repair statements found")
+			return nil
+		}
+		lines := [][]string{getStructFieldNames(repairSummaryListing{})}
+		for _, summary := range summaries {
+			lines = append(lines, []string{summary.Shard, summary.Table, summary.Type, summary.State, summary.Statements})
+		}
+		fmt.Fprintln(out, gotabulate.Create(lines).Render("grid"))
+		return nil
+	}
+	if len(statements) == 0 {
+		fmt.Fprintln(out, "No pending repair statements found")
+		return nil
+	}
+	shard := ""
+	for _, statement := range statements {
+		if statement.Shard != shard {
+			shard = statement.Shard
+			fmt.Fprintf(out, "-- Shard %s\n", shard)
+		}
+		fmt.Fprintf(out, "%s;\n", statement.Statement)
+	}
+	return nil
+}
+
+func commandRepair(cmd *cobra.Command, args []string) error {
+	format, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().VDiffRepair(common.GetCommandCtx(), &vtctldatapb.VDiffRepairRequest{
+		Workflow:       common.BaseOptions.Workflow,
+		TargetKeyspace: common.BaseOptions.TargetKeyspace,
+		Uuid:           repairOptions.UUID.String(),
+		TargetShards:   repairOptions.TargetShards,
+		Apply:          repairOptions.Apply,
+		Limit:          repairOptions.Limit,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return displayRepairResponse(cmd.OutOrStdout(), format, repairOptions.Apply, resp)
+}
+
 func commandResume(cmd *cobra.Command, args []string) error {
 	format, err := common.GetOutputFormat(cmd)
 	if err != nil {
@@ -683,10 +850,19 @@ func registerCommands(root *cobra.Command) {
 	create.Flags().DurationVar(&createOptions.MaxDiffDuration, "max-diff-duration", 0, "How long should an individual table diff run before being stopped and restarted in order to lessen the impact on tablets due to holding open database snapshots for long periods of time (0 is the default and means no time limit).")
 	create.Flags().Int64Var(&createOptions.RowDiffColumnTruncateAt, "row-diff-column-truncate-at", 128, "When showing row differences, truncate the non Primary Key column values to this length. A value less than 1 means do not truncate.")
 	create.Flags().BoolVar(&createOptions.AutoStart, "auto-start", true, "Start the vdiff upon creation. When false, the vdiff will be created but will not run until resumed.")
+	create.Flags().BoolVar(&createOptions.Checksum, "checksum", false, "Compare the checksums of ranges of primary keys, computed by MySQL on the source and target tablets, and only compare the rows of the ranges whose checksums differ. Replication is stopped on the source tablets while each chunk of a table is diffed, so --tablet-types must not include primary.")
+	create.Flags().Int64Var(&createOptions.ChecksumChunkSize, "checksum-chunk-size", vdiff.DefaultChecksumChunkSize, "The number of rows in the ranges of primary keys whose checksums are compared when using --checksum.")
+	create.Flags().BoolVar(&createOptions.Repair, "repair", false, "Record every difference that is found, along with an idempotent statement that repairs the target row, so that the statements can be reviewed and applied with the repair command.")
+	create.Flags().DurationVar(&createOptions.ContinuousInterval, "continuous-interval", 0, "Keep verifying the workflow once the initial diff is done, by comparing the checksums of the rows changed since the last checkpoint at this interval, until the vdiff is stopped. The rows changed on the source shards are tracked from the positions of the workflow, and neither the workflow nor replication are stopped (0 is the default and means that the vdiff completes after the initial diff).")
 	base.AddCommand(create)
 
 	base.AddCommand(delete)
 
+	repair.Flags().BoolVar(&repairOptions.Apply, "apply", false, "Apply the pending repair statements rather than only showing them.")
+	repair.Flags().Int64Var(&repairOptions.Limit, "limit", 0, "The maximum number of pending repair statements to show for each shard (0 for all of them).")
+	repair.Flags().StringSliceVar(&repairOptions.TargetShards, "target-shards", nil, "The target shards to show or apply the repair statements on; default is all shards.")
+	base.AddCommand(repair)
+
 	resume.Flags().StringSliceVar(&resumeOptions.TargetShards, "target-shards", nil, "The target shards to resume the vdiff on; default is all shards.")
 	base.AddCommand(resume)
 
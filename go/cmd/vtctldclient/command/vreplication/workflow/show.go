@@ -56,10 +56,11 @@ func commandShow(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	req := &vtctldatapb.GetWorkflowsRequest{
-		Keyspace:    baseOptions.Keyspace,
-		Workflow:    baseOptions.Workflow,
-		IncludeLogs: workflowShowOptions.IncludeLogs,
-		Shards:      baseOptions.Shards,
+		Keyspace:           baseOptions.Keyspace,
+		Workflow:           baseOptions.Workflow,
+		IncludeLogs:        workflowShowOptions.IncludeLogs,
+		IncludeSchemaDrift: workflowShowOptions.IncludeSchemaDrift,
+		Shards:             baseOptions.Shards,
 	}
 	resp, err := common.GetClient().GetWorkflows(common.GetCommandCtx(), req)
 	if err != nil {
@@ -17,6 +17,10 @@ limitations under the License.
 package cli
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/acl"
@@ -47,9 +51,22 @@ var (
 		PreRunE: servenv.CobraPreRunE,
 		Run:     run,
 	}
+
+	// simulateRecoveriesFrom is the path of a snapshot of the database state,
+	// as returned by /api/database-state, to simulate the recoveries of.
+	simulateRecoveriesFrom string
 )
 
 func run(cmd *cobra.Command, args []string) {
+	// A simulation doesn't serve anything, so it doesn't need the servenv.
+	if simulateRecoveriesFrom != "" {
+		config.MarkConfigurationLoaded()
+		if err := simulateRecoveries(cmd, simulateRecoveriesFrom); err != nil {
+			log.Exitf("Failed to simulate the recoveries: %v", err)
+		}
+		return
+	}
+
 	servenv.Init()
 	inst.RegisterStats()
 
@@ -75,12 +92,38 @@ func run(cmd *cobra.Command, args []string) {
 	servenv.RunDefault()
 }
 
+// simulateRecoveries loads the snapshot of the database state, and prints
+// the recoveries that VTOrc would run on it, without running them.
+func simulateRecoveries(cmd *cobra.Command, snapshotPath string) error {
+	snapshot, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		return err
+	}
+	if err := inst.LoadDatabaseState(snapshot); err != nil {
+		return err
+	}
+	simulatedRecoveries, err := logic.SimulateRecoveries()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(simulatedRecoveries, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	return err
+}
+
 // addStatusParts adds UI parts to the /debug/status page of VTOrc
 func addStatusParts() {
 	servenv.AddStatusPart("Recent Recoveries", logic.TopologyRecoveriesTemplate, func() any {
 		recoveries, _ := logic.ReadRecentRecoveries(0)
 		return recoveries
 	})
+	servenv.AddStatusPart("Recovery Silences", logic.RecoverySilencesTemplate, func() any {
+		silences, _ := logic.ReadRecoverySilencesStatus()
+		return silences
+	})
 }
 
 func init() {
@@ -90,5 +133,6 @@ func init() {
 	servenv.MoveFlagsToCobraCommand(Main)
 
 	logic.RegisterFlags(Main.Flags())
+	Main.Flags().StringVar(&simulateRecoveriesFrom, "simulate-recoveries-from", "", "Path of a snapshot of the VTOrc database state, as returned by /api/database-state. When set, VTOrc prints the recoveries it would run on the snapshot as JSON and exits, without running them")
 	acl.RegisterFlags(Main.Flags())
 }
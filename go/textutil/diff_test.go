@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package textutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	lines := func(l ...string) string {
+		return strings.Join(l, "\n") + "\n"
+	}
+	tcs := []struct {
+		name     string
+		from, to string
+		want     string
+	}{{
+		name: "same",
+		from: lines("a", "b"),
+		to:   lines("a", "b"),
+		want: "",
+	}, {
+		name: "changed line",
+		from: lines("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		to:   lines("1", "2", "3", "4", "five", "6", "7", "8", "9"),
+		want: lines("--- v1", "+++ v2", "@@ -2,7 +2,7 @@", " 2", " 3", " 4", "-5", "+five", " 6", " 7", " 8"),
+	}, {
+		name: "from empty",
+		from: "",
+		to:   lines("a", "b"),
+		want: lines("--- v1", "+++ v2", "@@ -0,0 +1,2 @@", "+a", "+b"),
+	}, {
+		name: "to empty",
+		from: lines("a"),
+		to:   "",
+		want: lines("--- v1", "+++ v2", "@@ -1 +0,0 @@", "-a"),
+	}, {
+		name: "two hunks",
+		from: lines("a", "1", "2", "3", "4", "5", "6", "7", "8", "b"),
+		to:   lines("A", "1", "2", "3", "4", "5", "6", "7", "8", "B"),
+		want: lines("--- v1", "+++ v2", "@@ -1,4 +1,4 @@", "-a", "+A", " 1", " 2", " 3", "@@ -7,4 +7,4 @@", " 6", " 7", " 8", "-b", "+B"),
+	}, {
+		name: "merged hunks",
+		from: lines("a", "1", "2", "3", "b"),
+		to:   lines("1", "2", "3", "B", "c"),
+		want: lines("--- v1", "+++ v2", "@@ -1,5 +1,5 @@", "-a", " 1", " 2", " 3", "-b", "+B", "+c"),
+	}}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, UnifiedDiff("v1", "v2", tc.from, tc.to))
+		})
+	}
+}
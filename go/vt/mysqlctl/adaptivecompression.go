@@ -0,0 +1,253 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	// NoCompression is the compression engine recorded in the MANIFEST for
+	// the files that were stored uncompressed by adaptive compression.
+	NoCompression = "none"
+
+	// zstdDictionaryFileName is the name of the file that holds the zstd
+	// dictionary of a backup, next to its MANIFEST.
+	zstdDictionaryFileName = "ZSTD_DICTIONARY"
+
+	// zstdDictionarySampleSize is the size of the samples the zstd dictionary
+	// is trained on, which is the size of an InnoDB page.
+	zstdDictionarySampleSize = 16 * 1024
+	// zstdDictionarySamplesPerFile is the maximum number of samples taken
+	// from every file to train the zstd dictionary.
+	zstdDictionarySamplesPerFile = 16
+)
+
+var (
+	// adaptiveCompression makes the builtin backup engine choose the
+	// compression of every file separately.
+	adaptiveCompression = false
+	// adaptiveCompressionSampleSize is the size of the sample of a file used
+	// to estimate how well it compresses.
+	adaptiveCompressionSampleSize = 1024 * 1024
+	// adaptiveCompressionMinSavings is the fraction of the size of the sample
+	// of a file that compression has to save for the file to be compressed.
+	adaptiveCompressionMinSavings = 0.1
+	// adaptiveCompressionLargeFileSize is the size from which files are
+	// compressed with adaptiveCompressionLargeFileEngine.
+	adaptiveCompressionLargeFileSize int64 = 1024 * 1024 * 1024
+	// adaptiveCompressionLargeFileEngine is the engine large files are
+	// compressed with, usually a faster one than the compression engine.
+	adaptiveCompressionLargeFileEngine = Lz4Compressor
+
+	// zstdDictionaryEnabled trains a zstd dictionary on samples of the files
+	// of a backup, used to compress the files compressed with zstd.
+	zstdDictionaryEnabled = false
+	// zstdDictionaryMaxSize is the maximum size of the zstd dictionary.
+	zstdDictionaryMaxSize = 112640
+)
+
+func init() {
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerAdaptiveCompressionFlags)
+	}
+}
+
+func registerAdaptiveCompressionFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&adaptiveCompression, "compression-adaptive", adaptiveCompression, "(builtin backup engine only) Choose the compression of every file of a backup separately. Files that do not compress well, such as compressed or encrypted tablespaces, are stored uncompressed, and large files are compressed with --compression-adaptive-large-file-engine. The choice is recorded in the MANIFEST.")
+	fs.IntVar(&adaptiveCompressionSampleSize, "compression-adaptive-sample-size", adaptiveCompressionSampleSize, "Number of bytes read from the middle of a file to estimate how well it compresses, when using --compression-adaptive.")
+	fs.Float64Var(&adaptiveCompressionMinSavings, "compression-adaptive-min-savings", adaptiveCompressionMinSavings, "Minimum fraction of its size that compressing the sample of a file must save for the file to be compressed, when using --compression-adaptive.")
+	fs.Int64Var(&adaptiveCompressionLargeFileSize, "compression-adaptive-large-file-size", adaptiveCompressionLargeFileSize, "Size in bytes from which files are compressed with --compression-adaptive-large-file-engine, when using --compression-adaptive. 0 disables it.")
+	fs.StringVar(&adaptiveCompressionLargeFileEngine, "compression-adaptive-large-file-engine", adaptiveCompressionLargeFileEngine, "Compression engine used for large files, when using --compression-adaptive. Supported values are 'pgzip', 'pargzip', 'zstd' and 'lz4'.")
+	fs.BoolVar(&zstdDictionaryEnabled, "compression-zstd-dictionary", zstdDictionaryEnabled, "(builtin backup engine only) Train a zstd dictionary on samples of the files of a backup, stored with the backup and used to compress the files compressed with zstd. Mostly helps backups with many small tables.")
+	fs.IntVar(&zstdDictionaryMaxSize, "compression-zstd-dictionary-size", zstdDictionaryMaxSize, "Maximum size in bytes of the zstd dictionary trained when using --compression-zstd-dictionary.")
+}
+
+// backupCompression holds the compression settings shared by all the files of
+// a backup taken by the builtin backup engine.
+type backupCompression struct {
+	// zstdDictionary is the zstd dictionary of the backup, or nil if there is
+	// none.
+	zstdDictionary []byte
+}
+
+// chooseFileCompression returns the compression engine to use for a file of
+// the given size, given a sample of its contents. It returns NoCompression if
+// compressing the sample does not save at least adaptiveCompressionMinSavings
+// of its size.
+func chooseFileCompression(size int64, sample []byte) (string, error) {
+	if len(sample) > 0 {
+		savings, err := compressionSavings(sample)
+		if err != nil {
+			return "", err
+		}
+		if savings < adaptiveCompressionMinSavings {
+			return NoCompression, nil
+		}
+	}
+	// An external compressor is used for every compressed file.
+	if ExternalCompressorCmd == "" && adaptiveCompressionLargeFileSize > 0 && size >= adaptiveCompressionLargeFileSize {
+		return adaptiveCompressionLargeFileEngine, nil
+	}
+	return CompressionEngineName, nil
+}
+
+// compressionSavings returns the fraction of the size of data that a fast zstd
+// compression saves.
+func compressionSavings(data []byte) (float64, error) {
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return 0, err
+	}
+	defer enc.Close()
+	compressed := enc.EncodeAll(data, nil)
+	return 1 - float64(len(compressed))/float64(len(data)), nil
+}
+
+// readFileSample returns up to sampleSize bytes from the middle of the file,
+// where the data is more representative of the file than its headers.
+func readFileSample(f *os.File, size int64, sampleSize int) ([]byte, error) {
+	if sampleSize <= 0 || size <= 0 {
+		return nil, nil
+	}
+	n := min(int64(sampleSize), size)
+	sample := make([]byte, n)
+	read, err := f.ReadAt(sample, (size-n)/2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return sample[:read], nil
+}
+
+// trainZstdDictionary trains a zstd dictionary on pages sampled evenly from
+// the files to back up. It returns nil if there are too few samples to train
+// a dictionary.
+func trainZstdDictionary(cnf *Mycnf, fes []FileEntry, logger logutil.Logger) ([]byte, error) {
+	var samples [][]byte
+	for i := range fes {
+		fe := &fes[i]
+		f, err := fe.open(cnf, true)
+		if err != nil {
+			return nil, err
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		pages := fi.Size() / zstdDictionarySampleSize
+		step := max(pages/zstdDictionarySamplesPerFile, 1)
+		for page := int64(0); page < pages && page/step < zstdDictionarySamplesPerFile; page += step {
+			sample := make([]byte, zstdDictionarySampleSize)
+			if _, err := f.ReadAt(sample, page*zstdDictionarySampleSize); err != nil {
+				f.Close()
+				return nil, vterrors.Wrapf(err, "cannot sample %v", fe.Name)
+			}
+			samples = append(samples, sample)
+		}
+		f.Close()
+	}
+
+	zstdDictionary, err := dict.BuildZstdDict(samples, dict.Options{
+		MaxDictSize: zstdDictionaryMaxSize,
+		HashBytes:   6,
+		ZstdLevel:   zstd.EncoderLevel(compressionLevel),
+	})
+	if err != nil {
+		logger.Warningf("Not using a zstd dictionary, it could not be trained on %d samples: %v", len(samples), err)
+		return nil, nil
+	}
+	logger.Infof("Trained a zstd dictionary of %d bytes on %d samples", len(zstdDictionary), len(samples))
+	return zstdDictionary, nil
+}
+
+// backupZstdDictionary writes the zstd dictionary next to the MANIFEST.
+func backupZstdDictionary(ctx context.Context, bh backupstorage.BackupHandle, zstdDictionary []byte) (finalErr error) {
+	wc, err := bh.AddFile(ctx, zstdDictionaryFileName, int64(len(zstdDictionary)))
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot add %v to backup", zstdDictionaryFileName)
+	}
+	defer func() {
+		if err := wc.Close(); err != nil {
+			finalErr = errors.Join(finalErr, vterrors.Wrapf(err, "cannot close %v", zstdDictionaryFileName))
+		}
+	}()
+	if _, err := wc.Write(zstdDictionary); err != nil {
+		return vterrors.Wrapf(err, "cannot write %v", zstdDictionaryFileName)
+	}
+	return nil
+}
+
+// restoreZstdDictionary reads the zstd dictionary of a backup.
+func restoreZstdDictionary(ctx context.Context, bh backupstorage.BackupHandle, name string) ([]byte, error) {
+	rc, err := bh.ReadFile(ctx, name)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot read %v", name)
+	}
+	defer rc.Close()
+	zstdDictionary, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot read %v", name)
+	}
+	return zstdDictionary, nil
+}
+
+// newZstdDictCompressor returns a writer that compresses to writer with zstd,
+// using the given dictionary.
+func newZstdDictCompressor(writer io.Writer, zstdDictionary []byte, logger logutil.Logger) (io.WriteCloser, error) {
+	zst, err := zstd.NewWriter(writer, zstd.WithEncoderLevel(zstd.EncoderLevel(compressionLevel)), zstd.WithEncoderDict(zstdDictionary))
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot create zstd compressor")
+	}
+	logger.Infof("Compressing backup using engine %q with a dictionary", ZstdCompressor)
+	return zst, nil
+}
+
+// newZstdDictDecompressor returns a reader that decompresses reader with zstd,
+// using the given dictionary.
+func newZstdDictDecompressor(reader io.Reader, zstdDictionary []byte, logger logutil.Logger) (io.ReadCloser, error) {
+	d, err := zstd.NewReader(reader, zstd.WithDecoderDicts(zstdDictionary))
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("Decompressing backup using engine %q with a dictionary", ZstdCompressor)
+	return d.IOReadCloser(), nil
+}
+
+// validateAdaptiveCompression returns an error if the adaptive compression
+// flags are invalid.
+func validateAdaptiveCompression() error {
+	switch adaptiveCompressionLargeFileEngine {
+	case PgzipCompressor, PargzipCompressor, ZstdCompressor, Lz4Compressor:
+	default:
+		return fmt.Errorf("%w value for --compression-adaptive-large-file-engine: %q", errUnsupportedCompressionEngine, adaptiveCompressionLargeFileEngine)
+	}
+	return nil
+}
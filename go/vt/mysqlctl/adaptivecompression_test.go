@@ -0,0 +1,157 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+)
+
+func TestChooseFileCompression(t *testing.T) {
+	oldLargeFileSize := adaptiveCompressionLargeFileSize
+	oldExternalCompressorCmd := ExternalCompressorCmd
+	defer func() {
+		adaptiveCompressionLargeFileSize = oldLargeFileSize
+		ExternalCompressorCmd = oldExternalCompressorCmd
+	}()
+	adaptiveCompressionLargeFileSize = 1024
+
+	random := make([]byte, 4096)
+	_, err := rand.Read(random)
+	require.NoError(t, err)
+	repetitive := bytes.Repeat([]byte("vitess "), 512)
+
+	tests := []struct {
+		name                  string
+		size                  int64
+		sample                []byte
+		externalCompressorCmd string
+		want                  string
+	}{
+		{"incompressible", 100, random, "", NoCompression},
+		{"compressible", 100, repetitive, "", CompressionEngineName},
+		{"large", 4096, repetitive, "", adaptiveCompressionLargeFileEngine},
+		{"large incompressible", 4096, random, "", NoCompression},
+		{"large external", 4096, repetitive, "gzip", CompressionEngineName},
+		{"empty", 0, nil, "", CompressionEngineName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ExternalCompressorCmd = tt.externalCompressorCmd
+			engine, err := chooseFileCompression(tt.size, tt.sample)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, engine)
+		})
+	}
+}
+
+func TestReadFileSample(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "sample")
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString("0123456789")
+	require.NoError(t, err)
+
+	sample, err := readFileSample(f, 10, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "3456", string(sample))
+
+	sample, err = readFileSample(f, 10, 100)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(sample))
+
+	sample, err = readFileSample(f, 10, 0)
+	require.NoError(t, err)
+	assert.Nil(t, sample)
+}
+
+func TestZstdDictionary(t *testing.T) {
+	dir := t.TempDir()
+	cnf := &Mycnf{DataDir: dir}
+	var fes []FileEntry
+	for i := range 10 {
+		var buf bytes.Buffer
+		for row := 0; buf.Len() < 4*zstdDictionarySampleSize; row++ {
+			fmt.Fprintf(&buf, "{\"id\": %d, \"table\": \"t%d\", \"name\": \"customer-%d\", \"email\": \"customer%d@example.com\"}\n", row, i, row*7, row*13)
+		}
+		name := fmt.Sprintf("t%d.ibd", i)
+		require.NoError(t, os.WriteFile(path.Join(dir, name), buf.Bytes(), 0o644))
+		fes = append(fes, FileEntry{Base: backupData, Name: name})
+	}
+
+	logger := logutil.NewMemoryLogger()
+	zstdDictionary, err := trainZstdDictionary(cnf, fes, logger)
+	require.NoError(t, err)
+	require.NotEmpty(t, zstdDictionary)
+
+	data := []byte("{\"id\": 42, \"table\": \"t3\", \"name\": \"customer-294\", \"email\": \"customer546@example.com\"}\n")
+	var compressed bytes.Buffer
+	compressor, err := newZstdDictCompressor(&compressed, zstdDictionary, logger)
+	require.NoError(t, err)
+	_, err = compressor.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, compressor.Close())
+
+	decompressor, err := newZstdDictDecompressor(&compressed, zstdDictionary, logger)
+	require.NoError(t, err)
+	defer decompressor.Close()
+	decompressed, err := io.ReadAll(decompressor)
+	require.NoError(t, err)
+	assert.Equal(t, data, decompressed)
+}
+
+func TestFileCompressionEngine(t *testing.T) {
+	tests := []struct {
+		name string
+		bm   builtinBackupManifest
+		fe   FileEntry
+		want string
+	}{
+		{"manifest engine", builtinBackupManifest{CompressionEngine: ZstdCompressor}, FileEntry{}, ZstdCompressor},
+		{"older backup", builtinBackupManifest{}, FileEntry{}, PgzipCompressor},
+		{"uncompressed backup", builtinBackupManifest{SkipCompress: true, CompressionEngine: ZstdCompressor}, FileEntry{}, NoCompression},
+		{"uncompressed file", builtinBackupManifest{CompressionEngine: ZstdCompressor}, FileEntry{CompressionEngine: NoCompression}, NoCompression},
+		{"file engine", builtinBackupManifest{CompressionEngine: ZstdCompressor}, FileEntry{CompressionEngine: Lz4Compressor}, Lz4Compressor},
+		{"pargzip file", builtinBackupManifest{CompressionEngine: ZstdCompressor}, FileEntry{CompressionEngine: PargzipCompressor}, PgzipCompressor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.bm.fileCompressionEngine(&tt.fe))
+		})
+	}
+}
+
+func TestValidateAdaptiveCompression(t *testing.T) {
+	oldEngine := adaptiveCompressionLargeFileEngine
+	defer func() { adaptiveCompressionLargeFileEngine = oldEngine }()
+
+	adaptiveCompressionLargeFileEngine = Lz4Compressor
+	assert.NoError(t, validateAdaptiveCompression())
+
+	adaptiveCompressionLargeFileEngine = "foobar"
+	assert.ErrorIs(t, validateAdaptiveCompression(), errUnsupportedCompressionEngine)
+}
@@ -92,6 +92,13 @@ type BackupParams struct {
 	MysqlShutdownTimeout time.Duration
 	// BackupEngine allows us to override which backup engine should be used for a request
 	BackupEngine string
+	// DbName is the name of the managed database / schema
+	DbName string
+	// TableStreamer is used by the logical backup engine to read the rows of all tables
+	// from a consistent snapshot. It is only available on a running vttablet.
+	TableStreamer TableStreamer
+	// Progress, if set, is updated by the backup engine as the backup runs.
+	Progress *BackupProgress
 }
 
 func (b *BackupParams) Copy() BackupParams {
@@ -110,6 +117,9 @@ func (b *BackupParams) Copy() BackupParams {
 		Stats:                b.Stats,
 		UpgradeSafe:          b.UpgradeSafe,
 		MysqlShutdownTimeout: b.MysqlShutdownTimeout,
+		DbName:               b.DbName,
+		TableStreamer:        b.TableStreamer,
+		Progress:             b.Progress,
 	}
 }
 
@@ -150,6 +160,8 @@ type RestoreParams struct {
 	MysqlShutdownTimeout time.Duration
 	// AllowedBackupEngines if present will filter out any backups taken with engines not included in the list
 	AllowedBackupEngines []string
+	// Progress, if set, is updated by the restore engine as the restore runs.
+	Progress *BackupProgress
 }
 
 func (p *RestoreParams) Copy() RestoreParams {
@@ -169,6 +181,7 @@ func (p *RestoreParams) Copy() RestoreParams {
 		DryRun:               p.DryRun,
 		Stats:                p.Stats,
 		MysqlShutdownTimeout: p.MysqlShutdownTimeout,
+		Progress:             p.Progress,
 	}
 }
 
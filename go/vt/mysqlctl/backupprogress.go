@@ -0,0 +1,243 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+const (
+	// BackupProgressOperationBackup is the operation of a BackupProgress tracking a backup.
+	BackupProgressOperationBackup = "backup"
+	// BackupProgressOperationRestore is the operation of a BackupProgress tracking a restore.
+	BackupProgressOperationRestore = "restore"
+
+	backupPhaseCopyingFiles    = "copying files"
+	backupPhaseWritingManifest = "writing manifest"
+)
+
+// BackupProgress tracks the progress of a backup or a restore, so that it can
+// be reported while the operation runs. Backup engines record the files they
+// copy and the phase they are in. All the methods can be called on a nil
+// *BackupProgress, in which case they do nothing.
+type BackupProgress struct {
+	operation string
+	startedAt time.Time
+	now       func() time.Time
+
+	mu sync.Mutex
+	// phase is the current phase of the operation.
+	phase   string
+	running bool
+	err     error
+	// bytesTotal is the number of bytes the operation copies, if known before
+	// the files are copied.
+	bytesTotal int64
+	// copyStartedAt is when the first file started being copied.
+	copyStartedAt time.Time
+	files         []*FileProgress
+	filesByName   map[string]*FileProgress
+}
+
+// FileProgress tracks the bytes copied for a single file of a backup or restore.
+type FileProgress struct {
+	name       string
+	bytesTotal atomic.Int64
+	bytesDone  atomic.Int64
+	done       atomic.Bool
+}
+
+// NewBackupProgress returns a running BackupProgress for the given operation.
+func NewBackupProgress(operation string) *BackupProgress {
+	return newBackupProgress(operation, time.Now)
+}
+
+func newBackupProgress(operation string, now func() time.Time) *BackupProgress {
+	return &BackupProgress{
+		operation:   operation,
+		startedAt:   now(),
+		now:         now,
+		phase:       "starting",
+		running:     true,
+		filesByName: make(map[string]*FileProgress),
+	}
+}
+
+// SetPhase records the current phase of the operation.
+func (p *BackupProgress) SetPhase(phase string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.phase = phase
+}
+
+// AddBytesTotal adds to the number of bytes the operation will copy, when it is
+// known before the files start being copied. A restore that applies several
+// backups adds the size of each of them.
+func (p *BackupProgress) AddBytesTotal(bytesTotal int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bytesTotal += bytesTotal
+}
+
+// Finish marks the operation as over, failed if err is not nil.
+func (p *BackupProgress) Finish(err error) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running = false
+	p.err = err
+	if err != nil {
+		p.phase = "failed"
+	} else {
+		p.phase = "complete"
+	}
+}
+
+// StartFile returns the FileProgress of the given file, with bytesTotal being the
+// size of the file or 0 if it is not known. Starting a file again, as is done
+// when retrying it, resets the number of bytes done.
+func (p *BackupProgress) StartFile(name string, bytesTotal int64) *FileProgress {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.copyStartedAt.IsZero() {
+		p.copyStartedAt = p.now()
+	}
+	fp, ok := p.filesByName[name]
+	if !ok {
+		fp = &FileProgress{name: name}
+		p.files = append(p.files, fp)
+		p.filesByName[name] = fp
+	}
+	fp.bytesTotal.Store(bytesTotal)
+	fp.bytesDone.Store(0)
+	fp.done.Store(false)
+	return fp
+}
+
+// Proto returns a snapshot of the progress.
+func (p *BackupProgress) Proto() *tabletmanagerdatapb.BackupProgress {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	progress := &tabletmanagerdatapb.BackupProgress{
+		Operation: p.operation,
+		Phase:     p.phase,
+		Running:   p.running,
+		StartedAt: protoutil.TimeToProto(p.startedAt),
+		Files:     make([]*tabletmanagerdatapb.BackupFileProgress, 0, len(p.files)),
+	}
+	if p.err != nil {
+		progress.Error = p.err.Error()
+	}
+
+	var filesTotal int64
+	for _, fp := range p.files {
+		fileProgress := &tabletmanagerdatapb.BackupFileProgress{
+			Name:       fp.name,
+			BytesTotal: fp.bytesTotal.Load(),
+			BytesDone:  fp.bytesDone.Load(),
+			Done:       fp.done.Load(),
+		}
+		filesTotal += fileProgress.BytesTotal
+		progress.BytesDone += fileProgress.BytesDone
+		progress.Files = append(progress.Files, fileProgress)
+	}
+	progress.BytesTotal = max(p.bytesTotal, filesTotal)
+
+	if p.running && progress.BytesTotal > 0 && progress.BytesDone > 0 && !p.copyStartedAt.IsZero() {
+		elapsed := p.now().Sub(p.copyStartedAt)
+		remaining := max(progress.BytesTotal-progress.BytesDone, 0)
+		eta := time.Duration(float64(elapsed) * float64(remaining) / float64(progress.BytesDone))
+		progress.Eta = protoutil.DurationToProto(eta)
+	}
+	return progress
+}
+
+// Add records that n more bytes of the file were copied.
+func (fp *FileProgress) Add(n int64) {
+	if fp == nil {
+		return
+	}
+	fp.bytesDone.Add(n)
+}
+
+// Done records that the file was fully copied.
+func (fp *FileProgress) Done() {
+	if fp == nil {
+		return
+	}
+	fp.done.Store(true)
+}
+
+// Reader returns a reader that records the bytes read from r.
+func (fp *FileProgress) Reader(r io.Reader) io.Reader {
+	if fp == nil {
+		return r
+	}
+	return &fileProgressReader{fp: fp, r: r}
+}
+
+// Writer returns a writer that records the bytes written to w.
+func (fp *FileProgress) Writer(w io.Writer) io.Writer {
+	if fp == nil {
+		return w
+	}
+	return &fileProgressWriter{fp: fp, w: w}
+}
+
+type fileProgressReader struct {
+	fp *FileProgress
+	r  io.Reader
+}
+
+func (r *fileProgressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.fp.Add(int64(n))
+	return n, err
+}
+
+type fileProgressWriter struct {
+	fp *FileProgress
+	w  io.Writer
+}
+
+func (w *fileProgressWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.fp.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+)
+
+func TestBackupProgress(t *testing.T) {
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	progress := newBackupProgress(BackupProgressOperationRestore, func() time.Time { return now })
+
+	p := progress.Proto()
+	assert.Equal(t, BackupProgressOperationRestore, p.Operation)
+	assert.Equal(t, "starting", p.Phase)
+	assert.True(t, p.Running)
+	assert.Equal(t, now, protoutil.TimeFromProto(p.StartedAt).UTC())
+	assert.Nil(t, p.Eta)
+
+	progress.AddBytesTotal(400)
+	progress.SetPhase(backupPhaseCopyingFiles)
+	f1 := progress.StartFile("f1", 100)
+	f2 := progress.StartFile("f2", 0)
+
+	_, err := io.Copy(io.Discard, f1.Reader(bytes.NewReader(make([]byte, 100))))
+	require.NoError(t, err)
+	f1.Done()
+	_, err = f2.Writer(io.Discard).Write(make([]byte, 100))
+	require.NoError(t, err)
+
+	// Half of the bytes were copied in 10 seconds, so the rest should take as long.
+	now = now.Add(10 * time.Second)
+	p = progress.Proto()
+	assert.Equal(t, backupPhaseCopyingFiles, p.Phase)
+	assert.EqualValues(t, 400, p.BytesTotal)
+	assert.EqualValues(t, 200, p.BytesDone)
+	require.Len(t, p.Files, 2)
+	assert.Equal(t, "f1", p.Files[0].Name)
+	assert.True(t, p.Files[0].Done)
+	assert.EqualValues(t, 100, p.Files[0].BytesDone)
+	assert.False(t, p.Files[1].Done)
+	eta, _, err := protoutil.DurationFromProto(p.Eta)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, eta)
+
+	// Retrying a file starts it over.
+	progress.StartFile("f2", 0)
+	p = progress.Proto()
+	require.Len(t, p.Files, 2)
+	assert.EqualValues(t, 100, p.BytesDone)
+
+	progress.Finish(errors.New("boom"))
+	p = progress.Proto()
+	assert.False(t, p.Running)
+	assert.Equal(t, "failed", p.Phase)
+	assert.Equal(t, "boom", p.Error)
+	assert.Nil(t, p.Eta)
+}
+
+func TestBackupProgressNil(t *testing.T) {
+	var progress *BackupProgress
+	progress.SetPhase("phase")
+	progress.AddBytesTotal(1)
+	fp := progress.StartFile("f", 1)
+	assert.Nil(t, fp)
+	fp.Add(1)
+	fp.Done()
+	var buf bytes.Buffer
+	_, err := fp.Writer(&buf).Write([]byte("x"))
+	require.NoError(t, err)
+	assert.Equal(t, "x", buf.String())
+	progress.Finish(nil)
+	assert.Nil(t, progress.Proto())
+}
+
+func TestRestoreSize(t *testing.T) {
+	assert.EqualValues(t, 30, restoreSize([]FileEntry{{Size: 10}, {Size: 20}}))
+	// An empty file doesn't make the size unknown.
+	assert.EqualValues(t, 10, restoreSize([]FileEntry{{Size: 10}, {}}))
+	// Backups taken before the size was recorded have no known size.
+	assert.EqualValues(t, 0, restoreSize([]FileEntry{{}, {}}))
+}
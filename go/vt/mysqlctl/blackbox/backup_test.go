@@ -116,6 +116,7 @@ func TestExecuteBackup(t *testing.T) {
 	// mysqld.ShutdownTime = time.Minute
 
 	fakeStats := backupstats.NewFakeStats()
+	progress := mysqlctl.NewBackupProgress(mysqlctl.BackupProgressOperationBackup)
 
 	backupResult, err := be.ExecuteBackup(ctx, mysqlctl.BackupParams{
 		Logger: logutil.NewConsoleLogger(),
@@ -132,11 +133,24 @@ func TestExecuteBackup(t *testing.T) {
 		Shard:                shard,
 		Stats:                fakeStats,
 		MysqlShutdownTimeout: MysqlShutdownTimeout,
+		Progress:             progress,
 	}, bh)
 
 	require.NoError(t, err)
 	assert.Equal(t, mysqlctl.BackupUsable, backupResult)
 
+	// Every file was fully copied, and counted in the progress.
+	progressProto := progress.Proto()
+	assert.Equal(t, "writing manifest", progressProto.Phase)
+	require.Len(t, progressProto.Files, 4)
+	for _, file := range progressProto.Files {
+		assert.True(t, file.Done, file.Name)
+		assert.EqualValues(t, len("hello, world!"), file.BytesTotal, file.Name)
+		assert.Equal(t, file.BytesTotal, file.BytesDone, file.Name)
+	}
+	assert.EqualValues(t, 4*len("hello, world!"), progressProto.BytesTotal)
+	assert.Equal(t, progressProto.BytesTotal, progressProto.BytesDone)
+
 	var destinationCloseStats int
 	var destinationOpenStats int
 	var destinationWriteStats int
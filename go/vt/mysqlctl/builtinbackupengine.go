@@ -130,6 +130,14 @@ type builtinBackupManifest struct {
 	// ExternalDecompressor will be used. If neither are set, the restore will
 	// abort.
 	ExternalDecompressor string
+
+	// ZstdDictionary is the name of the file of the backup that holds the
+	// zstd dictionary used by the files whose ZstdDictionary is set, if any.
+	ZstdDictionary string `json:",omitempty"`
+
+	// zstdDictionary is the content of the ZstdDictionary file, read before
+	// restoring the files.
+	zstdDictionary []byte
 }
 
 // FileEntry is one file to backup
@@ -152,6 +160,21 @@ type FileEntry struct {
 	// for writing files in a temporary directory
 	ParentPath string
 
+	// Size is the size of the file before it was transformed and compressed.
+	// It is used to report the progress of restores, and is not set for
+	// backups taken before it was introduced.
+	Size int64 `json:",omitempty"`
+
+	// CompressionEngine is the compression engine the file was compressed
+	// with, or NoCompression if it was stored uncompressed, when the backup
+	// was taken with adaptive compression. It is empty otherwise, in which
+	// case the compression settings of the MANIFEST apply.
+	CompressionEngine string `json:",omitempty"`
+
+	// ZstdDictionary is true if the file was compressed with the zstd
+	// dictionary of the backup.
+	ZstdDictionary bool `json:",omitempty"`
+
 	// RetryCount specifies how many times we retried restoring/backing up this FileEntry.
 	// If we fail to restore/backup this FileEntry, we will retry up to maxRetriesPerFile times.
 	// Every time the builtin backup engine retries this file, we increment this field by 1.
@@ -600,21 +623,29 @@ func (be *BuiltinBackupEngine) backupFiles(
 	defer cancel()
 
 	// Get the files to backup.
-	// We don't care about totalSize because we add each file separately.
 	var fes []FileEntry
+	var totalSize int64
 	var err error
 	if isIncrementalBackup(params) {
-		fes, _, err = binlogFilesToBackup(params.Cnf, binlogFiles)
+		fes, totalSize, err = binlogFilesToBackup(params.Cnf, binlogFiles)
 	} else {
-		fes, _, err = findFilesToBackup(params.Cnf)
+		fes, totalSize, err = findFilesToBackup(params.Cnf)
 	}
 	if err != nil {
 		return vterrors.Wrap(err, "can't find files to backup")
 	}
 	params.Logger.Infof("found %v files to backup", len(fes))
+	params.Progress.AddBytesTotal(totalSize)
+
+	bc, err := be.prepareCompression(ctx, params, bh, fes)
+	if err != nil {
+		return err
+	}
+
+	params.Progress.SetPhase(backupPhaseCopyingFiles)
 
 	// The error here can be ignored safely. Failed FileEntry's are handled in the next 'if' statement.
-	_ = be.backupFileEntries(ctx, fes, bh, params)
+	_ = be.backupFileEntries(ctx, fes, bh, params, bc)
 
 	// BackupHandle supports the BackupErrorRecorder interface for tracking errors
 	// across any goroutines that fan out to take the backup. This means that we
@@ -645,16 +676,17 @@ func (be *BuiltinBackupEngine) backupFiles(
 			}
 			bh.ResetErrorForFile(file)
 		}
-		err = be.backupFileEntries(ctx, newFEs, bh, params)
+		err = be.backupFileEntries(ctx, newFEs, bh, params, bc)
 		if err != nil {
 			return err
 		}
 	}
 
 	// Backup the MANIFEST file and apply retry logic.
+	params.Progress.SetPhase(backupPhaseWritingManifest)
 	var manifestErr error
 	for currentRetry := 0; currentRetry <= maxRetriesPerFile; currentRetry++ {
-		manifestErr = be.backupManifest(ctx, params, bh, backupPosition, purgedPosition, fromPosition, fromBackupName, serverUUID, mysqlVersion, incrDetails, fes, currentRetry)
+		manifestErr = be.backupManifest(ctx, params, bh, backupPosition, purgedPosition, fromPosition, fromBackupName, serverUUID, mysqlVersion, incrDetails, fes, bc, currentRetry)
 		if manifestErr == nil {
 			break
 		}
@@ -666,11 +698,46 @@ func (be *BuiltinBackupEngine) backupFiles(
 	return nil
 }
 
+// prepareCompression validates the compression settings of the backup and, if
+// enabled, trains the zstd dictionary of the backup and stores it.
+func (be *BuiltinBackupEngine) prepareCompression(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fes []FileEntry) (*backupCompression, error) {
+	bc := &backupCompression{}
+	if !backupStorageCompress {
+		return bc, nil
+	}
+	if adaptiveCompression {
+		if err := validateAdaptiveCompression(); err != nil {
+			return nil, err
+		}
+	}
+	if !zstdDictionaryEnabled {
+		return bc, nil
+	}
+	usesZstd := CompressionEngineName == ZstdCompressor || (adaptiveCompression && adaptiveCompressionLargeFileEngine == ZstdCompressor)
+	if ExternalCompressorCmd != "" || !usesZstd {
+		params.Logger.Warningf("Not using a zstd dictionary, files are not compressed with the builtin %q engine", ZstdCompressor)
+		return bc, nil
+	}
+
+	zstdDictionary, err := trainZstdDictionary(params.Cnf, fes, params.Logger)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot train zstd dictionary")
+	}
+	if zstdDictionary == nil {
+		return bc, nil
+	}
+	if err := backupZstdDictionary(ctx, bh, zstdDictionary); err != nil {
+		return nil, err
+	}
+	bc.zstdDictionary = zstdDictionary
+	return bc, nil
+}
+
 // backupFileEntries iterates over a slice of FileEntry, backing them up concurrently up to the defined concurrency limit.
 // This function will ignore empty FileEntry, allowing the retry mechanism to send a partially empty slice, to not
 // mess up the index of retriable FileEntry.
 // This function does not leave any background operation behind itself, all calls to bh.AddFile will be finished or canceled.
-func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, params BackupParams) error {
+func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []FileEntry, bh backupstorage.BackupHandle, params BackupParams, bc *backupCompression) error {
 	ctxCancel, cancel := context.WithCancel(ctx)
 	defer func() {
 		// If we reached this defer in all cases we can cancel the context.
@@ -704,7 +771,7 @@ func (be *BuiltinBackupEngine) backupFileEntries(ctx context.Context, fes []File
 
 			// Backup the individual file.
 			var errBackupFile error
-			if errBackupFile = be.backupFile(ctxCancel, params, bh, fe, name); errBackupFile != nil {
+			if errBackupFile = be.backupFile(ctxCancel, params, bh, fe, name, bc); errBackupFile != nil {
 				bh.RecordError(name, vterrors.Wrapf(errBackupFile, "failed to backup file '%s'", name))
 				if fe.RetryCount >= maxRetriesPerFile {
 					// this is the last attempt, and we have an error, we can cancel everything and fail fast.
@@ -833,7 +900,7 @@ func (bp *backupPipe) ReportProgress(ctx context.Context, period time.Duration,
 }
 
 // backupFile backs up an individual file.
-func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string) (finalErr error) {
+func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string, bc *backupCompression) (finalErr error) {
 	// We need another context that does not live outside of this function.
 	// Reporting progress, compressing and writing are operations that will be
 	// over by the time we exit this function, they can use this cancelable context.
@@ -864,9 +931,24 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 	if err != nil {
 		return err
 	}
+	fe.Size = fi.Size()
+
+	compressionEngine := CompressionEngineName
+	if backupStorageCompress && adaptiveCompression {
+		sample, err := readFileSample(source, fi.Size(), adaptiveCompressionSampleSize)
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot sample %v", fe.Name)
+		}
+		if compressionEngine, err = chooseFileCompression(fi.Size(), sample); err != nil {
+			return vterrors.Wrapf(err, "cannot choose compression of %v", fe.Name)
+		}
+		fe.CompressionEngine = compressionEngine
+	}
+
+	fileProgress := params.Progress.StartFile(fe.Name, fi.Size())
 
 	retryStr := retryToString(fe.RetryCount)
-	br := newBackupReader(fe.Name, fi.Size(), timedSource)
+	br := newBackupReader(fe.Name, fi.Size(), fileProgress.Reader(backupReadLimiter.reader(cancelableCtx, timedSource)))
 	go br.ReportProgress(cancelableCtx, builtinBackupProgress, params.Logger, false /*restore*/, retryStr)
 
 	// Open the destination file for writing, and a buffer.
@@ -891,7 +973,7 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 	destStats := params.Stats.Scope(stats.Operation("Destination:Write"))
 	timedDest := ioutil.NewMeteredWriteCloser(dest, destStats.TimedIncrementBytes)
 
-	bw := newBackupWriter(fe.Name, builtinBackupStorageWriteBufferSize, fi.Size(), timedDest)
+	bw := newBackupWriter(fe.Name, builtinBackupStorageWriteBufferSize, fi.Size(), backupWriteLimiter.writer(cancelableCtx, timedDest))
 
 	// We create the following inner function because:
 	// - we must `defer` the compressor's Close() function
@@ -912,12 +994,16 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 
 		}()
 		// Create the gzip compression pipe, if necessary.
-		if backupStorageCompress {
+		if backupStorageCompress && compressionEngine != NoCompression {
 			var compressor io.WriteCloser
-			if ExternalCompressorCmd != "" {
+			switch {
+			case ExternalCompressorCmd != "":
 				compressor, err = newExternalCompressor(cancelableCtx, ExternalCompressorCmd, writer, params.Logger)
-			} else {
-				compressor, err = newBuiltinCompressor(CompressionEngineName, writer, params.Logger)
+			case compressionEngine == ZstdCompressor && bc.zstdDictionary != nil:
+				compressor, err = newZstdDictCompressor(writer, bc.zstdDictionary, params.Logger)
+				fe.ZstdDictionary = true
+			default:
+				compressor, err = newBuiltinCompressor(compressionEngine, writer, params.Logger)
 			}
 			if err != nil {
 				return vterrors.Wrap(err, "can't create compressor")
@@ -959,6 +1045,7 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 
 	// Save the hash.
 	fe.Hash = bw.HashString()
+	fileProgress.Done()
 	return nil
 }
 
@@ -974,6 +1061,7 @@ func (be *BuiltinBackupEngine) backupManifest(
 	mysqlVersion string,
 	incrDetails *IncrementalBackupDetails,
 	fes []FileEntry,
+	bc *backupCompression,
 	currentAttempt int,
 ) (finalErr error) {
 	retryStr := retryToString(currentAttempt)
@@ -1028,6 +1116,9 @@ func (be *BuiltinBackupEngine) backupManifest(
 			CompressionEngine:    CompressionEngineName,
 			ExternalDecompressor: ManifestExternalDecompressorCmd,
 		}
+		if bc.zstdDictionary != nil {
+			bm.ZstdDictionary = zstdDictionaryFileName
+		}
 		data, err := json.MarshalIndent(bm, "", "  ")
 		if err != nil {
 			return vterrors.Wrapf(err, "cannot JSON encode %v %s", backupManifestFileName, retryStr)
@@ -1170,7 +1261,14 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 			return "", err
 		}
 	}
+	if bm.ZstdDictionary != "" {
+		if bm.zstdDictionary, err = restoreZstdDictionary(ctx, bh, bm.ZstdDictionary); err != nil {
+			return "", err
+		}
+	}
 	fes := bm.FileEntries
+	params.Progress.AddBytesTotal(restoreSize(fes))
+	params.Progress.SetPhase(backupPhaseCopyingFiles)
 	_ = be.restoreFileEntries(ctx, fes, bh, bm, params, createdDir)
 	if files := bh.GetFailedFiles(); len(files) > 0 {
 		newFEs := make([]FileEntry, len(fes))
@@ -1181,11 +1279,14 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 			}
 			oldFes := fes[fileNb]
 			newFEs[fileNb] = FileEntry{
-				Base:       oldFes.Base,
-				Name:       oldFes.Name,
-				ParentPath: oldFes.ParentPath,
-				Hash:       oldFes.Hash,
-				RetryCount: 1,
+				Base:              oldFes.Base,
+				Name:              oldFes.Name,
+				ParentPath:        oldFes.ParentPath,
+				Hash:              oldFes.Hash,
+				Size:              oldFes.Size,
+				CompressionEngine: oldFes.CompressionEngine,
+				ZstdDictionary:    oldFes.ZstdDictionary,
+				RetryCount:        1,
 			}
 			bh.ResetErrorForFile(file)
 		}
@@ -1253,7 +1354,7 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 	params.Stats.Scope(stats.Operation("Source:Open")).TimedIncrement(time.Since(openSourceAt))
 
 	readStats := params.Stats.Scope(stats.Operation("Source:Read"))
-	timedSource := ioutil.NewMeteredReader(source, readStats.TimedIncrementBytes)
+	timedSource := restoreReadLimiter.reader(ctx, ioutil.NewMeteredReader(source, readStats.TimedIncrementBytes))
 
 	defer func() {
 		closeSourceAt := time.Now()
@@ -1291,22 +1392,19 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 	writeStats := params.Stats.Scope(stats.Operation("Destination:Write"))
 	timedDest := ioutil.NewMeteredWriter(dest, writeStats.TimedIncrementBytes)
 
-	bufferedDest := bufio.NewWriterSize(timedDest, int(builtinBackupFileWriteBufferSize))
+	fileProgress := params.Progress.StartFile(fe.Name, fe.Size)
+	bufferedDest := bufio.NewWriterSize(fileProgress.Writer(restoreWriteLimiter.writer(ctx, timedDest)), int(builtinBackupFileWriteBufferSize))
 
 	// Create the uncompresser if needed.
-	if !bm.SkipCompress {
+	if deCompressionEngine := bm.fileCompressionEngine(fe); deCompressionEngine != NoCompression {
 		var decompressor io.ReadCloser
-		deCompressionEngine := bm.CompressionEngine
-
-		if deCompressionEngine == "" {
-			// for backward compatibility
-			deCompressionEngine = PgzipCompressor
-		}
 		externalDecompressorCmd := ExternalDecompressorCmd
 		if externalDecompressorCmd == "" && bm.ExternalDecompressor != "" {
 			externalDecompressorCmd = bm.ExternalDecompressor
 		}
-		if externalDecompressorCmd != "" {
+		if fe.ZstdDictionary {
+			decompressor, err = newZstdDictDecompressor(reader, bm.zstdDictionary, params.Logger)
+		} else if externalDecompressorCmd != "" {
 			if deCompressionEngine == ExternalCompressor {
 				deCompressionEngine = externalDecompressorCmd
 				decompressor, err = newExternalDecompressor(ctx, deCompressionEngine, reader, params.Logger)
@@ -1355,9 +1453,41 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 		return vterrors.Wrap(err, "failed to flush destination buffer")
 	}
 
+	fileProgress.Done()
 	return nil
 }
 
+// fileCompressionEngine returns the compression engine the file was
+// compressed with, or NoCompression if it is not compressed.
+func (bm *builtinBackupManifest) fileCompressionEngine(fe *FileEntry) string {
+	switch {
+	case fe.CompressionEngine == PargzipCompressor:
+		// pargzip doesn't support decompression, but writes gzip.
+		return PgzipCompressor
+	case fe.CompressionEngine != "":
+		return fe.CompressionEngine
+	case bm.SkipCompress:
+		return NoCompression
+	case bm.CompressionEngine == "":
+		// for backward compatibility
+		return PgzipCompressor
+	default:
+		return bm.CompressionEngine
+	}
+}
+
+// restoreSize returns the number of bytes restored for the given files, or 0
+// if it is unknown because the backup is older than the FileEntry.Size
+// field, in which case the size of every file is 0. Files that are
+// legitimately empty don't make the size unknown.
+func restoreSize(fes []FileEntry) int64 {
+	var size int64
+	for _, fe := range fes {
+		size += fe.Size
+	}
+	return size
+}
+
 // ShouldDrainForBackup satisfies the BackupEngine interface
 // backup requires query service to be stopped, hence true
 func (be *BuiltinBackupEngine) ShouldDrainForBackup(req *tabletmanagerdatapb.BackupRequest) bool {
@@ -0,0 +1,682 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
+
+	"vitess.io/vitess/go/ioutil"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/dbconnpool"
+	stats "vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	logicalBackupEngineName = "logical"
+
+	// logicalBackupDatabaseNamePlaceholder is how GetSchema refers to the
+	// database name in CREATE statements. It is replaced with the name of the
+	// database being restored into.
+	logicalBackupDatabaseNamePlaceholder = "{{.DatabaseName}}"
+)
+
+var (
+	// logicalBackupChunkSize is the approximate number of uncompressed bytes
+	// written to each data file of a logical backup.
+	logicalBackupChunkSize = 64 * 1024 * 1024
+
+	// logicalBackupStatementRows is the maximum number of rows batched into a
+	// single INSERT statement of a logical backup data file.
+	logicalBackupStatementRows = 100
+
+	// ErrLogicalBackupUnsupported is returned when the logical backup engine
+	// is asked to do something it cannot do.
+	ErrLogicalBackupUnsupported = errors.New("ErrLogicalBackupUnsupported")
+)
+
+// TableStreamer streams the rows of every table in the tablet's database, all
+// of them as of a single consistent snapshot. It is implemented on vttablet
+// by the VStreamTables call of the tablet server.
+type TableStreamer func(ctx context.Context, send func(*binlogdatapb.VStreamTablesResponse) error) error
+
+// LogicalBackupEngine takes backups by reading the rows of every table
+// through vstreamer's consistent snapshot, and writing them out as
+// compressed SQL statements. Logical backups can be restored to a
+// different MySQL version or flavor, and a subset of their tables can be
+// restored on their own.
+type LogicalBackupEngine struct{}
+
+// logicalBackupManifest represents a logical backup.
+type logicalBackupManifest struct {
+	// BackupManifest is an anonymous embedding of the base manifest struct.
+	BackupManifest
+
+	// CompressionEngine is the builtin compression engine used on the data
+	// files. It is empty when SkipCompress is set.
+	CompressionEngine string `json:",omitempty"`
+
+	// SkipCompress is true if the data files were not compressed.
+	SkipCompress bool
+
+	// DatabaseSchema is the CREATE DATABASE statement of the backed up database.
+	DatabaseSchema string
+
+	// Tables lists all the base tables in the backup, in the order they are restored.
+	Tables []LogicalTableEntry
+
+	// Views lists the CREATE VIEW statements, which are applied after all tables are restored.
+	Views []string
+}
+
+// LogicalTableEntry is one table in a logical backup.
+type LogicalTableEntry struct {
+	// Name is the table name.
+	Name string
+
+	// Schema is the CREATE TABLE statement of the table.
+	Schema string
+
+	// Chunks are the data files holding the table rows.
+	Chunks []LogicalChunkEntry
+}
+
+// LogicalChunkEntry is one data file of a table in a logical backup.
+type LogicalChunkEntry struct {
+	// Name is the name of the file in the BackupStorage.
+	Name string
+
+	// Rows is the number of rows in the file.
+	Rows int64
+
+	// Hash is the hash of the data stored in the BackupStorage.
+	Hash string
+}
+
+func init() {
+	BackupRestoreEngineMap[logicalBackupEngineName] = &LogicalBackupEngine{}
+
+	for _, cmd := range []string{"vtcombo", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerLogicalBackupEngineFlags)
+	}
+}
+
+func registerLogicalBackupEngineFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&logicalBackupChunkSize, "logical-backup-chunk-size", logicalBackupChunkSize, "approximate number of uncompressed bytes written to each data file of a logical backup.")
+	fs.IntVar(&logicalBackupStatementRows, "logical-backup-statement-rows", logicalBackupStatementRows, "maximum number of rows in each INSERT statement of a logical backup.")
+}
+
+// ExecuteBackup runs a logical backup. Incremental backups are always taken
+// by the builtin engine, and can be restored on top of a logical backup.
+func (be *LogicalBackupEngine) ExecuteBackup(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle) (result BackupResult, finalErr error) {
+	params.Logger.Infof("Executing logical backup at %v for keyspace/shard %v/%v on tablet %v, concurrency: %v, compress: %v",
+		params.BackupTime, params.Keyspace, params.Shard, params.TabletAlias, params.Concurrency, backupStorageCompress)
+
+	if err := be.backupPreCheck(params); err != nil {
+		return BackupUnusable, vterrors.Wrap(err, "failed backup precheck")
+	}
+
+	serverUUID, err := params.Mysqld.GetServerUUID(ctx)
+	if err != nil {
+		return BackupUnusable, vterrors.Wrap(err, "can't get server uuid")
+	}
+	mysqlVersion, err := params.Mysqld.GetVersionString(ctx)
+	if err != nil {
+		return BackupUnusable, vterrors.Wrap(err, "can't get MySQL version")
+	}
+
+	sd, err := params.Mysqld.GetSchema(ctx, params.DbName, &tabletmanagerdatapb.GetSchemaRequest{IncludeViews: true})
+	if err != nil {
+		return BackupUnusable, vterrors.Wrapf(err, "can't get schema of %v", params.DbName)
+	}
+
+	bm := &logicalBackupManifest{
+		BackupManifest: BackupManifest{
+			BackupMethod: logicalBackupEngineName,
+			BackupTime:   FormatRFC3339(params.BackupTime.UTC()),
+			ServerUUID:   serverUUID,
+			TabletAlias:  params.TabletAlias,
+			Keyspace:     params.Keyspace,
+			Shard:        params.Shard,
+			MySQLVersion: mysqlVersion,
+			UpgradeSafe:  true,
+		},
+		SkipCompress:   !backupStorageCompress,
+		DatabaseSchema: sd.DatabaseSchema,
+	}
+	if backupStorageCompress {
+		bm.CompressionEngine = CompressionEngineName
+	}
+	tableIndexes := make(map[string]int)
+	for _, td := range sd.TableDefinitions {
+		switch {
+		case td.Type == tmutils.TableView:
+			bm.Views = append(bm.Views, td.Schema)
+		case schema.IsInternalOperationTableName(td.Name):
+			params.Logger.Infof("Skipping internal table %s", td.Name)
+		default:
+			tableIndexes[td.Name] = len(bm.Tables)
+			bm.Tables = append(bm.Tables, LogicalTableEntry{Name: td.Name, Schema: td.Schema})
+		}
+	}
+
+	gtid, err := be.backupTables(ctx, params, bh, bm, tableIndexes)
+	if err != nil {
+		return BackupUnusable, err
+	}
+	if gtid == "" {
+		// No rows were streamed, so there is no snapshot position to use.
+		bm.Position, err = params.Mysqld.PrimaryPosition(ctx)
+		if err != nil {
+			return BackupUnusable, vterrors.Wrap(err, "can't get position")
+		}
+	} else {
+		bm.Position, err = replication.DecodePosition(gtid)
+		if err != nil {
+			return BackupUnusable, vterrors.Wrapf(err, "can't decode snapshot position %q", gtid)
+		}
+	}
+	bm.PurgedPosition = bm.Position
+	bm.FinishedTime = FormatRFC3339(time.Now().UTC())
+
+	// open the MANIFEST
+	params.Logger.Infof("Writing backup MANIFEST")
+	mwc, err := bh.AddFile(ctx, backupManifestFileName, backupstorage.FileSizeUnknown)
+	if err != nil {
+		return BackupUnusable, vterrors.Wrapf(err, "cannot add %v to backup", backupManifestFileName)
+	}
+	defer closeFile(mwc, backupManifestFileName, params.Logger, &finalErr)
+
+	data, err := json.MarshalIndent(bm, "", "  ")
+	if err != nil {
+		return BackupUnusable, vterrors.Wrapf(err, "cannot JSON encode %v", backupManifestFileName)
+	}
+	if _, err := mwc.Write(data); err != nil {
+		return BackupUnusable, vterrors.Wrapf(err, "cannot write %v", backupManifestFileName)
+	}
+
+	params.Logger.Infof("Backup completed")
+	return BackupUsable, nil
+}
+
+// backupTables streams all the tables and writes their rows to the backup, in
+// chunks that are compressed and uploaded concurrently. It returns the GTID
+// position of the snapshot the rows were read from.
+func (be *LogicalBackupEngine) backupTables(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, bm *logicalBackupManifest, tableIndexes map[string]int) (string, error) {
+	var (
+		mu      sync.Mutex
+		gtid    string
+		current *logicalChunkWriter
+	)
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(max(params.Concurrency, 1))
+
+	// flush hands the current chunk over to a backup goroutine. Its entry is
+	// added to the manifest right away, so that chunks are restored in the
+	// order they were read no matter in which order they finish uploading.
+	flush := func() {
+		cw := current
+		current = nil
+		if cw == nil || cw.rows == 0 {
+			return
+		}
+		mu.Lock()
+		te := &bm.Tables[cw.tableIndex]
+		chunkIndex := len(te.Chunks)
+		te.Chunks = append(te.Chunks, LogicalChunkEntry{
+			Name: fmt.Sprintf("%d.%d", cw.tableIndex, chunkIndex),
+			Rows: cw.rows,
+		})
+		name := te.Chunks[chunkIndex].Name
+		mu.Unlock()
+
+		g.Go(func() error {
+			hash, err := cw.backup(gCtx, params, bh, name)
+			if err != nil {
+				bh.RecordError(name, err)
+				return vterrors.Wrapf(err, "failed to backup chunk %v of table %v", name, cw.table)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			bm.Tables[cw.tableIndex].Chunks[chunkIndex].Hash = hash
+			return nil
+		})
+	}
+
+	send := func(response *binlogdatapb.VStreamTablesResponse) error {
+		if err := gCtx.Err(); err != nil {
+			return err
+		}
+		tableIndex, ok := tableIndexes[response.TableName]
+		if !ok {
+			return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "table %v is not in the schema of the backup, it was probably created while the backup was running", response.TableName)
+		}
+		if gtid == "" {
+			gtid = response.Gtid
+		}
+		if current != nil && current.table != response.TableName {
+			flush()
+		}
+		if current == nil {
+			current = newLogicalChunkWriter(response.TableName, tableIndex, nil)
+		}
+		if len(response.Fields) > 0 {
+			current.setFields(response.Fields)
+		}
+		for _, row := range response.Rows {
+			if err := backupReadLimiter.wait(gCtx, len(row.Values)); err != nil {
+				return err
+			}
+			current.addRow(row)
+			if current.buf.Len() >= logicalBackupChunkSize {
+				fields := current.fields
+				flush()
+				current = newLogicalChunkWriter(response.TableName, tableIndex, fields)
+			}
+		}
+		return nil
+	}
+
+	streamErr := params.TableStreamer(gCtx, send)
+	if streamErr == nil {
+		flush()
+	}
+	if err := g.Wait(); err != nil {
+		return "", err
+	}
+	if streamErr != nil {
+		return "", vterrors.Wrap(streamErr, "failed to stream tables")
+	}
+	return gtid, nil
+}
+
+// logicalChunkWriter accumulates the rows of one data file of a logical
+// backup, as newline separated multi-row INSERT statements.
+type logicalChunkWriter struct {
+	table      string
+	tableIndex int
+
+	fields []*querypb.Field
+	insert string
+	buf    bytes.Buffer
+	rows   int64
+
+	// statementRows is the number of rows in the last, still open, statement.
+	statementRows int
+}
+
+func newLogicalChunkWriter(table string, tableIndex int, fields []*querypb.Field) *logicalChunkWriter {
+	cw := &logicalChunkWriter{
+		table:      table,
+		tableIndex: tableIndex,
+	}
+	if fields != nil {
+		cw.setFields(fields)
+	}
+	return cw
+}
+
+func (cw *logicalChunkWriter) setFields(fields []*querypb.Field) {
+	cw.fields = fields
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, sqlescape.EscapeID(field.Name))
+	}
+	cw.insert = fmt.Sprintf("insert into %s (%s) values ", sqlescape.EscapeID(cw.table), strings.Join(columns, ", "))
+}
+
+func (cw *logicalChunkWriter) addRow(row *querypb.Row) {
+	if cw.statementRows == 0 {
+		cw.buf.WriteString(cw.insert)
+	} else {
+		cw.buf.WriteString(", ")
+	}
+	cw.buf.WriteByte('(')
+	for i, value := range sqltypes.MakeRowTrusted(cw.fields, row) {
+		if i > 0 {
+			cw.buf.WriteString(", ")
+		}
+		value.EncodeSQL(&cw.buf)
+	}
+	cw.buf.WriteByte(')')
+	cw.rows++
+	cw.statementRows++
+	if cw.statementRows >= logicalBackupStatementRows {
+		cw.endStatement()
+	}
+}
+
+func (cw *logicalChunkWriter) endStatement() {
+	if cw.statementRows > 0 {
+		// Values are escaped by EncodeSQL, so a newline always ends a statement.
+		cw.buf.WriteByte('\n')
+		cw.statementRows = 0
+	}
+}
+
+// backup compresses and writes the chunk to the backup, returning its hash.
+func (cw *logicalChunkWriter) backup(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, name string) (hash string, finalErr error) {
+	cw.endStatement()
+
+	openDestAt := time.Now()
+	dest, err := bh.AddFile(ctx, name, backupstorage.FileSizeUnknown)
+	if err != nil {
+		return "", vterrors.Wrapf(err, "cannot add file: %v", name)
+	}
+	params.Stats.Scope(stats.Operation("Destination:Open")).TimedIncrement(time.Since(openDestAt))
+	defer func() {
+		closeDestAt := time.Now()
+		if cerr := dest.Close(); cerr != nil {
+			finalErr = errors.Join(finalErr, vterrors.Wrapf(cerr, "failed to close file %v", name))
+		}
+		params.Stats.Scope(stats.Operation("Destination:Close")).TimedIncrement(time.Since(closeDestAt))
+	}()
+
+	destStats := params.Stats.Scope(stats.Operation("Destination:Write"))
+	bw := newBackupWriter(name, builtinBackupStorageWriteBufferSize, int64(cw.buf.Len()), backupWriteLimiter.writer(ctx, ioutil.NewMeteredWriter(dest, destStats.TimedIncrementBytes)))
+
+	var writer io.Writer = bw
+	var compressor io.WriteCloser
+	if backupStorageCompress {
+		compressor, err = newBuiltinCompressor(CompressionEngineName, bw, params.Logger)
+		if err != nil {
+			return "", vterrors.Wrap(err, "can't create compressor")
+		}
+		compressStats := params.Stats.Scope(stats.Operation("Compressor:Write"))
+		writer = ioutil.NewMeteredWriter(compressor, compressStats.TimedIncrementBytes)
+	}
+	if _, err := cw.buf.WriteTo(writer); err != nil {
+		return "", vterrors.Wrap(err, "cannot write data")
+	}
+	if compressor != nil {
+		if err := compressor.Close(); err != nil {
+			return "", vterrors.Wrap(err, "cannot close compressor")
+		}
+	}
+	if err := bw.Close(true); err != nil {
+		return "", vterrors.Wrapf(err, "cannot flush destination: %v", name)
+	}
+	return bw.HashString(), nil
+}
+
+// ExecuteRestore restores a logical backup into a running mysqld, dropping
+// and recreating the database first.
+func (be *LogicalBackupEngine) ExecuteRestore(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle) (*BackupManifest, error) {
+	params.Logger.Infof("Calling ExecuteRestore for %s (DeleteBeforeRestore: %v)", params.DbName, params.DeleteBeforeRestore)
+
+	var bm logicalBackupManifest
+	if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+		return nil, err
+	}
+	if err := be.restorePreCheck(&bm); err != nil {
+		return nil, vterrors.Wrap(err, "failed restore precheck")
+	}
+
+	// mark restore as in progress
+	if err := createStateFile(params.Cnf); err != nil {
+		return nil, err
+	}
+
+	if err := params.Mysqld.Wait(ctx, params.Cnf); err != nil {
+		return nil, vterrors.Wrap(err, "mysqld is not running")
+	}
+
+	// make sure semi-sync is disabled, otherwise we will wait forever for acknowledgements
+	if err := params.Mysqld.SetSemiSyncEnabled(ctx, false, false); err != nil {
+		return nil, vterrors.Wrap(err, "disable semi-sync failed")
+	}
+
+	readonly, err := params.Mysqld.IsSuperReadOnly(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "checking if mysqld has super_read_only=enable")
+	}
+	if readonly {
+		resetFunc, err := params.Mysqld.SetSuperReadOnly(ctx, false)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "unable to disable super-read-only")
+		}
+		defer func() {
+			if err := resetFunc(); err != nil {
+				params.Logger.Errorf("Not able to set super_read_only to its original value after restore")
+			}
+		}()
+	}
+
+	// The restored rows are loaded without writing to the binary log, so
+	// resetting replication leaves @@gtid_executed empty until it is set to
+	// the position of the backup once the restore completes.
+	if err := params.Mysqld.ResetReplication(ctx); err != nil {
+		return nil, vterrors.Wrap(err, "unable to reset replication")
+	}
+
+	dbName := sqlescape.EscapeID(params.DbName)
+	params.Logger.Infof("Dropping and recreating database %s", dbName)
+	if err := params.Mysqld.ExecuteSuperQueryList(ctx, []string{
+		"SET SESSION sql_log_bin = 0",
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbName),
+		strings.Replace(bm.DatabaseSchema, logicalBackupDatabaseNamePlaceholder, dbName, 1),
+		"SET SESSION sql_log_bin = 1",
+	}); err != nil {
+		return nil, vterrors.Wrapf(err, "unable to recreate database %s", dbName)
+	}
+
+	if err := be.restoreTables(ctx, params, bh, &bm, bm.Tables); err != nil {
+		return nil, err
+	}
+
+	params.Logger.Infof("Restore completed")
+	return &bm.BackupManifest, nil
+}
+
+// restoreTables creates the given tables of a logical backup and loads their
+// rows, followed by all the views of the backup.
+func (be *LogicalBackupEngine) restoreTables(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, bm *logicalBackupManifest, tables []LogicalTableEntry) error {
+	conn, err := be.restoreConnection(ctx, params)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, te := range tables {
+		params.Logger.Infof("Creating table %s", te.Name)
+		if _, err := conn.ExecuteFetch(te.Schema, 0, false); err != nil {
+			return vterrors.Wrapf(err, "unable to create table %s", te.Name)
+		}
+	}
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(max(params.Concurrency, 1))
+	for _, te := range tables {
+		for _, chunk := range te.Chunks {
+			g.Go(func() error {
+				if err := be.restoreChunk(gCtx, params, bh, bm, chunk); err != nil {
+					return vterrors.Wrapf(err, "failed to restore chunk %v of table %v", chunk.Name, te.Name)
+				}
+				return nil
+			})
+		}
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	for _, view := range bm.Views {
+		if _, err := conn.ExecuteFetch(strings.ReplaceAll(view, logicalBackupDatabaseNamePlaceholder, sqlescape.EscapeID(params.DbName)), 0, false); err != nil {
+			return vterrors.Wrapf(err, "unable to create view: %v", view)
+		}
+	}
+	return nil
+}
+
+// restoreConnection returns a dba connection to the restored database, set up
+// to load rows as they were read from the snapshot, without binary logging.
+func (be *LogicalBackupEngine) restoreConnection(ctx context.Context, params RestoreParams) (*dbconnpool.DBConnection, error) {
+	conn, err := params.Mysqld.GetDbaConnection(ctx)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "unable to get dba connection")
+	}
+	for _, query := range []string{
+		"SET SESSION sql_log_bin = 0",
+		"SET NAMES 'binary'",
+		"SET SESSION time_zone = '+00:00'",
+		"SET SESSION foreign_key_checks = 0",
+		"SET SESSION unique_checks = 0",
+		fmt.Sprintf("USE %s", sqlescape.EscapeID(params.DbName)),
+	} {
+		if _, err := conn.ExecuteFetch(query, 0, false); err != nil {
+			conn.Close()
+			return nil, vterrors.Wrapf(err, "unable to prepare restore connection with %q", query)
+		}
+	}
+	return conn, nil
+}
+
+// restoreChunk reads one data file of a logical backup and executes its
+// statements.
+func (be *LogicalBackupEngine) restoreChunk(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, bm *logicalBackupManifest, chunk LogicalChunkEntry) (finalErr error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	openSourceAt := time.Now()
+	source, err := bh.ReadFile(ctx, chunk.Name)
+	if err != nil {
+		return vterrors.Wrap(err, "can't open source file for reading")
+	}
+	params.Stats.Scope(stats.Operation("Source:Open")).TimedIncrement(time.Since(openSourceAt))
+	defer source.Close()
+
+	readStats := params.Stats.Scope(stats.Operation("Source:Read"))
+	br := newBackupReader(chunk.Name, 0, restoreReadLimiter.reader(ctx, ioutil.NewMeteredReader(source, readStats.TimedIncrementBytes)))
+	defer func() {
+		if err := br.Close(finalErr == nil); err != nil {
+			finalErr = errors.Join(finalErr, vterrors.Wrap(err, "failed to close the source reader"))
+		}
+	}()
+
+	var reader io.Reader = br
+	if !bm.SkipCompress {
+		decompressor, err := newBuiltinDecompressor(bm.CompressionEngine, br, params.Logger)
+		if err != nil {
+			return vterrors.Wrap(err, "can't create decompressor")
+		}
+		defer decompressor.Close()
+		reader = decompressor
+	}
+
+	conn, err := be.restoreConnection(ctx, params)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var rows int64
+	lines := bufio.NewReader(reader)
+	for {
+		statement, err := lines.ReadString('\n')
+		if statement = strings.TrimSuffix(statement, "\n"); statement != "" {
+			if err := restoreWriteLimiter.wait(ctx, len(statement)); err != nil {
+				return err
+			}
+			qr, execErr := conn.ExecuteFetch(statement, 0, false)
+			if execErr != nil {
+				return vterrors.Wrap(execErr, "failed to execute statement")
+			}
+			rows += int64(qr.RowsAffected)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return vterrors.Wrap(err, "failed to read data")
+		}
+	}
+
+	if hash := br.HashString(); hash != chunk.Hash {
+		return vterrors.Errorf(vtrpc.Code_INTERNAL, "hash mismatch for %v, got %v expected %v", chunk.Name, hash, chunk.Hash)
+	}
+	if rows != chunk.Rows {
+		return vterrors.Errorf(vtrpc.Code_INTERNAL, "row count mismatch for %v, got %v expected %v", chunk.Name, rows, chunk.Rows)
+	}
+	return nil
+}
+
+func (be *LogicalBackupEngine) backupPreCheck(params BackupParams) error {
+	if isIncrementalBackup(params) {
+		return fmt.Errorf("%w: incremental backups are taken by the %s engine", ErrLogicalBackupUnsupported, builtinBackupEngineName)
+	}
+	if params.TableStreamer == nil {
+		return fmt.Errorf("%w: backups can only be taken by a vttablet with a running tablet server", ErrLogicalBackupUnsupported)
+	}
+	if params.DbName == "" {
+		return fmt.Errorf("%w: no database name given", ErrLogicalBackupUnsupported)
+	}
+	if backupStorageCompress && (ExternalCompressorCmd != "" || CompressionEngineName == ExternalCompressor) {
+		return fmt.Errorf("%w: external compressors are not supported", ErrLogicalBackupUnsupported)
+	}
+	return nil
+}
+
+func (be *LogicalBackupEngine) restorePreCheck(bm *logicalBackupManifest) error {
+	if bm.SkipCompress {
+		return nil
+	}
+	if err := validateExternalCompressionEngineName(bm.CompressionEngine); err != nil {
+		return err
+	}
+	if bm.CompressionEngine == ExternalCompressor {
+		return fmt.Errorf("%w: external compressors are not supported", ErrLogicalBackupUnsupported)
+	}
+	return nil
+}
+
+// ShouldDrainForBackup satisfies the BackupEngine interface.
+// Logical backups read from a consistent snapshot of the running mysqld,
+// so the tablet keeps serving while they are taken.
+func (be *LogicalBackupEngine) ShouldDrainForBackup(req *tabletmanagerdatapb.BackupRequest) bool {
+	return false
+}
+
+// ShouldStartMySQLAfterRestore signifies if this backup engine needs to restart MySQL once the restore is completed.
+// Logical restores are loaded into a running mysqld, so there is no need to start it.
+func (be *LogicalBackupEngine) ShouldStartMySQLAfterRestore() bool {
+	return false
+}
+
+func (be *LogicalBackupEngine) Name() string { return logicalBackupEngineName }
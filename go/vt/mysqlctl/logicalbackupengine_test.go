@@ -0,0 +1,277 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/ioutil"
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+// memoryBackupHandle keeps the files of a backup in memory, and can be used
+// concurrently.
+type memoryBackupHandle struct {
+	FakeBackupHandle
+
+	mu    sync.Mutex
+	files map[string]ioutil.BytesBufferWriter
+}
+
+func newMemoryBackupHandle() *memoryBackupHandle {
+	return &memoryBackupHandle{files: make(map[string]ioutil.BytesBufferWriter)}
+}
+
+func (mbh *memoryBackupHandle) AddFile(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	mbh.mu.Lock()
+	defer mbh.mu.Unlock()
+	w := ioutil.NewBytesBufferWriter()
+	mbh.files[filename] = w
+	return w, nil
+}
+
+func (mbh *memoryBackupHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	mbh.mu.Lock()
+	defer mbh.mu.Unlock()
+	w, ok := mbh.files[filename]
+	if !ok {
+		return nil, fmt.Errorf("no such file: %s", filename)
+	}
+	return io.NopCloser(bytes.NewReader(w.Bytes())), nil
+}
+
+func TestLogicalBackupEngineBackupPreCheck(t *testing.T) {
+	be := &LogicalBackupEngine{}
+	streamer := func(ctx context.Context, send func(*binlogdatapb.VStreamTablesResponse) error) error { return nil }
+
+	tests := []struct {
+		name   string
+		params BackupParams
+		err    error
+	}{
+		{
+			name:   "no table streamer",
+			params: BackupParams{DbName: "vt_test"},
+			err:    ErrLogicalBackupUnsupported,
+		},
+		{
+			name:   "incremental",
+			params: BackupParams{DbName: "vt_test", TableStreamer: streamer, IncrementalFromPos: "auto"},
+			err:    ErrLogicalBackupUnsupported,
+		},
+		{
+			name:   "no database",
+			params: BackupParams{TableStreamer: streamer},
+			err:    ErrLogicalBackupUnsupported,
+		},
+		{
+			name:   "supported",
+			params: BackupParams{DbName: "vt_test", TableStreamer: streamer},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := be.backupPreCheck(tt.params)
+			if tt.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestLogicalBackupEngineBackupAndRestore(t *testing.T) {
+	originalStatementRows := logicalBackupStatementRows
+	originalChunkSize := logicalBackupChunkSize
+	defer func() {
+		logicalBackupStatementRows = originalStatementRows
+		logicalBackupChunkSize = originalChunkSize
+	}()
+	logicalBackupStatementRows = 2
+
+	ctx := context.Background()
+	fakedb := fakesqldb.New(t)
+	defer fakedb.Close()
+	mysqld := NewFakeMysqlDaemon(fakedb)
+	defer mysqld.Close()
+	mysqld.Schema = &tabletmanagerdatapb.SchemaDefinition{
+		DatabaseSchema: "CREATE DATABASE {{.DatabaseName}}",
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{Name: "t1", Type: "BASE TABLE", Schema: "CREATE TABLE `t1` (`id` int, `val` varchar(10), PRIMARY KEY (`id`))"},
+			{Name: "t2", Type: "BASE TABLE", Schema: "CREATE TABLE `t2` (`id` int, PRIMARY KEY (`id`))"},
+			{Name: "_vt_hld_6ace8bcef73211ea87e9f875a4d24e90_20200915120410_", Type: "BASE TABLE", Schema: "CREATE TABLE `_vt_hld_6ace8bcef73211ea87e9f875a4d24e90_20200915120410_` (`id` int)"},
+			{Name: "v1", Type: "VIEW", Schema: "CREATE VIEW {{.DatabaseName}}.`v1` AS SELECT `id` FROM {{.DatabaseName}}.`t1`"},
+		},
+	}
+
+	gtid := "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615"
+	t1Fields := sqltypes.MakeTestFields("id|val", "int32|varchar")
+	t2Fields := sqltypes.MakeTestFields("id", "int32")
+	streamer := func(ctx context.Context, send func(*binlogdatapb.VStreamTablesResponse) error) error {
+		responses := []*binlogdatapb.VStreamTablesResponse{
+			{TableName: "t1", Fields: t1Fields, Gtid: gtid},
+			{TableName: "t1", Gtid: gtid, Rows: []*querypb.Row{
+				sqltypes.RowToProto3(sqltypes.MakeTestResult(t1Fields, "1|a'b").Rows[0]),
+				sqltypes.RowToProto3(sqltypes.MakeTestResult(t1Fields, "2|c\nd").Rows[0]),
+				sqltypes.RowToProto3(sqltypes.MakeTestResult(t1Fields, "3|null").Rows[0]),
+			}},
+			{TableName: "t2", Fields: t2Fields, Gtid: gtid},
+			{TableName: "t2", Gtid: gtid, Rows: []*querypb.Row{
+				sqltypes.RowToProto3(sqltypes.MakeTestResult(t2Fields, "10").Rows[0]),
+			}},
+		}
+		for _, response := range responses {
+			if err := send(response); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	bh := newMemoryBackupHandle()
+	be := &LogicalBackupEngine{}
+	result, err := be.ExecuteBackup(ctx, BackupParams{
+		Logger:        logutil.NewMemoryLogger(),
+		Mysqld:        mysqld,
+		Concurrency:   4,
+		Keyspace:      "ks",
+		Shard:         "0",
+		TabletAlias:   "zone1-0000000100",
+		BackupTime:    time.Now(),
+		Stats:         backupstats.NoStats(),
+		DbName:        "vt_ks",
+		TableStreamer: streamer,
+	}, bh)
+	require.NoError(t, err)
+	require.Equal(t, BackupUsable, result)
+
+	var bm logicalBackupManifest
+	require.NoError(t, getBackupManifestInto(ctx, bh, &bm))
+	assert.Equal(t, logicalBackupEngineName, bm.BackupMethod)
+	assert.Equal(t, gtid, "MySQL56/"+bm.Position.String())
+	assert.Equal(t, bm.Position, bm.PurgedPosition)
+	require.Len(t, bm.Tables, 2)
+	assert.Equal(t, "t1", bm.Tables[0].Name)
+	require.Len(t, bm.Tables[0].Chunks, 1)
+	assert.EqualValues(t, 3, bm.Tables[0].Chunks[0].Rows)
+	assert.Equal(t, "t2", bm.Tables[1].Name)
+	require.Len(t, bm.Tables[1].Chunks, 1)
+	assert.EqualValues(t, 1, bm.Tables[1].Chunks[0].Rows)
+	assert.Equal(t, []string{mysqld.Schema.TableDefinitions[3].Schema}, bm.Views)
+
+	// Restore the backup, checking every statement reaches mysqld.
+	mysqld.ExpectedExecuteSuperQueryList = []string{
+		"FAKE RESET ALL REPLICATION",
+		"SET SESSION sql_log_bin = 0",
+		"DROP DATABASE IF EXISTS `vt_ks`",
+		"CREATE DATABASE `vt_ks`",
+		"SET SESSION sql_log_bin = 1",
+	}
+	for _, query := range []string{
+		"SET SESSION sql_log_bin = 0",
+		"SET NAMES 'binary'",
+		"SET SESSION time_zone = '+00:00'",
+		"SET SESSION foreign_key_checks = 0",
+		"SET SESSION unique_checks = 0",
+		"USE `vt_ks`",
+		bm.Tables[0].Schema,
+		bm.Tables[1].Schema,
+		"CREATE VIEW `vt_ks`.`v1` AS SELECT `id` FROM `vt_ks`.`t1`",
+	} {
+		fakedb.AddQuery(query, &sqltypes.Result{})
+	}
+	fakedb.AddQuery("insert into `t1` (`id`, `val`) values (1, 'a\\'b'), (2, 'c\\nd')", &sqltypes.Result{RowsAffected: 2})
+	fakedb.AddQuery("insert into `t1` (`id`, `val`) values (3, null)", &sqltypes.Result{RowsAffected: 1})
+	fakedb.AddQuery("insert into `t2` (`id`) values (10)", &sqltypes.Result{RowsAffected: 1})
+
+	manifest, err := be.ExecuteRestore(ctx, RestoreParams{
+		Cnf:         &Mycnf{DataDir: t.TempDir() + "/data"},
+		Mysqld:      mysqld,
+		Logger:      logutil.NewMemoryLogger(),
+		Concurrency: 4,
+		DbName:      "vt_ks",
+		Stats:       backupstats.NoStats(),
+	}, bh)
+	require.NoError(t, err)
+	assert.Equal(t, bm.Position, manifest.Position)
+	assert.Equal(t, len(mysqld.ExpectedExecuteSuperQueryList), mysqld.ExpectedExecuteSuperQueryCurrent)
+}
+
+func TestLogicalBackupEngineChunks(t *testing.T) {
+	originalChunkSize := logicalBackupChunkSize
+	defer func() {
+		logicalBackupChunkSize = originalChunkSize
+	}()
+	// Every row goes into a chunk of its own.
+	logicalBackupChunkSize = 1
+
+	fields := sqltypes.MakeTestFields("id", "int32")
+	var rows []*querypb.Row
+	for i := range 10 {
+		rows = append(rows, sqltypes.RowToProto3(sqltypes.MakeTestResult(fields, fmt.Sprintf("%d", i)).Rows[0]))
+	}
+	streamer := func(ctx context.Context, send func(*binlogdatapb.VStreamTablesResponse) error) error {
+		if err := send(&binlogdatapb.VStreamTablesResponse{TableName: "t1", Fields: fields}); err != nil {
+			return err
+		}
+		return send(&binlogdatapb.VStreamTablesResponse{TableName: "t1", Rows: rows})
+	}
+
+	bm := &logicalBackupManifest{Tables: []LogicalTableEntry{{Name: "t1"}}}
+	bh := newMemoryBackupHandle()
+	be := &LogicalBackupEngine{}
+	_, err := be.backupTables(context.Background(), BackupParams{
+		Logger:        logutil.NewMemoryLogger(),
+		Concurrency:   3,
+		Stats:         backupstats.NoStats(),
+		TableStreamer: streamer,
+	}, bh, bm, map[string]int{"t1": 0})
+	require.NoError(t, err)
+
+	require.Len(t, bm.Tables[0].Chunks, 10)
+	for i, chunk := range bm.Tables[0].Chunks {
+		assert.Equal(t, fmt.Sprintf("0.%d", i), chunk.Name)
+		assert.EqualValues(t, 1, chunk.Rows)
+		assert.NotEmpty(t, chunk.Hash)
+	}
+
+	// A table that is missing from the schema fails the backup.
+	_, err = be.backupTables(context.Background(), BackupParams{
+		Logger:        logutil.NewMemoryLogger(),
+		Concurrency:   3,
+		Stats:         backupstats.NoStats(),
+		TableStreamer: streamer,
+	}, newMemoryBackupHandle(), &logicalBackupManifest{}, map[string]int{})
+	require.ErrorContains(t, err, "table t1 is not in the schema of the backup")
+}
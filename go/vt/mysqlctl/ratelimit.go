@@ -0,0 +1,219 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/time/rate"
+
+	"vitess.io/vitess/go/viperutil"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var (
+	backupReadRateLimit = viperutil.Configure(
+		"backup-read-rate-limit",
+		viperutil.Options[int64]{
+			FlagName: "backup-read-rate-limit",
+			Dynamic:  true,
+		},
+	)
+	backupWriteRateLimit = viperutil.Configure(
+		"backup-write-rate-limit",
+		viperutil.Options[int64]{
+			FlagName: "backup-write-rate-limit",
+			Dynamic:  true,
+		},
+	)
+	restoreReadRateLimit = viperutil.Configure(
+		"restore-read-rate-limit",
+		viperutil.Options[int64]{
+			FlagName: "restore-read-rate-limit",
+			Dynamic:  true,
+		},
+	)
+	restoreWriteRateLimit = viperutil.Configure(
+		"restore-write-rate-limit",
+		viperutil.Options[int64]{
+			FlagName: "restore-write-rate-limit",
+			Dynamic:  true,
+		},
+	)
+
+	// The limiters are shared by all the files that are copied concurrently,
+	// so that the limits apply to a backup or restore as a whole.
+	backupReadLimiter   = newRateLimiter(backupReadRateLimit.Get)
+	backupWriteLimiter  = newRateLimiter(backupWriteRateLimit.Get)
+	restoreReadLimiter  = newRateLimiter(restoreReadRateLimit.Get)
+	restoreWriteLimiter = newRateLimiter(restoreWriteRateLimit.Get)
+)
+
+func init() {
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerRateLimitFlags)
+	}
+}
+
+func registerRateLimitFlags(fs *pflag.FlagSet) {
+	fs.Int64("backup-read-rate-limit", backupReadRateLimit.Default(), "Maximum number of bytes per second read from local files while taking a backup. 0 means unlimited. Can be changed at runtime.")
+	fs.Int64("backup-write-rate-limit", backupWriteRateLimit.Default(), "Maximum number of bytes per second written to backup storage while taking a backup. 0 means unlimited. Can be changed at runtime.")
+	fs.Int64("restore-read-rate-limit", restoreReadRateLimit.Default(), "Maximum number of bytes per second read from backup storage while restoring a backup. 0 means unlimited. Can be changed at runtime.")
+	fs.Int64("restore-write-rate-limit", restoreWriteRateLimit.Default(), "Maximum number of bytes per second written to local files while restoring a backup. 0 means unlimited. Can be changed at runtime.")
+
+	viperutil.BindFlags(fs,
+		backupReadRateLimit,
+		backupWriteRateLimit,
+		restoreReadRateLimit,
+		restoreWriteRateLimit,
+	)
+}
+
+// GetBackupReadRateLimit getter for use by debugenv
+func GetBackupReadRateLimit() int64 {
+	return backupReadRateLimit.Get()
+}
+
+// SetBackupReadRateLimit setter for use by debugenv
+func SetBackupReadRateLimit(limit int64) {
+	backupReadRateLimit.Set(limit)
+}
+
+// GetBackupWriteRateLimit getter for use by debugenv
+func GetBackupWriteRateLimit() int64 {
+	return backupWriteRateLimit.Get()
+}
+
+// SetBackupWriteRateLimit setter for use by debugenv
+func SetBackupWriteRateLimit(limit int64) {
+	backupWriteRateLimit.Set(limit)
+}
+
+// GetRestoreReadRateLimit getter for use by debugenv
+func GetRestoreReadRateLimit() int64 {
+	return restoreReadRateLimit.Get()
+}
+
+// SetRestoreReadRateLimit setter for use by debugenv
+func SetRestoreReadRateLimit(limit int64) {
+	restoreReadRateLimit.Set(limit)
+}
+
+// GetRestoreWriteRateLimit getter for use by debugenv
+func GetRestoreWriteRateLimit() int64 {
+	return restoreWriteRateLimit.Get()
+}
+
+// SetRestoreWriteRateLimit setter for use by debugenv
+func SetRestoreWriteRateLimit(limit int64) {
+	restoreWriteRateLimit.Set(limit)
+}
+
+// rateLimiter limits the number of bytes per second going through the
+// readers and writers it wraps. The limit is read again before every wait,
+// so that it can be changed while a backup or restore is running.
+type rateLimiter struct {
+	getLimit func() int64
+
+	mu      sync.Mutex
+	limit   int64
+	limiter *rate.Limiter
+}
+
+func newRateLimiter(getLimit func() int64) *rateLimiter {
+	return &rateLimiter{getLimit: getLimit}
+}
+
+// current returns the limiter to use for the current limit, or nil if there is
+// no limit.
+func (rl *rateLimiter) current() *rate.Limiter {
+	limit := rl.getLimit()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if limit <= 0 {
+		rl.limit, rl.limiter = 0, nil
+		return nil
+	}
+	if rl.limiter == nil {
+		rl.limiter = rate.NewLimiter(rate.Limit(limit), int(limit))
+	} else if limit != rl.limit {
+		rl.limiter.SetLimit(rate.Limit(limit))
+		rl.limiter.SetBurst(int(limit))
+	}
+	rl.limit = limit
+	return rl.limiter
+}
+
+// wait blocks until n bytes can go through the limiter.
+func (rl *rateLimiter) wait(ctx context.Context, n int) error {
+	for n > 0 {
+		limiter := rl.current()
+		if limiter == nil {
+			return nil
+		}
+		// A single wait cannot exceed the burst, which is one second worth of bytes.
+		chunk := min(n, limiter.Burst())
+		if err := limiter.WaitN(ctx, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+// reader returns a reader that reads from r at no more than the limit.
+func (rl *rateLimiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	return &rateLimitedReader{ctx: ctx, rl: rl, r: r}
+}
+
+// writer returns a writer that writes to w at no more than the limit.
+func (rl *rateLimiter) writer(ctx context.Context, w io.Writer) io.Writer {
+	return &rateLimitedWriter{ctx: ctx, rl: rl, w: w}
+}
+
+type rateLimitedReader struct {
+	ctx context.Context
+	rl  *rateLimiter
+	r   io.Reader
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.rl.wait(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+type rateLimitedWriter struct {
+	ctx context.Context
+	rl  *rateLimiter
+	w   io.Writer
+}
+
+func (w *rateLimitedWriter) Write(p []byte) (int, error) {
+	if err := w.rl.wait(w.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	rl := newRateLimiter(func() int64 { return 0 })
+
+	var buf bytes.Buffer
+	data := bytes.Repeat([]byte("x"), 1<<20)
+	start := time.Now()
+	n, err := io.Copy(rl.writer(context.Background(), &buf), rl.reader(context.Background(), bytes.NewReader(data)))
+	require.NoError(t, err)
+	assert.EqualValues(t, len(data), n)
+	assert.Equal(t, data, buf.Bytes())
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRateLimiterLimits(t *testing.T) {
+	var limit atomic.Int64
+	limit.Store(1000)
+	rl := newRateLimiter(limit.Load)
+
+	// The first second worth of bytes is the initial burst, the next one
+	// has to wait for about a second.
+	var buf bytes.Buffer
+	start := time.Now()
+	_, err := io.Copy(rl.writer(context.Background(), &buf), bytes.NewReader(make([]byte, 2000)))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+	assert.Equal(t, 2000, buf.Len())
+
+	// Raising the limit applies to the next writes.
+	limit.Store(1 << 30)
+	start = time.Now()
+	_, err = io.Copy(rl.writer(context.Background(), &buf), bytes.NewReader(make([]byte, 1<<20)))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.EqualValues(t, 1<<30, rl.limit)
+
+	// Removing the limit drops the limiter.
+	limit.Store(0)
+	require.NoError(t, rl.wait(context.Background(), 1<<30))
+	assert.Nil(t, rl.limiter)
+}
+
+func TestRateLimiterCanceled(t *testing.T) {
+	rl := newRateLimiter(func() int64 { return 10 })
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := rl.reader(ctx, bytes.NewReader(make([]byte, 100))).Read(make([]byte, 100))
+	assert.Error(t, err)
+	_, err = rl.writer(ctx, io.Discard).Write(make([]byte, 100))
+	assert.Error(t, err)
+}
@@ -29,6 +29,7 @@ CREATE TABLE IF NOT EXISTS vdiff
     `liveness_timestamp` timestamp    NULL     DEFAULT NULL,
     `completed_at`       timestamp    NULL     DEFAULT NULL,
     `last_error`         varbinary(1024)      DEFAULT NULL,
+    `continuous_pos`     blob                 DEFAULT NULL,
     PRIMARY KEY (`id`),
     UNIQUE KEY `uuid_idx` (`vdiff_uuid`),
     KEY `state` (`state`),
@@ -0,0 +1,32 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+CREATE TABLE IF NOT EXISTS vdiff_repair
+(
+    `id`           bigint(20)     NOT NULL AUTO_INCREMENT,
+    `vdiff_id`     bigint(20)     NOT NULL,
+    `table_name`   varbinary(128) NOT NULL,
+    `type`         varbinary(16)  NOT NULL,
+    `statement`    longblob       NOT NULL,
+    `row_values`   longblob       NOT NULL,
+    `source_query` longblob       NOT NULL,
+    `target_query` longblob       NOT NULL,
+    `state`        varbinary(64)  NOT NULL DEFAULT 'pending',
+    `created_at`   timestamp      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `applied_at`   timestamp      NULL     DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    KEY `vdiff_table_state_idx` (`vdiff_id`, `table_name`, `state`)
+) ENGINE = InnoDB CHARSET = utf8mb4
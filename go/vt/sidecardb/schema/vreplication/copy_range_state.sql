@@ -0,0 +1,31 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+CREATE TABLE IF NOT EXISTS copy_range_state
+(
+    `id`          bigint unsigned  NOT NULL AUTO_INCREMENT,
+    `vrepl_id`    int              NOT NULL,
+    `table_name`  varbinary(128)   NOT NULL,
+    `range_id`    int              NOT NULL,
+    `range_start` varbinary(2000)           DEFAULT NULL,
+    `range_end`   varbinary(2000)           DEFAULT NULL,
+    `lastpk`      varbinary(2000)           DEFAULT NULL,
+    `settled_pk`  varbinary(2000)           DEFAULT NULL,
+    `pos`         varbinary(10000)          DEFAULT NULL,
+    `done`        tinyint unsigned NOT NULL DEFAULT '0',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `vrepl_id_table_range` (`vrepl_id`, `table_name`, `range_id`)
+) ENGINE = InnoDB CHARSET = utf8mb4
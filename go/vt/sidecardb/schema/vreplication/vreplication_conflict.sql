@@ -0,0 +1,30 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+CREATE TABLE IF NOT EXISTS vreplication_conflict
+(
+    `id`            bigint         NOT NULL AUTO_INCREMENT,
+    `vrepl_id`      int            NOT NULL,
+    `table_name`    varbinary(128) NOT NULL,
+    `change_type`   varbinary(16)  NOT NULL,
+    `source_before` json           NULL,
+    `source_after`  json           NULL,
+    `target_row`    json           NULL,
+    `resolution`    varbinary(16)  NOT NULL,
+    `created_at`    timestamp      NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `vrepl_id_table_idx` (`vrepl_id`, `table_name`)
+) ENGINE = InnoDB CHARSET = utf8mb4
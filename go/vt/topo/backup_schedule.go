@@ -0,0 +1,169 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// backupSchedulePath returns the path of the backup schedule of a shard, or of
+// the keyspace if shard is empty.
+func backupSchedulePath(keyspace, shard string) string {
+	if shard == "" {
+		return path.Join(KeyspacesPath, keyspace, BackupScheduleFile)
+	}
+	return path.Join(KeyspacesPath, keyspace, ShardsPath, shard, BackupScheduleFile)
+}
+
+func backupScheduleStatusPath(keyspace, shard string) string {
+	return path.Join(KeyspacesPath, keyspace, ShardsPath, shard, BackupScheduleStatusFile)
+}
+
+// SaveBackupSchedule creates or replaces the backup schedule of a shard, or of
+// the keyspace if shard is empty. The schedule of a shard overrides the
+// schedule of its keyspace.
+func (ts *Server) SaveBackupSchedule(ctx context.Context, keyspace, shard string, schedule *topodatapb.BackupSchedule) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := schedule.MarshalVT()
+	if err != nil {
+		return err
+	}
+	_, err = ts.globalCell.Update(ctx, backupSchedulePath(keyspace, shard), data, nil)
+	return err
+}
+
+// GetBackupSchedule returns the backup schedule of a shard, or of the keyspace
+// if shard is empty. It returns a NoNode error if there is no such schedule.
+func (ts *Server) GetBackupSchedule(ctx context.Context, keyspace, shard string) (*topodatapb.BackupSchedule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	data, _, err := ts.globalCell.Get(ctx, backupSchedulePath(keyspace, shard))
+	if err != nil {
+		return nil, err
+	}
+	schedule := &topodatapb.BackupSchedule{}
+	if err := schedule.UnmarshalVT(data); err != nil {
+		return nil, vterrors.Wrapf(err, "bad backup schedule data for %v", backupSchedulePath(keyspace, shard))
+	}
+	return schedule, nil
+}
+
+// GetShardBackupSchedule returns the backup schedule that applies to a shard:
+// the schedule of the shard if there is one, otherwise the schedule of its
+// keyspace. It returns nil if neither has a schedule.
+func (ts *Server) GetShardBackupSchedule(ctx context.Context, keyspace, shard string) (*topodatapb.BackupSchedule, error) {
+	schedule, err := ts.GetBackupSchedule(ctx, keyspace, shard)
+	if err == nil || !IsErrType(err, NoNode) {
+		return schedule, err
+	}
+	schedule, err = ts.GetBackupSchedule(ctx, keyspace, "")
+	if IsErrType(err, NoNode) {
+		return nil, nil
+	}
+	return schedule, err
+}
+
+// DeleteBackupSchedule deletes the backup schedule of a shard, or of the
+// keyspace if shard is empty.
+func (ts *Server) DeleteBackupSchedule(ctx context.Context, keyspace, shard string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return ts.globalCell.Delete(ctx, backupSchedulePath(keyspace, shard), nil)
+}
+
+// GetBackupScheduleStatus returns the outcome of the scheduled backups of a
+// shard. It returns an empty status if no backup was scheduled yet.
+func (ts *Server) GetBackupScheduleStatus(ctx context.Context, keyspace, shard string) (*topodatapb.BackupScheduleStatus, error) {
+	status, _, err := ts.getBackupScheduleStatus(ctx, keyspace, shard)
+	return status, err
+}
+
+func (ts *Server) getBackupScheduleStatus(ctx context.Context, keyspace, shard string) (*topodatapb.BackupScheduleStatus, Version, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	data, version, err := ts.globalCell.Get(ctx, backupScheduleStatusPath(keyspace, shard))
+	switch {
+	case IsErrType(err, NoNode):
+		return &topodatapb.BackupScheduleStatus{}, nil, nil
+	case err != nil:
+		return nil, nil, err
+	}
+	status := &topodatapb.BackupScheduleStatus{}
+	if err := status.UnmarshalVT(data); err != nil {
+		return nil, nil, vterrors.Wrapf(err, "bad backup schedule status data for %v", backupScheduleStatusPath(keyspace, shard))
+	}
+	return status, version, nil
+}
+
+// UpdateBackupScheduleStatus reads the backup schedule status of a shard,
+// applies update to it and writes it back, retrying if it was modified
+// concurrently.
+func (ts *Server) UpdateBackupScheduleStatus(ctx context.Context, keyspace, shard string, update func(*topodatapb.BackupScheduleStatus) error) (*topodatapb.BackupScheduleStatus, error) {
+	for {
+		status, version, err := ts.getBackupScheduleStatus(ctx, keyspace, shard)
+		if err != nil {
+			return nil, err
+		}
+		original := status.CloneVT()
+		if err := update(status); err != nil {
+			return nil, err
+		}
+		if proto.Equal(original, status) {
+			return status, nil
+		}
+		data, err := status.MarshalVT()
+		if err != nil {
+			return nil, err
+		}
+		p := backupScheduleStatusPath(keyspace, shard)
+		if version == nil {
+			_, err = ts.globalCell.Create(ctx, p, data)
+		} else {
+			_, err = ts.globalCell.Update(ctx, p, data, version)
+		}
+		if IsErrType(err, BadVersion) || IsErrType(err, NodeExists) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return status, nil
+	}
+}
+
+// deleteShardBackupSchedule deletes the backup schedule and status of a
+// shard, if any.
+func (ts *Server) deleteShardBackupSchedule(ctx context.Context, keyspace, shard string) error {
+	for _, p := range []string{backupSchedulePath(keyspace, shard), backupScheduleStatusPath(keyspace, shard)} {
+		if err := ts.globalCell.Delete(ctx, p, nil); err != nil && !IsErrType(err, NoNode) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
+)
+
+func TestBackupSchedule(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "-80"))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "80-"))
+
+	schedule, err := ts.GetShardBackupSchedule(ctx, "ks", "-80")
+	require.NoError(t, err)
+	assert.Nil(t, schedule)
+	_, err = ts.GetBackupSchedule(ctx, "ks", "")
+	assert.True(t, topo.IsErrType(err, topo.NoNode))
+
+	keyspaceSchedule := &topodatapb.BackupSchedule{FullBackupInterval: &vttimepb.Duration{Seconds: 3600}}
+	shardSchedule := &topodatapb.BackupSchedule{FullBackupInterval: &vttimepb.Duration{Seconds: 60}, Cell: "zone1"}
+	require.NoError(t, ts.SaveBackupSchedule(ctx, "ks", "", keyspaceSchedule))
+	require.NoError(t, ts.SaveBackupSchedule(ctx, "ks", "80-", shardSchedule))
+
+	// The schedule of a shard overrides the schedule of its keyspace.
+	schedule, err = ts.GetShardBackupSchedule(ctx, "ks", "-80")
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(keyspaceSchedule, schedule))
+	schedule, err = ts.GetShardBackupSchedule(ctx, "ks", "80-")
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(shardSchedule, schedule))
+
+	require.NoError(t, ts.DeleteBackupSchedule(ctx, "ks", "80-"))
+	schedule, err = ts.GetShardBackupSchedule(ctx, "ks", "80-")
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(keyspaceSchedule, schedule))
+
+	// Deleting the keyspace and its shards deletes the schedules, so that no
+	// file is left in the keyspace directory.
+	require.NoError(t, ts.SaveBackupSchedule(ctx, "ks", "80-", shardSchedule))
+	_, err = ts.UpdateBackupScheduleStatus(ctx, "ks", "80-", func(status *topodatapb.BackupScheduleStatus) error {
+		status.LastFullBackup = &topodatapb.BackupScheduleRun{Error: "boom"}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, ts.DeleteShard(ctx, "ks", "-80"))
+	require.NoError(t, ts.DeleteShard(ctx, "ks", "80-"))
+	require.NoError(t, ts.DeleteKeyspace(ctx, "ks"))
+	keyspaces, err := ts.GetKeyspaces(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, keyspaces)
+}
+
+func TestUpdateBackupScheduleStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	status, err := ts.GetBackupScheduleStatus(ctx, "ks", "0")
+	require.NoError(t, err)
+	assert.Nil(t, status.LastFullBackup)
+
+	run := &topodatapb.BackupScheduleRun{
+		TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		StartedAt:   protoutil.TimeToProto(time.Unix(1000, 0)),
+	}
+	_, err = ts.UpdateBackupScheduleStatus(ctx, "ks", "0", func(status *topodatapb.BackupScheduleStatus) error {
+		status.LastFullBackup = run
+		return nil
+	})
+	require.NoError(t, err)
+
+	status, err = ts.UpdateBackupScheduleStatus(ctx, "ks", "0", func(status *topodatapb.BackupScheduleStatus) error {
+		status.LastFullBackup.FinishedAt = protoutil.TimeToProto(time.Unix(1060, 0))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1060, status.LastFullBackup.FinishedAt.Seconds)
+
+	status, err = ts.GetBackupScheduleStatus(ctx, "ks", "0")
+	require.NoError(t, err)
+	assert.EqualValues(t, 100, status.LastFullBackup.TabletAlias.Uid)
+	assert.EqualValues(t, 1060, status.LastFullBackup.FinishedAt.Seconds)
+}
@@ -0,0 +1,469 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// The config history records every change made to the VSchemas, and to
+// the routing and mirror rules, in the global cell. Each object has its
+// own directory under ConfigHistoryPath, with one file per version that
+// is never modified once created, and a head file with a copy of the
+// latest version. The object, its new version and the head are written
+// in the same transaction, and only the last configHistoryRetention
+// versions are kept.
+
+// maxConfigHistoryAttempts is the number of times we try to save an
+// object when other processes append entries to the same history.
+const maxConfigHistoryAttempts = 10
+
+// configHistoryHeadFile is the name of the file with a copy of the
+// latest version in the directory of a history.
+const configHistoryHeadFile = "head"
+
+// configHistoryRetention is the number of versions kept in the history
+// of an object.
+var configHistoryRetention int64 = 1000
+
+// configHistoryDir returns the directory of the history of an object.
+func configHistoryDir(kind topodatapb.ConfigKind, keyspace string) string {
+	dir := path.Join(ConfigHistoryPath, strings.ToLower(kind.String()))
+	if kind == topodatapb.ConfigKind_VSCHEMA {
+		dir = path.Join(dir, keyspace)
+	}
+	return dir
+}
+
+// configHistoryEntryPath returns the path of a version in the history of
+// an object. Versions are zero-padded so they list in order.
+func configHistoryEntryPath(kind topodatapb.ConfigKind, keyspace string, version int64) string {
+	return path.Join(configHistoryDir(kind, keyspace), fmt.Sprintf("%010d", version))
+}
+
+// configHistoryHeadPath returns the path of the head of the history of
+// an object.
+func configHistoryHeadPath(kind topodatapb.ConfigKind, keyspace string) string {
+	return path.Join(configHistoryDir(kind, keyspace), configHistoryHeadFile)
+}
+
+// configFilePath returns the path of an object in the global cell.
+func (ts *Server) configFilePath(kind topodatapb.ConfigKind, keyspace string) (string, error) {
+	switch kind {
+	case topodatapb.ConfigKind_VSCHEMA:
+		if keyspace == "" {
+			return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a keyspace is required for the config history of a VSchema")
+		}
+		return path.Join(KeyspacesPath, keyspace, VSchemaFile), nil
+	case topodatapb.ConfigKind_ROUTING_RULES:
+		return RoutingRulesFile, nil
+	case topodatapb.ConfigKind_SHARD_ROUTING_RULES:
+		return ShardRoutingRulesFile, nil
+	case topodatapb.ConfigKind_KEYSPACE_ROUTING_RULES:
+		return ts.GetKeyspaceRoutingRulesPath(), nil
+	case topodatapb.ConfigKind_MIRROR_RULES:
+		return MirrorRulesFile, nil
+	}
+	return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown config kind %v", kind)
+}
+
+// configJSON returns the JSON representation of a serialized object, as
+// used in the diffs. Deleted objects have none.
+func configJSON(kind topodatapb.ConfigKind, contents []byte, deleted bool) (string, error) {
+	if deleted {
+		return "", nil
+	}
+	var msg proto.Message
+	switch kind {
+	case topodatapb.ConfigKind_VSCHEMA:
+		msg = &vschemapb.Keyspace{}
+	case topodatapb.ConfigKind_ROUTING_RULES:
+		msg = &vschemapb.RoutingRules{}
+	case topodatapb.ConfigKind_SHARD_ROUTING_RULES:
+		msg = &vschemapb.ShardRoutingRules{}
+	case topodatapb.ConfigKind_KEYSPACE_ROUTING_RULES:
+		msg = &vschemapb.KeyspaceRoutingRules{}
+	case topodatapb.ConfigKind_MIRROR_RULES:
+		msg = &vschemapb.MirrorRules{}
+	default:
+		return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown config kind %v", kind)
+	}
+	if err := proto.Unmarshal(contents, msg); err != nil {
+		return "", vterrors.Wrapf(err, "bad %v data", kind)
+	}
+	data, err := json2.MarshalIndentPB(msg, "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// configChangeAuthor returns who makes the changes done with ctx: the
+// authenticated user or the caller of the RPC if known, else this
+// process.
+func configChangeAuthor(ctx context.Context) string {
+	if user := servenv.StaticAuthUsernameFromContext(ctx); user != "" {
+		return user
+	}
+	if ef := callerid.EffectiveCallerIDFromContext(ctx); ef != nil && ef.Principal != "" {
+		return ef.Principal
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s@%s", filepath.Base(os.Args[0]), hostname)
+}
+
+// getConfigHistoryHead returns the latest version in the history of an
+// object, and the version of the head file, or nils if the history is
+// empty.
+func (ts *Server) getConfigHistoryHead(ctx context.Context, kind topodatapb.ConfigKind, keyspace string) (*topodatapb.ConfigHistoryEntry, Version, error) {
+	data, version, err := ts.globalCell.Get(ctx, configHistoryHeadPath(kind, keyspace))
+	if err != nil {
+		if IsErrType(err, NoNode) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	head := &topodatapb.ConfigHistoryEntry{}
+	if err := head.UnmarshalVT(data); err != nil {
+		return nil, nil, vterrors.Wrapf(err, "bad config history head data: %q", data)
+	}
+	return head, version, nil
+}
+
+// GetConfigHistoryEntry returns a version in the history of an object.
+func (ts *Server) GetConfigHistoryEntry(ctx context.Context, kind topodatapb.ConfigKind, keyspace string, version int64) (*topodatapb.ConfigHistoryEntry, error) {
+	if _, err := ts.configFilePath(kind, keyspace); err != nil {
+		return nil, err
+	}
+	data, _, err := ts.globalCell.Get(ctx, configHistoryEntryPath(kind, keyspace, version))
+	if err != nil {
+		return nil, err
+	}
+	entry := &topodatapb.ConfigHistoryEntry{}
+	if err := entry.UnmarshalVT(data); err != nil {
+		return nil, vterrors.Wrapf(err, "bad config history entry data: %q", data)
+	}
+	return entry, nil
+}
+
+// GetConfigHistory returns the history of an object, sorted by version.
+// Only the last limit versions are returned if limit is positive.
+func (ts *Server) GetConfigHistory(ctx context.Context, kind topodatapb.ConfigKind, keyspace string, limit int) ([]*topodatapb.ConfigHistoryEntry, error) {
+	if _, err := ts.configFilePath(kind, keyspace); err != nil {
+		return nil, err
+	}
+	head, _, err := ts.getConfigHistoryHead(ctx, kind, keyspace)
+	if err != nil || head == nil {
+		return nil, err
+	}
+	first := max(head.Version-configHistoryRetention+1, 1)
+	if limit > 0 {
+		first = max(head.Version-int64(limit)+1, first)
+	}
+
+	history := make([]*topodatapb.ConfigHistoryEntry, 0, head.Version-first+1)
+	for v := first; v <= head.Version; v++ {
+		entry, err := ts.GetConfigHistoryEntry(ctx, kind, keyspace, v)
+		if err != nil {
+			if IsErrType(err, NoNode) {
+				// Pruned since we read the head.
+				continue
+			}
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+	return history, nil
+}
+
+// newConfigHistoryEntry returns the version that follows prev, which is
+// nil if the history is empty.
+func newConfigHistoryEntry(ctx context.Context, kind topodatapb.ConfigKind, keyspace string, prev *topodatapb.ConfigHistoryEntry, contents []byte, deleted bool, comment string) (*topodatapb.ConfigHistoryEntry, error) {
+	var last int64
+	var prevJSON string
+	if prev != nil {
+		last = prev.Version
+		var err error
+		if prevJSON, err = configJSON(kind, prev.Contents, prev.Deleted); err != nil {
+			return nil, err
+		}
+	}
+	newJSON, err := configJSON(kind, contents, deleted)
+	if err != nil {
+		return nil, err
+	}
+	return &topodatapb.ConfigHistoryEntry{
+		Version:  last + 1,
+		Kind:     kind,
+		Keyspace: keyspace,
+		Author:   configChangeAuthor(ctx),
+		Time:     protoutil.TimeToProto(time.Now()),
+		Contents: contents,
+		Deleted:  deleted,
+		Diff:     textutil.UnifiedDiff(fmt.Sprintf("version %d", last), fmt.Sprintf("version %d", last+1), prevJSON, newJSON),
+		Comment:  comment,
+	}, nil
+}
+
+// sameConfig returns true if prev, which can be nil, is the same object
+// as contents. The serialized objects are compared as JSON, as the maps
+// of the VSchemas are not serialized in a deterministic order.
+func sameConfig(kind topodatapb.ConfigKind, prev *topodatapb.ConfigHistoryEntry, contents []byte, deleted bool) (bool, error) {
+	switch {
+	case prev == nil || prev.Deleted != deleted:
+		return false, nil
+	case deleted || bytes.Equal(prev.Contents, contents):
+		return true, nil
+	}
+	prevJSON, err := configJSON(kind, prev.Contents, false)
+	if err != nil {
+		return false, err
+	}
+	newJSON, err := configJSON(kind, contents, false)
+	if err != nil {
+		return false, err
+	}
+	return prevJSON == newJSON, nil
+}
+
+// saveConfig applies op, which creates, updates or deletes an object of
+// the config history, and appends the new version to its history in the
+// same transaction. If the history is empty, the object it replaces is
+// recorded first. Nothing is appended if the object is the same as
+// the latest version, which is then returned with the new version of
+// the object.
+func (ts *Server) saveConfig(ctx context.Context, kind topodatapb.ConfigKind, keyspace string, op *TxnOp, comment string) (Version, *topodatapb.ConfigHistoryEntry, error) {
+	filePath, err := ts.configFilePath(kind, keyspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	op.Cell = GlobalCell
+	op.Path = filePath
+	deleted := op.Type == TxnDelete
+
+	for range maxConfigHistoryAttempts {
+		head, headVersion, err := ts.getConfigHistoryHead(ctx, kind, keyspace)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var current []byte
+		if head == nil {
+			data, _, err := ts.globalCell.Get(ctx, filePath)
+			switch {
+			case err == nil:
+				current = data
+			case !IsErrType(err, NoNode):
+				return nil, nil, err
+			}
+		}
+
+		ops := []*TxnOp{op}
+		prev := head
+		if head == nil && current != nil {
+			baseline, err := newConfigHistoryEntry(ctx, kind, keyspace, nil, current, false, "version before the config history was recorded")
+			if err != nil {
+				return nil, nil, err
+			}
+			data, err := baseline.MarshalVT()
+			if err != nil {
+				return nil, nil, err
+			}
+			ops = append(ops, &TxnOp{Type: TxnCreate, Cell: GlobalCell, Path: configHistoryEntryPath(kind, keyspace, baseline.Version), Contents: data})
+			prev = baseline
+		}
+
+		same, err := sameConfig(kind, prev, op.Contents, deleted)
+		if err != nil {
+			return nil, nil, err
+		}
+		entry := prev
+		if !same {
+			if entry, err = newConfigHistoryEntry(ctx, kind, keyspace, prev, op.Contents, deleted, comment); err != nil {
+				return nil, nil, err
+			}
+			data, err := entry.MarshalVT()
+			if err != nil {
+				return nil, nil, err
+			}
+			ops = append(ops, &TxnOp{Type: TxnCreate, Cell: GlobalCell, Path: configHistoryEntryPath(kind, keyspace, entry.Version), Contents: data})
+		}
+		if entry != head {
+			data, err := entry.MarshalVT()
+			if err != nil {
+				return nil, nil, err
+			}
+			headOp := &TxnOp{Type: TxnCreate, Cell: GlobalCell, Path: configHistoryHeadPath(kind, keyspace), Contents: data}
+			if head != nil {
+				headOp.Type = TxnUpdate
+				headOp.Version = headVersion
+			}
+			ops = append(ops, headOp)
+		}
+
+		// Every config save is a transaction on the global cell: on the
+		// backends without transactions, it is not worth a warning each
+		// time, as a failure can at worst leave the history without the
+		// latest version.
+		versions, err := ts.applyTxnOps(ctx, ops, false)
+		if err != nil {
+			if IsErrType(err, NodeExists) || IsErrType(err, BadVersion) {
+				// Retry if another process appended to the history,
+				// the object itself may not have changed.
+				if _, v, herr := ts.getConfigHistoryHead(ctx, kind, keyspace); herr == nil && !sameVersion(v, headVersion) {
+					continue
+				}
+			}
+			return nil, nil, err
+		}
+		if entry != head {
+			ts.pruneConfigHistory(ctx, kind, keyspace, entry.Version)
+		}
+		return versions[0], entry, nil
+	}
+	return nil, nil, vterrors.Errorf(vtrpcpb.Code_ABORTED, "too many concurrent changes to the config history of %v", configHistoryDir(kind, keyspace))
+}
+
+// sameVersion returns true if a and b are the same version of a file, or
+// both nil.
+func sameVersion(a, b Version) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.String() == b.String()
+}
+
+// pruneConfigHistory deletes the versions that fall out of the retention
+// window once last is appended. It goes back from the most recent one
+// until it finds a version that was already deleted, which is usually
+// right after one deletion. Failures are only logged: the versions left
+// behind are not returned anymore.
+func (ts *Server) pruneConfigHistory(ctx context.Context, kind topodatapb.ConfigKind, keyspace string, last int64) {
+	for v := last - configHistoryRetention; v > 0; v-- {
+		err := ts.globalCell.Delete(ctx, configHistoryEntryPath(kind, keyspace, v), nil)
+		if IsErrType(err, NoNode) {
+			return
+		}
+		if err != nil {
+			log.Warningf("failed to prune version %d from the config history of %v: %v", v, configHistoryDir(kind, keyspace), err)
+			return
+		}
+	}
+}
+
+// DiffConfigVersions returns the diff between two versions of an object.
+// The current object is used if toVersion is 0.
+func (ts *Server) DiffConfigVersions(ctx context.Context, kind topodatapb.ConfigKind, keyspace string, fromVersion, toVersion int64) (string, error) {
+	from, err := ts.GetConfigHistoryEntry(ctx, kind, keyspace, fromVersion)
+	if err != nil {
+		return "", err
+	}
+	fromJSON, err := configJSON(kind, from.Contents, from.Deleted)
+	if err != nil {
+		return "", err
+	}
+
+	toName := "current"
+	var toJSON string
+	if toVersion == 0 {
+		filePath, err := ts.configFilePath(kind, keyspace)
+		if err != nil {
+			return "", err
+		}
+		data, _, err := ts.globalCell.Get(ctx, filePath)
+		switch {
+		case IsErrType(err, NoNode):
+		case err != nil:
+			return "", err
+		default:
+			if toJSON, err = configJSON(kind, data, false); err != nil {
+				return "", err
+			}
+		}
+	} else {
+		to, err := ts.GetConfigHistoryEntry(ctx, kind, keyspace, toVersion)
+		if err != nil {
+			return "", err
+		}
+		if toJSON, err = configJSON(kind, to.Contents, to.Deleted); err != nil {
+			return "", err
+		}
+		toName = fmt.Sprintf("version %d", toVersion)
+	}
+	return textutil.UnifiedDiff(fmt.Sprintf("version %d", fromVersion), toName, fromJSON, toJSON), nil
+}
+
+// RollbackConfig restores a version of an object, and returns the entry
+// recording the rollback in its history. The object is only written if it
+// was not changed since we read it, so concurrent changes make the
+// rollback fail with a BadVersion error rather than being overwritten.
+func (ts *Server) RollbackConfig(ctx context.Context, kind topodatapb.ConfigKind, keyspace string, version int64) (*topodatapb.ConfigHistoryEntry, error) {
+	target, err := ts.GetConfigHistoryEntry(ctx, kind, keyspace, version)
+	if err != nil {
+		return nil, err
+	}
+	filePath, err := ts.configFilePath(kind, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	_, current, err := ts.globalCell.Get(ctx, filePath)
+	exists := err == nil
+	if err != nil && !IsErrType(err, NoNode) {
+		return nil, err
+	}
+
+	op := &TxnOp{Type: TxnCreate, Contents: target.Contents, Version: current}
+	switch {
+	case target.Deleted && !exists:
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "%v is already deleted", filePath)
+	case target.Deleted:
+		op.Type = TxnDelete
+	case exists:
+		op.Type = TxnUpdate
+	}
+	_, entry, err := ts.saveConfig(ctx, kind, keyspace, op, fmt.Sprintf("rollback to version %d", version))
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("rolled back %v to version %d of its config history", filePath, version)
+	return entry, nil
+}
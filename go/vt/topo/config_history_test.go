@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestConfigHistoryVSchema(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	ctx = callerid.NewContext(ctx, &vtrpcpb.CallerID{Principal: "alice"}, nil)
+
+	// A VSchema saved before the history existed.
+	v1 := &vschemapb.Keyspace{Tables: map[string]*vschemapb.Table{"t1": {}}}
+	data, err := v1.MarshalVT()
+	require.NoError(t, err)
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+	_, err = conn.Create(ctx, "keyspaces/ks/VSchema", data)
+	require.NoError(t, err)
+
+	v2 := &vschemapb.Keyspace{Tables: map[string]*vschemapb.Table{"t1": {}, "t2": {}}}
+	require.NoError(t, ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{Name: "ks", Keyspace: v2}))
+	// Saving the same VSchema again doesn't add a version.
+	require.NoError(t, ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{Name: "ks", Keyspace: v2}))
+
+	history, err := ts.GetConfigHistory(ctx, topodatapb.ConfigKind_VSCHEMA, "ks", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.EqualValues(t, 1, history[0].Version)
+	assert.Equal(t, "version before the config history was recorded", history[0].Comment)
+	assert.EqualValues(t, 2, history[1].Version)
+	assert.Equal(t, "alice", history[1].Author)
+	assert.Equal(t, "ks", history[1].Keyspace)
+	assert.NotNil(t, history[1].Time)
+	assert.Contains(t, history[1].Diff, "--- version 1\n+++ version 2\n")
+	assert.Contains(t, history[1].Diff, "\n+    \"t2\": {}")
+
+	// The current VSchema differs from the first version.
+	diff, err := ts.DiffConfigVersions(ctx, topodatapb.ConfigKind_VSCHEMA, "ks", 1, 0)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+++ current\n")
+	diff, err = ts.DiffConfigVersions(ctx, topodatapb.ConfigKind_VSCHEMA, "ks", 2, 0)
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+
+	// Roll back to the first version.
+	entry, err := ts.RollbackConfig(ctx, topodatapb.ConfigKind_VSCHEMA, "ks", 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, entry.Version)
+	assert.Equal(t, "rollback to version 1", entry.Comment)
+	assert.Contains(t, entry.Diff, "\n-    \"t2\": {}")
+	ksvs, err := ts.GetVSchema(ctx, "ks")
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(v1, ksvs.Keyspace))
+
+	history, err = ts.GetConfigHistory(ctx, topodatapb.ConfigKind_VSCHEMA, "ks", 2)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.EqualValues(t, 2, history[0].Version)
+	assert.EqualValues(t, 3, history[1].Version)
+
+	// Deleting the VSchema is recorded too.
+	require.NoError(t, ts.DeleteVSchema(ctx, "ks"))
+	history, err = ts.GetConfigHistory(ctx, topodatapb.ConfigKind_VSCHEMA, "ks", 1)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.True(t, history[0].Deleted)
+
+	_, err = ts.RollbackConfig(ctx, topodatapb.ConfigKind_VSCHEMA, "ks", 10)
+	assert.True(t, topo.IsErrType(err, topo.NoNode))
+	_, err = ts.GetConfigHistory(ctx, topodatapb.ConfigKind_VSCHEMA, "", 0)
+	assert.ErrorContains(t, err, "a keyspace is required")
+}
+
+func TestConfigHistoryRoutingRules(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	rules := &vschemapb.RoutingRules{Rules: []*vschemapb.RoutingRule{{FromTable: "t1", ToTables: []string{"ks2.t1"}}}}
+	require.NoError(t, ts.SaveRoutingRules(ctx, rules))
+	// Saving empty rules deletes them.
+	require.NoError(t, ts.SaveRoutingRules(ctx, &vschemapb.RoutingRules{}))
+
+	history, err := ts.GetConfigHistory(ctx, topodatapb.ConfigKind_ROUTING_RULES, "", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.False(t, history[0].Deleted)
+	assert.True(t, history[1].Deleted)
+
+	_, err = ts.RollbackConfig(ctx, topodatapb.ConfigKind_ROUTING_RULES, "", 1)
+	require.NoError(t, err)
+	got, err := ts.GetRoutingRules(ctx)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(rules, got))
+
+	// The history of the other rules is separate.
+	history, err = ts.GetConfigHistory(ctx, topodatapb.ConfigKind_MIRROR_RULES, "", 0)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestConfigHistoryRetention(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	defer topo.SetConfigHistoryRetention(3)()
+
+	for i := range 5 {
+		rules := &vschemapb.RoutingRules{Rules: []*vschemapb.RoutingRule{{FromTable: fmt.Sprintf("t%d", i), ToTables: []string{"ks2.t1"}}}}
+		require.NoError(t, ts.SaveRoutingRules(ctx, rules))
+	}
+
+	// Only the last versions are kept.
+	history, err := ts.GetConfigHistory(ctx, topodatapb.ConfigKind_ROUTING_RULES, "", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.EqualValues(t, 3, history[0].Version)
+	assert.EqualValues(t, 5, history[2].Version)
+	_, err = ts.GetConfigHistoryEntry(ctx, topodatapb.ConfigKind_ROUTING_RULES, "", 2)
+	assert.True(t, topo.IsErrType(err, topo.NoNode))
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+	entries, err := conn.ListDir(ctx, "config_history/routing_rules", false)
+	require.NoError(t, err)
+	assert.Len(t, entries, 4) // 3 versions and the head
+
+	// A write that fails doesn't add a version.
+	ksvs := &topo.KeyspaceVSchemaInfo{Name: "ks", Keyspace: &vschemapb.Keyspace{}}
+	require.NoError(t, ts.SaveVSchema(ctx, ksvs))
+	stale, err := ts.GetVSchema(ctx, "ks")
+	require.NoError(t, err)
+	ksvs.Keyspace.Sharded = true
+	require.NoError(t, ts.SaveVSchema(ctx, ksvs))
+	stale.Keyspace.Tables = map[string]*vschemapb.Table{"t1": {}}
+	err = ts.SaveVSchema(ctx, stale)
+	assert.True(t, topo.IsErrType(err, topo.BadVersion))
+	history, err = ts.GetConfigHistory(ctx, topodatapb.ConfigKind_VSCHEMA, "ks", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.NotContains(t, history[1].Diff, "t1")
+}
@@ -53,6 +53,8 @@ func DecodeContent(filename string, data []byte, json bool) (string, error) {
 		p = new(topodatapb.SrvKeyspace)
 	case RoutingRulesFile:
 		p = new(vschemapb.RoutingRules)
+	case RecoverySilencesFile:
+		p = new(topodatapb.RecoverySilences)
 	case CommonRoutingRulesFile:
 		switch path.Base(dir) {
 		case "keyspace":
@@ -53,6 +53,10 @@ var (
 	clientCertPath string
 	clientKeyPath  string
 	serverCaPath   string
+
+	// maxTxnOps is the maximum number of operations of a transaction.
+	// It matches the default --max-txn-ops of etcd.
+	maxTxnOps = 128
 )
 
 // Factory is the consul topo.Factory implementation.
@@ -76,6 +80,10 @@ type Server struct {
 	// root is the root path for this client.
 	root string
 
+	// serverAddr is the address of the etcd cluster, which identifies
+	// the backend for transactions.
+	serverAddr string
+
 	running chan struct{}
 }
 
@@ -90,6 +98,7 @@ func registerEtcd2TopoFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&clientCertPath, "topo_etcd_tls_cert", clientCertPath, "path to the client cert to use to connect to the etcd topo server, requires topo_etcd_tls_key, enables TLS")
 	fs.StringVar(&clientKeyPath, "topo_etcd_tls_key", clientKeyPath, "path to the client key to use to connect to the etcd topo server, enables TLS")
 	fs.StringVar(&serverCaPath, "topo_etcd_tls_ca", serverCaPath, "path to the ca to use to validate the server cert when connecting to the etcd topo server")
+	fs.IntVar(&maxTxnOps, "topo_etcd_max_txn_ops", maxTxnOps, "maximum number of operations in a single etcd transaction, which must not exceed the --max-txn-ops of the etcd server. Larger topo transactions are applied one operation at a time.")
 }
 
 // Close implements topo.Server.Close.
@@ -157,9 +166,10 @@ func NewServerWithOpts(serverAddr, root, certPath, keyPath, caPath string) (*Ser
 	}
 
 	return &Server{
-		cli:     cli,
-		root:    root,
-		running: make(chan struct{}),
+		cli:        cli,
+		root:       root,
+		serverAddr: serverAddr,
+		running:    make(chan struct{}),
 	}, nil
 }
 
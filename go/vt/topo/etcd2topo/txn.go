@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd2topo
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// TxnBackend is part of the topo.TxnConn interface. The cells that use
+// the same etcd cluster, with different roots, can be part of the same
+// transaction.
+func (s *Server) TxnBackend() string {
+	return "etcd2:" + s.serverAddr
+}
+
+// Txn is part of the topo.TxnConn interface. It applies all the ops in
+// a single etcd transaction.
+func (s *Server) Txn(ctx context.Context, ops []*topo.TxnOp) ([]topo.Version, error) {
+	if len(ops) > maxTxnOps {
+		return nil, topo.NewError(topo.NoImplementation, fmt.Sprintf("transaction of %d ops, more than --topo_etcd_max_txn_ops=%d", len(ops), maxTxnOps))
+	}
+
+	nodePaths := make([]string, len(ops))
+	var cmps []clientv3.Cmp
+	thenOps := make([]clientv3.Op, 0, len(ops))
+	elseOps := make([]clientv3.Op, 0, len(ops))
+	for i, op := range ops {
+		root := s.root
+		if op.Conn != nil {
+			conn, ok := op.Conn.(*Server)
+			if !ok || conn.serverAddr != s.serverAddr {
+				return nil, fmt.Errorf("transaction op on %v is not for the same etcd cluster", op.Path)
+			}
+			root = conn.root
+		}
+		nodePath := path.Join(root, op.Path)
+		nodePaths[i] = nodePath
+
+		switch op.Type {
+		case topo.TxnCreate:
+			cmps = append(cmps, clientv3.Compare(clientv3.Version(nodePath), "=", 0))
+		case topo.TxnCheck, topo.TxnUpdate, topo.TxnDelete:
+			switch {
+			case op.Version != nil:
+				cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(nodePath), "=", int64(op.Version.(EtcdVersion))))
+			case op.Type != topo.TxnUpdate:
+				cmps = append(cmps, clientv3.Compare(clientv3.Version(nodePath), ">", 0))
+			}
+		default:
+			return nil, fmt.Errorf("unknown transaction op type %v", op.Type)
+		}
+
+		switch op.Type {
+		case topo.TxnCreate, topo.TxnUpdate:
+			thenOps = append(thenOps, clientv3.OpPut(nodePath, string(op.Contents)))
+		case topo.TxnDelete:
+			thenOps = append(thenOps, clientv3.OpDelete(nodePath))
+		}
+		// If the transaction doesn't succeed, we read all the files, so
+		// we know which op failed, and why.
+		elseOps = append(elseOps, clientv3.OpGet(nodePath))
+	}
+
+	txnresp, err := s.cli.Txn(ctx).
+		If(cmps...).
+		Then(thenOps...).
+		Else(elseOps...).
+		Commit()
+	if err != nil {
+		return nil, convertError(err, nodePaths[0])
+	}
+	if !txnresp.Succeeded {
+		return nil, txnFailure(ops, nodePaths, txnresp)
+	}
+
+	versions := make([]topo.Version, len(ops))
+	for i, op := range ops {
+		if op.Type == topo.TxnCreate || op.Type == topo.TxnUpdate {
+			versions[i] = EtcdVersion(txnresp.Header.Revision)
+		}
+	}
+	return versions, nil
+}
+
+// txnFailure returns the error of the first op of a failed transaction
+// whose condition doesn't hold, from the files read by the Else ops.
+func txnFailure(ops []*topo.TxnOp, nodePaths []string, txnresp *clientv3.TxnResponse) error {
+	if len(txnresp.Responses) != len(ops) {
+		return ErrBadResponse
+	}
+	for i, op := range ops {
+		kvs := txnresp.Responses[i].GetResponseRange().GetKvs()
+		exists := len(kvs) > 0
+		switch {
+		case op.Type == topo.TxnCreate:
+			if exists {
+				return topo.NewError(topo.NodeExists, nodePaths[i])
+			}
+		case op.Type == topo.TxnUpdate && op.Version == nil:
+		case !exists:
+			return topo.NewError(topo.NoNode, nodePaths[i])
+		case op.Version != nil && kvs[0].ModRevision != int64(op.Version.(EtcdVersion)):
+			return topo.NewError(topo.BadVersion, nodePaths[i])
+		}
+	}
+	// The files changed again between the transaction and the reads.
+	return topo.NewError(topo.BadVersion, nodePaths[0])
+}
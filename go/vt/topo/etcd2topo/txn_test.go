@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd2topo
+
+import (
+	"context"
+	"os/exec"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// TestTxnArgs checks the transactions that are refused before they reach
+// etcd.
+func TestTxnArgs(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{root: "/global", serverAddr: "etcd1:2379"}
+	other := &Server{root: "/cell1", serverAddr: "etcd2:2379"}
+	assert.Equal(t, "etcd2:etcd1:2379", s.TxnBackend())
+	assert.NotEqual(t, s.TxnBackend(), other.TxnBackend())
+
+	defer func(max int) { maxTxnOps = max }(maxTxnOps)
+	maxTxnOps = 2
+	ops := []*topo.TxnOp{
+		{Type: topo.TxnCreate, Path: "a"},
+		{Type: topo.TxnCreate, Path: "b"},
+		{Type: topo.TxnCreate, Path: "c"},
+	}
+	_, err := s.Txn(ctx, ops)
+	assert.True(t, topo.IsErrType(err, topo.NoImplementation), "got %v", err)
+
+	_, err = s.Txn(ctx, []*topo.TxnOp{{Type: topo.TxnCreate, Conn: other, Path: "a"}})
+	assert.ErrorContains(t, err, "not for the same etcd cluster")
+}
+
+// TestEtcd2TopoTxn checks the transactions across two cells of the same
+// etcd cluster, and the fallback of the larger ones. It needs etcd.
+func TestEtcd2TopoTxn(t *testing.T) {
+	if _, err := exec.LookPath("etcd"); err != nil {
+		t.Skipf("etcd is not available: %v", err)
+	}
+	ctx := context.Background()
+	clientAddr, _ := startEtcd(t, 0)
+	ts, err := topo.OpenServer("etcd2", clientAddr, path.Join("/txn", topo.GlobalCell))
+	require.NoError(t, err)
+	defer ts.Close()
+	require.NoError(t, ts.CreateCellInfo(ctx, "cell1", &topodatapb.CellInfo{
+		ServerAddress: clientAddr,
+		Root:          "/txn/cell1",
+	}))
+	atomic, err := ts.TxnAtomic(ctx, []string{topo.GlobalCell, "cell1"})
+	require.NoError(t, err)
+	assert.True(t, atomic)
+
+	versions, err := ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnCreate, Cell: topo.GlobalCell, Path: "a", Contents: []byte("a1")},
+		{Type: topo.TxnCreate, Cell: "cell1", Path: "b", Contents: []byte("b1")},
+	})
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, versions[0].String(), versions[1].String())
+
+	// A stale version makes the whole transaction fail, and tells which
+	// file changed.
+	_, err = ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnCreate, Cell: topo.GlobalCell, Path: "c", Contents: []byte("c1")},
+		{Type: topo.TxnUpdate, Cell: "cell1", Path: "b", Contents: []byte("b2"), Version: versions[1]},
+		{Type: topo.TxnUpdate, Cell: topo.GlobalCell, Path: "a", Contents: []byte("a2"), Version: versions[0]},
+	})
+	require.NoError(t, err)
+	_, err = ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnDelete, Cell: topo.GlobalCell, Path: "c"},
+		{Type: topo.TxnUpdate, Cell: "cell1", Path: "b", Contents: []byte("b3"), Version: versions[1]},
+	})
+	require.True(t, topo.IsErrType(err, topo.BadVersion), "got %v", err)
+	assert.ErrorContains(t, err, "/txn/cell1/b")
+	_, err = ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnCheck, Cell: topo.GlobalCell, Path: "c"},
+		{Type: topo.TxnCreate, Cell: "cell1", Path: "b", Contents: []byte("b3")},
+	})
+	require.True(t, topo.IsErrType(err, topo.NodeExists), "got %v", err)
+
+	globalConn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+	contents, _, err := globalConn.Get(ctx, "c")
+	require.NoError(t, err)
+	assert.Equal(t, "c1", string(contents))
+	cellConn, err := ts.ConnForCell(ctx, "cell1")
+	require.NoError(t, err)
+	contents, _, err = cellConn.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, "b2", string(contents))
+
+	// Transactions with more ops than etcd accepts are applied one op at
+	// a time.
+	defer func(max int) { maxTxnOps = max }(maxTxnOps)
+	maxTxnOps = 1
+	_, err = ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnDelete, Cell: topo.GlobalCell, Path: "c"},
+		{Type: topo.TxnUpdate, Cell: "cell1", Path: "b", Contents: []byte("b3")},
+	})
+	require.NoError(t, err)
+	_, _, err = globalConn.Get(ctx, "c")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "got %v", err)
+	contents, _, err = cellConn.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, "b3", string(contents))
+}
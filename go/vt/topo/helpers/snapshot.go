@@ -0,0 +1,332 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// SnapshotFilter selects the files of a TopoSnapshot. Each empty field
+// selects everything.
+type SnapshotFilter struct {
+	// Cells are the names of the cells, "global" for the global topo.
+	Cells []string
+	// Keyspaces selects the files under keyspaces/<keyspace>/.
+	Keyspaces []string
+	// FileTypes are the names of the files, like SrvKeyspace or VSchema.
+	FileTypes []string
+}
+
+func (f *SnapshotFilter) matchesCell(cell string) bool {
+	return len(f.Cells) == 0 || slices.Contains(f.Cells, cell)
+}
+
+func (f *SnapshotFilter) matchesFile(filePath string) bool {
+	if len(f.FileTypes) > 0 && !slices.Contains(f.FileTypes, path.Base(filePath)) {
+		return false
+	}
+	if len(f.Keyspaces) == 0 {
+		return true
+	}
+	for _, keyspace := range f.Keyspaces {
+		if strings.HasPrefix(filePath, path.Join(topo.KeyspacesPath, keyspace)+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotFile is a file read from the live topo.
+type snapshotFile struct {
+	contents []byte
+	version  topo.Version
+}
+
+// readCellFiles reads all the files of a cell, except the ephemeral ones
+// like locks, keyed by their path relative to the root of the cell.
+func readCellFiles(ctx context.Context, conn topo.Conn) (map[string]*snapshotFile, error) {
+	files := make(map[string]*snapshotFile)
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		entries, err := conn.ListDir(ctx, dir, true /* full */)
+		switch {
+		case err == nil:
+		case topo.IsErrType(err, topo.NoNode):
+			return nil
+		default:
+			return vterrors.Wrapf(err, "ListDir(%v)", dir)
+		}
+		for _, entry := range entries {
+			if entry.Ephemeral {
+				continue
+			}
+			entryPath := path.Join(dir, entry.Name)
+			if entry.Type == topo.TypeDirectory {
+				if err := walk(entryPath); err != nil {
+					return err
+				}
+				continue
+			}
+			contents, version, err := conn.Get(ctx, entryPath)
+			switch {
+			case err == nil:
+				files[strings.TrimPrefix(entryPath, "/")] = &snapshotFile{contents: contents, version: version}
+			case topo.IsErrType(err, topo.NoNode):
+				// The file was deleted since we listed it.
+			default:
+				return vterrors.Wrapf(err, "Get(%v)", entryPath)
+			}
+		}
+		return nil
+	}
+	if err := walk("/"); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// SnapshotTopology reads all the files of the global topo and of cells,
+// except the ephemeral ones like locks, into a TopoSnapshot. If cells is
+// empty, it reads the global topo and all the cells. The cells are read
+// one after the other, so the snapshot is only consistent if nothing
+// changes the topo in the meantime.
+func SnapshotTopology(ctx context.Context, ts *topo.Server, cells []string) (*topodatapb.TopoSnapshot, error) {
+	if len(cells) == 0 {
+		cellNames, err := ts.GetCellInfoNames(ctx)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "GetCellInfoNames")
+		}
+		cells = append([]string{topo.GlobalCell}, cellNames...)
+	}
+
+	snapshot := &topodatapb.TopoSnapshot{
+		Time: protoutil.TimeToProto(time.Now()),
+	}
+	for _, cell := range cells {
+		conn, err := ts.ConnForCell(ctx, cell)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "ConnForCell(%v)", cell)
+		}
+		files, err := readCellFiles(ctx, conn)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to read cell %v", cell)
+		}
+		snapshotCell := &topodatapb.TopoSnapshotCell{Name: cell}
+		for _, filePath := range sortedPaths(files) {
+			snapshotCell.Files = append(snapshotCell.Files, &topodatapb.TopoSnapshotFile{
+				Path:     filePath,
+				Contents: files[filePath].contents,
+			})
+		}
+		snapshot.Cells = append(snapshot.Cells, snapshotCell)
+	}
+	return snapshot, nil
+}
+
+// DiffTopoSnapshots returns the changes from one snapshot to another, for
+// the files selected by filter, sorted by cell and path.
+func DiffTopoSnapshots(from, to *topodatapb.TopoSnapshot, filter *SnapshotFilter) []*topodatapb.TopoSnapshotChange {
+	fromFiles := snapshotFiles(from, filter)
+	toFiles := snapshotFiles(to, filter)
+
+	var cells []string
+	for cell := range fromFiles {
+		cells = append(cells, cell)
+	}
+	for cell := range toFiles {
+		if _, ok := fromFiles[cell]; !ok {
+			cells = append(cells, cell)
+		}
+	}
+	sort.Strings(cells)
+
+	var changes []*topodatapb.TopoSnapshotChange
+	for _, cell := range cells {
+		changes = append(changes, diffCellFiles(cell, fromFiles[cell], toFiles[cell])...)
+	}
+	return changes
+}
+
+// snapshotFiles returns the contents of the files of snapshot selected by
+// filter, keyed by cell and path.
+func snapshotFiles(snapshot *topodatapb.TopoSnapshot, filter *SnapshotFilter) map[string]map[string][]byte {
+	result := make(map[string]map[string][]byte)
+	for _, cell := range snapshot.GetCells() {
+		if !filter.matchesCell(cell.Name) {
+			continue
+		}
+		files := make(map[string][]byte)
+		for _, file := range cell.Files {
+			if filter.matchesFile(file.Path) {
+				files[file.Path] = file.Contents
+			}
+		}
+		result[cell.Name] = files
+	}
+	return result
+}
+
+// diffCellFiles returns the changes from one version of the files of a
+// cell to another, sorted by path.
+func diffCellFiles(cell string, from, to map[string][]byte) []*topodatapb.TopoSnapshotChange {
+	var paths []string
+	for filePath := range from {
+		paths = append(paths, filePath)
+	}
+	for filePath := range to {
+		if _, ok := from[filePath]; !ok {
+			paths = append(paths, filePath)
+		}
+	}
+	sort.Strings(paths)
+
+	var changes []*topodatapb.TopoSnapshotChange
+	for _, filePath := range paths {
+		fromContents, inFrom := from[filePath]
+		toContents, inTo := to[filePath]
+		change := &topodatapb.TopoSnapshotChange{Cell: cell, Path: filePath}
+		switch {
+		case !inFrom:
+			change.Type = topodatapb.TopoSnapshotChange_CREATED
+		case !inTo:
+			change.Type = topodatapb.TopoSnapshotChange_DELETED
+		case bytes.Equal(fromContents, toContents):
+			continue
+		default:
+			change.Type = topodatapb.TopoSnapshotChange_UPDATED
+		}
+		name := cell + ":" + filePath
+		change.Diff = textutil.UnifiedDiff("a/"+name, "b/"+name, decodeSnapshotFile(filePath, fromContents, inFrom), decodeSnapshotFile(filePath, toContents, inTo))
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// decodeSnapshotFile returns the contents of a file as JSON if its type is
+// known, for diffs.
+func decodeSnapshotFile(filePath string, contents []byte, exists bool) string {
+	if !exists {
+		return ""
+	}
+	decoded, err := topo.DecodeContent(filePath, contents, true /* json */)
+	if err != nil {
+		return string(contents)
+	}
+	return decoded + "\n"
+}
+
+// RestoreTopology writes the files of snapshot selected by filter back to
+// the topo with topo.Server.ApplyTxnOps: the files that are missing are
+// created, and the ones that differ are updated. If prune is set, the
+// selected files that are not in the snapshot are deleted. Every write is
+// a compare-and-swap on the version that was read, so a concurrent change
+// fails the restore rather than being overwritten.
+// The restore is atomic only if ApplyTxnOps can apply it in a single
+// transaction, that is if all the cells share an etcd cluster and there
+// are no more writes than --topo_etcd_max_txn_ops. Otherwise the writes
+// are applied one at a time, and a process that dies in the middle
+// leaves the restore partially applied.
+// It returns the changes, from the live topo to the snapshot. If dryRun
+// is set, they are not applied.
+// The cells of the snapshot must exist in the topo: when restoring a
+// lost topology, the global topo, which has the CellInfos, has to be
+// restored first.
+func RestoreTopology(ctx context.Context, ts *topo.Server, snapshot *topodatapb.TopoSnapshot, filter *SnapshotFilter, prune, dryRun bool) ([]*topodatapb.TopoSnapshotChange, error) {
+	snapshotFiles := snapshotFiles(snapshot, filter)
+	for _, cell := range filter.Cells {
+		if _, ok := snapshotFiles[cell]; !ok {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "cell %v is not in the snapshot", cell)
+		}
+	}
+
+	var cells []string
+	for cell := range snapshotFiles {
+		cells = append(cells, cell)
+	}
+	sort.Strings(cells)
+
+	var changes []*topodatapb.TopoSnapshotChange
+	var ops []*topo.TxnOp
+	for _, cell := range cells {
+		conn, err := ts.ConnForCell(ctx, cell)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "ConnForCell(%v)", cell)
+		}
+		liveFiles, err := readCellFiles(ctx, conn)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to read cell %v", cell)
+		}
+		live := make(map[string][]byte)
+		for filePath, file := range liveFiles {
+			if filter.matchesFile(filePath) {
+				live[filePath] = file.contents
+			}
+		}
+
+		for _, change := range diffCellFiles(cell, live, snapshotFiles[cell]) {
+			op := &topo.TxnOp{Cell: cell, Path: change.Path}
+			switch change.Type {
+			case topodatapb.TopoSnapshotChange_CREATED:
+				op.Type = topo.TxnCreate
+				op.Contents = snapshotFiles[cell][change.Path]
+			case topodatapb.TopoSnapshotChange_UPDATED:
+				op.Type = topo.TxnUpdate
+				op.Contents = snapshotFiles[cell][change.Path]
+				op.Version = liveFiles[change.Path].version
+			case topodatapb.TopoSnapshotChange_DELETED:
+				if !prune {
+					continue
+				}
+				op.Type = topo.TxnDelete
+				op.Version = liveFiles[change.Path].version
+			}
+			changes = append(changes, change)
+			ops = append(ops, op)
+		}
+	}
+
+	if dryRun || len(ops) == 0 {
+		return changes, nil
+	}
+	if _, err := ts.ApplyTxnOps(ctx, ops); err != nil {
+		return nil, vterrors.Wrapf(err, "failed to restore %d files", len(ops))
+	}
+	return changes, nil
+}
+
+func sortedPaths(files map[string]*snapshotFile) []string {
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	return paths
+}
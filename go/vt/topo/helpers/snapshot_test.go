@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func changeStrings(changes []*topodatapb.TopoSnapshotChange) []string {
+	var result []string
+	for _, change := range changes {
+		result = append(result, change.Type.String()+" "+change.Cell+":"+change.Path)
+	}
+	return result
+}
+
+func TestSnapshotTopology(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	for _, keyspace := range []string{"ks1", "ks2"} {
+		require.NoError(t, ts.CreateKeyspace(ctx, keyspace, &topodatapb.Keyspace{}))
+		require.NoError(t, ts.CreateShard(ctx, keyspace, "0"))
+		require.NoError(t, ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{Name: keyspace, Keyspace: &vschemapb.Keyspace{
+			Tables: map[string]*vschemapb.Table{"t1": {}},
+		}}))
+		require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone1", keyspace, &topodatapb.SrvKeyspace{
+			Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{{ServedType: topodatapb.TabletType_PRIMARY}},
+		}))
+	}
+
+	// Locks are not part of the snapshot.
+	lockCtx, unlock, err := ts.LockKeyspace(ctx, "ks1", "snapshot")
+	require.NoError(t, err)
+	snapshot, err := SnapshotTopology(lockCtx, ts, nil)
+	require.NoError(t, err)
+	unlock(&err)
+	require.NoError(t, err)
+
+	require.Len(t, snapshot.Cells, 2)
+	assert.Equal(t, topo.GlobalCell, snapshot.Cells[0].Name)
+	assert.Equal(t, "zone1", snapshot.Cells[1].Name)
+	var zone1Paths []string
+	for _, file := range snapshot.Cells[1].Files {
+		zone1Paths = append(zone1Paths, file.Path)
+	}
+	assert.Equal(t, []string{"keyspaces/ks1/SrvKeyspace", "keyspaces/ks2/SrvKeyspace"}, zone1Paths)
+
+	// Break both keyspaces, and add a third one.
+	for _, keyspace := range []string{"ks1", "ks2"} {
+		require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone1", keyspace, &topodatapb.SrvKeyspace{}))
+		require.NoError(t, ts.DeleteVSchema(ctx, keyspace))
+	}
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks3", &topodatapb.Keyspace{}))
+
+	current, err := SnapshotTopology(ctx, ts, nil)
+	require.NoError(t, err)
+	changes := DiffTopoSnapshots(snapshot, current, &SnapshotFilter{FileTypes: []string{topo.KeyspaceFile, topo.SrvKeyspaceFile, topo.VSchemaFile}})
+	assert.Equal(t, []string{
+		"DELETED global:keyspaces/ks1/VSchema",
+		"DELETED global:keyspaces/ks2/VSchema",
+		"CREATED global:keyspaces/ks3/Keyspace",
+		"UPDATED zone1:keyspaces/ks1/SrvKeyspace",
+		"UPDATED zone1:keyspaces/ks2/SrvKeyspace",
+	}, changeStrings(changes))
+	assert.Contains(t, changes[len(changes)-1].Diff, `-      "served_type": "PRIMARY"`)
+
+	// A dry run doesn't change anything.
+	filter := &SnapshotFilter{Keyspaces: []string{"ks1"}, FileTypes: []string{topo.SrvKeyspaceFile, topo.VSchemaFile}}
+	changes, err = RestoreTopology(ctx, ts, snapshot, filter, false /* prune */, true /* dryRun */)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"CREATED global:keyspaces/ks1/VSchema",
+		"UPDATED zone1:keyspaces/ks1/SrvKeyspace",
+	}, changeStrings(changes))
+	_, err = ts.GetVSchema(ctx, "ks1")
+	require.True(t, topo.IsErrType(err, topo.NoNode), "got %v", err)
+
+	// Restore the SrvKeyspace and the VSchema of ks1 only.
+	_, err = RestoreTopology(ctx, ts, snapshot, filter, false /* prune */, false /* dryRun */)
+	require.NoError(t, err)
+	vschema, err := ts.GetVSchema(ctx, "ks1")
+	require.NoError(t, err)
+	assert.Contains(t, vschema.Tables, "t1")
+	srvKeyspace, err := ts.GetSrvKeyspace(ctx, "zone1", "ks1")
+	require.NoError(t, err)
+	assert.Len(t, srvKeyspace.Partitions, 1)
+	_, err = ts.GetVSchema(ctx, "ks2")
+	require.True(t, topo.IsErrType(err, topo.NoNode), "got %v", err)
+	srvKeyspace, err = ts.GetSrvKeyspace(ctx, "zone1", "ks2")
+	require.NoError(t, err)
+	assert.Empty(t, srvKeyspace.Partitions)
+
+	// Pruning deletes ks3.
+	changes, err = RestoreTopology(ctx, ts, snapshot, &SnapshotFilter{Cells: []string{topo.GlobalCell}, Keyspaces: []string{"ks3"}}, true /* prune */, false /* dryRun */)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DELETED global:keyspaces/ks3/Keyspace"}, changeStrings(changes))
+	_, err = ts.GetKeyspace(ctx, "ks3")
+	require.True(t, topo.IsErrType(err, topo.NoNode), "got %v", err)
+
+	// Restoring everything with prune brings the topo back to the snapshot.
+	changes, err = RestoreTopology(ctx, ts, snapshot, &SnapshotFilter{}, true /* prune */, false /* dryRun */)
+	require.NoError(t, err)
+	assert.NotEmpty(t, changes)
+	current, err = SnapshotTopology(ctx, ts, nil)
+	require.NoError(t, err)
+	assert.Empty(t, changeStrings(DiffTopoSnapshots(snapshot, current, &SnapshotFilter{})))
+
+	// Cells that are not in the snapshot can't be restored.
+	_, err = RestoreTopology(ctx, ts, snapshot, &SnapshotFilter{Cells: []string{"zone2"}}, false /* prune */, false /* dryRun */)
+	require.ErrorContains(t, err, "cell zone2 is not in the snapshot")
+}
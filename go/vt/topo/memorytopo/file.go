@@ -50,6 +50,11 @@ func (c *Conn) Create(ctx context.Context, filePath string, contents []byte) (to
 		return nil, err
 	}
 
+	return c.createLocked(filePath, contents)
+}
+
+// createLocked creates a file. The caller must hold c.factory.mu.
+func (c *Conn) createLocked(filePath string, contents []byte) (topo.Version, error) {
 	// Get the parent dir.
 	dir, file := path.Split(filePath)
 	p := c.factory.getOrCreatePath(c.cell, dir)
@@ -99,6 +104,12 @@ func (c *Conn) Update(ctx context.Context, filePath string, contents []byte, ver
 		return nil, err
 	}
 
+	return c.updateLocked(filePath, contents, version)
+}
+
+// updateLocked updates a file, or creates it if version is nil. The
+// caller must hold c.factory.mu.
+func (c *Conn) updateLocked(filePath string, contents []byte, version topo.Version) (topo.Version, error) {
 	// Get the parent dir, we'll need it in case of creation.
 	dir, file := path.Split(filePath)
 	p := c.factory.nodeByPath(c.cell, dir)
@@ -275,6 +286,11 @@ func (c *Conn) Delete(ctx context.Context, filePath string, version topo.Version
 		return err
 	}
 
+	return c.deleteLocked(filePath, version)
+}
+
+// deleteLocked deletes a file. The caller must hold c.factory.mu.
+func (c *Conn) deleteLocked(filePath string, version topo.Version) error {
 	// Get the parent dir.
 	dir, file := path.Split(filePath)
 	p := c.factory.nodeByPath(c.cell, dir)
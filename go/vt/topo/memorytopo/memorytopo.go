@@ -68,6 +68,7 @@ const (
 	WatchRecursive
 	NewLeaderParticipation
 	Close
+	Txn
 )
 
 // Factory is a memory-based implementation of topo.Factory.  It
@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorytopo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// TxnBackend is part of the topo.TxnConn interface. All the cells of a
+// Factory can be part of the same transaction.
+func (c *Conn) TxnBackend() string {
+	return fmt.Sprintf("memorytopo:%p", c.factory)
+}
+
+// Txn is part of the topo.TxnConn interface.
+func (c *Conn) Txn(ctx context.Context, ops []*topo.TxnOp) ([]topo.Version, error) {
+	c.factory.callstats.Add([]string{"Txn"}, 1)
+
+	conns := make([]*Conn, len(ops))
+	for i, op := range ops {
+		conns[i] = c
+		if op.Conn != nil {
+			conn, ok := op.Conn.(*Conn)
+			if !ok || conn.factory != c.factory {
+				return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "transaction op on %v is not for the same memorytopo", op.Path)
+			}
+			conns[i] = conn
+		}
+		if err := conns[i].dial(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	c.factory.mu.Lock()
+	defer c.factory.mu.Unlock()
+
+	if c.factory.err != nil {
+		return nil, c.factory.err
+	}
+
+	// Check everything first, so we either apply all the ops or none.
+	for i, op := range ops {
+		if err := c.factory.getOperationError(Txn, op.Path); err != nil {
+			return nil, err
+		}
+		if err := c.factory.getOperationError(txnOperation(op.Type), op.Path); err != nil {
+			return nil, err
+		}
+		if err := conns[i].checkTxnOpLocked(op); err != nil {
+			return nil, err
+		}
+	}
+
+	versions := make([]topo.Version, len(ops))
+	for i, op := range ops {
+		var err error
+		switch op.Type {
+		case topo.TxnCreate:
+			versions[i], err = conns[i].createLocked(op.Path, txnContents(op))
+		case topo.TxnUpdate:
+			versions[i], err = conns[i].updateLocked(op.Path, txnContents(op), op.Version)
+		case topo.TxnDelete:
+			err = conns[i].deleteLocked(op.Path, op.Version)
+		}
+		if err != nil {
+			// This can't happen, as checkTxnOpLocked checked the ops.
+			return nil, vterrors.Wrapf(err, "transaction partially applied")
+		}
+	}
+	return versions, nil
+}
+
+// txnOperation returns the Operation of a transaction op, for
+// AddOperationError.
+func txnOperation(opType topo.TxnOpType) Operation {
+	switch opType {
+	case topo.TxnCreate:
+		return Create
+	case topo.TxnUpdate:
+		return Update
+	case topo.TxnDelete:
+		return Delete
+	}
+	return Get
+}
+
+func txnContents(op *topo.TxnOp) []byte {
+	if op.Contents == nil {
+		return []byte{}
+	}
+	return op.Contents
+}
+
+// checkTxnOpLocked checks that op would succeed. The caller must hold
+// c.factory.mu.
+func (c *Conn) checkTxnOpLocked(op *topo.TxnOp) error {
+	n := c.factory.nodeByPath(c.cell, op.Path)
+	if n != nil && n.isDirectory() {
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "%v(%v, %v) failed: it's a directory", op.Type, c.cell, op.Path)
+	}
+	dir, _ := path.Split(op.Path)
+
+	switch op.Type {
+	case topo.TxnCreate:
+		if n != nil {
+			return topo.NewError(topo.NodeExists, op.Path)
+		}
+		if c.pathHasFileLocked(dir) {
+			return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "trying to create file %v in cell %v in a path that contains files", op.Path, c.cell)
+		}
+		return nil
+	case topo.TxnUpdate:
+		if op.Version == nil && n == nil {
+			if c.pathHasFileLocked(dir) {
+				return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "trying to create file %v in cell %v in a path that contains files", op.Path, c.cell)
+			}
+			return nil
+		}
+	case topo.TxnCheck, topo.TxnDelete:
+	default:
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "unknown transaction op type %v", op.Type)
+	}
+
+	if n == nil {
+		return topo.NewError(topo.NoNode, op.Path)
+	}
+	if op.Version != nil && n.version != uint64(op.Version.(NodeVersion)) {
+		return topo.NewError(topo.BadVersion, op.Path)
+	}
+	return nil
+}
+
+// pathHasFileLocked returns true if one of the elements of dirPath is a
+// file, or if the cell doesn't exist, which are the cases where
+// getOrCreatePath fails. The caller must hold c.factory.mu.
+func (c *Conn) pathHasFileLocked(dirPath string) bool {
+	n, ok := c.factory.cells[c.cell]
+	if !ok {
+		return true
+	}
+	for _, part := range strings.Split(dirPath, "/") {
+		if part == "" {
+			continue
+		}
+		if !n.isDirectory() {
+			return true
+		}
+		child, ok := n.children[part]
+		if !ok {
+			return false
+		}
+		n = child
+	}
+	return !n.isDirectory()
+}
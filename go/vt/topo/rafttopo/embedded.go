@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/grpccommon"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// The embedded node is only configurable in vtctld, which then acts as
+// a member of the consensus group in addition to being a topo client.
+var (
+	nodeID            string
+	peers             string
+	dataDir           string
+	electionTimeout   = 1 * time.Second
+	heartbeatInterval = 100 * time.Millisecond
+	snapshotThreshold = uint64(10000)
+)
+
+func init() {
+	servenv.OnParseFor("vtctld", registerEmbeddedNodeFlags)
+	servenv.OnInit(startEmbeddedNode)
+}
+
+func registerEmbeddedNodeFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&nodeID, "topo_raft_node_id", nodeID, "If set, run an embedded raft topo node with this id. It must be one of the ids in --topo_raft_peers.")
+	fs.StringVar(&peers, "topo_raft_peers", peers, "Comma separated list of id=host:port of all the members of the raft topo group, including this one. The node serves the raft topo gRPC service on the port of its own address.")
+	fs.StringVar(&dataDir, "topo_raft_data_dir", dataDir, "Directory the embedded raft topo node persists its log and snapshots in.")
+	fs.DurationVar(&electionTimeout, "topo_raft_election_timeout", electionTimeout, "Minimum time without hearing from the raft topo leader before a node starts an election.")
+	fs.DurationVar(&heartbeatInterval, "topo_raft_heartbeat_interval", heartbeatInterval, "Interval at which the raft topo leader sends heartbeats. Must be smaller than --topo_raft_election_timeout.")
+	fs.Uint64Var(&snapshotThreshold, "topo_raft_snapshot_threshold", snapshotThreshold, "Number of raft topo log entries after which the log is compacted into a snapshot.")
+}
+
+// ParsePeers parses a comma separated list of id=host:port.
+func ParsePeers(s string) (map[string]string, error) {
+	res := make(map[string]string)
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		id, addr, ok := strings.Cut(p, "=")
+		if !ok || id == "" || addr == "" {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid raft topo peer %q, expected id=host:port", p)
+		}
+		if _, ok := res[id]; ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate raft topo peer id %v", id)
+		}
+		res[id] = addr
+	}
+	return res, nil
+}
+
+// StartNode creates a Node, serves its gRPC service on the port of its
+// address, and starts it. The returned function stops both.
+func StartNode(cfg Config) (*Node, func(), error) {
+	_, port, err := net.SplitHostPort(cfg.Peers[cfg.ID])
+	if err != nil {
+		return nil, nil, vterrors.Wrapf(err, "invalid address for raft topo node %v", cfg.ID)
+	}
+	node, err := NewNode(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort("", port))
+	if err != nil {
+		node.storage.close()
+		return nil, nil, err
+	}
+
+	opts, err := cfg.TLS.serverOptions()
+	if err != nil {
+		node.storage.close()
+		listener.Close()
+		return nil, nil, err
+	}
+	msgSize := grpccommon.MaxMessageSize()
+	opts = append(opts, grpc.MaxRecvMsgSize(msgSize), grpc.MaxSendMsgSize(msgSize))
+	server := grpc.NewServer(opts...)
+	RegisterServer(server, node)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Errorf("raft topo node %v stopped serving: %v", cfg.ID, err)
+		}
+	}()
+	if err := node.Start(); err != nil {
+		server.Stop()
+		node.storage.close()
+		return nil, nil, err
+	}
+
+	stop := func() {
+		server.Stop()
+		node.Close()
+	}
+	return node, stop, nil
+}
+
+// startEmbeddedNode starts the embedded node, if configured. It runs
+// before the process opens its topo server, so the process can use its
+// own node.
+func startEmbeddedNode() {
+	if nodeID == "" {
+		return
+	}
+	peerMap, err := ParsePeers(peers)
+	if err != nil {
+		log.Exitf("invalid --topo_raft_peers: %v", err)
+	}
+	if dataDir == "" {
+		log.Exitf("--topo_raft_data_dir is required with --topo_raft_node_id")
+	}
+
+	_, stop, err := StartNode(Config{
+		ID:                nodeID,
+		Peers:             peerMap,
+		DataDir:           dataDir,
+		ElectionTimeout:   electionTimeout,
+		HeartbeatInterval: heartbeatInterval,
+		SnapshotThreshold: snapshotThreshold,
+		TLS:               flagsTLSConfig(),
+	})
+	if err != nil {
+		log.Exitf("failed to start raft topo node %v: %v", nodeID, err)
+	}
+	log.Infof("raft topo node %v started with peers %v", nodeID, peerMap)
+	servenv.OnClose(stop)
+}
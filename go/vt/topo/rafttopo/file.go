@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"path"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	toporaftpb "vitess.io/vitess/go/vt/proto/toporaft"
+)
+
+// Create is part of the topo.Conn interface.
+func (s *Server) Create(ctx context.Context, filePath string, contents []byte) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	resp, err := s.propose(ctx, &toporaftpb.Command{
+		Type:   toporaftpb.Command_PUT,
+		Key:    nodePath,
+		Value:  contents,
+		Create: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return RaftVersion(resp.Revision), nil
+}
+
+// Update is part of the topo.Conn interface.
+func (s *Server) Update(ctx context.Context, filePath string, contents []byte, version topo.Version) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	cmd := &toporaftpb.Command{
+		Type:  toporaftpb.Command_PUT,
+		Key:   nodePath,
+		Value: contents,
+	}
+	if version != nil {
+		// The update is only applied if the current file revision
+		// is what we expect.
+		cmd.ExpectedRevision = int64(version.(RaftVersion))
+	}
+	resp, err := s.propose(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return RaftVersion(resp.Revision), nil
+}
+
+// Get is part of the topo.Conn interface.
+func (s *Server) Get(ctx context.Context, filePath string) ([]byte, topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	resp, err := s.rangeKeys(ctx, nodePath, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(resp.Kvs) != 1 {
+		return nil, nil, topo.NewError(topo.NoNode, nodePath)
+	}
+	return resp.Kvs[0].Value, RaftVersion(resp.Kvs[0].ModRevision), nil
+}
+
+// GetVersion is part of the topo.Conn interface.
+// The nodes only keep the latest version of every key.
+func (s *Server) GetVersion(ctx context.Context, filePath string, version int64) ([]byte, error) {
+	return nil, topo.NewError(topo.NoImplementation, "GetVersion not supported in raft topo")
+}
+
+// List is part of the topo.Conn interface.
+func (s *Server) List(ctx context.Context, filePathPrefix string) ([]topo.KVInfo, error) {
+	nodePathPrefix := path.Join(s.root, filePathPrefix)
+
+	resp, err := s.rangeKeys(ctx, nodePathPrefix, true)
+	if err != nil {
+		return []topo.KVInfo{}, err
+	}
+	if len(resp.Kvs) == 0 {
+		return []topo.KVInfo{}, topo.NewError(topo.NoNode, nodePathPrefix)
+	}
+	results := make([]topo.KVInfo, len(resp.Kvs))
+	for n, kv := range resp.Kvs {
+		results[n].Key = []byte(kv.Key)
+		results[n].Value = kv.Value
+		results[n].Version = RaftVersion(kv.ModRevision)
+	}
+	return results, nil
+}
+
+// Delete is part of the topo.Conn interface.
+func (s *Server) Delete(ctx context.Context, filePath string, version topo.Version) error {
+	nodePath := path.Join(s.root, filePath)
+
+	cmd := &toporaftpb.Command{
+		Type: toporaftpb.Command_DELETE,
+		Key:  nodePath,
+	}
+	if version != nil {
+		cmd.ExpectedRevision = int64(version.(RaftVersion))
+	}
+	_, err := s.propose(ctx, cmd)
+	return err
+}
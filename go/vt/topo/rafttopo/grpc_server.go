@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	toporaftpb "vitess.io/vitess/go/vt/proto/toporaft"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// leaderTrailer is the gRPC trailer in which a node that is not the
+// leader returns the address of the leader, if it knows it.
+const leaderTrailer = "raft-leader"
+
+// grpcServer implements toporaftpb.TopoRaftServer on top of a Node.
+type grpcServer struct {
+	toporaftpb.UnimplementedTopoRaftServer
+
+	node *Node
+}
+
+// RegisterServer registers the TopoRaft service of node with s.
+func RegisterServer(s *grpc.Server, node *Node) {
+	toporaftpb.RegisterTopoRaftServer(s, &grpcServer{node: node})
+}
+
+// RequestVote is part of the toporaftpb.TopoRaftServer interface.
+func (s *grpcServer) RequestVote(ctx context.Context, req *toporaftpb.RequestVoteRequest) (*toporaftpb.RequestVoteResponse, error) {
+	resp, err := s.node.RequestVote(req)
+	return resp, vterrors.ToGRPC(err)
+}
+
+// AppendEntries is part of the toporaftpb.TopoRaftServer interface.
+func (s *grpcServer) AppendEntries(ctx context.Context, req *toporaftpb.AppendEntriesRequest) (*toporaftpb.AppendEntriesResponse, error) {
+	resp, err := s.node.AppendEntries(req)
+	return resp, vterrors.ToGRPC(err)
+}
+
+// InstallSnapshot is part of the toporaftpb.TopoRaftServer interface.
+func (s *grpcServer) InstallSnapshot(ctx context.Context, req *toporaftpb.InstallSnapshotRequest) (*toporaftpb.InstallSnapshotResponse, error) {
+	resp, err := s.node.InstallSnapshot(req)
+	return resp, vterrors.ToGRPC(err)
+}
+
+// Range is part of the toporaftpb.TopoRaftServer interface.
+func (s *grpcServer) Range(ctx context.Context, req *toporaftpb.RangeRequest) (*toporaftpb.RangeResponse, error) {
+	resp, err := s.node.Range(ctx, req.Key, req.Prefix)
+	return resp, s.toGRPC(ctx, err)
+}
+
+// Propose is part of the toporaftpb.TopoRaftServer interface.
+func (s *grpcServer) Propose(ctx context.Context, req *toporaftpb.ProposeRequest) (*toporaftpb.ProposeResponse, error) {
+	if req.Command == nil {
+		return nil, vterrors.ToGRPC(vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "missing command"))
+	}
+	resp, err := s.node.Propose(ctx, req.Command)
+	return resp, s.toGRPC(ctx, err)
+}
+
+// KeepAlive is part of the toporaftpb.TopoRaftServer interface.
+func (s *grpcServer) KeepAlive(ctx context.Context, req *toporaftpb.KeepAliveRequest) (*toporaftpb.KeepAliveResponse, error) {
+	ttl, err := s.node.KeepAlive(ctx, req.Lease)
+	if err != nil {
+		return nil, s.toGRPC(ctx, err)
+	}
+	return &toporaftpb.KeepAliveResponse{TtlSeconds: ttl}, nil
+}
+
+// Watch is part of the toporaftpb.TopoRaftServer interface.
+// Watches are served from the local state machine, so they can be
+// served by any node.
+func (s *grpcServer) Watch(req *toporaftpb.WatchRequest, stream toporaftpb.TopoRaft_WatchServer) error {
+	w, backlog, err := s.node.watch(req.Key, req.Prefix, req.StartRevision)
+	if err != nil {
+		return vterrors.ToGRPC(err)
+	}
+	defer s.node.unwatch(w)
+
+	for _, resp := range backlog {
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return vterrors.ToGRPC(stream.Context().Err())
+		case <-s.node.stop:
+			return vterrors.ToGRPC(vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "raft node %v is shutting down", s.node.ID()))
+		case <-w.done:
+			return vterrors.ToGRPC(w.err)
+		case resp := <-w.responses:
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Status is part of the toporaftpb.TopoRaftServer interface.
+func (s *grpcServer) Status(ctx context.Context, req *toporaftpb.StatusRequest) (*toporaftpb.StatusResponse, error) {
+	return s.node.Status(), nil
+}
+
+// toGRPC converts err to a gRPC error. When we are not able to serve
+// the request because we are not the leader, the leader address is
+// returned to the client in a trailer.
+func (s *grpcServer) toGRPC(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if vterrors.Code(err) == vtrpcpb.Code_UNAVAILABLE {
+		if addr := s.node.LeaderAddress(); addr != "" {
+			_ = grpc.SetTrailer(ctx, metadata.Pairs(leaderTrailer, addr))
+		}
+	}
+	return vterrors.ToGRPC(err)
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+
+	toporaftpb "vitess.io/vitess/go/vt/proto/toporaft"
+)
+
+var (
+	leaseTTL = 30 // This is the default used for all non-named locks
+)
+
+// grantLease creates a new lease, and keeps it alive until the returned
+// stop function is called, or until the lease is gone.
+func (s *Server) grantLease(ctx context.Context, ttl int) (int64, func(), error) {
+	resp, err := s.propose(ctx, &toporaftpb.Command{
+		Type:       toporaftpb.Command_GRANT,
+		TtlSeconds: int64(ttl),
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	lease := resp.Lease
+
+	// Refresh the lease three times per TTL.
+	interval := time.Duration(ttl) * time.Second / 3
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-s.running:
+				return
+			case <-ticker.C:
+			}
+			kaCtx, kaCancel := context.WithTimeout(context.Background(), interval)
+			err := s.keepAlive(kaCtx, lease)
+			kaCancel()
+			if topo.IsErrType(err, topo.NoNode) {
+				log.Warningf("lease %v is gone, stopping its keep alive", lease)
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return lease, func() { once.Do(func() { close(done) }) }, nil
+}
+
+// revokeLease removes a lease, and all the keys attached to it.
+func (s *Server) revokeLease(ctx context.Context, lease int64) error {
+	_, err := s.propose(ctx, &toporaftpb.Command{
+		Type:  toporaftpb.Command_REVOKE,
+		Lease: lease,
+	})
+	return err
+}
+
+// waitOnLastRev waits on the file in the provided directory that has
+// the highest revision smaller than the provided revision.
+// It returns true only if there is no more other older files.
+func (s *Server) waitOnLastRev(ctx context.Context, nodePath string, revision int64) (bool, error) {
+	// Get the key that is blocking us, if any.
+	resp, err := s.rangeKeys(ctx, nodePath+"/", true)
+	if err != nil {
+		return false, err
+	}
+	var blocking *toporaftpb.KeyValue
+	for _, kv := range resp.Kvs {
+		if kv.ModRevision < revision && (blocking == nil || kv.ModRevision > blocking.ModRevision) {
+			blocking = kv
+		}
+	}
+	if blocking == nil {
+		// No older key, we're done waiting.
+		return true, nil
+	}
+
+	// Wait for release on blocking key. Cancel the watch when we
+	// exit this function.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for res := range s.watchEvents(ctx, blocking.Key, false, resp.Revision+1) {
+		if res.err != nil {
+			return false, res.err
+		}
+		for _, ev := range res.resp.Events {
+			if ev.Type == toporaftpb.Event_DELETE {
+				// There might still be older keys,
+				// but not this one.
+				return false, nil
+			}
+		}
+	}
+
+	// The watch stopped, we're not sure if there are more items.
+	if err := ctx.Err(); err != nil {
+		return false, convertError(err, nodePath)
+	}
+	return false, nil
+}
+
+// raftLockDescriptor implements topo.LockDescriptor.
+type raftLockDescriptor struct {
+	s         *Server
+	lease     int64
+	stopLease func()
+}
+
+// TryLock is part of the topo.Conn interface.
+func (s *Server) TryLock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list all the entries under dirPath
+	entries, err := s.ListDir(ctx, dirPath, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// If there is a folder '/locks' with some entries in it then we can assume that someone else already has a lock.
+	// Throw error in this case
+	for _, e := range entries {
+		if e.Name == locksPath && e.Type == topo.TypeDirectory && e.Ephemeral {
+			return nil, topo.NewError(topo.NodeExists, fmt.Sprintf("lock already exists at path %s", dirPath))
+		}
+	}
+
+	// everything is good let's acquire the lock.
+	return s.lock(ctx, dirPath, contents, leaseTTL)
+}
+
+// Lock is part of the topo.Conn interface.
+func (s *Server) Lock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		return nil, err
+	}
+
+	return s.lock(ctx, dirPath, contents, leaseTTL)
+}
+
+// LockWithTTL is part of the topo.Conn interface.
+func (s *Server) LockWithTTL(ctx context.Context, dirPath, contents string, ttl time.Duration) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		return nil, err
+	}
+
+	return s.lock(ctx, dirPath, contents, int(ttl.Seconds()))
+}
+
+// LockName is part of the topo.Conn interface.
+func (s *Server) LockName(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	return s.lock(ctx, dirPath, contents, int(topo.NamedLockTTL.Seconds()))
+}
+
+// lock is used by both Lock() and primary election.
+func (s *Server) lock(ctx context.Context, nodePath, contents string, ttl int) (topo.LockDescriptor, error) {
+	nodePath = path.Join(s.root, nodePath, locksPath)
+	if ttl <= 0 {
+		ttl = 1
+	}
+
+	// Get a lease, keep it alive.
+	lease, stopLease, err := s.grantLease(ctx, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create an ephemeral node in the locks directory. Use the lease
+	// ID as the file name, so it's guaranteed unique.
+	key := fmt.Sprintf("%v/%v", nodePath, lease)
+	resp, err := s.propose(ctx, &toporaftpb.Command{
+		Type:   toporaftpb.Command_PUT,
+		Key:    key,
+		Value:  []byte(contents),
+		Create: true,
+		Lease:  lease,
+	})
+	if err != nil {
+		// We don't know if the creation succeeded, revoke the
+		// lease so we don't leave an orphan node behind.
+		stopLease()
+		if rerr := s.revokeLease(context.Background(), lease); rerr != nil {
+			log.Warningf("Revoke(%d) failed, may have left %v behind: %v", lease, key, rerr)
+		}
+		return nil, err
+	}
+
+	// Wait until all older nodes in the locks directory are gone.
+	for {
+		done, err := s.waitOnLastRev(ctx, nodePath, resp.Revision)
+		if err != nil {
+			// We had an error waiting on the last node.
+			// Revoke our lease, this will delete the file.
+			stopLease()
+			if rerr := s.revokeLease(context.Background(), lease); rerr != nil {
+				log.Warningf("Revoke(%d) failed, may have left %v behind: %v", lease, key, rerr)
+			}
+			return nil, err
+		}
+		if done {
+			// No more older nodes, we're it!
+			return &raftLockDescriptor{
+				s:         s,
+				lease:     lease,
+				stopLease: stopLease,
+			}, nil
+		}
+	}
+}
+
+// Check is part of the topo.LockDescriptor interface.
+// We refresh the lease to make sure it is still active and well.
+func (ld *raftLockDescriptor) Check(ctx context.Context) error {
+	return ld.s.keepAlive(ctx, ld.lease)
+}
+
+// Unlock is part of the topo.LockDescriptor interface.
+func (ld *raftLockDescriptor) Unlock(ctx context.Context) error {
+	ld.stopLease()
+	return ld.s.revokeLease(ctx, ld.lease)
+}
@@ -0,0 +1,1059 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	toporaftpb "vitess.io/vitess/go/vt/proto/toporaft"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// maxAppendEntries is the maximum number of entries sent in a
+	// single AppendEntries call.
+	maxAppendEntries = 512
+
+	// snapshotTimeout is the timeout of an InstallSnapshot call.
+	snapshotTimeout = 30 * time.Second
+
+	// revokeRetryInterval is the delay before trying again to revoke
+	// an expired lease.
+	revokeRetryInterval = 5 * time.Second
+)
+
+// Config is the configuration of a Node.
+type Config struct {
+	// ID is the id of this node. It must be a key of Peers.
+	ID string
+
+	// Peers maps the id of every member of the group, including this
+	// node, to its gRPC address.
+	Peers map[string]string
+
+	// DataDir is the directory the node persists its state in.
+	DataDir string
+
+	// ElectionTimeout is the minimum time without hearing from a
+	// leader before a follower starts an election. The actual timeout
+	// is randomized between ElectionTimeout and twice its value.
+	ElectionTimeout time.Duration
+
+	// HeartbeatInterval is the interval at which the leader sends
+	// heartbeats to the followers. It must be much smaller than
+	// ElectionTimeout.
+	HeartbeatInterval time.Duration
+
+	// SnapshotThreshold is the number of applied entries after which
+	// the log is compacted into a snapshot.
+	SnapshotThreshold uint64
+
+	// TLS is the certificate the node serves its gRPC service with and
+	// connects to its peers with.
+	TLS TLSConfig
+}
+
+type role int
+
+const (
+	follower role = iota
+	candidate
+	leader
+)
+
+func (r role) String() string {
+	switch r {
+	case follower:
+		return "follower"
+	case candidate:
+		return "candidate"
+	case leader:
+		return "leader"
+	}
+	return fmt.Sprintf("role(%d)", int(r))
+}
+
+// proposal is a command appended by this node as a leader, waiting to
+// be applied.
+type proposal struct {
+	term   uint64
+	result chan applyResult
+}
+
+// peer is another member of the group.
+type peer struct {
+	id   string
+	addr string
+	cc   *grpc.ClientConn
+	cli  toporaftpb.TopoRaftClient
+
+	// kick is signaled when there is something to replicate to the peer.
+	kick chan struct{}
+}
+
+// Node is a member of a rafttopo consensus group. It replicates a log
+// of commands with the Raft algorithm, and applies the committed ones to
+// its key space. Use NewServer (or topo.OpenServer with the "raft"
+// implementation) to access the key space as a topo.Conn.
+type Node struct {
+	cfg   Config
+	peers []*peer
+
+	// applyMu serializes applying entries, installing snapshots and
+	// compacting the log. It is acquired before mu.
+	applyMu sync.Mutex
+
+	// mu protects all the fields below.
+	mu               sync.Mutex
+	role             role
+	term             uint64
+	votedFor         string
+	leaderID         string
+	electionDeadline time.Time
+	commitIndex      uint64
+
+	// snapshot is the latest snapshot, entries the log that follows it.
+	snapshot *toporaftpb.Snapshot
+	entries  []*toporaftpb.Entry
+
+	// The following fields are only used when we're the leader.
+	nextIndex        map[string]uint64
+	matchIndex       map[string]uint64
+	leaderStartIndex uint64
+	leaseDeadlines   map[int64]time.Time
+	proposals        map[uint64]*proposal
+
+	storage *storage
+	sm      *stateMachine
+
+	applyCh chan struct{}
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewNode creates a Node from its persisted state. Call Start to join
+// the group.
+func NewNode(cfg Config) (*Node, error) {
+	if _, ok := cfg.Peers[cfg.ID]; !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "node id %v is not in the peers list", cfg.ID)
+	}
+	if cfg.ElectionTimeout <= 0 || cfg.HeartbeatInterval <= 0 || cfg.HeartbeatInterval >= cfg.ElectionTimeout {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "heartbeat interval %v must be positive and smaller than election timeout %v", cfg.HeartbeatInterval, cfg.ElectionTimeout)
+	}
+
+	st, hs, snap, entries, err := openStorage(cfg.DataDir)
+	if err != nil {
+		return nil, err
+	}
+	n := &Node{
+		cfg:            cfg,
+		term:           hs.Term,
+		votedFor:       hs.VotedFor,
+		commitIndex:    snap.LastIndex,
+		snapshot:       snap,
+		entries:        entries,
+		nextIndex:      make(map[string]uint64),
+		matchIndex:     make(map[string]uint64),
+		leaseDeadlines: make(map[int64]time.Time),
+		proposals:      make(map[uint64]*proposal),
+		storage:        st,
+		sm:             newStateMachine(),
+		applyCh:        make(chan struct{}, 1),
+		stop:           make(chan struct{}),
+	}
+	n.sm.restore(snap)
+
+	ids := make([]string, 0, len(cfg.Peers))
+	for id := range cfg.Peers {
+		if id != cfg.ID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		n.peers = append(n.peers, &peer{
+			id:   id,
+			addr: cfg.Peers[id],
+			kick: make(chan struct{}, 1),
+		})
+	}
+	return n, nil
+}
+
+// Start connects to the peers, and starts participating in the group.
+func (n *Node) Start() error {
+	creds, err := n.cfg.TLS.dialOption()
+	if err != nil {
+		return err
+	}
+	for _, p := range n.peers {
+		cc, err := grpcclient.DialContext(context.Background(), p.addr, grpcclient.FailFast(true), creds)
+		if err != nil {
+			n.closePeers()
+			return err
+		}
+		p.cc = cc
+		p.cli = toporaftpb.NewTopoRaftClient(cc)
+	}
+
+	n.mu.Lock()
+	n.resetElectionDeadlineLocked()
+	n.mu.Unlock()
+
+	n.wg.Add(2 + len(n.peers))
+	go n.tickLoop()
+	go n.applyLoop()
+	for _, p := range n.peers {
+		go n.replicateLoop(p)
+	}
+	return nil
+}
+
+// Close stops the node. Pending proposals fail.
+func (n *Node) Close() {
+	close(n.stop)
+	n.wg.Wait()
+	n.closePeers()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for index, p := range n.proposals {
+		p.result <- applyResult{err: vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "node %v is shutting down", n.cfg.ID)}
+		delete(n.proposals, index)
+	}
+	if err := n.storage.close(); err != nil {
+		log.Warningf("failed to close raft storage of node %v: %v", n.cfg.ID, err)
+	}
+}
+
+func (n *Node) closePeers() {
+	for _, p := range n.peers {
+		if p.cc != nil {
+			p.cc.Close()
+			p.cc = nil
+		}
+	}
+}
+
+// ID returns the id of the node.
+func (n *Node) ID() string {
+	return n.cfg.ID
+}
+
+// Status returns the current state of the node.
+func (n *Node) Status() *toporaftpb.StatusResponse {
+	n.mu.Lock()
+	resp := &toporaftpb.StatusResponse{
+		Id:          n.cfg.ID,
+		LeaderId:    n.leaderID,
+		Term:        n.term,
+		CommitIndex: n.commitIndex,
+	}
+	n.mu.Unlock()
+	resp.AppliedIndex, resp.Revision = n.sm.status()
+	return resp
+}
+
+// LeaderAddress returns the address of the current leader, if known.
+func (n *Node) LeaderAddress() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.cfg.Peers[n.leaderID]
+}
+
+//
+// Log helpers. They all require mu to be held.
+//
+
+func (n *Node) lastIndexLocked() uint64 {
+	if len(n.entries) > 0 {
+		return n.entries[len(n.entries)-1].Index
+	}
+	return n.snapshot.LastIndex
+}
+
+func (n *Node) lastTermLocked() uint64 {
+	if len(n.entries) > 0 {
+		return n.entries[len(n.entries)-1].Term
+	}
+	return n.snapshot.LastTerm
+}
+
+// termAtLocked returns the term of the entry at index, and false if the
+// entry is not in the log anymore (or not yet).
+func (n *Node) termAtLocked(index uint64) (uint64, bool) {
+	if index == n.snapshot.LastIndex {
+		return n.snapshot.LastTerm, true
+	}
+	if e := n.entryLocked(index); e != nil {
+		return e.Term, true
+	}
+	return 0, false
+}
+
+func (n *Node) entryLocked(index uint64) *toporaftpb.Entry {
+	if index <= n.snapshot.LastIndex || index > n.lastIndexLocked() {
+		return nil
+	}
+	return n.entries[index-n.snapshot.LastIndex-1]
+}
+
+func (n *Node) majority() int {
+	return (len(n.peers)+1)/2 + 1
+}
+
+// appendLocked appends entries to the log, and persists them.
+func (n *Node) appendLocked(entries ...*toporaftpb.Entry) error {
+	if err := n.storage.appendEntries(entries); err != nil {
+		return err
+	}
+	n.entries = append(n.entries, entries...)
+	return nil
+}
+
+// truncateLocked removes the entries from index on.
+func (n *Node) truncateLocked(index uint64) error {
+	entries := append([]*toporaftpb.Entry(nil), n.entries[:index-n.snapshot.LastIndex-1]...)
+	if err := n.storage.rewriteLog(entries); err != nil {
+		return err
+	}
+	n.entries = entries
+	return nil
+}
+
+func (n *Node) setHardStateLocked(term uint64, votedFor string) error {
+	if err := n.storage.saveHardState(&toporaftpb.HardState{Term: term, VotedFor: votedFor}); err != nil {
+		return err
+	}
+	n.term = term
+	n.votedFor = votedFor
+	return nil
+}
+
+func (n *Node) resetElectionDeadlineLocked() {
+	timeout := n.cfg.ElectionTimeout + rand.N(n.cfg.ElectionTimeout)
+	n.electionDeadline = time.Now().Add(timeout)
+}
+
+// becomeFollowerLocked steps down to follower, and moves to term if it
+// is newer than ours.
+func (n *Node) becomeFollowerLocked(term uint64) error {
+	if term > n.term {
+		if err := n.setHardStateLocked(term, ""); err != nil {
+			return err
+		}
+		n.leaderID = ""
+	}
+	if n.role != follower {
+		log.Infof("raft node %v: becoming follower in term %v", n.cfg.ID, n.term)
+		n.role = follower
+		n.resetElectionDeadlineLocked()
+	}
+	return nil
+}
+
+func (n *Node) kickPeersLocked() {
+	for _, p := range n.peers {
+		select {
+		case p.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (n *Node) kickApplyLocked() {
+	select {
+	case n.applyCh <- struct{}{}:
+	default:
+	}
+}
+
+func (n *Node) notLeaderErrorLocked() error {
+	if n.leaderID == "" {
+		return vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "raft node %v is not the leader, and no leader is known", n.cfg.ID)
+	}
+	return vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "raft node %v is not the leader, %v is", n.cfg.ID, n.leaderID)
+}
+
+//
+// Leader election.
+//
+
+// tickLoop starts elections when the leader is gone, and sends
+// heartbeats and expires leases when we are the leader.
+func (n *Node) tickLoop() {
+	defer n.wg.Done()
+	ticker := time.NewTicker(n.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-n.stop:
+			return
+		case <-ticker.C:
+		}
+
+		var expired []int64
+		n.mu.Lock()
+		now := time.Now()
+		switch {
+		case n.role == leader:
+			n.kickPeersLocked()
+			for id, deadline := range n.leaseDeadlines {
+				if now.After(deadline) {
+					expired = append(expired, id)
+					n.leaseDeadlines[id] = now.Add(revokeRetryInterval)
+				}
+			}
+		case now.After(n.electionDeadline):
+			n.startElectionLocked()
+		}
+		n.mu.Unlock()
+
+		for _, id := range expired {
+			n.wg.Add(1)
+			go func(id int64) {
+				defer n.wg.Done()
+				ctx, cancel := context.WithTimeout(context.Background(), revokeRetryInterval)
+				defer cancel()
+				if _, err := n.Propose(ctx, &toporaftpb.Command{Type: toporaftpb.Command_REVOKE, Lease: id}); err != nil && vterrors.Code(err) != vtrpcpb.Code_NOT_FOUND {
+					log.Warningf("raft node %v: failed to revoke expired lease %v: %v", n.cfg.ID, id, err)
+				}
+			}(id)
+		}
+	}
+}
+
+func (n *Node) startElectionLocked() {
+	n.resetElectionDeadlineLocked()
+	if err := n.setHardStateLocked(n.term+1, n.cfg.ID); err != nil {
+		log.Errorf("raft node %v: cannot start an election: %v", n.cfg.ID, err)
+		return
+	}
+	n.role = candidate
+	n.leaderID = ""
+	log.Infof("raft node %v: starting election for term %v", n.cfg.ID, n.term)
+
+	votes := 1
+	if votes >= n.majority() {
+		n.becomeLeaderLocked()
+		return
+	}
+
+	req := &toporaftpb.RequestVoteRequest{
+		Term:         n.term,
+		CandidateId:  n.cfg.ID,
+		LastLogIndex: n.lastIndexLocked(),
+		LastLogTerm:  n.lastTermLocked(),
+	}
+	for _, p := range n.peers {
+		n.wg.Add(1)
+		go func(p *peer) {
+			defer n.wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), n.cfg.ElectionTimeout)
+			defer cancel()
+			resp, err := p.cli.RequestVote(ctx, req)
+			if err != nil {
+				return
+			}
+
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			if resp.Term > n.term {
+				if err := n.becomeFollowerLocked(resp.Term); err != nil {
+					log.Errorf("raft node %v: %v", n.cfg.ID, err)
+				}
+				return
+			}
+			if n.role != candidate || n.term != req.Term || !resp.VoteGranted {
+				return
+			}
+			votes++
+			if votes >= n.majority() {
+				n.becomeLeaderLocked()
+			}
+		}(p)
+	}
+}
+
+func (n *Node) becomeLeaderLocked() {
+	log.Infof("raft node %v: becoming leader in term %v", n.cfg.ID, n.term)
+	n.role = leader
+	n.leaderID = n.cfg.ID
+	for _, p := range n.peers {
+		n.nextIndex[p.id] = n.lastIndexLocked() + 1
+		n.matchIndex[p.id] = 0
+	}
+
+	// Commit a no-op entry in our term, which also commits all the
+	// entries from previous terms.
+	noop := &toporaftpb.Entry{
+		Term:    n.term,
+		Index:   n.lastIndexLocked() + 1,
+		Command: &toporaftpb.Command{Type: toporaftpb.Command_NOOP},
+	}
+	if err := n.appendLocked(noop); err != nil {
+		log.Errorf("raft node %v: cannot append to the log, stepping down: %v", n.cfg.ID, err)
+		n.role = follower
+		n.leaderID = ""
+		return
+	}
+	n.leaderStartIndex = noop.Index
+
+	// The previous leader tracked the lease deadlines, give all the
+	// leases a full TTL.
+	now := time.Now()
+	n.leaseDeadlines = make(map[int64]time.Time)
+	for id, ttl := range n.sm.leaseTTLs() {
+		n.leaseDeadlines[id] = now.Add(time.Duration(ttl) * time.Second)
+	}
+
+	n.advanceCommitLocked()
+	n.kickPeersLocked()
+}
+
+// RequestVote handles a vote request from a candidate.
+func (n *Node) RequestVote(req *toporaftpb.RequestVoteRequest) (*toporaftpb.RequestVoteResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if req.Term > n.term {
+		if err := n.becomeFollowerLocked(req.Term); err != nil {
+			return nil, err
+		}
+	}
+	resp := &toporaftpb.RequestVoteResponse{Term: n.term}
+	if req.Term < n.term {
+		return resp, nil
+	}
+
+	upToDate := req.LastLogTerm > n.lastTermLocked() ||
+		(req.LastLogTerm == n.lastTermLocked() && req.LastLogIndex >= n.lastIndexLocked())
+	if (n.votedFor == "" || n.votedFor == req.CandidateId) && upToDate {
+		if err := n.setHardStateLocked(n.term, req.CandidateId); err != nil {
+			return nil, err
+		}
+		n.resetElectionDeadlineLocked()
+		resp.VoteGranted = true
+	}
+	return resp, nil
+}
+
+//
+// Log replication.
+//
+
+// AppendEntries handles entries or a heartbeat from the leader.
+func (n *Node) AppendEntries(req *toporaftpb.AppendEntriesRequest) (*toporaftpb.AppendEntriesResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if req.Term < n.term {
+		return &toporaftpb.AppendEntriesResponse{Term: n.term}, nil
+	}
+	if req.Term > n.term || n.role != follower {
+		if err := n.becomeFollowerLocked(req.Term); err != nil {
+			return nil, err
+		}
+	}
+	n.leaderID = req.LeaderId
+	n.resetElectionDeadlineLocked()
+	resp := &toporaftpb.AppendEntriesResponse{Term: n.term}
+
+	// Entries up to the snapshot are committed, so they match the
+	// leader's log.
+	if req.PrevLogIndex > n.lastIndexLocked() {
+		resp.ConflictIndex = n.lastIndexLocked() + 1
+		return resp, nil
+	}
+	if req.PrevLogIndex >= n.snapshot.LastIndex {
+		if term, _ := n.termAtLocked(req.PrevLogIndex); term != req.PrevLogTerm {
+			// Skip all the entries of the conflicting term.
+			index := req.PrevLogIndex
+			for index-1 > n.snapshot.LastIndex {
+				if t, _ := n.termAtLocked(index - 1); t != term {
+					break
+				}
+				index--
+			}
+			resp.ConflictIndex = index
+			return resp, nil
+		}
+	}
+
+	for i, e := range req.Entries {
+		if e.Index <= n.snapshot.LastIndex {
+			continue
+		}
+		if e.Index <= n.lastIndexLocked() {
+			if term, _ := n.termAtLocked(e.Index); term == e.Term {
+				continue
+			}
+			if err := n.truncateLocked(e.Index); err != nil {
+				return nil, err
+			}
+		}
+		if err := n.appendLocked(req.Entries[i:]...); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	lastNew := req.PrevLogIndex + uint64(len(req.Entries))
+	if req.LeaderCommit > n.commitIndex && lastNew > n.commitIndex {
+		n.commitIndex = min(req.LeaderCommit, lastNew)
+		n.kickApplyLocked()
+	}
+	resp.Success = true
+	resp.MatchIndex = lastNew
+	return resp, nil
+}
+
+// InstallSnapshot handles a snapshot sent by the leader to a follower
+// that is too far behind.
+func (n *Node) InstallSnapshot(req *toporaftpb.InstallSnapshotRequest) (*toporaftpb.InstallSnapshotResponse, error) {
+	n.applyMu.Lock()
+	defer n.applyMu.Unlock()
+	n.mu.Lock()
+
+	if req.Term < n.term {
+		defer n.mu.Unlock()
+		return &toporaftpb.InstallSnapshotResponse{Term: n.term}, nil
+	}
+	if req.Term > n.term || n.role != follower {
+		if err := n.becomeFollowerLocked(req.Term); err != nil {
+			n.mu.Unlock()
+			return nil, err
+		}
+	}
+	n.leaderID = req.LeaderId
+	n.resetElectionDeadlineLocked()
+	resp := &toporaftpb.InstallSnapshotResponse{Term: n.term}
+
+	snap := req.Snapshot
+	if applied, _ := n.sm.status(); snap == nil || snap.LastIndex <= applied {
+		n.mu.Unlock()
+		return resp, nil
+	}
+
+	// Keep the entries that follow the snapshot if they match it.
+	var entries []*toporaftpb.Entry
+	if term, ok := n.termAtLocked(snap.LastIndex); ok && term == snap.LastTerm && snap.LastIndex > n.snapshot.LastIndex {
+		entries = append(entries, n.entries[snap.LastIndex-n.snapshot.LastIndex:]...)
+	}
+	if err := n.storage.saveSnapshot(snap, entries); err != nil {
+		n.mu.Unlock()
+		return nil, err
+	}
+	n.snapshot = snap
+	n.entries = entries
+	n.commitIndex = max(n.commitIndex, snap.LastIndex)
+	n.mu.Unlock()
+
+	log.Infof("raft node %v: installing snapshot at index %v", n.cfg.ID, snap.LastIndex)
+	n.sm.restore(snap)
+	return resp, nil
+}
+
+// replicateLoop sends entries and heartbeats to a peer while we are
+// the leader.
+func (n *Node) replicateLoop(p *peer) {
+	defer n.wg.Done()
+	for {
+		select {
+		case <-n.stop:
+			return
+		case <-p.kick:
+		}
+		for n.replicateOnce(p) {
+			select {
+			case <-n.stop:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// replicateOnce sends one AppendEntries or InstallSnapshot to the peer.
+// It returns true if there is more to send right away.
+func (n *Node) replicateOnce(p *peer) bool {
+	n.mu.Lock()
+	if n.role != leader {
+		n.mu.Unlock()
+		return false
+	}
+	term := n.term
+	next := n.nextIndex[p.id]
+
+	if next <= n.snapshot.LastIndex {
+		snap := n.snapshot
+		n.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+		defer cancel()
+		resp, err := p.cli.InstallSnapshot(ctx, &toporaftpb.InstallSnapshotRequest{
+			Term:     term,
+			LeaderId: n.cfg.ID,
+			Snapshot: snap,
+		})
+		if err != nil {
+			return false
+		}
+
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		if !n.checkLeaderResponseLocked(term, resp.Term) {
+			return false
+		}
+		n.matchIndex[p.id] = max(n.matchIndex[p.id], snap.LastIndex)
+		n.nextIndex[p.id] = max(n.nextIndex[p.id], snap.LastIndex+1)
+		n.advanceCommitLocked()
+		return true
+	}
+
+	prevTerm, _ := n.termAtLocked(next - 1)
+	req := &toporaftpb.AppendEntriesRequest{
+		Term:         term,
+		LeaderId:     n.cfg.ID,
+		PrevLogIndex: next - 1,
+		PrevLogTerm:  prevTerm,
+		LeaderCommit: n.commitIndex,
+	}
+	if next <= n.lastIndexLocked() {
+		entries := n.entries[next-n.snapshot.LastIndex-1:]
+		if len(entries) > maxAppendEntries {
+			entries = entries[:maxAppendEntries]
+		}
+		req.Entries = append(req.Entries, entries...)
+	}
+	n.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.cfg.ElectionTimeout)
+	defer cancel()
+	resp, err := p.cli.AppendEntries(ctx, req)
+	if err != nil {
+		return false
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !n.checkLeaderResponseLocked(term, resp.Term) {
+		return false
+	}
+	if resp.Success {
+		if resp.MatchIndex > n.matchIndex[p.id] {
+			n.matchIndex[p.id] = resp.MatchIndex
+			n.advanceCommitLocked()
+		}
+		n.nextIndex[p.id] = max(n.nextIndex[p.id], n.matchIndex[p.id]+1)
+		return n.nextIndex[p.id] <= n.lastIndexLocked()
+	}
+
+	// The peer's log doesn't match ours at next-1, go back.
+	next = min(resp.ConflictIndex, next-1)
+	if resp.ConflictIndex == 0 {
+		next = req.PrevLogIndex
+	}
+	n.nextIndex[p.id] = max(next, n.matchIndex[p.id]+1, 1)
+	return true
+}
+
+// checkLeaderResponseLocked processes the term of a response to a
+// request we sent as the leader of term. It returns true if we are
+// still that leader.
+func (n *Node) checkLeaderResponseLocked(term, respTerm uint64) bool {
+	if respTerm > n.term {
+		if err := n.becomeFollowerLocked(respTerm); err != nil {
+			log.Errorf("raft node %v: %v", n.cfg.ID, err)
+		}
+		return false
+	}
+	return n.role == leader && n.term == term
+}
+
+// advanceCommitLocked commits the entries of our term that are
+// replicated on a majority of the group.
+func (n *Node) advanceCommitLocked() {
+	if n.role != leader {
+		return
+	}
+	for index := n.lastIndexLocked(); index > n.commitIndex; index-- {
+		if term, _ := n.termAtLocked(index); term != n.term {
+			// Entries from previous terms are only committed
+			// indirectly.
+			return
+		}
+		count := 1
+		for _, p := range n.peers {
+			if n.matchIndex[p.id] >= index {
+				count++
+			}
+		}
+		if count >= n.majority() {
+			n.commitIndex = index
+			n.kickApplyLocked()
+			return
+		}
+	}
+}
+
+//
+// State machine.
+//
+
+func (n *Node) applyLoop() {
+	defer n.wg.Done()
+	for {
+		select {
+		case <-n.stop:
+			return
+		case <-n.applyCh:
+		}
+		n.applyCommitted()
+	}
+}
+
+// applyCommitted applies the committed entries, completes the matching
+// proposals, and compacts the log if needed.
+func (n *Node) applyCommitted() {
+	n.applyMu.Lock()
+	defer n.applyMu.Unlock()
+
+	n.mu.Lock()
+	applied, _ := n.sm.status()
+	var entries []*toporaftpb.Entry
+	for index := applied + 1; index <= n.commitIndex; index++ {
+		e := n.entryLocked(index)
+		if e == nil {
+			break
+		}
+		entries = append(entries, e)
+	}
+	n.mu.Unlock()
+
+	for _, e := range entries {
+		res := n.sm.apply(e)
+
+		n.mu.Lock()
+		if res.err == nil && e.Command != nil {
+			switch e.Command.Type {
+			case toporaftpb.Command_GRANT:
+				n.leaseDeadlines[res.lease] = time.Now().Add(time.Duration(e.Command.TtlSeconds) * time.Second)
+			case toporaftpb.Command_REVOKE:
+				delete(n.leaseDeadlines, e.Command.Lease)
+			}
+		}
+		if p, ok := n.proposals[e.Index]; ok {
+			delete(n.proposals, e.Index)
+			if p.term != e.Term {
+				res = applyResult{err: vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "proposal was dropped by a leader change")}
+			}
+			p.result <- res
+		}
+		n.mu.Unlock()
+	}
+
+	n.maybeSnapshot()
+}
+
+// maybeSnapshot compacts the log into a snapshot when enough entries
+// have been applied since the last one. applyMu must be held.
+func (n *Node) maybeSnapshot() {
+	applied, _ := n.sm.status()
+	n.mu.Lock()
+	threshold := n.snapshot.LastIndex + n.cfg.SnapshotThreshold
+	n.mu.Unlock()
+	if n.cfg.SnapshotThreshold == 0 || applied < threshold {
+		return
+	}
+
+	snap := n.sm.snapshot()
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	entries := append([]*toporaftpb.Entry(nil), n.entries[snap.LastIndex-n.snapshot.LastIndex:]...)
+	if err := n.storage.saveSnapshot(snap, entries); err != nil {
+		log.Errorf("raft node %v: failed to save snapshot: %v", n.cfg.ID, err)
+		return
+	}
+	n.snapshot = snap
+	n.entries = entries
+}
+
+//
+// Client requests.
+//
+
+// Propose replicates cmd, and waits until it is applied. Only the
+// leader accepts proposals.
+func (n *Node) Propose(ctx context.Context, cmd *toporaftpb.Command) (*toporaftpb.ProposeResponse, error) {
+	n.mu.Lock()
+	if n.role != leader {
+		err := n.notLeaderErrorLocked()
+		n.mu.Unlock()
+		return nil, err
+	}
+	e := &toporaftpb.Entry{
+		Term:    n.term,
+		Index:   n.lastIndexLocked() + 1,
+		Command: cmd,
+	}
+	if err := n.appendLocked(e); err != nil {
+		n.mu.Unlock()
+		return nil, err
+	}
+	p := &proposal{
+		term:   e.Term,
+		result: make(chan applyResult, 1),
+	}
+	n.proposals[e.Index] = p
+	n.advanceCommitLocked()
+	n.kickPeersLocked()
+	n.mu.Unlock()
+
+	select {
+	case res := <-p.result:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return &toporaftpb.ProposeResponse{Revision: res.revision, Lease: res.lease}, nil
+	case <-ctx.Done():
+		n.mu.Lock()
+		if n.proposals[e.Index] == p {
+			delete(n.proposals, e.Index)
+		}
+		n.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// Range returns the key, or the keys starting with key if prefix is
+// set. The read is linearizable: the leader confirms it is still the
+// leader with a round of heartbeats, and waits for the state machine to
+// catch up with its commit index before reading.
+func (n *Node) Range(ctx context.Context, key string, prefix bool) (*toporaftpb.RangeResponse, error) {
+	readIndex, err := n.readIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.sm.waitApplied(ctx, readIndex); err != nil {
+		return nil, err
+	}
+	return n.sm.rangeKeys(key, prefix), nil
+}
+
+// readIndex returns the commit index once a majority of the group has
+// acknowledged we are still the leader.
+func (n *Node) readIndex(ctx context.Context) (uint64, error) {
+	n.mu.Lock()
+	if n.role != leader {
+		err := n.notLeaderErrorLocked()
+		n.mu.Unlock()
+		return 0, err
+	}
+	term := n.term
+	// Until the no-op of our term is committed, our commit index may be
+	// behind the previous leader's.
+	readIndex := max(n.commitIndex, n.leaderStartIndex)
+	reqs := make([]*toporaftpb.AppendEntriesRequest, len(n.peers))
+	for i, p := range n.peers {
+		prevIndex := n.nextIndex[p.id] - 1
+		prevTerm, ok := n.termAtLocked(prevIndex)
+		if !ok {
+			prevIndex, prevTerm = n.snapshot.LastIndex, n.snapshot.LastTerm
+		}
+		reqs[i] = &toporaftpb.AppendEntriesRequest{
+			Term:         term,
+			LeaderId:     n.cfg.ID,
+			PrevLogIndex: prevIndex,
+			PrevLogTerm:  prevTerm,
+			LeaderCommit: min(n.commitIndex, prevIndex),
+		}
+	}
+	n.mu.Unlock()
+
+	if len(n.peers) == 0 {
+		return readIndex, nil
+	}
+
+	acks := make(chan bool, len(n.peers))
+	hbCtx, cancel := context.WithTimeout(ctx, n.cfg.ElectionTimeout)
+	defer cancel()
+	for i, p := range n.peers {
+		go func(p *peer, req *toporaftpb.AppendEntriesRequest) {
+			resp, err := p.cli.AppendEntries(hbCtx, req)
+			if err != nil {
+				acks <- false
+				return
+			}
+			n.mu.Lock()
+			stillLeader := n.checkLeaderResponseLocked(term, resp.Term)
+			n.mu.Unlock()
+			acks <- stillLeader && resp.Term == term
+		}(p, reqs[i])
+	}
+
+	count := 1
+	for range n.peers {
+		if <-acks {
+			count++
+			if count >= n.majority() {
+				return readIndex, nil
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return 0, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "raft node %v could not confirm its leadership", n.cfg.ID)
+}
+
+// KeepAlive refreshes the deadline of a lease. Only the leader tracks
+// lease deadlines.
+func (n *Node) KeepAlive(ctx context.Context, id int64) (int64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.role != leader {
+		return 0, n.notLeaderErrorLocked()
+	}
+	ttl, ok := n.sm.hasLease(id)
+	if !ok {
+		return 0, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "lease %v not found", id)
+	}
+	n.leaseDeadlines[id] = time.Now().Add(time.Duration(ttl) * time.Second)
+	return ttl, nil
+}
+
+// watch registers a watcher on the local state machine.
+func (n *Node) watch(key string, prefix bool, startRevision int64) (*watcher, []*toporaftpb.WatchResponse, error) {
+	return n.sm.watch(key, prefix, startRevision)
+}
+
+// unwatch removes a watcher registered with watch.
+func (n *Node) unwatch(w *watcher) {
+	n.sm.unwatch(w)
+}
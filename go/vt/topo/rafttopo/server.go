@@ -0,0 +1,261 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package rafttopo implements topo.Server with an embedded Raft consensus
+group as the backend, so no external coordination service is needed.
+
+A small, odd number of processes (usually the vtctlds) each run a Node.
+The nodes replicate a log of key space mutations with the Raft algorithm,
+persist it in a local data directory, and serve the TopoRaft gRPC service.
+Clients connect to any subset of the nodes: mutations and reads are sent
+to the leader, following the leader hints returned by the other nodes.
+
+The key space follows the etcd data model, so this package mirrors
+etcd2topo:
+
+  - Every mutation increases the revision of the key space. The revision
+    at which a key was last modified is its version.
+  - Keys can be attached to a lease. The leader revokes leases that are
+    not kept alive, which deletes the keys attached to them. Locks and
+    elections use leases.
+  - Watches can start at a past revision, as long as it is still in the
+    watch history of the node.
+  - Transactions are a single command, which changes all its keys at
+    the same revision, or none of them if one of its conditions fails.
+
+We follow these conventions within this package:
+
+  - Call convertError(err) on any errors returned from the gRPC client.
+    Functions defined in this package can be assumed to have already
+    converted errors as necessary.
+*/
+package rafttopo
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/topo"
+
+	toporaftpb "vitess.io/vitess/go/vt/proto/toporaft"
+)
+
+// retryInterval is the time we wait before trying all the nodes again
+// when none of them can serve a request.
+const retryInterval = 100 * time.Millisecond
+
+// Factory is the rafttopo topo.Factory implementation.
+type Factory struct{}
+
+// HasGlobalReadOnlyCell is part of the topo.Factory interface.
+func (f Factory) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+// Create is part of the topo.Factory interface.
+func (f Factory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	return NewServer(serverAddr, root)
+}
+
+// Server is the implementation of topo.Conn for rafttopo.
+type Server struct {
+	// root is the root path for this client.
+	root string
+	// cluster identifies the nodes the Server was created with, for
+	// TxnBackend: their sorted addresses.
+	cluster string
+
+	running chan struct{}
+
+	mu sync.Mutex
+	// addrs are the addresses of the nodes we know about. Leader hints
+	// pointing to unknown nodes are added to the list.
+	addrs []string
+	// current is the index in addrs of the node we send requests to.
+	current int
+	conns   map[string]*grpc.ClientConn
+	// creds are the transport credentials to connect to the nodes with.
+	creds grpc.DialOption
+}
+
+func init() {
+	topo.RegisterFactory("raft", Factory{})
+}
+
+// NewServer returns a new rafttopo.Server. serverAddr is a comma
+// separated list of node addresses. The connections to the nodes use the
+// TLS configuration of the flags.
+func NewServer(serverAddr, root string) (*Server, error) {
+	return NewServerWithTLS(serverAddr, root, flagsTLSConfig())
+}
+
+// NewServerWithTLS returns a new rafttopo.Server that connects to the
+// nodes with the given TLS configuration.
+func NewServerWithTLS(serverAddr, root string, tlsConfig TLSConfig) (*Server, error) {
+	var addrs []string
+	for _, addr := range strings.Split(serverAddr, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, topo.NewError(topo.NoImplementation, "no rafttopo server address")
+	}
+	creds, err := tlsConfig.dialOption()
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		root:    root,
+		cluster: strings.Join(slices.Sorted(slices.Values(addrs)), ","),
+		running: make(chan struct{}),
+		addrs:   addrs,
+		conns:   make(map[string]*grpc.ClientConn),
+		creds:   creds,
+	}, nil
+}
+
+// Close implements topo.Server.Close.
+func (s *Server) Close() {
+	close(s.running)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for addr, cc := range s.conns {
+		cc.Close()
+		delete(s.conns, addr)
+	}
+}
+
+// client returns the address of the node we currently talk to, and a
+// client for it.
+func (s *Server) client() (string, toporaftpb.TopoRaftClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	addr := s.addrs[s.current]
+	cc, ok := s.conns[addr]
+	if !ok {
+		var err error
+		cc, err = grpcclient.DialContext(context.Background(), addr, grpcclient.FailFast(true), s.creds)
+		if err != nil {
+			return "", nil, err
+		}
+		s.conns[addr] = cc
+	}
+	return addr, toporaftpb.NewTopoRaftClient(cc), nil
+}
+
+// nodeFailed moves on from addr, to the leader if we got a hint, or to
+// the next node otherwise.
+func (s *Server) nodeFailed(addr, leaderHint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.addrs[s.current] != addr {
+		// Someone already moved on.
+		return
+	}
+	if leaderHint != "" && leaderHint != addr {
+		for i, a := range s.addrs {
+			if a == leaderHint {
+				s.current = i
+				return
+			}
+		}
+		s.addrs = append(s.addrs, leaderHint)
+		s.current = len(s.addrs) - 1
+		return
+	}
+	s.current = (s.current + 1) % len(s.addrs)
+}
+
+// call runs f against the leader. It follows the leader hints, and tries
+// all the nodes until ctx is done. Errors are not converted.
+func (s *Server) call(ctx context.Context, f func(cli toporaftpb.TopoRaftClient, opts ...grpc.CallOption) error) error {
+	for attempt := 1; ; attempt++ {
+		addr, cli, err := s.client()
+		if err != nil {
+			return err
+		}
+		var trailer metadata.MD
+		err = f(cli, grpc.Trailer(&trailer))
+		if status.Code(err) != codes.Unavailable {
+			return err
+		}
+
+		var hint string
+		if values := trailer.Get(leaderTrailer); len(values) > 0 {
+			hint = values[0]
+		}
+		s.nodeFailed(addr, hint)
+
+		s.mu.Lock()
+		nodes := len(s.addrs)
+		s.mu.Unlock()
+		if attempt%nodes != 0 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-s.running:
+			return err
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// rangeKeys reads key, or all the keys starting with key if prefix is
+// set, from the leader.
+func (s *Server) rangeKeys(ctx context.Context, key string, prefix bool) (*toporaftpb.RangeResponse, error) {
+	var resp *toporaftpb.RangeResponse
+	err := s.call(ctx, func(cli toporaftpb.TopoRaftClient, opts ...grpc.CallOption) error {
+		var err error
+		resp, err = cli.Range(ctx, &toporaftpb.RangeRequest{Key: key, Prefix: prefix}, opts...)
+		return err
+	})
+	return resp, convertError(err, key)
+}
+
+// propose applies cmd through the leader.
+func (s *Server) propose(ctx context.Context, cmd *toporaftpb.Command) (*toporaftpb.ProposeResponse, error) {
+	var resp *toporaftpb.ProposeResponse
+	err := s.call(ctx, func(cli toporaftpb.TopoRaftClient, opts ...grpc.CallOption) error {
+		var err error
+		resp, err = cli.Propose(ctx, &toporaftpb.ProposeRequest{Command: cmd}, opts...)
+		return err
+	})
+	return resp, convertError(err, cmd.Key)
+}
+
+// keepAlive refreshes a lease on the leader.
+func (s *Server) keepAlive(ctx context.Context, lease int64) error {
+	err := s.call(ctx, func(cli toporaftpb.TopoRaftClient, opts ...grpc.CallOption) error {
+		_, err := cli.KeepAlive(ctx, &toporaftpb.KeepAliveRequest{Lease: lease}, opts...)
+		return err
+	})
+	return convertError(err, "lease")
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/tlstest"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/test"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	toporaftpb "vitess.io/vitess/go/vt/proto/toporaft"
+)
+
+// testCluster is a raft topo group running in the test process.
+type testCluster struct {
+	t     *testing.T
+	cfgs  []Config
+	nodes []*Node
+	stops []func()
+}
+
+// startCluster starts a group of size nodes on local ports.
+func startCluster(t *testing.T, size int) *testCluster {
+	return startTLSCluster(t, size, TLSConfig{})
+}
+
+// startTLSCluster starts a group of size nodes on local ports, which serve
+// and connect to each other with the TLS configuration.
+func startTLSCluster(t *testing.T, size int, tlsConfig TLSConfig) *testCluster {
+	peers := make(map[string]string)
+	for i := range size {
+		l, err := net.Listen("tcp", "localhost:0")
+		require.NoError(t, err)
+		peers[fmt.Sprintf("node%d", i)] = l.Addr().String()
+		l.Close()
+	}
+
+	c := &testCluster{
+		t:     t,
+		nodes: make([]*Node, size),
+		stops: make([]func(), size),
+	}
+	for i := range size {
+		c.cfgs = append(c.cfgs, Config{
+			ID:                fmt.Sprintf("node%d", i),
+			Peers:             peers,
+			DataDir:           t.TempDir(),
+			ElectionTimeout:   300 * time.Millisecond,
+			HeartbeatInterval: 30 * time.Millisecond,
+			SnapshotThreshold: 100,
+			TLS:               tlsConfig,
+		})
+		c.start(i)
+	}
+	t.Cleanup(func() {
+		for i := range c.nodes {
+			c.stop(i)
+		}
+	})
+	return c
+}
+
+func (c *testCluster) start(i int) {
+	node, stop, err := StartNode(c.cfgs[i])
+	require.NoError(c.t, err)
+	c.nodes[i] = node
+	c.stops[i] = stop
+}
+
+func (c *testCluster) stop(i int) {
+	if c.stops[i] != nil {
+		c.stops[i]()
+		c.stops[i] = nil
+		c.nodes[i] = nil
+	}
+}
+
+// addrs returns the address list to use in topo.OpenServer.
+func (c *testCluster) addrs() string {
+	var addrs []string
+	for _, cfg := range c.cfgs {
+		addrs = append(addrs, cfg.Peers[cfg.ID])
+	}
+	return strings.Join(addrs, ",")
+}
+
+// waitForLeader waits until one of the running nodes is the leader, and
+// returns its index.
+func (c *testCluster) waitForLeader() int {
+	var leaderIndex int
+	require.Eventually(c.t, func() bool {
+		for i, node := range c.nodes {
+			if node != nil && node.Status().LeaderId == node.ID() {
+				leaderIndex = i
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second, 10*time.Millisecond)
+	return leaderIndex
+}
+
+func TestRaftTopo(t *testing.T) {
+	c := startCluster(t, 3)
+	serverAddr := c.addrs()
+
+	testIndex := 0
+	newServer := func() *topo.Server {
+		// Each test will use its own sub-directories.
+		testRoot := fmt.Sprintf("/test-%v", testIndex)
+		testIndex++
+
+		// Create the server on the new root.
+		ts, err := topo.OpenServer("raft", serverAddr, path.Join(testRoot, topo.GlobalCell))
+		require.NoError(t, err)
+
+		// Create the CellInfo.
+		err = ts.CreateCellInfo(context.Background(), test.LocalCellName, &topodatapb.CellInfo{
+			ServerAddress: serverAddr,
+			Root:          path.Join(testRoot, test.LocalCellName),
+		})
+		require.NoError(t, err)
+		return ts
+	}
+
+	// Run the TopoServerTestSuite tests.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	test.TopoServerTestSuite(t, ctx, func() *topo.Server {
+		return newServer()
+	}, []string{})
+
+	// Run raft-specific tests.
+	ts := newServer()
+	testKeyspaceLock(t, ts)
+
+	// The cells use the same nodes, so their transactions are atomic.
+	atomic, err := ts.TxnAtomic(ctx, []string{topo.GlobalCell, test.LocalCellName})
+	require.NoError(t, err)
+	require.True(t, atomic)
+	ts.Close()
+}
+
+// testKeyspaceLock tests the lease keep alive of locks.
+func testKeyspaceLock(t *testing.T, ts *topo.Server) {
+	ctx := context.Background()
+	keyspacePath := path.Join(topo.KeyspacesPath, "test_keyspace")
+	err := ts.CreateKeyspace(ctx, "test_keyspace", &topodatapb.Keyspace{})
+	require.NoError(t, err)
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+
+	// Short TTL, make sure it doesn't expire.
+	defer func(ttl int) { leaseTTL = ttl }(leaseTTL)
+	leaseTTL = 1
+	lockDescriptor, err := conn.Lock(ctx, keyspacePath, "short ttl")
+	require.NoError(t, err)
+	time.Sleep(2 * time.Second)
+	require.NoError(t, lockDescriptor.Check(ctx))
+	require.NoError(t, lockDescriptor.Unlock(ctx))
+}
+
+// TestRaftTopoLeaderFailover checks the data survives the loss of the
+// leader, and that the locks of a process that stopped keeping its lease
+// alive are released.
+func TestRaftTopoLeaderFailover(t *testing.T) {
+	ctx := context.Background()
+	c := startCluster(t, 3)
+	conn, err := NewServer(c.addrs(), "/failover")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Create(ctx, "/dir/file", []byte("v1"))
+	require.NoError(t, err)
+
+	// Take a lock whose lease is never kept alive.
+	lease, stopLease, err := conn.grantLease(ctx, 1)
+	require.NoError(t, err)
+	stopLease()
+	_, err = conn.propose(ctx, &toporaftpb.Command{
+		Type:  toporaftpb.Command_PUT,
+		Key:   "/failover/dir/locks/orphan",
+		Value: []byte("orphan"),
+		Lease: lease,
+	})
+	require.NoError(t, err)
+
+	wd, changes, err := conn.Watch(ctx, "/dir/file")
+	require.NoError(t, err)
+	require.Equal(t, "v1", string(wd.Contents))
+
+	// Stop the leader, a new one takes over.
+	old := c.waitForLeader()
+	c.stop(old)
+	leader := c.waitForLeader()
+	require.NotEqual(t, old, leader)
+
+	contents, version, err := conn.Get(ctx, "/dir/file")
+	require.NoError(t, err)
+	require.Equal(t, "v1", string(contents))
+	_, err = conn.Update(ctx, "/dir/file", []byte("v2"), version)
+	require.NoError(t, err)
+
+	// The watch survives the failover.
+	select {
+	case wd := <-changes:
+		require.NoError(t, wd.Err)
+		require.Equal(t, "v2", string(wd.Contents))
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the watch notification")
+	}
+
+	// The new leader expires the orphan lease.
+	require.Eventually(t, func() bool {
+		_, _, err := conn.Get(ctx, "/dir/locks/orphan")
+		return topo.IsErrType(err, topo.NoNode)
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// The stopped node catches up when it comes back.
+	c.start(old)
+	require.Eventually(t, func() bool {
+		return c.nodes[old].Status().AppliedIndex >= c.nodes[leader].Status().AppliedIndex
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+// TestRaftTopoRestart checks the data is persisted across restarts of
+// the whole group, including after the log was compacted.
+func TestRaftTopoRestart(t *testing.T) {
+	ctx := context.Background()
+	c := startCluster(t, 3)
+	conn, err := NewServer(c.addrs(), "/restart")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Write more than the snapshot threshold.
+	for i := range 150 {
+		_, err := conn.Update(ctx, fmt.Sprintf("/file%d", i%10), []byte(fmt.Sprintf("v%d", i)), nil)
+		require.NoError(t, err)
+	}
+
+	for i := range c.nodes {
+		c.stop(i)
+	}
+	for i := range c.nodes {
+		c.start(i)
+	}
+
+	for i := range 10 {
+		contents, _, err := conn.Get(ctx, fmt.Sprintf("/file%d", i))
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("v%d", 140+i), string(contents))
+	}
+	entries, err := conn.ListDir(ctx, "/", false)
+	require.NoError(t, err)
+	require.Len(t, entries, 10)
+}
+
+// TestRaftTopoTLS checks the nodes and their clients authenticate each
+// other when TLS is enabled.
+func TestRaftTopoTLS(t *testing.T) {
+	root := t.TempDir()
+	tlstest.CreateCA(root)
+	tlstest.CreateSignedCert(root, tlstest.CA, "01", "node", "localhost")
+	tlsConfig := TLSConfig{
+		CertPath: path.Join(root, "node-cert.pem"),
+		KeyPath:  path.Join(root, "node-key.pem"),
+		CAPath:   path.Join(root, "ca-cert.pem"),
+	}
+	c := startTLSCluster(t, 3, tlsConfig)
+	c.waitForLeader()
+
+	ctx := context.Background()
+	conn, err := NewServerWithTLS(c.addrs(), "/tls", tlsConfig)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Create(ctx, "/file", []byte("contents"))
+	require.NoError(t, err)
+	contents, _, err := conn.Get(ctx, "/file")
+	require.NoError(t, err)
+	require.Equal(t, "contents", string(contents))
+
+	// Clients without TLS, or without a client certificate, are refused.
+	for _, clientConfig := range []TLSConfig{{}, {CAPath: tlsConfig.CAPath}} {
+		conn, err := NewServerWithTLS(c.addrs(), "/tls", clientConfig)
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		_, _, err = conn.Get(ctx, "/file")
+		cancel()
+		require.Error(t, err)
+		conn.Close()
+	}
+}
+
+// TestRaftTopoSnapshotCatchUp checks a node that missed compacted
+// entries catches up from a snapshot.
+func TestRaftTopoSnapshotCatchUp(t *testing.T) {
+	ctx := context.Background()
+	c := startCluster(t, 3)
+	conn, err := NewServer(c.addrs(), "/snapshot")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	leader := c.waitForLeader()
+	lagging := (leader + 1) % 3
+	c.stop(lagging)
+	for i := range 250 {
+		_, err := conn.Update(ctx, fmt.Sprintf("/file%d", i%5), []byte(fmt.Sprintf("v%d", i)), nil)
+		require.NoError(t, err)
+	}
+
+	c.start(lagging)
+	require.Eventually(t, func() bool {
+		status := c.nodes[lagging].Status()
+		return status.AppliedIndex >= c.nodes[leader].Status().AppliedIndex
+	}, 10*time.Second, 10*time.Millisecond)
+	resp := c.nodes[lagging].sm.rangeKeys("/snapshot/file0", false)
+	require.Len(t, resp.Kvs, 1)
+	require.Equal(t, "v245", string(resp.Kvs[0].Value))
+}
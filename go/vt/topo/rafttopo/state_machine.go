@@ -0,0 +1,498 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	toporaftpb "vitess.io/vitess/go/vt/proto/toporaft"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	// maxWatchHistory is the number of events kept in memory for
+	// watches that start at a past revision.
+	maxWatchHistory = 10000
+
+	// watcherBufferSize is the number of responses that can be queued
+	// for a watcher before it is canceled for being too slow.
+	watcherBufferSize = 1024
+)
+
+// applyResult is the outcome of applying a command.
+type applyResult struct {
+	revision int64
+	lease    int64
+	err      error
+}
+
+// leaseState is a lease and the keys attached to it.
+type leaseState struct {
+	ttlSeconds int64
+	keys       map[string]struct{}
+}
+
+// watcher is a registered Watch.
+type watcher struct {
+	key    string
+	prefix bool
+
+	// startRevision is the first revision to send events for. A node
+	// can be behind the revision the client read at, so live events
+	// have to be filtered too.
+	startRevision int64
+
+	// responses receives the events matching the watch. It is never
+	// closed, done is closed instead.
+	responses chan *toporaftpb.WatchResponse
+
+	// done is closed with err set when the watcher is canceled by the
+	// state machine.
+	done chan struct{}
+	err  error
+}
+
+func (w *watcher) matches(key string) bool {
+	if w.prefix {
+		return strings.HasPrefix(key, w.key)
+	}
+	return key == w.key
+}
+
+// stateMachine is the replicated key space. Every node applies the same
+// committed commands in the same order, and ends up with the same keys,
+// leases and revision.
+type stateMachine struct {
+	mu sync.Mutex
+
+	appliedIndex uint64
+	appliedTerm  uint64
+	revision     int64
+	kvs          map[string]*toporaftpb.KeyValue
+	leases       map[int64]*leaseState
+
+	// history holds the most recent events, in revision order.
+	// Events at revisions up to compactRevision are not available.
+	history         []*toporaftpb.Event
+	compactRevision int64
+	watchers        map[*watcher]struct{}
+
+	// applied is closed and replaced every time appliedIndex changes.
+	applied chan struct{}
+}
+
+func newStateMachine() *stateMachine {
+	return &stateMachine{
+		kvs:      make(map[string]*toporaftpb.KeyValue),
+		leases:   make(map[int64]*leaseState),
+		watchers: make(map[*watcher]struct{}),
+		applied:  make(chan struct{}),
+	}
+}
+
+// restore replaces the state machine content with snap. The watch
+// history is lost, so all the current watchers are canceled.
+func (sm *stateMachine) restore(snap *toporaftpb.Snapshot) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.appliedIndex = snap.LastIndex
+	sm.appliedTerm = snap.LastTerm
+	sm.revision = snap.Revision
+	sm.kvs = make(map[string]*toporaftpb.KeyValue, len(snap.Kvs))
+	sm.leases = make(map[int64]*leaseState, len(snap.Leases))
+	for _, l := range snap.Leases {
+		sm.leases[l.Id] = &leaseState{
+			ttlSeconds: l.TtlSeconds,
+			keys:       make(map[string]struct{}),
+		}
+	}
+	for _, kv := range snap.Kvs {
+		kv = kv.CloneVT()
+		sm.kvs[kv.Key] = kv
+		if l, ok := sm.leases[kv.Lease]; ok {
+			l.keys[kv.Key] = struct{}{}
+		}
+	}
+
+	sm.history = nil
+	sm.compactRevision = snap.Revision
+	for w := range sm.watchers {
+		sm.cancelWatcherLocked(w, vterrors.Errorf(vtrpcpb.Code_OUT_OF_RANGE, "watch history was reset by a snapshot at revision %v", snap.Revision))
+	}
+	sm.notifyAppliedLocked()
+}
+
+// snapshot returns the full content of the state machine.
+func (sm *stateMachine) snapshot() *toporaftpb.Snapshot {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	snap := &toporaftpb.Snapshot{
+		LastIndex: sm.appliedIndex,
+		LastTerm:  sm.appliedTerm,
+		Revision:  sm.revision,
+		Kvs:       make([]*toporaftpb.KeyValue, 0, len(sm.kvs)),
+		Leases:    make([]*toporaftpb.Lease, 0, len(sm.leases)),
+	}
+	for _, kv := range sm.kvs {
+		snap.Kvs = append(snap.Kvs, kv.CloneVT())
+	}
+	sort.Slice(snap.Kvs, func(i, j int) bool { return snap.Kvs[i].Key < snap.Kvs[j].Key })
+	for id, l := range sm.leases {
+		snap.Leases = append(snap.Leases, &toporaftpb.Lease{Id: id, TtlSeconds: l.ttlSeconds})
+	}
+	sort.Slice(snap.Leases, func(i, j int) bool { return snap.Leases[i].Id < snap.Leases[j].Id })
+	return snap
+}
+
+// apply applies a committed entry. Errors in the result are the
+// outcome of the command (e.g. a failed version check), they do not
+// stop the state machine.
+func (sm *stateMachine) apply(e *toporaftpb.Entry) applyResult {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	res := sm.applyCommandLocked(e)
+	sm.appliedIndex = e.Index
+	sm.appliedTerm = e.Term
+	sm.notifyAppliedLocked()
+	res.revision = sm.revision
+	return res
+}
+
+func (sm *stateMachine) applyCommandLocked(e *toporaftpb.Entry) applyResult {
+	cmd := e.Command
+	if cmd == nil {
+		return applyResult{}
+	}
+
+	switch cmd.Type {
+	case toporaftpb.Command_NOOP:
+		return applyResult{}
+
+	case toporaftpb.Command_PUT:
+		if err := sm.checkLocked(cmd); err != nil {
+			return applyResult{err: err}
+		}
+		sm.revision++
+		sm.publishLocked([]*toporaftpb.Event{sm.putLocked(cmd)})
+		return applyResult{}
+
+	case toporaftpb.Command_DELETE:
+		if err := sm.checkLocked(cmd); err != nil {
+			return applyResult{err: err}
+		}
+		sm.revision++
+		sm.publishLocked([]*toporaftpb.Event{sm.deleteLocked(sm.kvs[cmd.Key])})
+		return applyResult{}
+
+	case toporaftpb.Command_TXN:
+		// All the conditions are checked before anything is changed.
+		keys := make(map[string]bool, len(cmd.Ops))
+		mutations := 0
+		for _, op := range cmd.Ops {
+			switch op.Type {
+			case toporaftpb.Command_PUT, toporaftpb.Command_DELETE:
+				mutations++
+			case toporaftpb.Command_CHECK:
+			default:
+				return applyResult{err: vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid transaction op type %v", op.Type)}
+			}
+			if keys[op.Key] {
+				return applyResult{err: vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v appears more than once in the transaction", op.Key)}
+			}
+			keys[op.Key] = true
+			if err := sm.checkLocked(op); err != nil {
+				return applyResult{err: err}
+			}
+		}
+		if mutations == 0 {
+			return applyResult{}
+		}
+
+		// All the changes are made at the same revision.
+		sm.revision++
+		events := make([]*toporaftpb.Event, 0, mutations)
+		for _, op := range cmd.Ops {
+			switch op.Type {
+			case toporaftpb.Command_PUT:
+				events = append(events, sm.putLocked(op))
+			case toporaftpb.Command_DELETE:
+				events = append(events, sm.deleteLocked(sm.kvs[op.Key]))
+			}
+		}
+		sm.publishLocked(events)
+		return applyResult{}
+
+	case toporaftpb.Command_GRANT:
+		// The index of the entry is unique, use it as the lease id.
+		id := int64(e.Index)
+		sm.leases[id] = &leaseState{
+			ttlSeconds: cmd.TtlSeconds,
+			keys:       make(map[string]struct{}),
+		}
+		return applyResult{lease: id}
+
+	case toporaftpb.Command_REVOKE:
+		l, ok := sm.leases[cmd.Lease]
+		if !ok {
+			return applyResult{err: vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "lease %v not found", cmd.Lease)}
+		}
+		delete(sm.leases, cmd.Lease)
+		if len(l.keys) == 0 {
+			return applyResult{}
+		}
+
+		// All the keys are deleted at the same revision.
+		keys := make([]string, 0, len(l.keys))
+		for key := range l.keys {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		sm.revision++
+		events := make([]*toporaftpb.Event, 0, len(keys))
+		for _, key := range keys {
+			if kv, ok := sm.kvs[key]; ok {
+				events = append(events, sm.deleteLocked(kv))
+			}
+		}
+		sm.publishLocked(events)
+		return applyResult{}
+	}
+
+	return applyResult{err: vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown command type %v", cmd.Type)}
+}
+
+// checkLocked returns the error a PUT, DELETE or CHECK command fails
+// with, or nil if it can be applied.
+func (sm *stateMachine) checkLocked(cmd *toporaftpb.Command) error {
+	existing := sm.kvs[cmd.Key]
+	switch {
+	case cmd.Type == toporaftpb.Command_PUT && cmd.Create && existing != nil:
+		return vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "%v already exists", cmd.Key)
+	case cmd.Type != toporaftpb.Command_PUT && existing == nil:
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "%v not found", cmd.Key)
+	case cmd.ExpectedRevision != 0 && (existing == nil || existing.ModRevision != cmd.ExpectedRevision):
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "%v does not have revision %v", cmd.Key, cmd.ExpectedRevision)
+	}
+	if cmd.Type == toporaftpb.Command_PUT && cmd.Lease != 0 {
+		if _, ok := sm.leases[cmd.Lease]; !ok {
+			return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "lease %v not found", cmd.Lease)
+		}
+	}
+	return nil
+}
+
+// putLocked sets the key of a PUT command at the current revision, and
+// returns the matching event.
+func (sm *stateMachine) putLocked(cmd *toporaftpb.Command) *toporaftpb.Event {
+	kv := &toporaftpb.KeyValue{
+		Key:            cmd.Key,
+		Value:          cmd.Value,
+		CreateRevision: sm.revision,
+		ModRevision:    sm.revision,
+		Lease:          cmd.Lease,
+	}
+	if existing := sm.kvs[cmd.Key]; existing != nil {
+		kv.CreateRevision = existing.CreateRevision
+		sm.detachLeaseLocked(existing)
+	}
+	if l, ok := sm.leases[kv.Lease]; ok {
+		l.keys[kv.Key] = struct{}{}
+	}
+	sm.kvs[kv.Key] = kv
+	return &toporaftpb.Event{Type: toporaftpb.Event_PUT, Kv: kv.CloneVT()}
+}
+
+// deleteLocked removes kv at the current revision, and returns the
+// matching event.
+func (sm *stateMachine) deleteLocked(kv *toporaftpb.KeyValue) *toporaftpb.Event {
+	sm.detachLeaseLocked(kv)
+	delete(sm.kvs, kv.Key)
+	return &toporaftpb.Event{
+		Type: toporaftpb.Event_DELETE,
+		Kv: &toporaftpb.KeyValue{
+			Key:         kv.Key,
+			ModRevision: sm.revision,
+		},
+	}
+}
+
+func (sm *stateMachine) detachLeaseLocked(kv *toporaftpb.KeyValue) {
+	if l, ok := sm.leases[kv.Lease]; ok {
+		delete(l.keys, kv.Key)
+	}
+}
+
+// publishLocked adds the events of the current revision to the history,
+// and sends them to the matching watchers.
+func (sm *stateMachine) publishLocked(events []*toporaftpb.Event) {
+	sm.history = append(sm.history, events...)
+	if len(sm.history) > maxWatchHistory {
+		// Only drop whole revisions.
+		drop := len(sm.history) - maxWatchHistory
+		last := sm.history[drop-1].Kv.ModRevision
+		for drop < len(sm.history) && sm.history[drop].Kv.ModRevision == last {
+			drop++
+		}
+		sm.compactRevision = last
+		sm.history = append([]*toporaftpb.Event(nil), sm.history[drop:]...)
+	}
+
+	for w := range sm.watchers {
+		if sm.revision < w.startRevision {
+			continue
+		}
+		var matching []*toporaftpb.Event
+		for _, ev := range events {
+			if w.matches(ev.Kv.Key) {
+				matching = append(matching, ev)
+			}
+		}
+		if len(matching) == 0 {
+			continue
+		}
+		select {
+		case w.responses <- &toporaftpb.WatchResponse{Revision: sm.revision, Events: matching}:
+		default:
+			sm.cancelWatcherLocked(w, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "watcher on %v is too slow", w.key))
+		}
+	}
+}
+
+// rangeKeys returns the key, or all the keys starting with key if prefix
+// is set, sorted by key.
+func (sm *stateMachine) rangeKeys(key string, prefix bool) *toporaftpb.RangeResponse {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	resp := &toporaftpb.RangeResponse{Revision: sm.revision}
+	if !prefix {
+		if kv, ok := sm.kvs[key]; ok {
+			resp.Kvs = append(resp.Kvs, kv.CloneVT())
+		}
+		return resp
+	}
+	for k, kv := range sm.kvs {
+		if strings.HasPrefix(k, key) {
+			resp.Kvs = append(resp.Kvs, kv.CloneVT())
+		}
+	}
+	sort.Slice(resp.Kvs, func(i, j int) bool { return resp.Kvs[i].Key < resp.Kvs[j].Key })
+	return resp
+}
+
+// hasLease returns the ttl of the lease, and false if it doesn't exist.
+func (sm *stateMachine) hasLease(id int64) (int64, bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	l, ok := sm.leases[id]
+	if !ok {
+		return 0, false
+	}
+	return l.ttlSeconds, true
+}
+
+// leaseTTLs returns the ttl of all the leases.
+func (sm *stateMachine) leaseTTLs() map[int64]int64 {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	res := make(map[int64]int64, len(sm.leases))
+	for id, l := range sm.leases {
+		res[id] = l.ttlSeconds
+	}
+	return res
+}
+
+// watch registers a watcher for the events starting at startRevision.
+// It returns the events already in the history, grouped by revision.
+func (sm *stateMachine) watch(key string, prefix bool, startRevision int64) (*watcher, []*toporaftpb.WatchResponse, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if startRevision <= sm.compactRevision {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_OUT_OF_RANGE, "revision %v has been compacted, oldest available revision is %v", startRevision, sm.compactRevision+1)
+	}
+
+	w := &watcher{
+		key:           key,
+		prefix:        prefix,
+		startRevision: startRevision,
+		responses:     make(chan *toporaftpb.WatchResponse, watcherBufferSize),
+		done:          make(chan struct{}),
+	}
+	var backlog []*toporaftpb.WatchResponse
+	for _, ev := range sm.history {
+		if ev.Kv.ModRevision < startRevision || !w.matches(ev.Kv.Key) {
+			continue
+		}
+		if len(backlog) > 0 && backlog[len(backlog)-1].Revision == ev.Kv.ModRevision {
+			backlog[len(backlog)-1].Events = append(backlog[len(backlog)-1].Events, ev)
+			continue
+		}
+		backlog = append(backlog, &toporaftpb.WatchResponse{Revision: ev.Kv.ModRevision, Events: []*toporaftpb.Event{ev}})
+	}
+	sm.watchers[w] = struct{}{}
+	return w, backlog, nil
+}
+
+// unwatch removes a watcher.
+func (sm *stateMachine) unwatch(w *watcher) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	delete(sm.watchers, w)
+}
+
+func (sm *stateMachine) cancelWatcherLocked(w *watcher, err error) {
+	delete(sm.watchers, w)
+	w.err = err
+	close(w.done)
+}
+
+func (sm *stateMachine) notifyAppliedLocked() {
+	close(sm.applied)
+	sm.applied = make(chan struct{})
+}
+
+// waitApplied waits until the entry at index has been applied.
+func (sm *stateMachine) waitApplied(ctx context.Context, index uint64) error {
+	for {
+		sm.mu.Lock()
+		appliedIndex, applied := sm.appliedIndex, sm.applied
+		sm.mu.Unlock()
+		if appliedIndex >= index {
+			return nil
+		}
+		select {
+		case <-applied:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// status returns the applied index and the revision.
+func (sm *stateMachine) status() (uint64, int64) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.appliedIndex, sm.revision
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"vitess.io/vitess/go/vt/log"
+
+	toporaftpb "vitess.io/vitess/go/vt/proto/toporaft"
+)
+
+const (
+	hardStateFileName = "state"
+	snapshotFileName  = "snapshot"
+	logFileName       = "log"
+)
+
+// storage persists the Raft state of a node in its data directory:
+//   - the hard state (term and vote), rewritten atomically on change.
+//   - the latest snapshot, rewritten atomically on compaction.
+//   - the log entries that follow the snapshot, in an append-only file of
+//     length-prefixed records. The file is rewritten atomically when
+//     entries are truncated or compacted.
+type storage struct {
+	dir     string
+	logFile *os.File
+}
+
+// openStorage opens (or initializes) the data directory, and returns
+// the persisted state. A missing snapshot is returned as an empty one.
+func openStorage(dir string) (*storage, *toporaftpb.HardState, *toporaftpb.Snapshot, []*toporaftpb.Entry, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	hs := &toporaftpb.HardState{}
+	if err := readProtoFile(filepath.Join(dir, hardStateFileName), hs); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	snap := &toporaftpb.Snapshot{}
+	if err := readProtoFile(filepath.Join(dir, snapshotFileName), snap); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	entries, validSize, err := readLogFile(filepath.Join(dir, logFileName))
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	// Drop the entries already covered by the snapshot, and make
+	// sure the remaining ones follow it without any gap.
+	for len(entries) > 0 && entries[0].Index <= snap.LastIndex {
+		entries = entries[1:]
+	}
+	for i, e := range entries {
+		if e.Index != snap.LastIndex+uint64(i)+1 {
+			return nil, nil, nil, nil, fmt.Errorf("corrupted raft log in %v: expected entry %v, got %v", dir, snap.LastIndex+uint64(i)+1, e.Index)
+		}
+	}
+
+	logFile, err := os.OpenFile(filepath.Join(dir, logFileName), os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	// A record may have been partially written when we crashed,
+	// discard it.
+	if err := logFile.Truncate(validSize); err != nil {
+		logFile.Close()
+		return nil, nil, nil, nil, err
+	}
+	if _, err := logFile.Seek(validSize, io.SeekStart); err != nil {
+		logFile.Close()
+		return nil, nil, nil, nil, err
+	}
+
+	return &storage{
+		dir:     dir,
+		logFile: logFile,
+	}, hs, snap, entries, nil
+}
+
+// saveHardState persists the term and vote.
+func (st *storage) saveHardState(hs *toporaftpb.HardState) error {
+	return writeProtoFile(filepath.Join(st.dir, hardStateFileName), hs)
+}
+
+// saveSnapshot persists snap, and rewrites the log with the provided
+// entries, that must follow the snapshot.
+func (st *storage) saveSnapshot(snap *toporaftpb.Snapshot, entries []*toporaftpb.Entry) error {
+	if err := writeProtoFile(filepath.Join(st.dir, snapshotFileName), snap); err != nil {
+		return err
+	}
+	return st.rewriteLog(entries)
+}
+
+// appendEntries appends entries to the log file, and syncs it.
+func (st *storage) appendEntries(entries []*toporaftpb.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	buf, err := encodeEntries(entries)
+	if err != nil {
+		return err
+	}
+	if _, err := st.logFile.Write(buf); err != nil {
+		return err
+	}
+	return st.logFile.Sync()
+}
+
+// rewriteLog atomically replaces the log file with entries.
+func (st *storage) rewriteLog(entries []*toporaftpb.Entry) error {
+	buf, err := encodeEntries(entries)
+	if err != nil {
+		return err
+	}
+	fileName := filepath.Join(st.dir, logFileName)
+	if err := writeFileAtomic(fileName, buf); err != nil {
+		return err
+	}
+
+	logFile, err := os.OpenFile(fileName, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := st.logFile.Close(); err != nil {
+		log.Warningf("failed to close previous raft log file: %v", err)
+	}
+	st.logFile = logFile
+	return nil
+}
+
+func (st *storage) close() error {
+	return st.logFile.Close()
+}
+
+// encodeEntries encodes entries as records of a 4 bytes big endian
+// length followed by the marshaled entry.
+func encodeEntries(entries []*toporaftpb.Entry) ([]byte, error) {
+	var buf []byte
+	for _, e := range entries {
+		data, err := e.MarshalVT()
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
+
+// readLogFile reads all the complete records of the log file. It also
+// returns the size of the file up to the end of the last complete record.
+func readLogFile(fileName string) ([]*toporaftpb.Entry, int64, error) {
+	f, err := os.Open(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var (
+		entries []*toporaftpb.Entry
+		size    int64
+		header  [4]byte
+	)
+	r := bufio.NewReader(f)
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return entries, size, nil
+			}
+			return nil, 0, err
+		}
+		data := make([]byte, binary.BigEndian.Uint32(header[:]))
+		if _, err := io.ReadFull(r, data); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				log.Warningf("discarding partially written entry at the end of %v", fileName)
+				return entries, size, nil
+			}
+			return nil, 0, err
+		}
+		e := &toporaftpb.Entry{}
+		if err := e.UnmarshalVT(data); err != nil {
+			return nil, 0, fmt.Errorf("corrupted entry in %v: %v", fileName, err)
+		}
+		entries = append(entries, e)
+		size += int64(len(header) + len(data))
+	}
+}
+
+// protoFile is implemented by the messages persisted in their own file.
+type protoFile interface {
+	MarshalVT() ([]byte, error)
+	UnmarshalVT([]byte) error
+}
+
+// readProtoFile unmarshals the file into msg. A missing file leaves msg
+// untouched.
+func readProtoFile(fileName string, msg protoFile) error {
+	data, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return msg.UnmarshalVT(data)
+}
+
+func writeProtoFile(fileName string, msg protoFile) error {
+	data, err := msg.MarshalVT()
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(fileName, data)
+}
+
+// writeFileAtomic writes data to a temporary file, syncs it, and renames
+// it to fileName.
+func writeFileAtomic(fileName string, data []byte) error {
+	tmpName := fileName + ".tmp"
+	f, err := os.OpenFile(tmpName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, fileName); err != nil {
+		return err
+	}
+
+	// Sync the directory so the rename is durable.
+	dir, err := os.Open(filepath.Dir(fileName))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
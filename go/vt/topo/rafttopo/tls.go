@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"crypto/tls"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vttls"
+)
+
+// The TLS flags are used by the clients of the raft topo, and by the
+// embedded node, which serves the clients and connects to its peers with
+// the same certificate.
+var (
+	tlsCertPath string
+	tlsKeyPath  string
+	tlsCAPath   string
+)
+
+func init() {
+	for _, cmd := range topo.FlagBinaries {
+		servenv.OnParseFor(cmd, registerRaftTopoTLSFlags)
+	}
+}
+
+func registerRaftTopoTLSFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&tlsCertPath, "topo_raft_tls_cert", tlsCertPath, "path to the cert to use to connect to the raft topo nodes, and to serve the embedded raft topo node with, requires topo_raft_tls_key, enables TLS")
+	fs.StringVar(&tlsKeyPath, "topo_raft_tls_key", tlsKeyPath, "path to the key to use to connect to the raft topo nodes, and to serve the embedded raft topo node with, enables TLS")
+	fs.StringVar(&tlsCAPath, "topo_raft_tls_ca", tlsCAPath, "path to the ca to use to validate the certs of the raft topo nodes, and the client certs required by the embedded raft topo node")
+}
+
+// TLSConfig holds the paths of the certificate, key and ca used by the
+// raft topo clients and nodes. TLS is disabled if all of them are empty.
+type TLSConfig struct {
+	CertPath string
+	KeyPath  string
+	CAPath   string
+}
+
+// dialOption returns the transport credentials to connect to the nodes
+// with.
+func (c TLSConfig) dialOption() (grpc.DialOption, error) {
+	return grpcclient.SecureDialOption(c.CertPath, c.KeyPath, c.CAPath, "", "")
+}
+
+// serverOptions returns the transport credentials of the gRPC service of
+// a node. Clients must present a certificate signed by the ca, if any.
+func (c TLSConfig) serverOptions() ([]grpc.ServerOption, error) {
+	if c.CertPath == "" || c.KeyPath == "" {
+		return nil, nil
+	}
+	config, err := vttls.ServerConfig(c.CertPath, c.KeyPath, c.CAPath, "", "", tls.VersionTLS12)
+	if err != nil {
+		return nil, err
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(config))}, nil
+}
+
+// flagsTLSConfig returns the TLSConfig set by the flags.
+func flagsTLSConfig() TLSConfig {
+	return TLSConfig{
+		CertPath: tlsCertPath,
+		KeyPath:  tlsKeyPath,
+		CAPath:   tlsCAPath,
+	}
+}
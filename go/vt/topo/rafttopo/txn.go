@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	toporaftpb "vitess.io/vitess/go/vt/proto/toporaft"
+)
+
+// TxnBackend is part of the topo.TxnConn interface. The cells that use
+// the same nodes, with different roots, can be part of the same
+// transaction.
+func (s *Server) TxnBackend() string {
+	return "raft:" + s.cluster
+}
+
+// Txn is part of the topo.TxnConn interface. It proposes all the ops as a
+// single TXN command, which the state machine applies at one revision.
+func (s *Server) Txn(ctx context.Context, ops []*topo.TxnOp) ([]topo.Version, error) {
+	cmd := &toporaftpb.Command{Type: toporaftpb.Command_TXN}
+	nodePaths := make([]string, len(ops))
+	for i, op := range ops {
+		root := s.root
+		if op.Conn != nil {
+			conn, ok := op.Conn.(*Server)
+			if !ok || conn.cluster != s.cluster {
+				return nil, fmt.Errorf("transaction op on %v is not for the same raft cluster", op.Path)
+			}
+			root = conn.root
+		}
+		nodePaths[i] = path.Join(root, op.Path)
+
+		txnCmd := &toporaftpb.Command{Key: nodePaths[i]}
+		switch op.Type {
+		case topo.TxnCheck:
+			txnCmd.Type = toporaftpb.Command_CHECK
+		case topo.TxnCreate:
+			txnCmd.Type = toporaftpb.Command_PUT
+			txnCmd.Value = op.Contents
+			txnCmd.Create = true
+		case topo.TxnUpdate:
+			txnCmd.Type = toporaftpb.Command_PUT
+			txnCmd.Value = op.Contents
+		case topo.TxnDelete:
+			txnCmd.Type = toporaftpb.Command_DELETE
+		default:
+			return nil, fmt.Errorf("unknown transaction op type %v", op.Type)
+		}
+		if op.Version != nil {
+			txnCmd.ExpectedRevision = int64(op.Version.(RaftVersion))
+		}
+		cmd.Ops = append(cmd.Ops, txnCmd)
+	}
+
+	var resp *toporaftpb.ProposeResponse
+	err := s.call(ctx, func(cli toporaftpb.TopoRaftClient, opts ...grpc.CallOption) error {
+		var err error
+		resp, err = cli.Propose(ctx, &toporaftpb.ProposeRequest{Command: cmd}, opts...)
+		return err
+	})
+	if err != nil {
+		// We don't know which op failed, so the error has all the paths.
+		return nil, convertError(err, strings.Join(nodePaths, ", "))
+	}
+
+	versions := make([]topo.Version, len(ops))
+	for i, op := range ops {
+		if op.Type == topo.TxnCreate || op.Type == topo.TxnUpdate {
+			versions[i] = RaftVersion(resp.Revision)
+		}
+	}
+	return versions, nil
+}
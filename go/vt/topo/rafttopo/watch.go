@@ -0,0 +1,236 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rafttopo
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+
+	toporaftpb "vitess.io/vitess/go/vt/proto/toporaft"
+)
+
+// maxWatchRetryInterval caps the backoff between two attempts to
+// re-establish a broken watch stream.
+const maxWatchRetryInterval = 5 * time.Second
+
+// watchResult is either a response of a watch stream, or the final
+// error of the watch.
+type watchResult struct {
+	resp *toporaftpb.WatchResponse
+	err  error
+}
+
+// watchEvents streams the events on key, or on all the keys starting
+// with key if prefix is set, from startRevision on. It re-establishes the
+// stream when it breaks, resuming after the last received revision.
+// The returned channel is closed when ctx is done, when s is closed, or
+// when the watch cannot be resumed. In the first and last cases, the last
+// result carries the converted error.
+func (s *Server) watchEvents(ctx context.Context, key string, prefix bool, startRevision int64) <-chan watchResult {
+	results := make(chan watchResult, 10)
+	go func() {
+		defer close(results)
+
+		rev := startRevision
+		var retries int
+		for {
+			addr, cli, err := s.client()
+			if err == nil {
+				var stream toporaftpb.TopoRaft_WatchClient
+				stream, err = cli.Watch(ctx, &toporaftpb.WatchRequest{Key: key, Prefix: prefix, StartRevision: rev})
+				for err == nil {
+					var resp *toporaftpb.WatchResponse
+					resp, err = stream.Recv()
+					if err != nil {
+						break
+					}
+					retries = 0
+					rev = resp.Revision + 1
+					select {
+					case results <- watchResult{resp: resp}:
+					case <-ctx.Done():
+					case <-s.running:
+						return
+					}
+				}
+				if status.Code(err) == codes.Unavailable {
+					s.nodeFailed(addr, "")
+				}
+			}
+
+			select {
+			case <-s.running:
+				return
+			default:
+			}
+			if ctx.Err() != nil {
+				results <- watchResult{err: convertError(ctx.Err(), key)}
+				return
+			}
+			if status.Code(err) == codes.OutOfRange {
+				// The node doesn't have the history we need to
+				// resume anymore.
+				results <- watchResult{err: convertError(err, key)}
+				return
+			}
+
+			log.Warningf("watch %v failed, retrying from revision %v: %v", key, rev, err)
+			retries++
+			t := time.NewTimer(min(time.Duration(retries)*retryInterval, maxWatchRetryInterval))
+			select {
+			case <-t.C:
+			case <-s.running:
+				t.Stop()
+				return
+			case <-ctx.Done():
+				t.Stop()
+			}
+		}
+	}()
+	return results
+}
+
+// Watch is part of the topo.Conn interface.
+func (s *Server) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	// Get the initial version of the file
+	initialCtx, initialCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer initialCancel()
+	initial, err := s.rangeKeys(initialCtx, nodePath, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(initial.Kvs) != 1 {
+		// Node doesn't exist.
+		return nil, nil, topo.NewError(topo.NoNode, nodePath)
+	}
+	wd := &topo.WatchData{
+		Contents: initial.Kvs[0].Value,
+		Version:  RaftVersion(initial.Kvs[0].ModRevision),
+	}
+
+	// Watch the changes that follow the revision we read.
+	watchCtx, watchCancel := context.WithCancel(ctx)
+	events := s.watchEvents(watchCtx, nodePath, false, initial.Revision+1)
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchData, 10)
+	go func() {
+		defer close(notifications)
+		defer watchCancel()
+
+		for res := range events {
+			if res.err != nil {
+				// Final notification.
+				notifications <- &topo.WatchData{Err: res.err}
+				return
+			}
+			for _, ev := range res.resp.Events {
+				switch ev.Type {
+				case toporaftpb.Event_PUT:
+					notifications <- &topo.WatchData{
+						Contents: ev.Kv.Value,
+						Version:  RaftVersion(ev.Kv.ModRevision),
+					}
+				case toporaftpb.Event_DELETE:
+					// Node is gone, send a final notice.
+					notifications <- &topo.WatchData{
+						Err: topo.NewError(topo.NoNode, nodePath),
+					}
+					return
+				}
+			}
+		}
+	}()
+
+	return wd, notifications, nil
+}
+
+// WatchRecursive is part of the topo.Conn interface.
+func (s *Server) WatchRecursive(ctx context.Context, dirpath string) ([]*topo.WatchDataRecursive, <-chan *topo.WatchDataRecursive, error) {
+	nodePath := path.Join(s.root, dirpath)
+	if !strings.HasSuffix(nodePath, "/") {
+		nodePath = nodePath + "/"
+	}
+
+	// Get the initial version of the files.
+	initial, err := s.rangeKeys(ctx, nodePath, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	var initialwd []*topo.WatchDataRecursive
+	for _, kv := range initial.Kvs {
+		initialwd = append(initialwd, &topo.WatchDataRecursive{
+			Path: kv.Key,
+			WatchData: topo.WatchData{
+				Contents: kv.Value,
+				Version:  RaftVersion(kv.ModRevision),
+			},
+		})
+	}
+
+	// Watch the changes that follow the revision we read.
+	watchCtx, watchCancel := context.WithCancel(ctx)
+	events := s.watchEvents(watchCtx, nodePath, true, initial.Revision+1)
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchDataRecursive, 10)
+	go func() {
+		defer close(notifications)
+		defer watchCancel()
+
+		for res := range events {
+			if res.err != nil {
+				// Final notification.
+				notifications <- &topo.WatchDataRecursive{
+					WatchData: topo.WatchData{Err: res.err},
+				}
+				return
+			}
+			for _, ev := range res.resp.Events {
+				switch ev.Type {
+				case toporaftpb.Event_PUT:
+					notifications <- &topo.WatchDataRecursive{
+						Path: ev.Kv.Key,
+						WatchData: topo.WatchData{
+							Contents: ev.Kv.Value,
+							Version:  RaftVersion(ev.Kv.ModRevision),
+						},
+					}
+				case toporaftpb.Event_DELETE:
+					notifications <- &topo.WatchDataRecursive{
+						Path: ev.Kv.Key,
+						WatchData: topo.WatchData{
+							Err: topo.NewError(topo.NoNode, nodePath),
+						},
+					}
+				}
+			}
+		}
+	}()
+
+	return initialwd, notifications, nil
+}
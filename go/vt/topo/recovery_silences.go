@@ -0,0 +1,228 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// The VTOrc recovery silences are all stored in a single file of the
+// global cell, so that every VTOrc instance sees the same ones.
+
+// maxRecoverySilencesAttempts is the number of times we try to update the
+// recovery silences when other processes update them concurrently.
+const maxRecoverySilencesAttempts = 10
+
+// recoverySilencesFilePath is the path of the recovery silences in the
+// global cell.
+var recoverySilencesFilePath = path.Join(VTOrcPath, RecoverySilencesFile)
+
+// IsRecoverySilenceActive returns true if the silence applies at the given
+// time.
+func IsRecoverySilenceActive(silence *topodatapb.RecoverySilence, now time.Time) bool {
+	start := protoutil.TimeFromProto(silence.StartTime)
+	expire := protoutil.TimeFromProto(silence.ExpireTime)
+	return !now.Before(start) && now.Before(expire)
+}
+
+// isRecoverySilenceExpired returns true if the silence doesn't apply
+// anymore at the given time.
+func isRecoverySilenceExpired(silence *topodatapb.RecoverySilence, now time.Time) bool {
+	expire := protoutil.TimeFromProto(silence.ExpireTime)
+	return !now.Before(expire)
+}
+
+// validateRecoverySilence checks the fields of a silence that is added.
+func validateRecoverySilence(silence *topodatapb.RecoverySilence, now time.Time) error {
+	if silence.Keyspace == "" && silence.TabletAlias == nil {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a recovery silence requires a keyspace or a tablet alias")
+	}
+	if silence.Keyspace != "" {
+		if err := ValidateKeyspaceName(silence.Keyspace); err != nil {
+			return err
+		}
+	}
+	if silence.Shard != "" {
+		if silence.Keyspace == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a recovery silence for shard %v requires a keyspace", silence.Shard)
+		}
+		if _, _, err := ValidateShardName(silence.Shard); err != nil {
+			return err
+		}
+	}
+	if silence.Reason == "" {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a recovery silence requires a reason")
+	}
+	start := protoutil.TimeFromProto(silence.StartTime)
+	expire := protoutil.TimeFromProto(silence.ExpireTime)
+	if !expire.After(start) {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the recovery silence must expire after it starts at %v", start.Format(time.RFC3339))
+	}
+	if !expire.After(now) {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the recovery silence expired at %v", expire.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// GetRecoverySilences returns the VTOrc recovery silences that haven't
+// expired, sorted by start time.
+func (ts *Server) GetRecoverySilences(ctx context.Context) ([]*topodatapb.RecoverySilence, error) {
+	silences, _, err := ts.getRecoverySilences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return silences, nil
+}
+
+// getRecoverySilences returns the recovery silences that haven't expired,
+// sorted by start time, and the version of the file, nil if it doesn't
+// exist.
+func (ts *Server) getRecoverySilences(ctx context.Context) ([]*topodatapb.RecoverySilence, Version, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	data, version, err := ts.globalCell.Get(ctx, recoverySilencesFilePath)
+	if err != nil {
+		if IsErrType(err, NoNode) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	rs := &topodatapb.RecoverySilences{}
+	if err := rs.UnmarshalVT(data); err != nil {
+		return nil, nil, vterrors.Wrapf(err, "bad recovery silences data: %q", data)
+	}
+
+	now := time.Now()
+	silences := make([]*topodatapb.RecoverySilence, 0, len(rs.Silences))
+	for _, silence := range rs.Silences {
+		if !isRecoverySilenceExpired(silence, now) {
+			silences = append(silences, silence)
+		}
+	}
+	sort.SliceStable(silences, func(i, j int) bool {
+		return protoutil.TimeFromProto(silences[i].StartTime).Before(protoutil.TimeFromProto(silences[j].StartTime))
+	})
+	return silences, version, nil
+}
+
+// updateRecoverySilences applies update to the recovery silences that
+// haven't expired, and writes them back. It retries when the silences are
+// changed concurrently.
+func (ts *Server) updateRecoverySilences(ctx context.Context, update func([]*topodatapb.RecoverySilence) ([]*topodatapb.RecoverySilence, error)) error {
+	var err error
+	for range maxRecoverySilencesAttempts {
+		var silences []*topodatapb.RecoverySilence
+		var version Version
+		silences, version, err = ts.getRecoverySilences(ctx)
+		if err != nil {
+			return err
+		}
+		if silences, err = update(silences); err != nil {
+			return err
+		}
+		var data []byte
+		data, err = (&topodatapb.RecoverySilences{Silences: silences}).MarshalVT()
+		if err != nil {
+			return err
+		}
+		if version == nil {
+			_, err = ts.globalCell.Create(ctx, recoverySilencesFilePath, data)
+		} else {
+			_, err = ts.globalCell.Update(ctx, recoverySilencesFilePath, data, version)
+		}
+		if !IsErrType(err, BadVersion) && !IsErrType(err, NodeExists) {
+			return err
+		}
+	}
+	return vterrors.Wrapf(err, "failed to update the recovery silences after %d attempts", maxRecoverySilencesAttempts)
+}
+
+// AddRecoverySilence adds a VTOrc recovery silence, and returns it with
+// its generated id. The silence starts now if it has no start time, and
+// the expired silences are removed.
+func (ts *Server) AddRecoverySilence(ctx context.Context, silence *topodatapb.RecoverySilence) (*topodatapb.RecoverySilence, error) {
+	now := time.Now()
+	silence = silence.CloneVT()
+	silence.Id = uuid.NewString()
+	if silence.StartTime == nil {
+		silence.StartTime = protoutil.TimeToProto(now)
+	}
+	if silence.CreatedBy == "" {
+		silence.CreatedBy = configChangeAuthor(ctx)
+	}
+	if err := validateRecoverySilence(silence, now); err != nil {
+		return nil, err
+	}
+
+	err := ts.updateRecoverySilences(ctx, func(silences []*topodatapb.RecoverySilence) ([]*topodatapb.RecoverySilence, error) {
+		return append(silences, silence), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return silence, nil
+}
+
+// RemoveRecoverySilence removes a VTOrc recovery silence. It returns a
+// NoNode error if there is no such silence, or if it expired.
+func (ts *Server) RemoveRecoverySilence(ctx context.Context, id string) error {
+	return ts.updateRecoverySilences(ctx, func(silences []*topodatapb.RecoverySilence) ([]*topodatapb.RecoverySilence, error) {
+		for i, silence := range silences {
+			if silence.Id == id {
+				return append(silences[:i], silences[i+1:]...), nil
+			}
+		}
+		return nil, NewError(NoNode, "recovery silence "+id)
+	})
+}
+
+// RecoverySilenceMatches returns true if the silence selects the given
+// tablet, and analysis code. It doesn't check the silence is active.
+func RecoverySilenceMatches(silence *topodatapb.RecoverySilence, keyspace, shard string, tabletAlias *topodatapb.TabletAlias, analysisCode string) bool {
+	if silence.Keyspace != "" && silence.Keyspace != keyspace {
+		return false
+	}
+	if silence.Shard != "" && silence.Shard != shard {
+		return false
+	}
+	if silence.TabletAlias != nil && !topoproto.TabletAliasEqual(silence.TabletAlias, tabletAlias) {
+		return false
+	}
+	if len(silence.AnalysisCodes) == 0 {
+		return true
+	}
+	for _, code := range silence.AnalysisCodes {
+		if code == analysisCode {
+			return true
+		}
+	}
+	return false
+}
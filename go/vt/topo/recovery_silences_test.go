@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestRecoverySilences(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	silences, err := ts.GetRecoverySilences(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, silences)
+
+	now := time.Now()
+	shardSilence, err := ts.AddRecoverySilence(ctx, &topodatapb.RecoverySilence{
+		Keyspace:   "ks",
+		Shard:      "-80",
+		ExpireTime: protoutil.TimeToProto(now.Add(time.Hour)),
+		Reason:     "maintenance",
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, shardSilence.Id)
+	assert.NotEmpty(t, shardSilence.CreatedBy)
+	assert.True(t, topo.IsRecoverySilenceActive(shardSilence, time.Now()))
+
+	// A scheduled silence isn't active yet.
+	tabletAlias := &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}
+	scheduledSilence, err := ts.AddRecoverySilence(ctx, &topodatapb.RecoverySilence{
+		TabletAlias:   tabletAlias,
+		AnalysisCodes: []string{"ReplicationStopped"},
+		StartTime:     protoutil.TimeToProto(now.Add(time.Hour)),
+		ExpireTime:    protoutil.TimeToProto(now.Add(2 * time.Hour)),
+		Reason:        "host migration",
+	})
+	require.NoError(t, err)
+	assert.False(t, topo.IsRecoverySilenceActive(scheduledSilence, time.Now()))
+	assert.True(t, topo.IsRecoverySilenceActive(scheduledSilence, now.Add(90*time.Minute)))
+
+	silences, err = ts.GetRecoverySilences(ctx)
+	require.NoError(t, err)
+	require.Len(t, silences, 2)
+	assert.Equal(t, shardSilence.Id, silences[0].Id)
+	assert.Equal(t, scheduledSilence.Id, silences[1].Id)
+
+	assert.True(t, topo.RecoverySilenceMatches(shardSilence, "ks", "-80", tabletAlias, "DeadPrimary"))
+	assert.False(t, topo.RecoverySilenceMatches(shardSilence, "ks", "80-", tabletAlias, "DeadPrimary"))
+	assert.True(t, topo.RecoverySilenceMatches(scheduledSilence, "ks", "80-", tabletAlias, "ReplicationStopped"))
+	assert.False(t, topo.RecoverySilenceMatches(scheduledSilence, "ks", "80-", tabletAlias, "DeadPrimary"))
+	assert.False(t, topo.RecoverySilenceMatches(scheduledSilence, "ks", "80-", &topodatapb.TabletAlias{Cell: "zone1", Uid: 101}, "ReplicationStopped"))
+
+	require.NoError(t, ts.RemoveRecoverySilence(ctx, shardSilence.Id))
+	err = ts.RemoveRecoverySilence(ctx, shardSilence.Id)
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "got %v", err)
+	silences, err = ts.GetRecoverySilences(ctx)
+	require.NoError(t, err)
+	require.Len(t, silences, 1)
+	assert.Equal(t, scheduledSilence.Id, silences[0].Id)
+
+	invalid := []*topodatapb.RecoverySilence{
+		// No keyspace nor tablet.
+		{ExpireTime: protoutil.TimeToProto(now.Add(time.Hour)), Reason: "r"},
+		// Shard without keyspace.
+		{Shard: "0", TabletAlias: tabletAlias, ExpireTime: protoutil.TimeToProto(now.Add(time.Hour)), Reason: "r"},
+		// No reason.
+		{Keyspace: "ks", ExpireTime: protoutil.TimeToProto(now.Add(time.Hour))},
+		// Already expired.
+		{Keyspace: "ks", StartTime: protoutil.TimeToProto(now.Add(-time.Hour)), ExpireTime: protoutil.TimeToProto(now.Add(-time.Minute)), Reason: "r"},
+		// Expires before it starts.
+		{Keyspace: "ks", StartTime: protoutil.TimeToProto(now.Add(2 * time.Hour)), ExpireTime: protoutil.TimeToProto(now.Add(time.Hour)), Reason: "r"},
+	}
+	for _, silence := range invalid {
+		_, err := ts.AddRecoverySilence(ctx, silence)
+		assert.Error(t, err, "%v", silence)
+	}
+}
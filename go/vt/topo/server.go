@@ -69,20 +69,23 @@ const (
 
 // Filenames for all object types.
 const (
-	CellInfoFile           = "CellInfo"
-	CellsAliasFile         = "CellsAlias"
-	KeyspaceFile           = "Keyspace"
-	ShardFile              = "Shard"
-	VSchemaFile            = "VSchema"
-	ShardReplicationFile   = "ShardReplication"
-	TabletFile             = "Tablet"
-	SrvVSchemaFile         = "SrvVSchema"
-	SrvKeyspaceFile        = "SrvKeyspace"
-	RoutingRulesFile       = "RoutingRules"
-	ExternalClustersFile   = "ExternalClusters"
-	ShardRoutingRulesFile  = "ShardRoutingRules"
-	CommonRoutingRulesFile = "Rules"
-	MirrorRulesFile        = "MirrorRules"
+	CellInfoFile             = "CellInfo"
+	CellsAliasFile           = "CellsAlias"
+	KeyspaceFile             = "Keyspace"
+	ShardFile                = "Shard"
+	VSchemaFile              = "VSchema"
+	ShardReplicationFile     = "ShardReplication"
+	TabletFile               = "Tablet"
+	SrvVSchemaFile           = "SrvVSchema"
+	SrvKeyspaceFile          = "SrvKeyspace"
+	RoutingRulesFile         = "RoutingRules"
+	ExternalClustersFile     = "ExternalClusters"
+	ShardRoutingRulesFile    = "ShardRoutingRules"
+	CommonRoutingRulesFile   = "Rules"
+	MirrorRulesFile          = "MirrorRules"
+	BackupScheduleFile       = "BackupSchedule"
+	BackupScheduleStatusFile = "BackupScheduleStatus"
+	RecoverySilencesFile     = "RecoverySilences"
 )
 
 // Path for all object types.
@@ -97,6 +100,8 @@ const (
 	RoutingRulesPath         = "routing_rules"
 	KeyspaceRoutingRulesPath = "keyspace"
 	NamedLocksPath           = "internal/named_locks"
+	ConfigHistoryPath        = "config_history"
+	VTOrcPath                = "vtorc"
 )
 
 // Factory is a factory interface to create Conn objects.
@@ -0,0 +1,380 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"sync"
+
+	"vitess.io/vitess/go/event"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/topo/events"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// Txn stages changes to shard records and SrvKeyspaces, and writes them
+// all at once with ApplyTxnOps when it is committed. Every record is
+// written at the version it was read at, so that a Txn either applies
+// all its changes or none of them, and fails with ErrBadVersion if
+// something else changed one of them in the meantime.
+//
+// A Txn is not safe for concurrent use. RetryTxn is the usual way to
+// run one.
+type Txn struct {
+	ts      *Server
+	entries map[string]*txnEntry
+	// order is the order the entries were staged in, so the ops of the
+	// transaction are deterministic.
+	order []*txnEntry
+}
+
+// txnEntry is a file staged in a Txn.
+type txnEntry struct {
+	cell string
+	path string
+
+	// read is set if the file was read in the Txn, in which case it is
+	// written with a compare-and-swap on version, or created if it
+	// didn't exist.
+	read    bool
+	exists  bool
+	version Version
+
+	// dirty is set if the file must be written when the Txn is
+	// committed.
+	dirty bool
+
+	// shard is set for shard records.
+	shard *ShardInfo
+	// srvKeyspace is set for SrvKeyspaces.
+	srvKeyspace *topodatapb.SrvKeyspace
+}
+
+// NewTxn returns a new empty Txn.
+func (ts *Server) NewTxn() *Txn {
+	return &Txn{
+		ts:      ts,
+		entries: make(map[string]*txnEntry),
+	}
+}
+
+// RetryTxn runs f on a new Txn and commits it. If the commit fails
+// because one of the records changed since f read it, it runs f again on
+// a new Txn, until the commit succeeds, fails for another reason, or ctx
+// is done.
+func (ts *Server) RetryTxn(ctx context.Context, f func(*Txn) error) error {
+	for {
+		txn := ts.NewTxn()
+		if err := f(txn); err != nil {
+			return err
+		}
+		err := txn.Commit(ctx)
+		if !IsErrType(err, BadVersion) && !IsErrType(err, NodeExists) {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return vterrors.Wrapf(err, "topo transaction kept conflicting until %v", ctxErr)
+		}
+	}
+}
+
+// retryPartialTxn is RetryTxn for an f that returns ErrPartialResult
+// when it staged the changes of only some of the cells: they are
+// committed, and the ErrPartialResult is returned.
+func retryPartialTxn(ctx context.Context, ts *Server, f func(*Txn) error) error {
+	var partialErr error
+	err := ts.RetryTxn(ctx, func(txn *Txn) error {
+		partialErr = f(txn)
+		if IsErrType(partialErr, PartialResult) {
+			return nil
+		}
+		return partialErr
+	})
+	if err != nil {
+		return err
+	}
+	return partialErr
+}
+
+func (txn *Txn) entry(cell, path string) (*txnEntry, bool) {
+	key := cell + ":" + path
+	e, ok := txn.entries[key]
+	if !ok {
+		e = &txnEntry{cell: cell, path: path}
+		txn.entries[key] = e
+		txn.order = append(txn.order, e)
+	}
+	return e, ok
+}
+
+// GetShard returns the shard record staged in the Txn, reading it if
+// this is the first time. Changes to the returned ShardInfo are only
+// written if UpdateShardFields is called for it.
+func (txn *Txn) GetShard(ctx context.Context, keyspace, shard string) (*ShardInfo, error) {
+	e, ok := txn.entry(GlobalCell, shardFilePath(keyspace, shard))
+	if ok && e.shard != nil {
+		return e.shard, nil
+	}
+	si, err := txn.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	e.read = true
+	e.exists = true
+	e.version = si.version
+	e.shard = si
+	return si, nil
+}
+
+// UpdateShardFields calls update on the shard record staged in the Txn,
+// like Server.UpdateShardFields. If update returns ErrNoUpdateNeeded,
+// nothing is changed, and nil,nil is returned.
+func (txn *Txn) UpdateShardFields(ctx context.Context, keyspace, shard string, update func(*ShardInfo) error) (*ShardInfo, error) {
+	si, err := txn.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if err := update(si); err != nil {
+		if IsErrType(err, NoUpdateNeeded) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	e, _ := txn.entry(GlobalCell, shardFilePath(keyspace, shard))
+	e.dirty = true
+	return si, nil
+}
+
+// GetSrvKeyspace returns the SrvKeyspace staged in the Txn for a cell,
+// reading it if this is the first time. It returns ErrNoNode if it
+// doesn't exist. Changes to the returned SrvKeyspace are only written if
+// UpdateSrvKeyspace is called for it.
+func (txn *Txn) GetSrvKeyspace(ctx context.Context, cell, keyspace string) (*topodatapb.SrvKeyspace, error) {
+	e, _ := txn.entry(cell, srvKeyspaceFileName(keyspace))
+	if err := txn.readSrvKeyspaces(ctx, []*txnEntry{e}); err != nil {
+		return nil, err
+	}
+	if e.srvKeyspace == nil {
+		return nil, NewError(NoNode, srvKeyspaceFileName(keyspace))
+	}
+	return e.srvKeyspace, nil
+}
+
+// GetSrvKeyspaces returns the SrvKeyspaces staged in the Txn for cells,
+// reading the ones that weren't read yet in parallel. The cells that
+// don't have a SrvKeyspace are not in the result. If some cells can't be
+// read, it returns the SrvKeyspaces of the others with ErrPartialResult.
+func (txn *Txn) GetSrvKeyspaces(ctx context.Context, keyspace string, cells []string) (map[string]*topodatapb.SrvKeyspace, error) {
+	nodePath := srvKeyspaceFileName(keyspace)
+	var toRead []*txnEntry
+	for _, cell := range cells {
+		e, _ := txn.entry(cell, nodePath)
+		toRead = append(toRead, e)
+	}
+	err := txn.readSrvKeyspaces(ctx, toRead)
+
+	srvKeyspaces := make(map[string]*topodatapb.SrvKeyspace, len(cells))
+	for _, e := range toRead {
+		if e.srvKeyspace != nil {
+			srvKeyspaces[e.cell] = e.srvKeyspace
+		}
+	}
+	if err != nil {
+		return srvKeyspaces, NewError(PartialResult, err.Error())
+	}
+	return srvKeyspaces, nil
+}
+
+// readSrvKeyspaces reads the SrvKeyspaces of the entries that weren't
+// read yet, in parallel.
+func (txn *Txn) readSrvKeyspaces(ctx context.Context, entries []*txnEntry) error {
+	wg := sync.WaitGroup{}
+	rec := concurrency.AllErrorRecorder{}
+	for _, e := range entries {
+		if e.read || e.srvKeyspace != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(e *txnEntry) {
+			defer wg.Done()
+			conn, err := txn.ts.ConnForCell(ctx, e.cell)
+			if err != nil {
+				rec.RecordError(err)
+				return
+			}
+			data, version, err := conn.Get(ctx, e.path)
+			switch {
+			case err == nil:
+				srvKeyspace := &topodatapb.SrvKeyspace{}
+				if err := srvKeyspace.UnmarshalVT(data); err != nil {
+					rec.RecordError(vterrors.Wrapf(err, "SrvKeyspace unmarshal failed: %v", data))
+					return
+				}
+				e.exists = true
+				e.version = version
+				e.srvKeyspace = srvKeyspace
+			case IsErrType(err, NoNode):
+			default:
+				rec.RecordError(err)
+				return
+			}
+			e.read = true
+		}(e)
+	}
+	wg.Wait()
+	return rec.Error()
+}
+
+// UpdateSrvKeyspace stages a new SrvKeyspace for a cell. If the
+// SrvKeyspace was read in the Txn, it is written at the version it was
+// read at, or created if it didn't exist. Otherwise it is a blind write.
+func (txn *Txn) UpdateSrvKeyspace(cell, keyspace string, srvKeyspace *topodatapb.SrvKeyspace) {
+	e, _ := txn.entry(cell, srvKeyspaceFileName(keyspace))
+	e.srvKeyspace = srvKeyspace
+	e.dirty = true
+}
+
+// UpdateDisableQueryService is the transactional version of
+// Server.UpdateDisableQueryService. The caller must hold the keyspace
+// lock. If some cells can't be read, the others are staged, and it
+// returns ErrPartialResult.
+func (txn *Txn) UpdateDisableQueryService(ctx context.Context, keyspace string, shards []*ShardInfo, tabletType topodatapb.TabletType, cells []string, disableQueryService bool) (err error) {
+	// The caller intends to update all cells in this case
+	if len(cells) == 0 {
+		cells, err = txn.ts.GetCellInfoNames(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := checkDisableQueryService(shards, tabletType, disableQueryService); err != nil {
+		return err
+	}
+
+	srvKeyspaces, err := txn.GetSrvKeyspaces(ctx, keyspace, cells)
+	if err != nil && !IsErrType(err, PartialResult) {
+		return err
+	}
+	for _, cell := range cells {
+		srvKeyspace, ok := srvKeyspaces[cell]
+		if !ok {
+			continue
+		}
+		setDisableQueryService(srvKeyspace, shards, tabletType, disableQueryService)
+		txn.UpdateSrvKeyspace(cell, keyspace, srvKeyspace)
+	}
+	return err
+}
+
+// MigrateServedType is the transactional version of
+// Server.MigrateServedType. The caller must hold the keyspace lock. If
+// some cells can't be read, the others are staged, and it returns
+// ErrPartialResult.
+func (txn *Txn) MigrateServedType(ctx context.Context, keyspace string, shardsToAdd, shardsToRemove []*ShardInfo, tabletType topodatapb.TabletType, cells []string) (err error) {
+	// The caller intents to update all cells in this case
+	if len(cells) == 0 {
+		cells, err = txn.ts.GetCellInfoNames(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	srvKeyspaces, err := txn.GetSrvKeyspaces(ctx, keyspace, cells)
+	if err != nil && !IsErrType(err, PartialResult) {
+		return err
+	}
+	for _, cell := range cells {
+		// Cells without a SrvKeyspace are not active, nothing to do.
+		srvKeyspace, ok := srvKeyspaces[cell]
+		if !ok {
+			continue
+		}
+		if err := migrateServedType(cell, srvKeyspace, shardsToAdd, shardsToRemove, tabletType); err != nil {
+			return err
+		}
+		txn.UpdateSrvKeyspace(cell, keyspace, srvKeyspace)
+	}
+	return err
+}
+
+// Commit writes all the changes staged in the Txn atomically.
+// If one of the records changed since it was read, it returns
+// ErrBadVersion, or ErrNodeExists if it was created, and nothing is
+// written.
+func (txn *Txn) Commit(ctx context.Context) error {
+	span, ctx := trace.NewSpan(ctx, "TopoServer.CommitTxn")
+	defer span.Finish()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var ops []*TxnOp
+	var entries []*txnEntry
+	for _, e := range txn.order {
+		if !e.dirty {
+			continue
+		}
+		var data []byte
+		var err error
+		if e.shard != nil {
+			data, err = e.shard.Shard.MarshalVT()
+		} else {
+			data, err = e.srvKeyspace.MarshalVT()
+		}
+		if err != nil {
+			return err
+		}
+		op := &TxnOp{Type: TxnUpdate, Cell: e.cell, Path: e.path, Contents: data}
+		switch {
+		case e.read && e.exists:
+			op.Version = e.version
+		case e.read:
+			op.Type = TxnCreate
+		}
+		ops = append(ops, op)
+		entries = append(entries, e)
+	}
+	span.Annotate("ops", len(ops))
+	if len(ops) == 0 {
+		return nil
+	}
+
+	versions, err := txn.ts.ApplyTxnOps(ctx, ops)
+	if err != nil {
+		return err
+	}
+	for i, e := range entries {
+		e.read = true
+		e.exists = true
+		e.version = versions[i]
+		e.dirty = false
+		if e.shard != nil {
+			e.shard.version = versions[i]
+			event.Dispatch(&events.ShardChange{
+				KeyspaceName: e.shard.Keyspace(),
+				ShardName:    e.shard.ShardName(),
+				Shard:        e.shard.Shard,
+				Status:       "updated",
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,368 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltopo
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+)
+
+const (
+	// maxRows is the maximum number of rows a query can return.
+	maxRows = math.MaxInt32
+
+	// maxIdleConns is the number of idle connections a Server keeps.
+	maxIdleConns = 4
+
+	// maxIdleTime is the time after which idle connections are closed
+	// instead of reused, so we don't run into the server timeouts.
+	maxIdleTime = 1 * time.Minute
+
+	// purgeInterval is the number of revisions between two purges of
+	// the change log.
+	purgeInterval = 100
+)
+
+// schema creates the tables. The statements stick to the subset of SQL
+// most MySQL-compatible databases understand.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS topo_data (
+  path VARBINARY(767) NOT NULL,
+  data LONGBLOB NOT NULL,
+  version BIGINT NOT NULL,
+  expiration BIGINT NOT NULL,
+  PRIMARY KEY (path)
+)`,
+	`CREATE TABLE IF NOT EXISTS topo_revision (
+  id INT NOT NULL,
+  revision BIGINT NOT NULL,
+  PRIMARY KEY (id)
+)`,
+	`CREATE TABLE IF NOT EXISTS topo_changes (
+  revision BIGINT NOT NULL,
+  path VARBINARY(767) NOT NULL,
+  data LONGBLOB NOT NULL,
+  deleted TINYINT NOT NULL,
+  created BIGINT NOT NULL,
+  PRIMARY KEY (revision, path)
+)`,
+}
+
+// row is a row of topo_data.
+type row struct {
+	path    string
+	data    []byte
+	version int64
+	// expiration is the time in nanoseconds after which the row is
+	// ignored, or 0 if it doesn't expire.
+	expiration int64
+}
+
+// change is a row of topo_changes.
+type change struct {
+	revision int64
+	path     string
+	data     []byte
+	deleted  bool
+}
+
+// encodeBytes returns b as a hexadecimal literal, so no escaping is
+// needed.
+func encodeBytes(b []byte) string {
+	return "X'" + hex.EncodeToString(b) + "'"
+}
+
+// encodeString returns s as a hexadecimal literal.
+func encodeString(s string) string {
+	return encodeBytes([]byte(s))
+}
+
+// prefixEnd returns the smallest string greater than all the strings
+// starting with prefix, or "" if there is none.
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return ""
+}
+
+// pathCondition returns the condition matching key, or all the paths
+// starting with key if prefix is set.
+func pathCondition(key string, prefix bool) string {
+	if !prefix {
+		return "path = " + encodeString(key)
+	}
+	end := prefixEnd(key)
+	if end == "" {
+		return "path >= " + encodeString(key)
+	}
+	return fmt.Sprintf("path >= %s AND path < %s", encodeString(key), encodeString(end))
+}
+
+// liveCondition returns the condition matching the rows that have not
+// expired at now.
+func liveCondition(now int64) string {
+	return fmt.Sprintf("(expiration = 0 OR expiration > %d)", now)
+}
+
+// execute runs a single statement on conn.
+func execute(conn *mysql.Conn, query string) (*sqltypes.Result, error) {
+	return conn.ExecuteFetch(query, maxRows, false)
+}
+
+// getConn returns an idle connection, or a new one. The first new
+// connection makes sure the tables exist.
+func (s *Server) getConn(ctx context.Context) (*mysql.Conn, error) {
+	s.mu.Lock()
+	for len(s.idle) > 0 {
+		ic := s.idle[len(s.idle)-1]
+		s.idle = s.idle[:len(s.idle)-1]
+		if time.Since(ic.since) < maxIdleTime {
+			s.mu.Unlock()
+			return ic.conn, nil
+		}
+		ic.conn.Close()
+	}
+	schemaReady := s.schemaReady
+	s.mu.Unlock()
+
+	conn, err := mysql.Connect(ctx, s.params)
+	if err != nil {
+		return nil, err
+	}
+	if !schemaReady {
+		if err := initSchema(conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		s.mu.Lock()
+		s.schemaReady = true
+		s.mu.Unlock()
+	}
+	return conn, nil
+}
+
+// putConn returns conn to the idle pool, unless err says it is broken.
+func (s *Server) putConn(conn *mysql.Conn, err error) {
+	if err != nil && sqlerror.IsConnErr(err) || conn.IsClosed() {
+		conn.Close()
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || len(s.idle) >= maxIdleConns {
+		conn.Close()
+		return
+	}
+	s.idle = append(s.idle, idleConn{conn: conn, since: time.Now()})
+}
+
+// initSchema creates the tables if needed, and the row that holds the
+// revision of the key space.
+func initSchema(conn *mysql.Conn) error {
+	for _, query := range schema {
+		if _, err := execute(conn, query); err != nil {
+			return err
+		}
+	}
+	exists := func() (bool, error) {
+		qr, err := execute(conn, "SELECT revision FROM topo_revision WHERE id = 1")
+		if err != nil {
+			return false, err
+		}
+		return len(qr.Rows) == 1, nil
+	}
+	ok, err := exists()
+	if err != nil || ok {
+		return err
+	}
+	if _, err := execute(conn, "INSERT INTO topo_revision (id, revision) VALUES (1, 0)"); err != nil {
+		// Another process may have inserted it concurrently.
+		if ok, _ := exists(); ok {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// withConn runs f on a pooled connection. Errors are not converted.
+func (s *Server) withConn(ctx context.Context, f func(conn *mysql.Conn) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	conn, err := s.getConn(ctx)
+	if err != nil {
+		return err
+	}
+	err = f(conn)
+	s.putConn(conn, err)
+	return err
+}
+
+// transaction runs f in a transaction, which is committed if f returns
+// no error. Errors are not converted.
+func (s *Server) transaction(ctx context.Context, f func(conn *mysql.Conn) error) error {
+	return s.withConn(ctx, func(conn *mysql.Conn) error {
+		if _, err := execute(conn, "BEGIN"); err != nil {
+			return err
+		}
+		if err := f(conn); err != nil {
+			if _, rerr := execute(conn, "ROLLBACK"); rerr != nil {
+				log.Warningf("sqltopo: rollback failed: %v", rerr)
+				conn.Close()
+			}
+			return err
+		}
+		_, err := execute(conn, "COMMIT")
+		return err
+	})
+}
+
+// write runs f in a transaction that increments the revision of the key
+// space first, and returns the new revision. Holding the revision row
+// serializes all the mutations, so they commit in revision order.
+// Errors are converted for nodePath.
+func (s *Server) write(ctx context.Context, nodePath string, f func(conn *mysql.Conn, revision int64, now int64) error) (int64, error) {
+	var revision int64
+	err := s.transaction(ctx, func(conn *mysql.Conn) error {
+		if _, err := execute(conn, "UPDATE topo_revision SET revision = revision + 1 WHERE id = 1"); err != nil {
+			return err
+		}
+		var err error
+		if revision, err = readRevision(conn); err != nil {
+			return err
+		}
+		now := time.Now().UnixNano()
+		if err := f(conn, revision, now); err != nil {
+			return err
+		}
+		if revision%purgeInterval == 0 {
+			_, err := execute(conn, fmt.Sprintf("DELETE FROM topo_changes WHERE created < %d", now-changeLogRetention.Nanoseconds()))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, convertError(err, nodePath)
+	}
+	return revision, nil
+}
+
+// readRevision returns the revision of the key space.
+func readRevision(conn *mysql.Conn) (int64, error) {
+	qr, err := execute(conn, "SELECT revision FROM topo_revision WHERE id = 1")
+	if err != nil {
+		return 0, err
+	}
+	if len(qr.Rows) != 1 {
+		return 0, fmt.Errorf("sqltopo: missing revision row")
+	}
+	return qr.Rows[0][0].ToInt64()
+}
+
+// readRows returns the rows matching key, or starting with key if prefix
+// is set, that have not expired at now, in path order.
+func readRows(conn *mysql.Conn, key string, prefix bool, now int64) ([]row, error) {
+	qr, err := execute(conn, fmt.Sprintf("SELECT path, data, version, expiration FROM topo_data WHERE %s AND %s ORDER BY path", pathCondition(key, prefix), liveCondition(now)))
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]row, 0, len(qr.Rows))
+	for _, r := range qr.Rows {
+		version, err := r[2].ToInt64()
+		if err != nil {
+			return nil, err
+		}
+		expiration, err := r[3].ToInt64()
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row{
+			path:       r[0].ToString(),
+			data:       r[1].Raw(),
+			version:    version,
+			expiration: expiration,
+		})
+	}
+	return rows, nil
+}
+
+// readRow returns the live row at nodePath, or nil if there is none.
+func readRow(conn *mysql.Conn, nodePath string, now int64) (*row, error) {
+	rows, err := readRows(conn, nodePath, false, now)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return &rows[0], nil
+}
+
+// rangeRows returns the live rows matching key, or starting with key if
+// prefix is set. Errors are converted.
+func (s *Server) rangeRows(ctx context.Context, key string, prefix bool) ([]row, error) {
+	var rows []row
+	err := s.withConn(ctx, func(conn *mysql.Conn) error {
+		var err error
+		rows, err = readRows(conn, key, prefix, time.Now().UnixNano())
+		return err
+	})
+	return rows, convertError(err, key)
+}
+
+// rangeRowsAt is like rangeRows, but it also returns the revision of the
+// key space the rows were read at. Errors are converted.
+func (s *Server) rangeRowsAt(ctx context.Context, key string, prefix bool) ([]row, int64, error) {
+	var rows []row
+	var revision int64
+	err := s.transaction(ctx, func(conn *mysql.Conn) error {
+		var err error
+		if revision, err = readRevision(conn); err != nil {
+			return err
+		}
+		rows, err = readRows(conn, key, prefix, time.Now().UnixNano())
+		return err
+	})
+	return rows, revision, convertError(err, key)
+}
+
+// insertRow inserts r in topo_data.
+func insertRow(conn *mysql.Conn, r row) error {
+	_, err := execute(conn, fmt.Sprintf("INSERT INTO topo_data (path, data, version, expiration) VALUES (%s, %s, %d, %d)", encodeString(r.path), encodeBytes(r.data), r.version, r.expiration))
+	return err
+}
+
+// logChange appends a change to the change log.
+func logChange(conn *mysql.Conn, c change, now int64) error {
+	deleted := 0
+	if c.deleted {
+		deleted = 1
+	}
+	_, err := execute(conn, fmt.Sprintf("INSERT INTO topo_changes (revision, path, data, deleted, created) VALUES (%d, %s, %s, %d, %d)", c.revision, encodeString(c.path), encodeBytes(c.data), deleted, now))
+	return err
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltopo
+
+import (
+	"context"
+	"errors"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// convertError converts an error returned by the MySQL client into a
+// topo error. Errors that are already topo errors are returned as is.
+func convertError(err error, nodePath string) error {
+	if err == nil {
+		return nil
+	}
+
+	var topoErr topo.Error
+	if errors.As(err, &topoErr) {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return topo.NewError(topo.Interrupted, nodePath)
+	case errors.Is(err, context.DeadlineExceeded):
+		return topo.NewError(topo.Timeout, nodePath)
+	}
+
+	var sqlErr *sqlerror.SQLError
+	if errors.As(err, &sqlErr) {
+		switch sqlErr.Number() {
+		case sqlerror.ERLockWaitTimeout, sqlerror.ERLockDeadlock:
+			// Another mutation held the revision row for too long.
+			return topo.NewError(topo.Timeout, nodePath)
+		}
+		if sqlerror.IsConnErr(sqlErr) {
+			// We cannot reach the database, which is a timeout from
+			// the topo point of view.
+			return topo.NewError(topo.Timeout, nodePath)
+		}
+	}
+	return err
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltopo
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// Create is part of the topo.Conn interface.
+func (s *Server) Create(ctx context.Context, filePath string, contents []byte) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	revision, err := s.write(ctx, nodePath, func(conn *mysql.Conn, revision, now int64) error {
+		r, err := readRow(conn, nodePath, now)
+		if err != nil {
+			return err
+		}
+		if r != nil {
+			return topo.NewError(topo.NodeExists, nodePath)
+		}
+		if err := insertRow(conn, row{path: nodePath, data: contents, version: revision}); err != nil {
+			return err
+		}
+		return logChange(conn, change{revision: revision, path: nodePath, data: contents}, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return SQLVersion(revision), nil
+}
+
+// Update is part of the topo.Conn interface.
+func (s *Server) Update(ctx context.Context, filePath string, contents []byte, version topo.Version) (topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	revision, err := s.write(ctx, nodePath, func(conn *mysql.Conn, revision, now int64) error {
+		r, err := readRow(conn, nodePath, now)
+		if err != nil {
+			return err
+		}
+		if version != nil {
+			// The update is only applied if the current file
+			// version is what we expect.
+			if r == nil || r.version != int64(version.(SQLVersion)) {
+				return topo.NewError(topo.BadVersion, nodePath)
+			}
+		}
+		if r == nil {
+			err = insertRow(conn, row{path: nodePath, data: contents, version: revision})
+		} else {
+			err = updateRow(conn, nodePath, contents, revision)
+		}
+		if err != nil {
+			return err
+		}
+		return logChange(conn, change{revision: revision, path: nodePath, data: contents}, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return SQLVersion(revision), nil
+}
+
+// Get is part of the topo.Conn interface.
+func (s *Server) Get(ctx context.Context, filePath string) ([]byte, topo.Version, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	rows, err := s.rangeRows(ctx, nodePath, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rows) != 1 {
+		return nil, nil, topo.NewError(topo.NoNode, nodePath)
+	}
+	return rows[0].data, SQLVersion(rows[0].version), nil
+}
+
+// GetVersion is part of the topo.Conn interface.
+// Past versions are read from the change log, so they are only available
+// for --topo_sql_change_log_retention.
+func (s *Server) GetVersion(ctx context.Context, filePath string, version int64) ([]byte, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	var data []byte
+	err := s.withConn(ctx, func(conn *mysql.Conn) error {
+		qr, err := execute(conn, fmt.Sprintf("SELECT data FROM topo_changes WHERE revision = %d AND path = %s AND deleted = 0", version, encodeString(nodePath)))
+		if err != nil {
+			return err
+		}
+		if len(qr.Rows) != 1 {
+			return topo.NewError(topo.NoNode, fmt.Sprintf("%s:%d", nodePath, version))
+		}
+		data = qr.Rows[0][0].Raw()
+		return nil
+	})
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	return data, nil
+}
+
+// List is part of the topo.Conn interface.
+func (s *Server) List(ctx context.Context, filePathPrefix string) ([]topo.KVInfo, error) {
+	nodePathPrefix := path.Join(s.root, filePathPrefix)
+
+	rows, err := s.rangeRows(ctx, nodePathPrefix, true)
+	if err != nil {
+		return []topo.KVInfo{}, err
+	}
+	if len(rows) == 0 {
+		return []topo.KVInfo{}, topo.NewError(topo.NoNode, nodePathPrefix)
+	}
+	results := make([]topo.KVInfo, len(rows))
+	for n, r := range rows {
+		results[n].Key = []byte(r.path)
+		results[n].Value = r.data
+		results[n].Version = SQLVersion(r.version)
+	}
+	return results, nil
+}
+
+// Delete is part of the topo.Conn interface.
+func (s *Server) Delete(ctx context.Context, filePath string, version topo.Version) error {
+	nodePath := path.Join(s.root, filePath)
+
+	_, err := s.write(ctx, nodePath, func(conn *mysql.Conn, revision, now int64) error {
+		r, err := readRow(conn, nodePath, now)
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			return topo.NewError(topo.NoNode, nodePath)
+		}
+		if version != nil && r.version != int64(version.(SQLVersion)) {
+			return topo.NewError(topo.BadVersion, nodePath)
+		}
+		return deleteRow(conn, nodePath, revision, now)
+	})
+	return err
+}
+
+// deleteRow deletes the row at nodePath, and logs the change.
+func deleteRow(conn *mysql.Conn, nodePath string, revision, now int64) error {
+	if _, err := execute(conn, "DELETE FROM topo_data WHERE path = "+encodeString(nodePath)); err != nil {
+		return err
+	}
+	return logChange(conn, change{revision: revision, path: nodePath, deleted: true}, now)
+}
+
+// updateRow sets the contents of the row at nodePath, at revision.
+func updateRow(conn *mysql.Conn, nodePath string, contents []byte, revision int64) error {
+	_, err := execute(conn, fmt.Sprintf("UPDATE topo_data SET data = %s, version = %d WHERE path = %s", encodeBytes(contents), revision, encodeString(nodePath)))
+	return err
+}
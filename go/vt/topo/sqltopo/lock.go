@@ -0,0 +1,289 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltopo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// refreshExpiration pushes back the expiration of the lock row at key,
+// unless it already expired. It returns a NoNode error if the lock is
+// gone.
+func (s *Server) refreshExpiration(ctx context.Context, key string, ttl time.Duration) error {
+	err := s.withConn(ctx, func(conn *mysql.Conn) error {
+		now := time.Now().UnixNano()
+		qr, err := execute(conn, fmt.Sprintf("UPDATE topo_data SET expiration = %d WHERE path = %s AND expiration > %d", now+ttl.Nanoseconds(), encodeString(key), now))
+		if err != nil {
+			return err
+		}
+		if qr.RowsAffected == 0 {
+			return topo.NewError(topo.NoNode, key)
+		}
+		return nil
+	})
+	return convertError(err, key)
+}
+
+// deleteExpired deletes the rows starting with prefix that have expired,
+// so the processes watching them see them go.
+func (s *Server) deleteExpired(ctx context.Context, prefix string) error {
+	var expired []string
+	err := s.withConn(ctx, func(conn *mysql.Conn) error {
+		qr, err := execute(conn, fmt.Sprintf("SELECT path FROM topo_data WHERE %s AND expiration != 0 AND expiration <= %d", pathCondition(prefix, true), time.Now().UnixNano()))
+		if err != nil {
+			return err
+		}
+		for _, r := range qr.Rows {
+			expired = append(expired, r[0].ToString())
+		}
+		return nil
+	})
+	if err != nil || len(expired) == 0 {
+		return convertError(err, prefix)
+	}
+
+	_, err = s.write(ctx, prefix, func(conn *mysql.Conn, revision, now int64) error {
+		for _, key := range expired {
+			// The owner may have refreshed the row since we read it,
+			// so the condition is evaluated again.
+			qr, err := execute(conn, fmt.Sprintf("DELETE FROM topo_data WHERE path = %s AND expiration != 0 AND expiration <= %d", encodeString(key), now))
+			if err != nil {
+				return err
+			}
+			if qr.RowsAffected == 0 {
+				continue
+			}
+			log.Infof("sqltopo: deleted expired %v", key)
+			if err := logChange(conn, change{revision: revision, path: key, deleted: true}, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// waitOnLastRev waits on the file in the provided directory that has
+// the highest version smaller than the provided revision.
+// It returns true only if there is no more other older files.
+func (s *Server) waitOnLastRev(ctx context.Context, nodePath string, revision int64) (bool, error) {
+	if err := s.deleteExpired(ctx, nodePath+"/"); err != nil {
+		return false, err
+	}
+
+	// Get the file that is blocking us, if any.
+	rows, err := s.rangeRows(ctx, nodePath+"/", true)
+	if err != nil {
+		return false, err
+	}
+	var blocking *row
+	for i, r := range rows {
+		if r.version < revision && (blocking == nil || r.version > blocking.version) {
+			blocking = &rows[i]
+		}
+	}
+	if blocking == nil {
+		// No older file, we're done waiting.
+		return true, nil
+	}
+
+	// Wait until the blocking file is gone, or has expired.
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false, convertError(ctx.Err(), nodePath)
+		case <-s.running:
+			return false, topo.NewError(topo.Interrupted, nodePath)
+		case <-ticker.C:
+		}
+		rows, err := s.rangeRows(ctx, blocking.path, false)
+		if err != nil {
+			return false, err
+		}
+		if len(rows) == 0 || rows[0].version != blocking.version {
+			// There might still be older files, but not this one.
+			return false, nil
+		}
+	}
+}
+
+// sqlLockDescriptor implements topo.LockDescriptor.
+type sqlLockDescriptor struct {
+	s       *Server
+	key     string
+	ttl     time.Duration
+	stop    func()
+	stopped chan struct{}
+}
+
+// TryLock is part of the topo.Conn interface.
+func (s *Server) TryLock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list all the entries under dirPath
+	entries, err := s.ListDir(ctx, dirPath, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// If there is a folder '/locks' with some entries in it then we can assume that someone else already has a lock.
+	// Throw error in this case
+	for _, e := range entries {
+		if e.Name == locksPath && e.Type == topo.TypeDirectory && e.Ephemeral {
+			return nil, topo.NewError(topo.NodeExists, fmt.Sprintf("lock already exists at path %s", dirPath))
+		}
+	}
+
+	// everything is good let's acquire the lock.
+	return s.lock(ctx, dirPath, contents, lockTTL)
+}
+
+// Lock is part of the topo.Conn interface.
+func (s *Server) Lock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		return nil, err
+	}
+
+	return s.lock(ctx, dirPath, contents, lockTTL)
+}
+
+// LockWithTTL is part of the topo.Conn interface.
+func (s *Server) LockWithTTL(ctx context.Context, dirPath, contents string, ttl time.Duration) (topo.LockDescriptor, error) {
+	// We list the directory first to make sure it exists.
+	if _, err := s.ListDir(ctx, dirPath, false /*full*/); err != nil {
+		return nil, err
+	}
+
+	return s.lock(ctx, dirPath, contents, ttl)
+}
+
+// LockName is part of the topo.Conn interface.
+func (s *Server) LockName(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	return s.lock(ctx, dirPath, contents, topo.NamedLockTTL)
+}
+
+// lock is used by both Lock() and primary election.
+func (s *Server) lock(ctx context.Context, nodePath, contents string, ttl time.Duration) (topo.LockDescriptor, error) {
+	nodePath = path.Join(s.root, nodePath, locksPath)
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+
+	// Create an expiring file in the locks directory. Use the revision
+	// of the mutation as the file name, so it's guaranteed unique.
+	var key string
+	revision, err := s.write(ctx, nodePath, func(conn *mysql.Conn, revision, now int64) error {
+		key = fmt.Sprintf("%v/%v", nodePath, revision)
+		if err := insertRow(conn, row{path: key, data: []byte(contents), version: revision, expiration: now + ttl.Nanoseconds()}); err != nil {
+			return err
+		}
+		return logChange(conn, change{revision: revision, path: key, data: []byte(contents)}, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ld := s.newLockDescriptor(key, ttl)
+
+	// Wait until all older files in the locks directory are gone.
+	for {
+		done, err := s.waitOnLastRev(ctx, nodePath, revision)
+		if err != nil {
+			// We had an error waiting on the last file.
+			// Delete ours.
+			if uerr := ld.Unlock(context.Background()); uerr != nil {
+				log.Warningf("failed to delete %v, it will expire: %v", key, uerr)
+			}
+			return nil, err
+		}
+		if done {
+			// No more older files, we're it!
+			return ld, nil
+		}
+	}
+}
+
+// newLockDescriptor returns the descriptor of the lock at key, and
+// refreshes its expiration three times per TTL until it is unlocked.
+func (s *Server) newLockDescriptor(key string, ttl time.Duration) *sqlLockDescriptor {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		interval := ttl / 3
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-s.running:
+				return
+			case <-ticker.C:
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			err := s.refreshExpiration(ctx, key, ttl)
+			cancel()
+			if topo.IsErrType(err, topo.NoNode) {
+				log.Warningf("lock %v is gone, stopping its refresh", key)
+				return
+			}
+			if err != nil {
+				log.Warningf("failed to refresh lock %v: %v", key, err)
+			}
+		}
+	}()
+	var once sync.Once
+	return &sqlLockDescriptor{
+		s:       s,
+		key:     key,
+		ttl:     ttl,
+		stop:    func() { once.Do(func() { close(done) }) },
+		stopped: stopped,
+	}
+}
+
+// Check is part of the topo.LockDescriptor interface.
+// We refresh the expiration to make sure the lock is still ours.
+func (ld *sqlLockDescriptor) Check(ctx context.Context) error {
+	return ld.s.refreshExpiration(ctx, ld.key, ld.ttl)
+}
+
+// Unlock is part of the topo.LockDescriptor interface.
+func (ld *sqlLockDescriptor) Unlock(ctx context.Context) error {
+	ld.stop()
+	<-ld.stopped
+	_, err := ld.s.write(ctx, ld.key, func(conn *mysql.Conn, revision, now int64) error {
+		qr, err := execute(conn, "SELECT path FROM topo_data WHERE path = "+encodeString(ld.key))
+		if err != nil {
+			return err
+		}
+		if len(qr.Rows) == 0 {
+			return topo.NewError(topo.NoNode, ld.key)
+		}
+		return deleteRow(conn, ld.key, revision, now)
+	})
+	return err
+}
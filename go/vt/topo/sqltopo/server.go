@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package sqltopo implements topo.Server with a MySQL-compatible database
+as the backend, for deployments that would rather operate a database
+than a dedicated coordination service.
+
+The data lives in three tables, created on first use:
+
+  - topo_data holds one row per file, with its contents and version.
+    Locks and elections use rows that have an expiration, which their
+    owner refreshes periodically. Expired rows are ignored by reads,
+    and deleted by the next process that waits on them.
+  - topo_revision holds the revision of the whole key space. Every
+    mutation increments it in the same transaction, which serializes the
+    mutations. The revision a file was last modified at is its version.
+  - topo_changes is the change log: every mutation appends the new
+    contents of the files it changed, at its revision. Watches poll it,
+    and GetVersion reads past versions from it. Entries older than
+    --topo_sql_change_log_retention are purged.
+
+The server address has the form [user[:password]@]host:port/dbname.
+
+We follow these conventions within this package:
+
+  - Call convertError(err) on any errors returned from the MySQL client.
+    Functions defined in this package can be assumed to have already
+    converted errors as necessary.
+*/
+package sqltopo
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+var (
+	// lockTTL is the time after which the locks of a process that
+	// stopped refreshing them expire.
+	lockTTL = 30 * time.Second
+
+	// watchPollInterval is the interval at which watches read the
+	// change log.
+	watchPollInterval = 1 * time.Second
+
+	// changeLogRetention is how long entries are kept in the change log.
+	changeLogRetention = 1 * time.Hour
+)
+
+func init() {
+	for _, cmd := range topo.FlagBinaries {
+		servenv.OnParseFor(cmd, registerSQLTopoFlags)
+	}
+	topo.RegisterFactory("sql", Factory{})
+}
+
+func registerSQLTopoFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&lockTTL, "topo_sql_lock_ttl", lockTTL, "Time after which the locks and leader election entries of a process that stopped refreshing them expire in the sql topo server.")
+	fs.DurationVar(&watchPollInterval, "topo_sql_watch_poll_interval", watchPollInterval, "Interval at which watches read the change log of the sql topo server.")
+	fs.DurationVar(&changeLogRetention, "topo_sql_change_log_retention", changeLogRetention, "How long changes are kept in the change log of the sql topo server. Watches that fall further behind fail.")
+}
+
+// Factory is the sqltopo topo.Factory implementation.
+type Factory struct{}
+
+// HasGlobalReadOnlyCell is part of the topo.Factory interface.
+func (f Factory) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+// Create is part of the topo.Factory interface.
+func (f Factory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	return NewServer(serverAddr, root)
+}
+
+// Server is the implementation of topo.Conn for sqltopo.
+type Server struct {
+	// root is the root path for this client.
+	root string
+
+	// params are the connection parameters of the database.
+	params *mysql.ConnParams
+
+	running chan struct{}
+
+	mu sync.Mutex
+	// idle are the connections ready to be reused.
+	idle []idleConn
+	// schemaReady is set once we made sure the tables exist.
+	schemaReady bool
+	// closed is set by Close.
+	closed bool
+}
+
+// idleConn is a connection in the idle pool of a Server.
+type idleConn struct {
+	conn  *mysql.Conn
+	since time.Time
+}
+
+// NewServer returns a new sqltopo.Server. It doesn't connect to the
+// database until the first operation.
+func NewServer(serverAddr, root string) (*Server, error) {
+	params, err := parseServerAddr(serverAddr)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		root:    root,
+		params:  params,
+		running: make(chan struct{}),
+	}, nil
+}
+
+// parseServerAddr parses [user[:password]@]host:port/dbname.
+func parseServerAddr(serverAddr string) (*mysql.ConnParams, error) {
+	params := &mysql.ConnParams{}
+	addr := serverAddr
+	if i := strings.LastIndex(addr, "@"); i >= 0 {
+		params.Uname, params.Pass, _ = strings.Cut(addr[:i], ":")
+		addr = addr[i+1:]
+	}
+	hostPort, dbName, _ := strings.Cut(addr, "/")
+	if dbName == "" {
+		return nil, topo.NewError(topo.NoImplementation, "sqltopo server address must have the form [user[:password]@]host:port/dbname")
+	}
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return nil, topo.NewError(topo.NoImplementation, "invalid sqltopo server address: "+err.Error())
+	}
+	params.Host = host
+	if params.Port, err = strconv.Atoi(port); err != nil {
+		return nil, topo.NewError(topo.NoImplementation, "invalid sqltopo server port: "+port)
+	}
+	params.DbName = dbName
+	return params, nil
+}
+
+// Close implements topo.Server.Close.
+func (s *Server) Close() {
+	close(s.running)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for _, ic := range s.idle {
+		ic.conn.Close()
+	}
+	s.idle = nil
+}
@@ -0,0 +1,444 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltopo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/env"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/test"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// sqliteHandler is a mysql.Handler that runs the queries against a
+// SQLite database, so the tests don't need a MySQL server. The queries
+// of this package are in the subset of SQL both understand.
+type sqliteHandler struct {
+	mysql.UnimplementedHandler
+	db  *sql.DB
+	env *vtenv.Environment
+}
+
+// startTestServer starts a MySQL protocol server backed by a new
+// SQLite database, and returns its address.
+func startTestServer(t *testing.T) string {
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(30000)&_pragma=journal_mode(WAL)", filepath.Join(t.TempDir(), "topo.db"))
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	h := &sqliteHandler{db: db, env: vtenv.NewTestEnv()}
+
+	listener, err := mysql.NewListener("tcp", "127.0.0.1:0", mysql.NewAuthServerNone(), h, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	go listener.Accept()
+	t.Cleanup(func() {
+		listener.Close()
+		db.Close()
+	})
+	return fmt.Sprintf("user@%s/vt_topo", listener.Addr().String())
+}
+
+// Env is part of the mysql.Handler interface.
+func (h *sqliteHandler) Env() *vtenv.Environment {
+	return h.env
+}
+
+// ConnectionClosed is part of the mysql.Handler interface.
+func (h *sqliteHandler) ConnectionClosed(c *mysql.Conn) {
+	if conn, ok := c.ClientData.(*sql.Conn); ok {
+		// Don't return a connection in a transaction to the pool.
+		conn.ExecContext(context.Background(), "ROLLBACK")
+		conn.Close()
+	}
+}
+
+// ComQueryMulti is part of the mysql.Handler interface.
+func (h *sqliteHandler) ComQueryMulti(c *mysql.Conn, sql string, callback func(qr sqltypes.QueryResponse, more bool, firstPacket bool) error) error {
+	return fmt.Errorf("multi statements are not supported")
+}
+
+// ComPrepare is part of the mysql.Handler interface.
+func (h *sqliteHandler) ComPrepare(*mysql.Conn, string) ([]*querypb.Field, uint16, error) {
+	return nil, 0, fmt.Errorf("prepared statements are not supported")
+}
+
+// ComStmtExecute is part of the mysql.Handler interface.
+func (h *sqliteHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
+	return fmt.Errorf("prepared statements are not supported")
+}
+
+// ComRegisterReplica is part of the mysql.Handler interface.
+func (h *sqliteHandler) ComRegisterReplica(c *mysql.Conn, replicaHost string, replicaPort uint16, replicaUser string, replicaPassword string) error {
+	return fmt.Errorf("replication is not supported")
+}
+
+// ComBinlogDump is part of the mysql.Handler interface.
+func (h *sqliteHandler) ComBinlogDump(c *mysql.Conn, logFile string, binlogPos uint32) error {
+	return fmt.Errorf("replication is not supported")
+}
+
+// ComBinlogDumpGTID is part of the mysql.Handler interface.
+func (h *sqliteHandler) ComBinlogDumpGTID(c *mysql.Conn, logFile string, logPos uint64, gtidSet replication.GTIDSet) error {
+	return fmt.Errorf("replication is not supported")
+}
+
+// WarningCount is part of the mysql.Handler interface.
+func (h *sqliteHandler) WarningCount(c *mysql.Conn) uint16 {
+	return 0
+}
+
+// ComQuery is part of the mysql.Handler interface.
+func (h *sqliteHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	ctx := context.Background()
+	conn, ok := c.ClientData.(*sql.Conn)
+	if !ok {
+		var err error
+		if conn, err = h.db.Conn(ctx); err != nil {
+			return err
+		}
+		c.ClientData = conn
+	}
+
+	lower := strings.ToLower(strings.TrimSpace(query))
+	switch {
+	case strings.HasPrefix(lower, "use "), strings.HasPrefix(lower, "set "):
+		return callback(&sqltypes.Result{})
+	case !strings.HasPrefix(lower, "select"):
+		res, err := conn.ExecContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		return callback(&sqltypes.Result{RowsAffected: uint64(n)})
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	// A column is an integer if all its values are.
+	var values [][]any
+	integral := make([]bool, len(columns))
+	for i := range integral {
+		integral[i] = true
+	}
+	for rows.Next() {
+		vals := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		for i, v := range vals {
+			if _, ok := v.(int64); !ok && v != nil {
+				integral[i] = false
+			}
+		}
+		values = append(values, vals)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	result := &sqltypes.Result{}
+	for i, name := range columns {
+		typ := querypb.Type_VARBINARY
+		if integral[i] {
+			typ = querypb.Type_INT64
+		}
+		result.Fields = append(result.Fields, &querypb.Field{Name: name, Type: typ})
+	}
+	for _, vals := range values {
+		r := make([]sqltypes.Value, len(vals))
+		for i, v := range vals {
+			switch v := v.(type) {
+			case nil:
+				r[i] = sqltypes.NULL
+			case int64:
+				if integral[i] {
+					r[i] = sqltypes.NewInt64(v)
+				} else {
+					r[i] = sqltypes.NewVarBinary(strconv.FormatInt(v, 10))
+				}
+			case []byte:
+				r[i] = sqltypes.NewVarBinary(string(v))
+			default:
+				r[i] = sqltypes.NewVarBinary(fmt.Sprint(v))
+			}
+		}
+		result.Rows = append(result.Rows, r)
+	}
+	return callback(result)
+}
+
+// startMySQL starts a mysqld with a vt_topo database, and returns its
+// address. The test is skipped if mysqld is not installed.
+func startMySQL(t *testing.T) string {
+	if _, err := env.VtMysqlRoot(); err != nil {
+		t.Skipf("mysqld is not available: %v", err)
+	}
+	ctx := context.Background()
+
+	// Keep the data root short, the socket path length is limited.
+	dataRoot, err := os.MkdirTemp("", "sqltopo")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dataRoot) })
+	t.Setenv("VTDATAROOT", dataRoot)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	mysqld, cnf, err := mysqlctl.CreateMysqldAndMycnf(1, "", port, collations.MySQL8())
+	require.NoError(t, err)
+	require.NoError(t, mysqld.Init(ctx, cnf, ""))
+	t.Cleanup(func() {
+		if err := mysqld.Teardown(ctx, cnf, true, 30*time.Second); err != nil {
+			t.Logf("mysqld.Teardown failed: %v", err)
+		}
+		mysqld.Close()
+	})
+
+	conn, err := mysql.Connect(ctx, &mysql.ConnParams{Uname: "vt_dba", UnixSocket: cnf.SocketFile})
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.ExecuteFetch("CREATE DATABASE vt_topo", 0, false)
+	require.NoError(t, err)
+	return fmt.Sprintf("vt_dba@127.0.0.1:%d/vt_topo", port)
+}
+
+func TestSQLTopo(t *testing.T) {
+	testSQLTopo(t, startTestServer(t))
+}
+
+// TestSQLTopoMySQL runs the tests against a MySQL server, to cover the
+// locking and the watch polling with the MySQL transaction semantics.
+func TestSQLTopoMySQL(t *testing.T) {
+	serverAddr := startMySQL(t)
+	t.Run("Suite", func(t *testing.T) {
+		testSQLTopo(t, serverAddr)
+	})
+	t.Run("LockExpiration", func(t *testing.T) {
+		testLockExpiration(t, serverAddr)
+	})
+	t.Run("GetVersion", func(t *testing.T) {
+		testGetVersion(t, serverAddr)
+	})
+}
+
+func testSQLTopo(t *testing.T, serverAddr string) {
+	defer func(interval time.Duration) { watchPollInterval = interval }(watchPollInterval)
+	watchPollInterval = 20 * time.Millisecond
+
+	testIndex := 0
+	newServer := func() *topo.Server {
+		// Each test will use its own sub-directories.
+		testRoot := fmt.Sprintf("/test-%v", testIndex)
+		testIndex++
+
+		// Create the server on the new root.
+		ts, err := topo.OpenServer("sql", serverAddr, path.Join(testRoot, topo.GlobalCell))
+		require.NoError(t, err)
+
+		// Create the CellInfo.
+		err = ts.CreateCellInfo(context.Background(), test.LocalCellName, &topodatapb.CellInfo{
+			ServerAddress: serverAddr,
+			Root:          path.Join(testRoot, test.LocalCellName),
+		})
+		require.NoError(t, err)
+		return ts
+	}
+
+	// Run the TopoServerTestSuite tests.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	test.TopoServerTestSuite(t, ctx, func() *topo.Server {
+		return newServer()
+	}, []string{})
+
+	// Run sql-specific tests.
+	ts := newServer()
+	testKeyspaceLock(t, ts)
+
+	// The cells use the same database, so their transactions are atomic.
+	atomic, err := ts.TxnAtomic(ctx, []string{topo.GlobalCell, test.LocalCellName})
+	require.NoError(t, err)
+	require.True(t, atomic)
+	ts.Close()
+}
+
+// testKeyspaceLock tests the refresh of the lock expiration.
+func testKeyspaceLock(t *testing.T, ts *topo.Server) {
+	ctx := context.Background()
+	keyspacePath := path.Join(topo.KeyspacesPath, "test_keyspace")
+	err := ts.CreateKeyspace(ctx, "test_keyspace", &topodatapb.Keyspace{})
+	require.NoError(t, err)
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+
+	// Short TTL, make sure it doesn't expire.
+	defer func(ttl time.Duration) { lockTTL = ttl }(lockTTL)
+	lockTTL = 1 * time.Second
+	lockDescriptor, err := conn.Lock(ctx, keyspacePath, "short ttl")
+	require.NoError(t, err)
+	time.Sleep(2 * time.Second)
+	require.NoError(t, lockDescriptor.Check(ctx))
+	require.NoError(t, lockDescriptor.Unlock(ctx))
+}
+
+func TestSQLTopoLockExpiration(t *testing.T) {
+	testLockExpiration(t, startTestServer(t))
+}
+
+// testLockExpiration checks the lock of a process that stopped
+// refreshing it expires, and that waiters see it go.
+func testLockExpiration(t *testing.T, serverAddr string) {
+	defer func(interval time.Duration) { watchPollInterval = interval }(watchPollInterval)
+	watchPollInterval = 20 * time.Millisecond
+	ctx := context.Background()
+
+	conn1, err := NewServer(serverAddr, "/expiration")
+	require.NoError(t, err)
+	defer conn1.Close()
+	conn2, err := NewServer(serverAddr, "/expiration")
+	require.NoError(t, err)
+	defer conn2.Close()
+
+	_, err = conn1.Create(ctx, "/dir/file", []byte("contents"))
+	require.NoError(t, err)
+	_, changes, err := conn2.WatchRecursive(ctx, "/dir")
+	require.NoError(t, err)
+
+	// Take a lock, and stop refreshing it.
+	ld, err := conn1.lock(ctx, "/dir", "orphan", time.Second)
+	require.NoError(t, err)
+	lockPath := ld.(*sqlLockDescriptor).key
+	ld.(*sqlLockDescriptor).stop()
+
+	// The other process gets the lock once it expired.
+	start := time.Now()
+	ld2, err := conn2.Lock(ctx, "/dir", "waiter")
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+	require.True(t, topo.IsErrType(ld.Check(ctx), topo.NoNode))
+
+	// The watchers saw the lock come and go.
+	var deleted bool
+	for !deleted {
+		select {
+		case wd := <-changes:
+			if wd.Path == lockPath && topo.IsErrType(wd.Err, topo.NoNode) {
+				deleted = true
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the deletion of the expired lock")
+		}
+	}
+	require.NoError(t, ld2.Unlock(ctx))
+}
+
+func TestSQLTopoGetVersion(t *testing.T) {
+	testGetVersion(t, startTestServer(t))
+}
+
+// testGetVersion checks past versions are read from the change log,
+// until they are purged.
+func testGetVersion(t *testing.T, serverAddr string) {
+	ctx := context.Background()
+	conn, err := NewServer(serverAddr, "/versions")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	v1, err := conn.Create(ctx, "/file", []byte("v1"))
+	require.NoError(t, err)
+	_, err = conn.Update(ctx, "/file", []byte("v2"), v1)
+	require.NoError(t, err)
+
+	data, err := conn.GetVersion(ctx, "/file", int64(v1.(SQLVersion)))
+	require.NoError(t, err)
+	require.Equal(t, "v1", string(data))
+	_, err = conn.GetVersion(ctx, "/file", 1000)
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+
+	// With no retention, the next purge empties the change log, and
+	// watches from before fail.
+	defer func(retention time.Duration) { changeLogRetention = retention }(changeLogRetention)
+	changeLogRetention = 0
+	for i := range purgeInterval {
+		_, err := conn.Update(ctx, "/other", []byte(strconv.Itoa(i)), nil)
+		require.NoError(t, err)
+	}
+	_, err = conn.GetVersion(ctx, "/file", int64(v1.(SQLVersion)))
+	require.True(t, topo.IsErrType(err, topo.NoNode))
+	results := conn.watchEvents(ctx, "/versions/file", false, int64(v1.(SQLVersion)))
+	res := <-results
+	require.ErrorContains(t, res.err, "purged")
+}
+
+func TestParseServerAddr(t *testing.T) {
+	params, err := parseServerAddr("vt:secret@db1:3306/vt_topo")
+	require.NoError(t, err)
+	require.Equal(t, "vt", params.Uname)
+	require.Equal(t, "secret", params.Pass)
+	require.Equal(t, "db1", params.Host)
+	require.Equal(t, 3306, params.Port)
+	require.Equal(t, "vt_topo", params.DbName)
+
+	params, err = parseServerAddr("[::1]:3306/vt_topo")
+	require.NoError(t, err)
+	require.Equal(t, "", params.Uname)
+	require.Equal(t, "::1", params.Host)
+
+	for _, addr := range []string{"db1:3306", "db1/vt_topo", "db1:port/vt_topo"} {
+		_, err := parseServerAddr(addr)
+		require.Error(t, err, addr)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltopo
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// TxnBackend is part of the topo.TxnConn interface. The cells that use
+// the same database, with different roots, can be part of the same
+// transaction. The credentials are left out, as the backend is logged.
+func (s *Server) TxnBackend() string {
+	return fmt.Sprintf("sql:%s/%s", net.JoinHostPort(s.params.Host, strconv.Itoa(s.params.Port)), s.params.DbName)
+}
+
+// Txn is part of the topo.TxnConn interface. It applies all the ops in
+// a single database transaction, which increments the revision once, so
+// all the files it changes get the same version.
+func (s *Server) Txn(ctx context.Context, ops []*topo.TxnOp) ([]topo.Version, error) {
+	nodePaths := make([]string, len(ops))
+	for i, op := range ops {
+		root := s.root
+		if op.Conn != nil {
+			conn, ok := op.Conn.(*Server)
+			if !ok || conn.TxnBackend() != s.TxnBackend() {
+				return nil, fmt.Errorf("transaction op on %v is not for the same database", op.Path)
+			}
+			root = conn.root
+		}
+		nodePaths[i] = path.Join(root, op.Path)
+	}
+
+	versions := make([]topo.Version, len(ops))
+	_, err := s.write(ctx, "", func(conn *mysql.Conn, revision, now int64) error {
+		// The revision row is locked, so the rows can't change until
+		// the transaction commits, and any failed op rolls back the
+		// ones before it.
+		for i, op := range ops {
+			nodePath := nodePaths[i]
+			r, err := readRow(conn, nodePath, now)
+			if err != nil {
+				return err
+			}
+			switch op.Type {
+			case topo.TxnCreate:
+				if r != nil {
+					return topo.NewError(topo.NodeExists, nodePath)
+				}
+			case topo.TxnUpdate:
+				if op.Version != nil && (r == nil || r.version != int64(op.Version.(SQLVersion))) {
+					return topo.NewError(topo.BadVersion, nodePath)
+				}
+			case topo.TxnCheck, topo.TxnDelete:
+				if r == nil {
+					return topo.NewError(topo.NoNode, nodePath)
+				}
+				if op.Version != nil && r.version != int64(op.Version.(SQLVersion)) {
+					return topo.NewError(topo.BadVersion, nodePath)
+				}
+			default:
+				return fmt.Errorf("unknown transaction op type %v", op.Type)
+			}
+
+			switch op.Type {
+			case topo.TxnCreate, topo.TxnUpdate:
+				if r == nil {
+					err = insertRow(conn, row{path: nodePath, data: op.Contents, version: revision})
+				} else {
+					err = updateRow(conn, nodePath, op.Contents, revision)
+				}
+				if err != nil {
+					return err
+				}
+				if err := logChange(conn, change{revision: revision, path: nodePath, data: op.Contents}, now); err != nil {
+					return err
+				}
+				versions[i] = SQLVersion(revision)
+			case topo.TxnDelete:
+				if err := deleteRow(conn, nodePath, revision, now); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqltopo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// watchResult is either a batch of changes, or the final error of the
+// watch.
+type watchResult struct {
+	changes []change
+	err     error
+}
+
+// readChanges returns the changes on key, or on all the paths starting
+// with key if prefix is set, after the revision after. It fails if the
+// change log was purged past after.
+func readChanges(conn *mysql.Conn, key string, prefix bool, after int64) ([]change, error) {
+	qr, err := execute(conn, "SELECT MIN(revision) FROM topo_changes")
+	if err != nil {
+		return nil, err
+	}
+	if len(qr.Rows) == 1 && !qr.Rows[0][0].IsNull() {
+		oldest, err := qr.Rows[0][0].ToInt64()
+		if err != nil {
+			return nil, err
+		}
+		// Revisions have no gaps, as a failed mutation doesn't
+		// increment the revision.
+		if oldest > after+1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_OUT_OF_RANGE, "the change log was purged past revision %v", after)
+		}
+	}
+
+	qr, err = execute(conn, fmt.Sprintf("SELECT revision, path, data, deleted FROM topo_changes WHERE revision > %d AND %s ORDER BY revision, path", after, pathCondition(key, prefix)))
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]change, 0, len(qr.Rows))
+	for _, r := range qr.Rows {
+		revision, err := r[0].ToInt64()
+		if err != nil {
+			return nil, err
+		}
+		deleted, err := r[3].ToInt64()
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change{
+			revision: revision,
+			path:     r[1].ToString(),
+			data:     r[2].Raw(),
+			deleted:  deleted != 0,
+		})
+	}
+	return changes, nil
+}
+
+// watchEvents polls the change log for the changes on key, or on all the
+// paths starting with key if prefix is set, from startRevision on. Errors
+// reading the change log are retried at the next poll.
+// The returned channel is closed when ctx is done, when s is closed, or
+// when the change log was purged past the revision we need. In the first
+// and last cases, the last result carries the error.
+func (s *Server) watchEvents(ctx context.Context, key string, prefix bool, startRevision int64) <-chan watchResult {
+	results := make(chan watchResult, 10)
+	go func() {
+		defer close(results)
+
+		after := startRevision - 1
+		ticker := time.NewTicker(watchPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.running:
+				return
+			case <-ctx.Done():
+				results <- watchResult{err: convertError(ctx.Err(), key)}
+				return
+			case <-ticker.C:
+			}
+
+			var changes []change
+			err := s.withConn(ctx, func(conn *mysql.Conn) error {
+				var err error
+				changes, err = readChanges(conn, key, prefix, after)
+				return err
+			})
+			if vterrors.Code(err) == vtrpcpb.Code_OUT_OF_RANGE {
+				results <- watchResult{err: err}
+				return
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Warningf("watch %v failed to read the change log, retrying from revision %v: %v", key, after+1, err)
+				}
+				continue
+			}
+			if len(changes) == 0 {
+				continue
+			}
+			after = changes[len(changes)-1].revision
+			select {
+			case results <- watchResult{changes: changes}:
+			case <-s.running:
+				return
+			case <-ctx.Done():
+				results <- watchResult{err: convertError(ctx.Err(), key)}
+				return
+			}
+		}
+	}()
+	return results
+}
+
+// Watch is part of the topo.Conn interface.
+func (s *Server) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, error) {
+	nodePath := path.Join(s.root, filePath)
+
+	// Get the initial version of the file
+	initialCtx, initialCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer initialCancel()
+	initial, err := s.rangeRows(initialCtx, nodePath, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(initial) != 1 {
+		// Node doesn't exist.
+		return nil, nil, topo.NewError(topo.NoNode, nodePath)
+	}
+	wd := &topo.WatchData{
+		Contents: initial[0].data,
+		Version:  SQLVersion(initial[0].version),
+	}
+
+	// Watch the changes that follow the version we read: it is the
+	// latest change of this file.
+	watchCtx, watchCancel := context.WithCancel(ctx)
+	events := s.watchEvents(watchCtx, nodePath, false, initial[0].version+1)
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchData, 10)
+	go func() {
+		defer close(notifications)
+		defer watchCancel()
+
+		for res := range events {
+			if res.err != nil {
+				// Final notification.
+				notifications <- &topo.WatchData{Err: res.err}
+				return
+			}
+			for _, c := range res.changes {
+				if c.deleted {
+					// Node is gone, send a final notice.
+					notifications <- &topo.WatchData{
+						Err: topo.NewError(topo.NoNode, nodePath),
+					}
+					return
+				}
+				notifications <- &topo.WatchData{
+					Contents: c.data,
+					Version:  SQLVersion(c.revision),
+				}
+			}
+		}
+	}()
+
+	return wd, notifications, nil
+}
+
+// WatchRecursive is part of the topo.Conn interface.
+func (s *Server) WatchRecursive(ctx context.Context, dirpath string) ([]*topo.WatchDataRecursive, <-chan *topo.WatchDataRecursive, error) {
+	nodePath := path.Join(s.root, dirpath)
+	if !strings.HasSuffix(nodePath, "/") {
+		nodePath = nodePath + "/"
+	}
+
+	// Get the initial version of the files, and the revision they were
+	// read at.
+	initial, revision, err := s.rangeRowsAt(ctx, nodePath, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	var initialwd []*topo.WatchDataRecursive
+	for _, r := range initial {
+		initialwd = append(initialwd, &topo.WatchDataRecursive{
+			Path: r.path,
+			WatchData: topo.WatchData{
+				Contents: r.data,
+				Version:  SQLVersion(r.version),
+			},
+		})
+	}
+
+	// Watch the changes that follow the revision we read.
+	watchCtx, watchCancel := context.WithCancel(ctx)
+	events := s.watchEvents(watchCtx, nodePath, true, revision+1)
+
+	// Create the notifications channel, send updates to it.
+	notifications := make(chan *topo.WatchDataRecursive, 10)
+	go func() {
+		defer close(notifications)
+		defer watchCancel()
+
+		for res := range events {
+			if res.err != nil {
+				// Final notification.
+				notifications <- &topo.WatchDataRecursive{
+					WatchData: topo.WatchData{Err: res.err},
+				}
+				return
+			}
+			for _, c := range res.changes {
+				if c.deleted {
+					notifications <- &topo.WatchDataRecursive{
+						Path: c.path,
+						WatchData: topo.WatchData{
+							Err: topo.NewError(topo.NoNode, nodePath),
+						},
+					}
+					continue
+				}
+				notifications <- &topo.WatchDataRecursive{
+					Path: c.path,
+					WatchData: topo.WatchData{
+						Contents: c.data,
+						Version:  SQLVersion(c.revision),
+					},
+				}
+			}
+		}
+	}()
+
+	return initialwd, notifications, nil
+}
@@ -441,19 +441,21 @@ func (ts *Server) UpdateSrvKeyspaceThrottlerConfig(ctx context.Context, keyspace
 
 // UpdateDisableQueryService will make sure the disableQueryService is
 // set appropriately in tablet controls in srvKeyspace.
+// The SrvKeyspaces of the cells are updated in a single transaction. If
+// some cells can't be read, the others are updated, and it returns
+// ErrPartialResult.
 func (ts *Server) UpdateDisableQueryService(ctx context.Context, keyspace string, shards []*ShardInfo, tabletType topodatapb.TabletType, cells []string, disableQueryService bool) (err error) {
 	if err = CheckKeyspaceLocked(ctx, keyspace); err != nil {
 		return err
 	}
+	return retryPartialTxn(ctx, ts, func(txn *Txn) error {
+		return txn.UpdateDisableQueryService(ctx, keyspace, shards, tabletType, cells, disableQueryService)
+	})
+}
 
-	// The caller intends to update all cells in this case
-	if len(cells) == 0 {
-		cells, err = ts.GetCellInfoNames(ctx)
-		if err != nil {
-			return err
-		}
-	}
-
+// checkDisableQueryService checks that the disableQueryService of the
+// tablet controls of shards can be changed.
+func checkDisableQueryService(shards []*ShardInfo, tabletType topodatapb.TabletType, disableQueryService bool) error {
 	for _, shard := range shards {
 		for _, tc := range shard.TabletControls {
 			if len(tc.DeniedTables) > 0 {
@@ -473,156 +475,103 @@ func (ts *Server) UpdateDisableQueryService(ctx context.Context, keyspace string
 			}
 		}
 	}
+	return nil
+}
 
-	wg := sync.WaitGroup{}
-	rec := concurrency.AllErrorRecorder{}
-	for _, cell := range cells {
-		wg.Add(1)
-		go func(cell string) {
-			defer wg.Done()
-			srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, keyspace)
-			switch {
-			case err == nil:
-				for _, partition := range srvKeyspace.GetPartitions() {
-					if partition.GetServedType() != tabletType {
-						continue
-					}
-
-					for _, si := range shards {
-						found := false
-						for _, tabletControl := range partition.GetShardTabletControls() {
-							if key.KeyRangeEqual(tabletControl.GetKeyRange(), si.GetKeyRange()) {
-								found = true
-								tabletControl.QueryServiceDisabled = disableQueryService
-							}
-						}
+// setDisableQueryService sets the disableQueryService of the tablet
+// controls of shards in srvKeyspace.
+func setDisableQueryService(srvKeyspace *topodatapb.SrvKeyspace, shards []*ShardInfo, tabletType topodatapb.TabletType, disableQueryService bool) {
+	for _, partition := range srvKeyspace.GetPartitions() {
+		if partition.GetServedType() != tabletType {
+			continue
+		}
 
-						if !found {
-							shardTabletControl := &topodatapb.ShardTabletControl{
-								Name:                 si.ShardName(),
-								KeyRange:             si.KeyRange,
-								QueryServiceDisabled: disableQueryService,
-							}
-							partition.ShardTabletControls = append(partition.GetShardTabletControls(), shardTabletControl)
-						}
-					}
+		for _, si := range shards {
+			found := false
+			for _, tabletControl := range partition.GetShardTabletControls() {
+				if key.KeyRangeEqual(tabletControl.GetKeyRange(), si.GetKeyRange()) {
+					found = true
+					tabletControl.QueryServiceDisabled = disableQueryService
 				}
+			}
 
-				err = ts.UpdateSrvKeyspace(ctx, cell, keyspace, srvKeyspace)
-				if err != nil {
-					rec.RecordError(err)
-					return
+			if !found {
+				shardTabletControl := &topodatapb.ShardTabletControl{
+					Name:                 si.ShardName(),
+					KeyRange:             si.KeyRange,
+					QueryServiceDisabled: disableQueryService,
 				}
-			case IsErrType(err, NoNode):
-				// NOOP
-			default:
-				rec.RecordError(err)
-				return
+				partition.ShardTabletControls = append(partition.GetShardTabletControls(), shardTabletControl)
 			}
-		}(cell)
-	}
-	wg.Wait()
-	if rec.HasErrors() {
-		return NewError(PartialResult, rec.Error().Error())
+		}
 	}
-	return nil
 }
 
 // MigrateServedType removes/adds shards from srvKeyspace when migrating a served type.
+// The SrvKeyspaces of the cells are updated in a single transaction. If
+// some cells can't be read, the others are updated, and it returns
+// ErrPartialResult.
 func (ts *Server) MigrateServedType(ctx context.Context, keyspace string, shardsToAdd, shardsToRemove []*ShardInfo, tabletType topodatapb.TabletType, cells []string) (err error) {
 	if err = CheckKeyspaceLocked(ctx, keyspace); err != nil {
 		return err
 	}
+	return retryPartialTxn(ctx, ts, func(txn *Txn) error {
+		return txn.MigrateServedType(ctx, keyspace, shardsToAdd, shardsToRemove, tabletType, cells)
+	})
+}
 
-	// The caller intents to update all cells in this case
-	if len(cells) == 0 {
-		cells, err = ts.GetCellInfoNames(ctx)
-		if err != nil {
-			return err
-		}
-	}
-
-	wg := sync.WaitGroup{}
-	rec := concurrency.AllErrorRecorder{}
-	for _, cell := range cells {
-		wg.Add(1)
-		go func(cell, keyspace string) {
-			defer wg.Done()
-			srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, keyspace)
-			switch {
-			case err == nil:
-				for _, partition := range srvKeyspace.GetPartitions() {
-
-					// We are finishing the migration, cleaning up tablet controls from the srvKeyspace
-					if tabletType == topodatapb.TabletType_PRIMARY {
-						partition.ShardTabletControls = nil
-					}
-
-					if partition.GetServedType() != tabletType {
-						continue
-					}
-
-					shardReferences := make([]*topodatapb.ShardReference, 0)
-
-					for _, shardReference := range partition.GetShardReferences() {
-						inShardsToRemove := false
-						for _, si := range shardsToRemove {
-							if key.KeyRangeEqual(shardReference.GetKeyRange(), si.GetKeyRange()) {
-								inShardsToRemove = true
-								break
-							}
-						}
+// migrateServedType removes/adds shards from the partitions of srvKeyspace
+// for a served type, and checks the result.
+func migrateServedType(cell string, srvKeyspace *topodatapb.SrvKeyspace, shardsToAdd, shardsToRemove []*ShardInfo, tabletType topodatapb.TabletType) error {
+	for _, partition := range srvKeyspace.GetPartitions() {
 
-						if !inShardsToRemove {
-							shardReferences = append(shardReferences, shardReference)
-						}
-					}
+		// We are finishing the migration, cleaning up tablet controls from the srvKeyspace
+		if tabletType == topodatapb.TabletType_PRIMARY {
+			partition.ShardTabletControls = nil
+		}
 
-					for _, si := range shardsToAdd {
-						alreadyAdded := false
-						for _, shardReference := range partition.GetShardReferences() {
-							if key.KeyRangeEqual(shardReference.GetKeyRange(), si.GetKeyRange()) {
-								alreadyAdded = true
-								break
-							}
-						}
+		if partition.GetServedType() != tabletType {
+			continue
+		}
 
-						if !alreadyAdded {
-							shardReference := &topodatapb.ShardReference{
-								Name:     si.ShardName(),
-								KeyRange: si.KeyRange,
-							}
-							shardReferences = append(shardReferences, shardReference)
-						}
-					}
+		shardReferences := make([]*topodatapb.ShardReference, 0)
 
-					partition.ShardReferences = shardReferences
+		for _, shardReference := range partition.GetShardReferences() {
+			inShardsToRemove := false
+			for _, si := range shardsToRemove {
+				if key.KeyRangeEqual(shardReference.GetKeyRange(), si.GetKeyRange()) {
+					inShardsToRemove = true
+					break
 				}
+			}
 
-				if err := OrderAndCheckPartitions(cell, srvKeyspace); err != nil {
-					rec.RecordError(err)
-					return
-				}
+			if !inShardsToRemove {
+				shardReferences = append(shardReferences, shardReference)
+			}
+		}
 
-				err = ts.UpdateSrvKeyspace(ctx, cell, keyspace, srvKeyspace)
-				if err != nil {
-					rec.RecordError(err)
-					return
+		for _, si := range shardsToAdd {
+			alreadyAdded := false
+			for _, shardReference := range partition.GetShardReferences() {
+				if key.KeyRangeEqual(shardReference.GetKeyRange(), si.GetKeyRange()) {
+					alreadyAdded = true
+					break
 				}
+			}
 
-			case IsErrType(err, NoNode):
-				// Assuming this cell is not active, nothing to do.
-			default:
-				rec.RecordError(err)
-				return
+			if !alreadyAdded {
+				shardReference := &topodatapb.ShardReference{
+					Name:     si.ShardName(),
+					KeyRange: si.KeyRange,
+				}
+				shardReferences = append(shardReferences, shardReference)
 			}
-		}(cell, keyspace)
-	}
-	wg.Wait()
-	if rec.HasErrors() {
-		return NewError(PartialResult, rec.Error().Error())
+		}
+
+		partition.ShardReferences = shardReferences
 	}
-	return nil
+
+	return OrderAndCheckPartitions(cell, srvKeyspace)
 }
 
 // UpdateSrvKeyspace saves a new SrvKeyspace. It is a blind write.
@@ -136,6 +136,11 @@ func TopoServerTestSuite(t *testing.T, ctx context.Context, factory func() *topo
 	executeTestSuite(checkFile, t, ctx, ts, ignoreList, "checkFile")
 	ts.Close()
 
+	t.Log("=== checkTxn")
+	ts = factory()
+	executeTestSuite(checkTxn, t, ctx, ts, ignoreList, "checkTxn")
+	ts.Close()
+
 	t.Log("=== checkWatch")
 	ts = factory()
 	executeTestSuite(checkWatch, t, ctx, ts, ignoreList, "checkWatch")
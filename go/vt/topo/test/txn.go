@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// checkTxn tests Server.ApplyTxnOps, across the global and the local
+// cell.
+func checkTxn(t *testing.T, ctx context.Context, ts *topo.Server) {
+	globalConn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+	localConn, err := ts.ConnForCell(ctx, LocalCellName)
+	require.NoError(t, err)
+
+	checkContents := func(conn topo.Conn, filePath string, want string, wantVersion topo.Version) {
+		t.Helper()
+		contents, version, err := conn.Get(ctx, filePath)
+		require.NoError(t, err)
+		require.Equal(t, want, string(contents))
+		if wantVersion != nil {
+			require.Equal(t, wantVersion.String(), version.String())
+		}
+	}
+	checkNoNode := func(conn topo.Conn, filePath string) {
+		t.Helper()
+		_, _, err := conn.Get(ctx, filePath)
+		require.True(t, topo.IsErrType(err, topo.NoNode), "Get(%v) didn't return ErrNoNode but: %v", filePath, err)
+	}
+
+	// Create two files in two cells.
+	versions, err := ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnCreate, Cell: topo.GlobalCell, Path: "/txn/a", Contents: []byte("a1")},
+		{Type: topo.TxnCreate, Cell: LocalCellName, Path: "/txn/b", Contents: []byte("b1")},
+	})
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	checkContents(globalConn, "/txn/a", "a1", versions[0])
+	checkContents(localConn, "/txn/b", "b1", versions[1])
+	aVersion, bVersion := versions[0], versions[1]
+
+	// A stale version fails the whole transaction. The files written by
+	// the same transaction can have the same version, so we update b to
+	// get a stale version.
+	staleVersion := bVersion
+	bVersion, err = localConn.Update(ctx, "/txn/b", []byte("b1"), bVersion)
+	require.NoError(t, err)
+	_, err = ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnUpdate, Cell: topo.GlobalCell, Path: "/txn/a", Contents: []byte("a2"), Version: aVersion},
+		{Type: topo.TxnCreate, Cell: LocalCellName, Path: "/txn/c", Contents: []byte("c1")},
+		{Type: topo.TxnUpdate, Cell: LocalCellName, Path: "/txn/b", Contents: []byte("b2"), Version: staleVersion},
+	})
+	require.True(t, topo.IsErrType(err, topo.BadVersion), "stale version didn't return ErrBadVersion but: %v", err)
+	checkContents(globalConn, "/txn/a", "a1", aVersion)
+	checkContents(localConn, "/txn/b", "b1", bVersion)
+	checkNoNode(localConn, "/txn/c")
+
+	// So does creating a file that exists, or checking a file that
+	// doesn't.
+	_, err = ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnDelete, Cell: LocalCellName, Path: "/txn/b", Version: bVersion},
+		{Type: topo.TxnCreate, Cell: topo.GlobalCell, Path: "/txn/a", Contents: []byte("a2")},
+	})
+	require.True(t, topo.IsErrType(err, topo.NodeExists), "creating an existing file didn't return ErrNodeExists but: %v", err)
+	checkContents(localConn, "/txn/b", "b1", bVersion)
+	_, err = ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnUpdate, Cell: topo.GlobalCell, Path: "/txn/a", Contents: []byte("a2"), Version: aVersion},
+		{Type: topo.TxnCheck, Cell: LocalCellName, Path: "/txn/c"},
+	})
+	require.True(t, topo.IsErrType(err, topo.NoNode), "checking a missing file didn't return ErrNoNode but: %v", err)
+	checkContents(globalConn, "/txn/a", "a1", aVersion)
+
+	// The same file can't appear twice.
+	_, err = ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnUpdate, Cell: topo.GlobalCell, Path: "/txn/a", Contents: []byte("a2")},
+		{Type: topo.TxnUpdate, Cell: topo.GlobalCell, Path: "/txn/a", Contents: []byte("a3")},
+	})
+	require.Error(t, err)
+	checkContents(globalConn, "/txn/a", "a1", aVersion)
+
+	// Check, update and create.
+	versions, err = ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnCheck, Cell: LocalCellName, Path: "/txn/b", Version: bVersion},
+		{Type: topo.TxnUpdate, Cell: topo.GlobalCell, Path: "/txn/a", Contents: []byte("a2"), Version: aVersion},
+		{Type: topo.TxnUpdate, Cell: LocalCellName, Path: "/txn/c", Contents: []byte("c1")},
+	})
+	require.NoError(t, err)
+	require.Nil(t, versions[0])
+	checkContents(localConn, "/txn/b", "b1", bVersion)
+	checkContents(globalConn, "/txn/a", "a2", versions[1])
+	checkContents(localConn, "/txn/c", "c1", versions[2])
+	aVersion, cVersion := versions[1], versions[2]
+
+	// And delete everything.
+	_, err = ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnDelete, Cell: topo.GlobalCell, Path: "/txn/a", Version: aVersion},
+		{Type: topo.TxnDelete, Cell: LocalCellName, Path: "/txn/b", Version: bVersion},
+		{Type: topo.TxnDelete, Cell: LocalCellName, Path: "/txn/c", Version: cVersion},
+	})
+	require.NoError(t, err)
+	checkNoNode(globalConn, "/txn/a")
+	checkNoNode(localConn, "/txn/b")
+	checkNoNode(localConn, "/txn/c")
+}
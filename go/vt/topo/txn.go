@@ -0,0 +1,482 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var topoStatsNonAtomicTxns = stats.NewCountersWithSingleLabel(
+	"TopologyNonAtomicTransactions",
+	"Number of topology transactions that were not applied atomically, but one backend or one write at a time",
+	"Reason")
+
+// nonAtomicTxnLogged has the reasons of the non-atomic transactions that
+// were already logged, as they can be frequent: only the first one of
+// each reason is logged at its level, the others only at V(1).
+var nonAtomicTxnLogged sync.Map
+
+// TxnOpType is the type of an operation of a transaction.
+type TxnOpType int
+
+const (
+	// TxnCheck checks that a file exists, at Version if it is set,
+	// without changing it.
+	TxnCheck TxnOpType = iota
+	// TxnCreate creates a file, like Conn.Create.
+	TxnCreate
+	// TxnUpdate updates a file, like Conn.Update: if Version is nil,
+	// the file is created or overwritten.
+	TxnUpdate
+	// TxnDelete deletes a file, like Conn.Delete.
+	TxnDelete
+)
+
+// String returns the name of the type.
+func (t TxnOpType) String() string {
+	switch t {
+	case TxnCheck:
+		return "check"
+	case TxnCreate:
+		return "create"
+	case TxnUpdate:
+		return "update"
+	case TxnDelete:
+		return "delete"
+	}
+	return fmt.Sprintf("TxnOpType(%d)", int(t))
+}
+
+// TxnOp is an operation of a transaction.
+type TxnOp struct {
+	Type TxnOpType
+
+	// Cell is the cell of the file, for Server.ApplyTxnOps.
+	Cell string
+
+	// Conn is the connection to the cell of the file. ApplyTxnOps sets
+	// it before it calls TxnConn.Txn, where a nil Conn means the
+	// receiver.
+	Conn Conn
+
+	// Path is the path of the file, relative to the root of the cell.
+	Path string
+
+	// Contents are the new contents of the file, for TxnCreate and
+	// TxnUpdate.
+	Contents []byte
+
+	// Version is the version the file must be at, if it is set.
+	Version Version
+}
+
+// TxnConn is implemented by the Conn implementations that can apply
+// several operations atomically. It is optional: ApplyTxnOps falls back
+// to applying the operations one at a time with the other
+// implementations.
+type TxnConn interface {
+	// TxnBackend identifies the storage the Conn writes to. Conns with
+	// the same TxnBackend, for instance the cells that use different
+	// roots of the same etcd cluster, can be part of the same
+	// transaction.
+	TxnBackend() string
+
+	// Txn applies ops atomically: either all of them succeed, or none is
+	// applied. The Conn of every op is either nil, for the receiver, or
+	// a Conn of the same implementation with the same TxnBackend. A file
+	// appears at most once in ops.
+	// It returns the new version of every file, in the order of ops,
+	// and nil for TxnCheck and TxnDelete.
+	// If an op fails, it returns the error the matching Conn method
+	// would return: ErrNodeExists, ErrNoNode or ErrBadVersion.
+	// It returns ErrNoImplementation if it can't apply that many ops in
+	// a single transaction, in which case ApplyTxnOps falls back to
+	// applying them one at a time.
+	Txn(ctx context.Context, ops []*TxnOp) ([]Version, error)
+}
+
+// txnPrev is the state of a file before a transaction.
+type txnPrev struct {
+	exists   bool
+	contents []byte
+	version  Version
+}
+
+// txnGroup is the ops of a transaction that go to the same backend.
+type txnGroup struct {
+	// txnConn applies the ops, or is nil if they are applied one at a
+	// time with conns.
+	txnConn TxnConn
+	// backend is the TxnBackend of txnConn.
+	backend string
+	// cell is the cell of the first op, for the stats.
+	cell string
+	// ops have their Conn set to the unwrapped Conn of their cell.
+	ops []*TxnOp
+	// conns are the Conns of the cells of ops, as returned by
+	// ConnForCell.
+	conns []Conn
+	// indexes are the indexes of ops in the transaction.
+	indexes []int
+	// prevs are the states of the files before the transaction, once
+	// read.
+	prevs []txnPrev
+}
+
+// ApplyTxnOps applies ops atomically when all their cells share a topo
+// backend that implements TxnConn, and the backend accepts that many ops
+// in a single transaction. Otherwise, the ops are applied one backend at
+// a time, and one op at a time on the backends that don't implement
+// TxnConn: all the conditions are checked first, and the ops that were
+// applied are reverted if a later one fails. A process that dies in the
+// middle can still leave some of them applied, so callers must hold the
+// locks that protect the files. Such transactions are logged, and
+// counted in the TopologyNonAtomicTransactions stat. TxnAtomic tells
+// beforehand if the transactions on a set of cells are atomic.
+// It returns the new version of every file, in the order of ops, and
+// nil for TxnCheck and TxnDelete.
+func (ts *Server) ApplyTxnOps(ctx context.Context, ops []*TxnOp) ([]Version, error) {
+	return ts.applyTxnOps(ctx, ops, true)
+}
+
+// applyTxnOps is ApplyTxnOps. If recordNoTxnSupport is false, the
+// transactions that are not atomic only because their backend doesn't
+// implement TxnConn are neither logged nor counted.
+func (ts *Server) applyTxnOps(ctx context.Context, ops []*TxnOp, recordNoTxnSupport bool) ([]Version, error) {
+	span, ctx := trace.NewSpan(ctx, "TopoServer.ApplyTxnOps")
+	span.Annotate("ops", len(ops))
+	defer span.Finish()
+
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	groups, err := ts.txnGroups(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]Version, len(ops))
+
+	// A single atomic transaction doesn't need anything else.
+	if len(groups) == 1 && groups[0].txnConn != nil {
+		err := groups[0].apply(ctx, versions)
+		if !IsErrType(err, NoImplementation) {
+			return versions, err
+		}
+		topoStatsNonAtomicTxns.Add("TooManyOps", 1)
+		logNonAtomicTxn("TooManyOps", log.Errorf, "topo transaction of %d ops is NOT atomic, as it can't be applied in a single transaction: applying the ops one at a time: %v", len(ops), err)
+		groups[0].txnConn = nil
+	} else if len(groups) > 1 || recordNoTxnSupport {
+		nonAtomicTxn(groups, len(ops))
+	}
+
+	// Otherwise we read the files first, so we can check all the
+	// conditions before we apply anything, and revert what we applied
+	// if a later group fails.
+	for _, g := range groups {
+		if err := g.readPrevs(ctx); err != nil {
+			return nil, err
+		}
+		for i, op := range g.ops {
+			if err := checkTxnOp(op, g.prevs[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for i, g := range groups {
+		err := g.apply(ctx, versions)
+		if IsErrType(err, NoImplementation) && g.txnConn != nil {
+			logNonAtomicTxn("TooManyOps", log.Errorf, "topo transaction of %d ops can't be applied atomically on %v, applying the ops one at a time: %v", len(g.ops), g.backend, err)
+			g.txnConn = nil
+			err = g.apply(ctx, versions)
+		}
+		if err != nil {
+			for j := i - 1; j >= 0; j-- {
+				groups[j].revert(ctx, versions)
+			}
+			return nil, err
+		}
+	}
+	return versions, nil
+}
+
+// nonAtomicTxn records a transaction of count ops that is applied one
+// group at a time. The transactions that span several backends are
+// errors, as the cells could share one, while the backends without
+// transactions only get a warning.
+func nonAtomicTxn(groups []*txnGroup, count int) {
+	if len(groups) > 1 {
+		backends := make([]string, 0, len(groups))
+		for _, g := range groups {
+			backends = append(backends, g.backend)
+		}
+		topoStatsNonAtomicTxns.Add("SeveralBackends", 1)
+		logNonAtomicTxn("SeveralBackends", log.Errorf, "topo transaction of %d ops is NOT atomic, as its cells use several topo backends %q: applying the ops one backend at a time", count, backends)
+		return
+	}
+	topoStatsNonAtomicTxns.Add("NoTxnSupport", 1)
+	logNonAtomicTxn("NoTxnSupport", log.Warningf, "topo transaction of %d ops is NOT atomic, as the topo implementation doesn't support transactions: applying the ops one at a time", count)
+}
+
+// logNonAtomicTxn logs a non-atomic transaction with logf if it is the
+// first one for reason in this process, and at V(1) otherwise.
+func logNonAtomicTxn(reason string, logf func(format string, args ...any), format string, args ...any) {
+	if _, logged := nonAtomicTxnLogged.LoadOrStore(reason, true); !logged {
+		logf(format, args...)
+		return
+	}
+	if log.V(1) {
+		log.Infof(format, args...)
+	}
+}
+
+// TxnAtomic returns true if the transactions on the files of cells are
+// applied atomically, that is if the cells share a topo backend that
+// implements TxnConn. The transactions with more ops than the backend
+// accepts are still applied one op at a time.
+func (ts *Server) TxnAtomic(ctx context.Context, cells []string) (bool, error) {
+	backend := ""
+	for i, cell := range cells {
+		conn, err := ts.ConnForCell(ctx, cell)
+		if err != nil {
+			return false, err
+		}
+		if sc, ok := conn.(*StatsConn); ok {
+			conn = sc.conn
+		}
+		txnConn, ok := conn.(TxnConn)
+		if !ok {
+			return false, nil
+		}
+		if i == 0 {
+			backend = txnConn.TxnBackend()
+		} else if txnConn.TxnBackend() != backend {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// txnGroups resolves the cells of ops, and groups them by backend. The
+// ops of the backends that don't implement TxnConn go to a single group,
+// applied one at a time.
+func (ts *Server) txnGroups(ctx context.Context, ops []*TxnOp) ([]*txnGroup, error) {
+	var groups []*txnGroup
+	byBackend := make(map[string]*txnGroup)
+	files := make(map[string]bool)
+	for i, op := range ops {
+		file := op.Cell + ":" + op.Path
+		if files[file] {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "file %v appears more than once in the transaction", file)
+		}
+		files[file] = true
+
+		conn, err := ts.ConnForCell(ctx, op.Cell)
+		if err != nil {
+			return nil, err
+		}
+		unwrapped := conn
+		if sc, ok := conn.(*StatsConn); ok {
+			if sc.readOnly && op.Type != TxnCheck {
+				return nil, vterrors.Errorf(vtrpcpb.Code_READ_ONLY, readOnlyErrorStrFormat, "Txn", op.Path)
+			}
+			unwrapped = sc.conn
+		}
+
+		backend := ""
+		txnConn, ok := unwrapped.(TxnConn)
+		if ok {
+			backend = txnConn.TxnBackend()
+		}
+		g, ok := byBackend[backend]
+		if !ok {
+			g = &txnGroup{txnConn: txnConn, backend: backend, cell: op.Cell}
+			byBackend[backend] = g
+			groups = append(groups, g)
+		}
+		groupOp := *op
+		groupOp.Conn = unwrapped
+		g.ops = append(g.ops, &groupOp)
+		g.conns = append(g.conns, conn)
+		g.indexes = append(g.indexes, i)
+	}
+	return groups, nil
+}
+
+// readPrevs reads the state of the files of the group.
+func (g *txnGroup) readPrevs(ctx context.Context) error {
+	g.prevs = make([]txnPrev, len(g.ops))
+	for i, op := range g.ops {
+		contents, version, err := g.conns[i].Get(ctx, op.Path)
+		switch {
+		case err == nil:
+			g.prevs[i] = txnPrev{exists: true, contents: contents, version: version}
+		case IsErrType(err, NoNode):
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// apply applies the ops of the group, and stores their new versions in
+// versions.
+func (g *txnGroup) apply(ctx context.Context, versions []Version) error {
+	var groupVersions []Version
+	var err error
+	if g.txnConn != nil {
+		statsKey := []string{"Txn", g.cell}
+		startTime := time.Now()
+		groupVersions, err = g.txnConn.Txn(ctx, g.ops)
+		topoStatsConnTimings.Record(statsKey, startTime)
+		if err != nil {
+			topoStatsConnErrors.Add(statsKey, 1)
+		}
+	} else {
+		groupVersions, err = applyTxnOps(ctx, g.ops, g.conns, g.prevs)
+	}
+	if err != nil {
+		return err
+	}
+	for i, index := range g.indexes {
+		versions[index] = groupVersions[i]
+	}
+	return nil
+}
+
+// revert restores the files of a group that was applied to their
+// previous state. Failures are logged, as there is nothing else we can
+// do about them.
+func (g *txnGroup) revert(ctx context.Context, versions []Version) {
+	var ops []*TxnOp
+	var conns []Conn
+	for i := len(g.ops) - 1; i >= 0; i-- {
+		if op := revertTxnOp(g.ops[i], g.prevs[i], versions[g.indexes[i]]); op != nil {
+			ops = append(ops, op)
+			conns = append(conns, g.conns[i])
+		}
+	}
+	if g.txnConn != nil {
+		if _, err := g.txnConn.Txn(ctx, ops); err != nil {
+			log.Errorf("failed to revert the topo transaction on %v: %v", g.backend, err)
+		}
+		return
+	}
+	for i, op := range ops {
+		if _, err := applyTxnOp(ctx, conns[i], op); err != nil {
+			log.Errorf("failed to revert the %v of %v in cell %v after a failed topo transaction: %v", op.Type, op.Path, op.Cell, err)
+		}
+	}
+}
+
+// applyTxnOps applies ops one at a time. prevs are the states of the
+// files the conditions of ops were checked against. If an op fails,
+// the ones before it are reverted.
+func applyTxnOps(ctx context.Context, ops []*TxnOp, conns []Conn, prevs []txnPrev) ([]Version, error) {
+	versions := make([]Version, len(ops))
+	for i, op := range ops {
+		version, err := applyTxnOp(ctx, conns[i], op)
+		if err != nil {
+			for j := i - 1; j >= 0; j-- {
+				revert := revertTxnOp(ops[j], prevs[j], versions[j])
+				if revert == nil {
+					continue
+				}
+				if _, rerr := applyTxnOp(ctx, conns[j], revert); rerr != nil {
+					log.Errorf("failed to revert the %v of %v in cell %v after a failed topo transaction: %v", ops[j].Type, ops[j].Path, ops[j].Cell, rerr)
+				}
+			}
+			return nil, err
+		}
+		versions[i] = version
+	}
+	return versions, nil
+}
+
+// applyTxnOp applies a single op on conn. TxnCheck is a no-op, as the
+// conditions are checked beforehand.
+func applyTxnOp(ctx context.Context, conn Conn, op *TxnOp) (Version, error) {
+	switch op.Type {
+	case TxnCheck:
+		return nil, nil
+	case TxnCreate:
+		return conn.Create(ctx, op.Path, op.Contents)
+	case TxnUpdate:
+		return conn.Update(ctx, op.Path, op.Contents, op.Version)
+	case TxnDelete:
+		return nil, conn.Delete(ctx, op.Path, op.Version)
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown transaction op type %v", op.Type)
+}
+
+// revertTxnOp returns the op that restores the file of op, which was
+// applied and left at version, to prev. It returns nil for TxnCheck.
+func revertTxnOp(op *TxnOp, prev txnPrev, version Version) *TxnOp {
+	revert := &TxnOp{Cell: op.Cell, Conn: op.Conn, Path: op.Path}
+	switch {
+	case op.Type == TxnCheck:
+		return nil
+	case !prev.exists:
+		revert.Type = TxnDelete
+		revert.Version = version
+	case op.Type == TxnDelete:
+		revert.Type = TxnCreate
+		revert.Contents = prev.contents
+	default:
+		revert.Type = TxnUpdate
+		revert.Contents = prev.contents
+		revert.Version = version
+	}
+	return revert
+}
+
+// checkTxnOp checks the conditions of op against the state of its file.
+func checkTxnOp(op *TxnOp, prev txnPrev) error {
+	versionMatches := op.Version == nil || (prev.exists && op.Version.String() == prev.version.String())
+	switch op.Type {
+	case TxnCreate:
+		if prev.exists {
+			return NewError(NodeExists, op.Path)
+		}
+	case TxnUpdate:
+		if op.Version != nil && !prev.exists {
+			return NewError(NoNode, op.Path)
+		}
+		if !versionMatches {
+			return NewError(BadVersion, op.Path)
+		}
+	case TxnCheck, TxnDelete:
+		if !prev.exists {
+			return NewError(NoNode, op.Path)
+		}
+		if !versionMatches {
+			return NewError(BadVersion, op.Path)
+		}
+	default:
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown transaction op type %v", op.Type)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// TestApplyTxnOpsFallback checks that the ops are applied one at a time,
+// and reverted on failure, when the topo can't apply them atomically.
+func TestApplyTxnOpsFallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "zone1")
+	defer ts.Close()
+	factory.AddOperationError(memorytopo.Txn, ".*", topo.NewError(topo.NoImplementation, "txn"))
+
+	versions, err := ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnCreate, Cell: topo.GlobalCell, Path: "/txn/a", Contents: []byte("a1")},
+		{Type: topo.TxnCreate, Cell: "zone1", Path: "/txn/b", Contents: []byte("b1")},
+	})
+	require.NoError(t, err)
+
+	// The update of b fails after a was updated, so a is reverted.
+	factory.AddOperationError(memorytopo.Update, "/txn/b", errors.New("update failed"))
+	_, err = ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnUpdate, Cell: topo.GlobalCell, Path: "/txn/a", Contents: []byte("a2"), Version: versions[0]},
+		{Type: topo.TxnCreate, Cell: topo.GlobalCell, Path: "/txn/c", Contents: []byte("c1")},
+		{Type: topo.TxnUpdate, Cell: "zone1", Path: "/txn/b", Contents: []byte("b2"), Version: versions[1]},
+	})
+	require.ErrorContains(t, err, "update failed")
+
+	globalConn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	require.NoError(t, err)
+	contents, _, err := globalConn.Get(ctx, "/txn/a")
+	require.NoError(t, err)
+	assert.Equal(t, "a1", string(contents))
+	_, _, err = globalConn.Get(ctx, "/txn/c")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "c wasn't deleted: %v", err)
+
+	// A stale version is detected before anything is applied.
+	_, err = ts.ApplyTxnOps(ctx, []*topo.TxnOp{
+		{Type: topo.TxnCreate, Cell: topo.GlobalCell, Path: "/txn/c", Contents: []byte("c1")},
+		{Type: topo.TxnDelete, Cell: topo.GlobalCell, Path: "/txn/a", Version: versions[0]},
+	})
+	require.True(t, topo.IsErrType(err, topo.BadVersion), "got %v", err)
+	_, _, err = globalConn.Get(ctx, "/txn/c")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "c was created: %v", err)
+}
+
+func setupTxnKeyspace(ctx context.Context, t *testing.T, ts *topo.Server, cells ...string) {
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	for _, shard := range []string{"-80", "80-"} {
+		require.NoError(t, ts.CreateShard(ctx, "ks", shard))
+	}
+	// Only -80 is serving.
+	_, keyRange, err := topo.ValidateShardName("-80")
+	require.NoError(t, err)
+	for _, cell := range cells {
+		require.NoError(t, ts.UpdateSrvKeyspace(ctx, cell, "ks", &topodatapb.SrvKeyspace{
+			Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{{
+				ServedType:      topodatapb.TabletType_PRIMARY,
+				ShardReferences: []*topodatapb.ShardReference{{Name: "-80", KeyRange: keyRange}},
+			}},
+		}))
+	}
+}
+
+func TestRetryTxn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer ts.Close()
+	setupTxnKeyspace(ctx, t, ts, "zone1", "zone2")
+
+	attempts := 0
+	err := ts.RetryTxn(ctx, func(txn *topo.Txn) error {
+		attempts++
+		si, err := txn.UpdateShardFields(ctx, "ks", "80-", func(si *topo.ShardInfo) error {
+			si.IsPrimaryServing = false
+			return nil
+		})
+		require.NoError(t, err)
+		if attempts == 1 {
+			// Someone else changes the shard before we commit.
+			_, err := ts.UpdateShardFields(ctx, "ks", "80-", func(si *topo.ShardInfo) error {
+				si.PrimaryAlias = &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}
+				return nil
+			})
+			require.NoError(t, err)
+		}
+		return txn.MigrateServedType(ctx, "ks", []*topo.ShardInfo{si}, nil, topodatapb.TabletType_PRIMARY, nil)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	si, err := ts.GetShard(ctx, "ks", "80-")
+	require.NoError(t, err)
+	assert.False(t, si.IsPrimaryServing)
+	assert.EqualValues(t, 100, si.PrimaryAlias.GetUid())
+	for _, cell := range []string{"zone1", "zone2"} {
+		srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, "ks")
+		require.NoError(t, err)
+		require.Len(t, srvKeyspace.Partitions[0].ShardReferences, 2, cell)
+		assert.Equal(t, "80-", srvKeyspace.Partitions[0].ShardReferences[1].Name, cell)
+	}
+}
+
+func TestTxnCommitIsAtomic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts, factory := memorytopo.NewServerAndFactory(ctx, "zone1", "zone2")
+	defer ts.Close()
+	setupTxnKeyspace(ctx, t, ts, "zone1", "zone2")
+
+	// Writing the SrvKeyspaces fails, so the shard isn't written either.
+	factory.AddOperationError(memorytopo.Update, "SrvKeyspace", errors.New("SrvKeyspace unavailable"))
+	txn := ts.NewTxn()
+	si, err := txn.UpdateShardFields(ctx, "ks", "80-", func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = false
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, txn.MigrateServedType(ctx, "ks", []*topo.ShardInfo{si}, nil, topodatapb.TabletType_PRIMARY, nil))
+	require.ErrorContains(t, txn.Commit(ctx), "SrvKeyspace unavailable")
+
+	si, err = ts.GetShard(ctx, "ks", "80-")
+	require.NoError(t, err)
+	assert.True(t, si.IsPrimaryServing)
+	for _, cell := range []string{"zone1", "zone2"} {
+		srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, "ks")
+		require.NoError(t, err)
+		assert.Len(t, srvKeyspace.Partitions[0].ShardReferences, 1, cell)
+	}
+}
+
+// TestMigrateServedTypePartialResult checks the cells that can be read are
+// updated when others can't, and that the caller is told.
+func TestMigrateServedTypePartialResult(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer ts.Close()
+	setupTxnKeyspace(ctx, t, ts, "zone1", "zone2")
+
+	si, err := ts.GetShard(ctx, "ks", "80-")
+	require.NoError(t, err)
+	ctx, unlock, err := ts.LockKeyspace(ctx, "ks", "test")
+	require.NoError(t, err)
+	defer unlock(&err)
+	err = ts.MigrateServedType(ctx, "ks", []*topo.ShardInfo{si}, nil, topodatapb.TabletType_PRIMARY, []string{"zone1", "unknown"})
+	require.True(t, topo.IsErrType(err, topo.PartialResult), "got %v", err)
+	err = nil
+
+	srvKeyspace, err := ts.GetSrvKeyspace(ctx, "zone1", "ks")
+	require.NoError(t, err)
+	assert.Len(t, srvKeyspace.Partitions[0].ShardReferences, 2)
+	srvKeyspace, err = ts.GetSrvKeyspace(ctx, "zone2", "ks")
+	require.NoError(t, err)
+	assert.Len(t, srvKeyspace.Partitions[0].ShardReferences, 1)
+}
+
+func TestTxnAtomic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer ts.Close()
+
+	atomic, err := ts.TxnAtomic(ctx, []string{topo.GlobalCell, "zone1", "zone2"})
+	require.NoError(t, err)
+	assert.True(t, atomic)
+	_, err = ts.TxnAtomic(ctx, []string{topo.GlobalCell, "unknown"})
+	assert.Error(t, err)
+}
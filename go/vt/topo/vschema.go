@@ -23,6 +23,7 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/vterrors"
 
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 )
 
@@ -57,13 +58,12 @@ func (ts *Server) SaveVSchema(ctx context.Context, ksvs *KeyspaceVSchemaInfo) er
 		return err
 	}
 
-	nodePath := path.Join(KeyspacesPath, ksvs.Name, VSchemaFile)
 	data, err := ksvs.MarshalVT()
 	if err != nil {
 		return err
 	}
 
-	version, err := ts.globalCell.Update(ctx, nodePath, data, ksvs.version)
+	version, _, err := ts.saveConfig(ctx, topodatapb.ConfigKind_VSCHEMA, ksvs.Name, &TxnOp{Type: TxnUpdate, Contents: data, Version: ksvs.version}, "")
 	if err != nil {
 		log.Errorf("failed to update vschema for keyspace %s: %v", ksvs.Name, err)
 		return err
@@ -80,8 +80,8 @@ func (ts *Server) DeleteVSchema(ctx context.Context, keyspace string) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
-	nodePath := path.Join(KeyspacesPath, keyspace, VSchemaFile)
-	return ts.globalCell.Delete(ctx, nodePath, nil)
+	_, _, err := ts.saveConfig(ctx, topodatapb.ConfigKind_VSCHEMA, keyspace, &TxnOp{Type: TxnDelete}, "")
+	return err
 }
 
 // GetVSchema fetches the vschema from the topo.
@@ -144,13 +144,13 @@ func (ts *Server) SaveRoutingRules(ctx context.Context, routingRules *vschemapb.
 
 	if len(data) == 0 {
 		// No vschema, remove it. So we can remove the keyspace.
-		if err := ts.globalCell.Delete(ctx, RoutingRulesFile, nil); err != nil && !IsErrType(err, NoNode) {
+		if _, _, err := ts.saveConfig(ctx, topodatapb.ConfigKind_ROUTING_RULES, "", &TxnOp{Type: TxnDelete}, ""); err != nil && !IsErrType(err, NoNode) {
 			return err
 		}
 		return nil
 	}
 
-	_, err = ts.globalCell.Update(ctx, RoutingRulesFile, data, nil)
+	_, _, err = ts.saveConfig(ctx, topodatapb.ConfigKind_ROUTING_RULES, "", &TxnOp{Type: TxnUpdate, Contents: data}, "")
 	return err
 }
 
@@ -187,13 +187,13 @@ func (ts *Server) SaveShardRoutingRules(ctx context.Context, shardRoutingRules *
 	}
 
 	if len(data) == 0 {
-		if err := ts.globalCell.Delete(ctx, ShardRoutingRulesFile, nil); err != nil && !IsErrType(err, NoNode) {
+		if _, _, err := ts.saveConfig(ctx, topodatapb.ConfigKind_SHARD_ROUTING_RULES, "", &TxnOp{Type: TxnDelete}, ""); err != nil && !IsErrType(err, NoNode) {
 			return err
 		}
 		return nil
 	}
 
-	_, err = ts.globalCell.Update(ctx, ShardRoutingRulesFile, data, nil)
+	_, _, err = ts.saveConfig(ctx, topodatapb.ConfigKind_SHARD_ROUTING_RULES, "", &TxnOp{Type: TxnUpdate, Contents: data}, "")
 	return err
 }
 
@@ -228,10 +228,8 @@ func (ts *Server) CreateKeyspaceRoutingRules(ctx context.Context, value *vschema
 	if err != nil {
 		return err
 	}
-	if _, err := ts.globalCell.Create(ctx, ts.GetKeyspaceRoutingRulesPath(), data); err != nil {
-		return err
-	}
-	return nil
+	_, _, err = ts.saveConfig(ctx, topodatapb.ConfigKind_KEYSPACE_ROUTING_RULES, "", &TxnOp{Type: TxnCreate, Contents: data}, "")
+	return err
 }
 
 // SaveKeyspaceRoutingRules saves the given routing rules proto in the topo at
@@ -260,7 +258,7 @@ func (ts *Server) SaveKeyspaceRoutingRules(ctx context.Context, rules *vschemapb
 	if err != nil {
 		return err
 	}
-	_, err = ts.globalCell.Update(ctx, ts.GetKeyspaceRoutingRulesPath(), data, nil)
+	_, _, err = ts.saveConfig(ctx, topodatapb.ConfigKind_KEYSPACE_ROUTING_RULES, "", &TxnOp{Type: TxnUpdate, Contents: data}, "")
 	return err
 }
 
@@ -318,12 +316,12 @@ func (ts *Server) SaveMirrorRules(ctx context.Context, mirrorRules *vschemapb.Mi
 
 	if len(data) == 0 {
 		// No vschema, remove it. So we can remove the keyspace.
-		if err := ts.globalCell.Delete(ctx, MirrorRulesFile, nil); err != nil && !IsErrType(err, NoNode) {
+		if _, _, err := ts.saveConfig(ctx, topodatapb.ConfigKind_MIRROR_RULES, "", &TxnOp{Type: TxnDelete}, ""); err != nil && !IsErrType(err, NoNode) {
 			return err
 		}
 		return nil
 	}
 
-	_, err = ts.globalCell.Update(ctx, MirrorRulesFile, data, nil)
+	_, _, err = ts.saveConfig(ctx, topodatapb.ConfigKind_MIRROR_RULES, "", &TxnOp{Type: TxnUpdate, Contents: data}, "")
 	return err
 }
@@ -114,22 +114,36 @@ func UpdateShardRecords(
 	clearSourceShards bool,
 	logger logutil.Logger,
 ) error {
-	disableQueryService := isFrom
-	if err := ts.UpdateDisableQueryService(ctx, keyspace, shards, servedType, cells, disableQueryService); err != nil {
+	if err := topo.CheckKeyspaceLocked(ctx, keyspace); err != nil {
 		return err
 	}
-	for i, si := range shards {
-		updatedShard, err := ts.UpdateShardFields(ctx, si.Keyspace(), si.ShardName(), func(si *topo.ShardInfo) error {
-			if clearSourceShards {
-				si.SourceShards = nil
-			}
-			return nil
-		})
-
-		if err != nil {
+	disableQueryService := isFrom
+	// The SrvKeyspaces and the shard records are updated in a single
+	// transaction, before the tablets are refreshed.
+	updatedShards := make([]*topo.ShardInfo, len(shards))
+	err := ts.RetryTxn(ctx, func(txn *topo.Txn) error {
+		if err := txn.UpdateDisableQueryService(ctx, keyspace, shards, servedType, cells, disableQueryService); err != nil {
 			return err
 		}
-		shards[i] = updatedShard
+		for i, si := range shards {
+			updatedShard, err := txn.UpdateShardFields(ctx, si.Keyspace(), si.ShardName(), func(si *topo.ShardInfo) error {
+				if clearSourceShards {
+					si.SourceShards = nil
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			updatedShards[i] = updatedShard
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i, si := range shards {
+		shards[i] = updatedShards[i]
 		// For 'to' shards, refresh to make them serve. The 'from' shards will
 		// be refreshed after traffic has migrated.
 		if !isFrom {
@@ -19,9 +19,7 @@ package topotools
 import (
 	"context"
 	"fmt"
-	"sync"
 
-	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -97,6 +95,23 @@ func RebuildKeyspaceLocked(ctx context.Context, log logutil.Logger, ts *topo.Ser
 		return err
 	}
 
+	// The SrvKeyspaces of all the cells are written in a single
+	// transaction, so the cells never serve different versions of the
+	// keyspace, even if we fail half-way. buildErr tells the errors of
+	// the transaction apart from the errors building the SrvKeyspaces.
+	var buildErr error
+	err = ts.RetryTxn(ctx, func(txn *topo.Txn) error {
+		buildErr = rebuildSrvKeyspaces(ctx, txn, ki, keyspace, cells, shards, allowPartial)
+		return buildErr
+	})
+	if err != nil && buildErr == nil {
+		return fmt.Errorf("writing serving data failed: %v", err)
+	}
+	return err
+}
+
+// rebuildSrvKeyspaces stages the SrvKeyspaces of cells in txn.
+func rebuildSrvKeyspaces(ctx context.Context, txn *topo.Txn, ki *topo.KeyspaceInfo, keyspace string, cells []string, shards map[string]*topo.ShardInfo, allowPartial bool) error {
 	// This is safe to rebuild as long there are not srvKeyspaces with tablet controls set.
 	// Build the list of cells to work on: we get the union
 	// of all the Cells of all the Shards, limited to the provided cells.
@@ -104,11 +119,13 @@ func RebuildKeyspaceLocked(ctx context.Context, log logutil.Logger, ts *topo.Ser
 	// srvKeyspaceMap is a map:
 	//   key: cell
 	//   value: topo.SrvKeyspace object being built
+	existing, err := txn.GetSrvKeyspaces(ctx, keyspace, cells)
+	if err != nil {
+		return err
+	}
 	srvKeyspaceMap := make(map[string]*topodatapb.SrvKeyspace)
 	for _, cell := range cells {
-		srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, keyspace)
-		switch {
-		case err == nil:
+		if srvKeyspace, ok := existing[cell]; ok {
 			for _, partition := range srvKeyspace.GetPartitions() {
 				for _, shardTabletControl := range partition.GetShardTabletControls() {
 					if shardTabletControl.QueryServiceDisabled {
@@ -116,10 +133,6 @@ func RebuildKeyspaceLocked(ctx context.Context, log logutil.Logger, ts *topo.Ser
 					}
 				}
 			}
-		case topo.IsErrType(err, topo.NoNode):
-			// NOOP
-		default:
-			return err
 		}
 		srvKeyspaceMap[cell] = &topodatapb.SrvKeyspace{
 			ThrottlerConfig: ki.ThrottlerConfig,
@@ -163,19 +176,7 @@ func RebuildKeyspaceLocked(ctx context.Context, log logutil.Logger, ts *topo.Ser
 			}
 		}
 
+		txn.UpdateSrvKeyspace(cell, keyspace, srvKeyspace)
 	}
-	// And then finally save the keyspace objects, in parallel.
-	rec := concurrency.AllErrorRecorder{}
-	wg := sync.WaitGroup{}
-	for cell, srvKeyspace := range srvKeyspaceMap {
-		wg.Add(1)
-		go func(cell string, srvKeyspace *topodatapb.SrvKeyspace) {
-			defer wg.Done()
-			if err := ts.UpdateSrvKeyspace(ctx, cell, keyspace, srvKeyspace); err != nil {
-				rec.RecordError(fmt.Errorf("writing serving data failed: %v", err))
-			}
-		}(cell, srvKeyspace)
-	}
-	wg.Wait()
-	return rec.Error()
+	return nil
 }
@@ -371,6 +371,7 @@ func (api *API) Handler() http.Handler {
 	httpAPI := vtadminhttp.NewAPI(api, api.options.HTTPOpts)
 
 	router.HandleFunc("/backups", httpAPI.Adapt(vtadminhttp.GetBackups)).Name("API.GetBackups")
+	router.HandleFunc("/backup_schedules", httpAPI.Adapt(vtadminhttp.GetBackupSchedules)).Name("API.GetBackupSchedules")
 	router.HandleFunc("/cells", httpAPI.Adapt(vtadminhttp.GetCellInfos)).Name("API.GetCellInfos")
 	router.HandleFunc("/cells_aliases", httpAPI.Adapt(vtadminhttp.GetCellsAliases)).Name("API.GetCellsAliases")
 	router.HandleFunc("/clusters", httpAPI.Adapt(vtadminhttp.GetClusters)).Name("API.GetClusters")
@@ -840,6 +841,54 @@ func (api *API) GetBackups(ctx context.Context, req *vtadminpb.GetBackupsRequest
 	}, nil
 }
 
+// GetBackupSchedules is part of the vtadminpb.VTAdminServer interface.
+func (api *API) GetBackupSchedules(ctx context.Context, req *vtadminpb.GetBackupSchedulesRequest) (*vtadminpb.GetBackupSchedulesResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "API.GetBackupSchedules")
+	defer span.Finish()
+
+	clusters, _ := api.getClustersForRequest(req.ClusterIds)
+
+	var (
+		m         sync.Mutex
+		wg        sync.WaitGroup
+		rec       concurrency.AllErrorRecorder
+		schedules []*vtadminpb.ClusterBackupSchedules
+	)
+
+	for _, c := range clusters {
+		if !api.authz.IsAuthorized(ctx, c.ID, rbac.BackupResource, rbac.GetAction) {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(c *cluster.Cluster) {
+			defer wg.Done()
+
+			s, err := c.GetBackupSchedules(ctx, req.Keyspaces)
+			if err != nil {
+				rec.RecordError(err)
+				return
+			}
+
+			m.Lock()
+			defer m.Unlock()
+
+			schedules = append(schedules, s)
+		}(c)
+	}
+
+	wg.Wait()
+
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+
+	return &vtadminpb.GetBackupSchedulesResponse{
+		Schedules: schedules,
+	}, nil
+}
+
 // GetCellInfos is part of the vtadminpb.VTAdminServer interface.
 func (api *API) GetCellInfos(ctx context.Context, req *vtadminpb.GetCellInfosRequest) (*vtadminpb.GetCellInfosResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "API.GetCellInfos")
@@ -876,6 +876,79 @@ func TestGetBackups(t *testing.T) {
 	})
 }
 
+func TestGetBackupSchedules(t *testing.T) {
+	t.Parallel()
+
+	opts := vtadmin.Options{
+		RBAC: &rbac.Config{
+			Rules: []*struct {
+				Resource string
+				Actions  []string
+				Subjects []string
+				Clusters []string
+			}{
+				{
+					Resource: "Backup",
+					Actions:  []string{"get"},
+					Subjects: []string{"user:allowed-all"},
+					Clusters: []string{"*"},
+				},
+				{
+					Resource: "Backup",
+					Actions:  []string{"get"},
+					Subjects: []string{"user:allowed-other"},
+					Clusters: []string{"other"},
+				},
+			},
+		},
+	}
+	err := opts.RBAC.Reify()
+	require.NoError(t, err, "failed to reify authorization rules: %+v", opts.RBAC.Rules)
+
+	api := vtadmin.NewAPI(vtenv.NewTestEnv(), testClusters(t), opts)
+	t.Cleanup(func() {
+		if err := api.Close(); err != nil {
+			t.Logf("api did not close cleanly: %s", err.Error())
+		}
+	})
+
+	t.Run("unauthorized actor", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "unauthorized"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, err := api.GetBackupSchedules(ctx, &vtadminpb.GetBackupSchedulesRequest{})
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Schedules, "actor %+v should not be permitted to GetBackupSchedules", actor)
+	})
+
+	t.Run("partial access", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed-other"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, _ := api.GetBackupSchedules(ctx, &vtadminpb.GetBackupSchedulesRequest{})
+		require.Len(t, resp.Schedules, 1, "'other' actor should be able to see the schedules of cluster 'other'")
+		assert.Equal(t, "other", resp.Schedules[0].Cluster.Id)
+		assert.Equal(t, "otherks", resp.Schedules[0].Schedules[0].Keyspace)
+	})
+
+	t.Run("full access", func(t *testing.T) {
+		t.Parallel()
+
+		actor := &rbac.Actor{Name: "allowed-all"}
+		ctx := context.Background()
+		ctx = rbac.NewContext(ctx, actor)
+
+		resp, _ := api.GetBackupSchedules(ctx, &vtadminpb.GetBackupSchedulesRequest{})
+		assert.Len(t, resp.Schedules, 2, "'all' actor should be able to see the schedules of all clusters")
+	})
+}
+
 func TestGetCellInfos(t *testing.T) {
 	t.Parallel()
 
@@ -3526,6 +3599,18 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						},
 					},
 				},
+				GetBackupSchedulesResults: map[string]struct {
+					Response *vtctldatapb.GetBackupSchedulesResponse
+					Error    error
+				}{
+					"": {
+						Response: &vtctldatapb.GetBackupSchedulesResponse{
+							Schedules: []*vtctldatapb.BackupScheduleInfo{
+								{Keyspace: "test"},
+							},
+						},
+					},
+				},
 				GetBackupsResults: map[string]struct {
 					Response *vtctldatapb.GetBackupsResponse
 					Error    error
@@ -3804,6 +3889,18 @@ func testClusters(t testing.TB) []*cluster.Cluster {
 						},
 					},
 				},
+				GetBackupSchedulesResults: map[string]struct {
+					Response *vtctldatapb.GetBackupSchedulesResponse
+					Error    error
+				}{
+					"": {
+						Response: &vtctldatapb.GetBackupSchedulesResponse{
+							Schedules: []*vtctldatapb.BackupScheduleInfo{
+								{Keyspace: "otherks"},
+							},
+						},
+					},
+				},
 				GetBackupsResults: map[string]struct {
 					Response *vtctldatapb.GetBackupsResponse
 					Error    error
@@ -844,6 +844,44 @@ func (c *Cluster) findWorkflows(ctx context.Context, keyspaces []string, opts Fi
 	}, nil
 }
 
+// GetBackupSchedules returns the backup schedules of the cluster, and the
+// outcome of the scheduled backups of every shard. If keyspaces is not empty,
+// only the schedules of those keyspaces are returned.
+func (c *Cluster) GetBackupSchedules(ctx context.Context, keyspaces []string) (*vtadminpb.ClusterBackupSchedules, error) {
+	span, ctx := trace.NewSpan(ctx, "Cluster.GetBackupSchedules")
+	defer span.Finish()
+
+	AnnotateSpan(c, span)
+	span.Annotate("keyspaces", strings.Join(keyspaces, ","))
+
+	if len(keyspaces) == 0 {
+		// An empty keyspace gets the schedules of all the keyspaces.
+		keyspaces = []string{""}
+	}
+
+	result := &vtadminpb.ClusterBackupSchedules{
+		Cluster: c.ToProto(),
+	}
+	for _, keyspace := range keyspaces {
+		if err := c.topoReadPool.Acquire(ctx); err != nil {
+			return nil, fmt.Errorf("GetBackupSchedules(%s) failed to acquire topoReadPool: %w", keyspace, err)
+		}
+
+		resp, err := c.Vtctld.GetBackupSchedules(ctx, &vtctldatapb.GetBackupSchedulesRequest{
+			Keyspace: keyspace,
+		})
+		c.topoReadPool.Release()
+		if err != nil {
+			return nil, fmt.Errorf("GetBackupSchedules(%s): %w", keyspace, err)
+		}
+
+		result.Schedules = append(result.Schedules, resp.Schedules...)
+		result.Statuses = append(result.Statuses, resp.Statuses...)
+	}
+
+	return result, nil
+}
+
 // GetBackups returns a ClusterBackups object for all backups in the cluster.
 func (c *Cluster) GetBackups(ctx context.Context, req *vtadminpb.GetBackupsRequest) ([]*vtadminpb.ClusterBackup, error) {
 	span, ctx := trace.NewSpan(ctx, "Cluster.GetBackups")
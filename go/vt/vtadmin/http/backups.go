@@ -25,6 +25,19 @@ import (
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
+// GetBackupSchedules implements the http wrapper for
+// /backup_schedules[?cluster_id=[&cluster_id=]][&keyspace=[&keyspace=]].
+func GetBackupSchedules(ctx context.Context, r Request, api *API) *JSONResponse {
+	query := r.URL.Query()
+
+	schedules, err := api.server.GetBackupSchedules(ctx, &vtadminpb.GetBackupSchedulesRequest{
+		ClusterIds: query["cluster_id"],
+		Keyspaces:  query["keyspace"],
+	})
+
+	return NewJSONResponse(schedules, err)
+}
+
 // GetBackups implements the http wrapper for /backups[?cluster_id=[&cluster_id=]].
 func GetBackups(ctx context.Context, r Request, api *API) *JSONResponse {
 	query := r.URL.Query()
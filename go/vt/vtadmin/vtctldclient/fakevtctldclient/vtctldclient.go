@@ -69,6 +69,11 @@ type VtctldClient struct {
 		Response *vtctldatapb.FindAllShardsInKeyspaceResponse
 		Error    error
 	}
+	// Keyed by keyspace, which is empty for all keyspaces.
+	GetBackupSchedulesResults map[string]struct {
+		Response *vtctldatapb.GetBackupSchedulesResponse
+		Error    error
+	}
 	GetBackupsResults map[string]struct {
 		Response *vtctldatapb.GetBackupsResponse
 		Error    error
@@ -360,6 +365,19 @@ func (fake *VtctldClient) FindAllShardsInKeyspace(ctx context.Context, req *vtct
 	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.Keyspace)
 }
 
+// GetBackupSchedules is part of the vtctldclient.VtctldClient interface.
+func (fake *VtctldClient) GetBackupSchedules(ctx context.Context, req *vtctldatapb.GetBackupSchedulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupSchedulesResponse, error) {
+	if fake.GetBackupSchedulesResults == nil {
+		return nil, fmt.Errorf("%w: GetBackupSchedulesResults not set on fake vtctldclient", assert.AnError)
+	}
+
+	if result, ok := fake.GetBackupSchedulesResults[req.Keyspace]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no result set for keyspace %s", assert.AnError, req.Keyspace)
+}
+
 // GetBackups is part of the vtctldclient.VtctldClient interface.
 func (fake *VtctldClient) GetBackups(ctx context.Context, req *vtctldatapb.GetBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupsResponse, error) {
 	if fake.GetBackupsResults == nil {
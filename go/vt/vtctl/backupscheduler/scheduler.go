@@ -0,0 +1,485 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package backupscheduler takes backups of the shards that have a backup
+schedule in the topo.
+
+The schedules and the outcome of the backups are stored in the topo, and every
+check is done under a named lock, so that several vtctlds can run the
+scheduler: only one of them starts a given backup. The vtctld that started a
+backup records its outcome. A backup whose outcome is never recorded, e.g.
+because its vtctld was restarted, is treated as failed after the backup
+timeout.
+*/
+package backupscheduler
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// lockName is the name of the topo lock held while checking the schedules.
+	lockName = "backup_scheduler"
+	// defaultConcurrency is the number of files backed up in parallel when the
+	// schedule does not set it, which is the default of the Backup command.
+	defaultConcurrency = 4
+)
+
+// Config is the configuration of a Scheduler.
+type Config struct {
+	// CheckInterval is how often the schedules are checked.
+	CheckInterval time.Duration
+	// RetryInterval is how long to wait before retrying a failed backup.
+	RetryInterval time.Duration
+	// BackupTimeout is how long a backup can run before it is canceled and
+	// treated as failed.
+	BackupTimeout time.Duration
+}
+
+// Scheduler takes the backups of the shards that have a backup schedule.
+type Scheduler struct {
+	ts  *topo.Server
+	tmc tmclient.TabletManagerClient
+	cfg Config
+	now func() time.Time
+
+	// ctx is canceled when the scheduler is closed.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	opened bool
+}
+
+// New returns a Scheduler. It does nothing until it is opened.
+func New(ts *topo.Server, tmc tmclient.TabletManagerClient, cfg Config) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		ts:     ts,
+		tmc:    tmc,
+		cfg:    cfg,
+		now:    time.Now,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Open starts checking the schedules every CheckInterval.
+func (s *Scheduler) Open() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.opened {
+		return
+	}
+	s.opened = true
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.cfg.CheckInterval)
+		defer ticker.Stop()
+		for {
+			if err := s.check(s.ctx); err != nil {
+				log.Errorf("backup scheduler: %v", err)
+			}
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close stops checking the schedules, cancels the backups started by the
+// scheduler and waits for their outcome to be recorded. A closed scheduler
+// cannot be opened again.
+func (s *Scheduler) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Validate returns an error if the schedule cannot be used.
+func Validate(schedule *topodatapb.BackupSchedule) error {
+	if schedule == nil {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "backup schedule must be set")
+	}
+	full, _, err := protoutil.DurationFromProto(schedule.FullBackupInterval)
+	if err != nil {
+		return vterrors.Wrapf(err, "invalid full backup interval")
+	}
+	incremental, _, err := protoutil.DurationFromProto(schedule.IncrementalBackupInterval)
+	if err != nil {
+		return vterrors.Wrapf(err, "invalid incremental backup interval")
+	}
+	if full < 0 || incremental < 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "backup intervals cannot be negative")
+	}
+	if full == 0 && incremental == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "at least one of the full and incremental backup intervals must be set")
+	}
+	switch schedule.TabletType {
+	case topodatapb.TabletType_UNKNOWN, topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY, topodatapb.TabletType_SPARE:
+	default:
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "backups cannot be scheduled on %v tablets", schedule.TabletType)
+	}
+	if schedule.MaxConcurrentBackupsPerCell < 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "max concurrent backups per cell cannot be negative")
+	}
+	if schedule.Concurrency < 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "concurrency cannot be negative")
+	}
+	return nil
+}
+
+// shardState is what the scheduler knows about a shard of a keyspace.
+type shardState struct {
+	keyspace string
+	shard    string
+	schedule *topodatapb.BackupSchedule
+	status   *topodatapb.BackupScheduleStatus
+}
+
+// check starts the backups that are due.
+func (s *Scheduler) check(ctx context.Context) (err error) {
+	ctx, unlock, err := s.ts.LockName(ctx, lockName, "backup scheduler check")
+	if err != nil {
+		return err
+	}
+	defer unlock(&err)
+
+	keyspaces, err := s.ts.GetKeyspaces(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, keyspace := range keyspaces {
+		if err := s.checkKeyspace(ctx, keyspace); err != nil {
+			errs = append(errs, fmt.Errorf("keyspace %s: %w", keyspace, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Scheduler) checkKeyspace(ctx context.Context, keyspace string) error {
+	shardNames, err := s.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+
+	// runningPerCell counts the scheduled backups of the keyspace that are
+	// running in every cell.
+	runningPerCell := make(map[string]int32)
+	shards := make([]*shardState, 0, len(shardNames))
+	for _, shard := range shardNames {
+		schedule, err := s.ts.GetShardBackupSchedule(ctx, keyspace, shard)
+		if err != nil {
+			return err
+		}
+		status, err := s.expireRuns(ctx, keyspace, shard)
+		if err != nil {
+			return err
+		}
+		for _, run := range []*topodatapb.BackupScheduleRun{status.LastFullBackup, status.LastIncrementalBackup} {
+			if isRunning(run) {
+				runningPerCell[run.TabletAlias.GetCell()]++
+			}
+		}
+		if schedule != nil && !schedule.Paused {
+			shards = append(shards, &shardState{keyspace: keyspace, shard: shard, schedule: schedule, status: status})
+		}
+	}
+
+	var errs []error
+	for _, state := range shards {
+		if isRunning(state.status.LastFullBackup) || isRunning(state.status.LastIncrementalBackup) {
+			continue
+		}
+		incremental, due := s.due(state)
+		if !due {
+			continue
+		}
+		tablet, err := s.pickTablet(ctx, state, runningPerCell)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("shard %s: %w", state.shard, err))
+			continue
+		}
+		if tablet == nil {
+			// Every candidate is in a cell that already runs as many backups
+			// as allowed.
+			continue
+		}
+		if err := s.startBackup(ctx, state, tablet, incremental); err != nil {
+			errs = append(errs, fmt.Errorf("shard %s: %w", state.shard, err))
+			continue
+		}
+		runningPerCell[tablet.Alias.Cell]++
+	}
+	return errors.Join(errs...)
+}
+
+// expireRuns marks the backups of the shard that ran for longer than the
+// backup timeout as failed, and returns the status of the shard.
+func (s *Scheduler) expireRuns(ctx context.Context, keyspace, shard string) (*topodatapb.BackupScheduleStatus, error) {
+	return s.ts.UpdateBackupScheduleStatus(ctx, keyspace, shard, func(status *topodatapb.BackupScheduleStatus) error {
+		now := s.now()
+		for _, run := range []*topodatapb.BackupScheduleRun{status.LastFullBackup, status.LastIncrementalBackup} {
+			if isRunning(run) && now.Sub(protoutil.TimeFromProto(run.StartedAt)) >= s.cfg.BackupTimeout {
+				run.FinishedAt = protoutil.TimeToProto(now)
+				run.Error = fmt.Sprintf("backup did not finish within %v", s.cfg.BackupTimeout)
+			}
+		}
+		return nil
+	})
+}
+
+// due returns whether a backup of the shard is due, and whether it is an
+// incremental backup.
+func (s *Scheduler) due(state *shardState) (incremental bool, due bool) {
+	fullInterval, _, _ := protoutil.DurationFromProto(state.schedule.FullBackupInterval)
+	incrementalInterval, _, _ := protoutil.DurationFromProto(state.schedule.IncrementalBackupInterval)
+
+	lastFull := state.status.LastFullBackup
+	if fullInterval > 0 && s.runDue(lastFull, fullInterval) {
+		return false, true
+	}
+
+	if incrementalInterval <= 0 {
+		return false, false
+	}
+	if lastFull == nil || lastFull.Error != "" {
+		// Incremental backups apply on top of a full backup, which is taken
+		// first when only incremental backups are scheduled.
+		return false, fullInterval <= 0 && s.runDue(lastFull, incrementalInterval)
+	}
+	last := state.status.LastIncrementalBackup
+	if last == nil || (last.Error == "" && protoutil.TimeFromProto(lastFull.StartedAt).After(protoutil.TimeFromProto(last.StartedAt))) {
+		last = lastFull
+	}
+	return true, s.runDue(last, incrementalInterval)
+}
+
+// runDue returns whether a backup that last ran as run is due again.
+func (s *Scheduler) runDue(run *topodatapb.BackupScheduleRun, interval time.Duration) bool {
+	now := s.now()
+	switch {
+	case run == nil:
+		return true
+	case run.Error != "":
+		return now.Sub(protoutil.TimeFromProto(run.FinishedAt)) >= s.cfg.RetryInterval
+	default:
+		return now.Sub(protoutil.TimeFromProto(run.StartedAt)) >= interval
+	}
+}
+
+// pickTablet returns the tablet to take the backup of the shard on, or nil if
+// the only candidates are in cells that already run as many backups as
+// allowed. Candidates are healthy REPLICA, RDONLY and SPARE tablets that are
+// not serving-critical, i.e. are not the only serving tablet of their type in
+// their cell. They are ordered by preferred tablet type, preferred cell, and
+// replication lag.
+func (s *Scheduler) pickTablet(ctx context.Context, state *shardState, runningPerCell map[string]int32) (*topodatapb.Tablet, error) {
+	tablets, stats, err := reparentutil.ShardReplicationStatuses(ctx, s.ts, s.tmc, state.keyspace, state.shard)
+	if err != nil && len(tablets) == 0 {
+		return nil, err
+	}
+
+	type candidate struct {
+		tablet *topodatapb.Tablet
+		lag    uint32
+	}
+	var candidates []candidate
+	healthyPerCellAndType := make(map[string]int)
+	for i, ti := range tablets {
+		switch ti.Type {
+		case topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY, topodatapb.TabletType_SPARE:
+		default:
+			continue
+		}
+		if stats[i] == nil || stats[i].ReplicationLagUnknown {
+			continue
+		}
+		healthyPerCellAndType[ti.Alias.Cell+"/"+ti.Type.String()]++
+		candidates = append(candidates, candidate{tablet: ti.Tablet, lag: stats[i].ReplicationLagSeconds})
+	}
+
+	candidates = slices.DeleteFunc(candidates, func(c candidate) bool {
+		return c.tablet.Type != topodatapb.TabletType_SPARE && healthyPerCellAndType[c.tablet.Alias.Cell+"/"+c.tablet.Type.String()] < 2
+	})
+	if len(candidates) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no tablet available for backup")
+	}
+
+	schedule := state.schedule
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if schedule.TabletType != topodatapb.TabletType_UNKNOWN {
+			if c := cmp.Compare(boolRank(a.tablet.Type == schedule.TabletType), boolRank(b.tablet.Type == schedule.TabletType)); c != 0 {
+				return c
+			}
+		}
+		if schedule.Cell != "" {
+			if c := cmp.Compare(boolRank(a.tablet.Alias.Cell == schedule.Cell), boolRank(b.tablet.Alias.Cell == schedule.Cell)); c != 0 {
+				return c
+			}
+		}
+		if c := cmp.Compare(a.lag, b.lag); c != 0 {
+			return c
+		}
+		return cmp.Compare(topoproto.TabletAliasString(a.tablet.Alias), topoproto.TabletAliasString(b.tablet.Alias))
+	})
+
+	for _, c := range candidates {
+		if schedule.MaxConcurrentBackupsPerCell > 0 && runningPerCell[c.tablet.Alias.Cell] >= schedule.MaxConcurrentBackupsPerCell {
+			continue
+		}
+		return c.tablet, nil
+	}
+	return nil, nil
+}
+
+// boolRank sorts true before false.
+func boolRank(b bool) int {
+	if b {
+		return 0
+	}
+	return 1
+}
+
+// startBackup records the backup in the status of the shard and starts it.
+func (s *Scheduler) startBackup(ctx context.Context, state *shardState, tablet *topodatapb.Tablet, incremental bool) error {
+	run := &topodatapb.BackupScheduleRun{
+		TabletAlias: tablet.Alias,
+		StartedAt:   protoutil.TimeToProto(s.now()),
+	}
+	_, err := s.ts.UpdateBackupScheduleStatus(ctx, state.keyspace, state.shard, func(status *topodatapb.BackupScheduleStatus) error {
+		if incremental {
+			status.LastIncrementalBackup = run
+		} else {
+			status.LastFullBackup = run
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	req := &tabletmanagerdatapb.BackupRequest{
+		Concurrency: state.schedule.Concurrency,
+	}
+	if req.Concurrency == 0 {
+		req.Concurrency = defaultConcurrency
+	}
+	if incremental {
+		req.IncrementalFromPos = "auto"
+	}
+	if state.schedule.BackupEngine != "" {
+		req.BackupEngine = &state.schedule.BackupEngine
+	}
+	log.Infof("backup scheduler: starting %s backup of %s/%s on %s", backupKind(incremental), state.keyspace, state.shard, topoproto.TabletAliasString(tablet.Alias))
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		backupErr := s.runBackup(tablet, req)
+		if backupErr != nil {
+			log.Errorf("backup scheduler: %s backup of %s/%s on %s failed: %v", backupKind(incremental), state.keyspace, state.shard, topoproto.TabletAliasString(tablet.Alias), backupErr)
+		} else {
+			log.Infof("backup scheduler: %s backup of %s/%s on %s succeeded", backupKind(incremental), state.keyspace, state.shard, topoproto.TabletAliasString(tablet.Alias))
+		}
+		if err := s.finishBackup(state.keyspace, state.shard, run, incremental, backupErr); err != nil {
+			log.Errorf("backup scheduler: failed to record the outcome of the backup of %s/%s: %v", state.keyspace, state.shard, err)
+		}
+	}()
+	return nil
+}
+
+func backupKind(incremental bool) string {
+	if incremental {
+		return "incremental"
+	}
+	return "full"
+}
+
+// runBackup takes the backup and waits for it to finish.
+func (s *Scheduler) runBackup(tablet *topodatapb.Tablet, req *tabletmanagerdatapb.BackupRequest) error {
+	ctx, cancel := context.WithTimeout(s.ctx, s.cfg.BackupTimeout)
+	defer cancel()
+
+	stream, err := s.tmc.Backup(ctx, tablet, req)
+	if err != nil {
+		return err
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// finishBackup records the outcome of a backup, unless it was already
+// recorded as timed out.
+func (s *Scheduler) finishBackup(keyspace, shard string, run *topodatapb.BackupScheduleRun, incremental bool, backupErr error) error {
+	// The scheduler may be closing, the outcome is recorded anyway.
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+
+	_, err := s.ts.UpdateBackupScheduleStatus(ctx, keyspace, shard, func(status *topodatapb.BackupScheduleStatus) error {
+		current := status.LastFullBackup
+		if incremental {
+			current = status.LastIncrementalBackup
+		}
+		if current == nil || !isRunning(current) || !topoproto.TabletAliasEqual(current.TabletAlias, run.TabletAlias) || !proto.Equal(current.StartedAt, run.StartedAt) {
+			return nil
+		}
+		current.FinishedAt = protoutil.TimeToProto(s.now())
+		if backupErr != nil {
+			current.Error = backupErr.Error()
+		}
+		return nil
+	})
+	return err
+}
+
+// isRunning returns whether the backup is still running.
+func isRunning(run *topodatapb.BackupScheduleRun) bool {
+	return run != nil && run.StartedAt != nil && run.FinishedAt == nil
+}
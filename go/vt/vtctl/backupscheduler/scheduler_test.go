@@ -0,0 +1,391 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupscheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
+)
+
+type backupCall struct {
+	alias string
+	req   *tabletmanagerdatapb.BackupRequest
+}
+
+// fakeTMC returns the replication status of the tablets and records the
+// backups, which fail with backupErr.
+type fakeTMC struct {
+	tmclient.TabletManagerClient
+
+	lags map[string]uint32
+
+	mu        sync.Mutex
+	backups   []backupCall
+	backupErr error
+}
+
+func (tmc *fakeTMC) PrimaryPosition(ctx context.Context, tablet *topodatapb.Tablet) (string, error) {
+	return "", nil
+}
+
+func (tmc *fakeTMC) ReplicationStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
+	lag, ok := tmc.lags[topoproto.TabletAliasString(tablet.Alias)]
+	if !ok {
+		return nil, errors.New("unreachable")
+	}
+	return &replicationdatapb.Status{ReplicationLagSeconds: lag}, nil
+}
+
+func (tmc *fakeTMC) Backup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.BackupRequest) (logutil.EventStream, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tmc.backups = append(tmc.backups, backupCall{alias: topoproto.TabletAliasString(tablet.Alias), req: req})
+	return &fakeEventStream{err: tmc.backupErr}, nil
+}
+
+func (tmc *fakeTMC) calls() []backupCall {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	return tmc.backups
+}
+
+type fakeEventStream struct {
+	err error
+}
+
+func (s *fakeEventStream) Recv() (*logutilpb.Event, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return nil, io.EOF
+}
+
+func newTablet(cell string, uid uint32, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+	return &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: cell, Uid: uid},
+		Keyspace: "ks",
+		Shard:    "0",
+		Type:     tabletType,
+	}
+}
+
+func setup(t *testing.T, tablets ...*topodatapb.Tablet) (context.Context, *topo.Server) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	t.Cleanup(ts.Close)
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "0"))
+	for _, tablet := range tablets {
+		require.NoError(t, ts.CreateTablet(ctx, tablet))
+	}
+	return ctx, ts
+}
+
+func TestValidate(t *testing.T) {
+	hour := &vttimepb.Duration{Seconds: 3600}
+	tests := []struct {
+		name     string
+		schedule *topodatapb.BackupSchedule
+		wantErr  string
+	}{
+		{
+			name:     "full",
+			schedule: &topodatapb.BackupSchedule{FullBackupInterval: hour, TabletType: topodatapb.TabletType_RDONLY},
+		},
+		{
+			name:     "incremental only",
+			schedule: &topodatapb.BackupSchedule{IncrementalBackupInterval: hour},
+		},
+		{
+			name:    "nil",
+			wantErr: "must be set",
+		},
+		{
+			name:     "no interval",
+			schedule: &topodatapb.BackupSchedule{},
+			wantErr:  "at least one",
+		},
+		{
+			name:     "negative interval",
+			schedule: &topodatapb.BackupSchedule{FullBackupInterval: &vttimepb.Duration{Seconds: -1}},
+			wantErr:  "negative",
+		},
+		{
+			name:     "primary",
+			schedule: &topodatapb.BackupSchedule{FullBackupInterval: hour, TabletType: topodatapb.TabletType_PRIMARY},
+			wantErr:  "cannot be scheduled on PRIMARY tablets",
+		},
+		{
+			name:     "negative max concurrent backups",
+			schedule: &topodatapb.BackupSchedule{FullBackupInterval: hour, MaxConcurrentBackupsPerCell: -1},
+			wantErr:  "max concurrent backups per cell cannot be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.schedule)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPickTablet(t *testing.T) {
+	tablets := []*topodatapb.Tablet{
+		newTablet("zone1", 100, topodatapb.TabletType_PRIMARY),
+		newTablet("zone1", 101, topodatapb.TabletType_REPLICA),
+		newTablet("zone1", 102, topodatapb.TabletType_REPLICA),
+		newTablet("zone1", 103, topodatapb.TabletType_RDONLY),
+		newTablet("zone1", 104, topodatapb.TabletType_REPLICA),
+		newTablet("zone2", 200, topodatapb.TabletType_REPLICA),
+		newTablet("zone2", 201, topodatapb.TabletType_SPARE),
+	}
+	ctx, ts := setup(t, tablets...)
+	tmc := &fakeTMC{lags: map[string]uint32{
+		"zone1-0000000101": 5,
+		"zone1-0000000102": 1,
+		"zone1-0000000103": 0,
+		// zone1-0000000104 is unreachable.
+		"zone2-0000000200": 0,
+		"zone2-0000000201": 3,
+	}}
+	s := New(ts, tmc, Config{})
+
+	tests := []struct {
+		name           string
+		schedule       *topodatapb.BackupSchedule
+		runningPerCell map[string]int32
+		want           string
+	}{
+		{
+			// The RDONLY tablet of zone1 and the REPLICA tablet of zone2
+			// are serving-critical, and zone1-0000000104 is unreachable.
+			name:     "lowest lag",
+			schedule: &topodatapb.BackupSchedule{},
+			want:     "zone1-0000000102",
+		},
+		{
+			name:     "preferred tablet type",
+			schedule: &topodatapb.BackupSchedule{TabletType: topodatapb.TabletType_SPARE},
+			want:     "zone2-0000000201",
+		},
+		{
+			name:     "preferred cell",
+			schedule: &topodatapb.BackupSchedule{Cell: "zone2"},
+			want:     "zone2-0000000201",
+		},
+		{
+			name:     "preferred tablet type unavailable",
+			schedule: &topodatapb.BackupSchedule{TabletType: topodatapb.TabletType_RDONLY},
+			want:     "zone1-0000000102",
+		},
+		{
+			name:           "cell at max concurrent backups",
+			schedule:       &topodatapb.BackupSchedule{MaxConcurrentBackupsPerCell: 1},
+			runningPerCell: map[string]int32{"zone1": 1},
+			want:           "zone2-0000000201",
+		},
+		{
+			name:           "all cells at max concurrent backups",
+			schedule:       &topodatapb.BackupSchedule{MaxConcurrentBackupsPerCell: 1},
+			runningPerCell: map[string]int32{"zone1": 1, "zone2": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &shardState{keyspace: "ks", shard: "0", schedule: tt.schedule}
+			runningPerCell := tt.runningPerCell
+			if runningPerCell == nil {
+				runningPerCell = map[string]int32{}
+			}
+			tablet, err := s.pickTablet(ctx, state, runningPerCell)
+			require.NoError(t, err)
+			if tt.want == "" {
+				assert.Nil(t, tablet)
+				return
+			}
+			require.NotNil(t, tablet)
+			assert.Equal(t, tt.want, topoproto.TabletAliasString(tablet.Alias))
+		})
+	}
+
+	// A shard whose only replica is serving-critical is not backed up.
+	_, ts = setup(t, newTablet("zone1", 100, topodatapb.TabletType_PRIMARY), newTablet("zone1", 101, topodatapb.TabletType_REPLICA))
+	s = New(ts, tmc, Config{})
+	_, err := s.pickTablet(ctx, &shardState{keyspace: "ks", shard: "0", schedule: &topodatapb.BackupSchedule{}}, map[string]int32{})
+	assert.ErrorContains(t, err, "no tablet available for backup")
+}
+
+func TestCheck(t *testing.T) {
+	ctx, ts := setup(t,
+		newTablet("zone1", 100, topodatapb.TabletType_PRIMARY),
+		newTablet("zone1", 101, topodatapb.TabletType_REPLICA),
+		newTablet("zone1", 102, topodatapb.TabletType_REPLICA),
+	)
+	tmc := &fakeTMC{lags: map[string]uint32{"zone1-0000000101": 0, "zone1-0000000102": 0}}
+	s := New(ts, tmc, Config{
+		RetryInterval: 5 * time.Minute,
+		BackupTimeout: time.Hour,
+	})
+	defer s.Close()
+	now := time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	check := func() *topodatapb.BackupScheduleStatus {
+		t.Helper()
+		require.NoError(t, s.check(ctx))
+		// Wait for the outcome of the backups to be recorded.
+		s.wg.Wait()
+		status, err := ts.GetBackupScheduleStatus(ctx, "ks", "0")
+		require.NoError(t, err)
+		return status
+	}
+
+	// Nothing happens without a schedule.
+	check()
+	assert.Empty(t, tmc.calls())
+
+	require.NoError(t, ts.SaveBackupSchedule(ctx, "ks", "", &topodatapb.BackupSchedule{
+		FullBackupInterval:        protoutil.DurationToProto(24 * time.Hour),
+		IncrementalBackupInterval: protoutil.DurationToProto(time.Hour),
+		Concurrency:               8,
+	}))
+
+	// The first backup is a full one.
+	status := check()
+	require.Len(t, tmc.calls(), 1)
+	assert.Empty(t, tmc.calls()[0].req.IncrementalFromPos)
+	assert.EqualValues(t, 8, tmc.calls()[0].req.Concurrency)
+	require.NotNil(t, status.LastFullBackup)
+	assert.Equal(t, now, protoutil.TimeFromProto(status.LastFullBackup.StartedAt).UTC())
+	assert.NotNil(t, status.LastFullBackup.FinishedAt)
+	assert.Empty(t, status.LastFullBackup.Error)
+
+	// Nothing is due yet.
+	now = now.Add(30 * time.Minute)
+	check()
+	assert.Len(t, tmc.calls(), 1)
+
+	// An incremental backup is due an hour after the full one.
+	now = now.Add(30 * time.Minute)
+	status = check()
+	require.Len(t, tmc.calls(), 2)
+	assert.Equal(t, "auto", tmc.calls()[1].req.IncrementalFromPos)
+	require.NotNil(t, status.LastIncrementalBackup)
+	assert.Empty(t, status.LastIncrementalBackup.Error)
+
+	// A failed backup is retried after the retry interval.
+	tmc.backupErr = errors.New("boom")
+	now = now.Add(time.Hour)
+	status = check()
+	require.Len(t, tmc.calls(), 3)
+	assert.Equal(t, "boom", status.LastIncrementalBackup.Error)
+
+	now = now.Add(time.Minute)
+	check()
+	assert.Len(t, tmc.calls(), 3)
+
+	tmc.backupErr = nil
+	now = now.Add(5 * time.Minute)
+	status = check()
+	require.Len(t, tmc.calls(), 4)
+	assert.Empty(t, status.LastIncrementalBackup.Error)
+
+	// A full backup is due a day after the previous one.
+	now = now.Add(22 * time.Hour)
+	check()
+	require.Len(t, tmc.calls(), 5)
+	assert.Empty(t, tmc.calls()[4].req.IncrementalFromPos)
+
+	// Paused schedules are not checked.
+	require.NoError(t, ts.SaveBackupSchedule(ctx, "ks", "0", &topodatapb.BackupSchedule{
+		FullBackupInterval: protoutil.DurationToProto(time.Hour),
+		Paused:             true,
+	}))
+	now = now.Add(48 * time.Hour)
+	check()
+	assert.Len(t, tmc.calls(), 5)
+}
+
+func TestCheckExpiresRuns(t *testing.T) {
+	ctx, ts := setup(t,
+		newTablet("zone1", 101, topodatapb.TabletType_REPLICA),
+		newTablet("zone1", 102, topodatapb.TabletType_REPLICA),
+	)
+	tmc := &fakeTMC{lags: map[string]uint32{"zone1-0000000101": 0, "zone1-0000000102": 0}}
+	s := New(ts, tmc, Config{
+		RetryInterval: 5 * time.Minute,
+		BackupTimeout: time.Hour,
+	})
+	defer s.Close()
+	now := time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, ts.SaveBackupSchedule(ctx, "ks", "", &topodatapb.BackupSchedule{
+		FullBackupInterval: protoutil.DurationToProto(24 * time.Hour),
+	}))
+	// A backup started by another vtctld that never recorded its outcome.
+	_, err := ts.UpdateBackupScheduleStatus(ctx, "ks", "0", func(status *topodatapb.BackupScheduleStatus) error {
+		status.LastFullBackup = &topodatapb.BackupScheduleRun{
+			TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+			StartedAt:   protoutil.TimeToProto(now),
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, s.check(ctx))
+	assert.Empty(t, tmc.calls())
+
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, s.check(ctx))
+	assert.Empty(t, tmc.calls())
+	status, err := ts.GetBackupScheduleStatus(ctx, "ks", "0")
+	require.NoError(t, err)
+	assert.Equal(t, "backup did not finish within 1h0m0s", status.LastFullBackup.Error)
+
+	now = now.Add(5 * time.Minute)
+	require.NoError(t, s.check(ctx))
+	s.wg.Wait()
+	assert.Len(t, tmc.calls(), 1)
+}
@@ -47,6 +47,15 @@ func (client *gRPCVtctldClient) AddCellsAlias(ctx context.Context, in *vtctldata
 	return client.c.AddCellsAlias(ctx, in, opts...)
 }
 
+// AddRecoverySilence is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) AddRecoverySilence(ctx context.Context, in *vtctldatapb.AddRecoverySilenceRequest, opts ...grpc.CallOption) (*vtctldatapb.AddRecoverySilenceResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.AddRecoverySilence(ctx, in, opts...)
+}
+
 // ApplyKeyspaceRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ApplyKeyspaceRoutingRules(ctx context.Context, in *vtctldatapb.ApplyKeyspaceRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyKeyspaceRoutingRulesResponse, error) {
 	if client.c == nil {
@@ -200,6 +209,15 @@ func (client *gRPCVtctldClient) CreateShard(ctx context.Context, in *vtctldatapb
 	return client.c.CreateShard(ctx, in, opts...)
 }
 
+// DeleteBackupSchedule is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) DeleteBackupSchedule(ctx context.Context, in *vtctldatapb.DeleteBackupScheduleRequest, opts ...grpc.CallOption) (*vtctldatapb.DeleteBackupScheduleResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.DeleteBackupSchedule(ctx, in, opts...)
+}
+
 // DeleteCellInfo is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) DeleteCellInfo(ctx context.Context, in *vtctldatapb.DeleteCellInfoRequest, opts ...grpc.CallOption) (*vtctldatapb.DeleteCellInfoResponse, error) {
 	if client.c == nil {
@@ -254,6 +272,15 @@ func (client *gRPCVtctldClient) DeleteTablets(ctx context.Context, in *vtctldata
 	return client.c.DeleteTablets(ctx, in, opts...)
 }
 
+// DiffConfigVersions is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) DiffConfigVersions(ctx context.Context, in *vtctldatapb.DiffConfigVersionsRequest, opts ...grpc.CallOption) (*vtctldatapb.DiffConfigVersionsResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.DiffConfigVersions(ctx, in, opts...)
+}
+
 // EmergencyReparentShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) EmergencyReparentShard(ctx context.Context, in *vtctldatapb.EmergencyReparentShardRequest, opts ...grpc.CallOption) (*vtctldatapb.EmergencyReparentShardResponse, error) {
 	if client.c == nil {
@@ -299,6 +326,15 @@ func (client *gRPCVtctldClient) ExecuteMultiFetchAsDBA(ctx context.Context, in *
 	return client.c.ExecuteMultiFetchAsDBA(ctx, in, opts...)
 }
 
+// ExportCreate is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ExportCreate(ctx context.Context, in *vtctldatapb.ExportCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.ExportCreateResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ExportCreate(ctx, in, opts...)
+}
+
 // FindAllShardsInKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) FindAllShardsInKeyspace(ctx context.Context, in *vtctldatapb.FindAllShardsInKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.FindAllShardsInKeyspaceResponse, error) {
 	if client.c == nil {
@@ -317,6 +353,24 @@ func (client *gRPCVtctldClient) ForceCutOverSchemaMigration(ctx context.Context,
 	return client.c.ForceCutOverSchemaMigration(ctx, in, opts...)
 }
 
+// GetBackupProgress is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetBackupProgress(ctx context.Context, in *vtctldatapb.GetBackupProgressRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupProgressResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetBackupProgress(ctx, in, opts...)
+}
+
+// GetBackupSchedules is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetBackupSchedules(ctx context.Context, in *vtctldatapb.GetBackupSchedulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupSchedulesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetBackupSchedules(ctx, in, opts...)
+}
+
 // GetBackups is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetBackups(ctx context.Context, in *vtctldatapb.GetBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupsResponse, error) {
 	if client.c == nil {
@@ -353,6 +407,15 @@ func (client *gRPCVtctldClient) GetCellsAliases(ctx context.Context, in *vtctlda
 	return client.c.GetCellsAliases(ctx, in, opts...)
 }
 
+// GetConfigHistory is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetConfigHistory(ctx context.Context, in *vtctldatapb.GetConfigHistoryRequest, opts ...grpc.CallOption) (*vtctldatapb.GetConfigHistoryResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetConfigHistory(ctx, in, opts...)
+}
+
 // GetFullStatus is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetFullStatus(ctx context.Context, in *vtctldatapb.GetFullStatusRequest, opts ...grpc.CallOption) (*vtctldatapb.GetFullStatusResponse, error) {
 	if client.c == nil {
@@ -407,6 +470,15 @@ func (client *gRPCVtctldClient) GetPermissions(ctx context.Context, in *vtctldat
 	return client.c.GetPermissions(ctx, in, opts...)
 }
 
+// GetRecoverySilences is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetRecoverySilences(ctx context.Context, in *vtctldatapb.GetRecoverySilencesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRecoverySilencesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetRecoverySilences(ctx, in, opts...)
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetRoutingRules(ctx context.Context, in *vtctldatapb.GetRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRoutingRulesResponse, error) {
 	if client.c == nil {
@@ -803,6 +875,15 @@ func (client *gRPCVtctldClient) RemoveKeyspaceCell(ctx context.Context, in *vtct
 	return client.c.RemoveKeyspaceCell(ctx, in, opts...)
 }
 
+// RemoveRecoverySilence is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RemoveRecoverySilence(ctx context.Context, in *vtctldatapb.RemoveRecoverySilenceRequest, opts ...grpc.CallOption) (*vtctldatapb.RemoveRecoverySilenceResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RemoveRecoverySilence(ctx, in, opts...)
+}
+
 // RemoveShardCell is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) RemoveShardCell(ctx context.Context, in *vtctldatapb.RemoveShardCellRequest, opts ...grpc.CallOption) (*vtctldatapb.RemoveShardCellResponse, error) {
 	if client.c == nil {
@@ -839,6 +920,24 @@ func (client *gRPCVtctldClient) RestoreFromBackup(ctx context.Context, in *vtctl
 	return client.c.RestoreFromBackup(ctx, in, opts...)
 }
 
+// RestoreTableCreate is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RestoreTableCreate(ctx context.Context, in *vtctldatapb.RestoreTableCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowStatusResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RestoreTableCreate(ctx, in, opts...)
+}
+
+// RestoreTopology is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RestoreTopology(ctx context.Context, in *vtctldatapb.RestoreTopologyRequest, opts ...grpc.CallOption) (*vtctldatapb.RestoreTopologyResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RestoreTopology(ctx, in, opts...)
+}
+
 // RetrySchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) RetrySchemaMigration(ctx context.Context, in *vtctldatapb.RetrySchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
 	if client.c == nil {
@@ -848,6 +947,15 @@ func (client *gRPCVtctldClient) RetrySchemaMigration(ctx context.Context, in *vt
 	return client.c.RetrySchemaMigration(ctx, in, opts...)
 }
 
+// RollbackConfig is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RollbackConfig(ctx context.Context, in *vtctldatapb.RollbackConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.RollbackConfigResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RollbackConfig(ctx, in, opts...)
+}
+
 // RunHealthCheck is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) RunHealthCheck(ctx context.Context, in *vtctldatapb.RunHealthCheckRequest, opts ...grpc.CallOption) (*vtctldatapb.RunHealthCheckResponse, error) {
 	if client.c == nil {
@@ -857,6 +965,15 @@ func (client *gRPCVtctldClient) RunHealthCheck(ctx context.Context, in *vtctldat
 	return client.c.RunHealthCheck(ctx, in, opts...)
 }
 
+// SetBackupSchedule is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SetBackupSchedule(ctx context.Context, in *vtctldatapb.SetBackupScheduleRequest, opts ...grpc.CallOption) (*vtctldatapb.SetBackupScheduleResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SetBackupSchedule(ctx, in, opts...)
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceDurabilityPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceDurabilityPolicyResponse, error) {
 	if client.c == nil {
@@ -938,6 +1055,15 @@ func (client *gRPCVtctldClient) SleepTablet(ctx context.Context, in *vtctldatapb
 	return client.c.SleepTablet(ctx, in, opts...)
 }
 
+// SnapshotTopology is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SnapshotTopology(ctx context.Context, in *vtctldatapb.SnapshotTopologyRequest, opts ...grpc.CallOption) (*vtctldatapb.SnapshotTopologyResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SnapshotTopology(ctx, in, opts...)
+}
+
 // SourceShardAdd is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) SourceShardAdd(ctx context.Context, in *vtctldatapb.SourceShardAddRequest, opts ...grpc.CallOption) (*vtctldatapb.SourceShardAddResponse, error) {
 	if client.c == nil {
@@ -1028,6 +1154,15 @@ func (client *gRPCVtctldClient) VDiffDelete(ctx context.Context, in *vtctldatapb
 	return client.c.VDiffDelete(ctx, in, opts...)
 }
 
+// VDiffRepair is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) VDiffRepair(ctx context.Context, in *vtctldatapb.VDiffRepairRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffRepairResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.VDiffRepair(ctx, in, opts...)
+}
+
 // VDiffResume is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) VDiffResume(ctx context.Context, in *vtctldatapb.VDiffResumeRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffResumeResponse, error) {
 	if client.c == nil {
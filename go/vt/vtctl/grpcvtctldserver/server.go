@@ -68,9 +68,11 @@ import (
 	"vitess.io/vitess/go/vt/schemamanager"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/helpers"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/topotools/events"
+	"vitess.io/vitess/go/vt/vtctl/backupscheduler"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
 	"vitess.io/vitess/go/vt/vtctl/schematools"
@@ -108,6 +110,11 @@ func NewVtctldServer(env *vtenv.Environment, ts *topo.Server) *VtctldServer {
 	}
 }
 
+// WorkflowServer returns the workflow server of the VtctldServer.
+func (s *VtctldServer) WorkflowServer() *workflow.Server {
+	return s.ws
+}
+
 // NewTestVtctldServer returns a new VtctldServer for the given topo server
 // AND tmclient for use in tests. This should NOT be used in production.
 func NewTestVtctldServer(ts *topo.Server, tmc tmclient.TabletManagerClient) *VtctldServer {
@@ -170,6 +177,35 @@ func (s *VtctldServer) AddCellsAlias(ctx context.Context, req *vtctldatapb.AddCe
 	return &vtctldatapb.AddCellsAliasResponse{}, nil
 }
 
+// AddRecoverySilence is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) AddRecoverySilence(ctx context.Context, req *vtctldatapb.AddRecoverySilenceRequest) (resp *vtctldatapb.AddRecoverySilenceResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.AddRecoverySilence")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	if req.Silence == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "silence is required")
+	}
+
+	span.Annotate("keyspace", req.Silence.Keyspace)
+	span.Annotate("shard", req.Silence.Shard)
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.Silence.TabletAlias))
+	span.Annotate("analysis_codes", strings.Join(req.Silence.AnalysisCodes, ","))
+
+	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer cancel()
+
+	silence, err := s.ts.AddRecoverySilence(ctx, req.Silence)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.AddRecoverySilenceResponse{
+		Silence: silence,
+	}, nil
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ApplyRoutingRules(ctx context.Context, req *vtctldatapb.ApplyRoutingRulesRequest) (resp *vtctldatapb.ApplyRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyRoutingRules")
@@ -1082,6 +1118,27 @@ func (s *VtctldServer) CreateShard(ctx context.Context, req *vtctldatapb.CreateS
 	}, nil
 }
 
+// DeleteBackupSchedule is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) DeleteBackupSchedule(ctx context.Context, req *vtctldatapb.DeleteBackupScheduleRequest) (resp *vtctldatapb.DeleteBackupScheduleResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.DeleteBackupSchedule")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+
+	err = s.ts.DeleteBackupSchedule(ctx, req.Keyspace, req.Shard)
+	if topo.IsErrType(err, topo.NoNode) {
+		err = vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no backup schedule for %s", backupScheduleTarget(req.Keyspace, req.Shard))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.DeleteBackupScheduleResponse{}, nil
+}
+
 // DeleteCellInfo is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) DeleteCellInfo(ctx context.Context, req *vtctldatapb.DeleteCellInfoRequest) (resp *vtctldatapb.DeleteCellInfoResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.DeleteCellInfo")
@@ -1274,6 +1331,26 @@ func (s *VtctldServer) DeleteTablets(ctx context.Context, req *vtctldatapb.Delet
 	return &vtctldatapb.DeleteTabletsResponse{}, nil
 }
 
+// DiffConfigVersions is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) DiffConfigVersions(ctx context.Context, req *vtctldatapb.DiffConfigVersionsRequest) (resp *vtctldatapb.DiffConfigVersionsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.DiffConfigVersions")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("kind", req.Kind.String())
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("from_version", req.FromVersion)
+	span.Annotate("to_version", req.ToVersion)
+
+	diff, err := s.ts.DiffConfigVersions(ctx, req.Kind, req.Keyspace, req.FromVersion, req.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.DiffConfigVersionsResponse{Diff: diff}, nil
+}
+
 // EmergencyReparentShard is part of the vtctldservicepb.VtctldServer interface.
 func (s *VtctldServer) EmergencyReparentShard(ctx context.Context, req *vtctldatapb.EmergencyReparentShardRequest) (resp *vtctldatapb.EmergencyReparentShardResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.EmergencyReparentShard")
@@ -1470,6 +1547,25 @@ func (s *VtctldServer) ExecuteHook(ctx context.Context, req *vtctldatapb.Execute
 	}}, nil
 }
 
+// ExportCreate is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ExportCreate(ctx context.Context, req *vtctldatapb.ExportCreateRequest) (resp *vtctldatapb.ExportCreateResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ExportCreate")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("cells", req.Cells)
+	span.Annotate("tablet_types", req.TabletTypes)
+	span.Annotate("include_tables", req.IncludeTables)
+	span.Annotate("exclude_tables", req.ExcludeTables)
+	span.Annotate("sink", fmt.Sprintf("%+v", req.Sink))
+
+	resp, err = s.ws.ExportCreate(ctx, req)
+	return resp, err
+}
+
 // FindAllShardsInKeyspace is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) FindAllShardsInKeyspace(ctx context.Context, req *vtctldatapb.FindAllShardsInKeyspaceRequest) (resp *vtctldatapb.FindAllShardsInKeyspaceResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.FindAllShardsInKeyspace")
@@ -1498,6 +1594,98 @@ func (s *VtctldServer) FindAllShardsInKeyspace(ctx context.Context, req *vtctlda
 	}, nil
 }
 
+// GetBackupProgress is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetBackupProgress(ctx context.Context, req *vtctldatapb.GetBackupProgressRequest) (resp *vtctldatapb.GetBackupProgressResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetBackupProgress")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
+	ti, err := s.ts.GetTablet(ctx, req.TabletAlias)
+	if err != nil {
+		err = vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "Failed to get tablet %v: %v", req.TabletAlias, err)
+		return nil, err
+	}
+
+	progress, err := s.tmc.GetBackupProgress(ctx, ti.Tablet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetBackupProgressResponse{
+		Progress: progress,
+	}, nil
+}
+
+// GetBackupSchedules is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetBackupSchedules(ctx context.Context, req *vtctldatapb.GetBackupSchedulesRequest) (resp *vtctldatapb.GetBackupSchedulesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetBackupSchedules")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+
+	keyspaces := []string{req.Keyspace}
+	if req.Keyspace == "" {
+		keyspaces, err = s.ts.GetKeyspaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp = &vtctldatapb.GetBackupSchedulesResponse{}
+	for _, keyspace := range keyspaces {
+		shards, err := s.ts.GetShardNames(ctx, keyspace)
+		if err != nil {
+			return nil, err
+		}
+
+		keyspaceSchedule, err := s.ts.GetBackupSchedule(ctx, keyspace, "")
+		switch {
+		case err == nil:
+			resp.Schedules = append(resp.Schedules, &vtctldatapb.BackupScheduleInfo{
+				Keyspace: keyspace,
+				Schedule: keyspaceSchedule,
+			})
+		case !topo.IsErrType(err, topo.NoNode):
+			return nil, err
+		}
+
+		for _, shard := range shards {
+			shardSchedule, err := s.ts.GetBackupSchedule(ctx, keyspace, shard)
+			switch {
+			case err == nil:
+				resp.Schedules = append(resp.Schedules, &vtctldatapb.BackupScheduleInfo{
+					Keyspace: keyspace,
+					Shard:    shard,
+					Schedule: shardSchedule,
+				})
+			case !topo.IsErrType(err, topo.NoNode):
+				return nil, err
+			}
+
+			status, err := s.ts.GetBackupScheduleStatus(ctx, keyspace, shard)
+			if err != nil {
+				return nil, err
+			}
+			// Shards that were never scheduled and have no schedule are
+			// left out.
+			if keyspaceSchedule == nil && shardSchedule == nil && status.LastFullBackup == nil && status.LastIncrementalBackup == nil {
+				continue
+			}
+			resp.Statuses = append(resp.Statuses, &vtctldatapb.ShardBackupScheduleStatus{
+				Keyspace: keyspace,
+				Shard:    shard,
+				Status:   status,
+			})
+		}
+	}
+
+	return resp, nil
+}
+
 // GetBackups is part of the vtctldservicepb.VtctldServer interface.
 func (s *VtctldServer) GetBackups(ctx context.Context, req *vtctldatapb.GetBackupsRequest) (resp *vtctldatapb.GetBackupsResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetBackups")
@@ -1625,6 +1813,30 @@ func (s *VtctldServer) GetCellsAliases(ctx context.Context, req *vtctldatapb.Get
 	return &vtctldatapb.GetCellsAliasesResponse{Aliases: aliases}, nil
 }
 
+// GetConfigHistory is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetConfigHistory(ctx context.Context, req *vtctldatapb.GetConfigHistoryRequest) (resp *vtctldatapb.GetConfigHistoryResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetConfigHistory")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("kind", req.Kind.String())
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("limit", req.Limit)
+
+	if req.Limit < 0 {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "limit must be non-negative")
+		return nil, err
+	}
+
+	entries, err := s.ts.GetConfigHistory(ctx, req.Kind, req.Keyspace, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetConfigHistoryResponse{Entries: entries}, nil
+}
+
 // GetFullStatus is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetFullStatus(ctx context.Context, req *vtctldatapb.GetFullStatusRequest) (resp *vtctldatapb.GetFullStatusResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetFullStatus")
@@ -1722,6 +1934,23 @@ func (s *VtctldServer) GetPermissions(ctx context.Context, req *vtctldatapb.GetP
 	}, nil
 }
 
+// GetRecoverySilences is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetRecoverySilences(ctx context.Context, req *vtctldatapb.GetRecoverySilencesRequest) (resp *vtctldatapb.GetRecoverySilencesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetRecoverySilences")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	silences, err := s.ts.GetRecoverySilences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetRecoverySilencesResponse{
+		Silences: silences,
+	}, nil
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetRoutingRules(ctx context.Context, req *vtctldatapb.GetRoutingRulesRequest) (resp *vtctldatapb.GetRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetRoutingRules")
@@ -3605,6 +3834,25 @@ func (s *VtctldServer) RemoveKeyspaceCell(ctx context.Context, req *vtctldatapb.
 	return &vtctldatapb.RemoveKeyspaceCellResponse{}, nil
 }
 
+// RemoveRecoverySilence is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RemoveRecoverySilence(ctx context.Context, req *vtctldatapb.RemoveRecoverySilenceRequest) (resp *vtctldatapb.RemoveRecoverySilenceResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RemoveRecoverySilence")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("id", req.Id)
+
+	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer cancel()
+
+	if err = s.ts.RemoveRecoverySilence(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.RemoveRecoverySilenceResponse{}, nil
+}
+
 // RemoveShardCell is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) RemoveShardCell(ctx context.Context, req *vtctldatapb.RemoveShardCellRequest) (resp *vtctldatapb.RemoveShardCellResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.RemoveShardCell")
@@ -3793,6 +4041,55 @@ func (s *VtctldServer) RestoreFromBackup(req *vtctldatapb.RestoreFromBackupReque
 	}
 }
 
+// RestoreTableCreate is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RestoreTableCreate(ctx context.Context, req *vtctldatapb.RestoreTableCreateRequest) (resp *vtctldatapb.WorkflowStatusResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RestoreTableCreate")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("target_keyspace", req.TargetKeyspace)
+	span.Annotate("restore_keyspace", req.RestoreKeyspace)
+	span.Annotate("tables", req.Tables)
+	span.Annotate("cells", req.Cells)
+	span.Annotate("tablet_types", req.TabletTypes)
+
+	resp, err = s.ws.RestoreTableCreate(ctx, req)
+	return resp, err
+}
+
+// RestoreTopology is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RestoreTopology(ctx context.Context, req *vtctldatapb.RestoreTopologyRequest) (resp *vtctldatapb.RestoreTopologyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RestoreTopology")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("cells", strings.Join(req.Cells, ","))
+	span.Annotate("keyspaces", strings.Join(req.Keyspaces, ","))
+	span.Annotate("file_types", strings.Join(req.FileTypes, ","))
+	span.Annotate("prune", req.Prune)
+	span.Annotate("dry_run", req.DryRun)
+
+	if req.Snapshot == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "snapshot is required")
+		return nil, err
+	}
+
+	filter := &helpers.SnapshotFilter{
+		Cells:     req.Cells,
+		Keyspaces: req.Keyspaces,
+		FileTypes: req.FileTypes,
+	}
+	changes, err := helpers.RestoreTopology(ctx, s.ts, req.Snapshot, filter, req.Prune, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.RestoreTopologyResponse{Changes: changes}, nil
+}
+
 // RetrySchemaMigration is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) RetrySchemaMigration(ctx context.Context, req *vtctldatapb.RetrySchemaMigrationRequest) (resp *vtctldatapb.RetrySchemaMigrationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.RetrySchemaMigration")
@@ -3825,6 +4122,55 @@ func (s *VtctldServer) RetrySchemaMigration(ctx context.Context, req *vtctldatap
 	return resp, nil
 }
 
+// RollbackConfig is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RollbackConfig(ctx context.Context, req *vtctldatapb.RollbackConfigRequest) (resp *vtctldatapb.RollbackConfigResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RollbackConfig")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("kind", req.Kind.String())
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("version", req.Version)
+	span.Annotate("skip_rebuild", req.SkipRebuild)
+	span.Annotate("rebuild_cells", strings.Join(req.RebuildCells, ","))
+
+	switch req.Kind {
+	case topodatapb.ConfigKind_VSCHEMA:
+		if _, err = s.ts.GetKeyspace(ctx, req.Keyspace); err != nil {
+			err = vterrors.Wrapf(err, "GetKeyspace(%s)", req.Keyspace)
+			return nil, err
+		}
+	case topodatapb.ConfigKind_KEYSPACE_ROUTING_RULES:
+		// Keyspace routing rules are updated under the routing rules lock.
+		var unlock func(*error)
+		ctx, unlock, err = s.ts.LockRoutingRules(ctx, "RollbackConfig")
+		if err != nil {
+			return nil, err
+		}
+		defer unlock(&err)
+	}
+
+	entry, err := s.ts.RollbackConfig(ctx, req.Kind, req.Keyspace, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.RollbackConfigResponse{Entry: entry}
+
+	if req.SkipRebuild {
+		log.Warningf("Skipping rebuild of SrvVSchema, will need to run RebuildVSchemaGraph for changes to take effect")
+		return resp, nil
+	}
+
+	if err = s.ts.RebuildSrvVSchema(ctx, req.RebuildCells); err != nil {
+		err = vterrors.Wrapf(err, "RebuildSrvVSchema(%v) failed: %v", req.RebuildCells, err)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // RunHealthCheck is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunHealthCheckRequest) (resp *vtctldatapb.RunHealthCheckResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.RunHealthCheck")
@@ -3847,6 +4193,37 @@ func (s *VtctldServer) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunH
 	return &vtctldatapb.RunHealthCheckResponse{}, nil
 }
 
+// SetBackupSchedule is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetBackupSchedule(ctx context.Context, req *vtctldatapb.SetBackupScheduleRequest) (resp *vtctldatapb.SetBackupScheduleResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetBackupSchedule")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+
+	if err = backupscheduler.Validate(req.Schedule); err != nil {
+		return nil, err
+	}
+
+	if req.Shard == "" {
+		_, err = s.ts.GetKeyspace(ctx, req.Keyspace)
+	} else {
+		_, err = s.ts.GetShard(ctx, req.Keyspace, req.Shard)
+	}
+	if err != nil {
+		err = vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "failed to get %s: %v", backupScheduleTarget(req.Keyspace, req.Shard), err)
+		return nil, err
+	}
+
+	if err = s.ts.SaveBackupSchedule(ctx, req.Keyspace, req.Shard, req.Schedule); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.SetBackupScheduleResponse{}, nil
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceDurabilityPolicyRequest) (resp *vtctldatapb.SetKeyspaceDurabilityPolicyResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceDurabilityPolicy")
@@ -4232,6 +4609,23 @@ func (s *VtctldServer) SleepTablet(ctx context.Context, req *vtctldatapb.SleepTa
 	return &vtctldatapb.SleepTabletResponse{}, nil
 }
 
+// SnapshotTopology is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SnapshotTopology(ctx context.Context, req *vtctldatapb.SnapshotTopologyRequest) (resp *vtctldatapb.SnapshotTopologyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SnapshotTopology")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("cells", strings.Join(req.Cells, ","))
+
+	snapshot, err := helpers.SnapshotTopology(ctx, s.ts, req.Cells)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.SnapshotTopologyResponse{Snapshot: snapshot}, nil
+}
+
 // SourceShardAdd is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) SourceShardAdd(ctx context.Context, req *vtctldatapb.SourceShardAddRequest) (resp *vtctldatapb.SourceShardAddResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.SourceShardAdd")
@@ -5481,6 +5875,23 @@ func (s *VtctldServer) VDiffDelete(ctx context.Context, req *vtctldatapb.VDiffDe
 	return resp, err
 }
 
+// VDiffRepair is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) VDiffRepair(ctx context.Context, req *vtctldatapb.VDiffRepairRequest) (resp *vtctldatapb.VDiffRepairResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.VDiffRepair")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("uuid", req.Uuid)
+	span.Annotate("shards", req.TargetShards)
+	span.Annotate("apply", req.Apply)
+
+	resp, err = s.ws.VDiffRepair(ctx, req)
+	return resp, err
+}
+
 // VDiffResume is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) VDiffResume(ctx context.Context, req *vtctldatapb.VDiffResumeRequest) (resp *vtctldatapb.VDiffResumeResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.VDiffResume")
@@ -5814,3 +6225,12 @@ func (s *VtctldServer) GetKeyspaceRoutingRules(ctx context.Context, req *vtctlda
 		KeyspaceRoutingRules: rules,
 	}, nil
 }
+
+// backupScheduleTarget returns the name of the keyspace or shard a backup
+// schedule applies to.
+func backupScheduleTarget(keyspace, shard string) string {
+	if shard == "" {
+		return "keyspace " + keyspace
+	}
+	return "shard " + topoproto.KeyspaceShardString(keyspace, shard)
+}
@@ -240,6 +240,53 @@ func TestAddCellsAlias(t *testing.T) {
 	}
 }
 
+func TestAddRecoverySilence(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	_, err := vtctld.AddRecoverySilence(ctx, &vtctldatapb.AddRecoverySilenceRequest{})
+	assert.Error(t, err)
+	_, err = vtctld.AddRecoverySilence(ctx, &vtctldatapb.AddRecoverySilenceRequest{
+		Silence: &topodatapb.RecoverySilence{
+			Keyspace: "ks",
+			Reason:   "no expiry",
+		},
+	})
+	assert.Error(t, err)
+
+	resp, err := vtctld.AddRecoverySilence(ctx, &vtctldatapb.AddRecoverySilenceRequest{
+		Silence: &topodatapb.RecoverySilence{
+			Keyspace:      "ks",
+			Shard:         "0",
+			AnalysisCodes: []string{"ReplicationStopped"},
+			ExpireTime:    protoutil.TimeToProto(time.Now().Add(time.Hour)),
+			Reason:        "maintenance",
+		},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Silence.Id)
+
+	getResp, err := vtctld.GetRecoverySilences(ctx, &vtctldatapb.GetRecoverySilencesRequest{})
+	require.NoError(t, err)
+	require.Len(t, getResp.Silences, 1)
+	utils.MustMatch(t, resp.Silence, getResp.Silences[0])
+
+	_, err = vtctld.RemoveRecoverySilence(ctx, &vtctldatapb.RemoveRecoverySilenceRequest{Id: resp.Silence.Id})
+	require.NoError(t, err)
+	_, err = vtctld.RemoveRecoverySilence(ctx, &vtctldatapb.RemoveRecoverySilenceRequest{Id: resp.Silence.Id})
+	assert.Error(t, err)
+
+	getResp, err = vtctld.GetRecoverySilences(ctx, &vtctldatapb.GetRecoverySilencesRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, getResp.Silences)
+}
+
 func TestApplyRoutingRules(t *testing.T) {
 	t.Parallel()
 
@@ -5778,6 +5825,212 @@ func TestFindAllShardsInKeyspace(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestGetBackupProgress(t *testing.T) {
+	t.Parallel()
+
+	progress := &tabletmanagerdatapb.BackupProgress{
+		Operation:  "backup",
+		Phase:      "copying files",
+		Running:    true,
+		BytesTotal: 100,
+		BytesDone:  10,
+	}
+	tests := []struct {
+		name      string
+		tmc       testutil.TabletManagerClient
+		req       *vtctldatapb.GetBackupProgressRequest
+		expected  *vtctldatapb.GetBackupProgressResponse
+		shouldErr bool
+	}{
+		{
+			name: "ok",
+			tmc: testutil.TabletManagerClient{
+				GetBackupProgressResults: map[string]struct {
+					Progress *tabletmanagerdatapb.BackupProgress
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Progress: progress,
+					},
+				},
+			},
+			req: &vtctldatapb.GetBackupProgressRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			expected: &vtctldatapb.GetBackupProgressResponse{
+				Progress: progress,
+			},
+		},
+		{
+			name: "no backup run yet",
+			tmc: testutil.TabletManagerClient{
+				GetBackupProgressResults: map[string]struct {
+					Progress *tabletmanagerdatapb.BackupProgress
+					Error    error
+				}{
+					"zone1-0000000100": {},
+				},
+			},
+			req: &vtctldatapb.GetBackupProgressRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			expected: &vtctldatapb.GetBackupProgressResponse{},
+		},
+		{
+			name: "no tablet",
+			req: &vtctldatapb.GetBackupProgressRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  404,
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "tmc call failed",
+			tmc: testutil.TabletManagerClient{
+				GetBackupProgressResults: map[string]struct {
+					Progress *tabletmanagerdatapb.BackupProgress
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Error: assert.AnError,
+					},
+				},
+			},
+			req: &vtctldatapb.GetBackupProgressRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, nil, &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			})
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.GetBackupProgress(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
+func TestBackupSchedules(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddShards(ctx, t, ts,
+		&vtctldatapb.Shard{Keyspace: "ks1", Name: "-80"},
+		&vtctldatapb.Shard{Keyspace: "ks1", Name: "80-"},
+		&vtctldatapb.Shard{Keyspace: "ks2", Name: "0"},
+	)
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	keyspaceSchedule := &topodatapb.BackupSchedule{
+		FullBackupInterval:          protoutil.DurationToProto(24 * time.Hour),
+		IncrementalBackupInterval:   protoutil.DurationToProto(time.Hour),
+		TabletType:                  topodatapb.TabletType_RDONLY,
+		MaxConcurrentBackupsPerCell: 1,
+	}
+	shardSchedule := &topodatapb.BackupSchedule{
+		FullBackupInterval: protoutil.DurationToProto(12 * time.Hour),
+		Cell:               "zone1",
+	}
+
+	_, err := vtctld.SetBackupSchedule(ctx, &vtctldatapb.SetBackupScheduleRequest{Keyspace: "ks1", Schedule: keyspaceSchedule})
+	require.NoError(t, err)
+	_, err = vtctld.SetBackupSchedule(ctx, &vtctldatapb.SetBackupScheduleRequest{Keyspace: "ks1", Shard: "80-", Schedule: shardSchedule})
+	require.NoError(t, err)
+
+	// Invalid schedules, and schedules of keyspaces or shards that do not
+	// exist, are rejected.
+	_, err = vtctld.SetBackupSchedule(ctx, &vtctldatapb.SetBackupScheduleRequest{Keyspace: "ks1", Schedule: &topodatapb.BackupSchedule{}})
+	assert.ErrorContains(t, err, "at least one of the full and incremental backup intervals must be set")
+	_, err = vtctld.SetBackupSchedule(ctx, &vtctldatapb.SetBackupScheduleRequest{Keyspace: "ks1", Shard: "-40", Schedule: shardSchedule})
+	assert.ErrorContains(t, err, "failed to get shard ks1/-40")
+	_, err = vtctld.SetBackupSchedule(ctx, &vtctldatapb.SetBackupScheduleRequest{Keyspace: "ks3", Schedule: shardSchedule})
+	assert.ErrorContains(t, err, "failed to get keyspace ks3")
+
+	_, err = ts.UpdateBackupScheduleStatus(ctx, "ks1", "-80", func(status *topodatapb.BackupScheduleStatus) error {
+		status.LastFullBackup = &topodatapb.BackupScheduleRun{
+			TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			StartedAt:   &vttime.Time{Seconds: 1000},
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	resp, err := vtctld.GetBackupSchedules(ctx, &vtctldatapb.GetBackupSchedulesRequest{})
+	require.NoError(t, err)
+	utils.MustMatch(t, &vtctldatapb.GetBackupSchedulesResponse{
+		Schedules: []*vtctldatapb.BackupScheduleInfo{
+			{Keyspace: "ks1", Schedule: keyspaceSchedule},
+			{Keyspace: "ks1", Shard: "80-", Schedule: shardSchedule},
+		},
+		Statuses: []*vtctldatapb.ShardBackupScheduleStatus{
+			{
+				Keyspace: "ks1",
+				Shard:    "-80",
+				Status: &topodatapb.BackupScheduleStatus{
+					LastFullBackup: &topodatapb.BackupScheduleRun{
+						TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+						StartedAt:   &vttime.Time{Seconds: 1000},
+					},
+				},
+			},
+			{Keyspace: "ks1", Shard: "80-", Status: &topodatapb.BackupScheduleStatus{}},
+		},
+	}, resp)
+
+	resp, err = vtctld.GetBackupSchedules(ctx, &vtctldatapb.GetBackupSchedulesRequest{Keyspace: "ks2"})
+	require.NoError(t, err)
+	utils.MustMatch(t, &vtctldatapb.GetBackupSchedulesResponse{}, resp)
+
+	_, err = vtctld.DeleteBackupSchedule(ctx, &vtctldatapb.DeleteBackupScheduleRequest{Keyspace: "ks1", Shard: "80-"})
+	require.NoError(t, err)
+	_, err = vtctld.DeleteBackupSchedule(ctx, &vtctldatapb.DeleteBackupScheduleRequest{Keyspace: "ks1", Shard: "80-"})
+	assert.ErrorContains(t, err, "no backup schedule for shard ks1/80-")
+
+	resp, err = vtctld.GetBackupSchedules(ctx, &vtctldatapb.GetBackupSchedulesRequest{Keyspace: "ks1"})
+	require.NoError(t, err)
+	require.Len(t, resp.Schedules, 1)
+	assert.Empty(t, resp.Schedules[0].Shard)
+}
+
 func TestGetBackups(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -6013,6 +6266,52 @@ func TestGetCellsAliases(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestGetConfigHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	for _, tables := range []string{"t1", "t2", "t3"} {
+		err := ts.SaveRoutingRules(ctx, &vschemapb.RoutingRules{
+			Rules: []*vschemapb.RoutingRule{{FromTable: tables, ToTables: []string{"ks." + tables}}},
+		})
+		require.NoError(t, err)
+	}
+
+	resp, err := vtctld.GetConfigHistory(ctx, &vtctldatapb.GetConfigHistoryRequest{
+		Kind:  topodatapb.ConfigKind_ROUTING_RULES,
+		Limit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Entries, 2)
+	assert.EqualValues(t, 2, resp.Entries[0].Version)
+	assert.EqualValues(t, 3, resp.Entries[1].Version)
+
+	diffResp, err := vtctld.DiffConfigVersions(ctx, &vtctldatapb.DiffConfigVersionsRequest{
+		Kind:        topodatapb.ConfigKind_ROUTING_RULES,
+		FromVersion: 1,
+		ToVersion:   2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, resp.Entries[0].Diff, diffResp.Diff)
+
+	_, err = vtctld.GetConfigHistory(ctx, &vtctldatapb.GetConfigHistoryRequest{
+		Kind:  topodatapb.ConfigKind_ROUTING_RULES,
+		Limit: -1,
+	})
+	assert.Error(t, err)
+
+	_, err = vtctld.GetConfigHistory(ctx, &vtctldatapb.GetConfigHistoryRequest{
+		Kind: topodatapb.ConfigKind_VSCHEMA,
+	})
+	assert.Error(t, err, "the vschema history requires a keyspace")
+}
+
 func TestGetFullStatus(t *testing.T) {
 	t.Parallel()
 
@@ -11099,6 +11398,46 @@ func TestRestoreFromBackup(t *testing.T) {
 	}
 }
 
+func TestRestoreTopology(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name:     "ks",
+		Keyspace: &topodatapb.Keyspace{},
+	})
+	srvKeyspace := &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{{ServedType: topodatapb.TabletType_PRIMARY}},
+	}
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone1", "ks", srvKeyspace))
+	snapshot, err := vtctld.SnapshotTopology(ctx, &vtctldatapb.SnapshotTopologyRequest{Cells: []string{"zone1"}})
+	require.NoError(t, err)
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone1", "ks", &topodatapb.SrvKeyspace{}))
+
+	_, err = vtctld.RestoreTopology(ctx, &vtctldatapb.RestoreTopologyRequest{})
+	assert.Error(t, err)
+
+	resp, err := vtctld.RestoreTopology(ctx, &vtctldatapb.RestoreTopologyRequest{
+		Snapshot:  snapshot.Snapshot,
+		Keyspaces: []string{"ks"},
+		FileTypes: []string{topo.SrvKeyspaceFile},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Changes, 1)
+	assert.Equal(t, topodatapb.TopoSnapshotChange_UPDATED, resp.Changes[0].Type)
+	assert.Equal(t, "keyspaces/ks/SrvKeyspace", resp.Changes[0].Path)
+
+	got, err := ts.GetSrvKeyspace(ctx, "zone1", "ks")
+	require.NoError(t, err)
+	utils.MustMatch(t, srvKeyspace, got)
+}
+
 func TestRetrySchemaMigration(t *testing.T) {
 	t.Parallel()
 
@@ -11301,6 +11640,86 @@ func TestRetrySchemaMigration(t *testing.T) {
 	}
 }
 
+func TestRollbackConfig(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name:     "ks",
+		Keyspace: &topodatapb.Keyspace{},
+	})
+	v1 := &vschemapb.Keyspace{Tables: map[string]*vschemapb.Table{"t1": {}}}
+	v2 := &vschemapb.Keyspace{Tables: map[string]*vschemapb.Table{"t1": {}, "t2": {}}}
+	require.NoError(t, ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{Name: "ks", Keyspace: v1}))
+	require.NoError(t, ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{Name: "ks", Keyspace: v2}))
+	history, err := ts.GetConfigHistory(ctx, topodatapb.ConfigKind_VSCHEMA, "ks", 0)
+	require.NoError(t, err)
+	v1Version := history[len(history)-2].Version
+
+	t.Run("unknown keyspace", func(t *testing.T) {
+		_, err := vtctld.RollbackConfig(ctx, &vtctldatapb.RollbackConfigRequest{
+			Kind:     topodatapb.ConfigKind_VSCHEMA,
+			Keyspace: "unknown",
+			Version:  1,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		_, err := vtctld.RollbackConfig(ctx, &vtctldatapb.RollbackConfigRequest{
+			Kind:     topodatapb.ConfigKind_VSCHEMA,
+			Keyspace: "ks",
+			Version:  100,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("rollback and rebuild", func(t *testing.T) {
+		resp, err := vtctld.RollbackConfig(ctx, &vtctldatapb.RollbackConfigRequest{
+			Kind:         topodatapb.ConfigKind_VSCHEMA,
+			Keyspace:     "ks",
+			Version:      v1Version,
+			RebuildCells: []string{"zone1"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("rollback to version %d", v1Version), resp.Entry.Comment)
+
+		ksvs, err := ts.GetVSchema(ctx, "ks")
+		require.NoError(t, err)
+		utils.MustMatch(t, v1, ksvs.Keyspace)
+
+		srvVSchema, err := ts.GetSrvVSchema(ctx, "zone1")
+		require.NoError(t, err)
+		utils.MustMatch(t, v1, srvVSchema.Keyspaces["ks"])
+		_, err = ts.GetSrvVSchema(ctx, "zone2")
+		assert.True(t, topo.IsErrType(err, topo.NoNode), "zone2 should not have been rebuilt: %v", err)
+	})
+
+	t.Run("keyspace routing rules", func(t *testing.T) {
+		rules := &vschemapb.KeyspaceRoutingRules{
+			Rules: []*vschemapb.KeyspaceRoutingRule{{FromKeyspace: "ks1", ToKeyspace: "ks2"}},
+		}
+		require.NoError(t, ts.CreateKeyspaceRoutingRules(ctx, rules))
+		require.NoError(t, ts.SaveKeyspaceRoutingRules(ctx, &vschemapb.KeyspaceRoutingRules{}))
+
+		_, err := vtctld.RollbackConfig(ctx, &vtctldatapb.RollbackConfigRequest{
+			Kind:        topodatapb.ConfigKind_KEYSPACE_ROUTING_RULES,
+			Version:     1,
+			SkipRebuild: true,
+		})
+		require.NoError(t, err)
+		got, err := ts.GetKeyspaceRoutingRules(ctx)
+		require.NoError(t, err)
+		utils.MustMatch(t, rules, got)
+	})
+}
+
 func TestRunHealthCheck(t *testing.T) {
 	t.Parallel()
 
@@ -12412,6 +12831,37 @@ func TestShardReplicationRemove(t *testing.T) {
 	}})
 }
 
+func TestSnapshotTopology(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name:     "ks",
+		Keyspace: &topodatapb.Keyspace{},
+	})
+	require.NoError(t, ts.UpdateSrvKeyspace(ctx, "zone2", "ks", &topodatapb.SrvKeyspace{}))
+
+	resp, err := vtctld.SnapshotTopology(ctx, &vtctldatapb.SnapshotTopologyRequest{})
+	require.NoError(t, err)
+	var cells []string
+	for _, cell := range resp.Snapshot.Cells {
+		cells = append(cells, cell.Name)
+	}
+	assert.Equal(t, []string{topo.GlobalCell, "zone1", "zone2"}, cells)
+	assert.Empty(t, resp.Snapshot.Cells[1].Files)
+	require.Len(t, resp.Snapshot.Cells[2].Files, 1)
+	assert.Equal(t, "keyspaces/ks/SrvKeyspace", resp.Snapshot.Cells[2].Files[0].Path)
+
+	_, err = vtctld.SnapshotTopology(ctx, &vtctldatapb.SnapshotTopologyRequest{Cells: []string{"unknown"}})
+	assert.Error(t, err)
+}
+
 func TestSourceShardAdd(t *testing.T) {
 	t.Parallel()
 
@@ -243,6 +243,11 @@ type TabletManagerClient struct {
 	// FullStatus result
 	FullStatusResult *replicationdatapb.FullStatus
 	// keyed by tablet alias.
+	GetBackupProgressResults map[string]struct {
+		Progress *tabletmanagerdatapb.BackupProgress
+		Error    error
+	}
+	// keyed by tablet alias.
 	GetPermissionsDelays map[string]time.Duration
 	// keyed by tablet alias.
 	GetPermissionsResults map[string]struct {
@@ -752,6 +757,24 @@ func (fake *TabletManagerClient) FullStatus(ctx context.Context, tablet *topodat
 	return nil, fmt.Errorf("no output set for FullStatus")
 }
 
+// GetBackupProgress is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) GetBackupProgress(ctx context.Context, tablet *topodatapb.Tablet) (*tabletmanagerdatapb.BackupProgress, error) {
+	if fake.GetBackupProgressResults == nil {
+		return nil, assert.AnError
+	}
+
+	if tablet.Alias == nil {
+		return nil, assert.AnError
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.GetBackupProgressResults[key]; ok {
+		return result.Progress, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no backup progress for %s", assert.AnError, key)
+}
+
 // GetPermissions is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) GetPermissions(ctx context.Context, tablet *topodatapb.Tablet) (*tabletmanagerdatapb.Permissions, error) {
 	if fake.GetPermissionsResults == nil {
@@ -39,6 +39,11 @@ func (client *localVtctldClient) AddCellsAlias(ctx context.Context, in *vtctldat
 	return client.s.AddCellsAlias(ctx, in)
 }
 
+// AddRecoverySilence is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) AddRecoverySilence(ctx context.Context, in *vtctldatapb.AddRecoverySilenceRequest, opts ...grpc.CallOption) (*vtctldatapb.AddRecoverySilenceResponse, error) {
+	return client.s.AddRecoverySilence(ctx, in)
+}
+
 // ApplyKeyspaceRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ApplyKeyspaceRoutingRules(ctx context.Context, in *vtctldatapb.ApplyKeyspaceRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyKeyspaceRoutingRulesResponse, error) {
 	return client.s.ApplyKeyspaceRoutingRules(ctx, in)
@@ -216,6 +221,11 @@ func (client *localVtctldClient) CreateShard(ctx context.Context, in *vtctldatap
 	return client.s.CreateShard(ctx, in)
 }
 
+// DeleteBackupSchedule is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) DeleteBackupSchedule(ctx context.Context, in *vtctldatapb.DeleteBackupScheduleRequest, opts ...grpc.CallOption) (*vtctldatapb.DeleteBackupScheduleResponse, error) {
+	return client.s.DeleteBackupSchedule(ctx, in)
+}
+
 // DeleteCellInfo is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) DeleteCellInfo(ctx context.Context, in *vtctldatapb.DeleteCellInfoRequest, opts ...grpc.CallOption) (*vtctldatapb.DeleteCellInfoResponse, error) {
 	return client.s.DeleteCellInfo(ctx, in)
@@ -246,6 +256,11 @@ func (client *localVtctldClient) DeleteTablets(ctx context.Context, in *vtctldat
 	return client.s.DeleteTablets(ctx, in)
 }
 
+// DiffConfigVersions is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) DiffConfigVersions(ctx context.Context, in *vtctldatapb.DiffConfigVersionsRequest, opts ...grpc.CallOption) (*vtctldatapb.DiffConfigVersionsResponse, error) {
+	return client.s.DiffConfigVersions(ctx, in)
+}
+
 // EmergencyReparentShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) EmergencyReparentShard(ctx context.Context, in *vtctldatapb.EmergencyReparentShardRequest, opts ...grpc.CallOption) (*vtctldatapb.EmergencyReparentShardResponse, error) {
 	return client.s.EmergencyReparentShard(ctx, in)
@@ -271,6 +286,11 @@ func (client *localVtctldClient) ExecuteMultiFetchAsDBA(ctx context.Context, in
 	return client.s.ExecuteMultiFetchAsDBA(ctx, in)
 }
 
+// ExportCreate is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ExportCreate(ctx context.Context, in *vtctldatapb.ExportCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.ExportCreateResponse, error) {
+	return client.s.ExportCreate(ctx, in)
+}
+
 // FindAllShardsInKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) FindAllShardsInKeyspace(ctx context.Context, in *vtctldatapb.FindAllShardsInKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.FindAllShardsInKeyspaceResponse, error) {
 	return client.s.FindAllShardsInKeyspace(ctx, in)
@@ -281,6 +301,16 @@ func (client *localVtctldClient) ForceCutOverSchemaMigration(ctx context.Context
 	return client.s.ForceCutOverSchemaMigration(ctx, in)
 }
 
+// GetBackupProgress is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetBackupProgress(ctx context.Context, in *vtctldatapb.GetBackupProgressRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupProgressResponse, error) {
+	return client.s.GetBackupProgress(ctx, in)
+}
+
+// GetBackupSchedules is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetBackupSchedules(ctx context.Context, in *vtctldatapb.GetBackupSchedulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupSchedulesResponse, error) {
+	return client.s.GetBackupSchedules(ctx, in)
+}
+
 // GetBackups is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetBackups(ctx context.Context, in *vtctldatapb.GetBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupsResponse, error) {
 	return client.s.GetBackups(ctx, in)
@@ -301,6 +331,11 @@ func (client *localVtctldClient) GetCellsAliases(ctx context.Context, in *vtctld
 	return client.s.GetCellsAliases(ctx, in)
 }
 
+// GetConfigHistory is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetConfigHistory(ctx context.Context, in *vtctldatapb.GetConfigHistoryRequest, opts ...grpc.CallOption) (*vtctldatapb.GetConfigHistoryResponse, error) {
+	return client.s.GetConfigHistory(ctx, in)
+}
+
 // GetFullStatus is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetFullStatus(ctx context.Context, in *vtctldatapb.GetFullStatusRequest, opts ...grpc.CallOption) (*vtctldatapb.GetFullStatusResponse, error) {
 	return client.s.GetFullStatus(ctx, in)
@@ -331,6 +366,11 @@ func (client *localVtctldClient) GetPermissions(ctx context.Context, in *vtctlda
 	return client.s.GetPermissions(ctx, in)
 }
 
+// GetRecoverySilences is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetRecoverySilences(ctx context.Context, in *vtctldatapb.GetRecoverySilencesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRecoverySilencesResponse, error) {
+	return client.s.GetRecoverySilences(ctx, in)
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetRoutingRules(ctx context.Context, in *vtctldatapb.GetRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRoutingRulesResponse, error) {
 	return client.s.GetRoutingRules(ctx, in)
@@ -551,6 +591,11 @@ func (client *localVtctldClient) RemoveKeyspaceCell(ctx context.Context, in *vtc
 	return client.s.RemoveKeyspaceCell(ctx, in)
 }
 
+// RemoveRecoverySilence is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RemoveRecoverySilence(ctx context.Context, in *vtctldatapb.RemoveRecoverySilenceRequest, opts ...grpc.CallOption) (*vtctldatapb.RemoveRecoverySilenceResponse, error) {
+	return client.s.RemoveRecoverySilence(ctx, in)
+}
+
 // RemoveShardCell is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) RemoveShardCell(ctx context.Context, in *vtctldatapb.RemoveShardCellRequest, opts ...grpc.CallOption) (*vtctldatapb.RemoveShardCellResponse, error) {
 	return client.s.RemoveShardCell(ctx, in)
@@ -617,16 +662,36 @@ func (client *localVtctldClient) RestoreFromBackup(ctx context.Context, in *vtct
 	return stream, nil
 }
 
+// RestoreTableCreate is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RestoreTableCreate(ctx context.Context, in *vtctldatapb.RestoreTableCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowStatusResponse, error) {
+	return client.s.RestoreTableCreate(ctx, in)
+}
+
+// RestoreTopology is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RestoreTopology(ctx context.Context, in *vtctldatapb.RestoreTopologyRequest, opts ...grpc.CallOption) (*vtctldatapb.RestoreTopologyResponse, error) {
+	return client.s.RestoreTopology(ctx, in)
+}
+
 // RetrySchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) RetrySchemaMigration(ctx context.Context, in *vtctldatapb.RetrySchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.RetrySchemaMigrationResponse, error) {
 	return client.s.RetrySchemaMigration(ctx, in)
 }
 
+// RollbackConfig is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RollbackConfig(ctx context.Context, in *vtctldatapb.RollbackConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.RollbackConfigResponse, error) {
+	return client.s.RollbackConfig(ctx, in)
+}
+
 // RunHealthCheck is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) RunHealthCheck(ctx context.Context, in *vtctldatapb.RunHealthCheckRequest, opts ...grpc.CallOption) (*vtctldatapb.RunHealthCheckResponse, error) {
 	return client.s.RunHealthCheck(ctx, in)
 }
 
+// SetBackupSchedule is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SetBackupSchedule(ctx context.Context, in *vtctldatapb.SetBackupScheduleRequest, opts ...grpc.CallOption) (*vtctldatapb.SetBackupScheduleResponse, error) {
+	return client.s.SetBackupSchedule(ctx, in)
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceDurabilityPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceDurabilityPolicyResponse, error) {
 	return client.s.SetKeyspaceDurabilityPolicy(ctx, in)
@@ -672,6 +737,11 @@ func (client *localVtctldClient) SleepTablet(ctx context.Context, in *vtctldatap
 	return client.s.SleepTablet(ctx, in)
 }
 
+// SnapshotTopology is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SnapshotTopology(ctx context.Context, in *vtctldatapb.SnapshotTopologyRequest, opts ...grpc.CallOption) (*vtctldatapb.SnapshotTopologyResponse, error) {
+	return client.s.SnapshotTopology(ctx, in)
+}
+
 // SourceShardAdd is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) SourceShardAdd(ctx context.Context, in *vtctldatapb.SourceShardAddRequest, opts ...grpc.CallOption) (*vtctldatapb.SourceShardAddResponse, error) {
 	return client.s.SourceShardAdd(ctx, in)
@@ -722,6 +792,11 @@ func (client *localVtctldClient) VDiffDelete(ctx context.Context, in *vtctldatap
 	return client.s.VDiffDelete(ctx, in)
 }
 
+// VDiffRepair is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) VDiffRepair(ctx context.Context, in *vtctldatapb.VDiffRepairRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffRepairResponse, error) {
+	return client.s.VDiffRepair(ctx, in)
+}
+
 // VDiffResume is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) VDiffResume(ctx context.Context, in *vtctldatapb.VDiffResumeRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffResumeResponse, error) {
 	return client.s.VDiffResume(ctx, in)
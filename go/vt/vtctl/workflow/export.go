@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/ptr"
+	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication/sink"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// ExportCreate is part of the vtctlservicepb.VtctldServer interface.
+// It creates an Export workflow, whose streams run on the primary tablets
+// of the keyspace and write the rows of the tables of their own shard to
+// files rather than to the tables of a target keyspace.
+func (s *Server) ExportCreate(ctx context.Context, req *vtctldatapb.ExportCreateRequest) (*vtctldatapb.ExportCreateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ExportCreate")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("cells", req.Cells)
+	span.Annotate("tablet_types", req.TabletTypes)
+
+	settings, err := validateSinkSettings(req.Sink)
+	if err != nil {
+		return nil, err
+	}
+
+	ksTables, err := getTablesInKeyspace(ctx, s.ts, s.tmc, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	tables := req.IncludeTables
+	if len(tables) > 0 {
+		if err := validateSourceTablesExist(req.Keyspace, ksTables, tables); err != nil {
+			return nil, err
+		}
+	} else if req.AllTables {
+		tables = ksTables
+	}
+	if len(req.ExcludeTables) > 0 {
+		if err := validateSourceTablesExist(req.Keyspace, ksTables, req.ExcludeTables); err != nil {
+			return nil, err
+		}
+	}
+	var included []string
+	for _, table := range tables {
+		if shouldInclude(table, req.ExcludeTables) {
+			included = append(included, table)
+		}
+	}
+	if len(included) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no tables to export")
+	}
+	slices.Sort(included)
+	filter := &binlogdatapb.Filter{}
+	for _, table := range included {
+		buf := sqlparser.NewTrackedBuffer(nil)
+		buf.Myprintf("select * from %v", sqlparser.NewIdentifierCS(table))
+		filter.Rules = append(filter.Rules, &binlogdatapb.Rule{
+			Match:  table,
+			Filter: buf.String(),
+		})
+	}
+
+	if err := validateNewWorkflow(ctx, s.ts, s.tmc, req.Keyspace, req.Workflow); err != nil {
+		return nil, err
+	}
+	optionsJSON, err := getOptionsJSON(req.WorkflowOptions)
+	if err != nil {
+		return nil, err
+	}
+	shards, err := s.ts.GetServingShards(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := &tabletmanagerdatapb.CreateVReplicationWorkflowRequest{
+		Workflow:                  req.Workflow,
+		Cells:                     req.Cells,
+		TabletTypes:               req.TabletTypes,
+		TabletSelectionPreference: req.TabletSelectionPreference,
+		WorkflowType:              binlogdatapb.VReplicationWorkflowType_Export,
+		AutoStart:                 req.AutoStart,
+		StopAfterCopy:             req.StopAfterCopy,
+		Options:                   optionsJSON,
+	}
+	err = forAllShards(shards, func(shard *topo.ShardInfo) error {
+		primary, err := s.ts.GetTablet(ctx, shard.PrimaryAlias)
+		if err != nil {
+			return vterrors.Wrapf(err, "GetTablet(%v) failed", shard.PrimaryAlias)
+		}
+		tabletReq := createReq.CloneVT()
+		tabletReq.BinlogSource = []*binlogdatapb.BinlogSource{{
+			Keyspace:      req.Keyspace,
+			Shard:         shard.ShardName(),
+			Filter:        filter.CloneVT(),
+			StopAfterCopy: req.StopAfterCopy,
+			Sink:          settings,
+		}}
+		if _, err := s.tmc.CreateVReplicationWorkflow(ctx, primary.Tablet, tabletReq); err != nil {
+			return err
+		}
+		if !req.AutoStart {
+			return nil
+		}
+		if _, err := s.tmc.UpdateVReplicationWorkflow(ctx, primary.Tablet, &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+			Workflow: req.Workflow,
+			State:    ptr.Of(binlogdatapb.VReplicationWorkflowState_Running),
+			// Don't change anything else, so pass simulated NULLs.
+			Cells:       textutil.SimulatedNullStringSlice,
+			TabletTypes: textutil.SimulatedNullTabletTypeSlice,
+		}); err != nil {
+			return vterrors.Wrap(err, "failed to update workflow")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &vtctldatapb.ExportCreateResponse{
+		Summary: fmt.Sprintf("Successfully created the %s workflow exporting %s from the %s keyspace to %s",
+			req.Workflow, strings.Join(included, ","), req.Keyspace, settings.Directory),
+	}, nil
+}
+
+// validateSinkSettings validates the sink of an Export workflow and returns
+// it with the defaults filled in.
+func validateSinkSettings(settings *binlogdatapb.SinkSettings) (*binlogdatapb.SinkSettings, error) {
+	if settings == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no sink specified")
+	}
+	settings = settings.CloneVT()
+	if settings.Storage == "" {
+		settings.Storage = sink.StorageLocal
+	}
+	if settings.Format == "" {
+		settings.Format = sink.FormatNDJSON
+	}
+	switch {
+	case settings.Storage != sink.StorageLocal && settings.Storage != sink.StorageBackup:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unsupported storage %q, supported storages are %q and %q",
+			settings.Storage, sink.StorageLocal, sink.StorageBackup)
+	case settings.Format != sink.FormatNDJSON:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unsupported format %q, the only supported format is %q",
+			settings.Format, sink.FormatNDJSON)
+	case settings.Directory == "":
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no directory specified for the %s storage", settings.Storage)
+	case settings.MaxBatchRows < 0 || settings.MaxBatchSeconds < 0:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the maximum rows and seconds of a batch cannot be negative")
+	}
+	return settings, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestExportCreate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "export",
+		SourceKeyspace: "commerce",
+		TargetKeyspace: "commerce",
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      "t1",
+			SourceExpression: "select * from t1",
+			CreateDdl:        "create table t1 (id int primary key, val varchar(16))",
+		}, {
+			TargetTable:      "t2",
+			SourceExpression: "select * from t2",
+			CreateDdl:        "create table t2 (id int primary key)",
+		}, {
+			TargetTable:      "t3",
+			SourceExpression: "select * from t3",
+			CreateDdl:        "create table t3 (id int primary key)",
+		}},
+	}
+	shards := []string{"-80", "80-"}
+	env := newTestMaterializerEnv(t, ctx, ms, shards, shards)
+	defer env.close()
+
+	req := &vtctldatapb.ExportCreateRequest{
+		Workflow:      ms.Workflow,
+		Keyspace:      ms.SourceKeyspace,
+		TabletTypes:   []topodatapb.TabletType{topodatapb.TabletType_REPLICA},
+		AllTables:     true,
+		ExcludeTables: []string{"t2"},
+		Sink: &binlogdatapb.SinkSettings{
+			Directory:    "/tmp/export",
+			MaxBatchRows: 100,
+		},
+		AutoStart: true,
+	}
+	for i, shard := range shards {
+		env.tmc.expectCreateVReplicationWorkflowRequest(uint32(startingSourceTabletUID+i*tabletUIDStep), &createVReplicationWorkflowRequestResponse{
+			req: &tabletmanagerdatapb.CreateVReplicationWorkflowRequest{
+				Workflow: ms.Workflow,
+				BinlogSource: []*binlogdatapb.BinlogSource{{
+					Keyspace: ms.SourceKeyspace,
+					Shard:    shard,
+					Filter: &binlogdatapb.Filter{
+						Rules: []*binlogdatapb.Rule{
+							{Match: "t1", Filter: "select * from t1"},
+							{Match: "t3", Filter: "select * from t3"},
+						},
+					},
+					Sink: &binlogdatapb.SinkSettings{
+						Storage:      "local",
+						Directory:    "/tmp/export",
+						Format:       "ndjson",
+						MaxBatchRows: 100,
+					},
+				}},
+				TabletTypes:  []topodatapb.TabletType{topodatapb.TabletType_REPLICA},
+				WorkflowType: binlogdatapb.VReplicationWorkflowType_Export,
+				AutoStart:    true,
+				Options:      "{}",
+			},
+		})
+	}
+	resp, err := env.ws.ExportCreate(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "Successfully created the export workflow exporting t1,t3 from the commerce keyspace to /tmp/export", resp.Summary)
+
+	testcases := []struct {
+		name   string
+		modify func(req *vtctldatapb.ExportCreateRequest)
+		err    string
+	}{{
+		name:   "no sink",
+		modify: func(req *vtctldatapb.ExportCreateRequest) { req.Sink = nil },
+		err:    "no sink specified",
+	}, {
+		name:   "unsupported format",
+		modify: func(req *vtctldatapb.ExportCreateRequest) { req.Sink.Format = "parquet" },
+		err:    `unsupported format "parquet", the only supported format is "ndjson"`,
+	}, {
+		name:   "unsupported storage",
+		modify: func(req *vtctldatapb.ExportCreateRequest) { req.Sink.Storage = "s3" },
+		err:    `unsupported storage "s3"`,
+	}, {
+		name:   "no directory",
+		modify: func(req *vtctldatapb.ExportCreateRequest) { req.Sink.Directory = "" },
+		err:    "no directory specified for the local storage",
+	}, {
+		name: "no tables",
+		modify: func(req *vtctldatapb.ExportCreateRequest) {
+			req.AllTables = false
+			req.ExcludeTables = nil
+		},
+		err: "no tables to export",
+	}, {
+		name:   "unknown table",
+		modify: func(req *vtctldatapb.ExportCreateRequest) { req.IncludeTables = []string{"t4"} },
+		err:    "table(s) not found in source keyspace commerce: t4",
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			req := req.CloneVT()
+			tcase.modify(req)
+			_, err := env.ws.ExportCreate(ctx, req)
+			require.ErrorContains(t, err, tcase.err)
+		})
+	}
+}
@@ -74,6 +74,9 @@ type materializer struct {
 	isPartial             bool
 	primaryVindexesDiffer bool
 	workflowType          binlogdatapb.VReplicationWorkflowType
+	// lazySourceDDLs defers getting the DDLs of the source tables until a
+	// create DDL needs them, for source keyspaces without primaries.
+	lazySourceDDLs bool
 
 	env *vtenv.Environment
 }
@@ -187,6 +190,7 @@ func (mz *materializer) generateBinlogSources(ctx context.Context, targetShard *
 			SourceTimeZone:  mz.ms.SourceTimeZone,
 			TargetTimeZone:  mz.ms.TargetTimeZone,
 			OnDdl:           binlogdatapb.OnDDLAction(binlogdatapb.OnDDLAction_value[mz.ms.OnDdl]),
+			Bidirectional:   mz.ms.Bidirectional.CloneVT(),
 		}
 
 		var tenantClause *sqlparser.Expr
@@ -327,28 +331,41 @@ func (mz *materializer) deploySchema() error {
 				return fmt.Errorf("target table %s does not exist and there is no create ddl defined", ts.TargetTable)
 			}
 
-			var err error
-			mu.Lock()
-			if len(sourceDDLs) == 0 {
-				// Only get DDLs for tables once and lazily: if we need to copy the schema from source
-				// to target then we copy schemas from primaries on the source keyspace; we have found
-				// use cases where the user just has a replica (no primary) in the source keyspace.
-				sourceDDLs, err = getSourceTableDDLs(mz.ctx, mz.sourceTs, mz.tmc, mz.sourceShards)
-			}
-			mu.Unlock()
-			if err != nil {
-				log.Errorf("Error getting DDLs of source tables: %s", err.Error())
+			getSourceDDLs := func() error {
+				var err error
+				mu.Lock()
+				if len(sourceDDLs) == 0 {
+					// Only get DDLs for tables once and lazily: if we need to copy the schema from source
+					// to target then we copy schemas from primaries on the source keyspace; we have found
+					// use cases where the user just has a replica (no primary) in the source keyspace.
+					sourceDDLs, err = getSourceTableDDLs(mz.ctx, mz.sourceTs, mz.tmc, mz.sourceShards)
+				}
+				mu.Unlock()
+				if err != nil {
+					log.Errorf("Error getting DDLs of source tables: %s", err.Error())
+				}
 				return err
 			}
+			if !mz.lazySourceDDLs {
+				if err := getSourceDDLs(); err != nil {
+					return err
+				}
+			}
 
 			createDDL := ts.CreateDdl
 			// Make any necessary adjustments to the create DDL.
 			if removeAutoInc || createDDL == createDDLAsCopy || createDDL == createDDLAsCopyDropConstraint || createDDL == createDDLAsCopyDropForeignKeys {
+				if err := getSourceDDLs(); err != nil {
+					return err
+				}
+
 				if ts.SourceExpression != "" {
 					// Check for table if non-empty SourceExpression.
 					sourceTableName, err := mz.env.Parser().TableFromStatement(ts.SourceExpression)
 					if err != nil {
-						return err
+						// This is the case for joins, whose target table definition
+						// must be provided.
+						return vterrors.Wrapf(err, "cannot copy the schema of table %s from its source expression, provide its create_ddl instead", ts.TargetTable)
 					}
 					if sourceTableName.Name.String() != ts.TargetTable {
 						return fmt.Errorf("source and target table names must match for copying schema: %v vs %v", sqlparser.String(sourceTableName), ts.TargetTable)
@@ -0,0 +1,288 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/log"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+const (
+	// defaultProgressSamplingPeriod is the default period over which the
+	// progress of a workflow is sampled to compute its rates and trends.
+	defaultProgressSamplingPeriod = 10 * time.Minute
+	// maxProgressSamples caps the number of samples kept for a workflow.
+	maxProgressSamples = 120
+)
+
+// progressSample is a snapshot of the progress of a workflow.
+type progressSample struct {
+	time time.Time
+	// tables holds the progress of the tables being copied.
+	tables map[string]tableCopyProgress
+	// lags holds the replication lag of the running streams, in seconds,
+	// keyed by keyspace/shard/id.
+	lags map[string]int64
+}
+
+// progressTracker keeps the progress samples of the workflows over the
+// sampling period. The samples are recorded by the ProgressSampler when it
+// runs, and by the status requests of the workflows otherwise.
+type progressTracker struct {
+	mu      sync.Mutex
+	period  time.Duration
+	sampled bool
+	samples map[string][]*progressSample
+}
+
+func newProgressTracker() *progressTracker {
+	return &progressTracker{
+		period:  defaultProgressSamplingPeriod,
+		samples: make(map[string][]*progressSample),
+	}
+}
+
+// setSampled records whether a ProgressSampler records the samples, and
+// the period over which they are kept.
+func (pt *progressTracker) setSampled(sampled bool, period time.Duration) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	pt.sampled = sampled
+	pt.period = period
+}
+
+func (pt *progressTracker) isSampled() bool {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	return pt.sampled
+}
+
+// oldest returns the oldest sample kept for the workflow over the sampling
+// period before now, or nil if there is none.
+func (pt *progressTracker) oldest(key string, now time.Time) *progressSample {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	cutoff := now.Add(-pt.period)
+	for _, sample := range pt.samples[key] {
+		if !sample.time.Before(cutoff) {
+			if sample.time.Before(now) {
+				return sample
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// record adds a sample for the workflow and returns the oldest sample kept
+// for it over the sampling period, which is nil if there is none before the
+// new sample. The samples of the workflows that were not sampled over the
+// period are dropped.
+func (pt *progressTracker) record(key string, sample *progressSample) *progressSample {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	cutoff := sample.time.Add(-pt.period)
+	for k, samples := range pt.samples {
+		if k != key && samples[len(samples)-1].time.Before(cutoff) {
+			delete(pt.samples, k)
+		}
+	}
+	samples := pt.samples[key]
+	first := 0
+	for first < len(samples) && samples[first].time.Before(cutoff) {
+		first++
+	}
+	samples = append(samples[first:], sample)
+	if len(samples) > maxProgressSamples {
+		samples = samples[len(samples)-maxProgressSamples:]
+	}
+	pt.samples[key] = samples
+	if len(samples) < 2 || !samples[0].time.Before(sample.time) {
+		return nil
+	}
+	return samples[0]
+}
+
+// addForecast sets the rates, estimates and trends of a status response
+// computed from the oldest sample of the workflow and the current one.
+func addForecast(resp *vtctldatapb.WorkflowStatusResponse, oldest, current *progressSample) {
+	if oldest == nil {
+		return
+	}
+	elapsed := current.time.Sub(oldest.time).Seconds()
+	resp.SamplingPeriod = protoutil.DurationToProto(current.time.Sub(oldest.time))
+
+	var rowsRemaining, rowsCopied int64
+	for table, state := range resp.TableCopyState {
+		progress, ok := current.tables[table]
+		if !ok {
+			continue
+		}
+		previous, ok := oldest.tables[table]
+		if !ok {
+			continue
+		}
+		rowsRate := float64(progress.TargetRowCount-previous.TargetRowCount) / elapsed
+		bytesRate := float64(progress.TargetTableSize-previous.TargetTableSize) / elapsed
+		state.RowsPerSecond = float32(max(rowsRate, 0))
+		state.BytesPerSecond = float32(max(bytesRate, 0))
+		remaining := max(progress.SourceRowCount-progress.TargetRowCount, 0)
+		if rowsRate > 0 {
+			state.EstimatedTimeRemaining = protoutil.DurationToProto(time.Duration(float64(remaining) / rowsRate * float64(time.Second)))
+		}
+		rowsRemaining += remaining
+		rowsCopied += progress.TargetRowCount - previous.TargetRowCount
+	}
+	if rowsCopied > 0 {
+		// The tables are not all copied at the same time, so the estimate for
+		// the workflow is based on the rows copied across all of them.
+		resp.EstimatedCopyTimeRemaining = protoutil.DurationToProto(time.Duration(float64(rowsRemaining) / (float64(rowsCopied) / elapsed) * float64(time.Second)))
+	}
+
+	for ksShard, shardStreams := range resp.ShardStreams {
+		for _, st := range shardStreams.Streams {
+			key := streamProgressKey(ksShard, st.Id)
+			lag, ok := current.lags[key]
+			if !ok {
+				continue
+			}
+			previous, ok := oldest.lags[key]
+			if !ok {
+				continue
+			}
+			st.ReplicationLagTrend = float32(float64(lag-previous) / elapsed)
+		}
+	}
+}
+
+func streamProgressKey(ksShard string, id int32) string {
+	return fmt.Sprintf("%s/%d", ksShard, id)
+}
+
+// ProgressSampler samples the progress of the workflows of every keyspace at
+// a regular interval, so that their rates and trends can be computed when
+// their status is requested, whether or not it was requested before.
+type ProgressSampler struct {
+	ws       *Server
+	interval time.Duration
+	period   time.Duration
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewProgressSampler returns a ProgressSampler that samples the progress of
+// the workflows of the server every interval, and keeps their samples over
+// period.
+func NewProgressSampler(ws *Server, interval, period time.Duration) *ProgressSampler {
+	return &ProgressSampler{
+		ws:       ws,
+		interval: interval,
+		period:   period,
+	}
+}
+
+// Open starts sampling the progress of the workflows in the background.
+func (ps *ProgressSampler) Open() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ps.cancel = cancel
+	ps.done = make(chan struct{})
+	ps.ws.progress.setSampled(true, ps.period)
+	go ps.run(ctx, ps.done)
+}
+
+// Close stops sampling the progress of the workflows and waits for the
+// sampling in progress to return.
+func (ps *ProgressSampler) Close() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.cancel == nil {
+		return
+	}
+	ps.cancel()
+	<-ps.done
+	ps.cancel = nil
+	ps.ws.progress.setSampled(false, defaultProgressSamplingPeriod)
+}
+
+func (ps *ProgressSampler) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(ps.interval)
+	defer ticker.Stop()
+	for {
+		ps.sampleAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sampleAll records a progress sample for each workflow of each keyspace.
+// Online DDL workflows are not sampled, as their progress is reported by
+// the schema migrations.
+func (ps *ProgressSampler) sampleAll(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, ps.interval)
+	defer cancel()
+	keyspaces, err := ps.ws.ts.GetKeyspaces(ctx)
+	if err != nil {
+		log.Warningf("Failed to get the keyspaces to sample the progress of their workflows: %v", err)
+		return
+	}
+	for _, keyspace := range keyspaces {
+		resp, err := ps.ws.GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{Keyspace: keyspace, ActiveOnly: true})
+		if err != nil {
+			log.Warningf("Failed to get the workflows of keyspace %s to sample their progress: %v", keyspace, err)
+			continue
+		}
+		for _, wf := range resp.Workflows {
+			if wf.WorkflowType == binlogdatapb.VReplicationWorkflowType_OnlineDDL.String() {
+				continue
+			}
+			_, sample, err := ps.ws.workflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{Keyspace: keyspace, Workflow: wf.Name})
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Warningf("Failed to sample the progress of workflow %s.%s: %v", keyspace, wf.Name, err)
+				continue
+			}
+			ps.ws.progress.record(progressKey(keyspace, wf.Name), sample)
+		}
+	}
+}
+
+func progressKey(keyspace, workflow string) string {
+	return fmt.Sprintf("%s.%s", keyspace, workflow)
+}
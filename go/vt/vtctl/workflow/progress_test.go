@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestProgressTracker(t *testing.T) {
+	pt := newProgressTracker()
+	start := time.Now()
+	sampleAt := func(d time.Duration) *progressSample {
+		return &progressSample{time: start.Add(d)}
+	}
+
+	require.Nil(t, pt.record("ks.wf", sampleAt(0)))
+	require.Equal(t, start, pt.record("ks.wf", sampleAt(time.Minute)).time)
+	require.Equal(t, start, pt.record("ks.wf", sampleAt(5*time.Minute)).time)
+	require.Nil(t, pt.record("ks.other", sampleAt(5*time.Minute)))
+
+	// The samples older than the sampling period are dropped.
+	require.Equal(t, start.Add(time.Minute), pt.record("ks.wf", sampleAt(defaultProgressSamplingPeriod+time.Minute)).time)
+	// As are the workflows which were not sampled over the period.
+	pt.record("ks.wf", sampleAt(3*defaultProgressSamplingPeriod))
+	require.NotContains(t, pt.samples, "ks.other")
+	require.Len(t, pt.samples["ks.wf"], 1)
+
+	for i := range 2 * maxProgressSamples {
+		pt.record("ks.wf", sampleAt(3*defaultProgressSamplingPeriod+time.Duration(i)*time.Second))
+	}
+	require.Len(t, pt.samples["ks.wf"], maxProgressSamples)
+}
+
+func TestProgressTrackerOldest(t *testing.T) {
+	pt := newProgressTracker()
+	pt.setSampled(true, 5*time.Minute)
+	require.True(t, pt.isSampled())
+	start := time.Now()
+	for i := range 10 {
+		pt.record("ks.wf", &progressSample{time: start.Add(time.Duration(i) * time.Minute)})
+	}
+	// The samples are kept over the period set by the sampler.
+	require.Len(t, pt.samples["ks.wf"], 6)
+
+	require.Equal(t, start.Add(4*time.Minute), pt.oldest("ks.wf", start.Add(9*time.Minute)).time)
+	require.Equal(t, start.Add(5*time.Minute), pt.oldest("ks.wf", start.Add(10*time.Minute)).time)
+	// Reading the samples does not record one.
+	require.Len(t, pt.samples["ks.wf"], 6)
+	require.Nil(t, pt.oldest("ks.wf", start.Add(20*time.Minute)))
+	require.Nil(t, pt.oldest("ks.wf", start.Add(4*time.Minute)))
+	require.Nil(t, pt.oldest("ks.other", start.Add(9*time.Minute)))
+}
+
+func TestProgressSampler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	ws := NewServer(vtenv.NewTestEnv(), ts, nil)
+
+	ps := NewProgressSampler(ws, time.Millisecond, time.Minute)
+	ps.Open()
+	require.True(t, ws.progress.isSampled())
+	require.Equal(t, time.Minute, ws.progress.period)
+	time.Sleep(10 * time.Millisecond)
+	ps.Close()
+	require.False(t, ws.progress.isSampled())
+	require.Equal(t, defaultProgressSamplingPeriod, ws.progress.period)
+	// Closing the sampler again is a no-op.
+	ps.Close()
+}
+
+func TestAddForecast(t *testing.T) {
+	start := time.Now()
+	oldest := &progressSample{
+		time: start,
+		tables: map[string]tableCopyProgress{
+			"t1": {TargetRowCount: 100, TargetTableSize: 1000, SourceRowCount: 1000, SourceTableSize: 10000},
+			"t2": {TargetRowCount: 0, TargetTableSize: 0, SourceRowCount: 500, SourceTableSize: 5000},
+		},
+		lags: map[string]int64{"ks/-80/1": 100},
+	}
+	current := &progressSample{
+		time: start.Add(100 * time.Second),
+		tables: map[string]tableCopyProgress{
+			"t1": {TargetRowCount: 600, TargetTableSize: 6000, SourceRowCount: 1000, SourceTableSize: 10000},
+			"t2": {TargetRowCount: 0, TargetTableSize: 0, SourceRowCount: 500, SourceTableSize: 5000},
+		},
+		lags: map[string]int64{"ks/-80/1": 50},
+	}
+	newResponse := func() *vtctldatapb.WorkflowStatusResponse {
+		return &vtctldatapb.WorkflowStatusResponse{
+			TableCopyState: map[string]*vtctldatapb.WorkflowStatusResponse_TableCopyState{
+				"t1": {},
+				"t2": {},
+			},
+			ShardStreams: map[string]*vtctldatapb.WorkflowStatusResponse_ShardStreams{
+				"ks/-80": {Streams: []*vtctldatapb.WorkflowStatusResponse_ShardStreamState{{Id: 1}, {Id: 2}}},
+			},
+		}
+	}
+
+	resp := newResponse()
+	addForecast(resp, nil, current)
+	require.Nil(t, resp.SamplingPeriod)
+	require.Nil(t, resp.EstimatedCopyTimeRemaining)
+
+	resp = newResponse()
+	addForecast(resp, oldest, current)
+	require.Equal(t, int64(100), resp.SamplingPeriod.Seconds)
+	t1 := resp.TableCopyState["t1"]
+	require.Equal(t, float32(5), t1.RowsPerSecond)
+	require.Equal(t, float32(50), t1.BytesPerSecond)
+	require.Equal(t, int64(80), t1.EstimatedTimeRemaining.Seconds)
+	t2 := resp.TableCopyState["t2"]
+	require.Zero(t, t2.RowsPerSecond)
+	require.Nil(t, t2.EstimatedTimeRemaining)
+	// The 900 rows left to copy in both tables are copied at 5 rows/sec.
+	require.Equal(t, int64(180), resp.EstimatedCopyTimeRemaining.Seconds)
+	streams := resp.ShardStreams["ks/-80"].Streams
+	require.Equal(t, float32(-0.5), streams[0].ReplicationLagTrend)
+	require.Zero(t, streams[1].ReplicationLagTrend)
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// defaultRestoreTableSuffix is appended to the names of the restored tables
+// when no explicit suffix is requested.
+const defaultRestoreTableSuffix = "_restored"
+
+// defaultRestoreTableTabletTypes are the tablet types of the restore keyspace
+// that are restored and copied from when none are requested.
+var defaultRestoreTableTabletTypes = []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY}
+
+// RestoreTableCreate is part of the vtctlservicepb.VtctldServer interface.
+// It restores a backup onto the tablets of a SNAPSHOT keyspace and then
+// creates a Materialize workflow that copies the requested tables, optionally
+// filtered, from those tablets into new tables in the target keyspace. The
+// workflow stops once the copy phase is done.
+func (s *Server) RestoreTableCreate(ctx context.Context, req *vtctldatapb.RestoreTableCreateRequest) (*vtctldatapb.WorkflowStatusResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.RestoreTableCreate")
+	defer span.Finish()
+
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("target_keyspace", req.TargetKeyspace)
+	span.Annotate("restore_keyspace", req.RestoreKeyspace)
+	span.Annotate("tables", req.Tables)
+	span.Annotate("skip_restore", req.SkipRestore)
+
+	if err := validateRestoreTableRequest(req); err != nil {
+		return nil, err
+	}
+	ki, err := s.ts.GetKeyspace(ctx, req.RestoreKeyspace)
+	if topo.IsErrType(err, topo.NoNode) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "restore keyspace %s does not exist, create it as a SNAPSHOT keyspace of %s with tablets to restore into first",
+			req.RestoreKeyspace, req.TargetKeyspace)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ki.KeyspaceType != topodatapb.KeyspaceType_SNAPSHOT {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "restore keyspace %s must be a SNAPSHOT keyspace", req.RestoreKeyspace)
+	}
+
+	tabletTypes := req.TabletTypes
+	if len(tabletTypes) == 0 {
+		tabletTypes = defaultRestoreTableTabletTypes
+	}
+	tablets, err := s.getRestoreTablets(ctx, req.RestoreKeyspace, req.Cells, tabletTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.SkipRestore {
+		if err := s.restoreTablets(ctx, tablets, &tabletmanagerdatapb.RestoreFromBackupRequest{
+			BackupTime:         req.BackupTime,
+			RestoreToPos:       req.RestoreToPos,
+			RestoreToTimestamp: req.RestoreToTimestamp,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// All the restore tablets were restored from the same backup, so any
+	// one of them can provide the schema of the restored tables.
+	schema, err := s.tmc.GetSchema(ctx, tablets[0].Tablet, &tabletmanagerdatapb.GetSchemaRequest{Tables: req.Tables})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get the schema of the restored tables from %s", topoproto.TabletAliasString(tablets[0].Alias))
+	}
+	suffix := req.TargetTableSuffix
+	if suffix == "" {
+		suffix = defaultRestoreTableSuffix
+	}
+	tableSettings, err := buildRestoreTableSettings(s.env.Parser(), schema, req.Tables, suffix, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addRestoredTablesToVSchema(ctx, req.TargetKeyspace, tableSettings, suffix); err != nil {
+		return nil, err
+	}
+
+	mz := &materializer{
+		ctx:      ctx,
+		ts:       s.ts,
+		sourceTs: s.ts,
+		tmc:      s.tmc,
+		ms: &vtctldatapb.MaterializeSettings{
+			Workflow:              req.Workflow,
+			SourceKeyspace:        req.RestoreKeyspace,
+			TargetKeyspace:        req.TargetKeyspace,
+			TableSettings:         tableSettings,
+			StopAfterCopy:         true,
+			Cell:                  strings.Join(req.Cells, ","),
+			TabletTypes:           topoproto.MakeStringTypeCSV(tabletTypes),
+			MaterializationIntent: vtctldatapb.MaterializationIntent_CUSTOM,
+		},
+		env: s.env,
+		// The restore keyspace has no primaries, and every table has its own
+		// create DDL.
+		lazySourceDDLs: true,
+	}
+	err = mz.createWorkflowStreams(&tabletmanagerdatapb.CreateVReplicationWorkflowRequest{
+		Workflow:                  req.Workflow,
+		Cells:                     req.Cells,
+		TabletTypes:               tabletTypes,
+		TabletSelectionPreference: tabletmanagerdatapb.TabletSelectionPreference_ANY,
+		WorkflowType:              mz.getWorkflowType(),
+		AutoStart:                 req.AutoStart,
+		StopAfterCopy:             true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if req.AutoStart {
+		if err := mz.startStreams(ctx); err != nil {
+			return nil, err
+		}
+	}
+	var targetShards []string
+	for _, shard := range mz.targetShards {
+		targetShards = append(targetShards, shard.ShardName())
+	}
+	return s.WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
+		Keyspace: req.TargetKeyspace,
+		Workflow: req.Workflow,
+		Shards:   targetShards,
+	})
+}
+
+func validateRestoreTableRequest(req *vtctldatapb.RestoreTableCreateRequest) error {
+	switch {
+	case req.Workflow == "":
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a workflow name is required")
+	case req.TargetKeyspace == "":
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a target keyspace is required")
+	case req.RestoreKeyspace == "":
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a restore keyspace is required")
+	case req.RestoreKeyspace == req.TargetKeyspace:
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the restore keyspace cannot be the target keyspace")
+	case len(req.Tables) == 0:
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "at least one table to restore is required")
+	case req.RestoreToPos != "" && !protoutil.TimeFromProto(req.RestoreToTimestamp).IsZero():
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--restore-to-pos and --restore-to-timestamp are mutually exclusive")
+	}
+	return nil
+}
+
+// getRestoreTablets returns the tablets of the restore keyspace that match
+// the given cells and tablet types. Every shard of the keyspace must have at
+// least one such tablet, and none of them may be a primary as those cannot be
+// restored from backup.
+func (s *Server) getRestoreTablets(ctx context.Context, keyspace string, cells []string, tabletTypes []topodatapb.TabletType) ([]*topo.TabletInfo, error) {
+	if slices.Contains(tabletTypes, topodatapb.TabletType_PRIMARY) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "PRIMARY tablets cannot be restored from backup")
+	}
+	shards, err := s.ts.GetServingShards(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	var tablets []*topo.TabletInfo
+	for _, shard := range shards {
+		shardTablets, err := s.ts.GetTabletsByShardCell(ctx, keyspace, shard.ShardName(), cells)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, tablet := range shardTablets {
+			if slices.Contains(tabletTypes, tablet.Type) {
+				tablets = append(tablets, tablet)
+				found = true
+			}
+		}
+		if !found {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no %s tablets found in shard %s/%s to restore into",
+				topoproto.MakeStringTypeCSV(tabletTypes), keyspace, shard.ShardName())
+		}
+	}
+	return tablets, nil
+}
+
+// restoreTablets restores the given tablets from backup in parallel, and
+// waits for all of them to finish.
+func (s *Server) restoreTablets(ctx context.Context, tablets []*topo.TabletInfo, req *tabletmanagerdatapb.RestoreFromBackupRequest) error {
+	var wg sync.WaitGroup
+	rec := &concurrency.AllErrorRecorder{}
+	for _, tablet := range tablets {
+		wg.Add(1)
+		go func(tablet *topo.TabletInfo) {
+			defer wg.Done()
+			alias := topoproto.TabletAliasString(tablet.Alias)
+			s.Logger().Infof("Restoring tablet %s from backup", alias)
+			stream, err := s.tmc.RestoreFromBackup(ctx, tablet.Tablet, req)
+			if err != nil {
+				rec.RecordError(vterrors.Wrapf(err, "failed to restore tablet %s", alias))
+				return
+			}
+			for {
+				event, err := stream.Recv()
+				if err == io.EOF {
+					return
+				}
+				if err != nil {
+					rec.RecordError(vterrors.Wrapf(err, "failed to restore tablet %s", alias))
+					return
+				}
+				logutil.LogEvent(s.Logger(), event)
+			}
+		}(tablet)
+	}
+	wg.Wait()
+	return rec.AggrError(vterrors.Aggregate)
+}
+
+// buildRestoreTableSettings returns the table settings that copy the given
+// tables from the restore keyspace into tables with the given suffix. The
+// target tables are created with the schema of the restored tables, without
+// their foreign keys since the referenced tables are not restored.
+func buildRestoreTableSettings(parser *sqlparser.Parser, schema *tabletmanagerdatapb.SchemaDefinition, tables []string, suffix, filter string) ([]*vtctldatapb.TableMaterializeSettings, error) {
+	ddls := make(map[string]string, len(schema.TableDefinitions))
+	for _, td := range schema.TableDefinitions {
+		ddls[td.Name] = td.Schema
+	}
+	tableSettings := make([]*vtctldatapb.TableMaterializeSettings, 0, len(tables))
+	for _, table := range tables {
+		ddl, ok := ddls[table]
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s was not found in the restored schema", table)
+		}
+		targetTable := table + suffix
+		createDDL, err := renameCreateTable(parser, ddl, targetTable)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to build the create statement for %s", targetTable)
+		}
+		sourceExpression := fmt.Sprintf("select * from %s", sqlescape.EscapeID(table))
+		if filter != "" {
+			sourceExpression = fmt.Sprintf("%s where %s", sourceExpression, filter)
+		}
+		if _, err := parser.Parse(sourceExpression); err != nil {
+			return nil, vterrors.Wrapf(err, "invalid filter %q", filter)
+		}
+		tableSettings = append(tableSettings, &vtctldatapb.TableMaterializeSettings{
+			TargetTable:      targetTable,
+			SourceExpression: sourceExpression,
+			CreateDdl:        createDDL,
+		})
+	}
+	return tableSettings, nil
+}
+
+// renameCreateTable returns the given CREATE TABLE statement for a table
+// with the given name, and without foreign keys.
+func renameCreateTable(parser *sqlparser.Parser, ddl, name string) (string, error) {
+	ddl, err := stripTableForeignKeys(ddl, parser)
+	if err != nil {
+		return "", err
+	}
+	stmt, err := parser.ParseStrictDDL(ddl)
+	if err != nil {
+		return "", err
+	}
+	createTable, ok := stmt.(*sqlparser.CreateTable)
+	if !ok {
+		return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "expected a CREATE TABLE statement: %s", ddl)
+	}
+	createTable.Table = sqlparser.NewTableName(name)
+	return sqlparser.String(createTable), nil
+}
+
+// addRestoredTablesToVSchema adds the tables that the restored rows are
+// copied into to the vschema of a sharded target keyspace, using the same
+// definition as the tables they were restored from.
+func (s *Server) addRestoredTablesToVSchema(ctx context.Context, keyspace string, tableSettings []*vtctldatapb.TableMaterializeSettings, suffix string) error {
+	vschema, err := s.ts.GetVSchema(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+	if !vschema.Sharded {
+		return nil
+	}
+	updated := false
+	for _, ts := range tableSettings {
+		if _, ok := vschema.Tables[ts.TargetTable]; ok {
+			continue
+		}
+		table := strings.TrimSuffix(ts.TargetTable, suffix)
+		vtable, ok := vschema.Tables[table]
+		if !ok {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s not found in vschema for keyspace %s", table, keyspace)
+		}
+		vtable = proto.Clone(vtable).(*vschemapb.Table)
+		// Sequences must not be shared with the live table.
+		vtable.AutoIncrement = nil
+		vschema.Tables[ts.TargetTable] = vtable
+		updated = true
+	}
+	if !updated {
+		return nil
+	}
+	if err := s.ts.SaveVSchema(ctx, vschema); err != nil {
+		return err
+	}
+	return s.ts.RebuildSrvVSchema(ctx, nil)
+}
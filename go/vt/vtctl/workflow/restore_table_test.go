@@ -0,0 +1,203 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
+)
+
+func TestValidateRestoreTableRequest(t *testing.T) {
+	valid := func() *vtctldatapb.RestoreTableCreateRequest {
+		return &vtctldatapb.RestoreTableCreateRequest{
+			Workflow:        "wf",
+			TargetKeyspace:  "ks",
+			RestoreKeyspace: "ks_restore",
+			Tables:          []string{"t1"},
+		}
+	}
+	tests := []struct {
+		name    string
+		modify  func(req *vtctldatapb.RestoreTableCreateRequest)
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			modify: func(req *vtctldatapb.RestoreTableCreateRequest) {},
+		},
+		{
+			name:    "no workflow",
+			modify:  func(req *vtctldatapb.RestoreTableCreateRequest) { req.Workflow = "" },
+			wantErr: "a workflow name is required",
+		},
+		{
+			name:    "same keyspaces",
+			modify:  func(req *vtctldatapb.RestoreTableCreateRequest) { req.RestoreKeyspace = "ks" },
+			wantErr: "the restore keyspace cannot be the target keyspace",
+		},
+		{
+			name:    "no tables",
+			modify:  func(req *vtctldatapb.RestoreTableCreateRequest) { req.Tables = nil },
+			wantErr: "at least one table to restore is required",
+		},
+		{
+			name: "position and timestamp",
+			modify: func(req *vtctldatapb.RestoreTableCreateRequest) {
+				req.RestoreToPos = "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615"
+				req.RestoreToTimestamp = &vttimepb.Time{Seconds: 1}
+			},
+			wantErr: "mutually exclusive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(req)
+			err := validateRestoreTableRequest(req)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBuildRestoreTableSettings(t *testing.T) {
+	parser := vtenv.NewTestEnv().Parser()
+	schema := &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{
+				Name:   "t1",
+				Schema: "CREATE TABLE `t1` (`id` int NOT NULL, `parent_id` int, PRIMARY KEY (`id`), CONSTRAINT `fk1` FOREIGN KEY (`parent_id`) REFERENCES `t2` (`id`))",
+			},
+			{
+				Name:   "t2",
+				Schema: "CREATE TABLE `t2` (`id` int NOT NULL, PRIMARY KEY (`id`))",
+			},
+		},
+	}
+
+	tableSettings, err := buildRestoreTableSettings(parser, schema, []string{"t1", "t2"}, "_restored", "id < 100")
+	require.NoError(t, err)
+	require.Len(t, tableSettings, 2)
+	assert.Equal(t, "t1_restored", tableSettings[0].TargetTable)
+	assert.Equal(t, "select * from `t1` where id < 100", tableSettings[0].SourceExpression)
+	assert.Equal(t, "create table t1_restored (\n\tid int not null,\n\tparent_id int,\n\tprimary key (id)\n)", tableSettings[0].CreateDdl)
+	assert.Equal(t, "t2_restored", tableSettings[1].TargetTable)
+	assert.Equal(t, "select * from `t2` where id < 100", tableSettings[1].SourceExpression)
+
+	tableSettings, err = buildRestoreTableSettings(parser, schema, []string{"t2"}, "_old", "")
+	require.NoError(t, err)
+	require.Len(t, tableSettings, 1)
+	assert.Equal(t, "t2_old", tableSettings[0].TargetTable)
+	assert.Equal(t, "select * from `t2`", tableSettings[0].SourceExpression)
+
+	_, err = buildRestoreTableSettings(parser, schema, []string{"t3"}, "_restored", "")
+	assert.ErrorContains(t, err, "table t3 was not found in the restored schema")
+
+	_, err = buildRestoreTableSettings(parser, schema, []string{"t1"}, "_restored", "id <")
+	assert.ErrorContains(t, err, "invalid filter")
+}
+
+func TestRestoreTableCreatePreconditions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	ws := NewServer(vtenv.NewTestEnv(), ts, nil)
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks_restore", &topodatapb.Keyspace{
+		KeyspaceType: topodatapb.KeyspaceType_SNAPSHOT,
+		BaseKeyspace: "ks",
+		SnapshotTime: &vttimepb.Time{Seconds: 1},
+	}))
+	require.NoError(t, ts.CreateShard(ctx, "ks_restore", "0"))
+
+	req := &vtctldatapb.RestoreTableCreateRequest{
+		Workflow:        "wf",
+		TargetKeyspace:  "ks",
+		RestoreKeyspace: "ks_missing",
+		Tables:          []string{"t1"},
+	}
+	_, err := ws.RestoreTableCreate(ctx, req)
+	assert.ErrorContains(t, err, "restore keyspace ks_missing does not exist, create it as a SNAPSHOT keyspace of ks")
+
+	req.TargetKeyspace, req.RestoreKeyspace = "ks_restore", "ks"
+	_, err = ws.RestoreTableCreate(ctx, req)
+	assert.ErrorContains(t, err, "restore keyspace ks must be a SNAPSHOT keyspace")
+
+	req.TargetKeyspace, req.RestoreKeyspace = "ks", "ks_restore"
+	_, err = ws.RestoreTableCreate(ctx, req)
+	assert.ErrorContains(t, err, "no replica,rdonly tablets found in shard ks_restore/0 to restore into")
+
+	req.TabletTypes = []topodatapb.TabletType{topodatapb.TabletType_PRIMARY}
+	_, err = ws.RestoreTableCreate(ctx, req)
+	assert.ErrorContains(t, err, "PRIMARY tablets cannot be restored from backup")
+}
+
+func TestAddRestoredTablesToVSchema(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	ws := NewServer(vtenv.NewTestEnv(), ts, nil)
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{
+		Name: "ks",
+		Keyspace: &vschemapb.Keyspace{
+			Sharded: true,
+			Vindexes: map[string]*vschemapb.Vindex{
+				"hash": {Type: "hash"},
+			},
+			Tables: map[string]*vschemapb.Table{
+				"t1": {
+					ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}},
+					AutoIncrement:  &vschemapb.AutoIncrement{Column: "id", Sequence: "t1_seq"},
+				},
+			},
+		},
+	}))
+
+	tableSettings := []*vtctldatapb.TableMaterializeSettings{{TargetTable: "t1_restored"}}
+	require.NoError(t, ws.addRestoredTablesToVSchema(ctx, "ks", tableSettings, "_restored"))
+	vschema, err := ts.GetVSchema(ctx, "ks")
+	require.NoError(t, err)
+	require.Contains(t, vschema.Tables, "t1_restored")
+	assert.Equal(t, vschema.Tables["t1"].ColumnVindexes, vschema.Tables["t1_restored"].ColumnVindexes)
+	assert.Nil(t, vschema.Tables["t1_restored"].AutoIncrement)
+	assert.NotNil(t, vschema.Tables["t1"].AutoIncrement)
+
+	tableSettings = []*vtctldatapb.TableMaterializeSettings{{TargetTable: "t2_restored"}}
+	err = ws.addRestoredTablesToVSchema(ctx, "ks", tableSettings, "_restored")
+	assert.ErrorContains(t, err, "table t2 not found in vschema for keyspace ks")
+}
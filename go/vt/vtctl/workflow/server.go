@@ -136,15 +136,19 @@ type Server struct {
 	sem     *semaphore.Weighted
 	env     *vtenv.Environment
 	options serverOptions
+	// progress keeps the progress samples used to forecast the completion
+	// of the workflows.
+	progress *progressTracker
 }
 
 // NewServer returns a new server instance with the given topo.Server and
 // TabletManagerClient.
 func NewServer(env *vtenv.Environment, ts *topo.Server, tmc tmclient.TabletManagerClient, opts ...ServerOption) *Server {
 	s := &Server{
-		ts:  ts,
-		tmc: tmc,
-		env: env,
+		ts:       ts,
+		tmc:      tmc,
+		env:      env,
+		progress: newProgressTracker(),
 	}
 	for _, o := range opts {
 		o.apply(&s.options)
@@ -378,6 +382,7 @@ func (s *Server) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflows
 	span.Annotate("workflow", req.Workflow)
 	span.Annotate("active_only", req.ActiveOnly)
 	span.Annotate("include_logs", req.IncludeLogs)
+	span.Annotate("include_schema_drift", req.IncludeSchemaDrift)
 	span.Annotate("shards", req.Shards)
 
 	w := &workflowFetcher{
@@ -385,6 +390,7 @@ func (s *Server) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflows
 		tmc:    s.tmc,
 		parser: s.SQLParser(),
 		logger: s.Logger(),
+		env:    s.env,
 	}
 
 	workflowsByShard, err := w.fetchWorkflowsByShard(ctx, req)
@@ -862,9 +868,14 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 	span.Annotate("cells", req.Cells)
 	span.Annotate("tablet_types", req.TabletTypes)
 	span.Annotate("on_ddl", req.OnDdl)
+	span.Annotate("bidirectional", req.Bidirectional != nil)
 
 	sourceKeyspace := req.SourceKeyspace
 	targetKeyspace := req.TargetKeyspace
+	bidirectional, err := bidirectionalSettings(req, workflowType)
+	if err != nil {
+		return nil, err
+	}
 	// FIXME validate tableSpecs, allTables, excludeTables
 	var (
 		tables       = req.IncludeTables
@@ -953,6 +964,11 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no tables to move")
 	}
 	s.Logger().Infof("Found tables to move: %s", strings.Join(tables, ","))
+	if bidirectional.GetConflictResolution() == binlogdatapb.ConflictResolution_LAST_WRITER_WINS {
+		if err := validateTimestampColumn(ctx, sourceTopo, s.tmc, sourceKeyspace, tables, bidirectional.TimestampColumn); err != nil {
+			return nil, err
+		}
+	}
 
 	if !vschema.Sharded {
 		// Save the original in case we need to restore it for a late failure in
@@ -982,6 +998,7 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		DeferSecondaryKeys:        req.DeferSecondaryKeys,
 		AtomicCopy:                req.AtomicCopy,
 		WorkflowOptions:           req.WorkflowOptions,
+		Bidirectional:             bidirectional,
 	}
 	if req.SourceTimeZone != "" {
 		ms.SourceTimeZone = req.SourceTimeZone
@@ -1431,8 +1448,10 @@ func (s *Server) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDe
 		}
 	}
 
-	// Cleanup related data and artifacts. There are none for a LookupVindex workflow.
-	if ts.workflowType != binlogdatapb.VReplicationWorkflowType_CreateLookupIndex {
+	// Cleanup related data and artifacts. There are none for a LookupVindex
+	// workflow, and the tables of an Export workflow are its source tables.
+	if ts.workflowType != binlogdatapb.VReplicationWorkflowType_CreateLookupIndex &&
+		ts.workflowType != binlogdatapb.VReplicationWorkflowType_Export {
 		if _, err := s.dropTargets(ctx, ts, req.GetKeepData(), req.GetKeepRoutingRules(), false, opts...); err != nil {
 			if topo.IsErrType(err, topo.NoNode) {
 				return nil, vterrors.Wrapf(err, "%s keyspace does not exist", req.GetKeyspace())
@@ -1468,17 +1487,44 @@ func (s *Server) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDe
 }
 
 func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowStatusRequest) (*vtctldatapb.WorkflowStatusResponse, error) {
-	ts, state, err := s.getWorkflowState(ctx, req.Keyspace, req.Workflow)
+	resp, sample, err := s.workflowStatus(ctx, req)
 	if err != nil {
 		return nil, err
 	}
+	if s.progress != nil {
+		// The samples of the ProgressSampler are used when it runs, rather
+		// than the samples of the status requests.
+		key := progressKey(req.Keyspace, req.Workflow)
+		var oldest *progressSample
+		if s.progress.isSampled() {
+			oldest = s.progress.oldest(key, sample.time)
+		} else {
+			oldest = s.progress.record(key, sample)
+		}
+		addForecast(resp, oldest, sample)
+	}
+	return resp, nil
+}
+
+// workflowStatus returns the status of the workflow, without its forecast,
+// along with the progress sample it is built from.
+func (s *Server) workflowStatus(ctx context.Context, req *vtctldatapb.WorkflowStatusRequest) (*vtctldatapb.WorkflowStatusResponse, *progressSample, error) {
+	ts, state, err := s.getWorkflowState(ctx, req.Keyspace, req.Workflow)
+	if err != nil {
+		return nil, nil, err
+	}
 	copyProgress, err := s.GetCopyProgress(ctx, ts, state)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	resp := &vtctldatapb.WorkflowStatusResponse{
 		TrafficState: state.String(),
 	}
+	sample := &progressSample{
+		time:   time.Now(),
+		tables: make(map[string]tableCopyProgress),
+		lags:   make(map[string]int64),
+	}
 	if copyProgress != nil {
 		resp.TableCopyState = make(map[string]*vtctldatapb.WorkflowStatusResponse_TableCopyState, len(*copyProgress))
 		// We sort the tables for intuitive and consistent output.
@@ -1492,6 +1538,7 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 			var rowCountPct, tableSizePct float32
 			resp.TableCopyState[table] = &vtctldatapb.WorkflowStatusResponse_TableCopyState{}
 			progress = *(*copyProgress)[table]
+			sample.tables[table] = progress
 			if progress.SourceRowCount > 0 {
 				rowCountPct = float32(100.0 * float64(progress.TargetRowCount) / float64(progress.SourceRowCount))
 			}
@@ -1509,7 +1556,7 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 
 	workflow, err := s.GetWorkflow(ctx, req.Keyspace, req.Workflow, false, req.Shards)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	// The stream key is target keyspace/tablet alias, e.g. 0/test-0000000100.
 	// We sort the keys for intuitive and consistent output.
@@ -1523,7 +1570,7 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 		streams := workflow.ShardStreams[streamKey].GetStreams()
 		keyParts := strings.Split(streamKey, "/")
 		if len(keyParts) != 2 {
-			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected stream key format in: %s ; expect <keyspace>/<tablet-alias>",
+			return nil, nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected stream key format in: %s ; expect <keyspace>/<tablet-alias>",
 				streamKey)
 		}
 		// We want to use target keyspace/shard as the map key for the
@@ -1559,11 +1606,16 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 			ts.Position = st.Position
 			ts.Status = st.State
 			ts.Info = strings.Join(info, "; ")
+			if st.State == binlogdatapb.VReplicationWorkflowState_Running.String() && st.Position != "" {
+				ts.ReplicationLagSeconds = int64(getVReplicationTrxLag(st.TransactionTimestamp, st.TimeUpdated, st.TimeHeartbeat,
+					binlogdatapb.VReplicationWorkflowState_Running))
+				sample.lags[streamProgressKey(ksShard, ts.Id)] = ts.ReplicationLagSeconds
+			}
 			resp.ShardStreams[ksShard].Streams[i] = ts
 		}
 	}
 
-	return resp, nil
+	return resp, sample, nil
 }
 
 // GetCopyProgress returns the progress of all tables being copied in the workflow.
@@ -2623,7 +2675,11 @@ func (s *Server) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.Wor
 		dryRunResults = append(dryRunResults, *rdDryRunResults...)
 	}
 
-	if switchPrimary {
+	switch {
+	case switchPrimary && writesAlreadySwitched && ts.isBidirectional():
+		// A bidirectional workflow is not frozen once writes are switched.
+		s.Logger().Infof("Writes already switched for the bidirectional workflow %s.%s", req.Keyspace, req.Workflow)
+	case switchPrimary:
 		if _, wrDryRunResults, err = s.switchWrites(ctx, req, ts, timeout, false); err != nil {
 			return nil, err
 		}
@@ -2875,6 +2931,11 @@ func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwit
 	if err := ts.validate(ctx); err != nil {
 		return handleError("workflow validation failed", err)
 	}
+	bidirectional := ts.isBidirectional()
+	if bidirectional && !req.EnableReverseReplication {
+		return handleError("invalid request", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION,
+			"reverse replication cannot be disabled for the bidirectional workflow %s", ts.WorkflowName()))
+	}
 
 	if req.EnableReverseReplication {
 		// Does the source keyspace have tablets that are able to manage
@@ -3098,8 +3159,12 @@ func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwit
 	if err := confirmKeyspaceLocksHeld(); err != nil {
 		return handleError("locks were lost", err)
 	}
-	if err := sw.createJournals(ctx, sourceWorkflows); err != nil {
-		return handleError("failed to create the journal", err)
+	if !bidirectional {
+		// The streams of a bidirectional workflow keep replicating, so there
+		// is no journal to stop them.
+		if err := sw.createJournals(ctx, sourceWorkflows); err != nil {
+			return handleError("failed to create the journal", err)
+		}
 	}
 	if err := sw.allowTargetWrites(ctx); err != nil {
 		return handleError(fmt.Sprintf("failed to allow writes in the %s keyspace", ts.TargetKeyspaceName()), err)
@@ -3116,6 +3181,18 @@ func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwit
 		}
 	}
 
+	if bidirectional {
+		// Both keyspaces take writes, and the workflow keeps replicating the
+		// writes to the source keyspace rather than being frozen.
+		if err := sw.dropSourceDeniedTables(ctx); err != nil {
+			return handleError(fmt.Sprintf("failed to allow writes in the %s keyspace", ts.SourceKeyspaceName()), err)
+		}
+		if err := sw.startTargetVReplication(ctx); err != nil {
+			return handleError(fmt.Sprintf("failed to restart the workflow in the %s keyspace", ts.TargetKeyspaceName()), err)
+		}
+		return ts.id, sw.logs(), nil
+	}
+
 	if err := sw.freezeTargetVReplication(ctx); err != nil {
 		return handleError(fmt.Sprintf("failed to freeze the workflow in the %s keyspace", ts.TargetKeyspaceName()), err)
 	}
@@ -88,6 +88,10 @@ func (r *switcher) startReverseVReplication(ctx context.Context) error {
 	return r.ts.startReverseVReplication(ctx)
 }
 
+func (r *switcher) startTargetVReplication(ctx context.Context) error {
+	return r.ts.startTargetVReplication(ctx)
+}
+
 func (r *switcher) createJournals(ctx context.Context, sourceWorkflows []string) error {
 	return r.ts.createJournals(ctx, sourceWorkflows)
 }
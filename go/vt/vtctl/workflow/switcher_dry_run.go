@@ -189,6 +189,20 @@ func (dr *switcherDryRun) streamMigraterfinalize(ctx context.Context, ts *traffi
 	return nil
 }
 
+func (dr *switcherDryRun) startTargetVReplication(ctx context.Context) error {
+	logs := make([]string, 0)
+	targets := maps.Values(dr.ts.Targets())
+	// Sort the slice for deterministic output.
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].GetPrimary().Alias.Uid < targets[j].GetPrimary().Alias.Uid
+	})
+	for _, t := range targets {
+		logs = append(logs, fmt.Sprintf("tablet:%d", t.GetPrimary().Alias.Uid))
+	}
+	dr.drLog.Logf("Restart bidirectional vreplication streams on: [%s]", strings.Join(logs, ","))
+	return nil
+}
+
 func (dr *switcherDryRun) startReverseVReplication(ctx context.Context) error {
 	logs := make([]string, 0)
 	sources := maps.Values(dr.ts.Sources())
@@ -39,6 +39,7 @@ type iswitcher interface {
 	mirrorTableTraffic(ctx context.Context, types []topodatapb.TabletType, percent float32) error
 	streamMigraterfinalize(ctx context.Context, ts *trafficSwitcher, workflows []string) error
 	startReverseVReplication(ctx context.Context) error
+	startTargetVReplication(ctx context.Context) error
 	switchKeyspaceReads(ctx context.Context, types []topodatapb.TabletType) error
 	switchTableReads(ctx context.Context, cells []string, servedType []topodatapb.TabletType, rebuildSrvVSchema bool, direction TrafficSwitchDirection) error
 	switchShardReads(ctx context.Context, cells []string, servedType []topodatapb.TabletType, direction TrafficSwitchDirection) error
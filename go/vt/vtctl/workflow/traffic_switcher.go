@@ -691,6 +691,54 @@ func (ts *trafficSwitcher) startReverseVReplication(ctx context.Context) error {
 	})
 }
 
+// startTargetVReplication restarts the streams of a bidirectional workflow,
+// which keep replicating once writes are switched rather than being frozen.
+func (ts *trafficSwitcher) startTargetVReplication(ctx context.Context) error {
+	return ts.ForAllTargets(func(target *MigrationTarget) error {
+		query := fmt.Sprintf("update _vt.vreplication set state='Running', message='' where db_name=%s and workflow=%s",
+			encodeString(target.GetPrimary().DbName()), encodeString(ts.WorkflowName()))
+		_, err := ts.VReplicationExec(ctx, target.GetPrimary().GetAlias(), query)
+		return err
+	})
+}
+
+// isBidirectional returns true if the workflow replicates in both directions
+// once writes are switched.
+func (ts *trafficSwitcher) isBidirectional() bool {
+	for _, target := range ts.Targets() {
+		for _, bls := range target.Sources {
+			if bls.GetBidirectional() != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reverseVReplicationExists returns true if the streams of the reverse
+// workflow exist on all the source primaries.
+func (ts *trafficSwitcher) reverseVReplicationExists(ctx context.Context) (bool, error) {
+	var (
+		mu     sync.Mutex
+		exists = true
+	)
+	err := ts.ForAllSources(func(source *MigrationSource) error {
+		res, err := ts.TabletManagerClient().ReadVReplicationWorkflow(ctx, source.GetPrimary().Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
+			Workflow: ts.ReverseWorkflowName(),
+		})
+		if err != nil {
+			return err
+		}
+		if len(res.GetStreams()) == 0 {
+			mu.Lock()
+			defer mu.Unlock()
+			exists = false
+		}
+		return nil
+	})
+	return exists, err
+}
+
 func (ts *trafficSwitcher) createJournals(ctx context.Context, sourceWorkflows []string) error {
 	ts.Logger().Infof("In createJournals for source workflows %+v", sourceWorkflows)
 	return ts.ForAllSources(func(source *MigrationSource) error {
@@ -812,30 +860,34 @@ func (ts *trafficSwitcher) changeShardRouting(ctx context.Context) error {
 		ts.Logger().Errorf("%w", err2)
 		return err2
 	}
-	err := ts.ForAllSources(func(source *MigrationSource) error {
-		_, err := ts.TopoServer().UpdateShardFields(ctx, ts.SourceKeyspaceName(), source.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-			si.IsPrimaryServing = false
-			return nil
-		})
-		return err
-	})
-	if err != nil {
+	if err := topo.CheckKeyspaceLocked(ctx, ts.TargetKeyspaceName()); err != nil {
 		return err
 	}
-	err = ts.ForAllTargets(func(target *MigrationTarget) error {
-		_, err := ts.TopoServer().UpdateShardFields(ctx, ts.TargetKeyspaceName(), target.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-			si.IsPrimaryServing = true
-			return nil
-		})
-		return err
+	// The shard records and the SrvKeyspaces are updated in a single
+	// transaction, so that a failure can't leave both the source and the
+	// target shards serving, or neither of them.
+	err := ts.TopoServer().RetryTxn(ctx, func(txn *topo.Txn) error {
+		for _, source := range ts.SourceShards() {
+			if _, err := txn.UpdateShardFields(ctx, ts.SourceKeyspaceName(), source.ShardName(), func(si *topo.ShardInfo) error {
+				si.IsPrimaryServing = false
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		for _, target := range ts.TargetShards() {
+			if _, err := txn.UpdateShardFields(ctx, ts.TargetKeyspaceName(), target.ShardName(), func(si *topo.ShardInfo) error {
+				si.IsPrimaryServing = true
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return txn.MigrateServedType(ctx, ts.TargetKeyspaceName(), ts.TargetShards(), ts.SourceShards(), topodatapb.TabletType_PRIMARY, nil)
 	})
 	if err != nil {
 		return err
 	}
-	err = ts.TopoServer().MigrateServedType(ctx, ts.TargetKeyspaceName(), ts.TargetShards(), ts.SourceShards(), topodatapb.TabletType_PRIMARY, nil)
-	if err != nil {
-		return err
-	}
 	if err := ts.TopoServer().ValidateSrvKeyspace(ctx, ts.TargetKeyspaceName(), ""); err != nil {
 		err2 := vterrors.Wrapf(err, "after changing shard routes, found SrvKeyspace for %s is corrupt", ts.TargetKeyspaceName())
 		ts.Logger().Errorf("%w", err2)
@@ -894,6 +946,18 @@ func (ts *trafficSwitcher) ForAllUIDs(f func(target *MigrationTarget, uid int32)
 }
 
 func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error {
+	if ts.isBidirectional() {
+		exists, err := ts.reverseVReplicationExists(ctx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			// The reverse streams of a bidirectional workflow kept replicating,
+			// and must resume from their own positions.
+			ts.Logger().Infof("Keeping the streams of the bidirectional reverse workflow %s", ts.ReverseWorkflowName())
+			return nil
+		}
+	}
 	if err := ts.deleteReverseVReplication(ctx); err != nil {
 		return err
 	}
@@ -909,6 +973,10 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 			SourceTimeZone: bls.TargetTimeZone,
 			TargetTimeZone: bls.SourceTimeZone,
 		}
+		if bls.Bidirectional != nil {
+			reverseBls.Bidirectional = bls.Bidirectional.CloneVT()
+			reverseBls.Bidirectional.PeerWorkflow = ts.WorkflowName()
+		}
 		var err error
 		for _, rule := range bls.Filter.Rules {
 			if rule.Filter == "exclude" {
@@ -1086,14 +1154,33 @@ func (ts *trafficSwitcher) switchDeniedTables(ctx context.Context, backward bool
 		rmsource, rmtarget = true, false
 	}
 
-	egrp, ectx := errgroup.WithContext(ctx)
-	egrp.Go(func() error {
-		return ts.ForAllSources(func(source *MigrationSource) error {
-			if _, err := ts.TopoServer().UpdateShardFields(ctx, ts.SourceKeyspaceName(), source.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-				return si.UpdateDeniedTables(ectx, topodatapb.TabletType_PRIMARY, nil, rmsource, ts.Tables())
+	// The denied tables of all the shards are updated in a single
+	// transaction, before the tablets are refreshed.
+	err := ts.TopoServer().RetryTxn(ctx, func(txn *topo.Txn) error {
+		for _, source := range ts.SourceShards() {
+			if _, err := txn.UpdateShardFields(ctx, ts.SourceKeyspaceName(), source.ShardName(), func(si *topo.ShardInfo) error {
+				return si.UpdateDeniedTables(ctx, topodatapb.TabletType_PRIMARY, nil, rmsource, ts.Tables())
 			}); err != nil {
 				return err
 			}
+		}
+		for _, target := range ts.TargetShards() {
+			if _, err := txn.UpdateShardFields(ctx, ts.TargetKeyspaceName(), target.ShardName(), func(si *topo.ShardInfo) error {
+				return si.UpdateDeniedTables(ctx, topodatapb.TabletType_PRIMARY, nil, rmtarget, ts.Tables())
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		ts.Logger().Warningf("Error in switchDeniedTables: %s", err)
+		return err
+	}
+
+	egrp, ectx := errgroup.WithContext(ctx)
+	egrp.Go(func() error {
+		return ts.ForAllSources(func(source *MigrationSource) error {
 			rtbsCtx, cancel := context.WithTimeout(ectx, shardTabletRefreshTimeout)
 			defer cancel()
 			isPartial, partialDetails, err := topotools.RefreshTabletsByShard(rtbsCtx, ts.TopoServer(), ts.TabletManagerClient(), source.GetShard(), nil, ts.Logger())
@@ -1112,11 +1199,6 @@ func (ts *trafficSwitcher) switchDeniedTables(ctx context.Context, backward bool
 	})
 	egrp.Go(func() error {
 		return ts.ForAllTargets(func(target *MigrationTarget) error {
-			if _, err := ts.TopoServer().UpdateShardFields(ectx, ts.TargetKeyspaceName(), target.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-				return si.UpdateDeniedTables(ctx, topodatapb.TabletType_PRIMARY, nil, rmtarget, ts.Tables())
-			}); err != nil {
-				return err
-			}
 			rtbsCtx, cancel := context.WithTimeout(ectx, shardTabletRefreshTimeout)
 			defer cancel()
 			isPartial, partialDetails, err := topotools.RefreshTabletsByShard(rtbsCtx, ts.TopoServer(), ts.TabletManagerClient(), target.GetShard(), nil, ts.Logger())
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"math"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -575,6 +576,16 @@ func doValidateWorkflowHasCompleted(ctx context.Context, ts *trafficSwitcher) er
 				return nil
 			}
 			for _, stream := range res.Streams {
+				if stream.Bls.GetBidirectional() != nil {
+					// A bidirectional workflow is not frozen: it must be stopped
+					// once the source keyspace does not take writes anymore.
+					if stream.State != binlogdatapb.VReplicationWorkflowState_Stopped {
+						rec.RecordError(fmt.Errorf("bidirectional vreplication streams are still running on tablet %d, stop the writes to the %s keyspace and then the workflow",
+							target.GetPrimary().Alias.Uid, ts.SourceKeyspaceName()))
+						return nil
+					}
+					continue
+				}
 				if stream.Message != Frozen {
 					rec.RecordError(fmt.Errorf("vreplication streams are not frozen on tablet %d", target.GetPrimary().Alias.Uid))
 					return nil
@@ -622,6 +633,62 @@ func ReverseWorkflowName(workflow string) string {
 	return workflow + reverseSuffix
 }
 
+// bidirectionalSettings validates the bidirectional settings of a MoveTables
+// request and returns the settings of its streams, or nil if the workflow is
+// not bidirectional.
+func bidirectionalSettings(req *vtctldatapb.MoveTablesCreateRequest, workflowType binlogdatapb.VReplicationWorkflowType) (*binlogdatapb.BidirectionalSettings, error) {
+	if req.Bidirectional == nil {
+		return nil, nil
+	}
+	switch {
+	case workflowType != binlogdatapb.VReplicationWorkflowType_MoveTables:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "only MoveTables workflows can be bidirectional")
+	case req.ExternalClusterName != "":
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "bidirectional workflows cannot move tables from an external cluster")
+	case req.GetWorkflowOptions().GetTenantId() != "":
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "bidirectional workflows cannot move the tables of a tenant")
+	case len(req.SourceShards) > 0:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "bidirectional workflows cannot be partial")
+	case req.Bidirectional.ConflictResolution == binlogdatapb.ConflictResolution_LAST_WRITER_WINS && req.Bidirectional.TimestampColumn == "":
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a timestamp column is required to resolve conflicts with %s",
+			binlogdatapb.ConflictResolution_LAST_WRITER_WINS)
+	}
+	settings := req.Bidirectional.CloneVT()
+	settings.PeerWorkflow = ReverseWorkflowName(req.Workflow)
+	settings.SourceKeyspace = req.SourceKeyspace
+	return settings, nil
+}
+
+// validateTimestampColumn ensures that all of the tables have the timestamp
+// column used to resolve the conflicts of a bidirectional workflow.
+func validateTimestampColumn(ctx context.Context, ts *topo.Server, tmc tmclient.TabletManagerClient, keyspace string, tables []string, column string) error {
+	shards, err := ts.GetServingShards(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+	if len(shards) == 0 {
+		return fmt.Errorf("keyspace %s has no shards", keyspace)
+	}
+	primary := shards[0].PrimaryAlias
+	if primary == nil {
+		return fmt.Errorf("shard does not have a primary: %v", shards[0].ShardName())
+	}
+	ti, err := ts.GetTablet(ctx, primary)
+	if err != nil {
+		return err
+	}
+	schema, err := tmc.GetSchema(ctx, ti.Tablet, &tabletmanagerdatapb.GetSchemaRequest{Tables: tables})
+	if err != nil {
+		return err
+	}
+	for _, td := range schema.TableDefinitions {
+		if !slices.Contains(td.Columns, column) {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "table %s does not have the timestamp column %s", td.Name, column)
+		}
+	}
+	return nil
+}
+
 // Straight copy-paste of encodeString from wrangler/keyspace.go. I want to make
 // this public, but it doesn't belong in package workflow. Maybe package sqltypes,
 // or maybe package sqlescape?
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/protobuf/proto"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/testfiles"
@@ -23,6 +24,7 @@ import (
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topotools"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	"vitess.io/vitess/go/vt/proto/vtctldata"
 )
@@ -283,6 +285,74 @@ func TestValidateSourceTablesExist(t *testing.T) {
 	}
 }
 
+func TestBidirectionalSettings(t *testing.T) {
+	testCases := []struct {
+		name         string
+		req          *vtctldata.MoveTablesCreateRequest
+		workflowType binlogdatapb.VReplicationWorkflowType
+		want         *binlogdatapb.BidirectionalSettings
+		errContains  string
+	}{
+		{
+			name: "not bidirectional",
+			req:  &vtctldata.MoveTablesCreateRequest{Workflow: "wf", SourceKeyspace: "ks1"},
+		},
+		{
+			name: "source wins",
+			req: &vtctldata.MoveTablesCreateRequest{Workflow: "wf", SourceKeyspace: "ks1",
+				Bidirectional: &binlogdatapb.BidirectionalSettings{}},
+			want: &binlogdatapb.BidirectionalSettings{PeerWorkflow: "wf_reverse", SourceKeyspace: "ks1"},
+		},
+		{
+			name: "last writer wins",
+			req: &vtctldata.MoveTablesCreateRequest{Workflow: "wf", SourceKeyspace: "ks1",
+				Bidirectional: &binlogdatapb.BidirectionalSettings{ConflictResolution: binlogdatapb.ConflictResolution_LAST_WRITER_WINS, TimestampColumn: "updated_at"}},
+			want: &binlogdatapb.BidirectionalSettings{ConflictResolution: binlogdatapb.ConflictResolution_LAST_WRITER_WINS, TimestampColumn: "updated_at",
+				PeerWorkflow: "wf_reverse", SourceKeyspace: "ks1"},
+		},
+		{
+			name: "last writer wins without a timestamp column",
+			req: &vtctldata.MoveTablesCreateRequest{Workflow: "wf", SourceKeyspace: "ks1",
+				Bidirectional: &binlogdatapb.BidirectionalSettings{ConflictResolution: binlogdatapb.ConflictResolution_LAST_WRITER_WINS}},
+			errContains: "timestamp column is required",
+		},
+		{
+			name: "migrate",
+			req: &vtctldata.MoveTablesCreateRequest{Workflow: "wf", SourceKeyspace: "ks1",
+				Bidirectional: &binlogdatapb.BidirectionalSettings{}},
+			workflowType: binlogdatapb.VReplicationWorkflowType_Migrate,
+			errContains:  "only MoveTables",
+		},
+		{
+			name: "partial",
+			req: &vtctldata.MoveTablesCreateRequest{Workflow: "wf", SourceKeyspace: "ks1", SourceShards: []string{"-80"},
+				Bidirectional: &binlogdatapb.BidirectionalSettings{}},
+			errContains: "cannot be partial",
+		},
+		{
+			name: "multi-tenant",
+			req: &vtctldata.MoveTablesCreateRequest{Workflow: "wf", SourceKeyspace: "ks1",
+				WorkflowOptions: &vtctldata.WorkflowOptions{TenantId: "1"},
+				Bidirectional:   &binlogdatapb.BidirectionalSettings{}},
+			errContains: "tenant",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.workflowType == 0 {
+				tc.workflowType = binlogdatapb.VReplicationWorkflowType_MoveTables
+			}
+			got, err := bidirectionalSettings(tc.req, tc.workflowType)
+			if tc.errContains != "" {
+				assert.ErrorContains(t, err, tc.errContains)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, proto.Equal(tc.want, got), "got: %v, want: %v", got, tc.want)
+		})
+	}
+}
+
 func TestLegacyBuildTargets(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
@@ -45,16 +45,19 @@ import (
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/schemadiff"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtctl/workflow/common"
 	"vitess.io/vitess/go/vt/vtctl/workflow/vexec"
+	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
@@ -68,6 +71,7 @@ type workflowFetcher struct {
 
 	logger logutil.Logger
 	parser *sqlparser.Parser
+	env    *vtenv.Environment
 }
 
 type workflowMetadata struct {
@@ -321,6 +325,18 @@ func (wf *workflowFetcher) buildWorkflows(
 		fetchLogsWG.Wait()
 	}
 
+	if req.IncludeSchemaDrift {
+		var fetchDriftWG sync.WaitGroup
+		for _, workflow := range workflowsMap {
+			fetchDriftWG.Add(1)
+			go func(ctx context.Context, workflow *vtctldatapb.Workflow) {
+				defer fetchDriftWG.Done()
+				wf.fetchSchemaDrift(ctx, workflow)
+			}(ctx, workflow)
+		}
+		fetchDriftWG.Wait()
+	}
+
 	return maps.Values(workflowsMap), nil
 }
 
@@ -392,6 +408,7 @@ func (wf *workflowFetcher) scanWorkflow(
 			Cells:                     cells,
 			TransactionTimestamp:      rstream.TransactionTimestamp,
 			TimeUpdated:               rstream.TimeUpdated,
+			TimeHeartbeat:             rstream.TimeHeartbeat,
 			Message:                   rstream.Message,
 			Tags:                      strings.Split(res.Tags, ","),
 			RowsCopied:                rstream.RowsCopied,
@@ -606,6 +623,146 @@ func (wf *workflowFetcher) fetchStreamLogs(ctx context.Context, keyspace string,
 	}
 }
 
+// fetchSchemaDrift compares the definitions of the tables of every stream of
+// a MoveTables or Reshard workflow on its source and target shards, and
+// records the tables whose definitions differ. The rows of the tables of the
+// other workflows are transformed, so their definitions are not compared.
+func (wf *workflowFetcher) fetchSchemaDrift(ctx context.Context, workflow *vtctldatapb.Workflow) {
+	span, ctx := trace.NewSpan(ctx, "workflowFetcher.workflow.fetchSchemaDrift")
+	defer span.Finish()
+
+	span.Annotate("workflow", workflow.Name)
+
+	if workflow.WorkflowType != binlogdatapb.VReplicationWorkflowType_MoveTables.String() &&
+		workflow.WorkflowType != binlogdatapb.VReplicationWorkflowType_Reshard.String() {
+		return
+	}
+	env := schemadiff.NewEnv(wf.env, wf.env.CollationEnv().DefaultConnectionCharset())
+	// The schemas are fetched once per tablet, as the streams of a shard
+	// share their target tablet and the source shards are shared by the
+	// streams of all the target shards.
+	schemas := make(map[string]*tabletmanagerdatapb.SchemaDefinition)
+	getSchema := func(tablet *topodatapb.Tablet, tables []string) (*tabletmanagerdatapb.SchemaDefinition, error) {
+		key := topoproto.TabletAliasString(tablet.Alias) + "/" + strings.Join(tables, ",")
+		if sd, ok := schemas[key]; ok {
+			return sd, nil
+		}
+		sd, err := wf.tmc.GetSchema(ctx, tablet, &tabletmanagerdatapb.GetSchemaRequest{
+			Tables:          tables,
+			TableSchemaOnly: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		schemas[key] = sd
+		return sd, nil
+	}
+
+	var drift []*vtctldatapb.Workflow_TableSchemaDrift
+	for _, shardStream := range workflow.ShardStreams {
+		for _, stream := range shardStream.Streams {
+			source := stream.BinlogSource
+			if source == nil || source.Filter == nil {
+				continue
+			}
+			// The streams of a Reshard workflow copy all the tables.
+			var tables []string
+			for _, rule := range source.Filter.Rules {
+				if !strings.HasPrefix(rule.Match, "/") && rule.Filter != "exclude" {
+					tables = append(tables, rule.Match)
+				}
+			}
+			sort.Strings(tables)
+			streamDrift, err := wf.diffStreamSchemas(ctx, env, stream, tables, getSchema)
+			if err != nil {
+				drift = append(drift, &vtctldatapb.Workflow_TableSchemaDrift{
+					SourceShard: source.Shard,
+					TargetShard: stream.Shard,
+					Error:       err.Error(),
+				})
+				continue
+			}
+			drift = append(drift, streamDrift...)
+		}
+	}
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Table != drift[j].Table {
+			return drift[i].Table < drift[j].Table
+		}
+		if drift[i].SourceShard != drift[j].SourceShard {
+			return drift[i].SourceShard < drift[j].SourceShard
+		}
+		return drift[i].TargetShard < drift[j].TargetShard
+	})
+	workflow.SchemaDrift = drift
+}
+
+// diffStreamSchemas returns the tables whose definitions differ between the
+// source and the target shards of a stream.
+func (wf *workflowFetcher) diffStreamSchemas(ctx context.Context, env *schemadiff.Environment, stream *vtctldatapb.Workflow_Stream, tables []string,
+	getSchema func(*topodatapb.Tablet, []string) (*tabletmanagerdatapb.SchemaDefinition, error)) ([]*vtctldatapb.Workflow_TableSchemaDrift, error) {
+	source := stream.BinlogSource
+	si, err := wf.ts.GetShard(ctx, source.Keyspace, source.Shard)
+	if err != nil {
+		return nil, err
+	}
+	if si.PrimaryAlias == nil {
+		return nil, fmt.Errorf("%w %s/%s", vexec.ErrNoShardPrimary, source.Keyspace, source.Shard)
+	}
+	sourcePrimary, err := wf.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return nil, err
+	}
+	targetTablet, err := wf.ts.GetTablet(ctx, stream.Tablet)
+	if err != nil {
+		return nil, err
+	}
+	sourceSchema, err := getSchema(sourcePrimary.Tablet, tables)
+	if err != nil {
+		return nil, err
+	}
+	targetSchema, err := getSchema(targetTablet.Tablet, tables)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := make(map[string][2]string)
+	for _, td := range sourceSchema.TableDefinitions {
+		d := defs[td.Name]
+		d[0] = td.Schema
+		defs[td.Name] = d
+	}
+	for _, td := range targetSchema.TableDefinitions {
+		d := defs[td.Name]
+		d[1] = td.Schema
+		defs[td.Name] = d
+	}
+	var drift []*vtctldatapb.Workflow_TableSchemaDrift
+	for table, d := range defs {
+		tableDrift := &vtctldatapb.Workflow_TableSchemaDrift{
+			Table:       table,
+			SourceShard: source.Shard,
+			TargetShard: stream.Shard,
+		}
+		diff, err := schemadiff.DiffCreateTablesQueries(env, d[1], d[0], &schemadiff.DiffHints{})
+		if err != nil {
+			tableDrift.Error = err.Error()
+			drift = append(drift, tableDrift)
+			continue
+		}
+		var statements []string
+		for ; diff != nil && !diff.IsEmpty(); diff = diff.SubsequentDiff() {
+			statements = append(statements, diff.CanonicalStatementString())
+		}
+		if len(statements) == 0 {
+			continue
+		}
+		tableDrift.Diff = strings.Join(statements, "; ")
+		drift = append(drift, tableDrift)
+	}
+	return drift, nil
+}
+
 func (wf *workflowFetcher) forAllShards(
 	ctx context.Context,
 	keyspace string,
@@ -258,3 +258,65 @@ func TestFetchCopyStatesByShardStream(t *testing.T) {
 
 	assert.Nil(t, copyStatesByStreamId["80-/2"])
 }
+
+func TestFetchSchemaDrift(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{KeyspaceName: "source", ShardNames: []string{"0"}}
+	targetKeyspace := &testKeyspace{KeyspaceName: "target", ShardNames: []string{"0"}}
+	te := newTestEnv(t, ctx, "zone1", sourceKeyspace, targetKeyspace)
+	defer te.close()
+
+	tableDefinition := func(name, schema string) *tabletmanagerdatapb.SchemaDefinition {
+		return &tabletmanagerdatapb.SchemaDefinition{
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{Name: name, Schema: schema}},
+		}
+	}
+	te.tmc.schema["source.t1"] = tableDefinition("t1", "create table t1 (id int primary key, c1 varchar(10))")
+	te.tmc.schema["target.t1"] = tableDefinition("t1", "create table t1 (id int primary key)")
+	te.tmc.schema["source.t2"] = tableDefinition("t2", "create table t2 (id int primary key)")
+	te.tmc.schema["target.t2"] = tableDefinition("t2", "create table t2 (id int primary key)")
+
+	wf := &workflowFetcher{
+		ts:  te.ws.ts,
+		tmc: te.tmc,
+		env: te.ws.env,
+	}
+	workflow := &vtctldatapb.Workflow{
+		Name:         "wf1",
+		WorkflowType: binlogdata.VReplicationWorkflowType_MoveTables.String(),
+		ShardStreams: map[string]*vtctldatapb.Workflow_ShardStream{
+			"0/zone1-0000000200": {
+				Streams: []*vtctldatapb.Workflow_Stream{{
+					Id:     1,
+					Shard:  "0",
+					Tablet: te.tablets["target"][startingTargetTabletUID].Alias,
+					BinlogSource: &binlogdata.BinlogSource{
+						Keyspace: "source",
+						Shard:    "0",
+						Filter: &binlogdata.Filter{
+							Rules: []*binlogdata.Rule{
+								{Match: "t1", Filter: "select * from t1"},
+								{Match: "t2", Filter: "select * from t2"},
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+	wf.fetchSchemaDrift(ctx, workflow)
+	require.Len(t, workflow.SchemaDrift, 1)
+	assert.Equal(t, "t1", workflow.SchemaDrift[0].Table)
+	assert.Equal(t, "0", workflow.SchemaDrift[0].SourceShard)
+	assert.Equal(t, "0", workflow.SchemaDrift[0].TargetShard)
+	assert.Equal(t, "ALTER TABLE `t1` ADD COLUMN `c1` varchar(10)", workflow.SchemaDrift[0].Diff)
+	assert.Empty(t, workflow.SchemaDrift[0].Error)
+
+	// The tables of the other workflows are not compared.
+	workflow.SchemaDrift = nil
+	workflow.WorkflowType = binlogdata.VReplicationWorkflowType_Materialize.String()
+	wf.fetchSchemaDrift(ctx, workflow)
+	assert.Empty(t, workflow.SchemaDrift)
+}
@@ -792,6 +792,7 @@ func TestRebuildTopoGraphs(t *testing.T) {
 				if err != nil {
 					return err
 				}
+				factory.AddOperationError(memorytopo.Create, ".*/"+topo.SrvKeyspaceFile, errors.New("simulated topo error"))
 				factory.AddOperationError(memorytopo.Update, ".*/"+topo.SrvKeyspaceFile, errors.New("simulated topo error"))
 				return nil
 			},
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 
 	"vitess.io/vitess/go/viperutil"
 	"vitess.io/vitess/go/vt/servenv"
@@ -209,12 +210,147 @@ var (
 			Dynamic:  true,
 		},
 	)
+
+	preRecoveryHooks = viperutil.Configure(
+		"pre-recovery-hooks",
+		viperutil.Options[[]string]{
+			FlagName: "pre-recovery-hooks",
+			Default:  nil,
+			Dynamic:  true,
+			GetFunc:  getStringArray,
+		},
+	)
+
+	postRecoveryHooks = viperutil.Configure(
+		"post-recovery-hooks",
+		viperutil.Options[[]string]{
+			FlagName: "post-recovery-hooks",
+			Default:  nil,
+			Dynamic:  true,
+			GetFunc:  getStringArray,
+		},
+	)
+
+	recoveryHooksTimeout = viperutil.Configure(
+		"recovery-hooks-timeout",
+		viperutil.Options[time.Duration]{
+			FlagName: "recovery-hooks-timeout",
+			Default:  10 * time.Second,
+			Dynamic:  true,
+		},
+	)
+
+	recoveryHooksRetries = viperutil.Configure(
+		"recovery-hooks-retries",
+		viperutil.Options[int]{
+			FlagName: "recovery-hooks-retries",
+			Default:  2,
+			Dynamic:  true,
+		},
+	)
+
+	enableReplicaRebuild = viperutil.Configure(
+		"enable-replica-rebuild-from-backup",
+		viperutil.Options[bool]{
+			FlagName: "enable-replica-rebuild-from-backup",
+			Default:  false,
+			Dynamic:  true,
+		},
+	)
+
+	replicaRebuildFailedFixes = viperutil.Configure(
+		"replica-rebuild-failed-fixes",
+		viperutil.Options[int]{
+			FlagName: "replica-rebuild-failed-fixes",
+			Default:  3,
+			Dynamic:  true,
+		},
+	)
+
+	replicaRebuildFailedFixesWindow = viperutil.Configure(
+		"replica-rebuild-failed-fixes-window",
+		viperutil.Options[time.Duration]{
+			FlagName: "replica-rebuild-failed-fixes-window",
+			Default:  30 * time.Minute,
+			Dynamic:  true,
+		},
+	)
+
+	replicaRebuildConcurrencyPerCell = viperutil.Configure(
+		"replica-rebuild-concurrency-per-cell",
+		viperutil.Options[int]{
+			FlagName: "replica-rebuild-concurrency-per-cell",
+			Default:  1,
+			Dynamic:  true,
+		},
+	)
+
+	replicaRebuildTimeout = viperutil.Configure(
+		"replica-rebuild-timeout",
+		viperutil.Options[time.Duration]{
+			FlagName: "replica-rebuild-timeout",
+			Default:  4 * time.Hour,
+			Dynamic:  true,
+		},
+	)
+
+	enableDegradedPrimaryReparent = viperutil.Configure(
+		"enable-degraded-primary-reparent",
+		viperutil.Options[bool]{
+			FlagName: "enable-degraded-primary-reparent",
+			Default:  false,
+			Dynamic:  true,
+		},
+	)
+
+	degradedPrimaryMaintenanceTag = viperutil.Configure(
+		"degraded-primary-maintenance-tag",
+		viperutil.Options[string]{
+			FlagName: "degraded-primary-maintenance-tag",
+			Default:  "maintenance",
+			Dynamic:  true,
+		},
+	)
+
+	degradedPrimaryDuration = viperutil.Configure(
+		"degraded-primary-duration",
+		viperutil.Options[time.Duration]{
+			FlagName: "degraded-primary-duration",
+			Default:  5 * time.Minute,
+			Dynamic:  true,
+		},
+	)
+
+	degradedPrimaryReparentCooldown = viperutil.Configure(
+		"degraded-primary-reparent-cooldown",
+		viperutil.Options[time.Duration]{
+			FlagName: "degraded-primary-reparent-cooldown",
+			Default:  time.Hour,
+			Dynamic:  true,
+		},
+	)
 )
 
 func init() {
 	servenv.OnParseFor("vtorc", registerFlags)
 }
 
+// getStringArray is the GetFunc of the values of StringArray flags, which
+// are not split on commas. A single string, from a config file or an
+// environment variable, is a single value rather than being split on
+// spaces by GetStringSlice.
+func getStringArray(v *viper.Viper) func(key string) []string {
+	return func(key string) []string {
+		if s, ok := v.Get(key).(string); ok {
+			if s == "" {
+				return nil
+			}
+			return []string{s}
+		}
+		return v.GetStringSlice(key)
+	}
+}
+
 // registerFlags registers the flags required by VTOrc
 func registerFlags(fs *pflag.FlagSet) {
 	fs.Int("discovery-workers", discoveryWorkers.Default(), "Number of workers used for tablet discovery")
@@ -236,6 +372,19 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.Bool("allow-emergency-reparent", ersEnabled.Default(), "Whether VTOrc should be allowed to run emergency reparent operation when it detects a dead primary")
 	fs.Bool("change-tablets-with-errant-gtid-to-drained", convertTabletsWithErrantGTIDs.Default(), "Whether VTOrc should be changing the type of tablets with errant GTIDs to DRAINED")
 	fs.Bool("enable-primary-disk-stalled-recovery", enablePrimaryDiskStalledRecovery.Default(), "Whether VTOrc should detect a stalled disk on the primary and failover")
+	fs.StringArray("pre-recovery-hooks", preRecoveryHooks.Default(), "Commands, or http(s) webhook URLs, that VTOrc runs before a recovery with a JSON description of the recovery on stdin or as the request body. A hook that fails vetoes the recovery")
+	fs.StringArray("post-recovery-hooks", postRecoveryHooks.Default(), "Commands, or http(s) webhook URLs, that VTOrc runs after a recovery with a JSON description of the recovery and its outcome on stdin or as the request body")
+	fs.Duration("recovery-hooks-timeout", recoveryHooksTimeout.Default(), "Timeout of each attempt to run a pre- or post-recovery hook")
+	fs.Int("recovery-hooks-retries", recoveryHooksRetries.Default(), "Number of times VTOrc retries a pre- or post-recovery hook that failed")
+	fs.Bool("enable-replica-rebuild-from-backup", enableReplicaRebuild.Default(), "Whether VTOrc should rebuild replicas with errant GTIDs, or with replication problems that it failed to fix, by restoring them from the latest backup")
+	fs.Int("replica-rebuild-failed-fixes", replicaRebuildFailedFixes.Default(), "Number of times VTOrc must have tried to fix a replica with stopped replication or a misconfiguration, with the problem persisting after each fix, within --replica-rebuild-failed-fixes-window, before rebuilding it from a backup")
+	fs.Duration("replica-rebuild-failed-fixes-window", replicaRebuildFailedFixesWindow.Default(), "Duration over which the fixes of a replica are counted to decide whether to rebuild it from a backup")
+	fs.Int("replica-rebuild-concurrency-per-cell", replicaRebuildConcurrencyPerCell.Default(), "Maximum number of replicas that each VTOrc process rebuilds from a backup at the same time in each cell. The limit is not shared between VTOrc processes")
+	fs.Duration("replica-rebuild-timeout", replicaRebuildTimeout.Default(), "Maximum duration of the rebuild of a replica from a backup, including the restore and replication catching up")
+	fs.Bool("enable-degraded-primary-reparent", enableDegradedPrimaryReparent.Default(), "Whether VTOrc should run a PlannedReparentShard away from primaries that are reachable but degraded")
+	fs.String("degraded-primary-maintenance-tag", degradedPrimaryMaintenanceTag.Default(), "A primary is degraded when its tablet has this tag set to true. An empty value disables this check")
+	fs.Duration("degraded-primary-duration", degradedPrimaryDuration.Default(), "Duration for which a primary must be degraded before VTOrc reparents away from it")
+	fs.Duration("degraded-primary-reparent-cooldown", degradedPrimaryReparentCooldown.Default(), "Minimum duration between two reparents away from degraded primaries in a shard")
 
 	viperutil.BindFlags(fs,
 		instancePollTime,
@@ -257,6 +406,19 @@ func registerFlags(fs *pflag.FlagSet) {
 		ersEnabled,
 		convertTabletsWithErrantGTIDs,
 		enablePrimaryDiskStalledRecovery,
+		preRecoveryHooks,
+		postRecoveryHooks,
+		recoveryHooksTimeout,
+		recoveryHooksRetries,
+		enableReplicaRebuild,
+		replicaRebuildFailedFixes,
+		replicaRebuildFailedFixesWindow,
+		replicaRebuildConcurrencyPerCell,
+		replicaRebuildTimeout,
+		enableDegradedPrimaryReparent,
+		degradedPrimaryMaintenanceTag,
+		degradedPrimaryDuration,
+		degradedPrimaryReparentCooldown,
 	)
 }
 
@@ -395,6 +557,101 @@ func GetStalledDiskPrimaryRecovery() bool {
 	return enablePrimaryDiskStalledRecovery.Get()
 }
 
+// GetPreRecoveryHooks is a getter function.
+func GetPreRecoveryHooks() []string {
+	return preRecoveryHooks.Get()
+}
+
+// SetPreRecoveryHooks is a setter function. This should only be used from tests.
+func SetPreRecoveryHooks(v []string) {
+	preRecoveryHooks.Set(v)
+}
+
+// GetPostRecoveryHooks is a getter function.
+func GetPostRecoveryHooks() []string {
+	return postRecoveryHooks.Get()
+}
+
+// SetPostRecoveryHooks is a setter function. This should only be used from tests.
+func SetPostRecoveryHooks(v []string) {
+	postRecoveryHooks.Set(v)
+}
+
+// GetRecoveryHooksTimeout is a getter function.
+func GetRecoveryHooksTimeout() time.Duration {
+	return recoveryHooksTimeout.Get()
+}
+
+// GetRecoveryHooksRetries is a getter function.
+func GetRecoveryHooksRetries() int {
+	return recoveryHooksRetries.Get()
+}
+
+// ReplicaRebuildEnabled reports whether VTOrc is allowed to rebuild replicas from a backup.
+func ReplicaRebuildEnabled() bool {
+	return enableReplicaRebuild.Get()
+}
+
+// SetReplicaRebuildEnabled sets the value for the enableReplicaRebuild variable. This should only be used from tests.
+func SetReplicaRebuildEnabled(val bool) {
+	enableReplicaRebuild.Set(val)
+}
+
+// GetReplicaRebuildFailedFixes is a getter function.
+func GetReplicaRebuildFailedFixes() int {
+	return replicaRebuildFailedFixes.Get()
+}
+
+// GetReplicaRebuildFailedFixesWindow is a getter function.
+func GetReplicaRebuildFailedFixesWindow() time.Duration {
+	return replicaRebuildFailedFixesWindow.Get()
+}
+
+// GetReplicaRebuildConcurrencyPerCell is a getter function.
+func GetReplicaRebuildConcurrencyPerCell() int {
+	return replicaRebuildConcurrencyPerCell.Get()
+}
+
+// SetReplicaRebuildConcurrencyPerCell is a setter function. This should only be used from tests.
+func SetReplicaRebuildConcurrencyPerCell(val int) {
+	replicaRebuildConcurrencyPerCell.Set(val)
+}
+
+// GetReplicaRebuildTimeout is a getter function.
+func GetReplicaRebuildTimeout() time.Duration {
+	return replicaRebuildTimeout.Get()
+}
+
+// DegradedPrimaryReparentEnabled reports whether VTOrc is allowed to reparent away from degraded primaries.
+func DegradedPrimaryReparentEnabled() bool {
+	return enableDegradedPrimaryReparent.Get()
+}
+
+// SetDegradedPrimaryReparentEnabled sets the value for the enableDegradedPrimaryReparent variable. This should only be used from tests.
+func SetDegradedPrimaryReparentEnabled(val bool) {
+	enableDegradedPrimaryReparent.Set(val)
+}
+
+// GetDegradedPrimaryMaintenanceTag is a getter function.
+func GetDegradedPrimaryMaintenanceTag() string {
+	return degradedPrimaryMaintenanceTag.Get()
+}
+
+// GetDegradedPrimaryDuration is a getter function.
+func GetDegradedPrimaryDuration() time.Duration {
+	return degradedPrimaryDuration.Get()
+}
+
+// SetDegradedPrimaryDuration is a setter function. This should only be used from tests.
+func SetDegradedPrimaryDuration(val time.Duration) {
+	degradedPrimaryDuration.Set(val)
+}
+
+// GetDegradedPrimaryReparentCooldown is a getter function.
+func GetDegradedPrimaryReparentCooldown() time.Duration {
+	return degradedPrimaryReparentCooldown.Get()
+}
+
 // MarkConfigurationLoaded is called once configuration has first been loaded.
 // Listeners on ConfigurationLoaded will get a notification
 func MarkConfigurationLoaded() {
@@ -59,6 +59,7 @@ const (
 	PrimarySemiSyncBlocked                 AnalysisCode = "PrimarySemiSyncBlocked"
 	ErrantGTIDDetected                     AnalysisCode = "ErrantGTIDDetected"
 	PrimaryDiskStalled                     AnalysisCode = "PrimaryDiskStalled"
+	PrimaryDegraded                        AnalysisCode = "PrimaryDegraded"
 )
 
 type StructureAnalysisCode string
@@ -538,6 +538,9 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 			a.Analysis = AllPrimaryReplicasNotReplicatingOrDead
 			a.Description = "Primary is reachable but none of its replicas is replicating"
 			//
+		} else if degradation := getPrimaryDegradation(a, tablet); degradation != "" {
+			a.Analysis = PrimaryDegraded
+			a.Description = degradation
 		}
 		//		 else if a.IsPrimary && a.CountReplicas == 0 {
 		//			a.Analysis = PrimaryWithoutReplicas
@@ -585,8 +588,9 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 		}
 		appendAnalysis(a)
 
-		if a.CountReplicas > 0 && hints.AuditAnalysis {
-			// Interesting enough for analysis
+		if (a.CountReplicas > 0 || !a.IsPrimary) && hints.AuditAnalysis {
+			// Interesting enough for analysis. The analyses of the replicas
+			// tell how long their problems persisted, to rebuild them.
 			go func() {
 				_ = auditInstanceAnalysisInChangelog(a.AnalyzedInstanceAlias, a.Analysis)
 			}()
@@ -603,6 +607,25 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 	return result, err
 }
 
+// getPrimaryDegradation returns the reason why the analyzed primary is
+// degraded, so that it should be reparented away from while it is still
+// reachable, or an empty string if it isn't.
+func getPrimaryDegradation(a *ReplicationAnalysis, tablet *topodatapb.Tablet) string {
+	if !config.DegradedPrimaryReparentEnabled() || !a.IsClusterPrimary || !a.LastCheckValid || a.CountValidReplicatingReplicas == 0 {
+		return ""
+	}
+	if tag := config.GetDegradedPrimaryMaintenanceTag(); tag != "" && tablet.Tags[tag] == "true" {
+		return fmt.Sprintf("Primary is marked for maintenance with the %v tag", tag)
+	}
+	if a.SemiSyncPrimaryEnabled && !a.SemiSyncPrimaryStatus && a.SemiSyncPrimaryWaitForReplicaCount > 0 && a.CountSemiSyncReplicasEnabled >= a.SemiSyncPrimaryWaitForReplicaCount {
+		return "Semi-sync timed out on the primary, even though sufficient replicas are configured to send ACKs"
+	}
+	// The replication lag of the replicas isn't a degradation: the planned
+	// reparent waits for the new primary to catch up, so it can't run while
+	// all the replicas lag.
+	return ""
+}
+
 // postProcessAnalyses is used to update different analyses based on the information gleaned from looking at all the analyses together instead of individual data.
 func postProcessAnalyses(result []*ReplicationAnalysis, clusters map[string]*clusterAnalysis) []*ReplicationAnalysis {
 	for {
@@ -740,6 +763,28 @@ func auditInstanceAnalysisInChangelog(tabletAlias string, analysisCode AnalysisC
 	return err
 }
 
+// ReadAnalysisPersistedFor returns how long the tablet has had the given
+// analysis, according to the last analysis recorded for it, or 0 if its last
+// analysis is different.
+func ReadAnalysisPersistedFor(tabletAlias string, analysisCode AnalysisCode) (persistedFor time.Duration, err error) {
+	query := `SELECT
+			STRFTIME('%s', 'now') - STRFTIME('%s', analysis_timestamp) AS seconds_since_analysis
+		FROM
+			database_instance_last_analysis
+		WHERE
+			alias = ?
+			AND analysis = ?
+		`
+	err = db.QueryVTOrc(query, sqlutils.Args(tabletAlias, string(analysisCode)), func(m sqlutils.RowMap) error {
+		persistedFor = time.Duration(m.GetInt64("seconds_since_analysis")) * time.Second
+		return nil
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return persistedFor, err
+}
+
 // ExpireInstanceAnalysisChangelog removes old-enough analysis entries from the changelog
 func ExpireInstanceAnalysisChangelog() error {
 	_, err := db.ExecVTOrc(`DELETE
@@ -26,6 +26,7 @@ import (
 	"vitess.io/vitess/go/vt/external/golib/sqlutils"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
+	"vitess.io/vitess/go/vt/vtorc/config"
 	"vitess.io/vitess/go/vt/vtorc/db"
 	"vitess.io/vitess/go/vt/vtorc/test"
 )
@@ -993,6 +994,98 @@ func TestGetReplicationAnalysisDecision(t *testing.T) {
 	}
 }
 
+func TestGetReplicationAnalysisPrimaryDegraded(t *testing.T) {
+	oldEnabled := config.DegradedPrimaryReparentEnabled()
+	defer config.SetDegradedPrimaryReparentEnabled(oldEnabled)
+
+	healthyPrimary := func() *test.InfoForRecoveryAnalysis {
+		return &test.InfoForRecoveryAnalysis{
+			TabletInfo: &topodatapb.Tablet{
+				Alias:         &topodatapb.TabletAlias{Cell: "zon1", Uid: 101},
+				Hostname:      "localhost",
+				Keyspace:      "ks",
+				Shard:         "0",
+				Type:          topodatapb.TabletType_PRIMARY,
+				MysqlHostname: "localhost",
+				MysqlPort:     6708,
+			},
+			DurabilityPolicy:                   policy.DurabilitySemiSync,
+			LastCheckValid:                     1,
+			CountReplicas:                      4,
+			CountValidReplicas:                 4,
+			CountValidReplicatingReplicas:      4,
+			CountSemiSyncReplicasEnabled:       4,
+			IsPrimary:                          1,
+			SemiSyncPrimaryEnabled:             1,
+			SemiSyncPrimaryStatus:              1,
+			SemiSyncPrimaryWaitForReplicaCount: 1,
+			SemiSyncPrimaryClients:             4,
+			CurrentTabletType:                  int(topodatapb.TabletType_PRIMARY),
+		}
+	}
+
+	tests := []struct {
+		name            string
+		disabled        bool
+		update          func(info *test.InfoForRecoveryAnalysis)
+		codeWanted      AnalysisCode
+		descriptionWant string
+	}{
+		{
+			name:       "healthy primary",
+			update:     func(info *test.InfoForRecoveryAnalysis) {},
+			codeWanted: NoProblem,
+		}, {
+			name: "primary marked for maintenance",
+			update: func(info *test.InfoForRecoveryAnalysis) {
+				info.TabletInfo.Tags = map[string]string{"maintenance": "true"}
+			},
+			codeWanted:      PrimaryDegraded,
+			descriptionWant: "Primary is marked for maintenance with the maintenance tag",
+		}, {
+			name:     "degraded primary with the reparents disabled",
+			disabled: true,
+			update: func(info *test.InfoForRecoveryAnalysis) {
+				info.TabletInfo.Tags = map[string]string{"maintenance": "true"}
+			},
+			codeWanted: NoProblem,
+		}, {
+			name: "semi-sync timed out",
+			update: func(info *test.InfoForRecoveryAnalysis) {
+				info.SemiSyncPrimaryStatus = 0
+			},
+			codeWanted:      PrimaryDegraded,
+			descriptionWant: "Semi-sync timed out on the primary, even though sufficient replicas are configured to send ACKs",
+		}, {
+			name: "replicas lagging",
+			update: func(info *test.InfoForRecoveryAnalysis) {
+				info.CountLaggingReplicas = 4
+			},
+			codeWanted: NoProblem,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldDB := db.Db
+			defer func() {
+				db.Db = oldDB
+			}()
+			config.SetDegradedPrimaryReparentEnabled(!tt.disabled)
+
+			info := healthyPrimary()
+			tt.update(info)
+			info.SetValuesFromTabletInfo()
+			db.Db = test.NewTestDB([][]sqlutils.RowMap{{info.ConvertToRowMap()}})
+
+			got, err := GetReplicationAnalysis("", "", &ReplicationAnalysisHints{})
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			require.Equal(t, tt.codeWanted, got[0].Analysis)
+			require.Equal(t, tt.descriptionWant, got[0].Description)
+		})
+	}
+}
+
 // TestGetReplicationAnalysis tests the entire GetReplicationAnalysis. It inserts data into the database and runs the function.
 // The database is not faked. This is intended to give more test coverage. This test is more comprehensive but more expensive than TestGetReplicationAnalysisDecision.
 // This test is somewhere between a unit test, and an end-to-end test. It is specifically useful for testing situations which are hard to come by in end-to-end test, but require
@@ -1146,6 +1239,29 @@ func TestAuditInstanceAnalysisInChangelog(t *testing.T) {
 }
 
 // TestPostProcessAnalyses tests the functionality of the postProcessAnalyses function.
+func TestReadAnalysisPersistedFor(t *testing.T) {
+	defer func() {
+		db.ClearVTOrcDatabase()
+	}()
+
+	_, err := db.ExecVTOrc(`INSERT INTO database_instance_last_analysis (alias, analysis_timestamp, analysis) VALUES
+		('zone1-0000000100', DATETIME('now', '-10 MINUTE'), ?)`, string(PrimaryDegraded))
+	require.NoError(t, err)
+
+	persistedFor, err := ReadAnalysisPersistedFor("zone1-0000000100", PrimaryDegraded)
+	require.NoError(t, err)
+	require.InDelta(t, 10*time.Minute, persistedFor, float64(5*time.Second))
+
+	// The tablet's last analysis is different.
+	persistedFor, err = ReadAnalysisPersistedFor("zone1-0000000100", ReplicationStopped)
+	require.NoError(t, err)
+	require.Zero(t, persistedFor)
+
+	persistedFor, err = ReadAnalysisPersistedFor("zone1-0000000101", PrimaryDegraded)
+	require.NoError(t, err)
+	require.Zero(t, persistedFor)
+}
+
 func TestPostProcessAnalyses(t *testing.T) {
 	ks0 := ClusterInfo{
 		Keyspace: "ks",
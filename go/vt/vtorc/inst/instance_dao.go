@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -1234,3 +1235,71 @@ func GetDatabaseState() (string, error) {
 
 	return string(jsonData), nil
 }
+
+// columnNameRegexp matches the column names that LoadDatabaseState accepts.
+var columnNameRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// LoadDatabaseState replaces the content of the tables in a snapshot taken
+// by GetDatabaseState with the rows of the snapshot. The tables that the
+// snapshot doesn't contain are left untouched.
+func LoadDatabaseState(dbState []byte) error {
+	type tableState struct {
+		TableName string
+		// The values are pointers, so that the NULL values are kept.
+		Rows []map[string]*string
+	}
+
+	var tableStates []tableState
+	if err := json.Unmarshal(dbState, &tableStates); err != nil {
+		return fmt.Errorf("invalid database state: %w", err)
+	}
+	knownTables := make(map[string]bool, len(db.TableNames))
+	for _, tableName := range db.TableNames {
+		knownTables[tableName] = true
+	}
+
+	vtorcDb, err := db.OpenVTOrc()
+	if err != nil {
+		return err
+	}
+	tx, err := vtorcDb.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	for _, ts := range tableStates {
+		if !knownTables[ts.TableName] {
+			return fmt.Errorf("unknown table %v in database state", ts.TableName)
+		}
+		if _, err := tx.Exec("DELETE FROM " + ts.TableName); err != nil {
+			return err
+		}
+		for _, row := range ts.Rows {
+			columns := make([]string, 0, len(row))
+			for column := range row {
+				if !columnNameRegexp.MatchString(column) {
+					return fmt.Errorf("invalid column %v of table %v in database state", column, ts.TableName)
+				}
+				columns = append(columns, column)
+			}
+			// The columns are sorted, so that the statement doesn't depend on the order of the map.
+			sort.Strings(columns)
+			args := make([]any, 0, len(columns))
+			for _, column := range columns {
+				if value := row[column]; value != nil {
+					args = append(args, *value)
+				} else {
+					args = append(args, nil)
+				}
+			}
+			query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", ts.TableName,
+				strings.Join(columns, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+			if _, err := tx.Exec(query, args...); err != nil {
+				return fmt.Errorf("failed to load a row of table %v: %w", ts.TableName, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
@@ -840,6 +840,55 @@ func TestGetDatabaseState(t *testing.T) {
 	require.Contains(t, ds, `"alias": "zone1-0000000112"`)
 }
 
+func TestLoadDatabaseState(t *testing.T) {
+	// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
+	defer func() {
+		db.ClearVTOrcDatabase()
+	}()
+
+	for _, query := range initialSQL {
+		_, err := db.ExecVTOrc(query)
+		require.NoError(t, err)
+	}
+	ds, err := GetDatabaseState()
+	require.NoError(t, err)
+	instance, found, err := ReadInstance("zone1-0000000112")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	// Loading the snapshot into an empty database restores the same state.
+	db.ClearVTOrcDatabase()
+	_, found, err = ReadInstance("zone1-0000000112")
+	require.NoError(t, err)
+	require.False(t, found)
+	require.NoError(t, LoadDatabaseState([]byte(ds)))
+	loadedInstance, found, err := ReadInstance("zone1-0000000112")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, instance, loadedInstance)
+	loadedDs, err := GetDatabaseState()
+	require.NoError(t, err)
+	require.Equal(t, ds, loadedDs)
+
+	// The tables of the snapshot are replaced.
+	require.NoError(t, LoadDatabaseState([]byte(`[{"TableName": "vitess_tablet", "Rows": []}]`)))
+	_, found, err = ReadInstance("zone1-0000000112")
+	require.NoError(t, err)
+	require.False(t, found)
+	// The other tables are left untouched.
+	var instanceCount int
+	err = db.QueryVTOrc("SELECT COUNT(*) AS instance_count FROM database_instance", nil, func(row sqlutils.RowMap) error {
+		instanceCount = row.GetInt("instance_count")
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotZero(t, instanceCount)
+
+	require.ErrorContains(t, LoadDatabaseState([]byte(`[{"TableName": "unknown", "Rows": []}]`)), "unknown table unknown")
+	require.ErrorContains(t, LoadDatabaseState([]byte(`[{"TableName": "vitess_tablet", "Rows": [{"alias; DROP": "a"}]}]`)), "invalid column")
+	require.ErrorContains(t, LoadDatabaseState([]byte(`{`)), "invalid database state")
+}
+
 func TestExpireTableData(t *testing.T) {
 	oldVal := config.GetAuditPurgeDays()
 	config.SetAuditPurgeDays(10)
@@ -0,0 +1,119 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+// This file holds the routines to reparent away from degraded primaries.
+// This is an opt-in recovery, for primaries that are reachable but that
+// the analysis found degraded, for example because they are marked for
+// maintenance or because all their replicas lag. Unlike the recoveries of
+// dead primaries, it runs a PlannedReparentShard, which loses no writes.
+// It only runs once the primary has been degraded for a while, and at
+// most once per shard during the cooldown, so that a flapping primary
+// doesn't cause a series of reparents.
+
+import (
+	"context"
+	"fmt"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+)
+
+// shouldReparentDegradedPrimary returns true if the primary has been
+// degraded for long enough, and its shard wasn't reparented away from a
+// degraded primary during the cooldown.
+func shouldReparentDegradedPrimary(tabletAlias string) bool {
+	if !config.DegradedPrimaryReparentEnabled() {
+		return false
+	}
+	persistedFor, err := inst.ReadAnalysisPersistedFor(tabletAlias, inst.PrimaryDegraded)
+	if err != nil || persistedFor == 0 || persistedFor < config.GetDegradedPrimaryDuration() {
+		return false
+	}
+	tablet, err := inst.ReadTablet(tabletAlias)
+	if err != nil {
+		return false
+	}
+	count, err := CountRecentShardRecoveries(tablet.Keyspace, tablet.Shard, inst.PrimaryDegraded, config.GetDegradedPrimaryReparentCooldown())
+	if err != nil {
+		return false
+	}
+	return count == 0
+}
+
+// plannedReparentDegradedPrimary runs a PlannedReparentShard away from the
+// degraded primary.
+func plannedReparentDegradedPrimary(ctx context.Context, analysisEntry *inst.ReplicationAnalysis, logger *log.PrefixedLogger) (recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error) {
+	topologyRecovery, err = AttemptRecoveryRegistration(analysisEntry)
+	if topologyRecovery == nil || err != nil {
+		message := fmt.Sprintf("found an active or recent recovery on %+v. Will not issue another plannedReparentDegradedPrimary.", analysisEntry.AnalyzedInstanceAlias)
+		logger.Warning(message)
+		_ = AuditTopologyRecovery(topologyRecovery, message)
+		return false, nil, err
+	}
+	logger.Infof("Analysis: %v, will reparent away from degraded primary %+v: %v", analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias, analysisEntry.Description)
+
+	var promotedReplica *inst.Instance
+	// This has to be done in the end; whether successful or not, we should mark that the recovery is done.
+	// So that after the active period passes, we are able to run other recoveries.
+	defer func() {
+		_ = resolveRecovery(topologyRecovery, promotedReplica)
+	}()
+
+	analyzedTablet, err := inst.ReadTablet(analysisEntry.AnalyzedInstanceAlias)
+	if err != nil {
+		logger.Errorf("Failed to read instance %s, aborting recovery", analysisEntry.AnalyzedInstanceAlias)
+		return false, topologyRecovery, err
+	}
+	_ = AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("starting PlannedReparentShard away from degraded primary: %v", analysisEntry.Description))
+
+	// The reparent only proceeds if the degraded tablet is still the primary,
+	// and it picks a candidate that is reachable and whose lag is tolerable.
+	ev, err := reparentutil.NewPlannedReparenter(ts, tmc, logutil.NewCallbackLogger(func(event *logutilpb.Event) {
+		level := event.GetLevel()
+		value := event.GetValue()
+		// we only log the warnings and errors explicitly, everything gets logged as an information message anyways in auditing topology recovery
+		switch level {
+		case logutilpb.Level_WARNING:
+			logger.Warningf("PRS - %s", value)
+		case logutilpb.Level_ERROR:
+			logger.Errorf("PRS - %s", value)
+		}
+		_ = AuditTopologyRecovery(topologyRecovery, value)
+	})).ReparentShard(ctx,
+		analyzedTablet.Keyspace,
+		analyzedTablet.Shard,
+		reparentutil.PlannedReparentOptions{
+			AvoidPrimaryAlias:    analyzedTablet.Alias,
+			ExpectedPrimaryAlias: analyzedTablet.Alias,
+			WaitReplicasTimeout:  config.GetWaitReplicasTimeout(),
+			TolerableReplLag:     config.GetTolerableReplicationLag(),
+		},
+	)
+
+	if ev != nil && ev.NewPrimary != nil {
+		promotedReplica, _, _ = inst.ReadInstance(topoproto.TabletAliasString(ev.NewPrimary.Alias))
+	}
+	postPrsCompletion(topologyRecovery, analysisEntry, promotedReplica)
+	return true, topologyRecovery, err
+}
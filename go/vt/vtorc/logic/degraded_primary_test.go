@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestShouldReparentDegradedPrimary(t *testing.T) {
+	oldEnabled := config.DegradedPrimaryReparentEnabled()
+	oldDuration := config.GetDegradedPrimaryDuration()
+	defer func() {
+		config.SetDegradedPrimaryReparentEnabled(oldEnabled)
+		config.SetDegradedPrimaryDuration(oldDuration)
+		db.ClearVTOrcDatabase()
+	}()
+	config.SetDegradedPrimaryReparentEnabled(true)
+	config.SetDegradedPrimaryDuration(5 * time.Minute)
+
+	primaryAlias := "zone1-0000000100"
+	require.NoError(t, inst.SaveTablet(&topodatapb.Tablet{
+		Alias:         &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Hostname:      "localhost",
+		MysqlHostname: "localhost",
+		MysqlPort:     1200,
+		Keyspace:      "ks",
+		Shard:         "0",
+		Type:          topodatapb.TabletType_PRIMARY,
+	}))
+
+	// The primary hasn't been degraded yet.
+	require.False(t, shouldReparentDegradedPrimary(primaryAlias))
+	require.EqualValues(t, recoverGenericProblemFunc, getCheckAndRecoverFunctionCode(inst.PrimaryDegraded, primaryAlias))
+
+	// The primary has only been degraded for a minute.
+	_, err := db.ExecVTOrc(`INSERT INTO database_instance_last_analysis (alias, analysis_timestamp, analysis)
+		VALUES (?, DATETIME('now', '-1 MINUTE'), ?)`, primaryAlias, string(inst.PrimaryDegraded))
+	require.NoError(t, err)
+	require.False(t, shouldReparentDegradedPrimary(primaryAlias))
+
+	// The primary has been degraded for longer than the duration.
+	_, err = db.ExecVTOrc(`UPDATE database_instance_last_analysis SET analysis_timestamp = DATETIME('now', '-10 MINUTE') WHERE alias = ?`, primaryAlias)
+	require.NoError(t, err)
+	require.True(t, shouldReparentDegradedPrimary(primaryAlias))
+	require.EqualValues(t, plannedReparentDegradedPrimaryFunc, getCheckAndRecoverFunctionCode(inst.PrimaryDegraded, primaryAlias))
+
+	// The reparents away from degraded primaries are disabled.
+	config.SetDegradedPrimaryReparentEnabled(false)
+	require.False(t, shouldReparentDegradedPrimary(primaryAlias))
+	config.SetDegradedPrimaryReparentEnabled(true)
+
+	// The shard was reparented away from a degraded primary during the cooldown.
+	_, err = db.ExecVTOrc(`INSERT INTO topology_recovery (recovery_id, alias, start_recovery, analysis, keyspace, shard)
+		VALUES (1, 'zone1-0000000101', DATETIME('now', '-10 MINUTE'), ?, 'ks', '0')`, string(inst.PrimaryDegraded))
+	require.NoError(t, err)
+	require.False(t, shouldReparentDegradedPrimary(primaryAlias))
+	require.EqualValues(t, recoverGenericProblemFunc, getCheckAndRecoverFunctionCode(inst.PrimaryDegraded, primaryAlias))
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+// This file holds the routines to run the pre- and post-recovery hooks.
+// A hook is either a command, run with sh -c, or an http(s) URL to which
+// a webhook is posted. In both cases the hook receives a JSON description
+// of the recovery, on its stdin or as the request body. The pre-recovery
+// hooks can veto a recovery by failing, for example to fence application
+// writers before a failover, and the post-recovery hooks notify external
+// tooling of the outcome.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+const (
+	// PreRecoveryHookPhase is the phase of the hooks run before a recovery.
+	PreRecoveryHookPhase = "pre-recovery"
+	// PostRecoveryHookPhase is the phase of the hooks run after a recovery.
+	PostRecoveryHookPhase = "post-recovery"
+
+	// maxRecoveryHookOutputLength is the length of the hook output that is
+	// kept in the error of a failed hook.
+	maxRecoveryHookOutputLength = 1024
+)
+
+// recoveryHookRetryDelay is the time to wait before retrying a hook that failed.
+var recoveryHookRetryDelay = time.Second
+
+// recoveryHookWaitDelay is the time to wait for the output of a command
+// hook to be closed once it was killed.
+var recoveryHookWaitDelay = 100 * time.Millisecond
+
+// RecoveryHookPayload is the JSON description of a recovery that is given
+// to the recovery hooks.
+type RecoveryHookPayload struct {
+	// Phase is either pre-recovery or post-recovery.
+	Phase               string `json:"phase"`
+	AnalysisCode        string `json:"analysis_code"`
+	RecoveryName        string `json:"recovery_name"`
+	Keyspace            string `json:"keyspace"`
+	Shard               string `json:"shard"`
+	AnalyzedTabletAlias string `json:"analyzed_tablet_alias"`
+	// OldPrimaryAlias is the primary of the shard when the problem was analyzed.
+	OldPrimaryAlias string `json:"old_primary_alias,omitempty"`
+	// NewPrimaryAlias is the tablet that was promoted, for the post-recovery
+	// hooks of the recoveries that elect a new primary.
+	NewPrimaryAlias string `json:"new_primary_alias,omitempty"`
+	// Successful, Error and DurationSeconds are only set for the
+	// post-recovery hooks.
+	Successful      bool    `json:"successful"`
+	Error           string  `json:"error,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// newRecoveryHookPayload returns the payload of the hooks of the given
+// phase, for the recovery of the analysis entry.
+func newRecoveryHookPayload(phase string, analysisEntry *inst.ReplicationAnalysis, recoveryName string) *RecoveryHookPayload {
+	oldPrimaryAlias := analysisEntry.AnalyzedInstancePrimaryAlias
+	if analysisEntry.IsClusterPrimary {
+		oldPrimaryAlias = analysisEntry.AnalyzedInstanceAlias
+	}
+	return &RecoveryHookPayload{
+		Phase:               phase,
+		AnalysisCode:        string(analysisEntry.Analysis),
+		RecoveryName:        recoveryName,
+		Keyspace:            analysisEntry.AnalyzedKeyspace,
+		Shard:               analysisEntry.AnalyzedShard,
+		AnalyzedTabletAlias: analysisEntry.AnalyzedInstanceAlias,
+		OldPrimaryAlias:     oldPrimaryAlias,
+	}
+}
+
+// runPreRecoveryHooks runs the pre-recovery hooks in order. It returns the
+// error of the first hook that failed, which vetoes the recovery.
+func runPreRecoveryHooks(ctx context.Context, payload *RecoveryHookPayload) error {
+	hooks := config.GetPreRecoveryHooks()
+	if len(hooks) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if err := runRecoveryHook(ctx, hook, data, config.GetRecoveryHooksTimeout(), config.GetRecoveryHooksRetries()); err != nil {
+			return fmt.Errorf("pre-recovery hook %v failed: %w", hook, err)
+		}
+	}
+	return nil
+}
+
+// runPostRecoveryHooks runs all the post-recovery hooks in order, and logs
+// the ones that failed.
+func runPostRecoveryHooks(ctx context.Context, payload *RecoveryHookPayload) {
+	hooks := config.GetPostRecoveryHooks()
+	if len(hooks) == 0 {
+		return
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Errorf("Failed to marshal the post-recovery hook payload: %v", err)
+		return
+	}
+	for _, hook := range hooks {
+		if err := runRecoveryHook(ctx, hook, data, config.GetRecoveryHooksTimeout(), config.GetRecoveryHooksRetries()); err != nil {
+			log.Errorf("Post-recovery hook %v failed: %v", hook, err)
+			recoveryHookFailuresCounter.Add(PostRecoveryHookPhase, 1)
+		}
+	}
+}
+
+// isWebhook returns true if the hook is an http(s) URL rather than a command.
+func isWebhook(hook string) bool {
+	return strings.HasPrefix(hook, "http://") || strings.HasPrefix(hook, "https://")
+}
+
+// runRecoveryHook runs a hook with the payload, retrying it up to retries
+// times if it fails. Every attempt is limited by the timeout.
+func runRecoveryHook(ctx context.Context, hook string, payload []byte, timeout time.Duration, retries int) error {
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%w, after: %w", ctx.Err(), err)
+			case <-time.After(recoveryHookRetryDelay):
+			}
+		}
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		if isWebhook(hook) {
+			err = postRecoveryWebhook(attemptCtx, hook, payload)
+		} else {
+			err = execRecoveryHookCommand(attemptCtx, hook, payload)
+		}
+		cancel()
+		if err == nil {
+			return nil
+		}
+		log.Warningf("Attempt %d of recovery hook %v failed: %v", attempt+1, hook, err)
+	}
+	return err
+}
+
+// execRecoveryHookCommand runs the command with the payload on its stdin. It
+// fails if the command exits with a non-zero status.
+func execRecoveryHookCommand(ctx context.Context, command string, payload []byte) error {
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(payload)
+	// The children of the shell can keep its output open after it is killed.
+	cmd.WaitDelay = recoveryHookWaitDelay
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%w: %s", err, truncateRecoveryHookOutput(output))
+	}
+	return nil
+}
+
+// postRecoveryWebhook posts the payload to the URL. It fails unless the
+// response has a 2xx status.
+func postRecoveryWebhook(ctx context.Context, url string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxRecoveryHookOutputLength))
+		return fmt.Errorf("webhook returned %v: %s", resp.Status, body)
+	}
+	return nil
+}
+
+// truncateRecoveryHookOutput returns the output of a hook, truncated to
+// maxRecoveryHookOutputLength.
+func truncateRecoveryHookOutput(output []byte) string {
+	if len(output) > maxRecoveryHookOutputLength {
+		output = output[:maxRecoveryHookOutputLength]
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+func TestNewRecoveryHookPayload(t *testing.T) {
+	payload := newRecoveryHookPayload(PreRecoveryHookPhase, &inst.ReplicationAnalysis{
+		AnalyzedInstanceAlias: "zone1-0000000100",
+		AnalyzedKeyspace:      "ks",
+		AnalyzedShard:         "0",
+		Analysis:              inst.DeadPrimary,
+		IsClusterPrimary:      true,
+	}, RecoverDeadPrimaryRecoveryName)
+	require.Equal(t, &RecoveryHookPayload{
+		Phase:               PreRecoveryHookPhase,
+		AnalysisCode:        "DeadPrimary",
+		RecoveryName:        RecoverDeadPrimaryRecoveryName,
+		Keyspace:            "ks",
+		Shard:               "0",
+		AnalyzedTabletAlias: "zone1-0000000100",
+		OldPrimaryAlias:     "zone1-0000000100",
+	}, payload)
+
+	payload = newRecoveryHookPayload(PreRecoveryHookPhase, &inst.ReplicationAnalysis{
+		AnalyzedInstanceAlias:        "zone1-0000000101",
+		AnalyzedInstancePrimaryAlias: "zone1-0000000100",
+		Analysis:                     inst.ReplicationStopped,
+	}, FixReplicaRecoveryName)
+	require.Equal(t, "zone1-0000000100", payload.OldPrimaryAlias)
+}
+
+func TestRunPreRecoveryHooks(t *testing.T) {
+	oldRetryDelay := recoveryHookRetryDelay
+	defer func() {
+		recoveryHookRetryDelay = oldRetryDelay
+		config.SetPreRecoveryHooks(nil)
+	}()
+	recoveryHookRetryDelay = time.Millisecond
+
+	payload := &RecoveryHookPayload{
+		Phase:        PreRecoveryHookPhase,
+		AnalysisCode: "DeadPrimary",
+		Keyspace:     "ks",
+		Shard:        "0",
+	}
+
+	// Without hooks, nothing vetoes the recovery.
+	require.NoError(t, runPreRecoveryHooks(context.Background(), payload))
+
+	// A command receives the payload on its stdin.
+	payloadFile := filepath.Join(t.TempDir(), "payload.json")
+	config.SetPreRecoveryHooks([]string{"cat > " + payloadFile})
+	require.NoError(t, runPreRecoveryHooks(context.Background(), payload))
+	data, err := os.ReadFile(payloadFile)
+	require.NoError(t, err)
+	var got RecoveryHookPayload
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Equal(t, *payload, got)
+
+	// A command that fails vetoes the recovery.
+	config.SetPreRecoveryHooks([]string{"true", "echo fencing failed; exit 1"})
+	err = runPreRecoveryHooks(context.Background(), payload)
+	require.ErrorContains(t, err, "fencing failed")
+}
+
+func TestRunRecoveryHookWebhook(t *testing.T) {
+	oldRetryDelay := recoveryHookRetryDelay
+	defer func() {
+		recoveryHookRetryDelay = oldRetryDelay
+	}()
+	recoveryHookRetryDelay = time.Millisecond
+
+	var calls atomic.Int32
+	var body atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body.Store(string(data))
+		// The first call fails, so that the hook is retried.
+		if calls.Add(1) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	payload := []byte(`{"phase":"post-recovery"}`)
+	require.NoError(t, runRecoveryHook(context.Background(), server.URL, payload, time.Second, 1))
+	require.EqualValues(t, 2, calls.Load())
+	require.Equal(t, string(payload), body.Load())
+
+	// Without retries the first failure is returned.
+	calls.Store(0)
+	err := runRecoveryHook(context.Background(), server.URL, payload, time.Second, 0)
+	require.ErrorContains(t, err, "503")
+
+	// An attempt that takes longer than the timeout fails.
+	err = runRecoveryHook(context.Background(), "sleep 5", payload, 10*time.Millisecond, 0)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+// This file holds the routines to manage the recovery silences. Unlike
+// the global recovery disable, which only applies to this VTOrc, the
+// silences are stored in the global topo so that all the VTOrc instances
+// share them. They prevent the recoveries of a keyspace, a shard or a
+// tablet, optionally only for some analysis codes, during a window of
+// time, so that maintenance on a shard doesn't require disabling the
+// recoveries of the whole fleet.
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// ReadRecoverySilences returns the recovery silences that haven't expired.
+func ReadRecoverySilences() ([]*topodatapb.RecoverySilence, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+	return ts.GetRecoverySilences(ctx)
+}
+
+// RecoverySilenceStatus is a recovery silence, formatted to be displayed
+// in the status page.
+type RecoverySilenceStatus struct {
+	ID            string
+	Keyspace      string
+	Shard         string
+	TabletAlias   string
+	AnalysisCodes string
+	StartTime     string
+	ExpireTime    string
+	Reason        string
+}
+
+// ReadRecoverySilencesStatus returns the recovery silences that haven't
+// expired, formatted to be displayed with RecoverySilencesTemplate.
+func ReadRecoverySilencesStatus() ([]RecoverySilenceStatus, error) {
+	silences, err := ReadRecoverySilences()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]RecoverySilenceStatus, 0, len(silences))
+	for _, silence := range silences {
+		status := RecoverySilenceStatus{
+			ID:            silence.Id,
+			Keyspace:      silence.Keyspace,
+			Shard:         silence.Shard,
+			AnalysisCodes: strings.Join(silence.AnalysisCodes, ", "),
+			StartTime:     protoutil.TimeFromProto(silence.StartTime).UTC().Format(time.RFC3339),
+			ExpireTime:    protoutil.TimeFromProto(silence.ExpireTime).UTC().Format(time.RFC3339),
+			Reason:        silence.Reason,
+		}
+		if silence.TabletAlias != nil {
+			status.TabletAlias = topoproto.TabletAliasString(silence.TabletAlias)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// AddRecoverySilence adds a recovery silence, and returns it with its id.
+func AddRecoverySilence(silence *topodatapb.RecoverySilence) (*topodatapb.RecoverySilence, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+	return ts.AddRecoverySilence(ctx, silence)
+}
+
+// RemoveRecoverySilence removes the recovery silence with the given id.
+func RemoveRecoverySilence(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+	return ts.RemoveRecoverySilence(ctx, id)
+}
+
+// getActiveRecoverySilence returns the active recovery silence that
+// applies to the analysis entry, nil if there is none.
+func getActiveRecoverySilence(analysisEntry *inst.ReplicationAnalysis) (*topodatapb.RecoverySilence, error) {
+	silences, err := ReadRecoverySilences()
+	if err != nil {
+		return nil, err
+	}
+	// The analyzed instance alias can be empty, for example when the
+	// primary tablet of the shard was deleted.
+	var tabletAlias *topodatapb.TabletAlias
+	if analysisEntry.AnalyzedInstanceAlias != "" {
+		if tabletAlias, err = topoproto.ParseTabletAlias(analysisEntry.AnalyzedInstanceAlias); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	for _, silence := range silences {
+		if topo.IsRecoverySilenceActive(silence, now) &&
+			topo.RecoverySilenceMatches(silence, analysisEntry.AnalyzedKeyspace, analysisEntry.AnalyzedShard, tabletAlias, string(analysisEntry.Analysis)) {
+			return silence, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestGetActiveRecoverySilence(t *testing.T) {
+	oldTs := ts
+	defer func() {
+		ts = oldTs
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts = memorytopo.NewServer(ctx, "zone1")
+
+	now := time.Now()
+	shardSilence, err := AddRecoverySilence(&topodatapb.RecoverySilence{
+		Keyspace:      "ks",
+		Shard:         "-80",
+		AnalysisCodes: []string{string(inst.ReplicationStopped)},
+		ExpireTime:    protoutil.TimeToProto(now.Add(time.Hour)),
+		Reason:        "maintenance",
+	})
+	require.NoError(t, err)
+	_, err = AddRecoverySilence(&topodatapb.RecoverySilence{
+		TabletAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+		StartTime:   protoutil.TimeToProto(now.Add(time.Hour)),
+		ExpireTime:  protoutil.TimeToProto(now.Add(2 * time.Hour)),
+		Reason:      "scheduled maintenance",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		analysisEntry *inst.ReplicationAnalysis
+		wantSilence   *topodatapb.RecoverySilence
+	}{
+		{
+			name: "silenced analysis code on the shard",
+			analysisEntry: &inst.ReplicationAnalysis{
+				AnalyzedInstanceAlias: "zone1-0000000100",
+				AnalyzedKeyspace:      "ks",
+				AnalyzedShard:         "-80",
+				Analysis:              inst.ReplicationStopped,
+			},
+			wantSilence: shardSilence,
+		}, {
+			name: "other analysis code on the shard",
+			analysisEntry: &inst.ReplicationAnalysis{
+				AnalyzedInstanceAlias: "zone1-0000000100",
+				AnalyzedKeyspace:      "ks",
+				AnalyzedShard:         "-80",
+				Analysis:              inst.DeadPrimary,
+			},
+		}, {
+			name: "other shard",
+			analysisEntry: &inst.ReplicationAnalysis{
+				AnalyzedInstanceAlias: "zone1-0000000100",
+				AnalyzedKeyspace:      "ks",
+				AnalyzedShard:         "80-",
+				Analysis:              inst.ReplicationStopped,
+			},
+		}, {
+			name: "tablet silence that hasn't started",
+			analysisEntry: &inst.ReplicationAnalysis{
+				AnalyzedInstanceAlias: "zone1-0000000200",
+				AnalyzedKeyspace:      "ks",
+				AnalyzedShard:         "80-",
+				Analysis:              inst.ReplicationStopped,
+			},
+		}, {
+			name: "no analyzed instance",
+			analysisEntry: &inst.ReplicationAnalysis{
+				AnalyzedKeyspace: "ks",
+				AnalyzedShard:    "-80",
+				Analysis:         inst.ReplicationStopped,
+			},
+			wantSilence: shardSilence,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			silence, err := getActiveRecoverySilence(tt.analysisEntry)
+			require.NoError(t, err)
+			if tt.wantSilence == nil {
+				require.Nil(t, silence)
+				return
+			}
+			require.NotNil(t, silence)
+			require.Equal(t, tt.wantSilence.Id, silence.Id)
+		})
+	}
+
+	statuses, err := ReadRecoverySilencesStatus()
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	require.Equal(t, "ReplicationStopped", statuses[0].AnalysisCodes)
+	require.Equal(t, "zone1-0000000200", statuses[1].TabletAlias)
+}
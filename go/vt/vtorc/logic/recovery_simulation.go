@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+// This file holds the routines to simulate the recoveries. A simulation
+// runs the replication analysis on the data in the backend database, either
+// the live data of a running VTOrc or a snapshot loaded with
+// inst.LoadDatabaseState, and the recovery selection of CheckAndRecover,
+// and returns the recoveries that VTOrc would run. It neither locks shards
+// nor calls the tablets, nor does it run the recovery hooks, so that policy
+// and durability changes can be tested safely.
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+)
+
+// SimulatedRecovery is a recovery that VTOrc would run for a problem.
+type SimulatedRecovery struct {
+	AnalysisCode        string
+	Description         string
+	Keyspace            string
+	Shard               string
+	AnalyzedTabletAlias string
+	// RecoveryName is empty when VTOrc has no recovery for the problem.
+	RecoveryName string
+	// IsActionable is true for the recoveries that change the topology,
+	// rather than only detecting the problem.
+	IsActionable bool
+	// IsClusterWide is true for the recoveries that elect a new primary.
+	IsClusterWide bool
+	// SkipReason is the reason why VTOrc wouldn't run the recovery, if any.
+	SkipReason string
+}
+
+// SimulateRecoveries returns the recoveries that VTOrc would run on the
+// data in its backend database. They are ordered by keyspace and shard,
+// with the cluster-wide recoveries of a shard first, since they change
+// the primary that the other recoveries of the shard depend on.
+func SimulateRecoveries() ([]*SimulatedRecovery, error) {
+	replicationAnalysis, err := inst.GetReplicationAnalysis("", "", &inst.ReplicationAnalysisHints{})
+	if err != nil {
+		return nil, err
+	}
+	recoveryDisabledGlobally, err := IsRecoveryDisabled()
+	if err != nil {
+		return nil, err
+	}
+
+	var simulatedRecoveries []*SimulatedRecovery
+	for _, analysisEntry := range replicationAnalysis {
+		if analysisEntry.Analysis == inst.NoProblem {
+			continue
+		}
+		checkAndRecoverFunctionCode := getCheckAndRecoverFunctionCode(analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias)
+		simulatedRecovery := &SimulatedRecovery{
+			AnalysisCode:        string(analysisEntry.Analysis),
+			Description:         analysisEntry.Description,
+			Keyspace:            analysisEntry.AnalyzedKeyspace,
+			Shard:               analysisEntry.AnalyzedShard,
+			AnalyzedTabletAlias: analysisEntry.AnalyzedInstanceAlias,
+			IsActionable:        hasActionableRecovery(checkAndRecoverFunctionCode),
+			IsClusterWide:       isClusterWideRecovery(checkAndRecoverFunctionCode),
+		}
+		switch {
+		case checkAndRecoverFunctionCode == noRecoveryFunc:
+			simulatedRecovery.SkipReason = "no recovery for the problem"
+		case recoveryDisabledGlobally:
+			simulatedRecovery.RecoveryName = getRecoverFunctionName(checkAndRecoverFunctionCode)
+			simulatedRecovery.SkipReason = "recoveries are disabled globally"
+		default:
+			simulatedRecovery.RecoveryName = getRecoverFunctionName(checkAndRecoverFunctionCode)
+			// The silences are stored in the topo server, which a VTOrc that
+			// replays a snapshot isn't connected to.
+			if ts == nil {
+				break
+			}
+			silence, err := getActiveRecoverySilence(analysisEntry)
+			if err != nil {
+				return nil, err
+			}
+			if silence != nil {
+				simulatedRecovery.SkipReason = fmt.Sprintf("silenced by %v until %v: %v",
+					silence.Id, protoutil.TimeFromProto(silence.ExpireTime).Format(time.RFC3339), silence.Reason)
+			}
+		}
+		simulatedRecoveries = append(simulatedRecoveries, simulatedRecovery)
+	}
+
+	sort.SliceStable(simulatedRecoveries, func(i, j int) bool {
+		a, b := simulatedRecoveries[i], simulatedRecoveries[j]
+		if a.Keyspace != b.Keyspace {
+			return a.Keyspace < b.Keyspace
+		}
+		if a.Shard != b.Shard {
+			return a.Shard < b.Shard
+		}
+		if a.IsClusterWide != b.IsClusterWide {
+			return a.IsClusterWide
+		}
+		if a.AnalyzedTabletAlias != b.AnalyzedTabletAlias {
+			return a.AnalyzedTabletAlias < b.AnalyzedTabletAlias
+		}
+		return a.AnalysisCode < b.AnalysisCode
+	})
+	return simulatedRecoveries, nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestSimulateRecoveries(t *testing.T) {
+	oldTs := ts
+	defer func() {
+		ts = oldTs
+		db.ClearVTOrcDatabase()
+	}()
+	ts = nil
+
+	keyspaceInfo := &topo.KeyspaceInfo{
+		Keyspace: &topodatapb.Keyspace{DurabilityPolicy: "none"},
+	}
+	keyspaceInfo.SetKeyspaceName("ks")
+	require.NoError(t, inst.SaveKeyspace(keyspaceInfo))
+	for _, shard := range []string{"-80", "80-"} {
+		require.NoError(t, inst.SaveShard(topo.NewShardInfo("ks", shard, &topodatapb.Shard{}, nil)))
+	}
+	for i, tabletType := range []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA} {
+		tablet := &topodatapb.Tablet{
+			Alias:         &topodatapb.TabletAlias{Cell: "zone1", Uid: uint32(100 + i)},
+			Hostname:      "localhost",
+			MysqlHostname: "localhost",
+			MysqlPort:     int32(1200 + i),
+			Keyspace:      "ks",
+			Shard:         "80-",
+			Type:          tabletType,
+		}
+		require.NoError(t, inst.SaveTablet(tablet))
+	}
+	require.NoError(t, inst.SaveTablet(&topodatapb.Tablet{
+		Alias:         &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+		Hostname:      "localhost",
+		MysqlHostname: "localhost",
+		MysqlPort:     1300,
+		Keyspace:      "ks",
+		Shard:         "-80",
+		Type:          topodatapb.TabletType_REPLICA,
+	}))
+
+	deadPrimary := &SimulatedRecovery{
+		AnalysisCode:        string(inst.DeadPrimary),
+		Description:         "VTOrc hasn't been able to reach the primary even once since restart/shutdown",
+		Keyspace:            "ks",
+		Shard:               "80-",
+		AnalyzedTabletAlias: "zone1-0000000101",
+		RecoveryName:        RecoverDeadPrimaryRecoveryName,
+		IsActionable:        true,
+		IsClusterWide:       true,
+	}
+	invalidReplica := &SimulatedRecovery{
+		AnalysisCode:        string(inst.InvalidReplica),
+		Description:         "VTOrc hasn't been able to reach the replica even once since restart/shutdown",
+		Keyspace:            "ks",
+		Shard:               "-80",
+		AnalyzedTabletAlias: "zone1-0000000200",
+		SkipReason:          "no recovery for the problem",
+	}
+	simulatedRecoveries, err := SimulateRecoveries()
+	require.NoError(t, err)
+	require.Equal(t, []*SimulatedRecovery{invalidReplica, deadPrimary}, simulatedRecoveries)
+
+	// A recovery silence skips the recoveries it matches.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts = memorytopo.NewServer(ctx, "zone1")
+	silence, err := AddRecoverySilence(&topodatapb.RecoverySilence{
+		Keyspace:   "ks",
+		Shard:      "80-",
+		ExpireTime: protoutil.TimeToProto(time.Now().Add(time.Hour)),
+		Reason:     "maintenance",
+	})
+	require.NoError(t, err)
+	simulatedRecoveries, err = SimulateRecoveries()
+	require.NoError(t, err)
+	require.Len(t, simulatedRecoveries, 2)
+	require.Contains(t, simulatedRecoveries[1].SkipReason, "silenced by "+silence.Id)
+
+	// So do the recoveries being disabled globally.
+	require.NoError(t, DisableRecovery())
+	defer func() {
+		require.NoError(t, EnableRecovery())
+	}()
+	simulatedRecoveries, err = SimulateRecoveries()
+	require.NoError(t, err)
+	require.Len(t, simulatedRecoveries, 2)
+	require.Equal(t, RecoverDeadPrimaryRecoveryName, simulatedRecoveries[1].RecoveryName)
+	require.Equal(t, "recoveries are disabled globally", simulatedRecoveries[1].SkipReason)
+}
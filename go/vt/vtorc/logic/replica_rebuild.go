@@ -0,0 +1,291 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+// This file holds the routines to rebuild a broken replica from a backup.
+// This is an opt-in recovery, for replicas with errant GTIDs and for
+// replicas whose replication problems fixReplica failed to resolve. The
+// replica is first changed to DRAINED, so that it stops serving and is no
+// longer analyzed, and the rest of the rebuild runs in the background so
+// that the shard isn't locked for the duration of the restore: the tablet
+// restores the latest backup, VTOrc waits for replication to catch up,
+// and the tablet is changed back to its original type. A rebuild that
+// fails leaves the tablet DRAINED for an operator to look at.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+var (
+	// replicaRebuildPollInterval is the interval at which the replication of
+	// a rebuilt replica is checked while it catches up.
+	replicaRebuildPollInterval = 5 * time.Second
+
+	// replicaRebuilds holds the replicas being rebuilt, by cell.
+	replicaRebuilds = newReplicaRebuildTracker()
+
+	replicaRebuildsInProgress = stats.NewGaugesFuncWithMultiLabels(
+		"ReplicaRebuildsInProgress",
+		"Number of replicas being rebuilt from a backup by cell",
+		[]string{"Cell"},
+		replicaRebuilds.countByCell,
+	)
+
+	// replicaRebuildsCounter counts the replica rebuilds that completed, by result.
+	replicaRebuildsCounter = stats.NewCountersWithSingleLabel("ReplicaRebuilds", "Count of the replica rebuilds from a backup that completed", "Result", "Success", "Failure")
+)
+
+// replicaRebuildTracker tracks the replicas being rebuilt, to limit the
+// number of concurrent rebuilds in each cell.
+type replicaRebuildTracker struct {
+	mu sync.Mutex
+	// tablets are the aliases of the tablets being rebuilt, by cell.
+	tablets map[string]map[string]bool
+}
+
+func newReplicaRebuildTracker() *replicaRebuildTracker {
+	return &replicaRebuildTracker{
+		tablets: make(map[string]map[string]bool),
+	}
+}
+
+// tryStart registers the rebuild of the tablet, and returns an error if
+// the tablet is already being rebuilt or if its cell has reached the
+// maximum number of concurrent rebuilds.
+func (t *replicaRebuildTracker) tryStart(alias *topodatapb.TabletAlias, limit int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	aliasStr := topoproto.TabletAliasString(alias)
+	cellTablets := t.tablets[alias.Cell]
+	if cellTablets[aliasStr] {
+		return fmt.Errorf("tablet %v is already being rebuilt", aliasStr)
+	}
+	if len(cellTablets) >= limit {
+		return fmt.Errorf("%d replicas are already being rebuilt in cell %v", len(cellTablets), alias.Cell)
+	}
+	if cellTablets == nil {
+		cellTablets = make(map[string]bool)
+		t.tablets[alias.Cell] = cellTablets
+	}
+	cellTablets[aliasStr] = true
+	return nil
+}
+
+// done unregisters the rebuild of the tablet.
+func (t *replicaRebuildTracker) done(alias *topodatapb.TabletAlias) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.tablets[alias.Cell], topoproto.TabletAliasString(alias))
+	if len(t.tablets[alias.Cell]) == 0 {
+		delete(t.tablets, alias.Cell)
+	}
+}
+
+// countByCell returns the number of replicas being rebuilt by cell in stats format.
+func (t *replicaRebuildTracker) countByCell() map[string]int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counts := make(map[string]int64, len(t.tablets))
+	for cell, tablets := range t.tablets {
+		counts[cell] = int64(len(tablets))
+	}
+	return counts
+}
+
+// hasFatalReplicationError returns true if the replication of the instance
+// stopped because of an error that restarting it can't fix: an error of the
+// SQL thread, which usually means that the data diverged from the primary,
+// or a fatal error of the IO thread, like the primary having purged the
+// binary logs that the replica needs.
+func hasFatalReplicationError(instance *inst.Instance) bool {
+	return instance.LastSQLError != "" || strings.Contains(strings.ToLower(instance.LastIOError), "fatal error")
+}
+
+// shouldRebuildReplica returns true if VTOrc should rebuild the tablet
+// from a backup rather than running the usual recovery of the analysis.
+func shouldRebuildReplica(analysisCode inst.AnalysisCode, tabletAlias string) bool {
+	if !config.ReplicaRebuildEnabled() {
+		return false
+	}
+	switch analysisCode {
+	case inst.ErrantGTIDDetected:
+		return true
+	case inst.ReplicationStopped:
+		instance, found, err := inst.ReadInstance(tabletAlias)
+		if err != nil || !found || !hasFatalReplicationError(instance) {
+			return false
+		}
+	case inst.ReplicaMisconfigured:
+	default:
+		return false
+	}
+	// The replica is only rebuilt once fixReplica failed to resolve the problem
+	// a number of times. fixReplica doesn't know whether it resolved the
+	// problem, so only the fixes that ran while the problem persisted, that is
+	// since it was last detected, are counted as failed.
+	persistedFor, err := inst.ReadAnalysisPersistedFor(tabletAlias, analysisCode)
+	if err != nil || persistedFor == 0 {
+		return false
+	}
+	count, err := CountRecentTabletRecoveries(tabletAlias, analysisCode, min(persistedFor, config.GetReplicaRebuildFailedFixesWindow()))
+	if err != nil {
+		return false
+	}
+	return count >= config.GetReplicaRebuildFailedFixes()
+}
+
+// rebuildReplicaFromBackup changes the type of the replica to DRAINED, and
+// rebuilds it from the latest backup in the background.
+func rebuildReplicaFromBackup(ctx context.Context, analysisEntry *inst.ReplicationAnalysis, logger *log.PrefixedLogger) (recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error) {
+	analyzedTablet, err := inst.ReadTablet(analysisEntry.AnalyzedInstanceAlias)
+	if err != nil {
+		return false, nil, err
+	}
+	if err := replicaRebuilds.tryStart(analyzedTablet.Alias, config.GetReplicaRebuildConcurrencyPerCell()); err != nil {
+		logger.Infof("Not rebuilding replica %v yet: %v", analysisEntry.AnalyzedInstanceAlias, err)
+		return false, nil, err
+	}
+	// Unless the rebuild is started in the background, the tablet isn't being rebuilt.
+	rebuildStarted := false
+	defer func() {
+		if !rebuildStarted {
+			replicaRebuilds.done(analyzedTablet.Alias)
+		}
+	}()
+
+	topologyRecovery, err = AttemptRecoveryRegistration(analysisEntry)
+	if topologyRecovery == nil {
+		message := fmt.Sprintf("found an active or recent recovery on %+v. Will not issue another rebuildReplicaFromBackup.", analysisEntry.AnalyzedInstanceAlias)
+		logger.Warning(message)
+		_ = AuditTopologyRecovery(topologyRecovery, message)
+		return false, nil, err
+	}
+	logger.Infof("Analysis: %v, will rebuild replica %+v from a backup", analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias)
+	// This has to be done in the end; whether successful or not, we should mark that the recovery is done.
+	// So that after the active period passes, we are able to run other recoveries.
+	defer func() {
+		_ = resolveRecovery(topologyRecovery, nil)
+	}()
+
+	primaryTablet, err := shardPrimary(analyzedTablet.Keyspace, analyzedTablet.Shard)
+	if err != nil {
+		logger.Infof("Could not compute primary for %v/%v", analyzedTablet.Keyspace, analyzedTablet.Shard)
+		return false, topologyRecovery, err
+	}
+
+	durabilityPolicy, err := inst.GetDurabilityPolicy(analyzedTablet.Keyspace)
+	if err != nil {
+		logger.Infof("Could not read the durability policy for %v/%v", analyzedTablet.Keyspace, analyzedTablet.Shard)
+		return false, topologyRecovery, err
+	}
+	semiSync := policy.IsReplicaSemiSync(durabilityPolicy, primaryTablet, analyzedTablet)
+
+	originalType := analyzedTablet.Type
+	if err = changeTabletType(ctx, analyzedTablet, topodatapb.TabletType_DRAINED, semiSync); err != nil {
+		return true, topologyRecovery, err
+	}
+	_ = AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("changed replica %v to DRAINED, rebuilding it from a backup", analysisEntry.AnalyzedInstanceAlias))
+
+	rebuildStarted = true
+	go func() {
+		defer replicaRebuilds.done(analyzedTablet.Alias)
+
+		rebuildCtx, cancel := context.WithTimeout(context.Background(), config.GetReplicaRebuildTimeout())
+		defer cancel()
+		if err := restoreReplicaAndWaitForCatchUp(rebuildCtx, analyzedTablet, originalType, semiSync, logger); err != nil {
+			logger.Errorf("Failed to rebuild replica %v from a backup, leaving it DRAINED: %v", analysisEntry.AnalyzedInstanceAlias, err)
+			replicaRebuildsCounter.Add("Failure", 1)
+			return
+		}
+		logger.Infof("Rebuilt replica %v from a backup, and changed it back to %v", analysisEntry.AnalyzedInstanceAlias, originalType)
+		replicaRebuildsCounter.Add("Success", 1)
+	}()
+	return true, topologyRecovery, nil
+}
+
+// restoreReplicaAndWaitForCatchUp restores the tablet from the latest
+// backup, waits for its replication to catch up, and changes it back to its
+// original type.
+func restoreReplicaAndWaitForCatchUp(ctx context.Context, tablet *topodatapb.Tablet, originalType topodatapb.TabletType, semiSync bool, logger *log.PrefixedLogger) error {
+	stream, err := tmc.RestoreFromBackup(ctx, tablet, &tabletmanagerdatapb.RestoreFromBackupRequest{})
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("restore failed: %w", err)
+		}
+		logger.Infof("Restore of %v: %v", topoproto.TabletAliasString(tablet.Alias), event.Value)
+	}
+
+	if err := waitForReplicationCatchUp(ctx, tablet); err != nil {
+		return err
+	}
+	return changeTabletType(ctx, tablet, originalType, semiSync)
+}
+
+// waitForReplicationCatchUp waits until both replication threads of the
+// tablet run, and its replication lag is reasonable.
+func waitForReplicationCatchUp(ctx context.Context, tablet *topodatapb.Tablet) error {
+	maxLag := uint32(config.GetReasonableReplicationLagSeconds())
+	ticker := time.NewTicker(replicaRebuildPollInterval)
+	defer ticker.Stop()
+	for {
+		tmcCtx, tmcCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+		status, err := tmc.ReplicationStatus(tmcCtx, tablet)
+		tmcCancel()
+		if err == nil {
+			replStatus := replication.ProtoToReplicationStatus(status)
+			if replStatus.Running() && !status.ReplicationLagUnknown && status.ReplicationLagSeconds <= maxLag {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("replication didn't catch up: %w, last error: %w", ctx.Err(), err)
+			}
+			return fmt.Errorf("replication didn't catch up: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
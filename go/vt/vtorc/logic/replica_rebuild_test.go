@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver/testutil"
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/db"
+	"vitess.io/vitess/go/vt/vtorc/inst"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestReplicaRebuildTracker(t *testing.T) {
+	tracker := newReplicaRebuildTracker()
+	tablet1 := &topodatapb.TabletAlias{Cell: "zone1", Uid: 101}
+	tablet2 := &topodatapb.TabletAlias{Cell: "zone1", Uid: 102}
+	tablet3 := &topodatapb.TabletAlias{Cell: "zone2", Uid: 201}
+
+	require.NoError(t, tracker.tryStart(tablet1, 1))
+	require.ErrorContains(t, tracker.tryStart(tablet1, 2), "already being rebuilt")
+	require.ErrorContains(t, tracker.tryStart(tablet2, 1), "1 replicas are already being rebuilt in cell zone1")
+	// The limit applies to each cell.
+	require.NoError(t, tracker.tryStart(tablet3, 1))
+	require.Equal(t, map[string]int64{"zone1": 1, "zone2": 1}, tracker.countByCell())
+
+	tracker.done(tablet1)
+	require.NoError(t, tracker.tryStart(tablet2, 1))
+	tracker.done(tablet2)
+	tracker.done(tablet3)
+	require.Empty(t, tracker.countByCell())
+}
+
+func TestHasFatalReplicationError(t *testing.T) {
+	require.False(t, hasFatalReplicationError(&inst.Instance{}))
+	require.True(t, hasFatalReplicationError(&inst.Instance{
+		LastSQLError: "Could not execute Write_rows event on table ks.t1; Duplicate entry '1' for key 'PRIMARY', Error_code: 1062",
+	}))
+	require.True(t, hasFatalReplicationError(&inst.Instance{
+		LastIOError: "Got fatal error 1236 from source when reading data from binary log",
+	}))
+	require.False(t, hasFatalReplicationError(&inst.Instance{
+		LastIOError: "error reconnecting to source 'vt_repl@localhost:100' - retry-time: 10 retries: 1",
+	}))
+}
+
+func TestShouldRebuildReplica(t *testing.T) {
+	orcDb, err := db.OpenVTOrc()
+	require.NoError(t, err)
+	defer func() {
+		_, err = orcDb.Exec("delete from topology_recovery")
+		require.NoError(t, err)
+		_, err = orcDb.Exec("delete from database_instance_last_analysis")
+		require.NoError(t, err)
+	}()
+	oldEnabled := config.ReplicaRebuildEnabled()
+	defer config.SetReplicaRebuildEnabled(oldEnabled)
+
+	tabletAlias := "zone1-0000000101"
+
+	// Rebuilding replicas is opt-in.
+	config.SetReplicaRebuildEnabled(false)
+	require.False(t, shouldRebuildReplica(inst.ErrantGTIDDetected, tabletAlias))
+
+	config.SetReplicaRebuildEnabled(true)
+	require.True(t, shouldRebuildReplica(inst.ErrantGTIDDetected, tabletAlias))
+	require.EqualValues(t, rebuildReplicaFromBackupFunc, getCheckAndRecoverFunctionCode(inst.ErrantGTIDDetected, tabletAlias))
+	require.False(t, shouldRebuildReplica(inst.ReplicaIsWritable, tabletAlias))
+	// Without a fatal replication error, a replica whose replication stopped is only fixed.
+	require.False(t, shouldRebuildReplica(inst.ReplicationStopped, tabletAlias))
+
+	// The replica is only rebuilt once enough fixes failed.
+	require.False(t, shouldRebuildReplica(inst.ReplicaMisconfigured, tabletAlias))
+	require.EqualValues(t, fixReplicaFunc, getCheckAndRecoverFunctionCode(inst.ReplicaMisconfigured, tabletAlias))
+	// Older fixes aren't counted.
+	_, err = orcDb.Exec(`insert into topology_recovery (alias, start_recovery, analysis, keyspace, shard) values
+		(?, DATETIME('now', '-1 DAY'), ?, ?, ?)`, tabletAlias, string(inst.ReplicaMisconfigured), keyspace, shard)
+	require.NoError(t, err)
+	for range config.GetReplicaRebuildFailedFixes() - 1 {
+		_, err = orcDb.Exec(`insert into topology_recovery (alias, start_recovery, analysis, keyspace, shard) values
+			(?, DATETIME('now', '-1 MINUTE'), ?, ?, ?)`, tabletAlias, string(inst.ReplicaMisconfigured), keyspace, shard)
+		require.NoError(t, err)
+	}
+	_, err = orcDb.Exec(`insert into database_instance_last_analysis (alias, analysis_timestamp, analysis) values
+		(?, DATETIME('now', '-1 HOUR'), ?)`, tabletAlias, string(inst.ReplicaMisconfigured))
+	require.NoError(t, err)
+	require.False(t, shouldRebuildReplica(inst.ReplicaMisconfigured, tabletAlias))
+	_, err = orcDb.Exec(`insert into topology_recovery (alias, start_recovery, analysis, keyspace, shard) values
+		(?, DATETIME('now'), ?, ?, ?)`, tabletAlias, string(inst.ReplicaMisconfigured), keyspace, shard)
+	require.NoError(t, err)
+	require.True(t, shouldRebuildReplica(inst.ReplicaMisconfigured, tabletAlias))
+	require.EqualValues(t, rebuildReplicaFromBackupFunc, getCheckAndRecoverFunctionCode(inst.ReplicaMisconfigured, tabletAlias))
+	// The fixes after which the problem was resolved aren't counted: here the
+	// problem was only detected again after the fixes of a minute ago.
+	_, err = orcDb.Exec(`update database_instance_last_analysis set analysis_timestamp = DATETIME('now', '-30 SECOND') where alias = ?`, tabletAlias)
+	require.NoError(t, err)
+	require.False(t, shouldRebuildReplica(inst.ReplicaMisconfigured, tabletAlias))
+	// The fixes of other tablets aren't counted.
+	require.False(t, shouldRebuildReplica(inst.ReplicaMisconfigured, "zone1-0000000102"))
+}
+
+func TestRestoreReplicaAndWaitForCatchUp(t *testing.T) {
+	oldTmc := tmc
+	oldPollInterval := replicaRebuildPollInterval
+	defer func() {
+		tmc = oldTmc
+		replicaRebuildPollInterval = oldPollInterval
+	}()
+	replicaRebuildPollInterval = 10 * time.Millisecond
+
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+		Type:  topodatapb.TabletType_DRAINED,
+	}
+	restoreResults := map[string]struct {
+		Events        []*logutilpb.Event
+		EventInterval time.Duration
+		EventJitter   time.Duration
+		ErrorAfter    time.Duration
+	}{
+		"zone1-0000000101": {
+			Events:        []*logutilpb.Event{{Value: "restoring"}, {Value: "restore done"}},
+			EventInterval: time.Millisecond,
+			EventJitter:   time.Millisecond,
+		},
+	}
+	logger := log.NewPrefixedLogger("test")
+
+	tmc = &testutil.TabletManagerClient{
+		RestoreFromBackupResults: restoreResults,
+		ReplicationStatusResults: map[string]struct {
+			Position *replicationdatapb.Status
+			Error    error
+		}{
+			"zone1-0000000101": {
+				Position: &replicationdatapb.Status{
+					IoState:               int32(replication.ReplicationStateRunning),
+					SqlState:              int32(replication.ReplicationStateRunning),
+					ReplicationLagSeconds: 1,
+				},
+			},
+		},
+		ChangeTabletTypeResult: map[string]error{
+			"zone1-0000000101": nil,
+		},
+	}
+	err := restoreReplicaAndWaitForCatchUp(context.Background(), tablet, topodatapb.TabletType_REPLICA, false, logger)
+	require.NoError(t, err)
+
+	// A replica that doesn't catch up before the timeout isn't changed back.
+	tmc = &testutil.TabletManagerClient{
+		RestoreFromBackupResults: restoreResults,
+		ReplicationStatusResults: map[string]struct {
+			Position *replicationdatapb.Status
+			Error    error
+		}{
+			"zone1-0000000101": {
+				Position: &replicationdatapb.Status{
+					IoState:               int32(replication.ReplicationStateRunning),
+					SqlState:              int32(replication.ReplicationStateRunning),
+					ReplicationLagSeconds: 3600,
+				},
+			},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = restoreReplicaAndWaitForCatchUp(ctx, tablet, topodatapb.TabletType_REPLICA, false, logger)
+	require.ErrorContains(t, err, "replication didn't catch up")
+}
@@ -25,6 +25,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
@@ -48,6 +49,8 @@ const (
 	FixPrimaryRecoveryName                           string = "FixPrimary"
 	FixReplicaRecoveryName                           string = "FixReplica"
 	RecoverErrantGTIDDetectedName                    string = "RecoverErrantGTIDDetected"
+	RebuildReplicaFromBackupRecoveryName             string = "RebuildReplicaFromBackup"
+	PlannedReparentDegradedPrimaryRecoveryName       string = "PlannedReparentDegradedPrimary"
 )
 
 var (
@@ -57,6 +60,8 @@ var (
 		ElectNewPrimaryRecoveryName,
 		FixPrimaryRecoveryName,
 		FixReplicaRecoveryName,
+		RebuildReplicaFromBackupRecoveryName,
+		PlannedReparentDegradedPrimaryRecoveryName,
 	}
 
 	countPendingRecoveries = stats.NewGauge("PendingRecoveries", "Count of the number of pending recoveries")
@@ -84,6 +89,15 @@ var (
 	// recoveriesFailureCounter counts the number of failed recoveries that VTOrc has performed
 	recoveriesFailureCounter = stats.NewCountersWithSingleLabel("FailedRecoveries", "Count of the different failed recoveries performed", "RecoveryType", actionableRecoveriesNames...)
 
+	// recoveriesSilencedCounter counts the number of recoveries that VTOrc skipped because of a recovery silence
+	recoveriesSilencedCounter = stats.NewCountersWithSingleLabel("SilencedRecoveries", "Count of the different recoveries skipped because of a recovery silence", "RecoveryType", actionableRecoveriesNames...)
+
+	// recoveriesVetoedCounter counts the number of recoveries that a pre-recovery hook vetoed
+	recoveriesVetoedCounter = stats.NewCountersWithSingleLabel("VetoedRecoveries", "Count of the different recoveries vetoed by a pre-recovery hook", "RecoveryType", actionableRecoveriesNames...)
+
+	// recoveryHookFailuresCounter counts the number of recovery hooks that failed
+	recoveryHookFailuresCounter = stats.NewCountersWithSingleLabel("RecoveryHookFailures", "Count of the recovery hooks that failed", "Phase", PreRecoveryHookPhase, PostRecoveryHookPhase)
+
 	// shardLockTimings measures the timing of LockShard operations.
 	shardLockTimingsActions = []string{"Lock", "Unlock"}
 	shardLockTimings        = stats.NewTimings("ShardLockTimings", "Timings of global shard locks", "Action", shardLockTimingsActions...)
@@ -105,6 +119,8 @@ const (
 	fixPrimaryFunc
 	fixReplicaFunc
 	recoverErrantGTIDDetectedFunc
+	rebuildReplicaFromBackupFunc
+	plannedReparentDegradedPrimaryFunc
 )
 
 // TopologyRecovery represents an entry in the topology_recovery table
@@ -361,6 +377,9 @@ func getCheckAndRecoverFunctionCode(analysisCode inst.AnalysisCode, tabletAlias
 		}
 		return recoverPrimaryTabletDeletedFunc
 	case inst.ErrantGTIDDetected:
+		if shouldRebuildReplica(analysisCode, tabletAlias) {
+			return rebuildReplicaFromBackupFunc
+		}
 		if !config.ConvertTabletWithErrantGTIDs() {
 			log.Infof("VTOrc not configured to do anything on detecting errant GTIDs, skipping recovering %v", analysisCode)
 			return noRecoveryFunc
@@ -374,9 +393,19 @@ func getCheckAndRecoverFunctionCode(analysisCode inst.AnalysisCode, tabletAlias
 		return electNewPrimaryFunc
 	case inst.PrimaryIsReadOnly, inst.PrimarySemiSyncMustBeSet, inst.PrimarySemiSyncMustNotBeSet, inst.PrimaryCurrentTypeMismatch:
 		return fixPrimaryFunc
+	case inst.PrimaryDegraded:
+		// Until the primary has been degraded for long enough, the problem is only detected.
+		if shouldReparentDegradedPrimary(tabletAlias) {
+			return plannedReparentDegradedPrimaryFunc
+		}
+		return recoverGenericProblemFunc
 	// replica
 	case inst.NotConnectedToPrimary, inst.ConnectedToWrongPrimary, inst.ReplicationStopped, inst.ReplicaIsWritable,
 		inst.ReplicaSemiSyncMustBeSet, inst.ReplicaSemiSyncMustNotBeSet, inst.ReplicaMisconfigured:
+		// Replicas that fixReplica failed to fix can be rebuilt from a backup instead.
+		if shouldRebuildReplica(analysisCode, tabletAlias) {
+			return rebuildReplicaFromBackupFunc
+		}
 		return fixReplicaFunc
 	// primary, non actionable
 	case inst.DeadPrimaryAndReplicas:
@@ -421,6 +450,10 @@ func hasActionableRecovery(recoveryFunctionCode recoveryFunction) bool {
 		return true
 	case recoverErrantGTIDDetectedFunc:
 		return true
+	case rebuildReplicaFromBackupFunc:
+		return true
+	case plannedReparentDegradedPrimaryFunc:
+		return true
 	default:
 		return false
 	}
@@ -451,6 +484,10 @@ func getCheckAndRecoverFunction(recoveryFunctionCode recoveryFunction) (
 		return fixReplica
 	case recoverErrantGTIDDetectedFunc:
 		return recoverErrantGTIDDetected
+	case rebuildReplicaFromBackupFunc:
+		return rebuildReplicaFromBackup
+	case plannedReparentDegradedPrimaryFunc:
+		return plannedReparentDegradedPrimary
 	default:
 		return nil
 	}
@@ -480,6 +517,10 @@ func getRecoverFunctionName(recoveryFunctionCode recoveryFunction) string {
 		return FixReplicaRecoveryName
 	case recoverErrantGTIDDetectedFunc:
 		return RecoverErrantGTIDDetectedName
+	case rebuildReplicaFromBackupFunc:
+		return RebuildReplicaFromBackupRecoveryName
+	case plannedReparentDegradedPrimaryFunc:
+		return PlannedReparentDegradedPrimaryRecoveryName
 	default:
 		return ""
 	}
@@ -488,7 +529,7 @@ func getRecoverFunctionName(recoveryFunctionCode recoveryFunction) string {
 // isClusterWideRecovery returns whether the given recovery is a cluster-wide recovery or not
 func isClusterWideRecovery(recoveryFunctionCode recoveryFunction) bool {
 	switch recoveryFunctionCode {
-	case recoverDeadPrimaryFunc, electNewPrimaryFunc, recoverPrimaryTabletDeletedFunc:
+	case recoverDeadPrimaryFunc, electNewPrimaryFunc, recoverPrimaryTabletDeletedFunc, plannedReparentDegradedPrimaryFunc:
 		return true
 	default:
 		return false
@@ -551,6 +592,17 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 		return err
 	}
 
+	// Check for a recovery silence of the keyspace, the shard or the tablet
+	if silence, err := getActiveRecoverySilence(analysisEntry); err != nil {
+		// Unexpected. Shouldn't get this
+		logger.Errorf("Unable to determine if recovery is silenced, still attempting to recover: %v", err)
+	} else if silence != nil {
+		logger.Infof("CheckAndRecover: Tablet: %+v: NOT Recovering host (silenced by %v until %v: %v)",
+			analysisEntry.AnalyzedInstanceAlias, silence.Id, protoutil.TimeFromProto(silence.ExpireTime).Format(time.RFC3339), silence.Reason)
+		recoveriesSilencedCounter.Add(getRecoverFunctionName(checkAndRecoverFunctionCode), 1)
+		return nil
+	}
+
 	// We lock the shard here and then refresh the tablets information
 	ctx, unlock, err := LockShard(context.Background(), analysisEntry.AnalyzedKeyspace, analysisEntry.AnalyzedShard,
 		getLockAction(analysisEntry.AnalyzedInstanceAlias, analysisEntry.Analysis),
@@ -627,16 +679,27 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 		}
 	}
 
+	recoveryName := getRecoverFunctionName(checkAndRecoverFunctionCode)
+	// The pre-recovery hooks run after the problem is confirmed, and any of them can veto the recovery.
+	if isActionableRecovery {
+		if err = runPreRecoveryHooks(ctx, newRecoveryHookPayload(PreRecoveryHookPhase, analysisEntry, recoveryName)); err != nil {
+			logger.Errorf("Recovery vetoed: %v", err)
+			recoveryHookFailuresCounter.Add(PreRecoveryHookPhase, 1)
+			recoveriesVetoedCounter.Add(recoveryName, 1)
+			return err
+		}
+	}
+
 	// Actually attempt recovery:
 	if isActionableRecovery || util.ClearToLog("executeCheckAndRecoverFunction: recovery", analysisEntry.AnalyzedInstanceAlias) {
 		logger.Infof("executeCheckAndRecoverFunction: proceeding with recovery on %+v; isRecoverable?: %+v", analysisEntry.AnalyzedInstanceAlias, isActionableRecovery)
 	}
+	recoveryStart := time.Now()
 	recoveryAttempted, topologyRecovery, err := getCheckAndRecoverFunction(checkAndRecoverFunctionCode)(ctx, analysisEntry, logger)
 	if !recoveryAttempted {
 		logger.Errorf("Recovery not attempted: %+v", err)
 		return err
 	}
-	recoveryName := getRecoverFunctionName(checkAndRecoverFunctionCode)
 	recoveriesCounter.Add(recoveryName, 1)
 	if err != nil {
 		logger.Errorf("Failed to recover: %+v", err)
@@ -645,6 +708,17 @@ func executeCheckAndRecoverFunction(analysisEntry *inst.ReplicationAnalysis) (er
 		logger.Info("Recovery succeeded")
 		recoveriesSuccessfulCounter.Add(recoveryName, 1)
 	}
+	// The post-recovery hooks run in the background, so that they don't hold the shard lock.
+	postRecoveryPayload := newRecoveryHookPayload(PostRecoveryHookPhase, analysisEntry, recoveryName)
+	postRecoveryPayload.Successful = err == nil
+	postRecoveryPayload.DurationSeconds = time.Since(recoveryStart).Seconds()
+	if err != nil {
+		postRecoveryPayload.Error = err.Error()
+	}
+	if topologyRecovery != nil {
+		postRecoveryPayload.NewPrimaryAlias = topologyRecovery.SuccessorAlias
+	}
+	go runPostRecoveryHooks(context.Background(), postRecoveryPayload)
 	if topologyRecovery == nil {
 		logger.Error("Topology recovery is nil - recovery might have failed")
 		return err
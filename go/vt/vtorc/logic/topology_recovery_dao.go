@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"vitess.io/vitess/go/vt/external/golib/sqlutils"
 	"vitess.io/vitess/go/vt/log"
@@ -230,6 +231,51 @@ func ReadRecentRecoveries(page int) ([]*TopologyRecovery, error) {
 	return readRecoveries(whereClause, limit, args)
 }
 
+// CountRecentTabletRecoveries returns the number of recoveries of the given analysis
+// that were run on the tablet within the given duration.
+func CountRecentTabletRecoveries(tabletAlias string, analysis inst.AnalysisCode, within time.Duration) (count int, err error) {
+	query := `SELECT
+			COUNT(*) AS recovery_count
+		FROM
+			topology_recovery
+		WHERE
+			alias = ?
+			AND analysis = ?
+			AND start_recovery >= DATETIME('now', PRINTF('-%d SECOND', ?))
+		`
+	err = db.QueryVTOrc(query, sqlutils.Args(tabletAlias, string(analysis), int64(within/time.Second)), func(m sqlutils.RowMap) error {
+		count = m.GetInt("recovery_count")
+		return nil
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return count, err
+}
+
+// CountRecentShardRecoveries returns the number of recoveries of the given analysis
+// that were run on the shard within the given duration.
+func CountRecentShardRecoveries(keyspace string, shard string, analysis inst.AnalysisCode, within time.Duration) (count int, err error) {
+	query := `SELECT
+			COUNT(*) AS recovery_count
+		FROM
+			topology_recovery
+		WHERE
+			keyspace = ?
+			AND shard = ?
+			AND analysis = ?
+			AND start_recovery >= DATETIME('now', PRINTF('-%d SECOND', ?))
+		`
+	err = db.QueryVTOrc(query, sqlutils.Args(keyspace, shard, string(analysis), int64(within/time.Second)), func(m sqlutils.RowMap) error {
+		count = m.GetInt("recovery_count")
+		return nil
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return count, err
+}
+
 // writeTopologyRecoveryStep writes down a single step in a recovery process
 func writeTopologyRecoveryStep(topologyRecoveryStep *TopologyRecoveryStep) error {
 	sqlResult, err := db.ExecVTOrc(`INSERT OR IGNORE
@@ -48,3 +48,35 @@ const TopologyRecoveriesTemplate = `
   {{end}}
 </table>
 `
+
+// RecoverySilencesTemplate is the HTML to use to display the
+// recovery silences that haven't expired
+const RecoverySilencesTemplate = `
+<table>
+  <tr>
+    <th colspan="8">Recovery Silences</th>
+  </tr>
+  <tr>
+    <th>Silence ID</th>
+    <th>Keyspace</th>
+    <th>Shard</th>
+    <th>Tablet Alias</th>
+    <th>Analysis Codes</th>
+    <th>Start Time</th>
+    <th>Expire Time</th>
+    <th>Reason</th>
+  </tr>
+  {{range $i, $silence := .}}
+  <tr>
+    <td>{{$silence.ID}}</td>
+    <td>{{$silence.Keyspace}}</td>
+    <td>{{$silence.Shard}}</td>
+    <td>{{$silence.TabletAlias}}</td>
+    <td>{{$silence.AnalysisCodes}}</td>
+    <td>{{$silence.StartTime}}</td>
+    <td>{{$silence.ExpireTime}}</td>
+    <td>{{$silence.Reason}}</td>
+  </tr>
+  {{end}}
+</table>
+`
@@ -22,16 +22,24 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/viperutil/debug"
 	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtorc/collection"
 	"vitess.io/vitess/go/vt/vtorc/discovery"
 	"vitess.io/vitess/go/vt/vtorc/inst"
 	"vitess.io/vitess/go/vt/vtorc/logic"
 	"vitess.io/vitess/go/vt/vtorc/process"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 // vtorcAPI struct is created to implement the Handler interface to register
@@ -45,7 +53,11 @@ const (
 	errantGTIDsAPI                = "/api/errant-gtids"
 	disableGlobalRecoveriesAPI    = "/api/disable-global-recoveries"
 	enableGlobalRecoveriesAPI     = "/api/enable-global-recoveries"
+	recoverySilencesAPI           = "/api/recovery-silences"
+	addRecoverySilenceAPI         = "/api/add-recovery-silence"
+	removeRecoverySilenceAPI      = "/api/remove-recovery-silence"
 	replicationAnalysisAPI        = "/api/replication-analysis"
+	simulateRecoveriesAPI         = "/api/simulate-recoveries"
 	databaseStateAPI              = "/api/database-state"
 	configAPI                     = "/api/config"
 	healthAPI                     = "/debug/health"
@@ -53,6 +65,7 @@ const (
 
 	shardWithoutKeyspaceFilteringErrorStr = "Filtering by shard without keyspace isn't supported"
 	notAValidValueForSeconds              = "Invalid value for seconds"
+	recoverySilenceIDRequiredErrorStr     = "A recovery silence id is required"
 )
 
 var (
@@ -62,7 +75,11 @@ var (
 		errantGTIDsAPI,
 		disableGlobalRecoveriesAPI,
 		enableGlobalRecoveriesAPI,
+		recoverySilencesAPI,
+		addRecoverySilenceAPI,
+		removeRecoverySilenceAPI,
 		replicationAnalysisAPI,
+		simulateRecoveriesAPI,
 		databaseStateAPI,
 		configAPI,
 		healthAPI,
@@ -83,6 +100,12 @@ func (v *vtorcAPI) ServeHTTP(response http.ResponseWriter, request *http.Request
 		disableGlobalRecoveriesAPIHandler(response)
 	case enableGlobalRecoveriesAPI:
 		enableGlobalRecoveriesAPIHandler(response)
+	case recoverySilencesAPI:
+		recoverySilencesAPIHandler(response)
+	case addRecoverySilenceAPI:
+		addRecoverySilenceAPIHandler(response, request)
+	case removeRecoverySilenceAPI:
+		removeRecoverySilenceAPIHandler(response, request)
 	case healthAPI:
 		healthAPIHandler(response, request)
 	case problemsAPI:
@@ -91,6 +114,8 @@ func (v *vtorcAPI) ServeHTTP(response http.ResponseWriter, request *http.Request
 		errantGTIDsAPIHandler(response, request)
 	case replicationAnalysisAPI:
 		replicationAnalysisAPIHandler(response, request)
+	case simulateRecoveriesAPI:
+		simulateRecoveriesAPIHandler(response)
 	case databaseStateAPI:
 		databaseStateAPIHandler(response)
 	case configAPI:
@@ -111,7 +136,9 @@ func getACLPermissionLevelForAPI(apiEndpoint string) string {
 		return acl.MONITORING
 	case disableGlobalRecoveriesAPI, enableGlobalRecoveriesAPI:
 		return acl.ADMIN
-	case replicationAnalysisAPI, configAPI:
+	case addRecoverySilenceAPI, removeRecoverySilenceAPI:
+		return acl.ADMIN
+	case replicationAnalysisAPI, simulateRecoveriesAPI, configAPI, recoverySilencesAPI:
 		return acl.MONITORING
 	case healthAPI, databaseStateAPI:
 		return acl.MONITORING
@@ -241,6 +268,84 @@ func enableGlobalRecoveriesAPIHandler(response http.ResponseWriter) {
 	writePlainTextResponse(response, "Global recoveries enabled", http.StatusOK)
 }
 
+// recoverySilencesAPIHandler is the handler for the recoverySilencesAPI endpoint
+func recoverySilencesAPIHandler(response http.ResponseWriter) {
+	silences, err := logic.ReadRecoverySilences()
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	returnAsJSON(response, http.StatusOK, silences)
+}
+
+// addRecoverySilenceAPIHandler is the handler for the addRecoverySilenceAPI endpoint
+func addRecoverySilenceAPIHandler(response http.ResponseWriter, request *http.Request) {
+	// The silence applies to the tablets that match all of the keyspace, shard and tablet provided.
+	query := request.URL.Query()
+	silence := &topodatapb.RecoverySilence{
+		Keyspace: query.Get("keyspace"),
+		Shard:    query.Get("shard"),
+		Reason:   query.Get("reason"),
+	}
+	if tablet := query.Get("tablet"); tablet != "" {
+		alias, err := topoproto.ParseTabletAlias(tablet)
+		if err != nil {
+			http.Error(response, err.Error(), http.StatusBadRequest)
+			return
+		}
+		silence.TabletAlias = alias
+	}
+	if codes := query.Get("analysis-codes"); codes != "" {
+		silence.AnalysisCodes = strings.Split(codes, ",")
+	}
+	start := time.Now()
+	if qStart := query.Get("start-time"); qStart != "" {
+		var err error
+		start, err = time.Parse(time.RFC3339, qStart)
+		if err != nil {
+			http.Error(response, fmt.Sprintf("Invalid value for start-time: %v", err), http.StatusBadRequest)
+			return
+		}
+		silence.StartTime = protoutil.TimeToProto(start)
+	}
+	duration, err := time.ParseDuration(query.Get("duration"))
+	if err != nil || duration <= 0 {
+		http.Error(response, "A positive duration is required", http.StatusBadRequest)
+		return
+	}
+	silence.ExpireTime = protoutil.TimeToProto(start.Add(duration))
+
+	silence, err = logic.AddRecoverySilence(silence)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if vterrors.Code(err) == vtrpcpb.Code_INVALID_ARGUMENT {
+			code = http.StatusBadRequest
+		}
+		http.Error(response, err.Error(), code)
+		return
+	}
+	returnAsJSON(response, http.StatusOK, silence)
+}
+
+// removeRecoverySilenceAPIHandler is the handler for the removeRecoverySilenceAPI endpoint
+func removeRecoverySilenceAPIHandler(response http.ResponseWriter, request *http.Request) {
+	id := request.URL.Query().Get("id")
+	if id == "" {
+		http.Error(response, recoverySilenceIDRequiredErrorStr, http.StatusBadRequest)
+		return
+	}
+	err := logic.RemoveRecoverySilence(id)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if topo.IsErrType(err, topo.NoNode) {
+			code = http.StatusNotFound
+		}
+		http.Error(response, err.Error(), code)
+		return
+	}
+	writePlainTextResponse(response, "Recovery silence removed", http.StatusOK)
+}
+
 // replicationAnalysisAPIHandler is the handler for the replicationAnalysisAPI endpoint
 func replicationAnalysisAPIHandler(response http.ResponseWriter, request *http.Request) {
 	// This api also supports filtering by shard and keyspace provided.
@@ -261,6 +366,16 @@ func replicationAnalysisAPIHandler(response http.ResponseWriter, request *http.R
 	returnAsJSON(response, http.StatusOK, analysis)
 }
 
+// simulateRecoveriesAPIHandler is the handler for the simulateRecoveriesAPI endpoint
+func simulateRecoveriesAPIHandler(response http.ResponseWriter) {
+	simulatedRecoveries, err := logic.SimulateRecoveries()
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	returnAsJSON(response, http.StatusOK, simulatedRecoveries)
+}
+
 // healthAPIHandler is the handler for the healthAPI endpoint
 func healthAPIHandler(response http.ResponseWriter, request *http.Request) {
 	health, discoveredOnce := process.HealthTest()
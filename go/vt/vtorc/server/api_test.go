@@ -25,9 +25,21 @@ func TestGetACLPermissionLevelForAPI(t *testing.T) {
 		}, {
 			apiEndpoint: enableGlobalRecoveriesAPI,
 			want:        acl.ADMIN,
+		}, {
+			apiEndpoint: recoverySilencesAPI,
+			want:        acl.MONITORING,
+		}, {
+			apiEndpoint: addRecoverySilenceAPI,
+			want:        acl.ADMIN,
+		}, {
+			apiEndpoint: removeRecoverySilenceAPI,
+			want:        acl.ADMIN,
 		}, {
 			apiEndpoint: replicationAnalysisAPI,
 			want:        acl.MONITORING,
+		}, {
+			apiEndpoint: simulateRecoveriesAPI,
+			want:        acl.MONITORING,
 		}, {
 			apiEndpoint: healthAPI,
 			want:        acl.MONITORING,
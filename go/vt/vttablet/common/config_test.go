@@ -48,6 +48,7 @@ func TestNewVReplicationConfig(t *testing.T) {
 				"vreplication_heartbeat_update_interval":  "2",
 				"vreplication_store_compressed_gtid":      "true",
 				"vreplication-parallel-insert-workers":    "4",
+				"vreplication-copy-parallel-ranges":       "8",
 				"vstream_packet_size":                     "1024",
 				"vstream_dynamic_packet_size":             "false",
 				"vstream_binlog_rotation_threshold":       "2048",
@@ -66,6 +67,7 @@ func TestNewVReplicationConfig(t *testing.T) {
 				HeartbeatUpdateInterval:                2,
 				StoreCompressedGTID:                    true,
 				ParallelInsertWorkers:                  4,
+				CopyParallelRanges:                     8,
 				VStreamPacketSize:                      1024,
 				VStreamDynamicPacketSize:               false,
 				VStreamBinlogRotationThreshold:         2048,
@@ -90,11 +92,12 @@ func TestNewVReplicationConfig(t *testing.T) {
 				"vreplication_heartbeat_update_interval":  "invalid",
 				"vreplication_store_compressed_gtid":      "nottrue",
 				"vreplication-parallel-insert-workers":    "invalid",
+				"vreplication-copy-parallel-ranges":       "invalid",
 				"vstream_packet_size":                     "invalid",
 				"vstream_dynamic_packet_size":             "waar",
 				"vstream_binlog_rotation_threshold":       "invalid",
 			},
-			wantErr: 15,
+			wantErr: 16,
 		},
 		{
 			name: "Partial values",
@@ -118,6 +121,7 @@ func TestNewVReplicationConfig(t *testing.T) {
 				HeartbeatUpdateInterval:          DefaultVReplicationConfig.HeartbeatUpdateInterval,
 				StoreCompressedGTID:              !DefaultVReplicationConfig.StoreCompressedGTID,
 				ParallelInsertWorkers:            DefaultVReplicationConfig.ParallelInsertWorkers,
+				CopyParallelRanges:               DefaultVReplicationConfig.CopyParallelRanges,
 				VStreamPacketSize:                DefaultVReplicationConfig.VStreamPacketSize,
 				VStreamDynamicPacketSize:         !DefaultVReplicationConfig.VStreamDynamicPacketSize,
 				VStreamBinlogRotationThreshold:   DefaultVReplicationConfig.VStreamBinlogRotationThreshold,
@@ -407,6 +407,11 @@ func (client *FakeTabletManagerClient) RestoreFromBackup(ctx context.Context, ta
 	return &eofEventStream{}, nil
 }
 
+// GetBackupProgress is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) GetBackupProgress(ctx context.Context, tablet *topodatapb.Tablet) (*tabletmanagerdatapb.BackupProgress, error) {
+	return nil, nil
+}
+
 // Throttler related methods
 
 func (client *FakeTabletManagerClient) CheckThrottler(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error) {
@@ -1388,6 +1388,20 @@ func (client *Client) RestoreFromBackup(ctx context.Context, tablet *topodatapb.
 	}, nil
 }
 
+// GetBackupProgress is part of the tmclient.TabletManagerClient interface.
+func (client *Client) GetBackupProgress(ctx context.Context, tablet *topodatapb.Tablet) (*tabletmanagerdatapb.BackupProgress, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	response, err := c.GetBackupProgress(ctx, &tabletmanagerdatapb.GetBackupProgressRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return response.Progress, nil
+}
+
 // Close is part of the tmclient.TabletManagerClient interface.
 func (client *Client) Close() {
 	client.dialer.Close()
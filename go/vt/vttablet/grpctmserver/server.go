@@ -688,6 +688,14 @@ func (s *server) RestoreFromBackup(request *tabletmanagerdatapb.RestoreFromBacku
 	return s.tm.RestoreFromBackup(ctx, logger, request)
 }
 
+func (s *server) GetBackupProgress(ctx context.Context, request *tabletmanagerdatapb.GetBackupProgressRequest) (response *tabletmanagerdatapb.GetBackupProgressResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "GetBackupProgress", request, response, false /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.GetBackupProgressResponse{}
+	response.Progress, err = s.tm.GetBackupProgress(ctx)
+	return response, err
+}
+
 func (s *server) CheckThrottler(ctx context.Context, request *tabletmanagerdatapb.CheckThrottlerRequest) (response *tabletmanagerdatapb.CheckThrottlerResponse, err error) {
 	defer s.tm.HandleRPCPanic(ctx, "CheckThrottler", request, response, false /*verbose*/, &err)
 	ctx = callinfo.GRPCCallInfo(ctx)
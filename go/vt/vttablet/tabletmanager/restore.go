@@ -236,6 +236,7 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 		return err
 	}
 	// Loop until a backup exists, unless we were told to give up immediately.
+	params.Progress = tm.startBackupProgress(mysqlctl.BackupProgressOperationRestore)
 	var backupManifest *mysqlctl.BackupManifest
 	for {
 		backupManifest, err = mysqlctl.Restore(ctx, params)
@@ -260,6 +261,8 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 		}
 	}
 
+	params.Progress.Finish(err)
+
 	var pos replication.Position
 	if backupManifest != nil {
 		pos = backupManifest.Position
@@ -168,6 +168,8 @@ type RPCTM interface {
 
 	IsBackupRunning() bool
 
+	GetBackupProgress(ctx context.Context) (*tabletmanagerdatapb.BackupProgress, error)
+
 	// HandleRPCPanic is to be called in a defer statement in each
 	// RPC input point.
 	HandleRPCPanic(ctx context.Context, name string, args, reply any, verbose bool, err *error)
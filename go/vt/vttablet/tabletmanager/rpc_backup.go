@@ -32,6 +32,8 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
@@ -172,9 +174,13 @@ func (tm *TabletManager) Backup(ctx context.Context, logger logutil.Logger, req
 		UpgradeSafe:          req.UpgradeSafe,
 		MysqlShutdownTimeout: shutdownTimeout(l, req.MysqlShutdownTimeout),
 		BackupEngine:         backupEngine,
+		DbName:               topoproto.TabletDbName(tablet.Tablet),
+		TableStreamer:        tm.tableStreamer(tablet.Tablet),
+		Progress:             tm.startBackupProgress(mysqlctl.BackupProgressOperationBackup),
 	}
 
 	returnErr := mysqlctl.Backup(ctx, backupParams)
+	backupParams.Progress.Finish(returnErr)
 
 	return returnErr
 }
@@ -207,6 +213,40 @@ func (tm *TabletManager) RestoreFromBackup(ctx context.Context, logger logutil.L
 	return err
 }
 
+// tableStreamer returns a mysqlctl.TableStreamer that reads all tables from
+// a consistent snapshot through the tablet server's VStreamTables.
+func (tm *TabletManager) tableStreamer(tablet *topodatapb.Tablet) mysqlctl.TableStreamer {
+	if tm.QueryServiceControl == nil {
+		return nil
+	}
+	target := &querypb.Target{
+		Keyspace:   tablet.Keyspace,
+		Shard:      tablet.Shard,
+		TabletType: tablet.Type,
+	}
+	return func(ctx context.Context, send func(*binlogdatapb.VStreamTablesResponse) error) error {
+		return tm.QueryServiceControl.QueryService().VStreamTables(ctx, &binlogdatapb.VStreamTablesRequest{Target: target}, send)
+	}
+}
+
+// GetBackupProgress returns the progress of the running, or latest, backup or
+// restore, or nil if the tablet has not run any since it started.
+func (tm *TabletManager) GetBackupProgress(ctx context.Context) (*tabletmanagerdatapb.BackupProgress, error) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	return tm._backupProgress.Proto(), nil
+}
+
+// startBackupProgress returns a new BackupProgress for the given operation,
+// which becomes the one reported by GetBackupProgress.
+func (tm *TabletManager) startBackupProgress(operation string) *mysqlctl.BackupProgress {
+	progress := mysqlctl.NewBackupProgress(operation)
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	tm._backupProgress = progress
+	return progress
+}
+
 func (tm *TabletManager) IsBackupRunning() bool {
 	return tm._isBackupRunning
 }
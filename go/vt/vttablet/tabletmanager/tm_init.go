@@ -221,6 +221,8 @@ type TabletManager struct {
 	_lockTablesTimer      *time.Timer
 	// _isBackupRunning tells us whether there is a backup that is currently running
 	_isBackupRunning bool
+	// _backupProgress is the progress of the running, or latest, backup or restore.
+	_backupProgress *mysqlctl.BackupProgress
 }
 
 // BuildTabletFromInput builds a tablet record from input parameters.
@@ -40,13 +40,17 @@ import (
 type VDiffAction string // nolint
 
 const (
-	CreateAction  VDiffAction = "create"
-	ShowAction    VDiffAction = "show"
-	StopAction    VDiffAction = "stop"
-	ResumeAction  VDiffAction = "resume"
-	DeleteAction  VDiffAction = "delete"
-	AllActionArg              = "all"
-	LastActionArg             = "last"
+	CreateAction VDiffAction = "create"
+	ShowAction   VDiffAction = "show"
+	StopAction   VDiffAction = "stop"
+	ResumeAction VDiffAction = "resume"
+	DeleteAction VDiffAction = "delete"
+	// RepairAction shows, or applies, the repair statements recorded by a
+	// vdiff that was created with repair enabled.
+	RepairAction   VDiffAction = "repair"
+	AllActionArg               = "all"
+	LastActionArg              = "last"
+	ApplyActionArg             = "apply"
 
 	maxVDiffsToReport = 100
 )
@@ -108,6 +112,10 @@ func (vde *Engine) PerformVDiffAction(ctx context.Context, req *tabletmanagerdat
 		if err := vde.handleDeleteAction(ctx, dbClient, req, resp); err != nil {
 			return nil, err
 		}
+	case RepairAction:
+		if err := vde.handleRepairAction(ctx, dbClient, req, resp); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("action %s not supported", action)
 	}
@@ -0,0 +1,988 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil/policy"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// DefaultChecksumChunkSize is the default number of rows in the primary
+	// key ranges that a checksum vdiff compares the checksums of.
+	DefaultChecksumChunkSize = 100000
+
+	// checksumSplitFactor is the number of sub-ranges that a range whose
+	// checksums differ is split into.
+	checksumSplitFactor = 16
+	// checksumLeafRows is the number of rows up to which the rows of a range
+	// whose checksums differ are compared one by one, rather than splitting
+	// the range any further.
+	checksumLeafRows = 1000
+)
+
+// pkRange is a range of primary key values. lo is exclusive and hi is
+// inclusive. A nil bound means that the range is unbounded on that side.
+// When pks is set, the range is further restricted to the rows with those
+// primary key values.
+type pkRange struct {
+	lo, hi []sqltypes.Value
+	pks    [][]sqltypes.Value
+}
+
+// rangeChecksum is the number of rows in a pkRange and the checksum of
+// those rows.
+type rangeChecksum struct {
+	count    int64
+	checksum uint64
+}
+
+// add combines the checksum of another set of rows, e.g. from another
+// source shard, into this one. The rows checksums are xor-ed, so the order
+// in which they are combined does not matter.
+func (rc *rangeChecksum) add(other rangeChecksum) {
+	rc.count += other.count
+	rc.checksum ^= other.checksum
+}
+
+// checksumPlan holds the queries used to diff a table with checksums.
+type checksumPlan struct {
+	// sourceSelect and targetSelect are the select queries of the table plan,
+	// without their order by clause.
+	sourceSelect *sqlparser.Select
+	targetSelect *sqlparser.Select
+	// sourcePKs and targetPKs are the primary key expressions, in the order
+	// of the comparePKs of the table plan.
+	sourcePKs []sqlparser.Expr
+	targetPKs []sqlparser.Expr
+	// keyRangeExpr is the in_keyrange() expression of the workflow filter,
+	// which is removed from sourceSelect as MySQL cannot evaluate it. The
+	// source rows are filtered on keyRange instead, once it is built from
+	// keyRangeExpr by buildKeyRangeFilter.
+	keyRangeExpr *sqlparser.FuncExpr
+	keyRange     *keyRangeFilter
+}
+
+// keyRangeFilter selects the source rows whose keyspace id, computed by the
+// vindex from the columns, is in the key range.
+type keyRangeFilter struct {
+	vindex   vindexes.Vindex
+	columns  []sqlparser.Expr
+	keyRange *topodatapb.KeyRange
+}
+
+// filter returns the rows whose keyspace id is in the key range, without
+// their last len(columns) values, which are the values of the vindex columns.
+func (f *keyRangeFilter) filter(ctx context.Context, rows [][]sqltypes.Value) ([][]sqltypes.Value, error) {
+	var filtered [][]sqltypes.Value
+	for _, row := range rows {
+		n := len(row) - len(f.columns)
+		destinations, err := vindexes.Map(ctx, f.vindex, nil, [][]sqltypes.Value{row[n:]})
+		if err != nil {
+			return nil, err
+		}
+		if len(destinations) != 1 {
+			return nil, fmt.Errorf("mapping row to keyspace id returned an invalid array of destinations: %v", key.DestinationsString(destinations))
+		}
+		ksid, ok := destinations[0].(key.DestinationKeyspaceID)
+		if !ok || len(ksid) == 0 {
+			return nil, fmt.Errorf("could not map %v to a keyspace id, got destination %v", row[n:], destinations[0])
+		}
+		if key.KeyRangeContains(f.keyRange, ksid) {
+			filtered = append(filtered, row[:n])
+		}
+	}
+	return filtered, nil
+}
+
+// checksumUnsupportedReason returns why the table cannot be diffed with
+// checksums, or an empty string if it can. Tables that cannot be diffed
+// with checksums are diffed by streaming all their rows.
+func (td *tableDiffer) checksumUnsupportedReason() (string, error) {
+	switch {
+	case len(td.tablePlan.aggregates) > 0:
+		return "the workflow filter has aggregates", nil
+	case td.wd.ct.sourceTimeZone != "":
+		return "the workflow converts datetime columns between time zones", nil
+	}
+	statement, err := td.wd.ct.vde.parser.Parse(td.sourceQuery)
+	if err != nil {
+		return "", err
+	}
+	sel, ok := statement.(*sqlparser.Select)
+	if !ok {
+		return "", fmt.Errorf("unexpected: %v", sqlparser.String(statement))
+	}
+	if len(sel.GroupByExprs()) > 0 {
+		return "the workflow filter has a group by clause", nil
+	}
+	return "", nil
+}
+
+// buildChecksumPlan builds the queries used to diff the table with checksums
+// from the table plan. The in_keyrange() expression of the workflow filter,
+// if any, is removed from the source query and kept in the plan, for
+// buildKeyRangeFilter to build the filter of the source rows from.
+func (td *tableDiffer) buildChecksumPlan() (*checksumPlan, error) {
+	parseSelect := func(query string) (*sqlparser.Select, error) {
+		statement, err := td.wd.ct.vde.parser.Parse(query)
+		if err != nil {
+			return nil, err
+		}
+		sel, ok := statement.(*sqlparser.Select)
+		if !ok {
+			return nil, fmt.Errorf("unexpected: %v", sqlparser.String(statement))
+		}
+		sel.OrderBy = nil
+		return sel, nil
+	}
+	sourceSelect, err := parseSelect(td.tablePlan.sourceQuery)
+	if err != nil {
+		return nil, err
+	}
+	targetSelect, err := parseSelect(td.tablePlan.targetQuery)
+	if err != nil {
+		return nil, err
+	}
+	cp := &checksumPlan{
+		sourceSelect: sourceSelect,
+		targetSelect: targetSelect,
+	}
+	if sourceSelect.Where != nil {
+		for _, expr := range sqlparser.SplitAndExpression(nil, sourceSelect.Where.Expr) {
+			if funcExpr, ok := expr.(*sqlparser.FuncExpr); ok && funcExpr.Name.EqualString("in_keyrange") {
+				cp.keyRangeExpr = funcExpr
+			}
+		}
+		sourceSelect.Where = copyNonKeyRangeExpressions(sourceSelect.Where)
+	}
+	for _, pk := range td.tablePlan.comparePKs {
+		cp.sourcePKs = append(cp.sourcePKs, sourceSelect.GetColumns()[pk.colIndex].(*sqlparser.AliasedExpr).Expr)
+		cp.targetPKs = append(cp.targetPKs, targetSelect.GetColumns()[pk.colIndex].(*sqlparser.AliasedExpr).Expr)
+	}
+	return cp, nil
+}
+
+// buildKeyRangeFilter builds the filter of the source rows from the
+// in_keyrange() expression of the workflow filter, which has the same forms
+// as for the vstreamer: "in_keyrange('-80')", which uses the primary vindex of
+// the source table, "in_keyrange(col, 'hash', '-80')", where the vindex is a
+// vindex of the source keyspace or a new vindex of that type, or
+// "in_keyrange(col, 'ks.vindex', '-80')".
+func (td *tableDiffer) buildKeyRangeFilter(ctx context.Context, cp *checksumPlan) error {
+	if cp.keyRangeExpr == nil || cp.keyRange != nil {
+		return nil
+	}
+	ts, err := td.wd.getSourceTopoServer()
+	if err != nil {
+		return err
+	}
+	getKeyspaceSchema := func(keyspace string) (*vindexes.KeyspaceSchema, error) {
+		vs, err := ts.GetVSchema(ctx, keyspace)
+		if err != nil {
+			return nil, err
+		}
+		return vindexes.BuildKeyspaceSchema(vs.Keyspace, keyspace, td.wd.ct.vde.parser)
+	}
+	exprs := cp.keyRangeExpr.Exprs
+	f := &keyRangeFilter{}
+	switch {
+	case len(exprs) == 1:
+		from, ok := cp.sourceSelect.From[0].(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return fmt.Errorf("unexpected: %v", sqlparser.String(cp.sourceSelect))
+		}
+		tableName := sqlparser.GetTableName(from.Expr).String()
+		ks, err := getKeyspaceSchema(td.wd.ct.sourceKeyspace)
+		if err != nil {
+			return err
+		}
+		table := ks.Tables[tableName]
+		if table == nil {
+			return fmt.Errorf("table %s not found in the vschema of keyspace %s", tableName, td.wd.ct.sourceKeyspace)
+		}
+		cv, err := vindexes.FindBestColVindex(table)
+		if err != nil {
+			return err
+		}
+		f.vindex = cv.Vindex
+		for _, col := range cv.Columns {
+			f.columns = append(f.columns, sqlparser.NewColName(col.String()))
+		}
+	case len(exprs) >= 3:
+		for _, expr := range exprs[:len(exprs)-2] {
+			f.columns = append(f.columns, expr)
+		}
+		vindexName, err := keyRangeString(exprs[len(exprs)-2])
+		if err != nil {
+			return err
+		}
+		keyspace, name := td.wd.ct.sourceKeyspace, vindexName
+		if qualifier, unqualified, ok := strings.Cut(vindexName, "."); ok {
+			keyspace, name = qualifier, unqualified
+		}
+		ks, err := getKeyspaceSchema(keyspace)
+		if err != nil {
+			return err
+		}
+		f.vindex = ks.Vindexes[name]
+		if f.vindex == nil {
+			if keyspace != td.wd.ct.sourceKeyspace {
+				return fmt.Errorf("vindex %s not found", vindexName)
+			}
+			if f.vindex, err = vindexes.CreateVindex(name, name, map[string]string{}); err != nil {
+				return err
+			}
+		}
+		if !f.vindex.IsUnique() {
+			return fmt.Errorf("vindex must be Unique to be used for VReplication: %s", vindexName)
+		}
+	default:
+		return fmt.Errorf("unexpected in_keyrange parameters: %v", sqlparser.String(cp.keyRangeExpr))
+	}
+	kr, err := keyRangeString(exprs[len(exprs)-1])
+	if err != nil {
+		return err
+	}
+	keyRanges, err := key.ParseShardingSpec(kr)
+	if err != nil {
+		return err
+	}
+	if len(keyRanges) != 1 {
+		return fmt.Errorf("unexpected in_keyrange parameter: %v", kr)
+	}
+	f.keyRange = keyRanges[0]
+	cp.keyRange = f
+	return nil
+}
+
+// keyRangeString returns the value of a string parameter of in_keyrange().
+func keyRangeString(expr sqlparser.Expr) (string, error) {
+	val, ok := expr.(*sqlparser.Literal)
+	if !ok || val.Type != sqlparser.StrVal {
+		return "", fmt.Errorf("unexpected in_keyrange parameter: %v", sqlparser.String(expr))
+	}
+	return val.Val, nil
+}
+
+// keyRangeColumns returns the vindex columns of the key range filter, which
+// the source queries select after the other columns, or nil if the source
+// rows are not filtered.
+func (cp *checksumPlan) keyRangeColumns() []sqlparser.Expr {
+	if cp.keyRange == nil {
+		return nil
+	}
+	return cp.keyRange.columns
+}
+
+// formatPKTuple formats the primary key expressions or values as a tuple, or
+// as a single value if there is only one primary key column.
+func formatPKTuple(buf *sqlparser.TrackedBuffer, n int, format func(i int)) {
+	if n > 1 {
+		buf.WriteString("(")
+	}
+	for i := range n {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		format(i)
+	}
+	if n > 1 {
+		buf.WriteString(")")
+	}
+}
+
+// formatRangeFilter formats the where clause that selects the rows of the
+// range, and of the workflow filter.
+func formatRangeFilter(buf *sqlparser.TrackedBuffer, sel *sqlparser.Select, pks []sqlparser.Expr, r pkRange) {
+	var conds []string
+	formatBound := func(op string, bound []sqltypes.Value) {
+		condBuf := sqlparser.NewTrackedBuffer(nil)
+		formatPKTuple(condBuf, len(pks), func(i int) { condBuf.Myprintf("%v", pks[i]) })
+		condBuf.WriteString(" " + op + " ")
+		formatPKTuple(condBuf, len(bound), func(i int) { bound[i].EncodeSQL(condBuf) })
+		conds = append(conds, condBuf.String())
+	}
+	if r.lo != nil {
+		formatBound(">", r.lo)
+	}
+	if r.hi != nil {
+		formatBound("<=", r.hi)
+	}
+	if r.pks != nil {
+		condBuf := sqlparser.NewTrackedBuffer(nil)
+		formatPKTuple(condBuf, len(pks), func(i int) { condBuf.Myprintf("%v", pks[i]) })
+		condBuf.WriteString(" in (")
+		for i, pk := range r.pks {
+			if i > 0 {
+				condBuf.WriteString(", ")
+			}
+			formatPKTuple(condBuf, len(pk), func(i int) { pk[i].EncodeSQL(condBuf) })
+		}
+		condBuf.WriteString(")")
+		conds = append(conds, condBuf.String())
+	}
+	if sel.Where != nil && sel.Where.Expr != nil {
+		conds = append(conds, "("+sqlparser.String(sel.Where.Expr)+")")
+	}
+	for i, cond := range conds {
+		if i == 0 {
+			buf.WriteString(" where ")
+		} else {
+			buf.WriteString(" and ")
+		}
+		buf.WriteString(cond)
+	}
+}
+
+// formatOrderByPKs formats the order by clause on the primary key expressions.
+func formatOrderByPKs(buf *sqlparser.TrackedBuffer, pks []sqlparser.Expr) {
+	buf.WriteString(" order by ")
+	for i, pk := range pks {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.Myprintf("%v", pk)
+	}
+}
+
+// checksumQuery returns the query computing the number of rows of the range
+// and the checksum of those rows. The checksum of a row is the first 64 bits
+// of the md5 of all its columns, and the checksum of the range is the xor of
+// the checksums of its rows, so that it does not depend on the order of the
+// rows and can be combined across source shards.
+func checksumQuery(sel *sqlparser.Select, pks []sqlparser.Expr, r pkRange) string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.WriteString("select count(*), bit_xor(")
+	formatRowChecksum(buf, sel)
+	buf.WriteString(") from " + sqlparser.ToString(sel.From))
+	formatRangeFilter(buf, sel, pks, r)
+	return buf.String()
+}
+
+// rowChecksumsQuery returns the query selecting the primary key and the
+// checksum of each row of the range, followed by the extra columns.
+func rowChecksumsQuery(sel *sqlparser.Select, pks []sqlparser.Expr, r pkRange, extra ...sqlparser.Expr) string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.WriteString("select ")
+	for _, pk := range pks {
+		buf.Myprintf("%v, ", pk)
+	}
+	formatRowChecksum(buf, sel)
+	for _, expr := range extra {
+		buf.Myprintf(", %v", expr)
+	}
+	buf.WriteString(" from " + sqlparser.ToString(sel.From))
+	formatRangeFilter(buf, sel, pks, r)
+	return buf.String()
+}
+
+// formatRowChecksum formats the checksum of a row, which is the first 64 bits
+// of the md5 of all its columns.
+func formatRowChecksum(buf *sqlparser.TrackedBuffer, sel *sqlparser.Select) {
+	buf.WriteString("cast(conv(substr(md5(concat_ws('#'")
+	for _, col := range sel.GetColumns() {
+		expr := col.(*sqlparser.AliasedExpr).Expr
+		buf.Myprintf(", isnull(%v), cast(%v as binary)", expr, expr)
+	}
+	buf.WriteString(")), 1, 16), 16, 10) as unsigned)")
+}
+
+// boundaryQuery returns the query selecting the primary key of the row at the
+// given offset of the range.
+func boundaryQuery(sel *sqlparser.Select, pks []sqlparser.Expr, r pkRange, offset int64) string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.WriteString("select ")
+	for i, pk := range pks {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.Myprintf("%v", pk)
+	}
+	buf.WriteString(" from " + sqlparser.ToString(sel.From))
+	formatRangeFilter(buf, sel, pks, r)
+	formatOrderByPKs(buf, pks)
+	buf.Myprintf(" limit %d, 1", offset)
+	return buf.String()
+}
+
+// rowsQuery returns the query selecting the rows of the range, followed by
+// the extra columns, ordered by primary key.
+func rowsQuery(sel *sqlparser.Select, pks []sqlparser.Expr, r pkRange, extra ...sqlparser.Expr) string {
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.Myprintf("select %v", sel.SelectExprs)
+	for _, expr := range extra {
+		buf.Myprintf(", %v", expr)
+	}
+	buf.WriteString(" from " + sqlparser.ToString(sel.From))
+	formatRangeFilter(buf, sel, pks, r)
+	formatOrderByPKs(buf, pks)
+	return buf.String()
+}
+
+// executeFetch runs the query on the tablet.
+func (td *tableDiffer) executeFetch(ctx context.Context, tablet *topodatapb.Tablet, query string, maxRows int64) (*sqltypes.Result, error) {
+	res, err := td.wd.ct.tmc.ExecuteFetchAsApp(ctx, tablet, false, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+		Query:   []byte(query),
+		MaxRows: uint64(maxRows),
+	})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to run %q on tablet %s", query, topoproto.TabletAliasString(tablet.Alias))
+	}
+	return sqltypes.Proto3ToResult(res), nil
+}
+
+// lockChecksum locks the workflow and picks the source and target tablets
+// that the checksums are computed on. It returns a function that unlocks the
+// workflow, which must be called once the diff of the table is done. The
+// workflow is kept locked until then, so the target shards diff the table one
+// at a time, as they may use the same source tablets.
+func (td *tableDiffer) lockChecksum(ctx context.Context) (context.Context, func(), error) {
+	ct := td.wd.ct
+	lockName := fmt.Sprintf("%s/%s", ct.vde.thisTablet.Keyspace, ct.workflow)
+	log.Infof("Locking workflow %s", lockName)
+	lockCtx, unlock, err := ct.ts.LockName(ctx, lockName, "vdiff")
+	if err != nil {
+		log.Errorf("Locking workflow %s failed: %v", lockName, err)
+		return nil, nil, err
+	}
+	release := func() {
+		var unlockErr error
+		unlock(&unlockErr)
+		if unlockErr != nil {
+			log.Errorf("Unlocking workflow %s failed: %v", lockName, unlockErr)
+		}
+	}
+
+	err = func() error {
+		if err := td.selectTablets(lockCtx); err != nil {
+			return err
+		}
+		for _, source := range ct.sources {
+			if source.tablet.Type == topodatapb.TabletType_PRIMARY {
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION,
+					"source tablet %s is a primary: a checksum vdiff stops replication on the source tablets, use replica or rdonly tablet types",
+					topoproto.TabletAliasString(source.tablet.Alias))
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return lockCtx, release, nil
+}
+
+// pauseForChecksum stops replication on the source tablets, and the workflow
+// on the target, at the same position, so that the checksums of a chunk are
+// computed on the same data on both sides. It returns a function that
+// restarts replication and the workflow, which must be called as soon as the
+// chunk is diffed, so that the workflow and the source tablets are only held
+// back for the duration of one chunk rather than of the whole table.
+func (td *tableDiffer) pauseForChecksum(ctx context.Context, dbClient binlogplayer.DBClient) (func(), error) {
+	defer td.wd.ct.TableDiffPhaseTimings.Record(fmt.Sprintf("%s.%s", td.table.Name, stoppingReplication), time.Now())
+	ct := td.wd.ct
+
+	var stoppedSources []*topodatapb.Tablet
+	resume := func() {
+		// We use a new context as we want to reset the state even
+		// when the parent context has timed out or been canceled.
+		restartCtx, restartCancel := context.WithTimeout(context.Background(), BackgroundOperationTimeout)
+		defer restartCancel()
+		for _, tablet := range stoppedSources {
+			if err := td.startSourceReplication(restartCtx, tablet); err != nil {
+				log.Errorf("error restarting replication on source tablet %s: %v", topoproto.TabletAliasString(tablet.Alias), err)
+			}
+		}
+		if err := td.restartTargetVReplicationStreams(restartCtx); err != nil {
+			log.Errorf("error restarting target streams: %v", err)
+		}
+	}
+
+	err := func() error {
+		if err := td.stopTargetVReplicationStreams(ctx, dbClient); err != nil {
+			return err
+		}
+		waitCtx, cancel := context.WithTimeout(ctx, time.Duration(ct.options.CoreOptions.TimeoutSeconds*int64(time.Second)))
+		defer cancel()
+		for _, source := range ct.sources {
+			pos, err := ct.tmc.StopReplicationMinimum(waitCtx, source.tablet, replication.EncodePosition(source.position),
+				time.Duration(ct.options.CoreOptions.TimeoutSeconds*int64(time.Second)))
+			if err != nil {
+				return vterrors.Wrapf(err, "StopReplicationMinimum for tablet %v", topoproto.TabletAliasString(source.tablet.Alias))
+			}
+			stoppedSources = append(stoppedSources, source.tablet)
+			source.snapshotPosition = pos
+		}
+		if err := td.syncTargetStreams(ctx); err != nil {
+			return err
+		}
+		targetTablet := ct.targetShardStreamer.tablet
+		if !topoproto.TabletAliasEqual(targetTablet.Alias, ct.vde.thisTablet.Alias) {
+			pos, err := ct.tmc.PrimaryPosition(waitCtx, ct.vde.thisTablet)
+			if err != nil {
+				return err
+			}
+			if err := ct.tmc.WaitForPosition(waitCtx, targetTablet, pos); err != nil {
+				return vterrors.Wrapf(err, "WaitForPosition for tablet %v", topoproto.TabletAliasString(targetTablet.Alias))
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		resume()
+		return nil, err
+	}
+	return resume, nil
+}
+
+// startSourceReplication restarts replication on a source tablet stopped by
+// pauseForChecksum.
+func (td *tableDiffer) startSourceReplication(ctx context.Context, tablet *topodatapb.Tablet) error {
+	ts, err := td.wd.getSourceTopoServer()
+	if err != nil {
+		return err
+	}
+	durabilityName, err := ts.GetKeyspaceDurability(ctx, tablet.Keyspace)
+	if err != nil {
+		return err
+	}
+	durability, err := policy.GetDurabilityPolicy(durabilityName)
+	if err != nil {
+		return err
+	}
+	shard, err := ts.GetShard(ctx, tablet.Keyspace, tablet.Shard)
+	if err != nil {
+		return err
+	}
+	var primary *topodatapb.Tablet
+	if shard.PrimaryAlias != nil {
+		primaryInfo, err := ts.GetTablet(ctx, shard.PrimaryAlias)
+		if err != nil {
+			return err
+		}
+		primary = primaryInfo.Tablet
+	}
+	return td.wd.ct.tmc.StartReplication(ctx, tablet, policy.IsReplicaSemiSync(durability, primary, tablet))
+}
+
+// checksumDiff diffs the table by splitting it into ranges of primary keys
+// and comparing the checksums of each range, computed by MySQL on the source
+// and target tablets. The rows of a range are only compared when its
+// checksums differ, after narrowing the range down to the sub-ranges whose
+// checksums differ.
+func (td *tableDiffer) checksumDiff(ctx context.Context, coreOpts *tabletmanagerdatapb.VDiffCoreOptions, reportOpts *tabletmanagerdatapb.VDiffReportOptions, stop <-chan time.Time) (*DiffReport, error) {
+	defer td.wd.ct.TableDiffPhaseTimings.Record(fmt.Sprintf("%s.%s", td.table.Name, checksummingTable), time.Now())
+	dbClient := td.wd.ct.dbClientFactory()
+	if err := dbClient.Connect(); err != nil {
+		return nil, err
+	}
+	defer dbClient.Close()
+
+	dr, mismatch, err := td.getDiffReport(dbClient)
+	if err != nil {
+		return nil, err
+	}
+	if err := td.startRepairs(dbClient); err != nil {
+		return nil, err
+	}
+	cp, err := td.buildChecksumPlan()
+	if err != nil {
+		return nil, err
+	}
+	if err := td.buildKeyRangeFilter(ctx, cp); err != nil {
+		return nil, err
+	}
+
+	chunkSize := coreOpts.GetChecksumChunkSize()
+	if chunkSize <= 0 {
+		chunkSize = DefaultChecksumChunkSize
+	}
+	rowsToCompare := coreOpts.GetMaxRows()
+	var lo []sqltypes.Value
+	if td.lastTargetPK != nil && len(td.lastTargetPK.Rows) > 0 {
+		lo = sqltypes.Proto3ToResult(td.lastTargetPK).Rows[0]
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
+		case <-td.wd.ct.done:
+			return nil, ErrVDiffStoppedByUser
+		case <-stop:
+			globalStats.RestartedTableDiffs.Add(td.table.Name, 1)
+			return nil, ErrMaxDiffDurationExceeded
+		default:
+		}
+
+		// The chunks are split on the target, whose primary key the diff
+		// is ordered by, while the workflow is running: the bounds of a chunk
+		// are the same on both sides, whatever rows they hold.
+		qr, err := td.executeFetch(ctx, td.wd.ct.targetShardStreamer.tablet, boundaryQuery(cp.targetSelect, cp.targetPKs, pkRange{lo: lo}, chunkSize-1), 1)
+		if err != nil {
+			return nil, err
+		}
+		var hi []sqltypes.Value
+		if len(qr.Rows) > 0 {
+			hi = qr.Rows[0]
+		}
+		resume, err := td.pauseForChecksum(ctx, dbClient)
+		if err != nil {
+			return nil, err
+		}
+		err = td.diffRange(ctx, dbClient, cp, pkRange{lo: lo, hi: hi}, dr, coreOpts, reportOpts)
+		resume()
+		if err != nil {
+			return nil, err
+		}
+		if !mismatch && (dr.MismatchedRows > 0 || dr.ExtraRowsSource > 0 || dr.ExtraRowsTarget > 0) {
+			mismatch = true
+			log.Infof("Flagging mismatch for %s: %+v", td.table.Name, dr)
+			if err := updateTableMismatch(dbClient, td.wd.ct.id, td.table.Name); err != nil {
+				return nil, err
+			}
+		}
+		if hi == nil {
+			if err := td.updateTableProgress(dbClient, dr, nil); err != nil {
+				return nil, err
+			}
+			return dr, nil
+		}
+		if err := td.updateTableProgress(dbClient, dr, td.rowFromPK(hi)); err != nil {
+			return nil, err
+		}
+		lo = hi
+		if dr.ProcessedRows >= rowsToCompare {
+			log.Infof("Stopping vdiff, specified row limit reached")
+			return dr, nil
+		}
+	}
+}
+
+// rowFromPK returns a row of the table plan holding the given primary key
+// values, to save the progress of the diff.
+func (td *tableDiffer) rowFromPK(pk []sqltypes.Value) []sqltypes.Value {
+	row := make([]sqltypes.Value, len(td.tablePlan.compareCols))
+	for i, pkCol := range td.tablePlan.comparePKs {
+		row[pkCol.colIndex] = pk[i]
+	}
+	return row
+}
+
+// diffRange diffs the rows of a range, recursively narrowing it down to the
+// sub-ranges whose checksums differ.
+func (td *tableDiffer) diffRange(ctx context.Context, dbClient binlogplayer.DBClient, cp *checksumPlan, r pkRange, dr *DiffReport, coreOpts *tabletmanagerdatapb.VDiffCoreOptions, reportOpts *tabletmanagerdatapb.VDiffReportOptions) error {
+	select {
+	case <-ctx.Done():
+		return vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
+	case <-td.wd.ct.done:
+		return ErrVDiffStoppedByUser
+	default:
+	}
+
+	targetTablet := td.wd.ct.targetShardStreamer.tablet
+	var (
+		sourceChecksum rangeChecksum
+		// sourceRows is the number of rows in the range on the source tablets,
+		// including those that the key range filter rejects.
+		sourceRows int64
+		// largestSource is the source tablet with the most rows in the range,
+		// and largestSourceCount its number of rows.
+		largestSource      *topodatapb.Tablet
+		largestSourceCount int64
+	)
+	for _, source := range td.wd.ct.sources {
+		rc, rows, err := td.fetchSourceChecksum(ctx, source.tablet, cp, r)
+		if err != nil {
+			return err
+		}
+		sourceChecksum.add(rc)
+		sourceRows += rows
+		if largestSource == nil || rows > largestSourceCount {
+			largestSource, largestSourceCount = source.tablet, rows
+		}
+	}
+	targetChecksum, err := td.fetchChecksum(ctx, targetTablet, checksumQuery(cp.targetSelect, cp.targetPKs, r))
+	if err != nil {
+		return err
+	}
+	if sourceChecksum == targetChecksum {
+		td.wd.ct.TableChecksumRangeCounts.Add(td.table.Name+".matching", 1)
+		dr.ProcessedRows += targetChecksum.count
+		dr.MatchingRows += targetChecksum.count
+		return nil
+	}
+	td.wd.ct.TableChecksumRangeCounts.Add(td.table.Name+".mismatched", 1)
+
+	if max(sourceRows, targetChecksum.count) > checksumLeafRows {
+		// Split the range on the side with the most rows, so that every
+		// sub-range has fewer rows on that side.
+		tablet, sel, pks, count := targetTablet, cp.targetSelect, cp.targetPKs, targetChecksum.count
+		if largestSourceCount > targetChecksum.count {
+			tablet, sel, pks, count = largestSource, cp.sourceSelect, cp.sourcePKs, largestSourceCount
+		}
+		subRanges, err := td.splitRange(ctx, tablet, sel, pks, r, count)
+		if err != nil {
+			return err
+		}
+		if len(subRanges) > 1 {
+			for _, subRange := range subRanges {
+				if err := td.diffRange(ctx, dbClient, cp, subRange, dr, coreOpts, reportOpts); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		// The range could not be split, e.g. because many rows have the
+		// same primary key equivalent, so compare its rows.
+	}
+	return td.diffRangeRows(ctx, dbClient, cp, r, sourceRows, targetChecksum.count, dr, coreOpts, reportOpts)
+}
+
+// fetchSourceChecksum returns the checksum of the rows of the range on the
+// source tablet, and its number of rows in the range. When the workflow
+// filter has a key range, which MySQL cannot evaluate, the checksum is
+// computed from the checksums of the rows that the key range filter accepts,
+// and the number of rows includes those that it rejects.
+func (td *tableDiffer) fetchSourceChecksum(ctx context.Context, tablet *topodatapb.Tablet, cp *checksumPlan, r pkRange) (rangeChecksum, int64, error) {
+	rc, err := td.fetchChecksum(ctx, tablet, checksumQuery(cp.sourceSelect, cp.sourcePKs, r))
+	if err != nil || cp.keyRange == nil {
+		return rc, rc.count, err
+	}
+	rows, err := td.fetchSourceRowChecksums(ctx, tablet, cp, r, rc.count)
+	if err != nil {
+		return rangeChecksum{}, 0, err
+	}
+	var filtered rangeChecksum
+	for _, row := range rows {
+		checksum, err := row[len(row)-1].ToUint64()
+		if err != nil {
+			return rangeChecksum{}, 0, err
+		}
+		filtered.add(rangeChecksum{count: 1, checksum: checksum})
+	}
+	return filtered, rc.count, nil
+}
+
+// fetchSourceRowChecksums returns the primary key and the checksum of the
+// rows of the range on the source tablet, which has up to count rows in the
+// range, that the key range filter accepts.
+func (td *tableDiffer) fetchSourceRowChecksums(ctx context.Context, tablet *topodatapb.Tablet, cp *checksumPlan, r pkRange, count int64) ([][]sqltypes.Value, error) {
+	qr, err := td.executeFetch(ctx, tablet, rowChecksumsQuery(cp.sourceSelect, cp.sourcePKs, r, cp.keyRangeColumns()...), count)
+	if err != nil {
+		return nil, err
+	}
+	if cp.keyRange == nil {
+		return qr.Rows, nil
+	}
+	return cp.keyRange.filter(ctx, qr.Rows)
+}
+
+// fetchChecksum returns the checksum computed by the query on the tablet.
+func (td *tableDiffer) fetchChecksum(ctx context.Context, tablet *topodatapb.Tablet, query string) (rangeChecksum, error) {
+	qr, err := td.executeFetch(ctx, tablet, query, 1)
+	if err != nil {
+		return rangeChecksum{}, err
+	}
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) != 2 {
+		return rangeChecksum{}, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected checksum result on tablet %s: %v",
+			topoproto.TabletAliasString(tablet.Alias), qr.Rows)
+	}
+	var rc rangeChecksum
+	if rc.count, err = qr.Rows[0][0].ToInt64(); err != nil {
+		return rangeChecksum{}, err
+	}
+	if !qr.Rows[0][1].IsNull() {
+		if rc.checksum, err = qr.Rows[0][1].ToUint64(); err != nil {
+			return rangeChecksum{}, err
+		}
+	}
+	return rc, nil
+}
+
+// splitRange splits the range into up to checksumSplitFactor sub-ranges with
+// about the same number of rows on the tablet, which has count rows in the
+// range.
+func (td *tableDiffer) splitRange(ctx context.Context, tablet *topodatapb.Tablet, sel *sqlparser.Select, pks []sqlparser.Expr, r pkRange, count int64) ([]pkRange, error) {
+	step := max(count/checksumSplitFactor, 1)
+	var subRanges []pkRange
+	lo := r.lo
+	for offset := step - 1; offset < count-1; offset += step {
+		qr, err := td.executeFetch(ctx, tablet, boundaryQuery(sel, pks, r, offset), 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(qr.Rows) == 0 {
+			break
+		}
+		hi := qr.Rows[0]
+		if lo != nil {
+			c, err := td.compare(lo, hi, pkColsInOrder(len(hi), td.tablePlan.comparePKs), false)
+			if err != nil {
+				return nil, err
+			}
+			if c >= 0 {
+				// Rows with the same primary key equivalent.
+				continue
+			}
+		}
+		subRanges = append(subRanges, pkRange{lo: lo, hi: hi, pks: r.pks})
+		lo = hi
+	}
+	return append(subRanges, pkRange{lo: lo, hi: r.hi, pks: r.pks}), nil
+}
+
+// pkColsInOrder returns the primary key columns with their indexes set to
+// their position in a row holding only the primary key values.
+func pkColsInOrder(n int, comparePKs []compareColInfo) []compareColInfo {
+	cols := make([]compareColInfo, n)
+	for i := range cols {
+		cols[i] = comparePKs[i]
+		cols[i].colIndex = i
+	}
+	return cols
+}
+
+// diffRangeRows compares the rows of the range one by one, and adds the
+// differences to the report in the same way as the row streaming diff.
+func (td *tableDiffer) diffRangeRows(ctx context.Context, dbClient binlogplayer.DBClient, cp *checksumPlan, r pkRange, sourceCount, targetCount int64, dr *DiffReport, coreOpts *tabletmanagerdatapb.VDiffCoreOptions, reportOpts *tabletmanagerdatapb.VDiffReportOptions) error {
+	var sourceRows [][]sqltypes.Value
+	for _, source := range td.wd.ct.sources {
+		qr, err := td.executeFetch(ctx, source.tablet, rowsQuery(cp.sourceSelect, cp.sourcePKs, r, cp.keyRangeColumns()...), sourceCount)
+		if err != nil {
+			return err
+		}
+		rows := qr.Rows
+		if cp.keyRange != nil {
+			if rows, err = cp.keyRange.filter(ctx, rows); err != nil {
+				return err
+			}
+		}
+		sourceRows = append(sourceRows, rows...)
+	}
+	if len(td.wd.ct.sources) > 1 {
+		var sortErr error
+		sort.SliceStable(sourceRows, func(i, j int) bool {
+			c, err := td.compare(sourceRows[i], sourceRows[j], td.tablePlan.comparePKs, false)
+			if err != nil && sortErr == nil {
+				sortErr = err
+			}
+			return c < 0
+		})
+		if sortErr != nil {
+			return sortErr
+		}
+	}
+	qr, err := td.executeFetch(ctx, td.wd.ct.targetShardStreamer.tablet, rowsQuery(cp.targetSelect, cp.targetPKs, r), targetCount)
+	if err != nil {
+		return err
+	}
+	targetRows := qr.Rows
+
+	maxExtraRowsToCompare := coreOpts.GetMaxExtraRowsToCompare()
+	maxReportSampleRows := reportOpts.GetMaxSampleRows()
+	for len(sourceRows) > 0 || len(targetRows) > 0 {
+		dr.ProcessedRows++
+		c := 0
+		switch {
+		case len(targetRows) == 0:
+			c = -1
+		case len(sourceRows) == 0:
+			c = 1
+		default:
+			if c, err = td.compare(sourceRows[0], targetRows[0], td.tablePlan.comparePKs, false); err != nil {
+				return err
+			}
+		}
+		switch {
+		case c < 0:
+			if dr.ExtraRowsSource < maxExtraRowsToCompare {
+				diffRow, err := td.genRowDiff(td.tablePlan.sourceQuery, sourceRows[0], reportOpts)
+				if err != nil {
+					return vterrors.Wrap(err, "unexpected error generating diff")
+				}
+				dr.ExtraRowsSourceDiffs = append(dr.ExtraRowsSourceDiffs, diffRow)
+			}
+			if err := td.recordRepair(dbClient, repairInsert, sourceRows[0]); err != nil {
+				return err
+			}
+			dr.ExtraRowsSource++
+			sourceRows = sourceRows[1:]
+			continue
+		case c > 0:
+			if dr.ExtraRowsTarget < maxExtraRowsToCompare {
+				diffRow, err := td.genRowDiff(td.tablePlan.targetQuery, targetRows[0], reportOpts)
+				if err != nil {
+					return vterrors.Wrap(err, "unexpected error generating diff")
+				}
+				dr.ExtraRowsTargetDiffs = append(dr.ExtraRowsTargetDiffs, diffRow)
+			}
+			if err := td.recordRepair(dbClient, repairDelete, targetRows[0]); err != nil {
+				return err
+			}
+			dr.ExtraRowsTarget++
+			targetRows = targetRows[1:]
+			continue
+		}
+
+		c, err = td.compare(sourceRows[0], targetRows[0], td.tablePlan.compareCols, true)
+		switch {
+		case err != nil:
+			return err
+		case c != 0:
+			if maxReportSampleRows == 0 || dr.MismatchedRows < maxReportSampleRows {
+				sourceDiffRow, err := td.genRowDiff(td.tablePlan.targetQuery, sourceRows[0], reportOpts)
+				if err != nil {
+					return vterrors.Wrap(err, "unexpected error generating diff")
+				}
+				targetDiffRow, err := td.genRowDiff(td.tablePlan.targetQuery, targetRows[0], reportOpts)
+				if err != nil {
+					return vterrors.Wrap(err, "unexpected error generating diff")
+				}
+				dr.MismatchedRowsDiffs = append(dr.MismatchedRowsDiffs, &DiffMismatch{Source: sourceDiffRow, Target: targetDiffRow})
+			}
+			if err := td.recordRepair(dbClient, repairUpdate, sourceRows[0]); err != nil {
+				return err
+			}
+			dr.MismatchedRows++
+		default:
+			dr.MatchingRows++
+		}
+		sourceRows = sourceRows[1:]
+		targetRows = targetRows[1:]
+	}
+	return nil
+}
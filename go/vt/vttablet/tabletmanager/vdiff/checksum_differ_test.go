@@ -0,0 +1,290 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func TestChecksumQueries(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+	parseSelect := func(query string) *sqlparser.Select {
+		statement, err := parser.Parse(query)
+		require.NoError(t, err)
+		return statement.(*sqlparser.Select)
+	}
+
+	sel := parseSelect("select c1, c2 + 1 as c2 from t1 where c3 = 'a'")
+	pks := []sqlparser.Expr{sel.GetColumns()[0].(*sqlparser.AliasedExpr).Expr}
+	multiSel := parseSelect("select c1, c2, c3 from t1")
+	multiPKs := []sqlparser.Expr{
+		multiSel.GetColumns()[0].(*sqlparser.AliasedExpr).Expr,
+		multiSel.GetColumns()[1].(*sqlparser.AliasedExpr).Expr,
+	}
+	bounded := pkRange{
+		lo: []sqltypes.Value{sqltypes.NewInt64(1)},
+		hi: []sqltypes.Value{sqltypes.NewInt64(10)},
+	}
+	multiBounded := pkRange{
+		lo: []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("a")},
+		hi: []sqltypes.Value{sqltypes.NewInt64(10), sqltypes.NewVarChar("b")},
+	}
+
+	testCases := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "checksum of the whole table",
+			query: checksumQuery(sel, pks, pkRange{}),
+			want:  "select count(*), bit_xor(cast(conv(substr(md5(concat_ws('#', isnull(c1), cast(c1 as binary), isnull(c2 + 1), cast(c2 + 1 as binary))), 1, 16), 16, 10) as unsigned)) from t1 where (c3 = 'a')",
+		},
+		{
+			name:  "checksum of a range",
+			query: checksumQuery(multiSel, multiPKs, multiBounded),
+			want:  "select count(*), bit_xor(cast(conv(substr(md5(concat_ws('#', isnull(c1), cast(c1 as binary), isnull(c2), cast(c2 as binary), isnull(c3), cast(c3 as binary))), 1, 16), 16, 10) as unsigned)) from t1 where (c1, c2) > (1, 'a') and (c1, c2) <= (10, 'b')",
+		},
+		{
+			name:  "boundary",
+			query: boundaryQuery(sel, pks, pkRange{lo: bounded.lo}, 99),
+			want:  "select c1 from t1 where c1 > 1 and (c3 = 'a') order by c1 limit 99, 1",
+		},
+		{
+			name:  "rows",
+			query: rowsQuery(multiSel, multiPKs, multiBounded),
+			want:  "select c1, c2, c3 from t1 where (c1, c2) > (1, 'a') and (c1, c2) <= (10, 'b') order by c1, c2",
+		},
+		{
+			name:  "checksum of rows by primary key",
+			query: checksumQuery(multiSel, multiPKs, pkRange{pks: [][]sqltypes.Value{multiBounded.lo, multiBounded.hi}}),
+			want:  "select count(*), bit_xor(cast(conv(substr(md5(concat_ws('#', isnull(c1), cast(c1 as binary), isnull(c2), cast(c2 as binary), isnull(c3), cast(c3 as binary))), 1, 16), 16, 10) as unsigned)) from t1 where (c1, c2) in ((1, 'a'), (10, 'b'))",
+		},
+		{
+			name:  "rows by primary key with filter",
+			query: rowsQuery(sel, pks, pkRange{pks: [][]sqltypes.Value{bounded.lo, bounded.hi}}),
+			want:  "select c1, c2 + 1 as c2 from t1 where c1 in (1, 10) and (c3 = 'a') order by c1",
+		},
+		{
+			name:  "rows with filter",
+			query: rowsQuery(sel, pks, bounded),
+			want:  "select c1, c2 + 1 as c2 from t1 where c1 > 1 and c1 <= 10 and (c3 = 'a') order by c1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.query)
+			// The queries must be valid.
+			_, err := parser.Parse(tc.query)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRangeChecksumAdd(t *testing.T) {
+	var rc rangeChecksum
+	rc.add(rangeChecksum{count: 2, checksum: 0b1100})
+	rc.add(rangeChecksum{count: 3, checksum: 0b1010})
+	assert.Equal(t, rangeChecksum{count: 5, checksum: 0b0110}, rc)
+}
+
+// checksumTMClient returns the results of the queries that a checksum vdiff
+// runs on the tablets.
+type checksumTMClient struct {
+	tmclient.TabletManagerClient
+	results map[string]*sqltypes.Result
+}
+
+func (tmc *checksumTMClient) setResult(tablet *topodatapb.Tablet, query string, result *sqltypes.Result) {
+	tmc.results[fmt.Sprintf("%d:%s", tablet.Alias.Uid, query)] = result
+}
+
+func (tmc *checksumTMClient) ExecuteFetchAsApp(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsAppRequest) (*querypb.QueryResult, error) {
+	result, ok := tmc.results[fmt.Sprintf("%d:%s", tablet.Alias.Uid, req.Query)]
+	if !ok {
+		return nil, fmt.Errorf("query %q not found for tablet %d", req.Query, tablet.Alias.Uid)
+	}
+	return sqltypes.ResultToProto3(result), nil
+}
+
+func TestChecksumDiffRange(t *testing.T) {
+	sourceTablet := &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200}, Type: topodatapb.TabletType_REPLICA}
+	targetTablet := &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}, Type: topodatapb.TabletType_REPLICA}
+	fields := sqltypes.MakeTestFields("c1|c2", "int64|varchar")
+	checksumFields := sqltypes.MakeTestFields("count(*)|checksum", "int64|uint64")
+	query := "select c1, c2 from t1 order by c1"
+
+	newTableDiffer := func(tmc *checksumTMClient) *tableDiffer {
+		ct := &controller{
+			vde:                      &Engine{parser: sqlparser.NewTestParser()},
+			tmc:                      tmc,
+			sources:                  map[string]*migrationSource{"0": {shardStreamer: &shardStreamer{tablet: sourceTablet, shard: "0"}}},
+			targetShardStreamer:      &shardStreamer{tablet: targetTablet, shard: "0"},
+			TableChecksumRangeCounts: stats.NewCountersWithSingleLabel("", "", "Ranges"),
+		}
+		return &tableDiffer{
+			wd: &workflowDiffer{
+				ct:           ct,
+				opts:         &tabletmanagerdatapb.VDiffOptions{CoreOptions: &tabletmanagerdatapb.VDiffCoreOptions{}},
+				collationEnv: collations.MySQL8(),
+			},
+			table:       &tabletmanagerdatapb.TableDefinition{Name: "t1"},
+			sourceQuery: query,
+			tablePlan: &tablePlan{
+				sourceQuery: query,
+				targetQuery: query,
+				comparePKs:  []compareColInfo{{colIndex: 0, isPK: true, colName: "c1"}},
+				compareCols: []compareColInfo{{colIndex: 0, isPK: true, colName: "c1"}, {colIndex: 1, colName: "c2"}},
+				selectPks:   []int{0},
+			},
+		}
+	}
+	coreOpts := &tabletmanagerdatapb.VDiffCoreOptions{MaxExtraRowsToCompare: 10}
+	reportOpts := &tabletmanagerdatapb.VDiffReportOptions{OnlyPks: true}
+
+	t.Run("matching range", func(t *testing.T) {
+		tmc := &checksumTMClient{results: make(map[string]*sqltypes.Result)}
+		td := newTableDiffer(tmc)
+		cp, err := td.buildChecksumPlan()
+		require.NoError(t, err)
+		r := pkRange{hi: []sqltypes.Value{sqltypes.NewInt64(3)}}
+		checksum := checksumQuery(cp.targetSelect, cp.targetPKs, r)
+		tmc.setResult(sourceTablet, checksum, sqltypes.MakeTestResult(checksumFields, "3|42"))
+		tmc.setResult(targetTablet, checksum, sqltypes.MakeTestResult(checksumFields, "3|42"))
+
+		dr := &DiffReport{}
+		require.NoError(t, td.diffRange(context.Background(), binlogplayer.NewMockDBClient(t), cp, r, dr, coreOpts, reportOpts))
+		assert.Equal(t, &DiffReport{ProcessedRows: 3, MatchingRows: 3}, dr)
+		assert.Equal(t, map[string]int64{"t1.matching": 1}, td.wd.ct.TableChecksumRangeCounts.Counts())
+	})
+
+	t.Run("mismatched range falls back to comparing rows", func(t *testing.T) {
+		tmc := &checksumTMClient{results: make(map[string]*sqltypes.Result)}
+		td := newTableDiffer(tmc)
+		cp, err := td.buildChecksumPlan()
+		require.NoError(t, err)
+		r := pkRange{lo: []sqltypes.Value{sqltypes.NewInt64(0)}}
+		checksum := checksumQuery(cp.targetSelect, cp.targetPKs, r)
+		rows := rowsQuery(cp.targetSelect, cp.targetPKs, r)
+		tmc.setResult(sourceTablet, checksum, sqltypes.MakeTestResult(checksumFields, "3|42"))
+		tmc.setResult(targetTablet, checksum, sqltypes.MakeTestResult(checksumFields, "3|43"))
+		tmc.setResult(sourceTablet, rows, sqltypes.MakeTestResult(fields, "1|a", "2|b", "3|c"))
+		tmc.setResult(targetTablet, rows, sqltypes.MakeTestResult(fields, "1|a", "2|x", "4|d"))
+
+		dr := &DiffReport{}
+		require.NoError(t, td.diffRange(context.Background(), binlogplayer.NewMockDBClient(t), cp, r, dr, coreOpts, reportOpts))
+		assert.Equal(t, int64(4), dr.ProcessedRows)
+		assert.Equal(t, int64(1), dr.MatchingRows)
+		assert.Equal(t, int64(1), dr.MismatchedRows)
+		assert.Equal(t, int64(1), dr.ExtraRowsSource)
+		assert.Equal(t, int64(1), dr.ExtraRowsTarget)
+		require.Len(t, dr.MismatchedRowsDiffs, 1)
+		assert.Equal(t, map[string]string{"c1": "2"}, dr.MismatchedRowsDiffs[0].Source.Row)
+		require.Len(t, dr.ExtraRowsSourceDiffs, 1)
+		assert.Equal(t, map[string]string{"c1": "3"}, dr.ExtraRowsSourceDiffs[0].Row)
+		require.Len(t, dr.ExtraRowsTargetDiffs, 1)
+		assert.Equal(t, map[string]string{"c1": "4"}, dr.ExtraRowsTargetDiffs[0].Row)
+		assert.Equal(t, map[string]int64{"t1.mismatched": 1}, td.wd.ct.TableChecksumRangeCounts.Counts())
+	})
+
+	t.Run("unsupported filters fall back to streaming rows", func(t *testing.T) {
+		td := newTableDiffer(&checksumTMClient{})
+		reason, err := td.checksumUnsupportedReason()
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+
+		td.sourceQuery = "select c1, c2 from t1 where c2 = 'a' and in_keyrange('-80')"
+		reason, err = td.checksumUnsupportedReason()
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+
+		td.sourceQuery = "select c1, c2 from t1 group by c1, c2"
+		reason, err = td.checksumUnsupportedReason()
+		require.NoError(t, err)
+		assert.Equal(t, "the workflow filter has a group by clause", reason)
+	})
+	t.Run("key range filter", func(t *testing.T) {
+		ctx := context.Background()
+		ts := memorytopo.NewServer(ctx, "zone1")
+		defer ts.Close()
+		require.NoError(t, ts.SaveVSchema(ctx, &topo.KeyspaceVSchemaInfo{Name: "ks", Keyspace: &vschemapb.Keyspace{
+			Sharded:  true,
+			Vindexes: map[string]*vschemapb.Vindex{"hash": {Type: "hash"}},
+			Tables: map[string]*vschemapb.Table{
+				"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "c1", Name: "hash"}}},
+			},
+		}}))
+		tmc := &checksumTMClient{results: make(map[string]*sqltypes.Result)}
+		td := newTableDiffer(tmc)
+		td.wd.ct.ts = ts
+		td.wd.ct.sourceKeyspace = "ks"
+		td.sourceQuery = "select c1, c2 from t1 where c2 != 'z' and in_keyrange('-80')"
+		td.tablePlan.sourceQuery = "select c1, c2 from t1 where c2 != 'z' and in_keyrange('-80') order by c1"
+
+		reason, err := td.checksumUnsupportedReason()
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+		cp, err := td.buildChecksumPlan()
+		require.NoError(t, err)
+		assert.Equal(t, "select c1, c2 from t1 where c2 != 'z'", sqlparser.String(cp.sourceSelect))
+		require.NoError(t, td.buildKeyRangeFilter(ctx, cp))
+		assert.Equal(t, []sqlparser.Expr{sqlparser.NewColName("c1")}, cp.keyRangeColumns())
+
+		// The keyspace ids of 1, 2 and 3 are in -80, that of 4 is not.
+		r := pkRange{lo: []sqltypes.Value{sqltypes.NewInt64(0)}}
+		tmc.setResult(sourceTablet, checksumQuery(cp.sourceSelect, cp.sourcePKs, r), sqltypes.MakeTestResult(checksumFields, "4|15"))
+		tmc.setResult(sourceTablet, rowChecksumsQuery(cp.sourceSelect, cp.sourcePKs, r, cp.keyRangeColumns()...),
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("c1|checksum|c1", "int64|uint64|int64"), "1|1|1", "2|2|2", "3|4|3", "4|8|4"))
+		tmc.setResult(sourceTablet, rowsQuery(cp.sourceSelect, cp.sourcePKs, r, cp.keyRangeColumns()...),
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("c1|c2|c1", "int64|varchar|int64"), "1|a|1", "2|b|2", "3|c|3", "4|d|4"))
+		targetChecksum := checksumQuery(cp.targetSelect, cp.targetPKs, r)
+
+		tmc.setResult(targetTablet, targetChecksum, sqltypes.MakeTestResult(checksumFields, "3|7"))
+		dr := &DiffReport{}
+		require.NoError(t, td.diffRange(ctx, binlogplayer.NewMockDBClient(t), cp, r, dr, coreOpts, reportOpts))
+		assert.Equal(t, &DiffReport{ProcessedRows: 3, MatchingRows: 3}, dr)
+
+		tmc.setResult(targetTablet, targetChecksum, sqltypes.MakeTestResult(checksumFields, "3|6"))
+		tmc.setResult(targetTablet, rowsQuery(cp.targetSelect, cp.targetPKs, r), sqltypes.MakeTestResult(fields, "1|a", "2|b", "3|x"))
+		dr = &DiffReport{}
+		require.NoError(t, td.diffRange(ctx, binlogplayer.NewMockDBClient(t), cp, r, dr, coreOpts, reportOpts))
+		assert.Equal(t, int64(3), dr.ProcessedRows)
+		assert.Equal(t, int64(2), dr.MatchingRows)
+		assert.Equal(t, int64(1), dr.MismatchedRows)
+		assert.Zero(t, dr.ExtraRowsSource)
+		assert.Zero(t, dr.ExtraRowsTarget)
+	})
+}
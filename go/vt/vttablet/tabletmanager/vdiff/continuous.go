@@ -0,0 +1,619 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// continuousMaxChangedRows is the number of changed rows of a table that
+	// are tracked between two verifications of a continuous vdiff. When more
+	// rows of the table change, the whole table is verified instead.
+	continuousMaxChangedRows = 100000
+	// continuousBatchSize is the number of changed rows whose checksums are
+	// compared at once, and the number of rows of the ranges a table is
+	// verified in when it is verified entirely.
+	continuousBatchSize = 1000
+)
+
+// trackedTable is a table whose changed rows are tracked, from the source
+// table that the workflow copies it from.
+type trackedTable struct {
+	name        string
+	sourceTable string
+	// sourcePKs are the source columns of the primary key of the table, in
+	// the order of its comparePKs.
+	sourcePKs []string
+}
+
+// changeTracker collects the primary keys of the rows changed on the source
+// shards of the workflow, by streaming their binary logs from the last
+// checkpoint of a continuous vdiff, which starts from the positions of the
+// workflow streams. The changes that the workflow failed to apply are thus
+// verified too.
+type changeTracker struct {
+	// tables holds the tracked tables by source table.
+	tables map[string]*trackedTable
+
+	mu sync.Mutex
+	// pos is, by source shard, the position of the last committed
+	// transaction whose changes were collected, and pendingPos the position
+	// of the transaction being streamed.
+	pos, pendingPos map[string]string
+	// fields holds the fields of the source tables by source shard.
+	fields map[string]map[string][]*querypb.Field
+	// changed holds the primary keys of the changed rows by table, keyed by
+	// their encoding.
+	changed map[string]map[string][]sqltypes.Value
+	// overflowed holds the tables with more than continuousMaxChangedRows
+	// changed rows.
+	overflowed map[string]bool
+}
+
+func newChangeTracker(tables []*trackedTable, pos map[string]string) *changeTracker {
+	tr := &changeTracker{
+		tables:     make(map[string]*trackedTable, len(tables)),
+		pos:        maps.Clone(pos),
+		pendingPos: make(map[string]string),
+		fields:     make(map[string]map[string][]*querypb.Field),
+		changed:    make(map[string]map[string][]sqltypes.Value),
+		overflowed: make(map[string]bool),
+	}
+	for _, table := range tables {
+		tr.tables[table.sourceTable] = table
+	}
+	return tr
+}
+
+// filter returns the filter selecting the primary key columns of the source
+// tables of the tracked tables.
+func (tr *changeTracker) filter() *binlogdatapb.Filter {
+	filter := &binlogdatapb.Filter{}
+	for _, name := range slices.Sorted(maps.Keys(tr.tables)) {
+		buf := sqlparser.NewTrackedBuffer(nil)
+		buf.WriteString("select ")
+		for i, pk := range tr.tables[name].sourcePKs {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.Myprintf("%v", sqlparser.NewIdentifierCI(pk))
+		}
+		buf.Myprintf(" from %v", sqlparser.NewIdentifierCS(name))
+		filter.Rules = append(filter.Rules, &binlogdatapb.Rule{Match: name, Filter: buf.String()})
+	}
+	return filter
+}
+
+// stream streams the binary log of the tablet of the source shard from the
+// position of the tracker, until the context is done.
+func (tr *changeTracker) stream(ctx context.Context, shard string, tablet *topodatapb.Tablet) error {
+	conn, err := tabletconn.GetDialer()(ctx, tablet, false)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	tr.mu.Lock()
+	pos, ok := tr.pos[shard]
+	tr.mu.Unlock()
+	if !ok {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no position found for source shard %s", shard)
+	}
+	req := &binlogdatapb.VStreamRequest{
+		Target: &querypb.Target{
+			Keyspace:   tablet.Keyspace,
+			Shard:      tablet.Shard,
+			TabletType: tablet.Type,
+		},
+		Position: pos,
+		Filter:   tr.filter(),
+	}
+	return conn.VStream(ctx, req, func(events []*binlogdatapb.VEvent) error {
+		return tr.process(shard, events)
+	})
+}
+
+// process collects the primary keys of the rows changed by the events of the
+// source shard.
+func (tr *changeTracker) process(shard string, events []*binlogdatapb.VEvent) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	fields, ok := tr.fields[shard]
+	if !ok {
+		fields = make(map[string][]*querypb.Field)
+		tr.fields[shard] = fields
+	}
+	for _, event := range events {
+		switch event.Type {
+		case binlogdatapb.VEventType_FIELD:
+			fields[event.FieldEvent.TableName] = event.FieldEvent.Fields
+		case binlogdatapb.VEventType_ROW:
+			sourceTable := event.RowEvent.TableName
+			table, ok := tr.tables[sourceTable]
+			if !ok {
+				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected rows received for table %s", sourceTable)
+			}
+			tableFields, ok := fields[sourceTable]
+			if !ok {
+				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no fields received for the rows of table %s", sourceTable)
+			}
+			for _, change := range event.RowEvent.RowChanges {
+				if change.Before != nil {
+					tr.add(table.name, sqltypes.MakeRowTrusted(tableFields, change.Before))
+				}
+				if change.After != nil {
+					tr.add(table.name, sqltypes.MakeRowTrusted(tableFields, change.After))
+				}
+			}
+		case binlogdatapb.VEventType_GTID:
+			tr.pendingPos[shard] = event.Gtid
+		case binlogdatapb.VEventType_COMMIT:
+			// The position only moves forward once all the changes of the
+			// transaction have been collected.
+			if pos := tr.pendingPos[shard]; pos != "" {
+				tr.pos[shard] = pos
+			}
+		}
+	}
+	return nil
+}
+
+// add adds the primary key of a changed row of the table.
+func (tr *changeTracker) add(table string, pk []sqltypes.Value) {
+	if tr.overflowed[table] {
+		return
+	}
+	changed, ok := tr.changed[table]
+	if !ok {
+		changed = make(map[string][]sqltypes.Value)
+		tr.changed[table] = changed
+	}
+	changed[encodeValues(pk)] = pk
+	if len(changed) > continuousMaxChangedRows {
+		tr.overflowed[table] = true
+		delete(tr.changed, table)
+	}
+}
+
+// take returns the primary keys of the rows changed by table, the tables with
+// too many changed rows, and the positions up to which the changes were
+// collected, and then resets the changes. The changes of a transaction being
+// streamed may already have been collected, which only means that they are
+// verified again after the next checkpoint.
+func (tr *changeTracker) take() (map[string][][]sqltypes.Value, map[string]bool, map[string]string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	changed := make(map[string][][]sqltypes.Value, len(tr.changed))
+	for table, pks := range tr.changed {
+		changed[table] = slices.Collect(maps.Values(pks))
+	}
+	overflowed := tr.overflowed
+	tr.changed = make(map[string]map[string][]sqltypes.Value)
+	tr.overflowed = make(map[string]bool)
+	return changed, overflowed, maps.Clone(tr.pos)
+}
+
+// encodeValues returns a string uniquely encoding the values, e.g. of a
+// primary key or of a row.
+func encodeValues(pk []sqltypes.Value) string {
+	var sb strings.Builder
+	for _, val := range pk {
+		if val.IsNull() {
+			sb.WriteString("-")
+			continue
+		}
+		fmt.Fprintf(&sb, "%d:", len(val.Raw()))
+		sb.Write(val.Raw())
+	}
+	return sb.String()
+}
+
+// getContinuousPos returns the last checkpoint of the continuous vdiff, which
+// is the position of each source shard up to which the changes have been
+// verified.
+func (wd *workflowDiffer) getContinuousPos(dbClient binlogplayer.DBClient) (map[string]string, error) {
+	query, err := sqlparser.ParseAndBind(sqlGetVDiffContinuousPos, sqltypes.Int64BindVariable(wd.ct.id))
+	if err != nil {
+		return nil, err
+	}
+	qr, err := dbClient.ExecuteFetch(query, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(qr.Rows) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "vdiff %s not found", wd.ct.uuid)
+	}
+	checkpoint := qr.Named().Row().AsBytes("continuous_pos", nil)
+	if len(checkpoint) == 0 {
+		return nil, nil
+	}
+	var pos map[string]string
+	if err := json.Unmarshal(checkpoint, &pos); err != nil {
+		return nil, vterrors.Wrapf(err, "invalid checkpoint for continuous vdiff %s", wd.ct.uuid)
+	}
+	return pos, nil
+}
+
+// saveContinuousPos saves the checkpoint of the continuous vdiff.
+func (wd *workflowDiffer) saveContinuousPos(dbClient binlogplayer.DBClient, pos map[string]string) error {
+	checkpoint, err := json.Marshal(pos)
+	if err != nil {
+		return err
+	}
+	query, err := sqlparser.ParseAndBind(sqlUpdateVDiffContinuousPos,
+		sqltypes.BytesBindVariable(checkpoint),
+		sqltypes.Int64BindVariable(wd.ct.id),
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := dbClient.ExecuteFetch(query, 1); err != nil {
+		return err
+	}
+	wd.ct.ContinuousCheckpoint.Set(time.Now().Unix())
+	return nil
+}
+
+// initContinuousPos saves the positions of the workflow streams as the first
+// checkpoint of the continuous vdiff, before its tables are diffed for the
+// first time. The rows changed on the source shards after that are verified
+// again once the tables have been diffed.
+func (wd *workflowDiffer) initContinuousPos(ctx context.Context, dbClient binlogplayer.DBClient) error {
+	pos, err := wd.getContinuousPos(dbClient)
+	if err != nil || pos != nil {
+		return err
+	}
+	query := sqlparser.BuildParsedQuery(sqlGetVReplicationEntry, wd.ct.workflowFilter)
+	qr, err := dbClient.ExecuteFetch(query.Query, -1)
+	if err != nil {
+		return err
+	}
+	pos = make(map[string]string, len(qr.Rows))
+	for _, row := range qr.Named().Rows {
+		var bls binlogdatapb.BinlogSource
+		if err := prototext.Unmarshal(row.AsBytes("source", nil), &bls); err != nil {
+			return err
+		}
+		streamPos := row.AsString("pos", "")
+		if streamPos == "" {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the stream of workflow %s from source shard %s has not started",
+				wd.ct.workflow, bls.Shard)
+		}
+		pos[bls.Shard] = streamPos
+	}
+	return wd.saveContinuousPos(dbClient, pos)
+}
+
+// trackedTable returns the table with its source table and source primary
+// key columns, or the reason why its changed rows cannot be tracked.
+func (td *tableDiffer) trackedTable() (*trackedTable, string, error) {
+	statement, err := td.wd.ct.vde.parser.Parse(td.tablePlan.sourceQuery)
+	if err != nil {
+		return nil, "", err
+	}
+	sel, ok := statement.(*sqlparser.Select)
+	if !ok {
+		return nil, "", fmt.Errorf("unexpected: %v", sqlparser.String(statement))
+	}
+	var tableName sqlparser.TableName
+	if len(sel.From) == 1 {
+		if aliased, ok := sel.From[0].(*sqlparser.AliasedTableExpr); ok {
+			tableName, _ = aliased.TableName()
+		}
+	}
+	if tableName.IsEmpty() {
+		return nil, "the workflow filter does not select from a single source table", nil
+	}
+	table := &trackedTable{name: td.table.Name, sourceTable: tableName.Name.String()}
+	for _, pk := range td.tablePlan.comparePKs {
+		col, ok := sel.GetColumns()[pk.colIndex].(*sqlparser.AliasedExpr).Expr.(*sqlparser.ColName)
+		if !ok {
+			return nil, "the primary key is not made of source columns", nil
+		}
+		table.sourcePKs = append(table.sourcePKs, col.Name.String())
+	}
+	return table, "", nil
+}
+
+// diffContinuously keeps verifying the workflow once its tables have been
+// diffed, until the vdiff is stopped. The primary keys of the rows changed
+// since the last checkpoint are tracked from the binary logs of the source
+// shards and, every interval, the checksums of those rows are compared on
+// the source and target tablets and the checkpoint is moved forward.
+func (wd *workflowDiffer) diffContinuously(ctx context.Context, dbClient binlogplayer.DBClient, interval time.Duration) error {
+	tables := make(map[string]*tableDiffer, len(wd.tableDiffers))
+	var tracked []*trackedTable
+	// sourceTables holds the tracked tables by source table, as the changes of
+	// a source table can only be tracked for one of them.
+	sourceTables := make(map[string]string)
+	for _, name := range slices.Sorted(maps.Keys(wd.tableDiffers)) {
+		td := wd.tableDiffers[name]
+		reason, err := td.checksumUnsupportedReason()
+		if err != nil {
+			return err
+		}
+		var table *trackedTable
+		if reason == "" {
+			if table, reason, err = td.trackedTable(); err != nil {
+				return err
+			}
+		}
+		if reason == "" && sourceTables[table.sourceTable] != "" {
+			reason = fmt.Sprintf("its source table %s is also the source table of %s", table.sourceTable, sourceTables[table.sourceTable])
+		}
+		if reason != "" {
+			log.Infof("Not verifying table %s continuously for vdiff %s, as %s", name, wd.ct.uuid, reason)
+			insertVDiffLog(ctx, dbClient, wd.ct.id, fmt.Sprintf("Table %s is not verified continuously, as %s", encodeString(name), reason))
+			continue
+		}
+		sourceTables[table.sourceTable] = name
+		tables[name] = td
+		tracked = append(tracked, table)
+	}
+	if len(tables) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "none of the tables of workflow %s can be verified continuously", wd.ct.workflow)
+	}
+	pos, err := wd.getContinuousPos(dbClient)
+	if err != nil {
+		return err
+	}
+	if pos == nil {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no checkpoint found for continuous vdiff %s", wd.ct.uuid)
+	}
+	// The same source tablets are used for the whole verification. The rows
+	// are read on the target primary, which the workflow writes to.
+	if err := tables[tracked[0].name].selectTablets(ctx); err != nil {
+		return err
+	}
+	wd.ct.targetShardStreamer.tablet = wd.ct.vde.thisTablet
+
+	tr := newChangeTracker(tracked, pos)
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	streamErr := make(chan error, len(wd.ct.sources))
+	for shard, source := range wd.ct.sources {
+		go func() {
+			err := tr.stream(streamCtx, shard, source.tablet)
+			if err == nil {
+				err = vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "stream ended")
+			}
+			streamErr <- vterrors.Wrapf(err, "failed to stream the changes from source shard %s", shard)
+		}()
+	}
+
+	log.Infof("Verifying workflow %s continuously for vdiff %s from positions %v, every %v", wd.ct.workflow, wd.ct.uuid, pos, interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired")
+		case <-wd.ct.done:
+			return ErrVDiffStoppedByUser
+		case err := <-streamErr:
+			return err
+		case <-ticker.C:
+		}
+
+		changed, overflowed, pos := tr.take()
+		for _, name := range slices.Sorted(maps.Keys(tables)) {
+			if len(changed[name]) == 0 && !overflowed[name] {
+				continue
+			}
+			if err := tables[name].verifyChanges(ctx, dbClient, changed[name], overflowed[name]); err != nil {
+				return err
+			}
+		}
+		if err := wd.saveContinuousPos(dbClient, pos); err != nil {
+			return err
+		}
+	}
+}
+
+// verifyChanges compares the checksums of the rows with the given primary
+// keys, or of all the rows of the table, on the source and target tablets,
+// and reports the differences found in the vdiff logs and metrics. The
+// workflow and replication keep running: the rows whose checksums differ are
+// compared again once the workflow has caught up with the source tablets, and
+// only those that still differ are reported.
+func (td *tableDiffer) verifyChanges(ctx context.Context, dbClient binlogplayer.DBClient, pks [][]sqltypes.Value, all bool) error {
+	cp, err := td.buildChecksumPlan()
+	if err != nil {
+		return err
+	}
+	if err := td.buildKeyRangeFilter(ctx, cp); err != nil {
+		return err
+	}
+	dr := &DiffReport{TableName: td.table.Name}
+	var suspects [][]sqltypes.Value
+	findSuspects := func(r pkRange) error {
+		matching, rangeSuspects, err := td.findSuspects(ctx, cp, r)
+		if err != nil {
+			return err
+		}
+		dr.ProcessedRows += matching
+		dr.MatchingRows += matching
+		suspects = append(suspects, rangeSuspects...)
+		return nil
+	}
+	if all {
+		log.Infof("Verifying all the rows of table %s for continuous vdiff %s, as too many of them changed", td.table.Name, td.wd.ct.uuid)
+		var lo []sqltypes.Value
+		for {
+			qr, err := td.executeFetch(ctx, td.wd.ct.targetShardStreamer.tablet, boundaryQuery(cp.targetSelect, cp.targetPKs, pkRange{lo: lo}, continuousBatchSize-1), 1)
+			if err != nil {
+				return err
+			}
+			var hi []sqltypes.Value
+			if len(qr.Rows) > 0 {
+				hi = qr.Rows[0]
+			}
+			if err := findSuspects(pkRange{lo: lo, hi: hi}); err != nil {
+				return err
+			}
+			if hi == nil {
+				break
+			}
+			lo = hi
+		}
+	} else {
+		for batch := range slices.Chunk(pks, continuousBatchSize) {
+			if err := findSuspects(pkRange{pks: batch}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(suspects) > 0 {
+		td.waitForCatchUp(ctx)
+		pks, suspects = suspects, nil
+		for batch := range slices.Chunk(pks, continuousBatchSize) {
+			if err := findSuspects(pkRange{pks: batch}); err != nil {
+				return err
+			}
+		}
+		for batch := range slices.Chunk(suspects, continuousBatchSize) {
+			if err := td.diffRange(ctx, dbClient, cp, pkRange{pks: batch}, dr, td.wd.opts.CoreOptions, td.wd.opts.ReportOptions); err != nil {
+				return err
+			}
+		}
+	}
+	if err := td.flushRepairs(dbClient); err != nil {
+		return err
+	}
+
+	counts := td.wd.ct.ContinuousRowCounts
+	counts.Add(td.table.Name+".verified", dr.ProcessedRows)
+	if dr.MismatchedRows == 0 && dr.ExtraRowsSource == 0 && dr.ExtraRowsTarget == 0 {
+		return nil
+	}
+	counts.Add(td.table.Name+".mismatched", dr.MismatchedRows)
+	counts.Add(td.table.Name+".extra_source", dr.ExtraRowsSource)
+	counts.Add(td.table.Name+".extra_target", dr.ExtraRowsTarget)
+	log.Warningf("Continuous vdiff %s found differences in table %s: %+v", td.wd.ct.uuid, td.table.Name, dr)
+	insertVDiffLog(ctx, dbClient, td.wd.ct.id, fmt.Sprintf("Verifying the changed rows of table %s found %d mismatched rows, %d extra rows on the source and %d extra rows on the target",
+		encodeString(td.table.Name), dr.MismatchedRows, dr.ExtraRowsSource, dr.ExtraRowsTarget))
+	return updateTableMismatch(dbClient, td.wd.ct.id, td.table.Name)
+}
+
+// findSuspects compares the checksums of the rows of the range on the source
+// and target tablets, and returns the number of matching rows and the primary
+// keys of the rows whose checksums differ. As the workflow is running, these
+// rows may only be lagging behind on the target.
+func (td *tableDiffer) findSuspects(ctx context.Context, cp *checksumPlan, r pkRange) (int64, [][]sqltypes.Value, error) {
+	targetTablet := td.wd.ct.targetShardStreamer.tablet
+	var sourceChecksum rangeChecksum
+	sourceRows := make(map[*topodatapb.Tablet]int64)
+	for _, source := range td.wd.ct.sources {
+		rc, rows, err := td.fetchSourceChecksum(ctx, source.tablet, cp, r)
+		if err != nil {
+			return 0, nil, err
+		}
+		sourceChecksum.add(rc)
+		sourceRows[source.tablet] = rows
+	}
+	targetChecksum, err := td.fetchChecksum(ctx, targetTablet, checksumQuery(cp.targetSelect, cp.targetPKs, r))
+	if err != nil {
+		return 0, nil, err
+	}
+	if sourceChecksum == targetChecksum {
+		return targetChecksum.count, nil, nil
+	}
+
+	// addRowChecksums adds the checksums of the rows, which hold the primary
+	// key and the checksum of a row, by encoded primary key.
+	pkCount := len(td.tablePlan.comparePKs)
+	addRowChecksums := func(rows [][]sqltypes.Value, checksums map[string]string, pkValues map[string][]sqltypes.Value) {
+		for _, row := range rows {
+			key := encodeValues(row[:pkCount])
+			checksums[key] = row[pkCount].ToString()
+			pkValues[key] = row[:pkCount]
+		}
+	}
+	sourceChecksums, targetChecksums := make(map[string]string), make(map[string]string)
+	pkValues := make(map[string][]sqltypes.Value)
+	for _, source := range td.wd.ct.sources {
+		rows, err := td.fetchSourceRowChecksums(ctx, source.tablet, cp, r, sourceRows[source.tablet])
+		if err != nil {
+			return 0, nil, err
+		}
+		addRowChecksums(rows, sourceChecksums, pkValues)
+	}
+	qr, err := td.executeFetch(ctx, targetTablet, rowChecksumsQuery(cp.targetSelect, cp.targetPKs, r), targetChecksum.count)
+	if err != nil {
+		return 0, nil, err
+	}
+	addRowChecksums(qr.Rows, targetChecksums, pkValues)
+	var matching int64
+	var suspects [][]sqltypes.Value
+	for _, key := range slices.Sorted(maps.Keys(pkValues)) {
+		sourceRowChecksum, inSource := sourceChecksums[key]
+		targetRowChecksum, inTarget := targetChecksums[key]
+		if inSource && inTarget && sourceRowChecksum == targetRowChecksum {
+			matching++
+			continue
+		}
+		suspects = append(suspects, pkValues[key])
+	}
+	return matching, suspects, nil
+}
+
+// waitForCatchUp waits, for up to the vdiff timeout, for the workflow streams
+// to reach the current positions of the source tablets. A workflow that does
+// not catch up, e.g. because it is failing, is not waited for any longer, and
+// the rows that still differ are then reported.
+func (td *tableDiffer) waitForCatchUp(ctx context.Context) {
+	ct := td.wd.ct
+	if ct.vde.vre == nil {
+		return
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, time.Duration(ct.options.CoreOptions.TimeoutSeconds*int64(time.Second)))
+	defer cancel()
+	for _, source := range ct.sources {
+		pos, err := ct.tmc.PrimaryPosition(waitCtx, source.tablet)
+		if err == nil {
+			err = ct.vde.vre.WaitForPos(waitCtx, source.vrID, pos)
+		}
+		if err != nil {
+			log.Warningf("Workflow %s did not catch up with source tablet %s for continuous vdiff %s: %v",
+				ct.workflow, topoproto.TabletAliasString(source.tablet.Alias), ct.uuid, err)
+		}
+	}
+}
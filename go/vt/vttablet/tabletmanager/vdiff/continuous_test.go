@@ -0,0 +1,216 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestChangeTracker(t *testing.T) {
+	tables := []*trackedTable{
+		{name: "t1", sourceTable: "s1", sourcePKs: []string{"c1"}},
+		{name: "t2", sourceTable: "s2", sourcePKs: []string{"c1", "c2"}},
+	}
+	tr := newChangeTracker(tables, map[string]string{"-80": "MySQL56/pos0", "80-": "MySQL56/pos0"})
+	assert.Equal(t, &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{
+		{Match: "s1", Filter: "select c1 from s1"},
+		{Match: "s2", Filter: "select c1, c2 from s2"},
+	}}, tr.filter())
+
+	t1Fields := sqltypes.MakeTestFields("c1", "int64")
+	t2Fields := sqltypes.MakeTestFields("c1|c2", "int64|varchar")
+	rowChange := func(before, after []sqltypes.Value) *binlogdatapb.RowChange {
+		change := &binlogdatapb.RowChange{}
+		if before != nil {
+			change.Before = sqltypes.RowToProto3(before)
+		}
+		if after != nil {
+			change.After = sqltypes.RowToProto3(after)
+		}
+		return change
+	}
+	pk1 := []sqltypes.Value{sqltypes.NewInt64(1)}
+	pk2 := []sqltypes.Value{sqltypes.NewInt64(2)}
+	pkA := []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("a")}
+	pkB := []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("b")}
+
+	// Rows can't be collected before the fields of their table.
+	err := tr.process("-80", []*binlogdatapb.VEvent{{
+		Type:     binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{TableName: "s1", RowChanges: []*binlogdatapb.RowChange{rowChange(nil, pk1)}},
+	}})
+	require.Error(t, err)
+
+	err = tr.process("-80", []*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "s1", Fields: t1Fields}},
+		{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "s2", Fields: t2Fields}},
+		{Type: binlogdatapb.VEventType_BEGIN},
+		{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: "s1", RowChanges: []*binlogdatapb.RowChange{
+			rowChange(nil, pk1),
+			rowChange(pk1, pk1),
+			rowChange(pk2, nil),
+		}}},
+		{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: "s2", RowChanges: []*binlogdatapb.RowChange{
+			// The primary key is updated, so both rows are changed.
+			rowChange(pkA, pkB),
+		}}},
+		{Type: binlogdatapb.VEventType_GTID, Gtid: "MySQL56/pos1"},
+		{Type: binlogdatapb.VEventType_COMMIT},
+		{Type: binlogdatapb.VEventType_BEGIN},
+		{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: "s1", RowChanges: []*binlogdatapb.RowChange{
+			rowChange(pk2, pk2),
+		}}},
+	})
+	require.NoError(t, err)
+
+	// The position does not move past the transaction being streamed.
+	changed, overflowed, pos := tr.take()
+	assert.Equal(t, map[string]string{"-80": "MySQL56/pos1", "80-": "MySQL56/pos0"}, pos)
+	assert.Empty(t, overflowed)
+	assert.ElementsMatch(t, [][]sqltypes.Value{pk1, pk2}, changed["t1"])
+	assert.ElementsMatch(t, [][]sqltypes.Value{pkA, pkB}, changed["t2"])
+
+	err = tr.process("-80", []*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_GTID, Gtid: "MySQL56/pos2"},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	})
+	require.NoError(t, err)
+	changed, overflowed, pos = tr.take()
+	assert.Equal(t, map[string]string{"-80": "MySQL56/pos2", "80-": "MySQL56/pos0"}, pos)
+	assert.Empty(t, overflowed)
+	assert.Empty(t, changed)
+
+	// The fields are received by each source shard.
+	err = tr.process("80-", []*binlogdatapb.VEvent{{
+		Type:     binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{TableName: "s1", RowChanges: []*binlogdatapb.RowChange{rowChange(nil, pk1)}},
+	}})
+	require.Error(t, err)
+
+	// Tables with too many changed rows are verified entirely.
+	var changes []*binlogdatapb.RowChange
+	for i := range continuousMaxChangedRows + 1 {
+		changes = append(changes, rowChange(nil, []sqltypes.Value{sqltypes.NewInt64(int64(i))}))
+	}
+	err = tr.process("-80", []*binlogdatapb.VEvent{
+		{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: "s1", RowChanges: changes}},
+		{Type: binlogdatapb.VEventType_ROW, RowEvent: &binlogdatapb.RowEvent{TableName: "s2", RowChanges: []*binlogdatapb.RowChange{rowChange(nil, pkA)}}},
+	})
+	require.NoError(t, err)
+	changed, overflowed, _ = tr.take()
+	assert.Equal(t, map[string]bool{"t1": true}, overflowed)
+	assert.Equal(t, map[string][][]sqltypes.Value{"t2": {pkA}}, changed)
+}
+
+func TestEncodePK(t *testing.T) {
+	pks := [][]sqltypes.Value{
+		{sqltypes.NewInt64(1), sqltypes.NewVarChar("12")},
+		{sqltypes.NewInt64(11), sqltypes.NewVarChar("2")},
+		{sqltypes.NewInt64(1), sqltypes.NULL},
+		{sqltypes.NewInt64(1), sqltypes.NewVarChar("")},
+		{sqltypes.NewInt64(1), sqltypes.NewVarChar("-")},
+	}
+	encoded := make(map[string]bool)
+	for _, pk := range pks {
+		encoded[encodeValues(pk)] = true
+	}
+	assert.Len(t, encoded, len(pks))
+}
+
+func TestTrackedTable(t *testing.T) {
+	newTableDiffer := func(query string) *tableDiffer {
+		return &tableDiffer{
+			wd:    &workflowDiffer{ct: &controller{vde: &Engine{parser: sqlparser.NewTestParser()}}},
+			table: &tabletmanagerdatapb.TableDefinition{Name: "t1"},
+			tablePlan: &tablePlan{
+				sourceQuery: query,
+				comparePKs:  []compareColInfo{{colIndex: 1, isPK: true, colName: "c1"}},
+			},
+		}
+	}
+
+	table, reason, err := newTableDiffer("select c2, id as c1 from s1 order by c1").trackedTable()
+	require.NoError(t, err)
+	assert.Empty(t, reason)
+	assert.Equal(t, &trackedTable{name: "t1", sourceTable: "s1", sourcePKs: []string{"id"}}, table)
+
+	_, reason, err = newTableDiffer("select c2, id + 1 as c1 from s1 order by c1").trackedTable()
+	require.NoError(t, err)
+	assert.Equal(t, "the primary key is not made of source columns", reason)
+
+	_, reason, err = newTableDiffer("select s1.c2, s1.id as c1 from s1 join s2 on s1.id = s2.id order by c1").trackedTable()
+	require.NoError(t, err)
+	assert.Equal(t, "the workflow filter does not select from a single source table", reason)
+}
+
+func TestFindSuspects(t *testing.T) {
+	sourceTablet := &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200}}
+	targetTablet := &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}}
+	checksumFields := sqltypes.MakeTestFields("count(*)|checksum", "int64|uint64")
+	rowChecksumFields := sqltypes.MakeTestFields("c1|checksum", "int64|uint64")
+	query := "select c1, c2 from t1 order by c1"
+	tmc := &checksumTMClient{results: make(map[string]*sqltypes.Result)}
+	td := &tableDiffer{
+		wd: &workflowDiffer{ct: &controller{
+			vde:                 &Engine{parser: sqlparser.NewTestParser()},
+			tmc:                 tmc,
+			sources:             map[string]*migrationSource{"0": {shardStreamer: &shardStreamer{tablet: sourceTablet, shard: "0"}}},
+			targetShardStreamer: &shardStreamer{tablet: targetTablet, shard: "0"},
+		}},
+		table:       &tabletmanagerdatapb.TableDefinition{Name: "t1"},
+		sourceQuery: query,
+		tablePlan: &tablePlan{
+			sourceQuery: query,
+			targetQuery: query,
+			comparePKs:  []compareColInfo{{colIndex: 0, isPK: true, colName: "c1"}},
+			compareCols: []compareColInfo{{colIndex: 0, isPK: true, colName: "c1"}, {colIndex: 1, colName: "c2"}},
+		},
+	}
+	cp, err := td.buildChecksumPlan()
+	require.NoError(t, err)
+	pk := func(c1 int64) []sqltypes.Value { return []sqltypes.Value{sqltypes.NewInt64(c1)} }
+	r := pkRange{pks: [][]sqltypes.Value{pk(1), pk(2), pk(3), pk(4)}}
+
+	// The checksums of the rows match.
+	tmc.setResult(sourceTablet, checksumQuery(cp.sourceSelect, cp.sourcePKs, r), sqltypes.MakeTestResult(checksumFields, "3|7"))
+	tmc.setResult(targetTablet, checksumQuery(cp.targetSelect, cp.targetPKs, r), sqltypes.MakeTestResult(checksumFields, "3|7"))
+	matching, suspects, err := td.findSuspects(context.Background(), cp, r)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, matching)
+	assert.Empty(t, suspects)
+
+	// The second row differs, the third one is missing on the target and the
+	// fourth one is missing on the source.
+	tmc.setResult(targetTablet, checksumQuery(cp.targetSelect, cp.targetPKs, r), sqltypes.MakeTestResult(checksumFields, "3|6"))
+	tmc.setResult(sourceTablet, rowChecksumsQuery(cp.sourceSelect, cp.sourcePKs, r), sqltypes.MakeTestResult(rowChecksumFields, "1|1", "2|2", "3|3"))
+	tmc.setResult(targetTablet, rowChecksumsQuery(cp.targetSelect, cp.targetPKs, r), sqltypes.MakeTestResult(rowChecksumFields, "1|1", "2|5", "4|4"))
+	matching, suspects, err = td.findSuspects(context.Background(), cp, r)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, matching)
+	assert.Equal(t, [][]sqltypes.Value{pk(2), pk(3), pk(4)}, suspects)
+}
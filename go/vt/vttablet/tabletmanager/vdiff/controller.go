@@ -81,6 +81,15 @@ type controller struct {
 	Errors                *stats.CountersWithSingleLabel
 	TableDiffRowCounts    *stats.CountersWithSingleLabel
 	TableDiffPhaseTimings *stats.Timings
+	// TableChecksumRangeCounts counts the primary key ranges whose checksums
+	// matched or not, by table, in checksum vdiffs.
+	TableChecksumRangeCounts *stats.CountersWithSingleLabel
+	// ContinuousRowCounts counts the changed rows verified, and those found
+	// to differ, by table, in continuous vdiffs.
+	ContinuousRowCounts *stats.CountersWithSingleLabel
+	// ContinuousCheckpoint is the time of the last checkpoint of a
+	// continuous vdiff, in seconds since the epoch.
+	ContinuousCheckpoint *stats.Gauge
 }
 
 func newController(row sqltypes.RowNamedValues, dbClientFactory func() binlogplayer.DBClient,
@@ -90,19 +99,22 @@ func newController(row sqltypes.RowNamedValues, dbClientFactory func() binlogpla
 	id, _ := row["id"].ToInt64()
 
 	ct := &controller{
-		id:                    id,
-		uuid:                  row["vdiff_uuid"].ToString(),
-		workflow:              row["workflow"].ToString(),
-		dbClientFactory:       dbClientFactory,
-		ts:                    ts,
-		vde:                   vde,
-		done:                  make(chan struct{}),
-		tmc:                   vde.tmClientFactory(),
-		sources:               make(map[string]*migrationSource),
-		options:               options,
-		Errors:                stats.NewCountersWithSingleLabel("", "", "Error"),
-		TableDiffRowCounts:    stats.NewCountersWithSingleLabel("", "", "Rows"),
-		TableDiffPhaseTimings: stats.NewTimings("", "", "", "TablePhase"),
+		id:                       id,
+		uuid:                     row["vdiff_uuid"].ToString(),
+		workflow:                 row["workflow"].ToString(),
+		dbClientFactory:          dbClientFactory,
+		ts:                       ts,
+		vde:                      vde,
+		done:                     make(chan struct{}),
+		tmc:                      vde.tmClientFactory(),
+		sources:                  make(map[string]*migrationSource),
+		options:                  options,
+		Errors:                   stats.NewCountersWithSingleLabel("", "", "Error"),
+		TableDiffRowCounts:       stats.NewCountersWithSingleLabel("", "", "Rows"),
+		TableDiffPhaseTimings:    stats.NewTimings("", "", "", "TablePhase"),
+		TableChecksumRangeCounts: stats.NewCountersWithSingleLabel("", "", "Ranges"),
+		ContinuousRowCounts:      stats.NewCountersWithSingleLabel("", "", "Rows"),
+		ContinuousCheckpoint:     stats.NewGauge("", ""),
 	}
 	return ct, nil
 }
@@ -0,0 +1,605 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// repairType is the type of the statement that reconciles a row on the
+// target with the source.
+type repairType string
+
+const (
+	// repairInsert inserts a source row that is missing on the target.
+	repairInsert repairType = "insert"
+	// repairUpdate updates a target row whose columns differ from the source.
+	repairUpdate repairType = "update"
+	// repairDelete deletes an extra row on the target.
+	repairDelete repairType = "delete"
+
+	// repairFlushCount and repairFlushSize are the number, and the total size,
+	// of the buffered repair statements at which they are saved.
+	repairFlushCount = 1000
+	repairFlushSize  = 4 * 1024 * 1024
+	// repairApplyBatchSize is the number of repair statements that are
+	// applied in each transaction.
+	repairApplyBatchSize = 100
+)
+
+// The states of a repair statement.
+const (
+	repairPending = "pending"
+	// repairApplied is the state of a statement that was applied.
+	repairApplied = "applied"
+	// repairStale is the state of a statement that was not applied because
+	// the source row changed since the diff.
+	repairStale = "stale"
+	// repairUnneeded is the state of a statement that was not applied because
+	// the target row already matched the source row.
+	repairUnneeded = "unneeded"
+)
+
+// repairStatement is a statement that repairs a row on the target.
+type repairStatement struct {
+	typ       repairType
+	statement string
+	// rowValues encodes the values of the row the statement was built from.
+	rowValues string
+	// sourceQuery and targetQuery select the row by primary key on the
+	// source and the target, so that it can be checked again before the
+	// statement is applied.
+	sourceQuery string
+	targetQuery string
+}
+
+// buildRepairStatement returns an idempotent statement that reconciles the
+// target row holding the primary key of the row with the source. The row is
+// the source row for inserts and updates, and the target row for deletes.
+func buildRepairStatement(typ repairType, dbName, tableName string, cols []compareColInfo, row []sqltypes.Value) string {
+	var buf strings.Builder
+	table := sqlescape.EscapeID(dbName) + "." + sqlescape.EscapeID(tableName)
+	// writeCols writes the pk or non-pk columns, each one formatted by
+	// writeCol, separated by sep.
+	writeCols := func(pk bool, sep string, writeCol func(col compareColInfo)) {
+		n := 0
+		for _, col := range cols {
+			if col.isPK != pk {
+				continue
+			}
+			if n > 0 {
+				buf.WriteString(sep)
+			}
+			writeCol(col)
+			n++
+		}
+	}
+	assign := func(col compareColInfo) {
+		buf.WriteString(sqlescape.EscapeID(col.colName))
+		buf.WriteString(" = ")
+		row[col.colIndex].EncodeSQLStringBuilder(&buf)
+	}
+	match := func(col compareColInfo) {
+		buf.WriteString(sqlescape.EscapeID(col.colName))
+		if row[col.colIndex].IsNull() {
+			buf.WriteString(" is null")
+			return
+		}
+		buf.WriteString(" = ")
+		row[col.colIndex].EncodeSQLStringBuilder(&buf)
+	}
+
+	switch typ {
+	case repairInsert:
+		hasNonPKs := false
+		for _, col := range cols {
+			hasNonPKs = hasNonPKs || !col.isPK
+		}
+		buf.WriteString("insert ")
+		if !hasNonPKs {
+			buf.WriteString("ignore ")
+		}
+		buf.WriteString("into " + table + " (")
+		for i, col := range cols {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(sqlescape.EscapeID(col.colName))
+		}
+		buf.WriteString(") values (")
+		for i, col := range cols {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			row[col.colIndex].EncodeSQLStringBuilder(&buf)
+		}
+		buf.WriteString(")")
+		if hasNonPKs {
+			buf.WriteString(" on duplicate key update ")
+			writeCols(false, ", ", assign)
+		}
+	case repairUpdate:
+		buf.WriteString("update " + table + " set ")
+		writeCols(false, ", ", assign)
+		buf.WriteString(" where ")
+		writeCols(true, " and ", match)
+	case repairDelete:
+		buf.WriteString("delete from " + table + " where ")
+		writeCols(true, " and ", match)
+	}
+	return buf.String()
+}
+
+// repairEnabled returns true if the statements that repair the differences
+// found in the table are recorded.
+func (td *tableDiffer) repairEnabled() bool {
+	// The target rows are not compared with the values stored on the target
+	// when their timestamps are converted from the source time zone, and the
+	// rows of aggregates cannot be read again by primary key.
+	return td.wd.opts.GetCoreOptions().GetRepair() && td.wd.ct.sourceTimeZone == "" &&
+		(td.tablePlan == nil || len(td.tablePlan.aggregates) == 0)
+}
+
+// startRepairs deletes the repair statements recorded by an earlier diff of
+// the table when it is diffed from the start.
+func (td *tableDiffer) startRepairs(dbClient binlogplayer.DBClient) error {
+	td.repairs, td.repairsSize = nil, 0
+	if !td.repairEnabled() || (td.lastTargetPK != nil && len(td.lastTargetPK.Rows) > 0) {
+		return nil
+	}
+	query, err := sqlparser.ParseAndBind(sqlDeleteVDiffTableRepairs,
+		sqltypes.Int64BindVariable(td.wd.ct.id),
+		sqltypes.StringBindVariable(td.table.Name),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = dbClient.ExecuteFetch(query, 0)
+	return err
+}
+
+// recordRepair buffers the statement that repairs the row, when repairs are
+// enabled, and saves the buffered statements when there are enough of them.
+// As they are idempotent, a statement saved again when a diff is resumed from
+// its last saved position does no harm.
+func (td *tableDiffer) recordRepair(dbClient binlogplayer.DBClient, typ repairType, row []sqltypes.Value) error {
+	if !td.repairEnabled() {
+		return nil
+	}
+	if td.repairPlan == nil {
+		repairPlan, err := td.buildChecksumPlan()
+		if err != nil {
+			return err
+		}
+		// The source rows are read again by primary key from the source shard
+		// primaries when the repairs are applied. The checksum plan does not
+		// filter them on the in_keyrange expression of the workflow filter,
+		// which MySQL cannot evaluate, but the row values are compared,
+		// sharding columns included, so a row that moved out of the key range
+		// is not repaired.
+		td.repairPlan = repairPlan
+	}
+	pk := make([]sqltypes.Value, 0, len(td.tablePlan.comparePKs))
+	for _, col := range td.tablePlan.comparePKs {
+		pk = append(pk, row[col.colIndex])
+	}
+	r := pkRange{pks: [][]sqltypes.Value{pk}}
+	repair := repairStatement{
+		typ:         typ,
+		statement:   buildRepairStatement(typ, td.tablePlan.dbName, td.table.Name, td.tablePlan.compareCols, row),
+		rowValues:   encodeValues(row),
+		sourceQuery: rowsQuery(td.repairPlan.sourceSelect, td.repairPlan.sourcePKs, r),
+		targetQuery: rowsQuery(td.repairPlan.targetSelect, td.repairPlan.targetPKs, r),
+	}
+	td.repairs = append(td.repairs, repair)
+	td.repairsSize += len(repair.statement) + len(repair.rowValues) + len(repair.sourceQuery) + len(repair.targetQuery)
+	if len(td.repairs) >= repairFlushCount || td.repairsSize >= repairFlushSize {
+		return td.flushRepairs(dbClient)
+	}
+	return nil
+}
+
+// flushRepairs saves the buffered repair statements.
+func (td *tableDiffer) flushRepairs(dbClient binlogplayer.DBClient) error {
+	if len(td.repairs) == 0 {
+		return nil
+	}
+	var values strings.Builder
+	for i, repair := range td.repairs {
+		if i > 0 {
+			values.WriteString(", ")
+		}
+		fmt.Fprintf(&values, "(%d, %s, %s", td.wd.ct.id, encodeString(td.table.Name), encodeString(string(repair.typ)))
+		for _, value := range []string{repair.statement, repair.rowValues, repair.sourceQuery, repair.targetQuery} {
+			values.WriteString(", ")
+			sqltypes.NewVarBinary(value).EncodeSQLStringBuilder(&values)
+		}
+		values.WriteString(")")
+	}
+	if _, err := dbClient.ExecuteFetch(fmt.Sprintf(sqlNewVDiffRepairs, values.String()), 0); err != nil {
+		return vterrors.Wrapf(err, "failed to save the repair statements for table %s", td.table.Name)
+	}
+	td.repairs, td.repairsSize = td.repairs[:0], 0
+	return nil
+}
+
+// reconcileRepairs deletes the repair statements of the extra rows that were
+// reconciled once the table was diffed. These are the rows that are both an
+// extra source row and an extra target row, and all the extra rows of a side
+// that was entirely reconciled, e.g. of a reference table.
+func (td *tableDiffer) reconcileRepairs(dbClient binlogplayer.DBClient, sourceReconciled, targetReconciled bool) error {
+	if !td.repairEnabled() {
+		return nil
+	}
+	if err := td.flushRepairs(dbClient); err != nil {
+		return err
+	}
+	vdiffID, tableName := sqltypes.Int64BindVariable(td.wd.ct.id), sqltypes.StringBindVariable(td.table.Name)
+	var queries []string
+	for _, typ := range []repairType{repairInsert, repairDelete} {
+		if (typ == repairInsert && !sourceReconciled) || (typ == repairDelete && !targetReconciled) {
+			continue
+		}
+		query, err := sqlparser.ParseAndBind(sqlDeleteVDiffTableRepairsByType, vdiffID, tableName, sqltypes.StringBindVariable(string(typ)))
+		if err != nil {
+			return err
+		}
+		queries = append(queries, query)
+	}
+	query, err := sqlparser.ParseAndBind(sqlDeleteVDiffReconciledRepairs, vdiffID, tableName)
+	if err != nil {
+		return err
+	}
+	queries = append(queries, query)
+	for _, query := range queries {
+		if _, err := dbClient.ExecuteFetch(query, 0); err != nil {
+			return vterrors.Wrapf(err, "failed to delete the reconciled repair statements for table %s", td.table.Name)
+		}
+	}
+	return nil
+}
+
+// drainRepairs drains the rows of the executor, recording the statements
+// that repair them when repairs are enabled, and returns the number of rows.
+func (td *tableDiffer) drainRepairs(ctx context.Context, dbClient binlogplayer.DBClient, pe *primitiveExecutor, typ repairType) (int64, error) {
+	if !td.repairEnabled() {
+		return pe.drain(ctx)
+	}
+	var count int64
+	for {
+		row, err := pe.next()
+		if err != nil {
+			return 0, err
+		}
+		if row == nil {
+			return count, nil
+		}
+		if err := td.recordRepair(dbClient, typ, row); err != nil {
+			return 0, err
+		}
+		count++
+	}
+}
+
+// handleRepairAction returns the pending repair statements of a vdiff or,
+// when the action argument is apply, applies them and returns a summary of
+// the repair statements by table, type, and state.
+func (vde *Engine) handleRepairAction(ctx context.Context, dbClient binlogplayer.DBClient, req *tabletmanagerdatapb.VDiffRequest, resp *tabletmanagerdatapb.VDiffResponse) error {
+	query, err := sqlparser.ParseAndBind(sqlGetVDiffByKeyspaceWorkflowUUID,
+		sqltypes.StringBindVariable(req.Keyspace),
+		sqltypes.StringBindVariable(req.Workflow),
+		sqltypes.StringBindVariable(req.VdiffUuid),
+	)
+	if err != nil {
+		return err
+	}
+	qr, err := dbClient.ExecuteFetch(query, 1)
+	if err != nil {
+		return err
+	}
+	row := qr.Named().Row()
+	if row == nil {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no vdiff found for UUID %s keyspace %s and workflow %s on tablet %s",
+			req.VdiffUuid, req.Keyspace, req.Workflow, topoproto.TabletAliasString(vde.thisTablet.Alias))
+	}
+	resp.VdiffUuid = req.VdiffUuid
+	if resp.Id, err = row.ToInt64("id"); err != nil {
+		return err
+	}
+	options := &tabletmanagerdatapb.VDiffOptions{}
+	if err := protojson.Unmarshal(row.AsBytes("options", []byte("{}")), options); err != nil {
+		return err
+	}
+	if !options.GetCoreOptions().GetRepair() {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vdiff %s was not created with repair enabled", req.VdiffUuid)
+	}
+
+	switch req.ActionArg {
+	case "":
+		limit := req.GetOptions().GetReportOptions().GetMaxSampleRows()
+		if limit <= 0 {
+			limit = math.MaxInt64
+		}
+		query, err = sqlparser.ParseAndBind(sqlGetPendingVDiffRepairs,
+			sqltypes.Int64BindVariable(resp.Id),
+			sqltypes.Int64BindVariable(limit),
+		)
+		if err != nil {
+			return err
+		}
+	case ApplyActionArg:
+		if state := VDiffState(strings.ToLower(row.AsString("state", ""))); state != CompletedState {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vdiff %s is %s on tablet %s, its repair statements can only be applied once it has completed",
+				req.VdiffUuid, state, topoproto.TabletAliasString(vde.thisTablet.Alias))
+		}
+		if err := vde.applyRepairs(ctx, dbClient, req.Workflow, resp.Id); err != nil {
+			return err
+		}
+		query, err = sqlparser.ParseAndBind(sqlVDiffRepairSummary, sqltypes.Int64BindVariable(resp.Id))
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("action argument %s not supported", req.ActionArg)
+	}
+	if qr, err = dbClient.ExecuteFetch(query, -1); err != nil {
+		return err
+	}
+	resp.Output = sqltypes.ResultToProto3(qr)
+	return nil
+}
+
+// applyRepairs applies the pending repair statements of the vdiff, in
+// batches that wait for the throttler. The running streams of the workflow
+// are stopped meanwhile, so that they do not change the rows being repaired.
+// Each row is read again from the source and the target first: a statement
+// is only applied if the source row is still the one that was diffed, and
+// the target row does not already match it.
+func (vde *Engine) applyRepairs(ctx context.Context, dbClient binlogplayer.DBClient, workflow string, vdiffID int64) (err error) {
+	sources, err := vde.getRepairSources(ctx, dbClient, workflow)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("select id from _vt.vreplication where db_name = %s and workflow = %s and state = 'Running'",
+		encodeString(vde.dbName), encodeString(workflow))
+	qr, err := dbClient.ExecuteFetch(query, -1)
+	if err != nil {
+		return err
+	}
+	if len(qr.Rows) > 0 {
+		ids := make([]string, 0, len(qr.Rows))
+		for _, row := range qr.Rows {
+			ids = append(ids, row[0].ToString())
+		}
+		streams := strings.Join(ids, ", ")
+		if _, err := vde.vre.Exec(fmt.Sprintf("update _vt.vreplication set state = 'Stopped', message = 'for vdiff repair' where id in (%s)", streams)); err != nil {
+			return err
+		}
+		defer func() {
+			log.Infof("Restarting the %q VReplication workflow streams %s after applying the repairs of vdiff %d", workflow, streams, vdiffID)
+			if _, restartErr := vde.vre.Exec(fmt.Sprintf("update _vt.vreplication set state = 'Running', message = '' where id in (%s)", streams)); restartErr != nil && err == nil {
+				err = restartErr
+			}
+		}()
+	}
+
+	tmc := vde.tmClientFactory()
+	defer tmc.Close()
+	for {
+		query, err := sqlparser.ParseAndBind(sqlGetPendingVDiffRepairs,
+			sqltypes.Int64BindVariable(vdiffID),
+			sqltypes.Int64BindVariable(repairApplyBatchSize),
+		)
+		if err != nil {
+			return err
+		}
+		qr, err := dbClient.ExecuteFetch(query, -1)
+		if err != nil {
+			return err
+		}
+		if len(qr.Rows) == 0 {
+			return nil
+		}
+		if err := vde.waitForRepairThrottler(ctx); err != nil {
+			return err
+		}
+		if err := vde.applyRepairBatch(ctx, dbClient, tmc, sources, qr); err != nil {
+			return err
+		}
+	}
+}
+
+// getRepairSources returns the primary tablets of the source shards of the
+// workflow, which the source rows are read from again before they are
+// repaired.
+func (vde *Engine) getRepairSources(ctx context.Context, dbClient binlogplayer.DBClient, workflow string) ([]*topodatapb.Tablet, error) {
+	query, err := sqlparser.ParseAndBind(sqlGetVReplicationSources,
+		sqltypes.StringBindVariable(vde.dbName),
+		sqltypes.StringBindVariable(workflow),
+	)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := dbClient.ExecuteFetch(query, -1)
+	if err != nil {
+		return nil, err
+	}
+	if len(qr.Rows) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no streams found for workflow %s", workflow)
+	}
+	var sources []*topodatapb.Tablet
+	for _, row := range qr.Named().Rows {
+		var bls binlogdatapb.BinlogSource
+		if err := prototext.Unmarshal(row.AsBytes("source", nil), &bls); err != nil {
+			return nil, err
+		}
+		ts := vde.ts
+		if bls.ExternalCluster != "" {
+			if ts, err = vde.ts.OpenExternalVitessClusterServer(ctx, bls.ExternalCluster); err != nil {
+				return nil, err
+			}
+		}
+		si, err := ts.GetShard(ctx, bls.Keyspace, bls.Shard)
+		if err != nil {
+			return nil, err
+		}
+		if si.PrimaryAlias == nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "source shard %s/%s has no primary", bls.Keyspace, bls.Shard)
+		}
+		ti, err := ts.GetTablet(ctx, si.PrimaryAlias)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, ti.Tablet)
+	}
+	return sources, nil
+}
+
+// checkRepair reads the row of the repair statement again from the sources
+// and the target, and returns the state of the statement: pending if it has
+// to be applied, stale if the source row changed since the diff, or unneeded
+// if the target row already matches the source row.
+func (vde *Engine) checkRepair(ctx context.Context, tmc tmclient.TabletManagerClient, sources []*topodatapb.Tablet, repair sqltypes.RowNamedValues) (string, error) {
+	readRows := func(tablet *topodatapb.Tablet, query string) ([][]sqltypes.Value, error) {
+		res, err := tmc.ExecuteFetchAsApp(ctx, tablet, false, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+			Query:   []byte(query),
+			MaxRows: 2,
+		})
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to run %q on tablet %s", query, topoproto.TabletAliasString(tablet.Alias))
+		}
+		return sqltypes.Proto3ToResult(res).Rows, nil
+	}
+	// matches returns true if the rows are the ones the statement leads to:
+	// none for a delete, and the row the statement was built from otherwise.
+	typ, rowValues := repairType(repair.AsString("type", "")), repair.AsString("row_values", "")
+	matches := func(rows [][]sqltypes.Value) bool {
+		if typ == repairDelete {
+			return len(rows) == 0
+		}
+		return len(rows) == 1 && encodeValues(rows[0]) == rowValues
+	}
+
+	var sourceRows [][]sqltypes.Value
+	for _, source := range sources {
+		rows, err := readRows(source, repair.AsString("source_query", ""))
+		if err != nil {
+			return "", err
+		}
+		sourceRows = append(sourceRows, rows...)
+	}
+	if !matches(sourceRows) {
+		return repairStale, nil
+	}
+	targetRows, err := readRows(vde.thisTablet, repair.AsString("target_query", ""))
+	if err != nil {
+		return "", err
+	}
+	if matches(targetRows) {
+		return repairUnneeded, nil
+	}
+	return repairPending, nil
+}
+
+// applyRepairBatch checks the repair statements, then applies those that
+// are still needed and saves the states of all of them, in one transaction.
+func (vde *Engine) applyRepairBatch(ctx context.Context, dbClient binlogplayer.DBClient, tmc tmclient.TabletManagerClient, sources []*topodatapb.Tablet, qr *sqltypes.Result) (err error) {
+	ids := make(map[string][]int64)
+	var statements []sqltypes.RowNamedValues
+	for _, row := range qr.Named().Rows {
+		id, err := row.ToInt64("id")
+		if err != nil {
+			return err
+		}
+		state, err := vde.checkRepair(ctx, tmc, sources, row)
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to check repair statement %d on table %s", id, row.AsString("table_name", ""))
+		}
+		if state == repairPending {
+			state = repairApplied
+			statements = append(statements, row)
+		}
+		ids[state] = append(ids[state], id)
+	}
+
+	if err := dbClient.Begin(); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = dbClient.Rollback()
+		}
+	}()
+	for _, row := range statements {
+		if _, err := dbClient.ExecuteFetch(row.AsString("statement", ""), 0); err != nil {
+			return vterrors.Wrapf(err, "failed to apply repair statement %s on table %s", row.AsString("id", ""), row.AsString("table_name", ""))
+		}
+	}
+	for _, state := range []string{repairApplied, repairStale, repairUnneeded} {
+		if len(ids[state]) == 0 {
+			continue
+		}
+		idsBV, err := sqltypes.BuildBindVariable(ids[state])
+		if err != nil {
+			return err
+		}
+		query, err := sqlparser.ParseAndBind(sqlUpdateVDiffRepairsState, sqltypes.StringBindVariable(state), idsBV)
+		if err != nil {
+			return err
+		}
+		if _, err := dbClient.ExecuteFetch(query, 0); err != nil {
+			return err
+		}
+	}
+	return dbClient.Commit()
+}
+
+// waitForRepairThrottler waits until the throttler lets the repairs go on.
+func (vde *Engine) waitForRepairThrottler(ctx context.Context) error {
+	if vde.vre == nil || vde.vre.ThrottlerClient() == nil {
+		return nil
+	}
+	for {
+		if _, ok := vde.vre.ThrottlerClient().ThrottleCheckOKOrWaitAppName(ctx, throttlerapp.VDiffRepairName); ok {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return vterrors.Errorf(vtrpcpb.Code_CANCELED, "context has expired while waiting for the throttler")
+		}
+	}
+}
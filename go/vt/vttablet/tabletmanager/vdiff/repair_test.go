@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestBuildRepairStatement(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+	cols := []compareColInfo{
+		{colIndex: 0, colName: "c1", isPK: true},
+		{colIndex: 1, colName: "c2"},
+		{colIndex: 2, colName: "c3"},
+	}
+	multiPKCols := []compareColInfo{
+		{colIndex: 0, colName: "c1", isPK: true},
+		{colIndex: 1, colName: "c2", isPK: true},
+	}
+	row := []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("it's"), sqltypes.NULL}
+	multiPKRow := []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NULL}
+
+	testCases := []struct {
+		name string
+		typ  repairType
+		cols []compareColInfo
+		row  []sqltypes.Value
+		want string
+	}{
+		{
+			name: "insert",
+			typ:  repairInsert,
+			cols: cols,
+			row:  row,
+			want: "insert into `vt_ks`.`t1` (`c1`, `c2`, `c3`) values (1, 'it\\'s', null) on duplicate key update `c2` = 'it\\'s', `c3` = null",
+		},
+		{
+			name: "insert without non-pk columns",
+			typ:  repairInsert,
+			cols: multiPKCols,
+			row:  multiPKRow,
+			want: "insert ignore into `vt_ks`.`t1` (`c1`, `c2`) values (1, null)",
+		},
+		{
+			name: "update",
+			typ:  repairUpdate,
+			cols: cols,
+			row:  row,
+			want: "update `vt_ks`.`t1` set `c2` = 'it\\'s', `c3` = null where `c1` = 1",
+		},
+		{
+			name: "delete",
+			typ:  repairDelete,
+			cols: multiPKCols,
+			row:  multiPKRow,
+			want: "delete from `vt_ks`.`t1` where `c1` = 1 and `c2` is null",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			statement := buildRepairStatement(tc.typ, "vt_ks", "t1", tc.cols, tc.row)
+			assert.Equal(t, tc.want, statement)
+			// The statements must be valid.
+			_, err := parser.Parse(statement)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRepairQueries(t *testing.T) {
+	ids, err := sqltypes.BuildBindVariable([]int64{1, 2})
+	require.NoError(t, err)
+	for _, query := range []struct {
+		query string
+		args  []*querypb.BindVariable
+	}{
+		{sqlDeleteVDiffTableRepairs, []*querypb.BindVariable{sqltypes.Int64BindVariable(1), sqltypes.StringBindVariable("t1")}},
+		{sqlGetPendingVDiffRepairs, []*querypb.BindVariable{sqltypes.Int64BindVariable(1), sqltypes.Int64BindVariable(repairApplyBatchSize)}},
+		{sqlDeleteVDiffTableRepairsByType, []*querypb.BindVariable{sqltypes.Int64BindVariable(1), sqltypes.StringBindVariable("t1"), sqltypes.StringBindVariable("insert")}},
+		{sqlDeleteVDiffReconciledRepairs, []*querypb.BindVariable{sqltypes.Int64BindVariable(1), sqltypes.StringBindVariable("t1")}},
+		{sqlUpdateVDiffRepairsState, []*querypb.BindVariable{sqltypes.StringBindVariable(repairApplied), ids}},
+		{sqlGetVReplicationSources, []*querypb.BindVariable{sqltypes.StringBindVariable("vt_ks"), sqltypes.StringBindVariable("wf")}},
+		{sqlVDiffRepairSummary, []*querypb.BindVariable{sqltypes.Int64BindVariable(1)}},
+	} {
+		_, err := sqlparser.ParseAndBind(query.query, query.args...)
+		require.NoError(t, err, query.query)
+	}
+}
+
+func TestRecordRepair(t *testing.T) {
+	query := "select c1, c2 from t1 where in_keyrange(c1, 'hash', '-80') and c2 != 'x' order by c1"
+	td := &tableDiffer{
+		wd: &workflowDiffer{
+			ct: &controller{vde: &Engine{parser: sqlparser.NewTestParser()}},
+			opts: &tabletmanagerdatapb.VDiffOptions{
+				CoreOptions: &tabletmanagerdatapb.VDiffCoreOptions{Repair: true},
+			},
+		},
+		table:       &tabletmanagerdatapb.TableDefinition{Name: "t1"},
+		sourceQuery: query,
+		tablePlan: &tablePlan{
+			dbName:      "vt_ks",
+			sourceQuery: query,
+			targetQuery: "select c1, c2 from t1 where c2 != 'x' order by c1",
+			comparePKs:  []compareColInfo{{colIndex: 0, isPK: true, colName: "c1"}},
+			compareCols: []compareColInfo{{colIndex: 0, isPK: true, colName: "c1"}, {colIndex: 1, colName: "c2"}},
+		},
+		// The diff is resumed, so the earlier repairs are not deleted.
+		lastTargetPK: &querypb.QueryResult{Rows: []*querypb.Row{{}}},
+	}
+	require.NoError(t, td.startRepairs(nil))
+	row := []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar("a")}
+	require.NoError(t, td.recordRepair(nil, repairInsert, row))
+	require.Len(t, td.repairs, 1)
+	assert.Equal(t, repairStatement{
+		typ:         repairInsert,
+		statement:   "insert into `vt_ks`.`t1` (`c1`, `c2`) values (1, 'a') on duplicate key update `c2` = 'a'",
+		rowValues:   encodeValues(row),
+		sourceQuery: "select c1, c2 from t1 where c1 in (1) and (c2 != 'x') order by c1",
+		targetQuery: "select c1, c2 from t1 where c1 in (1) and (c2 != 'x') order by c1",
+	}, td.repairs[0])
+}
+
+func TestApplyRepairBatch(t *testing.T) {
+	sourceTablet := &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200}}
+	targetTablet := &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}}
+	fields := sqltypes.MakeTestFields("c1|c2", "int64|varchar")
+	rowValues := func(c1 int64, c2 string) string {
+		return encodeValues([]sqltypes.Value{sqltypes.NewInt64(c1), sqltypes.NewVarChar(c2)})
+	}
+	rowQuery := func(c1 string) string {
+		return "select c1, c2 from t1 where c1 in (" + c1 + ") order by c1"
+	}
+	tmc := &checksumTMClient{results: make(map[string]*sqltypes.Result)}
+	// The source row is unchanged and missing on the target.
+	tmc.setResult(sourceTablet, rowQuery("1"), sqltypes.MakeTestResult(fields, "1|a"))
+	tmc.setResult(targetTablet, rowQuery("1"), sqltypes.MakeTestResult(fields))
+	// The source row changed since the diff.
+	tmc.setResult(sourceTablet, rowQuery("2"), sqltypes.MakeTestResult(fields, "2|c"))
+	// The target row was already deleted.
+	tmc.setResult(sourceTablet, rowQuery("3"), sqltypes.MakeTestResult(fields))
+	tmc.setResult(targetTablet, rowQuery("3"), sqltypes.MakeTestResult(fields))
+	// The target row already matches the source row.
+	tmc.setResult(sourceTablet, rowQuery("4"), sqltypes.MakeTestResult(fields, "4|d"))
+	tmc.setResult(targetTablet, rowQuery("4"), sqltypes.MakeTestResult(fields, "4|d"))
+
+	repairs := sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+		"id|table_name|type|statement|row_values|source_query|target_query",
+		"int64|varchar|varchar|varbinary|varbinary|varbinary|varbinary"),
+		"1|t1|insert|insert into t1 values (1, 'a')|"+rowValues(1, "a")+"|"+rowQuery("1")+"|"+rowQuery("1"),
+		"2|t1|update|update t1 set c2 = 'b' where c1 = 2|"+rowValues(2, "b")+"|"+rowQuery("2")+"|"+rowQuery("2"),
+		"3|t1|delete|delete from t1 where c1 = 3|"+rowValues(3, "c")+"|"+rowQuery("3")+"|"+rowQuery("3"),
+		"4|t1|update|update t1 set c2 = 'd' where c1 = 4|"+rowValues(4, "d")+"|"+rowQuery("4")+"|"+rowQuery("4"),
+	)
+	dbClient := binlogplayer.NewMockDBClient(t)
+	dbClient.ExpectRequest("begin", nil, nil)
+	dbClient.ExpectRequest("insert into t1 values (1, 'a')", &sqltypes.Result{}, nil)
+	dbClient.ExpectRequest("update _vt.vdiff_repair set state = 'applied', applied_at = now() where id in (1)", &sqltypes.Result{}, nil)
+	dbClient.ExpectRequest("update _vt.vdiff_repair set state = 'stale', applied_at = now() where id in (2)", &sqltypes.Result{}, nil)
+	dbClient.ExpectRequest("update _vt.vdiff_repair set state = 'unneeded', applied_at = now() where id in (3, 4)", &sqltypes.Result{}, nil)
+	dbClient.ExpectRequest("commit", nil, nil)
+
+	vde := &Engine{thisTablet: targetTablet}
+	err := vde.applyRepairBatch(context.Background(), dbClient, tmc, []*topodatapb.Tablet{sourceTablet}, repairs)
+	require.NoError(t, err)
+	dbClient.Wait()
+}
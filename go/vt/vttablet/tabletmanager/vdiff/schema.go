@@ -30,10 +30,12 @@ const (
 	sqlGetVDiffByKeyspaceWorkflowUUID       = "select * from _vt.vdiff where keyspace = %a and workflow = %a and vdiff_uuid = %a"
 	sqlGetMostRecentVDiffByKeyspaceWorkflow = "select * from _vt.vdiff where keyspace = %a and workflow = %a order by id desc limit %a"
 	sqlGetVDiffByID                         = "select * from _vt.vdiff where id = %a"
-	sqlDeleteVDiffs                         = `delete from vd, vdt, vdl using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+	sqlDeleteVDiffs                         = `delete from vd, vdt, vdl, vdr using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
 										left join _vt.vdiff_log as vdl on (vd.id = vdl.vdiff_id)
+										left join _vt.vdiff_repair as vdr on (vd.id = vdr.vdiff_id)
 										where vd.keyspace = %a and vd.workflow = %a`
-	sqlDeleteVDiffByUUID = `delete from vd, vdt using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+	sqlDeleteVDiffByUUID = `delete from vd, vdt, vdr using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+							left join _vt.vdiff_repair as vdr on (vd.id = vdr.vdiff_id)
 							where vd.vdiff_uuid = %a`
 	sqlVDiffSummary = `select vd.state as vdiff_state, vd.last_error as last_error, vdt.table_name as table_name,
 						vd.vdiff_uuid as 'uuid', vdt.state as table_state, vdt.table_rows as table_rows,
@@ -66,4 +68,25 @@ const (
 	sqlUpdateTableMismatch       = "update _vt.vdiff_table set mismatch = true where vdiff_id = %a and table_name = %a"
 
 	sqlGetIncompleteTables = "select table_name as table_name from _vt.vdiff_table where vdiff_id = %a and state != 'completed' order by table_name"
+
+	sqlNewVDiffRepairs = `insert into _vt.vdiff_repair(vdiff_id, table_name, type, statement, row_values, source_query, target_query)
+							values %s` // The values are added by the caller
+	sqlDeleteVDiffTableRepairs       = "delete from _vt.vdiff_repair where vdiff_id = %a and table_name = %a"
+	sqlDeleteVDiffTableRepairsByType = "delete from _vt.vdiff_repair where vdiff_id = %a and table_name = %a and type = %a and state = 'pending'"
+	// sqlDeleteVDiffReconciledRepairs deletes the pending insert and delete
+	// repairs of the rows that were found on both sides, i.e. that are both an
+	// extra source row and an extra target row with the same values.
+	sqlDeleteVDiffReconciledRepairs = `delete vdri, vdrd from _vt.vdiff_repair as vdri inner join _vt.vdiff_repair as vdrd on
+									(vdri.vdiff_id = vdrd.vdiff_id and vdri.table_name = vdrd.table_name and vdri.row_values = vdrd.row_values)
+									where vdri.vdiff_id = %a and vdri.table_name = %a and vdri.type = 'insert' and vdri.state = 'pending'
+									and vdrd.type = 'delete' and vdrd.state = 'pending'`
+	sqlGetPendingVDiffRepairs = `select id as id, table_name as table_name, type as type, statement as statement, row_values as row_values,
+								source_query as source_query, target_query as target_query from _vt.vdiff_repair
+								where vdiff_id = %a and state = 'pending' order by field(type, 'delete', 'insert', 'update'), id limit %a`
+	sqlUpdateVDiffRepairsState = "update _vt.vdiff_repair set state = %a, applied_at = now() where id in %a"
+	sqlVDiffRepairSummary      = `select table_name as table_name, type as type, state as state, count(*) as statements from _vt.vdiff_repair
+								where vdiff_id = %a group by table_name, type, state order by table_name, type, state`
+	sqlGetVReplicationSources   = "select source as source from _vt.vreplication where db_name = %a and workflow = %a"
+	sqlGetVDiffContinuousPos    = "select continuous_pos as continuous_pos from _vt.vdiff where id = %a"
+	sqlUpdateVDiffContinuousPos = "update _vt.vdiff set continuous_pos = %a where id = %a"
 )
@@ -131,6 +131,57 @@ func (vds *vdiffStats) register() {
 		},
 	)
 
+	stats.NewGaugesFuncWithMultiLabels(
+		"VDiffChecksumRanges",
+		"Live number of primary key ranges whose checksums matched or not per checksum vdiff by table",
+		[]string{"workflow", "uuid", "table", "result"},
+		func() map[string]int64 {
+			vds.mu.Lock()
+			defer vds.mu.Unlock()
+			result := make(map[string]int64, len(vds.controllers))
+			for _, ct := range vds.controllers {
+				for key, val := range ct.TableChecksumRangeCounts.Counts() {
+					result[fmt.Sprintf("%s.%s.%s", ct.workflow, ct.uuid, key)] = val
+				}
+			}
+			return result
+		},
+	)
+
+	stats.NewGaugesFuncWithMultiLabels(
+		"VDiffContinuousRows",
+		"Number of changed rows verified, and of those found to differ, by continuous vdiffs",
+		[]string{"workflow", "uuid", "table", "result"},
+		func() map[string]int64 {
+			vds.mu.Lock()
+			defer vds.mu.Unlock()
+			result := make(map[string]int64, len(vds.controllers))
+			for _, ct := range vds.controllers {
+				for key, val := range ct.ContinuousRowCounts.Counts() {
+					result[fmt.Sprintf("%s.%s.%s", ct.workflow, ct.uuid, key)] = val
+				}
+			}
+			return result
+		},
+	)
+
+	stats.NewGaugesFuncWithMultiLabels(
+		"VDiffContinuousCheckpointTimestamp",
+		"Time of the last checkpoint of continuous vdiffs, in seconds since the epoch",
+		[]string{"workflow", "uuid"},
+		func() map[string]int64 {
+			vds.mu.Lock()
+			defer vds.mu.Unlock()
+			result := make(map[string]int64, len(vds.controllers))
+			for _, ct := range vds.controllers {
+				if checkpoint := ct.ContinuousCheckpoint.Get(); checkpoint > 0 {
+					result[fmt.Sprintf("%s.%s", ct.workflow, ct.uuid)] = checkpoint
+				}
+			}
+			return result
+		},
+	)
+
 	stats.NewGaugesFuncWithMultiLabels(
 		"VDiffPhaseTimings",
 		"VDiff phase timings",
@@ -63,6 +63,8 @@ const (
 	startingTargets        = tableDiffPhase("starting_target_data_streams")
 	restartingVreplication = tableDiffPhase("restarting_vreplication_streams")
 	diffingTable           = tableDiffPhase("diffing_table")
+	stoppingReplication    = tableDiffPhase("stopping_source_replication")
+	checksummingTable      = tableDiffPhase("checksumming_table")
 )
 
 // how long to wait for background operations to complete
@@ -99,6 +101,14 @@ type tableDiffer struct {
 	wgShardStreamers   sync.WaitGroup
 	shardStreamsCtx    context.Context
 	shardStreamsCancel context.CancelFunc
+
+	// repairs are the buffered statements that repair the differences found,
+	// when repairs are enabled, and repairsSize is their total size.
+	repairs     []repairStatement
+	repairsSize int
+	// repairPlan holds the queries that read a row by primary key on the
+	// source and the target, which are recorded with its repair statement.
+	repairPlan *checksumPlan
 }
 
 func newTableDiffer(wd *workflowDiffer, table *tabletmanagerdatapb.TableDefinition, sourceQuery string) *tableDiffer {
@@ -501,33 +511,13 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 	// We need to continue were we left off when appropriate. This can be an
 	// auto-retry on error, or a manual retry via the resume command.
 	// Otherwise the existing state will be empty and we start from scratch.
-	query, err := sqlparser.ParseAndBind(sqlGetVDiffTable,
-		sqltypes.Int64BindVariable(td.wd.ct.id),
-		sqltypes.StringBindVariable(td.table.Name),
-	)
+	dr, mismatch, err := td.getDiffReport(dbClient)
 	if err != nil {
 		return nil, err
 	}
-	cs, err := dbClient.ExecuteFetch(query, -1)
-	if err != nil {
+	if err := td.startRepairs(dbClient); err != nil {
 		return nil, err
 	}
-	if len(cs.Rows) == 0 {
-		return nil, fmt.Errorf("no state found for vdiff table %s for vdiff_id %d on tablet %v",
-			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
-	} else if len(cs.Rows) > 1 {
-		return nil, fmt.Errorf("invalid state found for vdiff table %s (multiple records) for vdiff_id %d on tablet %v",
-			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
-	}
-	curState := cs.Named().Row()
-	mismatch := curState.AsBool("mismatch", false)
-	dr := &DiffReport{}
-	if rpt := curState.AsBytes("report", []byte("{}")); json.Valid(rpt) {
-		if err = json.Unmarshal(rpt, dr); err != nil {
-			return nil, err
-		}
-	}
-	dr.TableName = td.table.Name
 
 	sourceExecutor := newPrimitiveExecutor(ctx, td.sourcePrimitive, "source")
 	targetExecutor := newPrimitiveExecutor(ctx, td.targetPrimitive, "target")
@@ -601,9 +591,12 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 				return nil, vterrors.Wrap(err, "unexpected error generating diff")
 			}
 			dr.ExtraRowsTargetDiffs = append(dr.ExtraRowsTargetDiffs, diffRow)
+			if err := td.recordRepair(dbClient, repairDelete, targetRow); err != nil {
+				return nil, err
+			}
 
 			// Drain target, update count.
-			count, err := targetExecutor.drain(ctx)
+			count, err := td.drainRepairs(ctx, dbClient, targetExecutor, repairDelete)
 			if err != nil {
 				return nil, err
 			}
@@ -619,7 +612,10 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 				return nil, vterrors.Wrap(err, "unexpected error generating diff")
 			}
 			dr.ExtraRowsSourceDiffs = append(dr.ExtraRowsSourceDiffs, diffRow)
-			count, err := sourceExecutor.drain(ctx)
+			if err := td.recordRepair(dbClient, repairInsert, sourceRow); err != nil {
+				return nil, err
+			}
+			count, err := td.drainRepairs(ctx, dbClient, sourceExecutor, repairInsert)
 			if err != nil {
 				return nil, err
 			}
@@ -643,6 +639,9 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 				}
 				dr.ExtraRowsSourceDiffs = append(dr.ExtraRowsSourceDiffs, diffRow)
 			}
+			if err := td.recordRepair(dbClient, repairInsert, sourceRow); err != nil {
+				return nil, err
+			}
 			dr.ExtraRowsSource++
 			advanceTarget = false
 			continue
@@ -654,6 +653,9 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 				}
 				dr.ExtraRowsTargetDiffs = append(dr.ExtraRowsTargetDiffs, diffRow)
 			}
+			if err := td.recordRepair(dbClient, repairDelete, targetRow); err != nil {
+				return nil, err
+			}
 			dr.ExtraRowsTarget++
 			advanceSource = false
 			continue
@@ -678,6 +680,9 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 				}
 				dr.MismatchedRowsDiffs = append(dr.MismatchedRowsDiffs, &DiffMismatch{Source: sourceDiffRow, Target: targetDiffRow})
 			}
+			if err := td.recordRepair(dbClient, repairUpdate, sourceRow); err != nil {
+				return nil, err
+			}
 			dr.MismatchedRows++
 		default:
 			dr.MatchingRows++
@@ -694,6 +699,39 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 	}
 }
 
+// getDiffReport returns the report of the table diff saved so far, and whether
+// the table has been flagged as mismatched.
+func (td *tableDiffer) getDiffReport(dbClient binlogplayer.DBClient) (*DiffReport, bool, error) {
+	query, err := sqlparser.ParseAndBind(sqlGetVDiffTable,
+		sqltypes.Int64BindVariable(td.wd.ct.id),
+		sqltypes.StringBindVariable(td.table.Name),
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	cs, err := dbClient.ExecuteFetch(query, -1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(cs.Rows) == 0 {
+		return nil, false, fmt.Errorf("no state found for vdiff table %s for vdiff_id %d on tablet %v",
+			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
+	} else if len(cs.Rows) > 1 {
+		return nil, false, fmt.Errorf("invalid state found for vdiff table %s (multiple records) for vdiff_id %d on tablet %v",
+			td.table.Name, td.wd.ct.id, td.wd.ct.vde.thisTablet.Alias)
+	}
+	curState := cs.Named().Row()
+	mismatch := curState.AsBool("mismatch", false)
+	dr := &DiffReport{}
+	if rpt := curState.AsBytes("report", []byte("{}")); json.Valid(rpt) {
+		if err = json.Unmarshal(rpt, dr); err != nil {
+			return nil, false, err
+		}
+	}
+	dr.TableName = td.table.Name
+	return dr, mismatch, nil
+}
+
 func (td *tableDiffer) compare(sourceRow, targetRow []sqltypes.Value, cols []compareColInfo, compareOnlyNonPKs bool) (int, error) {
 	for _, col := range cols {
 		if col.isPK && compareOnlyNonPKs {
@@ -725,6 +763,11 @@ func (td *tableDiffer) updateTableProgress(dbClient binlogplayer.DBClient, dr *D
 	if dr == nil {
 		return fmt.Errorf("cannot update progress with a nil diff report")
 	}
+	// Save the repair statements before the progress, so that none are lost
+	// when the diff is resumed.
+	if err := td.flushRepairs(dbClient); err != nil {
+		return err
+	}
 
 	var err error
 	var query string
@@ -82,6 +82,9 @@ func (td *tableDiffer) buildTablePlan(dbClient binlogplayer.DBClient, dbName str
 	if !ok {
 		return nil, fmt.Errorf("unexpected: %v", sqlparser.String(statement))
 	}
+	if _, ok := sel.From[0].(*sqlparser.AliasedTableExpr); !ok || len(sel.From) > 1 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: VDiff of table %s, which is materialized from a join", td.table.Name)
+	}
 
 	sourceSelect := &sqlparser.Select{}
 	targetSelect := &sqlparser.Select{}
@@ -124,6 +127,16 @@ func (td *tableDiffer) buildTablePlan(dbClient binlogplayer.DBClient, dbName str
 						/*offset*/ sourceSelect.GetColumnCount()-1,
 						/*alias*/ "", collationEnv),
 					)
+				case "min", "max":
+					aggregates = append(aggregates, engine.NewAggregateParam(
+						/*opcode*/ opcode.SupportedAggregates[fname],
+						/*offset*/ sourceSelect.GetColumnCount()-1,
+						/*alias*/ "", collationEnv),
+					)
+				case "avg":
+					// The averages of the source shards cannot be merged.
+					return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: VDiff of table %s, which is materialized with an average: %v",
+						td.table.Name, sqlparser.String(expr))
 				}
 			}
 		default:
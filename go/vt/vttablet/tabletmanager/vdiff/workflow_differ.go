@@ -232,6 +232,23 @@ func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.D
 		return err
 	}
 
+	useChecksums := wd.opts.CoreOptions.GetChecksum()
+	if useChecksums {
+		reason, err := td.checksumUnsupportedReason()
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			log.Infof("Diffing table %s for vdiff %s by streaming its rows rather than with checksums, as %s", td.table.Name, wd.ct.uuid, reason)
+			insertVDiffLog(ctx, dbClient, wd.ct.id, fmt.Sprintf("Table %s is not diffed with checksums, as %s", encodeString(td.table.Name), reason))
+			useChecksums = false
+		}
+	}
+	if wd.opts.CoreOptions.GetRepair() && !td.repairEnabled() {
+		insertVDiffLog(ctx, dbClient, wd.ct.id, fmt.Sprintf("Repair statements are not recorded for table %s, as the workflow converts its timestamps from the %s time zone",
+			encodeString(td.table.Name), wd.ct.sourceTimeZone))
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -254,12 +271,25 @@ func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.D
 			// before we pick up where we left off (but with new database snapshots).
 			time.Sleep(30 * time.Second)
 		}
-		if err := td.initialize(ctx); err != nil { // Setup the consistent snapshots
-			return err
+		if useChecksums {
+			// The source tablets and the target are stopped at the same
+			// position for each chunk of the table.
+			checksumCtx, release, err := td.lockChecksum(ctx)
+			if err != nil {
+				return err
+			}
+			log.Infof("Table initialization done on table %s for checksum vdiff %s", td.table.Name, wd.ct.uuid)
+			diffTimer = time.NewTimer(maxDiffRuntime)
+			diffReport, diffErr = td.checksumDiff(checksumCtx, wd.opts.CoreOptions, wd.opts.ReportOptions, diffTimer.C)
+			release()
+		} else {
+			if err := td.initialize(ctx); err != nil { // Setup the consistent snapshots
+				return err
+			}
+			log.Infof("Table initialization done on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
+			diffTimer = time.NewTimer(maxDiffRuntime)
+			diffReport, diffErr = td.diff(ctx, wd.opts.CoreOptions, wd.opts.ReportOptions, diffTimer.C)
 		}
-		log.Infof("Table initialization done on table %s for vdiff %s", td.table.Name, wd.ct.uuid)
-		diffTimer = time.NewTimer(maxDiffRuntime)
-		diffReport, diffErr = td.diff(ctx, wd.opts.CoreOptions, wd.opts.ReportOptions, diffTimer.C)
 		if diffErr == nil { // We finished the diff successfully
 			break
 		}
@@ -271,10 +301,15 @@ func (wd *workflowDiffer) diffTable(ctx context.Context, dbClient binlogplayer.D
 	log.Infof("Table diff done on table %s for vdiff %s with report: %+v", td.table.Name, wd.ct.uuid, diffReport)
 
 	if diffReport.ExtraRowsSource > 0 || diffReport.ExtraRowsTarget > 0 {
+		extraRowsSource, extraRowsTarget := diffReport.ExtraRowsSource, diffReport.ExtraRowsTarget
 		if err := wd.reconcileExtraRows(diffReport, wd.opts.CoreOptions.MaxExtraRowsToCompare, wd.opts.ReportOptions.MaxSampleRows); err != nil {
 			log.Errorf("Encountered an error reconciling extra rows found for table %s for vdiff %s: %v", td.table.Name, wd.ct.uuid, err)
 			return vterrors.Wrap(err, "failed to reconcile extra rows")
 		}
+		if err := td.reconcileRepairs(dbClient, extraRowsSource > 0 && diffReport.ExtraRowsSource == 0,
+			extraRowsTarget > 0 && diffReport.ExtraRowsTarget == 0); err != nil {
+			return err
+		}
 	}
 
 	if diffReport.MismatchedRows > 0 || diffReport.ExtraRowsTarget > 0 || diffReport.ExtraRowsSource > 0 {
@@ -323,6 +358,12 @@ func (wd *workflowDiffer) diff(ctx context.Context) (err error) {
 	if err := wd.initVDiffTables(dbClient); err != nil {
 		return err
 	}
+	continuousInterval := time.Duration(wd.opts.CoreOptions.GetContinuousIntervalSeconds()) * time.Second
+	if continuousInterval > 0 {
+		if err := wd.initContinuousPos(ctx, dbClient); err != nil {
+			return err
+		}
+	}
 	for _, td := range wd.tableDiffers {
 		select {
 		case <-ctx.Done():
@@ -360,6 +401,9 @@ func (wd *workflowDiffer) diff(ctx context.Context) (err error) {
 		}
 		log.Infof("Completed diff of table %s for vdiff %s", td.table.Name, wd.ct.uuid)
 	}
+	if continuousInterval > 0 {
+		return wd.diffContinuously(ctx, dbClient, continuousInterval)
+	}
 	if err := wd.markIfCompleted(ctx, dbClient); err != nil {
 		return err
 	}
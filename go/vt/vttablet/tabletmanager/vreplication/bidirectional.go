@@ -0,0 +1,313 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"vitess.io/vitess/go/constants/sidecar"
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// The streams of a bidirectional workflow replicate the changes between two
+// keyspaces in both directions. A transaction applied by one direction must
+// not be replicated back by the other one, the peer workflow: every target
+// transaction starts by saving the position of the stream in
+// _vt.vreplication, which the peer, streaming this internal table, sees
+// before any other row change of the transaction and uses to skip it.
+
+const (
+	conflictApplied = "applied"
+	conflictSkipped = "skipped"
+)
+
+// markTransaction saves the position of the current source transaction at
+// the start of the target transaction. The position always changes, so
+// that the update is always written to the binlogs of the target.
+func (vp *vplayer) markTransaction(ctx context.Context, ts int64) error {
+	update := binlogplayer.GenerateUpdatePos(vp.vr.id, vp.pos, time.Now().Unix(), ts, vp.vr.stats.CopyRowCount.Get(), vp.vr.workflowConfig.StoreCompressedGTID)
+	if _, err := vp.query(ctx, update); err != nil {
+		return fmt.Errorf("error %v marking transaction", err)
+	}
+	return nil
+}
+
+// isPeerTransaction returns true if the row event of the _vt.vreplication
+// table updates a stream of the peer workflow, which means the transaction
+// was applied by it.
+func (vp *vplayer) isPeerTransaction(rowEvent *binlogdatapb.RowEvent) bool {
+	if rowEvent.TableName != vreplicationTableName {
+		return false
+	}
+	fields := vp.internalFields[rowEvent.TableName]
+	workflowIndex := -1
+	for i, field := range fields {
+		if field.Name == "workflow" {
+			workflowIndex = i
+			break
+		}
+	}
+	if workflowIndex == -1 {
+		return false
+	}
+	peer := vp.vr.source.Bidirectional.PeerWorkflow
+	for _, change := range rowEvent.RowChanges {
+		row := change.After
+		if row == nil {
+			row = change.Before
+		}
+		vals := sqltypes.MakeRowTrusted(fields, row)
+		if workflowIndex < len(vals) && vals[workflowIndex].ToString() == peer {
+			return true
+		}
+	}
+	return false
+}
+
+// hasConflictDetection returns true if the rows of the table are replicated
+// as is, which is needed to compare them with the rows of the target.
+func (vp *vplayer) hasConflictDetection(tplan *TablePlan) (bool, error) {
+	ok, found := vp.conflictTables[tplan.TargetName]
+	if !found {
+		var err error
+		ok, err = reconcilableTable(vp.vr.source.Filter, tplan.TargetName, vp.vr.vre.env.Parser())
+		if err != nil {
+			return false, err
+		}
+		vp.conflictTables[tplan.TargetName] = ok
+	}
+	return ok && len(tplan.PKIndices) == len(tplan.Fields), nil
+}
+
+// resolveConflict compares the before image of a row change with the current
+// row of the target: there is a conflict if they differ, or if an inserted
+// row already exists. It returns the change to apply, which is nil if the
+// change is skipped, and records the conflicts in _vt.vreplication_conflict.
+func (vp *vplayer) resolveConflict(ctx context.Context, tplan *TablePlan, change *binlogdatapb.RowChange) (*binlogdatapb.RowChange, error) {
+	ok, err := vp.hasConflictDetection(tplan)
+	if err != nil || !ok {
+		return change, err
+	}
+	key := change.Before
+	if key == nil {
+		key = change.After
+	}
+	target, err := vp.readTargetRow(ctx, tplan, key)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case change.Before == nil && target == nil:
+		return change, nil
+	case change.Before != nil && target != nil && (tplan.isPartial(change) || equalRows(tplan.Fields, sqltypes.MakeRowTrusted(tplan.Fields, change.Before), target)):
+		return change, nil
+	}
+
+	wins, err := vp.incomingChangeWins(tplan, change, target)
+	if err != nil {
+		return nil, err
+	}
+	if err := vp.recordConflict(ctx, tplan, change, target, wins); err != nil {
+		return nil, err
+	}
+	switch {
+	case !wins:
+		return nil, nil
+	case change.Before == nil:
+		// The inserted row already exists, so it is updated.
+		return &binlogdatapb.RowChange{
+			Before:            sqltypes.RowToProto3(target),
+			After:             change.After,
+			DataColumns:       change.DataColumns,
+			JsonPartialValues: change.JsonPartialValues,
+		}, nil
+	case change.After != nil && target == nil:
+		// The updated row does not exist anymore, so it is inserted.
+		return &binlogdatapb.RowChange{
+			After:             change.After,
+			DataColumns:       change.DataColumns,
+			JsonPartialValues: change.JsonPartialValues,
+		}, nil
+	default:
+		// Updates and deletes are applied by primary key.
+		return change, nil
+	}
+}
+
+// readTargetRow returns the current row of the target with the primary key
+// of the row image, or nil if there is none.
+func (vp *vplayer) readTargetRow(ctx context.Context, tplan *TablePlan, row *querypb.Row) ([]sqltypes.Value, error) {
+	vals := sqltypes.MakeRowTrusted(tplan.Fields, row)
+	bindvars := make(map[string]*querypb.BindVariable)
+	buf := sqlparser.NewTrackedBuffer(nil)
+	buf.WriteString("select ")
+	for i, field := range tplan.Fields {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.Myprintf("%v", sqlparser.NewIdentifierCI(field.Name))
+	}
+	buf.Myprintf(" from %v where ", sqlparser.NewIdentifierCS(tplan.TargetName))
+	separator := ""
+	for i, field := range tplan.Fields {
+		if !tplan.PKIndices[i] {
+			continue
+		}
+		bindVar, err := tplan.bindFieldVal(field, &vals[i])
+		if err != nil {
+			return nil, err
+		}
+		name := fmt.Sprintf("pk%d", i)
+		bindvars[name] = bindVar
+		buf.Myprintf("%s%v = %v", separator, sqlparser.NewIdentifierCI(field.Name), sqlparser.NewArgument(name))
+		separator = " and "
+	}
+	buf.WriteString(" for update")
+	query, err := buf.ParsedQuery().GenerateQuery(bindvars, nil)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := vp.query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(qr.Rows) == 0 {
+		return nil, nil
+	}
+	return qr.Rows[0], nil
+}
+
+// equalRows compares the values of two rows, but for their JSON values whose
+// formatting may differ.
+func equalRows(fields []*querypb.Field, a, b []sqltypes.Value) bool {
+	for i, field := range fields {
+		if field.Type == querypb.Type_JSON {
+			continue
+		}
+		if a[i].IsNull() != b[i].IsNull() || !bytes.Equal(a[i].Raw(), b[i].Raw()) {
+			return false
+		}
+	}
+	return true
+}
+
+// incomingChangeWins applies the conflict resolution policy of the workflow
+// to a row change and the conflicting row of the target.
+func (vp *vplayer) incomingChangeWins(tplan *TablePlan, change *binlogdatapb.RowChange, target []sqltypes.Value) (bool, error) {
+	settings := vp.vr.source.Bidirectional
+	fromSourceKeyspace := vp.vr.source.Keyspace == settings.SourceKeyspace
+	if settings.ConflictResolution != binlogdatapb.ConflictResolution_LAST_WRITER_WINS {
+		return fromSourceKeyspace, nil
+	}
+	if target == nil {
+		// There is no row of the target to compare with.
+		return true, nil
+	}
+	index := -1
+	for i, field := range tplan.Fields {
+		if field.Name == settings.TimestampColumn {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return false, fmt.Errorf("timestamp column %s not found in table %s", settings.TimestampColumn, tplan.TargetName)
+	}
+	row := change.After
+	if row == nil {
+		row = change.Before
+	}
+	incoming := sqltypes.MakeRowTrusted(tplan.Fields, row)[index]
+	field := tplan.Fields[index]
+	cmp, err := evalengine.NullsafeCompare(incoming, target[index], vp.vr.vre.env.CollationEnv(), collations.ID(field.Charset), nil)
+	if err != nil {
+		return false, err
+	}
+	if cmp == 0 {
+		return fromSourceKeyspace, nil
+	}
+	return cmp > 0, nil
+}
+
+// recordConflict inserts a conflict into _vt.vreplication_conflict, in the
+// transaction applying the change.
+func (vp *vplayer) recordConflict(ctx context.Context, tplan *TablePlan, change *binlogdatapb.RowChange, target []sqltypes.Value, applied bool) error {
+	changeType := "update"
+	switch {
+	case change.Before == nil:
+		changeType = "insert"
+	case change.After == nil:
+		changeType = "delete"
+	}
+	resolution := conflictSkipped
+	if applied {
+		resolution = conflictApplied
+	}
+	var before, after []sqltypes.Value
+	if change.Before != nil {
+		before = sqltypes.MakeRowTrusted(tplan.Fields, change.Before)
+	}
+	if change.After != nil {
+		after = sqltypes.MakeRowTrusted(tplan.Fields, change.After)
+	}
+	values := make([]string, 0, 3)
+	for _, row := range [][]sqltypes.Value{before, after, target} {
+		value, err := rowToJSON(tplan.Fields, row)
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+	}
+	query := fmt.Sprintf("insert into %s.vreplication_conflict(vrepl_id, table_name, change_type, source_before, source_after, target_row, resolution) values (%d, %s, %s, %s, %s, %s, %s)",
+		sidecar.GetIdentifier(), vp.vr.id, encodeString(tplan.TargetName), encodeString(changeType), values[0], values[1], values[2], encodeString(resolution))
+	if _, err := vp.query(ctx, query); err != nil {
+		return fmt.Errorf("error %v recording conflict", err)
+	}
+	return nil
+}
+
+// rowToJSON returns the SQL literal of a JSON object mapping the columns of
+// a row to their values, or null if there is no row.
+func rowToJSON(fields []*querypb.Field, row []sqltypes.Value) (string, error) {
+	if row == nil {
+		return "null", nil
+	}
+	obj := make(map[string]any, len(fields))
+	for i, field := range fields {
+		if row[i].IsNull() {
+			obj[field.Name] = nil
+			continue
+		}
+		obj[field.Name] = row[i].ToString()
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	return encodeString(string(b)), nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestIsPeerTransaction(t *testing.T) {
+	fields := sqltypes.MakeTestFields("id|workflow|pos", "int32|varbinary|varbinary")
+	vp := &vplayer{
+		vr: &vreplicator{source: &binlogdatapb.BinlogSource{
+			Bidirectional: &binlogdatapb.BidirectionalSettings{PeerWorkflow: "wf_reverse"},
+		}},
+		internalFields: map[string][]*querypb.Field{vreplicationTableName: fields},
+	}
+	rowEvent := func(workflow string) *binlogdatapb.RowEvent {
+		row := sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt32(1), sqltypes.NewVarBinary(workflow), sqltypes.NewVarBinary("pos")})
+		return &binlogdatapb.RowEvent{
+			TableName:       vreplicationTableName,
+			IsInternalTable: true,
+			RowChanges:      []*binlogdatapb.RowChange{{Before: row, After: row}},
+		}
+	}
+	require.True(t, vp.isPeerTransaction(rowEvent("wf_reverse")))
+	require.False(t, vp.isPeerTransaction(rowEvent("wf")))
+	require.False(t, vp.isPeerTransaction(rowEvent("other")))
+}
+
+func TestResolveConflict(t *testing.T) {
+	fields := sqltypes.MakeTestFields("id|val|updated_at", "int64|varchar|int64")
+	tplan := &TablePlan{
+		TargetName: "t1",
+		Fields:     fields,
+		PKIndices:  []bool{true, false, false},
+	}
+	row := func(id int64, val string, updatedAt int64) *querypb.Row {
+		return sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NewVarChar(val), sqltypes.NewInt64(updatedAt)})
+	}
+	targetRow := func(id int64, val string, updatedAt int64) *sqltypes.Result {
+		return sqltypes.MakeTestResult(fields, strings.Join([]string{sqltypes.NewInt64(id).ToString(), val, sqltypes.NewInt64(updatedAt).ToString()}, "|"))
+	}
+
+	testCases := []struct {
+		name       string
+		keyspace   string
+		resolution binlogdatapb.ConflictResolution
+		change     *binlogdatapb.RowChange
+		target     *sqltypes.Result
+		want       *binlogdatapb.RowChange
+		conflict   string
+	}{{
+		name:     "update without conflict",
+		keyspace: "ks1",
+		change:   &binlogdatapb.RowChange{Before: row(1, "a", 1), After: row(1, "b", 2)},
+		target:   targetRow(1, "a", 1),
+		want:     &binlogdatapb.RowChange{Before: row(1, "a", 1), After: row(1, "b", 2)},
+	}, {
+		name:     "insert without conflict",
+		keyspace: "ks2",
+		change:   &binlogdatapb.RowChange{After: row(1, "a", 1)},
+		target:   &sqltypes.Result{Fields: fields},
+		want:     &binlogdatapb.RowChange{After: row(1, "a", 1)},
+	}, {
+		name:     "source wins on the forward stream",
+		keyspace: "ks1",
+		change:   &binlogdatapb.RowChange{Before: row(1, "a", 1), After: row(1, "b", 2)},
+		target:   targetRow(1, "c", 3),
+		want:     &binlogdatapb.RowChange{Before: row(1, "a", 1), After: row(1, "b", 2)},
+		conflict: "'update', '{\"id\":\"1\",\"updated_at\":\"1\",\"val\":\"a\"}', '{\"id\":\"1\",\"updated_at\":\"2\",\"val\":\"b\"}', '{\"id\":\"1\",\"updated_at\":\"3\",\"val\":\"c\"}', 'applied'",
+	}, {
+		name:     "source wins and the reverse stream skips the change",
+		keyspace: "ks2",
+		change:   &binlogdatapb.RowChange{Before: row(1, "a", 1), After: row(1, "b", 2)},
+		target:   targetRow(1, "c", 3),
+		conflict: "'skipped'",
+	}, {
+		name:     "source wins and the existing row is updated",
+		keyspace: "ks1",
+		change:   &binlogdatapb.RowChange{After: row(1, "a", 1)},
+		target:   targetRow(1, "c", 3),
+		want:     &binlogdatapb.RowChange{Before: row(1, "c", 3), After: row(1, "a", 1)},
+		conflict: "'insert', null, '{\"id\":\"1\",\"updated_at\":\"1\",\"val\":\"a\"}'",
+	}, {
+		name:     "source wins and the deleted row is inserted",
+		keyspace: "ks1",
+		change:   &binlogdatapb.RowChange{Before: row(1, "a", 1), After: row(1, "b", 2)},
+		target:   &sqltypes.Result{Fields: fields},
+		want:     &binlogdatapb.RowChange{After: row(1, "b", 2)},
+		conflict: "'{\"id\":\"1\",\"updated_at\":\"2\",\"val\":\"b\"}', null, 'applied'",
+	}, {
+		name:       "last writer wins with a later change",
+		keyspace:   "ks2",
+		resolution: binlogdatapb.ConflictResolution_LAST_WRITER_WINS,
+		change:     &binlogdatapb.RowChange{Before: row(1, "a", 1), After: row(1, "b", 4)},
+		target:     targetRow(1, "c", 3),
+		want:       &binlogdatapb.RowChange{Before: row(1, "a", 1), After: row(1, "b", 4)},
+		conflict:   "'applied'",
+	}, {
+		name:       "last writer wins with an earlier change",
+		keyspace:   "ks1",
+		resolution: binlogdatapb.ConflictResolution_LAST_WRITER_WINS,
+		change:     &binlogdatapb.RowChange{Before: row(1, "a", 1), After: row(1, "b", 2)},
+		target:     targetRow(1, "c", 3),
+		conflict:   "'skipped'",
+	}, {
+		name:       "last writer wins ties are won by the source keyspace",
+		keyspace:   "ks1",
+		resolution: binlogdatapb.ConflictResolution_LAST_WRITER_WINS,
+		change:     &binlogdatapb.RowChange{Before: row(1, "a", 1), After: row(1, "b", 3)},
+		target:     targetRow(1, "c", 3),
+		want:       &binlogdatapb.RowChange{Before: row(1, "a", 1), After: row(1, "b", 3)},
+		conflict:   "'applied'",
+	}, {
+		name:       "last writer wins ties are lost by the target keyspace",
+		keyspace:   "ks2",
+		resolution: binlogdatapb.ConflictResolution_LAST_WRITER_WINS,
+		change:     &binlogdatapb.RowChange{Before: row(1, "a", 1), After: row(1, "b", 3)},
+		target:     targetRow(1, "c", 3),
+		conflict:   "'skipped'",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var queries []string
+			vp := &vplayer{
+				vr: &vreplicator{
+					id:  1,
+					vre: &Engine{env: vtenv.NewTestEnv()},
+					source: &binlogdatapb.BinlogSource{
+						Keyspace: tc.keyspace,
+						Filter: &binlogdatapb.Filter{Rules: []*binlogdatapb.Rule{{
+							Match:  "t1",
+							Filter: "select * from t1",
+						}}},
+						Bidirectional: &binlogdatapb.BidirectionalSettings{
+							ConflictResolution: tc.resolution,
+							TimestampColumn:    "updated_at",
+							PeerWorkflow:       "wf_reverse",
+							SourceKeyspace:     "ks1",
+						},
+					},
+				},
+				conflictTables: make(map[string]bool),
+				query: func(ctx context.Context, sql string) (*sqltypes.Result, error) {
+					queries = append(queries, sql)
+					if strings.HasPrefix(sql, "select") {
+						return tc.target, nil
+					}
+					return &sqltypes.Result{}, nil
+				},
+			}
+			got, err := vp.resolveConflict(context.Background(), tplan, tc.change)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+			require.Equal(t, "select id, val, updated_at from t1 where id = 1 for update", queries[0])
+			if tc.conflict == "" {
+				require.Len(t, queries, 1)
+				return
+			}
+			require.Len(t, queries, 2)
+			require.True(t, strings.HasPrefix(queries[1], "insert into _vt.vreplication_conflict"), queries[1])
+			require.Contains(t, queries[1], tc.conflict)
+		})
+	}
+}
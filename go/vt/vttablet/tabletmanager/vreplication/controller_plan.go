@@ -45,6 +45,9 @@ type controllerPlan struct {
 
 	// delPostCopyAction deletes related post copy actions.
 	delPostCopyAction *sqlparser.ParsedQuery
+
+	// delCopyRangeState deletes related copy range state.
+	delCopyRangeState *sqlparser.ParsedQuery
 }
 
 const (
@@ -365,12 +368,16 @@ func buildDeletePlan(del *sqlparser.Delete) (*controllerPlan, error) {
 	buf4 := sqlparser.NewTrackedBuffer(nil)
 	buf4.Myprintf("delete from %s.%s%v", sidecar.GetIdentifier(), postCopyActionTableName, copyStateWhere)
 
+	buf5 := sqlparser.NewTrackedBuffer(nil)
+	buf5.Myprintf("delete from %s.%s%v", sidecar.GetIdentifier(), copyRangeStateTableName, copyStateWhere)
+
 	return &controllerPlan{
 		opcode:            deleteQuery,
 		selector:          buf1.String(),
 		applier:           buf2.ParsedQuery(),
 		delCopyState:      buf3.ParsedQuery(),
 		delPostCopyAction: buf4.ParsedQuery(),
+		delCopyRangeState: buf5.ParsedQuery(),
 	}, nil
 }
 
@@ -392,7 +399,7 @@ func buildSelectPlan(sel *sqlparser.Select) (*controllerPlan, error) {
 		return nil, fmt.Errorf("invalid database name: %s", tableName.Qualifier.String())
 	}
 	switch tableName.Name.String() {
-	case vreplicationTableName, reshardingJournalTableName, copyStateTableName, vreplicationLogTableName:
+	case vreplicationTableName, reshardingJournalTableName, copyStateTableName, copyRangeStateTableName, vreplicationLogTableName:
 		return &controllerPlan{
 			opcode: selectQuery,
 		}, nil
@@ -33,6 +33,7 @@ type testControllerPlan struct {
 	applier           string
 	delCopyState      string
 	delPostCopyAction string
+	delCopyRangeState string
 }
 
 func TestControllerPlan(t *testing.T) {
@@ -170,6 +171,7 @@ func TestControllerPlan(t *testing.T) {
 			applier:           "delete from _vt.vreplication where id in ::ids",
 			delCopyState:      "delete from _vt.copy_state where vrepl_id in ::ids",
 			delPostCopyAction: "delete from _vt.post_copy_action where vrepl_id in ::ids",
+			delCopyRangeState: "delete from _vt.copy_range_state where vrepl_id in ::ids",
 		},
 	}, {
 		in:  "delete from _vt.vreplication",
@@ -183,6 +185,7 @@ func TestControllerPlan(t *testing.T) {
 			applier:           "delete /*vt+ ALLOW_UNSAFE_VREPLICATION_WRITE */ from _vt.vreplication where id in ::ids",
 			delCopyState:      "delete from _vt.copy_state where vrepl_id in ::ids",
 			delPostCopyAction: "delete from _vt.post_copy_action where vrepl_id in ::ids",
+			delCopyRangeState: "delete from _vt.copy_range_state where vrepl_id in ::ids",
 		},
 	}, {
 		in:  "delete from _vt.vreplication where state='Stopped'",
@@ -196,6 +199,7 @@ func TestControllerPlan(t *testing.T) {
 			applier:           "delete from _vt.vreplication where id in ::ids",
 			delCopyState:      "delete from _vt.copy_state where vrepl_id in ::ids",
 			delPostCopyAction: "delete from _vt.post_copy_action where vrepl_id in ::ids",
+			delCopyRangeState: "delete from _vt.copy_range_state where vrepl_id in ::ids",
 		},
 		err: "unsafe WHERE clause in delete without the /*vt+ ALLOW_UNSAFE_VREPLICATION_WRITE */ comment directive:  where a = 1; should be using = or in with at least one of the following columns: id, workflow",
 	}, {
@@ -239,6 +243,12 @@ func TestControllerPlan(t *testing.T) {
 			opcode: selectQuery,
 			query:  "select * from _vt.copy_state",
 		},
+	}, {
+		in: "select * from _vt.copy_range_state",
+		plan: &testControllerPlan{
+			opcode: selectQuery,
+			query:  "select * from _vt.copy_range_state",
+		},
 	}, {
 		in: "select * from _vt.vreplication_log",
 		plan: &testControllerPlan{
@@ -284,6 +294,9 @@ func TestControllerPlan(t *testing.T) {
 			if pl.delPostCopyAction != nil {
 				gotPlan.delPostCopyAction = pl.delPostCopyAction.Query
 			}
+			if pl.delCopyRangeState != nil {
+				gotPlan.delCopyRangeState = pl.delCopyRangeState.Query
+			}
 			if !reflect.DeepEqual(gotPlan, tcase.plan) {
 				t.Errorf("getPlan(%v):\n%+v, want\n%+v", tcase.in, gotPlan, tcase.plan)
 			}
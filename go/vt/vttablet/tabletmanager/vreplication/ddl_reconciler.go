@@ -0,0 +1,268 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+// schemaDriftError is returned when the definition of a target table cannot
+// be reconciled with the definition of its source table.
+type schemaDriftError struct {
+	table  string
+	diff   string
+	reason string
+}
+
+func (e *schemaDriftError) Error() string {
+	if e.diff == "" {
+		return fmt.Sprintf("table %s cannot be reconciled: %s", e.table, e.reason)
+	}
+	return fmt.Sprintf("table %s cannot be reconciled: %s, the target table needs: %s", e.table, e.reason, e.diff)
+}
+
+// reconcilableTable returns true if the rows of the source table are
+// replicated as is to the target table of the same name, as they are by
+// MoveTables and Reshard workflows. Only then can the definitions of the
+// two tables be compared.
+func reconcilableTable(filter *binlogdatapb.Filter, table string, parser *sqlparser.Parser) (bool, error) {
+	rule, err := MatchTable(table, filter)
+	if err != nil || rule == nil {
+		return false, err
+	}
+	switch {
+	case rule.Filter == ExcludeStr:
+		return false, nil
+	case strings.HasPrefix(rule.Match, "/"), rule.Filter == "":
+		// The filter of a regular expression, if any, is a key range.
+		return true, nil
+	}
+	stmt, err := parser.Parse(rule.Filter)
+	if err != nil {
+		// The filter is a key range.
+		return true, nil
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok || len(sel.From) != 1 || sel.GroupBy != nil || len(sel.SelectExprs.Exprs) != 1 {
+		return false, nil
+	}
+	if _, ok := sel.SelectExprs.Exprs[0].(*sqlparser.StarExpr); !ok {
+		return false, nil
+	}
+	from, ok := sel.From[0].(*sqlparser.AliasedTableExpr)
+	if !ok {
+		return false, nil
+	}
+	tableName, ok := from.Expr.(sqlparser.TableName)
+	return ok && tableName.Name.String() == table, nil
+}
+
+// reconcileDDL compares the definitions of the source and target tables
+// affected by a DDL, and returns the statements that alter the target
+// tables to match the source tables. It returns a schemaDriftError if some
+// of these changes are not compatible with the rows being replicated.
+// The source definitions are the current ones, which may already include
+// the changes of later DDLs: these are then applied at once.
+func (vp *vplayer) reconcileDDL(ctx context.Context, ddl string) ([]string, error) {
+	parser := vp.vr.vre.env.Parser()
+	stmt, err := parser.Parse(ddl)
+	if err != nil {
+		return nil, err
+	}
+	var affected sqlparser.TableNames
+	switch stmt := stmt.(type) {
+	case *sqlparser.CreateTable, *sqlparser.AlterTable, *sqlparser.DropTable, *sqlparser.RenameTable:
+		affected = stmt.(sqlparser.DDLStatement).AffectedTables()
+	case *sqlparser.TruncateTable:
+		for _, table := range stmt.AffectedTables() {
+			ok, err := reconcilableTable(vp.vr.source.Filter, table.Name.String(), parser)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				return nil, &schemaDriftError{table: table.Name.String(), reason: "its rows were truncated on the source"}
+			}
+		}
+		return nil, nil
+	default:
+		// Views and the other objects of the source are not replicated.
+		return nil, nil
+	}
+	var tables []string
+	for _, table := range affected {
+		name := table.Name.String()
+		ok, err := reconcilableTable(vp.vr.source.Filter, name, parser)
+		if err != nil {
+			return nil, err
+		}
+		if ok && !slices.Contains(tables, name) {
+			tables = append(tables, name)
+		}
+	}
+	if len(tables) == 0 {
+		return nil, nil
+	}
+
+	sourceDefs, err := vp.vr.sourceVStreamer.GetTableDefinitions(ctx, tables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the definitions of the source tables %s: %v", strings.Join(tables, ","), err)
+	}
+	sd, err := vp.vr.mysqld.GetSchema(ctx, vp.vr.dbClient.DBName(), &tabletmanagerdatapb.GetSchemaRequest{
+		Tables:          tables,
+		TableSchemaOnly: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the definitions of the target tables %s: %v", strings.Join(tables, ","), err)
+	}
+	targetDefs := make(map[string]string, len(sd.TableDefinitions))
+	for _, td := range sd.TableDefinitions {
+		targetDefs[td.Name] = td.Schema
+	}
+
+	env := schemadiff.NewEnv(vp.vr.vre.env, vp.vr.vre.env.CollationEnv().DefaultConnectionCharset())
+	var statements []string
+	for _, table := range tables {
+		diff, err := schemadiff.DiffCreateTablesQueries(env, targetDefs[table], sourceDefs[table], &schemadiff.DiffHints{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare the definitions of table %s: %v", table, err)
+		}
+		for ; diff != nil && !diff.IsEmpty(); diff = diff.SubsequentDiff() {
+			if err := checkCompatibleDiff(table, diff); err != nil {
+				return nil, err
+			}
+			statements = append(statements, diff.CanonicalStatementString())
+		}
+	}
+	return statements, nil
+}
+
+// checkCompatibleDiff returns a schemaDriftError if applying the diff to the
+// target table could lose or reject the rows being replicated: that is the
+// case if it drops the table or some columns, renames or narrows columns,
+// or changes the primary key.
+func checkCompatibleDiff(table string, diff schemadiff.EntityDiff) error {
+	driftError := func(reason string, args ...any) error {
+		return &schemaDriftError{table: table, diff: diff.CanonicalStatementString(), reason: fmt.Sprintf(reason, args...)}
+	}
+	switch diff := diff.(type) {
+	case *schemadiff.CreateTableEntityDiff:
+		return nil
+	case *schemadiff.DropTableEntityDiff:
+		return driftError("it does not exist on the source")
+	case *schemadiff.AlterTableEntityDiff:
+		alter := diff.AlterTable()
+		if alter.PartitionSpec != nil || alter.PartitionOption != nil {
+			return driftError("its partitioning changed")
+		}
+		from, _ := diff.Entities()
+		fromTable := from.(*schemadiff.CreateTableEntity)
+		for _, option := range alter.AlterOptions {
+			switch option := option.(type) {
+			case *sqlparser.AddColumns, *sqlparser.AlterColumn, *sqlparser.AlterIndex, *sqlparser.RenameIndex,
+				*sqlparser.AlterCheck, sqlparser.TableOptions:
+			case *sqlparser.AddIndexDefinition:
+				if option.IndexDefinition.Info.Type == sqlparser.IndexTypePrimary {
+					return driftError("its primary key changed")
+				}
+			case *sqlparser.AddConstraintDefinition:
+				if _, ok := option.ConstraintDefinition.Details.(*sqlparser.ForeignKeyDefinition); ok {
+					return driftError("its foreign keys changed")
+				}
+			case *sqlparser.DropKey:
+				if option.Type == sqlparser.PrimaryKeyType {
+					return driftError("its primary key changed")
+				}
+			case *sqlparser.ModifyColumn:
+				if !compatibleColumnChange(fromTable, option.NewColDefinition) {
+					return driftError("the type of column %s changed", option.NewColDefinition.Name.String())
+				}
+			case *sqlparser.DropColumn:
+				return driftError("column %s was dropped", option.Name.Name.String())
+			default:
+				return driftError("%s is not supported", sqlparser.String(option))
+			}
+		}
+		return nil
+	default:
+		return driftError("%s is not supported", diff.CanonicalStatementString())
+	}
+}
+
+// compatibleColumnChange returns true if the values of a column of the
+// table can be stored in the column as redefined: its type is unchanged,
+// but for a length that is not smaller and for enum and set values that
+// are appended.
+func compatibleColumnChange(table *schemadiff.CreateTableEntity, col *sqlparser.ColumnDefinition) bool {
+	var old *sqlparser.ColumnDefinition
+	for _, c := range table.TableSpec.Columns {
+		if c.Name.Equal(col.Name) {
+			old = c
+			break
+		}
+	}
+	if old == nil {
+		return false
+	}
+	oldType, newType := old.Type, col.Type
+	switch {
+	case !strings.EqualFold(oldType.Type, newType.Type),
+		oldType.Unsigned != newType.Unsigned,
+		!strings.EqualFold(oldType.Charset.Name, newType.Charset.Name),
+		!equalIntPtr(oldType.Scale, newType.Scale),
+		len(newType.EnumValues) < len(oldType.EnumValues),
+		!slices.Equal(oldType.EnumValues, newType.EnumValues[:len(oldType.EnumValues)]):
+		return false
+	case oldType.Length == nil || newType.Length == nil:
+		return oldType.Length == nil && newType.Length == nil
+	default:
+		return *newType.Length >= *oldType.Length
+	}
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// reloadReplicatorPlan rebuilds the replicator plan after the target tables
+// were altered. The plans of the altered tables are rebuilt from it by their
+// next field events, which the source sends as their table ids changed.
+func (vp *vplayer) reloadReplicatorPlan(ctx context.Context) error {
+	colInfoMap, err := vp.vr.buildColInfoMap(ctx)
+	if err != nil {
+		return err
+	}
+	vp.vr.colInfoMap = colInfoMap
+	plan, err := vp.vr.buildReplicatorPlan(vp.vr.source, colInfoMap, vp.copyState, vp.vr.stats, vp.vr.vre.env.CollationEnv(), vp.vr.vre.env.Parser())
+	if err != nil {
+		vp.vr.stats.ErrorCounts.Add([]string{"Plan"}, 1)
+		return err
+	}
+	vp.replicatorPlan = plan
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+)
+
+func TestReconcilableTable(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+	testCases := []struct {
+		name  string
+		rules []*binlogdatapb.Rule
+		want  bool
+	}{{
+		name:  "reshard",
+		rules: []*binlogdatapb.Rule{{Match: "/.*", Filter: "-80"}},
+		want:  true,
+	}, {
+		name:  "movetables",
+		rules: []*binlogdatapb.Rule{{Match: "t1", Filter: "select * from t1"}},
+		want:  true,
+	}, {
+		name:  "movetables to a sharded keyspace",
+		rules: []*binlogdatapb.Rule{{Match: "t1", Filter: "select * from t1 where in_keyrange(id, 'xxhash', '-80')"}},
+		want:  true,
+	}, {
+		name:  "excluded",
+		rules: []*binlogdatapb.Rule{{Match: "t1", Filter: ExcludeStr}, {Match: "/.*"}},
+		want:  false,
+	}, {
+		name:  "projection",
+		rules: []*binlogdatapb.Rule{{Match: "t1", Filter: "select id, c1 from t1"}},
+		want:  false,
+	}, {
+		name:  "other source table",
+		rules: []*binlogdatapb.Rule{{Match: "t1", Filter: "select * from t2"}},
+		want:  false,
+	}, {
+		name:  "aggregation",
+		rules: []*binlogdatapb.Rule{{Match: "t1", Filter: "select * from t1 group by c1"}},
+		want:  false,
+	}, {
+		name:  "not replicated",
+		rules: []*binlogdatapb.Rule{{Match: "t2", Filter: "select * from t2"}},
+		want:  false,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := reconcilableTable(&binlogdatapb.Filter{Rules: tc.rules}, "t1", parser)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCheckCompatibleDiff(t *testing.T) {
+	env := schemadiff.NewTestEnv()
+	target := "create table t1 (id int primary key, c1 varchar(10), c2 enum('a', 'b'), key c1 (c1))"
+	testCases := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{{
+		name:   "add column and index",
+		source: "create table t1 (id int primary key, c1 varchar(10), c2 enum('a', 'b'), c3 int default 1, key c1 (c1), key c3 (c3))",
+	}, {
+		name:   "drop index",
+		source: "create table t1 (id int primary key, c1 varchar(10), c2 enum('a', 'b'))",
+	}, {
+		name:   "widen column",
+		source: "create table t1 (id int primary key, c1 varchar(20) not null, c2 enum('a', 'b', 'c'), key c1 (c1))",
+	}, {
+		name:    "narrow column",
+		source:  "create table t1 (id int primary key, c1 varchar(5), c2 enum('a', 'b'), key c1 (c1))",
+		wantErr: "table t1 cannot be reconciled: the type of column c1 changed",
+	}, {
+		name:    "change enum values",
+		source:  "create table t1 (id int primary key, c1 varchar(10), c2 enum('b', 'a'), key c1 (c1))",
+		wantErr: "the type of column c2 changed",
+	}, {
+		name:    "change column type",
+		source:  "create table t1 (id int primary key, c1 int, c2 enum('a', 'b'), key c1 (c1))",
+		wantErr: "the type of column c1 changed",
+	}, {
+		name:    "drop column",
+		source:  "create table t1 (id int primary key, c2 enum('a', 'b'))",
+		wantErr: "column c1 was dropped",
+	}, {
+		name:    "change primary key",
+		source:  "create table t1 (id int, c1 varchar(10), c2 enum('a', 'b'), primary key (id, c1), key c1 (c1))",
+		wantErr: "its primary key changed",
+	}, {
+		name:    "drop table",
+		wantErr: "it does not exist on the source",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff, err := schemadiff.DiffCreateTablesQueries(env, target, tc.source, &schemadiff.DiffHints{})
+			require.NoError(t, err)
+			require.False(t, diff.IsEmpty())
+			err = checkCompatibleDiff("t1", diff)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var driftErr *schemaDriftError
+			require.ErrorAs(t, err, &driftErr)
+			assert.ErrorContains(t, err, tc.wantErr)
+			assert.Contains(t, err.Error(), diff.CanonicalStatementString())
+		})
+	}
+}
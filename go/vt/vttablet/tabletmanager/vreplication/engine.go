@@ -53,6 +53,7 @@ const (
 	vreplicationTableName      = "vreplication"
 	copyStateTableName         = "copy_state"
 	postCopyActionTableName    = "post_copy_action"
+	copyRangeStateTableName    = "copy_range_state"
 
 	maxRows = 10000
 )
@@ -525,6 +526,14 @@ func (vre *Engine) exec(query string, runAsAdmin bool) (*sqltypes.Result, error)
 		if err != nil {
 			return nil, err
 		}
+		delQuery, err = plan.delCopyRangeState.GenerateQuery(bv, nil)
+		if err != nil {
+			return nil, err
+		}
+		_, err = dbClient.ExecuteFetch(delQuery, maxRows)
+		if err != nil {
+			return nil, err
+		}
 		if err := dbClient.Commit(); err != nil {
 			return nil, err
 		}
@@ -20,13 +20,17 @@ import (
 	"context"
 	"sync"
 
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqlescape"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/grpcclient"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
@@ -35,6 +39,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/vstreamer"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
 )
 
 var (
@@ -58,6 +63,14 @@ type VStreamerClient interface {
 	// VStreamTables streams rows of a table from the specified starting point.
 	VStreamTables(ctx context.Context,
 		send func(*binlogdatapb.VStreamTablesResponse) error, options *binlogdatapb.VStreamOptions) error
+
+	// GetTableDefinitions returns the CREATE TABLE statements of the given
+	// tables, by table name. The tables that do not exist are omitted.
+	GetTableDefinitions(ctx context.Context, tables []string) (map[string]string, error)
+
+	// GetPKBounds returns the minimum and maximum values of the primary key
+	// column of a table.
+	GetPKBounds(ctx context.Context, table, column string) (*sqltypes.Result, error)
 }
 
 type externalConnector struct {
@@ -157,6 +170,38 @@ func (c *mysqlConnector) VStreamTables(ctx context.Context,
 	return c.vstreamer.StreamTables(ctx, send, options)
 }
 
+func (c *mysqlConnector) GetTableDefinitions(ctx context.Context, tables []string) (map[string]string, error) {
+	conn, err := c.se.GetConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Recycle()
+	defs := make(map[string]string, len(tables))
+	for _, table := range tables {
+		qr, err := conn.Conn.Exec(ctx, sqlparser.BuildParsedQuery("show create table %a", sqlescape.EscapeID(table)).Query, 1, false)
+		if err != nil {
+			if sqlErr, ok := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError); ok && sqlErr.Number() == sqlerror.ERNoSuchTable {
+				continue
+			}
+			return nil, err
+		}
+		if len(qr.Rows) != 1 || len(qr.Rows[0]) < 2 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result for show create table %s: %v", table, qr.Rows)
+		}
+		defs[table] = qr.Rows[0][1].ToString()
+	}
+	return defs, nil
+}
+
+func (c *mysqlConnector) GetPKBounds(ctx context.Context, table, column string) (*sqltypes.Result, error) {
+	conn, err := c.se.GetConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Recycle()
+	return conn.Conn.Exec(ctx, pkBoundsQuery(table, column), 1, true)
+}
+
 // -----------------------------------------------------------
 
 type tabletConnector struct {
@@ -203,3 +248,27 @@ func (tc *tabletConnector) VStreamTables(ctx context.Context,
 	req := &binlogdatapb.VStreamTablesRequest{Target: tc.target, Options: options}
 	return tc.qs.VStreamTables(ctx, req, send)
 }
+
+// GetTableDefinitions gets the definitions of the tables from the tablet
+// manager of the source tablet, as its query service only knows the tables
+// that are tracked by the schema tracker.
+func (tc *tabletConnector) GetTableDefinitions(ctx context.Context, tables []string) (map[string]string, error) {
+	tmc := tmclient.NewTabletManagerClient()
+	defer tmc.Close()
+	sd, err := tmc.GetSchema(ctx, tc.tablet, &tabletmanagerdatapb.GetSchemaRequest{
+		Tables:          tables,
+		TableSchemaOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defs := make(map[string]string, len(sd.TableDefinitions))
+	for _, td := range sd.TableDefinitions {
+		defs[td.Name] = td.Schema
+	}
+	return defs, nil
+}
+
+func (tc *tabletConnector) GetPKBounds(ctx context.Context, table, column string) (*sqltypes.Result, error) {
+	return tc.qs.Execute(ctx, tc.target, pkBoundsQuery(table, column), nil, 0, 0, nil)
+}
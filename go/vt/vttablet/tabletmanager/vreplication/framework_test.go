@@ -538,6 +538,7 @@ func expectDeleteQueries(t *testing.T) {
 		"/delete from _vt.vreplication",
 		"/delete from _vt.copy_state",
 		"/delete from _vt.post_copy_action",
+		"/delete from _vt.copy_range_state",
 	), recvTimeout)
 }
 
@@ -97,9 +97,44 @@ func (rp *ReplicatorPlan) buildExecutionPlan(fieldEvent *binlogdatapb.FieldEvent
 		return nil, vterrors.Wrapf(err, "failed to build replication plan for %s table", fieldEvent.TableName)
 	}
 	tplan.Fields = fieldEvent.Fields
+	if len(prelim.JoinTables) > 0 {
+		if err := tplan.buildJoinDelete(); err != nil {
+			return nil, vterrors.Wrapf(err, "failed to build replication plan for %s table", fieldEvent.TableName)
+		}
+		tplan.JoinTables = prelim.JoinTables
+	}
 	return tplan, nil
 }
 
+// buildJoinDelete replaces the delete of the plan with one that deletes all
+// the joined rows of a primary key of the source table. The source flags the
+// columns of that primary key in the fields it sends.
+func (tp *TablePlan) buildJoinDelete() error {
+	var keyColumns []sqlparser.IdentifierCI
+	for _, field := range tp.Fields {
+		if field.Flags&uint32(querypb.MySqlFlag_PRI_KEY_FLAG) == 0 {
+			continue
+		}
+		isPK := false
+		for _, pkCol := range tp.TablePlanBuilder.pkCols {
+			if pkCol.colName.EqualString(field.Name) {
+				isPK = true
+				break
+			}
+		}
+		if !isPK {
+			return fmt.Errorf("the primary key of the joined rows must include column %s of table %s", field.Name, tp.TargetName)
+		}
+		keyColumns = append(keyColumns, sqlparser.NewIdentifierCI(field.Name))
+	}
+	if len(keyColumns) == 0 {
+		return fmt.Errorf("no primary key columns were sent for the joined rows of table %s", tp.TargetName)
+	}
+	tp.Delete = tp.TablePlanBuilder.generateJoinDeleteStatement(keyColumns)
+	tp.MultiDelete = nil
+	return nil
+}
+
 // buildFromFields builds a full TablePlan, but uses the field info as the
 // full column list. This happens when the query used was a 'select *', which
 // requires us to wait for the field info sent by the source.
@@ -228,6 +263,17 @@ type TablePlan struct {
 
 	CollationEnv   *collations.Environment
 	WorkflowConfig *vttablet.VReplicationConfig
+
+	// JoinTables is set if the filter is a join. It lists the joined
+	// source tables, each of which gets its own copy of the plan. The
+	// source sends the changes to the joined rows as deletes of all the
+	// rows of a changed primary key of a joined table, followed by the
+	// inserts of the current rows.
+	JoinTables []string
+
+	// MinMax is set if the plan has min or max aggregates, which are
+	// recomputed when a row that may hold them leaves its group.
+	MinMax *minMaxPlan
 }
 
 // MarshalJSON performs a custom JSON Marshalling.
@@ -597,23 +597,32 @@ func TestBuildPlayerPlan(t *testing.T) {
 		},
 		err: "failed to build table replication plan for t1 table: unsupported distinct clause in query: select distinct c1 from t1",
 	}, {
-		// no ',' join
+		// no '*' in a ',' join
 		input: &binlogdatapb.Filter{
 			Rules: []*binlogdatapb.Rule{{
 				Match:  "t1",
 				Filter: "select * from t1, t2",
 			}},
 		},
-		err: "failed to build table replication plan for t1 table: unsupported multi-table usage in query: select * from t1, t2",
+		err: "failed to build table replication plan for t1 table: unsupported '*' in a join, the columns must be listed in query: select * from t1, t2",
 	}, {
-		// no join
+		// no '*' in a join
 		input: &binlogdatapb.Filter{
 			Rules: []*binlogdatapb.Rule{{
 				Match:  "t1",
 				Filter: "select * from t1 join t2",
 			}},
 		},
-		err: "failed to build table replication plan for t1 table: unsupported from expression (*sqlparser.JoinTableExpr) in query: select * from t1 join t2",
+		err: "failed to build table replication plan for t1 table: unsupported '*' in a join, the columns must be listed in query: select * from t1 join t2",
+	}, {
+		// no outer join
+		input: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  "t1",
+				Filter: "select t1.id, t2.val from t1 left join t2 on t1.id = t2.id",
+			}},
+		},
+		err: "failed to build table replication plan for t1 table: unsupported left join, only inner joins are supported in query: select t1.id, t2.val from t1 left join t2 on t1.id = t2.id",
 	}, {
 		// no subqueries
 		input: &binlogdatapb.Filter{
@@ -660,14 +669,14 @@ func TestBuildPlayerPlan(t *testing.T) {
 		},
 		err: "failed to build table replication plan for t1 table: expression needs an alias: hour(c1) in query: select hour(c1) from t1",
 	}, {
-		// only count(*)
+		// count(col) needs a group by
 		input: &binlogdatapb.Filter{
 			Rules: []*binlogdatapb.Rule{{
 				Match:  "t1",
 				Filter: "select count(c1) as c from t1",
 			}},
 		},
-		err: "failed to build table replication plan for t1 table: only count(*) is supported: count(c1) in query: select count(c1) as c from t1",
+		err: "failed to build table replication plan for t1 table: unsupported aggregate c without a group by clause in query: select count(c1) as c from t1",
 	}, {
 		// no sum(*)
 		input: &binlogdatapb.Filter{
@@ -696,23 +705,32 @@ func TestBuildPlayerPlan(t *testing.T) {
 		},
 		err: "failed to build table replication plan for t1 table: unsupported non-column name in sum clause: sum(a + b) in query: select sum(a + b) as c from t1",
 	}, {
-		// no complex expr in group by
+		// group by expression not in the select list
 		input: &binlogdatapb.Filter{
 			Rules: []*binlogdatapb.Rule{{
 				Match:  "t1",
 				Filter: "select a from t1 group by a + 1",
 			}},
 		},
-		err: "failed to build table replication plan for t1 table: unsupported non-column name or alias in group by clause: a + 1 in query: select a from t1 group by a + 1",
+		err: "failed to build table replication plan for t1 table: group by expression does not reference an alias or an expression in the select list: a + 1 in query: select a from t1 group by a + 1",
 	}, {
 		// group by does not reference alias
 		input: &binlogdatapb.Filter{
 			Rules: []*binlogdatapb.Rule{{
 				Match:  "t1",
-				Filter: "select a as b from t1 group by a",
+				Filter: "select a as b from t1 group by c",
+			}},
+		},
+		err: "failed to build table replication plan for t1 table: group by expression does not reference an alias or an expression in the select list: c in query: select a as b from t1 group by c",
+	}, {
+		// min and max need groups of columns
+		input: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  "t1",
+				Filter: "select date(a) as c1, min(b) as m from t1 group by date(a)",
 			}},
 		},
-		err: "failed to build table replication plan for t1 table: group by expression does not reference an alias in the select list: a in query: select a as b from t1 group by a",
+		err: "failed to build table replication plan for t1 table: unsupported aggregate m with a group by expression: date(a) in query: select date(a) as c1, min(b) as m from t1 group by date(a)",
 	}, {
 		// cannot group by aggr
 		input: &binlogdatapb.Filter{
@@ -831,6 +849,147 @@ func TestBuildPlayerPlanExclude(t *testing.T) {
 	assert.Equal(t, string(gotPlan), string(wantPlan))
 }
 
+func TestBuildPlayerPlanJoin(t *testing.T) {
+	colInfos := map[string][]*ColumnInfo{
+		"t1": {
+			{Name: "order_id", IsPK: true},
+			{Name: "customer_id", IsPK: true},
+			{Name: "name"},
+		},
+	}
+	query := "select o.id as order_id, c.id as customer_id, c.name from orders as o join customers as c on o.customer_id = c.id"
+	input := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "t1",
+			Filter: query,
+		}},
+	}
+	vr := &vreplicator{
+		workflowConfig: vttablet.DefaultVReplicationConfig,
+	}
+	plan, err := vr.buildReplicatorPlan(getSource(input), colInfos, nil, binlogplayer.NewStats(), collations.MySQL8(), sqlparser.NewTestParser())
+	require.NoError(t, err)
+
+	want := &TestReplicatorPlan{
+		VStreamFilter: &binlogdatapb.Filter{
+			Rules: []*binlogdatapb.Rule{{
+				Match:  "orders",
+				Filter: query,
+			}, {
+				Match:  "customers",
+				Filter: query,
+			}},
+		},
+		TargetTables: []string{"t1"},
+		TablePlans: map[string]*TestTablePlan{
+			"orders": {
+				TargetName: "t1",
+				SendRule:   "orders",
+			},
+			"customers": {
+				TargetName: "t1",
+				SendRule:   "customers",
+			},
+		},
+	}
+	gotPlan, _ := json.Marshal(plan)
+	wantPlan, _ := json.Marshal(want)
+	assert.Equal(t, string(wantPlan), string(gotPlan))
+
+	fields := []*querypb.Field{
+		{Name: "order_id", Type: sqltypes.Int64},
+		{Name: "customer_id", Type: sqltypes.Int64, Flags: uint32(querypb.MySqlFlag_PRI_KEY_FLAG)},
+		{Name: "name", Type: sqltypes.VarChar},
+	}
+	tplan, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{TableName: "customers", Fields: fields})
+	require.NoError(t, err)
+	assert.Equal(t, "delete from t1 where customer_id=:b_customer_id", tplan.Delete.Query)
+	assert.Nil(t, tplan.MultiDelete)
+	assert.Equal(t, "insert into t1(order_id,customer_id,`name`) values (:a_order_id,:a_customer_id,:a_name)", tplan.Insert.Query)
+
+	// The primary key of the target must include the primary keys of the joined tables.
+	colInfos["t1"][1].IsPK = false
+	_, err = plan.buildExecutionPlan(&binlogdatapb.FieldEvent{TableName: "customers", Fields: fields})
+	require.ErrorContains(t, err, "the primary key of the joined rows must include column customer_id of table t1")
+}
+
+func TestBuildPlayerPlanAggregates(t *testing.T) {
+	vttablet.InitVReplicationConfigDefaults()
+	colInfos := map[string][]*ColumnInfo{
+		"t1": {&ColumnInfo{Name: "c1", IsPK: true}},
+	}
+	testcases := []struct {
+		filter string
+		plan   *TestTablePlan
+		check  string
+		update string
+		source string
+	}{{
+		filter: "select c1, count(c2) as cnt, avg(c3) as a3, min(c4) as mn, max(c4) as mx from t2 group by c1",
+		plan: &TestTablePlan{
+			TargetName:   "t1",
+			SendRule:     "t2",
+			PKReferences: []string{"c1"},
+			InsertFront:  "insert into t1(c1,cnt,a3_vt_sum,a3_vt_count,a3,mn,mx)",
+			InsertValues: "(:a_c1,if(:a_c2 is null, 0, 1),ifnull(:a_c3, 0),if(:a_c3 is null, 0, 1),:a_c3,:a_c4,:a_c4)",
+			InsertOnDup: " on duplicate key update cnt=cnt+values(cnt), a3_vt_sum=a3_vt_sum+ifnull(values(a3_vt_sum), 0), a3_vt_count=a3_vt_count+values(a3_vt_count), " +
+				"a3=a3_vt_sum/nullif(a3_vt_count, 0), mn=coalesce(least(mn, values(mn)), mn, values(mn)), mx=coalesce(greatest(mx, values(mx)), mx, values(mx))",
+			Insert: "insert into t1(c1,cnt,a3_vt_sum,a3_vt_count,a3,mn,mx) values (:a_c1,if(:a_c2 is null, 0, 1),ifnull(:a_c3, 0),if(:a_c3 is null, 0, 1),:a_c3,:a_c4,:a_c4) " +
+				"on duplicate key update cnt=cnt+values(cnt), a3_vt_sum=a3_vt_sum+ifnull(values(a3_vt_sum), 0), a3_vt_count=a3_vt_count+values(a3_vt_count), " +
+				"a3=a3_vt_sum/nullif(a3_vt_count, 0), mn=coalesce(least(mn, values(mn)), mn, values(mn)), mx=coalesce(greatest(mx, values(mx)), mx, values(mx))",
+			Update: "update t1 set cnt=cnt-if(:b_c2 is null, 0, 1)+if(:a_c2 is null, 0, 1), a3_vt_sum=a3_vt_sum-ifnull(:b_c3, 0)+ifnull(:a_c3, 0), " +
+				"a3_vt_count=a3_vt_count-if(:b_c3 is null, 0, 1)+if(:a_c3 is null, 0, 1), a3=a3_vt_sum/nullif(a3_vt_count, 0), " +
+				"mn=coalesce(least(mn, :a_c4), mn, :a_c4), mx=coalesce(greatest(mx, :a_c4), mx, :a_c4) where c1=:b_c1",
+			Delete: "update t1 set cnt=cnt-if(:b_c2 is null, 0, 1), a3_vt_sum=a3_vt_sum-ifnull(:b_c3, 0), a3_vt_count=a3_vt_count-if(:b_c3 is null, 0, 1), " +
+				"a3=a3_vt_sum/nullif(a3_vt_count, 0), mn=mn, mx=mx where c1=:b_c1",
+		},
+		check:  "select 1 from t1 where c1=:b_c1 and (mn=:b_c4 or mx=:b_c4) limit 1",
+		update: "update t1 set mn=:r_mn, mx=:r_mx where c1=:b_c1",
+		source: "select c4, c4 from t2",
+	}, {
+		filter: "select date(c2) as c1, count(*) as cnt from t2 where in_keyrange('-80') group by date(c2)",
+		plan: &TestTablePlan{
+			TargetName:   "t1",
+			SendRule:     "t2",
+			PKReferences: []string{"c2"},
+			InsertFront:  "insert into t1(c1,cnt)",
+			InsertValues: "(date(:a_c2),1)",
+			InsertOnDup:  " on duplicate key update cnt=cnt+1",
+			Insert:       "insert into t1(c1,cnt) values (date(:a_c2),1) on duplicate key update cnt=cnt+1",
+			Update:       "update t1 set cnt=cnt where c1=(date(:b_c2))",
+			Delete:       "update t1 set cnt=cnt-1 where c1=(date(:b_c2))",
+		},
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.filter, func(t *testing.T) {
+			input := &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{
+					Match:  "t1",
+					Filter: tcase.filter,
+				}},
+			}
+			vr := &vreplicator{
+				workflowConfig: vttablet.DefaultVReplicationConfig,
+			}
+			plan, err := vr.buildReplicatorPlan(getSource(input), colInfos, nil, binlogplayer.NewStats(), collations.MySQL8(), sqlparser.NewTestParser())
+			require.NoError(t, err)
+			tplan := plan.TablePlans["t2"]
+			require.NotNil(t, tplan)
+			gotPlan, _ := json.Marshal(tplan)
+			wantPlan, _ := json.Marshal(tcase.plan)
+			assert.Equal(t, string(wantPlan), string(gotPlan))
+			if tcase.check == "" {
+				assert.Nil(t, tplan.MinMax)
+				return
+			}
+			require.NotNil(t, tplan.MinMax)
+			assert.Equal(t, tcase.check, tplan.MinMax.Check.Query)
+			assert.Equal(t, tcase.update, tplan.MinMax.Update.Query)
+			assert.Equal(t, tcase.source, sqlparser.String(tplan.MinMax.Select))
+		})
+	}
+}
+
 func TestAppendFromRow(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -0,0 +1,245 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+const (
+	// StorageLocal writes the files to a directory of the tablet host.
+	StorageLocal = "local"
+	// StorageBackup writes the files to the backup storage of the tablet.
+	StorageBackup = "backup"
+)
+
+// Storage stores the files of an export. Files are written in sets: a set
+// is a named group of files of a directory that are written at once, and
+// that only become visible once all of them are written. Sets map onto the
+// backups of a BackupStorage.
+type Storage interface {
+	// WriteSet writes the files of the set name in dir. It is an error if
+	// the set already exists.
+	WriteSet(ctx context.Context, dir, name string, files map[string][]byte) error
+	// ReadFile reads a file of the set name in dir.
+	ReadFile(ctx context.Context, dir, name, file string) ([]byte, error)
+	// ListSets returns the names of the sets in dir, sorted in ascending
+	// order. It is not an error if dir does not exist.
+	ListSets(ctx context.Context, dir string) ([]string, error)
+	// RemoveSet removes the set name in dir and its files. It is not an
+	// error if the set does not exist.
+	RemoveSet(ctx context.Context, dir, name string) error
+	// Close frees the resources of the storage.
+	Close() error
+}
+
+// NewStorage returns the storage of the given kind whose files are written
+// under directory: either a directory of the tablet host, or a directory of
+// the backup storage of the tablet as configured by its flags.
+func NewStorage(kind, directory string) (Storage, error) {
+	if directory == "" {
+		return nil, errors.New("a directory is required to store the files")
+	}
+	switch kind {
+	case StorageLocal, "":
+		return &localStorage{root: directory}, nil
+	case StorageBackup:
+		bs, err := backupstorage.GetBackupStorage()
+		if err != nil {
+			return nil, err
+		}
+		return &backupStorage{bs: bs, root: directory}, nil
+	default:
+		return nil, fmt.Errorf("unsupported storage %q, supported storages are %q and %q", kind, StorageLocal, StorageBackup)
+	}
+}
+
+// localStorage stores the sets as directories of a local directory. A set
+// is written in a hidden temporary directory which is then renamed.
+type localStorage struct {
+	root string
+}
+
+func (ls *localStorage) WriteSet(ctx context.Context, dir, name string, files map[string][]byte) error {
+	parent := filepath.Join(ls.root, dir)
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return err
+	}
+	final := filepath.Join(parent, name)
+	if _, err := os.Stat(final); err == nil {
+		return fmt.Errorf("set %s already exists", path.Join(dir, name))
+	}
+	tmp := filepath.Join(parent, "."+name+".tmp")
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := os.Mkdir(tmp, 0o755); err != nil {
+		return err
+	}
+	for file, data := range files {
+		if err := writeLocalFile(filepath.Join(tmp, file), data); err != nil {
+			os.RemoveAll(tmp)
+			return err
+		}
+	}
+	if err := os.Rename(tmp, final); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	return nil
+}
+
+// writeLocalFile writes and syncs a file, so that a set is not renamed into
+// place before its files are durable.
+func writeLocalFile(name string, data []byte) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (ls *localStorage) ReadFile(ctx context.Context, dir, name, file string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(ls.root, dir, name, file))
+}
+
+func (ls *localStorage) ListSets(ctx context.Context, dir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(ls.root, dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (ls *localStorage) RemoveSet(ctx context.Context, dir, name string) error {
+	return os.RemoveAll(filepath.Join(ls.root, dir, name))
+}
+
+func (ls *localStorage) Close() error {
+	return nil
+}
+
+// backupStorage stores the sets as the backups of a BackupStorage, which
+// only become visible once EndBackup succeeds.
+type backupStorage struct {
+	bs   backupstorage.BackupStorage
+	root string
+}
+
+func (bs *backupStorage) WriteSet(ctx context.Context, dir, name string, files map[string][]byte) (err error) {
+	handle, err := bs.bs.StartBackup(ctx, path.Join(bs.root, dir), name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			handle.AbortBackup(ctx)
+		}
+	}()
+	for file, data := range files {
+		w, err := handle.AddFile(ctx, file, int64(len(data)))
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, bytes.NewReader(data)); err != nil {
+			w.Close()
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+	}
+	return handle.EndBackup(ctx)
+}
+
+func (bs *backupStorage) ReadFile(ctx context.Context, dir, name, file string) ([]byte, error) {
+	handles, err := bs.bs.ListBackups(ctx, path.Join(bs.root, dir))
+	if err != nil {
+		return nil, err
+	}
+	for _, handle := range handles {
+		if handle.Name() != name {
+			continue
+		}
+		r, err := handle.ReadFile(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	}
+	return nil, fmt.Errorf("set %s does not exist", path.Join(dir, name))
+}
+
+func (bs *backupStorage) ListSets(ctx context.Context, dir string) ([]string, error) {
+	handles, err := bs.bs.ListBackups(ctx, path.Join(bs.root, dir))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(handles))
+	for _, handle := range handles {
+		names = append(names, handle.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (bs *backupStorage) RemoveSet(ctx context.Context, dir, name string) error {
+	names, err := bs.ListSets(ctx, dir)
+	if err != nil {
+		return err
+	}
+	for _, n := range names {
+		if n == name {
+			return bs.bs.RemoveBackup(ctx, path.Join(bs.root, dir), name)
+		}
+	}
+	return nil
+}
+
+func (bs *backupStorage) Close() error {
+	return bs.bs.Close()
+}
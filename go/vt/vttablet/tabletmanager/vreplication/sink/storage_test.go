@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func TestStorage(t *testing.T) {
+	ctx := context.Background()
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	defer func() { filebackupstorage.FileBackupStorageRoot = "" }()
+	implementation := backupstorage.BackupStorageImplementation
+	backupstorage.BackupStorageImplementation = "file"
+	defer func() { backupstorage.BackupStorageImplementation = implementation }()
+
+	for _, kind := range []string{StorageLocal, StorageBackup} {
+		t.Run(kind, func(t *testing.T) {
+			storage, err := NewStorage(kind, t.TempDir())
+			require.NoError(t, err)
+			defer storage.Close()
+
+			names, err := storage.ListSets(ctx, "t1/dt=2025-01-01")
+			require.NoError(t, err)
+			assert.Empty(t, names)
+
+			require.NoError(t, storage.WriteSet(ctx, "t1/dt=2025-01-01", "batch=2", map[string][]byte{"data.ndjson": []byte("{}\n")}))
+			require.NoError(t, storage.WriteSet(ctx, "t1/dt=2025-01-01", "batch=1", map[string][]byte{"data.ndjson": []byte("{\"id\":1}\n")}))
+			require.Error(t, storage.WriteSet(ctx, "t1/dt=2025-01-01", "batch=1", map[string][]byte{"data.ndjson": nil}))
+
+			names, err = storage.ListSets(ctx, "t1/dt=2025-01-01")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"batch=1", "batch=2"}, names)
+
+			data, err := storage.ReadFile(ctx, "t1/dt=2025-01-01", "batch=1", "data.ndjson")
+			require.NoError(t, err)
+			assert.Equal(t, "{\"id\":1}\n", string(data))
+
+			require.NoError(t, storage.RemoveSet(ctx, "t1/dt=2025-01-01", "batch=1"))
+			require.NoError(t, storage.RemoveSet(ctx, "t1/dt=2025-01-01", "batch=3"))
+			names, err = storage.ListSets(ctx, "t1/dt=2025-01-01")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"batch=2"}, names)
+		})
+	}
+
+	_, err := NewStorage(StorageLocal, "")
+	require.ErrorContains(t, err, "a directory is required")
+	_, err = NewStorage("s3", "exports")
+	require.ErrorContains(t, err, `unsupported storage "s3"`)
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package sink writes the rows streamed by an Export workflow to files.
+
+The rows are written in batches. Every table of a batch is written to its
+own newline-delimited JSON file, partitioned by the date of the batch and by
+the source shard:
+
+	<table>/dt=<yyyy-mm-dd>/shard=<shard>/batch=<batch>/data.ndjson
+
+A batch is committed by writing its checkpoint, which holds the position of
+the stream and the files of the batch:
+
+	_checkpoints/shard=<shard>/<batch>/checkpoint.json
+
+Files that are not listed by a checkpoint belong to a batch that was not
+committed, and are removed when the stream is restarted. A stream restarts
+from the position of its last checkpoint, so that every row is written to
+the files of exactly one committed batch.
+*/
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+const (
+	// FormatNDJSON writes the rows as newline-delimited JSON objects.
+	FormatNDJSON = "ndjson"
+
+	checkpointsDir = "_checkpoints"
+	pendingDir     = "_pending"
+	checkpointFile = "checkpoint.json"
+	pendingFile    = "pending.json"
+	dataFile       = "data.ndjson"
+)
+
+// The operations of a Record.
+const (
+	OpSnapshot = "snapshot"
+	OpInsert   = "insert"
+	OpUpdate   = "update"
+	OpDelete   = "delete"
+)
+
+// Record is a row written to the files of an export. Snapshot records are
+// the rows of the copy phase, and the other ones are the row changes that
+// are streamed once a table has been copied.
+type Record struct {
+	Keyspace string `json:"keyspace"`
+	Shard    string `json:"shard"`
+	Table    string `json:"table"`
+	Op       string `json:"op"`
+	// Timestamp is the time in seconds at which the change was committed
+	// on the source, or zero for snapshot records.
+	Timestamp int64           `json:"timestamp,omitempty"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+}
+
+// Checkpoint is the state of a stream after a committed batch.
+type Checkpoint struct {
+	Batch    int64  `json:"batch"`
+	Position string `json:"position"`
+	// LastPKs are the last primary keys of the tables being copied,
+	// encoded in the protobuf text format.
+	LastPKs map[string]string `json:"last_pks,omitempty"`
+	// CopiedTables are the tables whose copy completed, which are not
+	// copied again when the copy is resumed.
+	CopiedTables  []string `json:"copied_tables,omitempty"`
+	CopyCompleted bool     `json:"copy_completed"`
+	// Files are the files of the batch, relative to the export directory.
+	Files []string  `json:"files,omitempty"`
+	Rows  int64     `json:"rows"`
+	Time  time.Time `json:"time"`
+}
+
+// dataSet is a set of the data files of a batch.
+type dataSet struct {
+	Dir  string `json:"dir"`
+	Name string `json:"name"`
+}
+
+// Writer writes the batches of the rows of a shard to a Storage.
+type Writer struct {
+	storage  Storage
+	keyspace string
+	shard    string
+
+	last   *Checkpoint
+	tables map[string]*bytes.Buffer
+	rows   int64
+}
+
+// NewWriter returns a Writer that resumes after the last checkpoint of the
+// shard. The files of the batches that were not committed are removed.
+func NewWriter(ctx context.Context, storage Storage, format, keyspace, shard string) (*Writer, error) {
+	if format != FormatNDJSON && format != "" {
+		return nil, fmt.Errorf("unsupported format %q, the only supported format is %q", format, FormatNDJSON)
+	}
+	w := &Writer{
+		storage:  storage,
+		keyspace: keyspace,
+		shard:    shard,
+		last:     &Checkpoint{},
+		tables:   make(map[string]*bytes.Buffer),
+	}
+	if err := w.recover(ctx); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) shardDir(dir string) string {
+	return path.Join(dir, "shard="+w.shard)
+}
+
+func batchName(batch int64) string {
+	return fmt.Sprintf("%020d", batch)
+}
+
+// recover loads the last checkpoint and removes the files of the batches
+// that were not committed.
+func (w *Writer) recover(ctx context.Context) error {
+	checkpoints, err := w.storage.ListSets(ctx, w.shardDir(checkpointsDir))
+	if err != nil {
+		return err
+	}
+	if len(checkpoints) > 0 {
+		name := checkpoints[len(checkpoints)-1]
+		data, err := w.storage.ReadFile(ctx, w.shardDir(checkpointsDir), name, checkpointFile)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, w.last); err != nil {
+			return fmt.Errorf("invalid checkpoint %s: %v", name, err)
+		}
+	}
+	pending, err := w.storage.ListSets(ctx, w.shardDir(pendingDir))
+	if err != nil {
+		return err
+	}
+	for _, name := range pending {
+		batch, err := strconv.ParseInt(name, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid pending batch %s: %v", name, err)
+		}
+		if batch > w.last.Batch {
+			data, err := w.storage.ReadFile(ctx, w.shardDir(pendingDir), name, pendingFile)
+			if err != nil {
+				return err
+			}
+			var sets []*dataSet
+			if err := json.Unmarshal(data, &sets); err != nil {
+				return fmt.Errorf("invalid pending batch %s: %v", name, err)
+			}
+			for _, set := range sets {
+				if err := w.storage.RemoveSet(ctx, set.Dir, set.Name); err != nil {
+					return err
+				}
+			}
+		}
+		if err := w.storage.RemoveSet(ctx, w.shardDir(pendingDir), name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Checkpoint returns the checkpoint of the last committed batch. Its batch
+// is zero if no batch was committed.
+func (w *Writer) Checkpoint() *Checkpoint {
+	return w.last
+}
+
+// Rows returns the number of rows of the batch being written.
+func (w *Writer) Rows() int64 {
+	return w.rows
+}
+
+// Add adds a record to the batch being written.
+func (w *Writer) Add(rec *Record) error {
+	rec.Keyspace = w.keyspace
+	rec.Shard = w.shard
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	buf, ok := w.tables[rec.Table]
+	if !ok {
+		buf = &bytes.Buffer{}
+		w.tables[rec.Table] = buf
+	}
+	buf.Write(data)
+	buf.WriteByte('\n')
+	w.rows++
+	return nil
+}
+
+// Commit writes the batch and its checkpoint. The data files are written
+// first, after recording them as pending so that they are removed if the
+// checkpoint is never written.
+func (w *Writer) Commit(ctx context.Context, position string, lastPKs map[string]string, copiedTables []string, copyCompleted bool, now time.Time) error {
+	cp := &Checkpoint{
+		Batch:         w.last.Batch + 1,
+		Position:      position,
+		LastPKs:       lastPKs,
+		CopiedTables:  copiedTables,
+		CopyCompleted: copyCompleted,
+		Rows:          w.rows,
+		Time:          now.UTC(),
+	}
+	name := batchName(cp.Batch)
+	tables := make([]string, 0, len(w.tables))
+	for table := range w.tables {
+		tables = append(tables, table)
+	}
+	slices.Sort(tables)
+	sets := make([]*dataSet, 0, len(tables))
+	for _, table := range tables {
+		set := &dataSet{
+			Dir:  w.shardDir(path.Join(table, "dt="+cp.Time.Format(time.DateOnly))),
+			Name: "batch=" + name,
+		}
+		sets = append(sets, set)
+		cp.Files = append(cp.Files, path.Join(set.Dir, set.Name, dataFile))
+	}
+
+	if len(sets) > 0 {
+		data, err := json.Marshal(sets)
+		if err != nil {
+			return err
+		}
+		if err := w.storage.WriteSet(ctx, w.shardDir(pendingDir), name, map[string][]byte{pendingFile: data}); err != nil {
+			return err
+		}
+	}
+	for i, set := range sets {
+		if err := w.storage.WriteSet(ctx, set.Dir, set.Name, map[string][]byte{dataFile: w.tables[tables[i]].Bytes()}); err != nil {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := w.storage.WriteSet(ctx, w.shardDir(checkpointsDir), name, map[string][]byte{checkpointFile: data}); err != nil {
+		return err
+	}
+	if len(sets) > 0 {
+		if err := w.storage.RemoveSet(ctx, w.shardDir(pendingDir), name); err != nil {
+			return err
+		}
+	}
+	w.last = cp
+	w.tables = make(map[string]*bytes.Buffer)
+	w.rows = 0
+	return nil
+}
+
+// RowJSON encodes a row as a JSON object whose keys are the names of the
+// fields, in the order of the fields. Numbers and JSON values are encoded
+// as such, binary values as base64 strings and the other values as strings.
+func RowJSON(fields []*querypb.Field, row []sqltypes.Value) (json.RawMessage, error) {
+	if len(fields) != len(row) {
+		return nil, fmt.Errorf("the row has %d values but there are %d fields", len(row), len(fields))
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		val, err := valueJSON(row[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func valueJSON(v sqltypes.Value) ([]byte, error) {
+	switch {
+	case v.IsNull():
+		return []byte("null"), nil
+	case v.IsIntegral(), v.IsFloat():
+		return v.Raw(), nil
+	case v.Type() == sqltypes.TypeJSON && json.Valid(v.Raw()):
+		return v.Raw(), nil
+	case v.IsBinary():
+		return json.Marshal(base64.StdEncoding.EncodeToString(v.Raw()))
+	default:
+		return json.Marshal(v.ToString())
+	}
+}
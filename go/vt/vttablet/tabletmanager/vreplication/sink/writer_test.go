@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestWriter(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewStorage(StorageLocal, t.TempDir())
+	require.NoError(t, err)
+	now := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	_, err = NewWriter(ctx, storage, "parquet", "ks", "-80")
+	require.ErrorContains(t, err, `unsupported format "parquet"`)
+
+	w, err := NewWriter(ctx, storage, FormatNDJSON, "ks", "-80")
+	require.NoError(t, err)
+	assert.Zero(t, w.Checkpoint().Batch)
+
+	require.NoError(t, w.Add(&Record{Table: "t1", Op: OpSnapshot, After: json.RawMessage(`{"id":1}`)}))
+	require.NoError(t, w.Add(&Record{Table: "t2", Op: OpSnapshot, After: json.RawMessage(`{"id":2}`)}))
+	assert.EqualValues(t, 2, w.Rows())
+	require.NoError(t, w.Commit(ctx, "MySQL56/uuid:1-10", map[string]string{"t2": "lastpk"}, []string{"t1"}, false, now))
+	assert.Zero(t, w.Rows())
+
+	cp := w.Checkpoint()
+	assert.EqualValues(t, 1, cp.Batch)
+	assert.Equal(t, []string{"t1"}, cp.CopiedTables)
+	assert.Equal(t, []string{
+		"t1/dt=2025-03-04/shard=-80/batch=00000000000000000001/data.ndjson",
+		"t2/dt=2025-03-04/shard=-80/batch=00000000000000000001/data.ndjson",
+	}, cp.Files)
+	data, err := storage.ReadFile(ctx, "t1/dt=2025-03-04/shard=-80", "batch=00000000000000000001", "data.ndjson")
+	require.NoError(t, err)
+	assert.Equal(t, `{"keyspace":"ks","shard":"-80","table":"t1","op":"snapshot","after":{"id":1}}`+"\n", string(data))
+	pending, err := storage.ListSets(ctx, "_pending/shard=-80")
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// A batch whose data files were written but not its checkpoint.
+	require.NoError(t, w.Add(&Record{Table: "t1", Op: OpInsert, Timestamp: 1, After: json.RawMessage(`{"id":3}`)}))
+	require.NoError(t, storage.WriteSet(ctx, "_pending/shard=-80", batchName(2), map[string][]byte{
+		pendingFile: []byte(`[{"dir":"t1/dt=2025-03-04/shard=-80","name":"batch=00000000000000000002"}]`),
+	}))
+	require.NoError(t, storage.WriteSet(ctx, "t1/dt=2025-03-04/shard=-80", "batch=00000000000000000002", map[string][]byte{
+		dataFile: []byte("{}\n"),
+	}))
+
+	// The writer resumes after the last checkpoint and removes the files of
+	// the batch that was not committed.
+	w, err = NewWriter(ctx, storage, FormatNDJSON, "ks", "-80")
+	require.NoError(t, err)
+	assert.Equal(t, cp, w.Checkpoint())
+	batches, err := storage.ListSets(ctx, "t1/dt=2025-03-04/shard=-80")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"batch=00000000000000000001"}, batches)
+	pending, err = storage.ListSets(ctx, "_pending/shard=-80")
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// A batch without rows only writes its checkpoint.
+	require.NoError(t, w.Commit(ctx, "MySQL56/uuid:1-12", nil, nil, true, now))
+	assert.EqualValues(t, 2, w.Checkpoint().Batch)
+	assert.Empty(t, w.Checkpoint().Files)
+	assert.True(t, w.Checkpoint().CopyCompleted)
+
+	// Another shard has its own checkpoints.
+	w, err = NewWriter(ctx, storage, FormatNDJSON, "ks", "80-")
+	require.NoError(t, err)
+	assert.Zero(t, w.Checkpoint().Batch)
+}
+
+func TestRowJSON(t *testing.T) {
+	fields := sqltypes.MakeTestFields("id|price|name|data|doc|opt", "int64|float64|varchar|varbinary|json|int64")
+	row := []sqltypes.Value{
+		sqltypes.NewInt64(1),
+		sqltypes.NewFloat64(2.5),
+		sqltypes.NewVarChar(`a "b"`),
+		sqltypes.NewVarBinary("\x00\x01"),
+		sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`{"k": [1, 2]}`)),
+		sqltypes.NULL,
+	}
+	data, err := RowJSON(fields, row)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1,"price":2.5,"name":"a \"b\"","data":"AAE=","doc":{"k": [1, 2]},"opt":null}`, string(data))
+	assert.True(t, json.Valid(data))
+
+	_, err = RowJSON(fields, row[:2])
+	require.ErrorContains(t, err, "the row has 2 values but there are 6 fields")
+}
@@ -73,12 +73,19 @@ type colExpr struct {
 	// operation==opExpr: full expression is set
 	// operation==opCount: nothing is set.
 	// operation==opSum: for 'sum(a)', expr is set to 'a'.
+	// operation==opCountCol, opMin, opMax and opAvg: for 'count(a)',
+	// 'min(a)', 'max(a)' and 'avg(a)', expr is set to 'a'.
 	operation operation
 	// expr stores the expected field name from vstreamer and dictates
 	// the generated bindvar names, like a_col or b_col.
 	expr sqlparser.Expr
 	// references contains all the column names referenced in the expression.
 	references map[string]bool
+	// sumColName and countColName are set for opAvg. They are the hidden
+	// columns of the target table that keep the sum and the count of the
+	// non-null values the average is computed from.
+	sumColName   sqlparser.IdentifierCI
+	countColName sqlparser.IdentifierCI
 
 	isGrouped   bool
 	isPK        bool
@@ -95,6 +102,18 @@ const (
 	opExpr = operation(iota)
 	opCount
 	opSum
+	opCountCol
+	opMin
+	opMax
+	opAvg
+)
+
+// The hidden columns that keep the sum and the count of an 'avg(a) as c'
+// aggregate are named c_vt_sum and c_vt_count. They must exist in the
+// target table, and can be declared as INVISIBLE.
+const (
+	avgSumSuffix   = "_vt_sum"
+	avgCountSuffix = "_vt_count"
 )
 
 // insertType describes the type of insert statement to generate.
@@ -170,12 +189,28 @@ func (vr *vreplicator) buildReplicatorPlan(source *binlogdatapb.BinlogSource, co
 			// Table was excluded.
 			continue
 		}
-		if dup, ok := plan.TablePlans[tablePlan.SendRule.Match]; ok {
-			return nil, fmt.Errorf("more than one target for source table %s: %s and %s", tablePlan.SendRule.Match, dup.TargetName, tableName)
+		// The changes of every table of a join are sent with the same
+		// filter and are applied to the same target table.
+		tablePlans := []*TablePlan{tablePlan}
+		for _, joinTable := range tablePlan.JoinTables {
+			if joinTable == tablePlan.SendRule.Match {
+				continue
+			}
+			joinPlan := *tablePlan
+			joinPlan.SendRule = &binlogdatapb.Rule{
+				Match:  joinTable,
+				Filter: tablePlan.SendRule.Filter,
+			}
+			tablePlans = append(tablePlans, &joinPlan)
+		}
+		for _, tp := range tablePlans {
+			if dup, ok := plan.TablePlans[tp.SendRule.Match]; ok {
+				return nil, fmt.Errorf("more than one target for source table %s: %s and %s", tp.SendRule.Match, dup.TargetName, tableName)
+			}
+			plan.VStreamFilter.Rules = append(plan.VStreamFilter.Rules, tp.SendRule)
+			plan.TablePlans[tp.SendRule.Match] = tp
 		}
-		plan.VStreamFilter.Rules = append(plan.VStreamFilter.Rules, tablePlan.SendRule)
 		plan.TargetTables[tableName] = tablePlan
-		plan.TablePlans[tablePlan.SendRule.Match] = tablePlan
 	}
 	return plan, nil
 }
@@ -226,6 +261,30 @@ func buildTablePlan(tableName string, rule *binlogdatapb.Rule, colInfos []*Colum
 	case filter == ExcludeStr:
 		return nil, nil
 	}
+	joinTables, err := analyzeJoin(query, parser)
+	if err != nil {
+		return nil, planError(err, query)
+	}
+	if len(joinTables) > 0 {
+		// The columns of a join are only known once the source sends
+		// them, so we return a partial plan as for a "select *". The
+		// source validates the join.
+		tablePlan := &TablePlan{
+			TargetName: tableName,
+			SendRule: &binlogdatapb.Rule{
+				Match:  joinTables[0],
+				Filter: query,
+			},
+			Lastpk:           lastpk,
+			Stats:            stats,
+			ConvertCharset:   rule.ConvertCharset,
+			ConvertIntToEnum: rule.ConvertIntToEnum,
+			CollationEnv:     collationEnv,
+			WorkflowConfig:   workflowConfig,
+			JoinTables:       joinTables,
+		}
+		return tablePlan, nil
+	}
 	sel, fromTable, err := analyzeSelectFrom(query, parser)
 	if err != nil {
 		return nil, planError(err, query)
@@ -290,6 +349,9 @@ func buildTablePlan(tableName string, rule *binlogdatapb.Rule, colInfos []*Colum
 	if err := tpb.analyzeGroupBy(sel.GroupBy); err != nil {
 		return nil, planError(err, sqlparser.String(sel))
 	}
+	if err := tpb.analyzeAggregates(); err != nil {
+		return nil, planError(err, sqlparser.String(sel))
+	}
 	targetKeyColumnNames, err := textutil.SplitUnescape(rule.TargetUniqueKeyColumns, ",")
 	if err != nil {
 		return nil, err
@@ -372,6 +434,7 @@ func (tpb *tablePlanBuilder) generate() *TablePlan {
 		Update:                  tpb.generateUpdateStatement(),
 		Delete:                  tpb.generateDeleteStatement(),
 		MultiDelete:             tpb.generateMultiDeleteStatement(),
+		MinMax:                  tpb.generateMinMaxPlan(),
 		PKReferences:            pkrefs,
 		PKIndices:               tpb.pkIndices,
 		Stats:                   tpb.stats,
@@ -385,6 +448,52 @@ func (tpb *tablePlanBuilder) generate() *TablePlan {
 	}
 }
 
+// analyzeJoin returns the names of the tables joined by the query, or nil
+// if the query does not select from a join.
+func analyzeJoin(query string, parser *sqlparser.Parser) ([]string, error) {
+	statement, err := parser.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := statement.(*sqlparser.Select)
+	if !ok || len(sel.From) == 0 {
+		return nil, nil
+	}
+	if _, ok := sel.From[0].(*sqlparser.AliasedTableExpr); ok && len(sel.From) == 1 {
+		return nil, nil
+	}
+	for _, expr := range sel.GetColumns() {
+		if _, ok := expr.(*sqlparser.StarExpr); ok {
+			return nil, fmt.Errorf("unsupported '*' in a join, the columns must be listed")
+		}
+	}
+	var tables []string
+	for _, tableExpr := range sel.From {
+		err := sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			switch node := node.(type) {
+			case *sqlparser.JoinTableExpr:
+				if node.Join != sqlparser.NormalJoinType && node.Join != sqlparser.StraightJoinType {
+					return false, fmt.Errorf("unsupported %s, only inner joins are supported", node.Join.ToString())
+				}
+			case *sqlparser.AliasedTableExpr:
+				tableName, ok := node.Expr.(sqlparser.TableName)
+				if !ok {
+					return false, fmt.Errorf("unsupported from source (%T)", node.Expr)
+				}
+				tables = append(tables, tableName.Name.String())
+				return false, nil
+			case *sqlparser.JoinCondition:
+				return false, nil
+			}
+			return true, nil
+		}, tableExpr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
 func analyzeSelectFrom(query string, parser *sqlparser.Parser) (sel *sqlparser.Select, from string, err error) {
 	statement, err := parser.Parse(query)
 	if err != nil {
@@ -417,6 +526,21 @@ func (tpb *tablePlanBuilder) analyzeExprs(selExprs []sqlparser.SelectExpr) error
 		if err != nil {
 			return err
 		}
+		if cexpr.operation == opAvg {
+			// The hidden columns come first because the average is
+			// computed from their updated values.
+			tpb.colExprs = append(tpb.colExprs, &colExpr{
+				colName:    cexpr.sumColName,
+				operation:  opSum,
+				expr:       cexpr.expr,
+				references: cexpr.references,
+			}, &colExpr{
+				colName:    cexpr.countColName,
+				operation:  opCountCol,
+				expr:       cexpr.expr,
+				references: cexpr.references,
+			})
+		}
 		tpb.colExprs = append(tpb.colExprs, cexpr)
 	}
 	return nil
@@ -487,30 +611,41 @@ func (tpb *tablePlanBuilder) analyzeExpr(selExpr sqlparser.SelectExpr) (*colExpr
 		if sqlparser.IsDistinct(expr) {
 			return nil, fmt.Errorf("unsupported distinct expression usage: %v", sqlparser.String(expr))
 		}
-		switch fname := expr.AggrName(); fname {
+		fname := expr.AggrName()
+		switch fname {
 		case "count":
-			if _, ok := expr.(*sqlparser.CountStar); !ok {
-				return nil, fmt.Errorf("only count(*) is supported: %v", sqlparser.String(expr))
+			if _, ok := expr.(*sqlparser.CountStar); ok {
+				cexpr.operation = opCount
+				return cexpr, nil
 			}
-			cexpr.operation = opCount
-			return cexpr, nil
+			cexpr.operation = opCountCol
 		case "sum":
-			if len(expr.GetArgs()) != 1 {
-				return nil, fmt.Errorf("unsupported multiple columns in sum clause: %v", sqlparser.String(expr))
-			}
-			innerCol, ok := expr.GetArg().(*sqlparser.ColName)
-			if !ok {
-				return nil, fmt.Errorf("unsupported non-column name in sum clause: %v", sqlparser.String(expr))
-			}
-			if !innerCol.Qualifier.IsEmpty() {
-				return nil, fmt.Errorf("unsupported qualifier for column: %v", sqlparser.String(innerCol))
-			}
 			cexpr.operation = opSum
-			cexpr.expr = innerCol
-			tpb.addCol(innerCol.Name)
-			cexpr.references[innerCol.Name.String()] = true
-			return cexpr, nil
+		case "min":
+			cexpr.operation = opMin
+		case "max":
+			cexpr.operation = opMax
+		case "avg":
+			cexpr.operation = opAvg
+			cexpr.sumColName = sqlparser.NewIdentifierCI(as.String() + avgSumSuffix)
+			cexpr.countColName = sqlparser.NewIdentifierCI(as.String() + avgCountSuffix)
+		default:
+			return nil, fmt.Errorf("unsupported aggregation function: %v", sqlparser.String(expr))
+		}
+		if len(expr.GetArgs()) != 1 {
+			return nil, fmt.Errorf("unsupported multiple columns in %s clause: %v", fname, sqlparser.String(expr))
+		}
+		innerCol, ok := expr.GetArg().(*sqlparser.ColName)
+		if !ok {
+			return nil, fmt.Errorf("unsupported non-column name in %s clause: %v", fname, sqlparser.String(expr))
 		}
+		if !innerCol.Qualifier.IsEmpty() {
+			return nil, fmt.Errorf("unsupported qualifier for column: %v", sqlparser.String(innerCol))
+		}
+		cexpr.expr = innerCol
+		tpb.addCol(innerCol.Name)
+		cexpr.references[innerCol.Name.String()] = true
+		return cexpr, nil
 	}
 	err := sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
 		switch node := node.(type) {
@@ -548,13 +683,9 @@ func (tpb *tablePlanBuilder) analyzeGroupBy(groupBy *sqlparser.GroupBy) error {
 		return nil
 	}
 	for _, expr := range groupBy.Exprs {
-		colname, ok := expr.(*sqlparser.ColName)
-		if !ok {
-			return fmt.Errorf("unsupported non-column name or alias in group by clause: %v", sqlparser.String(expr))
-		}
-		cexpr := tpb.findCol(colname.Name)
+		cexpr := tpb.findGroupByCol(expr)
 		if cexpr == nil {
-			return fmt.Errorf("group by expression does not reference an alias in the select list: %v", sqlparser.String(expr))
+			return fmt.Errorf("group by expression does not reference an alias or an expression in the select list: %v", sqlparser.String(expr))
 		}
 		if cexpr.operation != opExpr {
 			return fmt.Errorf("group by expression is not allowed to reference an aggregate expression: %v", sqlparser.String(expr))
@@ -573,6 +704,51 @@ func (tpb *tablePlanBuilder) analyzeGroupBy(groupBy *sqlparser.GroupBy) error {
 	return nil
 }
 
+// findGroupByCol finds the column of the select list that a group by
+// expression refers to, either by its alias or by its expression.
+func (tpb *tablePlanBuilder) findGroupByCol(expr sqlparser.Expr) *colExpr {
+	if colname, ok := expr.(*sqlparser.ColName); ok && colname.Qualifier.IsEmpty() {
+		if cexpr := tpb.findCol(colname.Name); cexpr != nil {
+			return cexpr
+		}
+	}
+	for _, cexpr := range tpb.colExprs {
+		if cexpr.operation == opExpr && sqlparser.Equals.Expr(cexpr.expr, expr) {
+			return cexpr
+		}
+	}
+	return nil
+}
+
+// analyzeAggregates validates the aggregates that are only maintained per
+// group. The min and max of a group are recomputed from the source rows of
+// the group when a row that may hold them is removed, which requires the
+// group to be made of source columns.
+func (tpb *tablePlanBuilder) analyzeAggregates() error {
+	for _, cexpr := range tpb.colExprs {
+		switch cexpr.operation {
+		case opCountCol, opAvg, opMin, opMax:
+		default:
+			continue
+		}
+		if tpb.onInsert != insertOnDup {
+			return fmt.Errorf("unsupported aggregate %v without a group by clause", cexpr.colName)
+		}
+		if cexpr.operation != opMin && cexpr.operation != opMax {
+			continue
+		}
+		for _, gexpr := range tpb.colExprs {
+			if !gexpr.isGrouped {
+				continue
+			}
+			if _, ok := gexpr.expr.(*sqlparser.ColName); !ok {
+				return fmt.Errorf("unsupported aggregate %v with a group by expression: %v", cexpr.colName, sqlparser.String(gexpr.expr))
+			}
+		}
+	}
+	return nil
+}
+
 func (tpb *tablePlanBuilder) getPKColsInfo(uniqueKeyColumns []string, colInfos []*ColumnInfo) (pkColsInfo []*ColumnInfo) {
 	if len(uniqueKeyColumns) == 0 {
 		// No PK override
@@ -734,6 +910,10 @@ func (tpb *tablePlanBuilder) generateValuesPart(buf *sqlparser.TrackedBuffer, bv
 		case opSum:
 			// NULL values must be treated as 0 for SUM.
 			buf.Myprintf("ifnull(%v, 0)", cexpr.expr)
+		case opCountCol:
+			buf.Myprintf("if(%v is null, 0, 1)", cexpr.expr)
+		case opMin, opMax, opAvg:
+			buf.Myprintf("%v", cexpr.expr)
 		}
 	}
 	buf.Myprintf(")")
@@ -757,6 +937,10 @@ func (tpb *tablePlanBuilder) generateSelectPart(buf *sqlparser.TrackedBuffer, bv
 			buf.WriteString("1")
 		case opSum:
 			buf.Myprintf("ifnull(%v, 0)", cexpr.expr)
+		case opCountCol:
+			buf.Myprintf("if(%v is null, 0, 1)", cexpr.expr)
+		case opMin, opMax, opAvg:
+			buf.Myprintf("%v", cexpr.expr)
 		}
 	}
 	buf.WriteString(" from dual where ")
@@ -794,6 +978,14 @@ func (tpb *tablePlanBuilder) generateOnDupPart(buf *sqlparser.TrackedBuffer) *sq
 		case opSum:
 			buf.Myprintf("%v", cexpr.colName)
 			buf.Myprintf("+ifnull(values(%v), 0)", cexpr.colName)
+		case opCountCol:
+			buf.Myprintf("%v+values(%v)", cexpr.colName, cexpr.colName)
+		case opMin, opMax:
+			// NULL values are ignored by MIN and MAX.
+			buf.Myprintf("coalesce(%s(%v, values(%v)), %v, values(%v))", extremeFunc(cexpr.operation),
+				cexpr.colName, cexpr.colName, cexpr.colName, cexpr.colName)
+		case opAvg:
+			buf.Myprintf("%v/nullif(%v, 0)", cexpr.sumColName, cexpr.countColName)
 		}
 	}
 	return buf.ParsedQuery()
@@ -842,6 +1034,20 @@ func (tpb *tablePlanBuilder) generateUpdateStatement() *sqlparser.ParsedQuery {
 			buf.Myprintf("-ifnull(%v, 0)", cexpr.expr)
 			bvf.mode = bvAfter
 			buf.Myprintf("+ifnull(%v, 0)", cexpr.expr)
+		case opCountCol:
+			buf.Myprintf("%v", cexpr.colName)
+			bvf.mode = bvBefore
+			buf.Myprintf("-if(%v is null, 0, 1)", cexpr.expr)
+			bvf.mode = bvAfter
+			buf.Myprintf("+if(%v is null, 0, 1)", cexpr.expr)
+		case opMin, opMax:
+			// The value of the before image is removed by recomputing
+			// the aggregate, see TablePlan.recomputeMinMax.
+			bvf.mode = bvAfter
+			buf.Myprintf("coalesce(%s(%v, %v), %v, %v)", extremeFunc(cexpr.operation),
+				cexpr.colName, cexpr.expr, cexpr.colName, cexpr.expr)
+		case opAvg:
+			buf.Myprintf("%v/nullif(%v, 0)", cexpr.sumColName, cexpr.countColName)
 		}
 	}
 	tpb.generateWhere(buf, bvf)
@@ -872,6 +1078,13 @@ func (tpb *tablePlanBuilder) generateDeleteStatement() *sqlparser.ParsedQuery {
 				buf.Myprintf("%v-1", cexpr.colName)
 			case opSum:
 				buf.Myprintf("%v-ifnull(%v, 0)", cexpr.colName, cexpr.expr)
+			case opCountCol:
+				buf.Myprintf("%v-if(%v is null, 0, 1)", cexpr.colName, cexpr.expr)
+			case opMin, opMax:
+				// Recomputed after the delete, see TablePlan.recomputeMinMax.
+				buf.Myprintf("%v", cexpr.colName)
+			case opAvg:
+				buf.Myprintf("%v/nullif(%v, 0)", cexpr.sumColName, cexpr.countColName)
 			}
 		}
 		tpb.generateWhere(buf, bvf)
@@ -881,9 +1094,24 @@ func (tpb *tablePlanBuilder) generateDeleteStatement() *sqlparser.ParsedQuery {
 	return buf.ParsedQuery()
 }
 
+// generateJoinDeleteStatement generates the statement that deletes all the
+// joined rows of a primary key of one of the joined tables.
+func (tpb *tablePlanBuilder) generateJoinDeleteStatement(keyColumns []sqlparser.IdentifierCI) *sqlparser.ParsedQuery {
+	bvf := &bindvarFormatter{mode: bvBefore}
+	buf := sqlparser.NewTrackedBuffer(bvf.formatter)
+	buf.Myprintf("delete from %v where ", tpb.name)
+	separator := ""
+	for _, col := range keyColumns {
+		buf.Myprintf("%s%v=%v", separator, col, &sqlparser.ColName{Name: col})
+		separator = " and "
+	}
+	return buf.ParsedQuery()
+}
+
 func (tpb *tablePlanBuilder) generateMultiDeleteStatement() *sqlparser.ParsedQuery {
+	// The deletes of grouped rows update their aggregates instead.
 	if tpb.workflowConfig.ExperimentalFlags&vttablet.VReplicationExperimentalFlagVPlayerBatching == 0 ||
-		(len(tpb.pkCols)+len(tpb.extraSourcePkCols)) != 1 {
+		(len(tpb.pkCols)+len(tpb.extraSourcePkCols)) != 1 || tpb.onInsert == insertOnDup {
 		return nil
 	}
 	return sqlparser.BuildParsedQuery("delete from %s where %s in %a",
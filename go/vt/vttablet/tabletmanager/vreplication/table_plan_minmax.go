@@ -0,0 +1,272 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// minMaxPlan recomputes the min and max aggregates of a group. Unlike
+// counts and sums, they cannot be maintained from the changed rows alone:
+// once the row that holds the min or the max of a group is deleted, or
+// moved out of the group, the new value can only be found by going over
+// the rows of the group that remain in the source.
+type minMaxPlan struct {
+	// Check selects the target row of the group if one of its aggregates
+	// holds a value of the removed row.
+	Check *sqlparser.ParsedQuery
+	// Select streams the aggregated columns of the source rows. The
+	// conditions that select the group are added to it for every group.
+	Select *sqlparser.Select
+	// GroupColumns are the source columns of the group.
+	GroupColumns []*sqlparser.ColName
+	Aggregates   []*minMaxAggregate
+	// Update sets the recomputed aggregates of the group.
+	Update *sqlparser.ParsedQuery
+}
+
+// minMaxAggregate is a min or max aggregate of a minMaxPlan.
+type minMaxAggregate struct {
+	// Column is the aggregated source column.
+	Column string
+	// BindVar is the name of the bind variable of the recomputed value.
+	BindVar string
+	IsMax   bool
+}
+
+// extremeFunc returns the function that keeps the new min or max of a group
+// when a value is added to it.
+func extremeFunc(op operation) string {
+	if op == opMax {
+		return "greatest"
+	}
+	return "least"
+}
+
+// filterHasMinMax returns true if a rule of the filter has min or max
+// aggregates. Their plans read the target rows after each row change, so the
+// statements of the transaction cannot be batched.
+func filterHasMinMax(filter *binlogdatapb.Filter, parser *sqlparser.Parser) bool {
+	for _, rule := range filter.GetRules() {
+		if rule.Filter == "" {
+			continue
+		}
+		statement, err := parser.Parse(rule.Filter)
+		if err != nil {
+			// The error is returned when the plan is built.
+			continue
+		}
+		hasMinMax := false
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			switch node.(type) {
+			case *sqlparser.Min, *sqlparser.Max:
+				hasMinMax = true
+			}
+			return !hasMinMax, nil
+		}, statement)
+		if hasMinMax {
+			return true
+		}
+	}
+	return false
+}
+
+// generateMinMaxPlan generates the plan that recomputes the min and max
+// aggregates of a group, or nil if there are none.
+func (tpb *tablePlanBuilder) generateMinMaxPlan() *minMaxPlan {
+	if !slices.ContainsFunc(tpb.colExprs, func(cexpr *colExpr) bool {
+		return cexpr.operation == opMin || cexpr.operation == opMax
+	}) {
+		return nil
+	}
+	plan := &minMaxPlan{
+		Select: &sqlparser.Select{
+			From:  tpb.sendSelect.From,
+			Where: tpb.sendSelect.Where,
+		},
+	}
+	bvf := &bindvarFormatter{}
+	check := sqlparser.NewTrackedBuffer(bvf.formatter)
+	check.Myprintf("select 1 from %v", tpb.name)
+	tpb.generateWhere(check, bvf)
+	update := sqlparser.NewTrackedBuffer(bvf.formatter)
+	update.Myprintf("update %v set ", tpb.name)
+	checkSeparator := " and ("
+	updateSeparator := ""
+	for _, cexpr := range tpb.colExprs {
+		if col, ok := cexpr.expr.(*sqlparser.ColName); ok && cexpr.isGrouped {
+			plan.GroupColumns = append(plan.GroupColumns, col)
+		}
+		if cexpr.operation != opMin && cexpr.operation != opMax {
+			continue
+		}
+		aggr := &minMaxAggregate{
+			Column:  cexpr.expr.(*sqlparser.ColName).Name.String(),
+			BindVar: "r_" + cexpr.colName.String(),
+			IsMax:   cexpr.operation == opMax,
+		}
+		plan.Aggregates = append(plan.Aggregates, aggr)
+		plan.Select.AddSelectExpr(&sqlparser.AliasedExpr{Expr: cexpr.expr})
+		bvf.mode = bvBefore
+		check.Myprintf("%s%v=%v", checkSeparator, cexpr.colName, cexpr.expr)
+		checkSeparator = " or "
+		update.Myprintf("%s%v=%v", updateSeparator, cexpr.colName, sqlparser.NewArgument(aggr.BindVar))
+		updateSeparator = ", "
+	}
+	check.WriteString(") limit 1")
+	tpb.generateWhere(update, bvf)
+	plan.Check = check.ParsedQuery()
+	plan.Update = update.ParsedQuery()
+	return plan
+}
+
+// recomputeMinMax recomputes the min and max aggregates of the group that
+// the before image of the row change was removed from, if one of them may
+// have held a value of that row. The rows of the group are streamed from
+// the source, which may be ahead of the change being applied. This is fine
+// because the min and max of a group are not changed by adding a value that
+// is already accounted for, and the removal of a value that was already
+// ignored recomputes them again.
+func (tp *TablePlan) recomputeMinMax(ctx context.Context, rowChange *binlogdatapb.RowChange, executor func(string) (*sqltypes.Result, error),
+	vstreamer VStreamerClient, options *binlogdatapb.VStreamOptions) error {
+	if tp.MinMax == nil || rowChange.Before == nil || !tp.removesMinMaxValue(rowChange) {
+		return nil
+	}
+	before := sqltypes.MakeRowTrusted(tp.Fields, rowChange.Before)
+	bindvars := make(map[string]*querypb.BindVariable, len(tp.Fields)+len(tp.MinMax.Aggregates))
+	for i, field := range tp.Fields {
+		bindVar, err := tp.bindFieldVal(field, &before[i])
+		if err != nil {
+			return err
+		}
+		bindvars["b_"+field.Name] = bindVar
+	}
+	qr, err := execParsedQuery(tp.MinMax.Check, bindvars, executor)
+	if err != nil {
+		return err
+	}
+	if len(qr.Rows) == 0 {
+		return nil
+	}
+
+	sel := sqlparser.Clone(tp.MinMax.Select)
+	for _, col := range tp.MinMax.GroupColumns {
+		name := "b_" + col.Name.String()
+		if bv, ok := bindvars[name]; ok && bv.Type == querypb.Type_NULL_TYPE {
+			sel.AddWhere(&sqlparser.IsExpr{Left: col, Right: sqlparser.IsNullOp})
+			continue
+		}
+		sel.AddWhere(&sqlparser.ComparisonExpr{Operator: sqlparser.EqualOp, Left: col, Right: sqlparser.NewArgument(name)})
+	}
+	query, err := sqlparser.NewParsedQuery(sel).GenerateQuery(bindvars, nil)
+	if err != nil {
+		return err
+	}
+	values := make([]sqltypes.Value, len(tp.MinMax.Aggregates))
+	var fields []*querypb.Field
+	err = vstreamer.VStreamRows(ctx, query, nil, func(rows *binlogdatapb.VStreamRowsResponse) error {
+		if len(rows.Fields) > 0 {
+			fields = rows.Fields
+		}
+		for _, row := range rows.Rows {
+			vals := sqltypes.MakeRowTrusted(fields, row)
+			for i, aggr := range tp.MinMax.Aggregates {
+				if vals[i].IsNull() {
+					continue
+				}
+				if values[i].IsNull() {
+					values[i] = vals[i]
+					continue
+				}
+				cmp, err := evalengine.NullsafeCompare(vals[i], values[i], tp.CollationEnv, collations.ID(fields[i].Charset), nil)
+				if err != nil {
+					return err
+				}
+				if (aggr.IsMax && cmp > 0) || (!aggr.IsMax && cmp < 0) {
+					values[i] = vals[i]
+				}
+			}
+		}
+		return nil
+	}, options)
+	if err != nil {
+		return fmt.Errorf("failed to recompute the min and max aggregates of table %s: %v", tp.TargetName, err)
+	}
+	for i, aggr := range tp.MinMax.Aggregates {
+		bindvars[aggr.BindVar] = sqltypes.ValueBindVariable(values[i])
+		for _, field := range tp.Fields {
+			if field.Name != aggr.Column {
+				continue
+			}
+			bindVar, err := tp.bindFieldVal(field, &values[i])
+			if err != nil {
+				return err
+			}
+			bindvars[aggr.BindVar] = bindVar
+			break
+		}
+	}
+	_, err = execParsedQuery(tp.MinMax.Update, bindvars, executor)
+	return err
+}
+
+// removesMinMaxValue returns true if the row change removes a non-null value
+// of a min or max aggregate from a group: the row is deleted, moved to another
+// group, or the aggregated value is changed.
+func (tp *TablePlan) removesMinMaxValue(rowChange *binlogdatapb.RowChange) bool {
+	before := sqltypes.MakeRowTrusted(tp.Fields, rowChange.Before)
+	var after []sqltypes.Value
+	if rowChange.After != nil {
+		after = sqltypes.MakeRowTrusted(tp.Fields, rowChange.After)
+	}
+	changed := func(column string) bool {
+		for i, field := range tp.Fields {
+			if field.Name == column {
+				return after == nil || before[i].IsNull() != after[i].IsNull() || before[i].ToString() != after[i].ToString()
+			}
+		}
+		return true
+	}
+	groupChanged := false
+	for _, col := range tp.MinMax.GroupColumns {
+		if changed(col.Name.String()) {
+			groupChanged = true
+			break
+		}
+	}
+	for _, aggr := range tp.MinMax.Aggregates {
+		for i, field := range tp.Fields {
+			if field.Name != aggr.Column || before[i].IsNull() {
+				continue
+			}
+			if groupChanged || changed(aggr.Column) {
+				return true
+			}
+		}
+	}
+	return false
+}
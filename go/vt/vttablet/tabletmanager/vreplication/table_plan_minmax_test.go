@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vttablet "vitess.io/vitess/go/vt/vttablet/common"
+)
+
+// minMaxStreamer is a VStreamerClient that streams the same rows for any
+// query, and records the queries.
+type minMaxStreamer struct {
+	VStreamerClient
+	fields  []*querypb.Field
+	rows    [][]sqltypes.Value
+	queries []string
+}
+
+func (s *minMaxStreamer) VStreamRows(ctx context.Context, query string, lastpk *querypb.QueryResult,
+	send func(*binlogdatapb.VStreamRowsResponse) error, options *binlogdatapb.VStreamOptions) error {
+	s.queries = append(s.queries, query)
+	resp := &binlogdatapb.VStreamRowsResponse{Fields: s.fields}
+	for _, row := range s.rows {
+		resp.Rows = append(resp.Rows, sqltypes.RowToProto3(row))
+	}
+	return send(resp)
+}
+
+func TestRecomputeMinMax(t *testing.T) {
+	vttablet.InitVReplicationConfigDefaults()
+	colInfos := map[string][]*ColumnInfo{
+		"t1": {&ColumnInfo{Name: "c1", IsPK: true}},
+	}
+	input := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "t1",
+			Filter: "select c1, count(*) as cnt, min(c2) as mn, max(c2) as mx from t2 where c3 > 0 group by c1",
+		}},
+	}
+	vr := &vreplicator{
+		workflowConfig: vttablet.DefaultVReplicationConfig,
+	}
+	plan, err := vr.buildReplicatorPlan(getSource(input), colInfos, nil, binlogplayer.NewStats(), collations.MySQL8(), sqlparser.NewTestParser())
+	require.NoError(t, err)
+	fields := sqltypes.MakeTestFields("c1|c2|c3", "int64|int64|int64")
+	tplan, err := plan.buildExecutionPlan(&binlogdatapb.FieldEvent{TableName: "t2", Fields: fields})
+	require.NoError(t, err)
+
+	streamer := &minMaxStreamer{
+		fields: sqltypes.MakeTestFields("c2|c2", "int64|int64"),
+		rows: [][]sqltypes.Value{
+			{sqltypes.NewInt64(7), sqltypes.NewInt64(7)},
+			{sqltypes.NULL, sqltypes.NULL},
+			{sqltypes.NewInt64(3), sqltypes.NewInt64(3)},
+			{sqltypes.NewInt64(12), sqltypes.NewInt64(12)},
+		},
+	}
+	var executed []string
+	holdsValue := true
+	executor := func(query string) (*sqltypes.Result, error) {
+		executed = append(executed, query)
+		if strings.HasPrefix(query, "select 1 from t1") && holdsValue {
+			return sqltypes.MakeTestResult(sqltypes.MakeTestFields("1", "int64"), "1"), nil
+		}
+		return &sqltypes.Result{}, nil
+	}
+	row := func(vals ...int64) *querypb.Row {
+		var values []sqltypes.Value
+		for _, val := range vals {
+			values = append(values, sqltypes.NewInt64(val))
+		}
+		return sqltypes.RowToProto3(values)
+	}
+	ctx := context.Background()
+
+	// An update of a column that is not aggregated by min or max.
+	err = tplan.recomputeMinMax(ctx, &binlogdatapb.RowChange{Before: row(1, 5, 1), After: row(1, 5, 2)}, executor, streamer, nil)
+	require.NoError(t, err)
+	assert.Empty(t, executed)
+
+	// A delete of a row of a group whose aggregates do not hold its value.
+	holdsValue = false
+	err = tplan.recomputeMinMax(ctx, &binlogdatapb.RowChange{Before: row(1, 5, 1)}, executor, streamer, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"select 1 from t1 where c1=1 and (mn=5 or mx=5) limit 1"}, executed)
+	assert.Empty(t, streamer.queries)
+
+	// A row that moves to another group.
+	holdsValue = true
+	executed = nil
+	err = tplan.recomputeMinMax(ctx, &binlogdatapb.RowChange{Before: row(1, 5, 1), After: row(2, 5, 1)}, executor, streamer, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"select 1 from t1 where c1=1 and (mn=5 or mx=5) limit 1",
+		"update t1 set mn=3, mx=12 where c1=1",
+	}, executed)
+	assert.Equal(t, []string{"select c2, c2 from t2 where c3 > 0 and c1 = 1"}, streamer.queries)
+}
+
+func TestFilterHasMinMax(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+	filter := func(queries ...string) *binlogdatapb.Filter {
+		filter := &binlogdatapb.Filter{}
+		for _, query := range queries {
+			filter.Rules = append(filter.Rules, &binlogdatapb.Rule{Match: "t1", Filter: query})
+		}
+		return filter
+	}
+	assert.False(t, filterHasMinMax(filter("", "select * from t1", "select c1, count(*) as cnt, sum(c2) as s from t1 group by c1"), parser))
+	assert.True(t, filterHasMinMax(filter("select * from t1", "select c1, max(c2) as mx from t1 group by c1"), parser))
+	assert.True(t, filterHasMinMax(filter("select c1, min(c2) as mn from t1 group by c1"), parser))
+}
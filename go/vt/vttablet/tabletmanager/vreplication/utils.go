@@ -56,6 +56,9 @@ const (
 	LogCopyEnd = "Ended Copy Phase"
 	// LogStateChange is used when the state of the stream changes.
 	LogStateChange = "State Changed"
+	// LogDDLReconciled is used when the target tables are altered to match
+	// the source tables after a DDL, with the RECONCILE on-ddl action.
+	LogDDLReconciled = "DDL Reconciled"
 
 	// TODO: LogError is not used atm. Currently irrecoverable errors, resumable errors and informational messages
 	//  are all treated the same: the message column is updated and state left as Running.
@@ -314,16 +314,37 @@ func (vc *vcopier) copyNext(ctx context.Context, settings binlogplayer.VRSetting
 	if len(copyState) == 0 {
 		return fmt.Errorf("unexpected: there are no tables to copy")
 	}
-	if err := vc.catchup(ctx, copyState); err != nil {
+	// The tables copied in primary key ranges are replicated based on the
+	// progress of their ranges rather than on their lastpk.
+	copyRanges, err := vc.readCopyRanges()
+	if err != nil {
 		return err
 	}
+	if copyRanges[tableToCopy] == nil && copyState[tableToCopy] == nil {
+		tr, err := vc.initCopyRanges(ctx, tableToCopy)
+		if err != nil {
+			return err
+		}
+		if tr != nil {
+			copyRanges[tableToCopy] = tr
+		}
+	}
+	for tableName := range copyRanges {
+		delete(copyState, tableName)
+	}
+	if err := vc.catchup(ctx, copyState, copyRanges); err != nil {
+		return err
+	}
+	if tr := copyRanges[tableToCopy]; tr != nil {
+		return vc.copyTableRanges(ctx, tr, copyState, copyRanges)
+	}
 	return vc.copyTable(ctx, tableToCopy, copyState)
 }
 
 // catchup replays events to the subset of the tables that have been copied
 // until replication is caught up. In order to stop, the seconds behind primary has
 // to fall below replicationLagTolerance.
-func (vc *vcopier) catchup(ctx context.Context, copyState map[string]*sqltypes.Result) error {
+func (vc *vcopier) catchup(ctx context.Context, copyState map[string]*sqltypes.Result, copyRanges map[string]*tableCopyRanges) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	defer vc.vr.stats.PhaseTimings.Record("catchup", time.Now())
@@ -341,7 +362,7 @@ func (vc *vcopier) catchup(ctx context.Context, copyState map[string]*sqltypes.R
 	// Start vreplication.
 	errch := make(chan error, 1)
 	go func() {
-		errch <- newVPlayer(vc.vr, settings, copyState, replication.Position{}, "catchup").play(ctx)
+		errch <- newVPlayer(vc.vr, settings, copyState, copyRanges, replication.Position{}, "catchup").play(ctx)
 	}()
 
 	// Wait for catchup.
@@ -705,7 +726,7 @@ func (vc *vcopier) fastForward(ctx context.Context, copyState map[string]*sqltyp
 		_, err := vc.vr.dbClient.Execute(update)
 		return err
 	}
-	return newVPlayer(vc.vr, settings, copyState, pos, "fastforward").play(ctx)
+	return newVPlayer(vc.vr, settings, copyState, nil, pos, "fastforward").play(ctx)
 }
 
 func (vc *vcopier) newCopyWorkQueue(
@@ -0,0 +1,767 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/bytes2"
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// A table with a single-column integer primary key can be copied in
+// vttablet.VReplicationConfig.CopyParallelRanges primary key ranges, each
+// streamed concurrently from its own consistent snapshot of the source. The
+// progress of the ranges is recorded in _vt.copy_range_state, while the row
+// of the table in _vt.copy_state is kept until all the ranges are copied.
+//
+// As the snapshots of the ranges are taken at different positions, the
+// replication of the table is filtered row by row while the ranges are
+// copied: a change to a row is applied only if the row was copied, and only
+// if the change is not already part of the snapshot the row was copied from.
+// The rows copied by the previous cycles are settled once the target is
+// fast-forwarded past the positions of all the ranges, after which all
+// their changes are applied.
+
+// copyRange is a primary key range of a table copied in its own stream.
+type copyRange struct {
+	// id is the range_id of the range in _vt.copy_range_state.
+	id int64
+	// start is exclusive and end is inclusive. A NULL value is unbounded.
+	start, end sqltypes.Value
+	// lastpk is the last primary key copied. It is NULL if no row was copied.
+	lastpk sqltypes.Value
+	// settledPK is the last primary key copied before the last settling of
+	// the ranges.
+	settledPK sqltypes.Value
+	// pos is the position of the snapshot the rows above settledPK were
+	// copied from. It is zero if there are none.
+	pos replication.Position
+	// done is set once all the rows of the range are copied.
+	done bool
+}
+
+// tableCopyRanges holds the primary key ranges of a table that is copied in
+// parallel.
+type tableCopyRanges struct {
+	table string
+	// pkField is the field of the primary key column.
+	pkField      *querypb.Field
+	ranges       []*copyRange
+	collationEnv *collations.Environment
+}
+
+// compare compares two primary key values of the table.
+func (tr *tableCopyRanges) compare(v1, v2 sqltypes.Value) (int, error) {
+	return evalengine.NullsafeCompare(v1, v2, tr.collationEnv, collations.Unknown, nil)
+}
+
+// rangeOf returns the range that holds the primary key.
+func (tr *tableCopyRanges) rangeOf(pk sqltypes.Value) (*copyRange, error) {
+	for _, r := range tr.ranges {
+		if !r.end.IsNull() {
+			cmp, err := tr.compare(pk, r.end)
+			if err != nil {
+				return nil, err
+			}
+			if cmp > 0 {
+				continue
+			}
+		}
+		return r, nil
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no copy range of table %s holds primary key %s", tr.table, pk.String())
+}
+
+// applies returns true if a change to the row of the primary key is applied
+// when the target is at the given position.
+func (tr *tableCopyRanges) applies(pk sqltypes.Value, pos replication.Position) (bool, error) {
+	r, err := tr.rangeOf(pk)
+	if err != nil {
+		return false, err
+	}
+	if !r.done {
+		if r.lastpk.IsNull() {
+			return false, nil
+		}
+		cmp, err := tr.compare(pk, r.lastpk)
+		if err != nil {
+			return false, err
+		}
+		if cmp > 0 {
+			// The row is not copied yet.
+			return false, nil
+		}
+	}
+	if r.pos.IsZero() {
+		return true, nil
+	}
+	if !r.settledPK.IsNull() {
+		cmp, err := tr.compare(pk, r.settledPK)
+		if err != nil {
+			return false, err
+		}
+		if cmp <= 0 {
+			return true, nil
+		}
+	}
+	// The row was copied from the snapshot of the range, which holds the
+	// changes before its position.
+	return pos.AtLeast(r.pos), nil
+}
+
+// filterChange returns the part of a row change of the table that is
+// applied when the target is at the given position, which is nil if none
+// is. An update that moves a row in or out of the rows that are applied is
+// turned into an insert or a delete.
+func (tr *tableCopyRanges) filterChange(fields []*querypb.Field, change *binlogdatapb.RowChange, pos replication.Position) (*binlogdatapb.RowChange, error) {
+	pkIndex := -1
+	for i, field := range fields {
+		if strings.EqualFold(strings.Trim(field.Name, "`"), tr.pkField.Name) {
+			pkIndex = i
+			break
+		}
+	}
+	if pkIndex == -1 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "primary key column %s of table %s not found in the row event", tr.pkField.Name, tr.table)
+	}
+	applies := func(row *querypb.Row) (bool, error) {
+		if row == nil {
+			return false, nil
+		}
+		return tr.applies(sqltypes.MakeRowTrusted(fields, row)[pkIndex], pos)
+	}
+	before, err := applies(change.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := applies(change.After)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case before == (change.Before != nil) && after == (change.After != nil):
+		return change, nil
+	case before:
+		return &binlogdatapb.RowChange{Before: change.Before}, nil
+	case after:
+		return &binlogdatapb.RowChange{After: change.After, DataColumns: change.DataColumns, JsonPartialValues: change.JsonPartialValues}, nil
+	}
+	return nil, nil
+}
+
+// maxPos returns the latest position of the ranges.
+func (tr *tableCopyRanges) maxPos() replication.Position {
+	var pos replication.Position
+	for _, r := range tr.ranges {
+		if !r.pos.IsZero() && !pos.AtLeast(r.pos) {
+			pos = r.pos
+		}
+	}
+	return pos
+}
+
+// allDone returns true if all the ranges are copied.
+func (tr *tableCopyRanges) allDone() bool {
+	for _, r := range tr.ranges {
+		if !r.done {
+			return false
+		}
+	}
+	return true
+}
+
+// encode encodes a primary key value the same way as the lastpk of
+// _vt.copy_state.
+func (tr *tableCopyRanges) encode(pk sqltypes.Value) *querypb.QueryResult {
+	return &querypb.QueryResult{
+		Fields: []*querypb.Field{tr.pkField},
+		Rows:   []*querypb.Row{sqltypes.RowToProto3([]sqltypes.Value{pk})},
+	}
+}
+
+// encodeSQL returns the SQL literal of the encoding of a primary key value,
+// which is NULL for a NULL value.
+func (tr *tableCopyRanges) encodeSQL(pk sqltypes.Value) (string, error) {
+	if pk.IsNull() {
+		return "null", nil
+	}
+	buf, err := prototext.Marshal(tr.encode(pk))
+	if err != nil {
+		return "", err
+	}
+	return encodeString(string(buf)), nil
+}
+
+// decodeCopyRangePK decodes a primary key value of _vt.copy_range_state.
+func decodeCopyRangePK(value sqltypes.Value) (sqltypes.Value, *querypb.Field, error) {
+	if value.IsNull() {
+		return sqltypes.NULL, nil, nil
+	}
+	var qr querypb.QueryResult
+	if err := prototext.Unmarshal(value.Raw(), &qr); err != nil {
+		return sqltypes.NULL, nil, err
+	}
+	result := sqltypes.Proto3ToResult(&qr)
+	if len(result.Fields) != 1 || len(result.Rows) != 1 || len(result.Rows[0]) != 1 {
+		return sqltypes.NULL, nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid copy range primary key: %s", value.ToString())
+	}
+	return result.Rows[0][0], result.Fields[0], nil
+}
+
+// splitCopyRanges splits the primary keys between min and max into n
+// ranges of the same span. The first and last ranges are unbounded, so that
+// the ranges hold all the primary keys. It returns nil if the span is too
+// small to be split.
+func splitCopyRanges(pkType querypb.Type, min, max sqltypes.Value, n int) ([]*copyRange, error) {
+	if n <= 1 {
+		return nil, nil
+	}
+	var bounds []sqltypes.Value
+	if sqltypes.IsUnsigned(pkType) {
+		lo, err := min.ToCastUint64()
+		if err != nil {
+			return nil, err
+		}
+		hi, err := max.ToCastUint64()
+		if err != nil {
+			return nil, err
+		}
+		if hi < lo || hi-lo < uint64(n) {
+			return nil, nil
+		}
+		step := (hi - lo) / uint64(n)
+		for i := 1; i < n; i++ {
+			bounds = append(bounds, sqltypes.MakeTrusted(pkType, strconv.AppendUint(nil, lo+uint64(i)*step, 10)))
+		}
+	} else {
+		lo, err := min.ToCastInt64()
+		if err != nil {
+			return nil, err
+		}
+		hi, err := max.ToCastInt64()
+		if err != nil {
+			return nil, err
+		}
+		// The span is computed as unsigned, as it may not fit in a signed value.
+		if hi < lo || uint64(hi)-uint64(lo) < uint64(n) {
+			return nil, nil
+		}
+		step := (uint64(hi) - uint64(lo)) / uint64(n)
+		for i := 1; i < n; i++ {
+			bounds = append(bounds, sqltypes.MakeTrusted(pkType, strconv.AppendInt(nil, int64(uint64(lo)+uint64(i)*step), 10)))
+		}
+	}
+	ranges := make([]*copyRange, 0, n)
+	start := sqltypes.NULL
+	for i, end := range append(bounds, sqltypes.NULL) {
+		ranges = append(ranges, &copyRange{id: int64(i), start: start, end: end})
+		start = end
+	}
+	return ranges, nil
+}
+
+// readCopyRanges reads the primary key ranges of the tables of the stream
+// that are copied in parallel.
+func (vc *vcopier) readCopyRanges() (map[string]*tableCopyRanges, error) {
+	qr, err := vc.vr.dbClient.Execute(fmt.Sprintf("select table_name, range_id, range_start, range_end, lastpk, settled_pk, pos, done from _vt.%s where vrepl_id = %d order by table_name, range_id",
+		copyRangeStateTableName, vc.vr.id))
+	if err != nil {
+		return nil, err
+	}
+	copyRanges := make(map[string]*tableCopyRanges)
+	for _, row := range qr.Rows {
+		tableName := row[0].ToString()
+		tr := copyRanges[tableName]
+		if tr == nil {
+			tr = &tableCopyRanges{table: tableName, collationEnv: vc.vr.vre.env.CollationEnv()}
+			copyRanges[tableName] = tr
+		}
+		r := &copyRange{}
+		if r.id, err = row[1].ToInt64(); err != nil {
+			return nil, err
+		}
+		for i, v := range []*sqltypes.Value{&r.start, &r.end, &r.lastpk, &r.settledPK} {
+			pk, field, err := decodeCopyRangePK(row[2+i])
+			if err != nil {
+				return nil, err
+			}
+			*v = pk
+			if field != nil {
+				tr.pkField = field
+			}
+		}
+		if pos := row[6].ToString(); pos != "" {
+			if r.pos, err = binlogplayer.DecodePosition(pos); err != nil {
+				return nil, err
+			}
+		}
+		r.done = row[7].ToString() == "1"
+		tr.ranges = append(tr.ranges, r)
+	}
+	for tableName, tr := range copyRanges {
+		if tr.pkField == nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "the copy ranges of table %s have no bounds", tableName)
+		}
+	}
+	return copyRanges, nil
+}
+
+// initCopyRanges splits the table into primary key ranges and records them
+// in _vt.copy_range_state, if the table can be copied in parallel. It
+// returns nil if the table is to be copied in a single stream.
+func (vc *vcopier) initCopyRanges(ctx context.Context, tableName string) (*tableCopyRanges, error) {
+	n := vc.vr.workflowConfig.CopyParallelRanges
+	if n <= 1 || vc.vr.source.Bidirectional != nil {
+		return nil, nil
+	}
+	ok, err := reconcilableTable(vc.vr.source.Filter, tableName, vc.vr.vre.env.Parser())
+	if err != nil || !ok {
+		return nil, err
+	}
+	var pkColumn *ColumnInfo
+	for _, col := range vc.vr.colInfoMap[tableName] {
+		if !col.IsPK {
+			continue
+		}
+		if pkColumn != nil {
+			return nil, nil
+		}
+		pkColumn = col
+	}
+	if pkColumn == nil {
+		return nil, nil
+	}
+	switch strings.ToLower(pkColumn.DataType) {
+	case "tinyint", "smallint", "mediumint", "int", "bigint":
+	default:
+		return nil, nil
+	}
+
+	qr, err := vc.vr.sourceVStreamer.GetPKBounds(ctx, tableName, pkColumn.Name)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get the primary key bounds of table %s", tableName)
+	}
+	if len(qr.Fields) != 2 || len(qr.Rows) != 1 || qr.Rows[0][0].IsNull() {
+		// The table is empty.
+		return nil, nil
+	}
+	pkType := qr.Fields[0].Type
+	ranges, err := splitCopyRanges(pkType, qr.Rows[0][0], qr.Rows[0][1], n)
+	if err != nil || ranges == nil {
+		return nil, err
+	}
+	tr := &tableCopyRanges{
+		table:        tableName,
+		pkField:      &querypb.Field{Name: pkColumn.Name, Type: pkType},
+		ranges:       ranges,
+		collationEnv: vc.vr.vre.env.CollationEnv(),
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "insert into _vt.%s(vrepl_id, table_name, range_id, range_start, range_end) values ", copyRangeStateTableName)
+	for i, r := range ranges {
+		start, err := tr.encodeSQL(r.start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := tr.encodeSQL(r.end)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "(%d, %s, %d, %s, %s)", vc.vr.id, encodeString(tableName), r.id, start, end)
+	}
+	if _, err := vc.vr.dbClient.Execute(buf.String()); err != nil {
+		return nil, err
+	}
+	vc.vr.insertLog(LogCopyStart, fmt.Sprintf("Copying table %s in %d primary key ranges", tableName, len(ranges)))
+	return tr, nil
+}
+
+// settleCopyRanges fast-forwards the target past the positions of all the
+// ranges of the table, after which all the changes to the rows copied so
+// far are applied.
+func (vc *vcopier) settleCopyRanges(ctx context.Context, tr *tableCopyRanges, copyState map[string]*sqltypes.Result, copyRanges map[string]*tableCopyRanges) error {
+	pos := tr.maxPos()
+	if pos.IsZero() {
+		return nil
+	}
+	settings, err := binlogplayer.ReadVRSettings(vc.vr.dbClient, vc.vr.id)
+	if err != nil {
+		return err
+	}
+	if !settings.StartPos.AtLeast(pos) {
+		if err := newVPlayer(vc.vr, settings, copyState, copyRanges, pos, "fastforward").play(ctx); err != nil {
+			return err
+		}
+		settings, err := binlogplayer.ReadVRSettings(vc.vr.dbClient, vc.vr.id)
+		if err != nil {
+			return err
+		}
+		if !settings.StartPos.AtLeast(pos) {
+			// The fast-forward was interrupted.
+			return io.EOF
+		}
+	}
+	if _, err := vc.vr.dbClient.Execute(fmt.Sprintf("update _vt.%s set settled_pk = lastpk, pos = null where vrepl_id = %d and table_name = %s",
+		copyRangeStateTableName, vc.vr.id, encodeString(tr.table))); err != nil {
+		return err
+	}
+	for _, r := range tr.ranges {
+		r.settledPK = r.lastpk
+		r.pos = replication.Position{}
+	}
+	return nil
+}
+
+// copyTableRanges copies the next set of rows of the ranges of the table
+// that are not done, each in its own stream. When all the ranges are
+// copied, the table is done.
+func (vc *vcopier) copyTableRanges(ctx context.Context, tr *tableCopyRanges, copyState map[string]*sqltypes.Result, copyRanges map[string]*tableCopyRanges) error {
+	defer vc.vr.dbClient.Rollback()
+	defer vc.vr.stats.PhaseTimings.Record("copy", time.Now())
+	defer vc.vr.stats.CopyLoopCount.Add(1)
+
+	log.Infof("Copying table %s in %d primary key ranges", tr.table, len(tr.ranges))
+
+	if err := vc.settleCopyRanges(ctx, tr, copyState, copyRanges); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	if !tr.allDone() {
+		copyCtx, cancel := context.WithTimeout(ctx, vc.vr.workflowConfig.CopyPhaseDuration)
+		defer cancel()
+		if err := vc.copyRanges(copyCtx, tr); err != nil {
+			return err
+		}
+		select {
+		case <-copyCtx.Done():
+			// The copy phase duration elapsed, or the stream is stopped.
+			log.Infof("Copy of %s in primary key ranges stopped", tr.table)
+			return nil
+		default:
+		}
+		if !tr.allDone() {
+			return nil
+		}
+		if err := vc.settleCopyRanges(ctx, tr, copyState, copyRanges); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+
+	if err := vc.vr.execPostCopyActions(ctx, tr.table); err != nil {
+		return vterrors.Wrapf(err, "failed to execute post copy actions for table %q", tr.table)
+	}
+	log.Infof("Copy of %s in primary key ranges finished", tr.table)
+	if err := vc.vr.dbClient.Begin(); err != nil {
+		return err
+	}
+	for _, tableName := range []string{copyStateTableName, copyRangeStateTableName, postCopyActionTableName} {
+		if _, err := vc.vr.dbClient.Execute(fmt.Sprintf("delete from _vt.%s where vrepl_id = %d and table_name = %s",
+			tableName, vc.vr.id, encodeString(tr.table))); err != nil {
+			return err
+		}
+	}
+	return vc.vr.dbClient.Commit()
+}
+
+// copyRanges copies the ranges of the table that are not done concurrently.
+// The streams are started one after the other, so that the snapshot of each
+// range is taken at a position past the one of the previous range.
+func (vc *vcopier) copyRanges(ctx context.Context, tr *tableCopyRanges) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	settings, err := binlogplayer.ReadVRSettings(vc.vr.dbClient, vc.vr.id)
+	if err != nil {
+		return err
+	}
+
+	rowsCopiedTicker := time.NewTicker(rowsCopiedUpdateInterval)
+	defer rowsCopiedTicker.Stop()
+
+	var wg sync.WaitGroup
+	rec := &concurrency.AllErrorRecorder{}
+	done := make(chan struct{})
+	for _, r := range tr.ranges {
+		if r.done {
+			continue
+		}
+		started := make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := vc.copyRange(ctx, tr, r, started); err != nil {
+				rec.RecordError(vterrors.Wrapf(err, "failed to copy range %d of table %s", r.id, tr.table))
+				cancel()
+			}
+		}()
+		select {
+		case <-started:
+		case <-ctx.Done():
+		}
+		if rec.HasErrors() || ctx.Err() != nil {
+			break
+		}
+		// If there's no start position, this is the first table being
+		// copied. The target starts at the position of the first range, as
+		// the rows of the other ranges are copied past it.
+		if settings.StartPos.IsZero() && !r.pos.IsZero() {
+			update := binlogplayer.GenerateUpdatePos(vc.vr.id, r.pos, time.Now().Unix(), 0, vc.vr.stats.CopyRowCount.Get(), vc.vr.workflowConfig.StoreCompressedGTID)
+			if _, err := vc.vr.dbClient.Execute(update); err != nil {
+				rec.RecordError(err)
+				cancel()
+				break
+			}
+			settings.StartPos = r.pos
+		}
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for copying := true; copying; {
+		select {
+		case <-rowsCopiedTicker.C:
+			update := binlogplayer.GenerateUpdateRowsCopied(vc.vr.id, vc.vr.stats.CopyRowCount.Get())
+			_, _ = vc.vr.dbClient.Execute(update)
+		case <-done:
+			copying = false
+		}
+	}
+	return rec.Error()
+}
+
+// copyRange streams the rows of a range from a snapshot of the source, and
+// commits each packet of rows with the lastpk and the position of the
+// snapshot in _vt.copy_range_state. The started channel is closed once the
+// snapshot is taken, or if the range fails before.
+func (vc *vcopier) copyRange(ctx context.Context, tr *tableCopyRanges, r *copyRange, started chan struct{}) error {
+	var startOnce sync.Once
+	setStarted := func() { startOnce.Do(func() { close(started) }) }
+	defer setStarted()
+
+	plan, err := vc.vr.buildReplicatorPlan(vc.vr.source, vc.vr.colInfoMap, nil, vc.vr.stats, vc.vr.vre.env.CollationEnv(), vc.vr.vre.env.Parser())
+	if err != nil {
+		return err
+	}
+	initialPlan, ok := plan.TargetTables[tr.table]
+	if !ok {
+		return fmt.Errorf("plan not found for table: %s, current plans are: %#v", tr.table, plan.TargetTables)
+	}
+	dbClient, err := vc.vr.newClientConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Close()
+	defer dbClient.Rollback()
+
+	var lastpkpb *querypb.QueryResult
+	switch {
+	case !r.lastpk.IsNull():
+		lastpkpb = tr.encode(r.lastpk)
+	case !r.start.IsNull():
+		lastpkpb = tr.encode(r.start)
+	}
+
+	var (
+		tablePlan  *TablePlan
+		pkIndex    int
+		pos        string
+		sqlbuffer  bytes2.Buffer
+		reachedEnd bool
+	)
+	updateState := func(lastpk sqltypes.Value, done bool) error {
+		lastpkSQL, err := tr.encodeSQL(lastpk)
+		if err != nil {
+			return err
+		}
+		doneValue := 0
+		if done {
+			doneValue = 1
+		}
+		_, err = dbClient.Execute(fmt.Sprintf("update _vt.%s set lastpk = %s, pos = %s, done = %d where vrepl_id = %d and table_name = %s and range_id = %d",
+			copyRangeStateTableName, lastpkSQL, encodeString(pos), doneValue, vc.vr.id, encodeString(tr.table), r.id))
+		return err
+	}
+	vstreamOptions := &binlogdatapb.VStreamOptions{
+		ConfigOverrides: vc.vr.workflowConfig.Overrides,
+	}
+	serr := vc.vr.sourceVStreamer.VStreamRows(ctx, initialPlan.SendRule.Filter, lastpkpb, func(rows *binlogdatapb.VStreamRowsResponse) error {
+		if rows.Throttled || rows.Heartbeat {
+			return nil
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return io.EOF
+			default:
+			}
+			checkResult, ok := vc.vr.vre.throttlerClient.ThrottleCheckOKOrWaitAppName(ctx, throttlerapp.Name(vc.throttlerAppName))
+			if ok {
+				break
+			}
+			vc.vr.stats.ThrottledCounts.Add([]string{"tablet", throttlerapp.VCopierName.String()}, 1)
+			_ = vc.vr.throttleUpdatesRateLimiter.Do(func() error {
+				update, err := binlogplayer.GenerateUpdateTimeThrottled(vc.vr.id, time.Now().Unix(), throttlerapp.VCopierName.String(), checkResult.Summary())
+				if err != nil {
+					return err
+				}
+				_, err = dbClient.ExecuteFetch(update, maxRows)
+				return err
+			})
+		}
+		if tablePlan == nil {
+			if len(rows.Fields) == 0 {
+				return fmt.Errorf("expecting field event first, got: %v", rows)
+			}
+			gtid, err := binlogplayer.DecodePosition(rows.Gtid)
+			if err != nil {
+				return err
+			}
+			r.pos = gtid
+			pos = replication.EncodePosition(gtid)
+			fieldEvent := &binlogdatapb.FieldEvent{
+				TableName: initialPlan.SendRule.Match,
+			}
+			pkIndex = -1
+			for i, f := range rows.Fields {
+				fieldEvent.Fields = append(fieldEvent.Fields, f.CloneVT())
+				if strings.EqualFold(strings.Trim(f.Name, "`"), tr.pkField.Name) {
+					pkIndex = i
+				}
+			}
+			if pkIndex == -1 {
+				return fmt.Errorf("primary key column %s of table %s not found in the streamed fields", tr.pkField.Name, tr.table)
+			}
+			if tablePlan, err = plan.buildExecutionPlan(fieldEvent); err != nil {
+				return err
+			}
+			setStarted()
+		}
+		if len(rows.Rows) == 0 {
+			return nil
+		}
+
+		// Keep the rows within the range.
+		copied := rows.Rows
+		lastpk := r.lastpk
+		for i, row := range rows.Rows {
+			pk := sqltypes.MakeRowTrusted(tablePlan.Fields, row)[pkIndex]
+			if !r.end.IsNull() {
+				cmp, err := tr.compare(pk, r.end)
+				if err != nil {
+					return err
+				}
+				if cmp > 0 {
+					copied = rows.Rows[:i]
+					reachedEnd = true
+					break
+				}
+			}
+			lastpk = pk
+		}
+
+		start := time.Now()
+		if err := dbClient.Begin(); err != nil {
+			return err
+		}
+		if len(copied) > 0 {
+			if _, err := tablePlan.applyBulkInsert(&sqlbuffer, copied, func(sql string) (*sqltypes.Result, error) {
+				return dbClient.ExecuteWithRetry(ctx, sql)
+			}); err != nil {
+				return err
+			}
+		}
+		if err := updateState(lastpk, reachedEnd); err != nil {
+			return err
+		}
+		if err := dbClient.Commit(); err != nil {
+			return err
+		}
+		r.lastpk = lastpk
+		vc.vr.stats.CopyRowCount.Add(int64(len(copied)))
+		vc.vr.stats.QueryCount.Add("copy", 1)
+		vc.vr.stats.TableCopyRowCounts.Add(tr.table, int64(len(copied)))
+		vc.vr.stats.TableCopyTimings.Add(tr.table, time.Since(start))
+		if reachedEnd {
+			// The rows past the end of the range are copied by the next one.
+			return io.EOF
+		}
+		return nil
+	}, vstreamOptions)
+	if reachedEnd {
+		r.done = true
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		// A context expiration is a normal interruption of the copy phase.
+		return nil
+	default:
+	}
+	if serr != nil {
+		vc.vr.stats.ErrorCounts.Add([]string{"Copy"}, 1)
+		return serr
+	}
+	if tablePlan == nil {
+		return fmt.Errorf("the stream of range %d of table %s ended before it started", r.id, tr.table)
+	}
+	// All the rows past the start of the range are copied.
+	if err := updateState(r.lastpk, true); err != nil {
+		return err
+	}
+	r.done = true
+	return nil
+}
+
+// pkBoundsQuery returns the query that selects the minimum and maximum
+// values of the primary key column of a table.
+func pkBoundsQuery(table, column string) string {
+	return sqlparser.BuildParsedQuery("select min(%s), max(%s) from %s",
+		sqlescape.EscapeID(column), sqlescape.EscapeID(column), sqlescape.EscapeID(table)).Query
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestSplitCopyRanges(t *testing.T) {
+	bounds := func(ranges []*copyRange) []string {
+		var res []string
+		for _, r := range ranges {
+			res = append(res, r.start.String()+":"+r.end.String())
+		}
+		return res
+	}
+
+	ranges, err := splitCopyRanges(querypb.Type_INT32, sqltypes.NewInt32(1), sqltypes.NewInt32(100), 4)
+	require.NoError(t, err)
+	require.Equal(t, []string{"NULL:INT32(25)", "INT32(25):INT32(49)", "INT32(49):INT32(73)", "INT32(73):NULL"}, bounds(ranges))
+	for i, r := range ranges {
+		require.EqualValues(t, i, r.id)
+	}
+
+	ranges, err = splitCopyRanges(querypb.Type_INT64, sqltypes.NewInt64(-9223372036854775808), sqltypes.NewInt64(9223372036854775807), 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"NULL:INT64(-1)", "INT64(-1):NULL"}, bounds(ranges))
+
+	ranges, err = splitCopyRanges(querypb.Type_UINT64, sqltypes.NewUint64(0), sqltypes.NewUint64(18446744073709551615), 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"NULL:UINT64(6148914691236517205)", "UINT64(6148914691236517205):UINT64(12297829382473034410)", "UINT64(12297829382473034410):NULL"}, bounds(ranges))
+
+	// Spans too small to be split.
+	ranges, err = splitCopyRanges(querypb.Type_INT32, sqltypes.NewInt32(1), sqltypes.NewInt32(3), 4)
+	require.NoError(t, err)
+	require.Nil(t, ranges)
+	ranges, err = splitCopyRanges(querypb.Type_INT32, sqltypes.NewInt32(1), sqltypes.NewInt32(100), 1)
+	require.NoError(t, err)
+	require.Nil(t, ranges)
+}
+
+func TestCopyRangesFilterChange(t *testing.T) {
+	pos1, err := replication.DecodePosition("MySQL56/00000000-0000-0000-0000-000000000001:1-10")
+	require.NoError(t, err)
+	pos2, err := replication.DecodePosition("MySQL56/00000000-0000-0000-0000-000000000001:1-20")
+	require.NoError(t, err)
+
+	fields := []*querypb.Field{{Name: "val", Type: querypb.Type_VARCHAR}, {Name: "id", Type: querypb.Type_INT64}}
+	tr := &tableCopyRanges{
+		table:        "t1",
+		pkField:      &querypb.Field{Name: "id", Type: querypb.Type_INT64},
+		collationEnv: collations.MySQL8(),
+		ranges: []*copyRange{
+			// Copied up to 50 at pos2, settled up to 30.
+			{id: 0, start: sqltypes.NULL, end: sqltypes.NewInt64(100), lastpk: sqltypes.NewInt64(50), settledPK: sqltypes.NewInt64(30), pos: pos2},
+			// Not copied yet.
+			{id: 1, start: sqltypes.NewInt64(100), end: sqltypes.NewInt64(200)},
+			// Copied at pos1.
+			{id: 2, start: sqltypes.NewInt64(200), end: sqltypes.NULL, lastpk: sqltypes.NewInt64(250), pos: pos1, done: true},
+		},
+	}
+	row := func(id int64) *querypb.Row {
+		return sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewVarChar("a"), sqltypes.NewInt64(id)})
+	}
+
+	testcases := []struct {
+		name   string
+		before *querypb.Row
+		after  *querypb.Row
+		pos    replication.Position
+		want   *binlogdatapb.RowChange
+	}{{
+		name:  "insert of a settled row",
+		after: row(10),
+		pos:   pos1,
+		want:  &binlogdatapb.RowChange{After: row(10)},
+	}, {
+		name:  "insert of a row copied past the position",
+		after: row(40),
+		pos:   pos1,
+	}, {
+		name:  "insert of a row copied before the position",
+		after: row(40),
+		pos:   pos2,
+		want:  &binlogdatapb.RowChange{After: row(40)},
+	}, {
+		name:  "insert of a row not copied yet",
+		after: row(60),
+		pos:   pos2,
+	}, {
+		name:  "insert of a row of a range not copied yet",
+		after: row(150),
+		pos:   pos2,
+	}, {
+		name:   "update of a row of a done range",
+		before: row(300),
+		after:  row(300),
+		pos:    pos1,
+		want:   &binlogdatapb.RowChange{Before: row(300), After: row(300)},
+	}, {
+		name:   "update moving a row out of the copied rows",
+		before: row(10),
+		after:  row(150),
+		pos:    pos2,
+		want:   &binlogdatapb.RowChange{Before: row(10)},
+	}, {
+		name:   "update moving a row into the copied rows",
+		before: row(150),
+		after:  row(10),
+		pos:    pos2,
+		want:   &binlogdatapb.RowChange{After: row(10)},
+	}, {
+		name:   "delete of a row not copied yet",
+		before: row(150),
+		pos:    pos2,
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tr.filterChange(fields, &binlogdatapb.RowChange{Before: tc.before, After: tc.after}, tc.pos)
+			require.NoError(t, err)
+			if tc.want == nil {
+				require.Nil(t, got)
+				return
+			}
+			require.Equal(t, prototext.Format(tc.want), prototext.Format(got))
+		})
+	}
+}
+
+func TestCopyRangePKEncoding(t *testing.T) {
+	tr := &tableCopyRanges{pkField: &querypb.Field{Name: "id", Type: querypb.Type_UINT64}}
+	enc, err := tr.encodeSQL(sqltypes.NULL)
+	require.NoError(t, err)
+	require.Equal(t, "null", enc)
+
+	buf, err := prototext.Marshal(tr.encode(sqltypes.NewUint64(42)))
+	require.NoError(t, err)
+	pk, field, err := decodeCopyRangePK(sqltypes.NewVarBinary(string(buf)))
+	require.NoError(t, err)
+	require.Equal(t, sqltypes.NewUint64(42), pk)
+	require.Equal(t, "id", field.Name)
+
+	pk, field, err = decodeCopyRangePK(sqltypes.NULL)
+	require.NoError(t, err)
+	require.True(t, pk.IsNull())
+	require.Nil(t, field)
+}
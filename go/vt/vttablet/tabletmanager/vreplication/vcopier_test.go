@@ -547,6 +547,7 @@ func testPlayerCopyTablesWithFK(t *testing.T) {
 		"/delete from _vt.vreplication",
 		"/delete from _vt.copy_state",
 		"/delete from _vt.post_copy_action",
+		"/delete from _vt.copy_range_state",
 		"commit",
 	))
 }
@@ -1776,6 +1777,7 @@ func testCopyTablesWithInvalidDates(t *testing.T) {
 		"/delete from _vt.vreplication",
 		"/delete from _vt.copy_state",
 		"/delete from _vt.post_copy_action",
+		"/delete from _vt.copy_range_state",
 		"commit",
 	))
 }
@@ -0,0 +1,363 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication/sink"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+const (
+	// defaultExportBatchRows is the number of rows after which a batch
+	// is written if the sink does not specify one.
+	defaultExportBatchRows = 10000
+	// defaultExportBatchInterval is the time after which a batch is
+	// written if the sink does not specify one.
+	defaultExportBatchInterval = time.Minute
+)
+
+// vexporter streams the rows of the source tables of an Export workflow to
+// the files of its sink. It uses the copy phase of the source vstreamer,
+// rather than a vcopier, as there are no target tables to copy the rows to.
+// The position and the last primary keys of the stream are only consistent
+// at the end of a transaction, and only then is a batch committed.
+type vexporter struct {
+	vr     *vreplicator
+	writer *sink.Writer
+
+	maxRows     int64
+	maxInterval time.Duration
+
+	pos           string
+	lastPKs       map[string]*querypb.QueryResult
+	copiedTables  map[string]bool
+	copyCompleted bool
+	fields        map[string][]*querypb.Field
+	timestamp     int64
+	lastCommit    time.Time
+
+	// The records of the current transaction, which is a transaction of
+	// the copy phase if it has a LASTPK event.
+	inTransaction bool
+	records       []*sink.Record
+	snapshot      bool
+}
+
+func newVExporter(vr *vreplicator, writer *sink.Writer, settings *binlogdatapb.SinkSettings) (*vexporter, error) {
+	ve := &vexporter{
+		vr:           vr,
+		writer:       writer,
+		maxRows:      settings.MaxBatchRows,
+		maxInterval:  time.Duration(settings.MaxBatchSeconds) * time.Second,
+		lastPKs:      make(map[string]*querypb.QueryResult),
+		copiedTables: make(map[string]bool),
+		fields:       make(map[string][]*querypb.Field),
+		lastCommit:   time.Now(),
+	}
+	if ve.maxRows <= 0 {
+		ve.maxRows = defaultExportBatchRows
+	}
+	if ve.maxInterval <= 0 {
+		ve.maxInterval = defaultExportBatchInterval
+	}
+	cp := writer.Checkpoint()
+	ve.pos = cp.Position
+	ve.copyCompleted = cp.CopyCompleted
+	for table, lastpk := range cp.LastPKs {
+		var qr *querypb.QueryResult
+		if lastpk != "" {
+			qr = &querypb.QueryResult{}
+			if err := prototext.Unmarshal([]byte(lastpk), qr); err != nil {
+				return nil, fmt.Errorf("invalid last primary key of table %s in checkpoint %d: %v", table, cp.Batch, err)
+			}
+		}
+		ve.lastPKs[table] = qr
+	}
+	for _, table := range cp.CopiedTables {
+		ve.copiedTables[table] = true
+	}
+	// If no table was copied yet, there are no rows to resume from and
+	// the copy starts over.
+	if !ve.copyCompleted && len(ve.lastPKs) == 0 && len(ve.copiedTables) == 0 {
+		ve.pos = ""
+	}
+	return ve, nil
+}
+
+// export streams the rows of the source tables to the sink, instead of
+// applying them to the tables of the target keyspace.
+func (vr *vreplicator) export(ctx context.Context) error {
+	settings, _, err := vr.loadSettings(ctx, vr.dbClient)
+	if err != nil {
+		return err
+	}
+	if settings.State == binlogdatapb.VReplicationWorkflowState_Stopped || settings.State == binlogdatapb.VReplicationWorkflowState_Error {
+		return nil
+	}
+	if err := vr.validateBinlogRowImage(); err != nil {
+		return err
+	}
+	storage, err := sink.NewStorage(vr.source.Sink.Storage, vr.source.Sink.Directory)
+	if err != nil {
+		return err
+	}
+	defer storage.Close()
+	writer, err := sink.NewWriter(ctx, storage, vr.source.Sink.Format, vr.source.Keyspace, vr.source.Shard)
+	if err != nil {
+		return err
+	}
+	ve, err := newVExporter(vr, writer, vr.source.Sink)
+	if err != nil {
+		return err
+	}
+	return ve.export(ctx)
+}
+
+func (ve *vexporter) export(ctx context.Context) error {
+	if ve.copyCompleted {
+		if ve.vr.source.StopAfterCopy {
+			return ve.vr.setState(binlogdatapb.VReplicationWorkflowState_Stopped, "Stopped after copy.")
+		}
+		if err := ve.vr.setState(binlogdatapb.VReplicationWorkflowState_Running, ""); err != nil {
+			return err
+		}
+	} else if err := ve.vr.setState(binlogdatapb.VReplicationWorkflowState_Copying, ""); err != nil {
+		return err
+	}
+
+	var tablePKs []*binlogdatapb.TableLastPK
+	options := &binlogdatapb.VStreamOptions{
+		ConfigOverrides: ve.vr.workflowConfig.Overrides,
+	}
+	if !ve.copyCompleted {
+		// The tables whose copy completed are not copied again, as the
+		// changes to their rows since the checkpoint are streamed instead.
+		options.CopiedTables = ve.copiedTableNames()
+		for table, lastpk := range ve.lastPKs {
+			tablePKs = append(tablePKs, &binlogdatapb.TableLastPK{TableName: table, Lastpk: lastpk})
+		}
+		sort.Slice(tablePKs, func(i, j int) bool {
+			return tablePKs[i].TableName < tablePKs[j].TableName
+		})
+	}
+	err := ve.vr.sourceVStreamer.VStream(ctx, ve.pos, tablePKs, ve.vr.source.Filter, func(events []*binlogdatapb.VEvent) error {
+		return ve.applyEvents(ctx, events)
+	}, options)
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// applyEvents adds the rows of the events to the batch being written, and
+// commits the batch once it is large or old enough.
+func (ve *vexporter) applyEvents(ctx context.Context, events []*binlogdatapb.VEvent) error {
+	for _, event := range events {
+		select {
+		case <-ctx.Done():
+			return io.EOF
+		default:
+		}
+		switch event.Type {
+		case binlogdatapb.VEventType_GTID:
+			ve.pos = event.Gtid
+		case binlogdatapb.VEventType_BEGIN:
+			ve.inTransaction = true
+			ve.records = nil
+			ve.snapshot = false
+		case binlogdatapb.VEventType_FIELD:
+			ve.fields[event.FieldEvent.TableName] = event.FieldEvent.Fields
+		case binlogdatapb.VEventType_ROW:
+			if err := ve.addRowEvent(event); err != nil {
+				return err
+			}
+		case binlogdatapb.VEventType_LASTPK:
+			ve.snapshot = true
+			tablePK := event.LastPKEvent.TableLastPK
+			if event.LastPKEvent.Completed {
+				delete(ve.lastPKs, tablePK.TableName)
+				ve.copiedTables[tablePK.TableName] = true
+			} else {
+				ve.lastPKs[tablePK.TableName] = tablePK.Lastpk
+			}
+		case binlogdatapb.VEventType_COMMIT:
+			ve.inTransaction = false
+			for _, rec := range ve.records {
+				if ve.snapshot {
+					rec.Op = sink.OpSnapshot
+					rec.Timestamp = 0
+					ve.vr.stats.CopyRowCount.Add(1)
+				}
+				if err := ve.writer.Add(rec); err != nil {
+					return err
+				}
+			}
+			ve.records = nil
+			if event.Timestamp != 0 {
+				ve.timestamp = event.Timestamp
+			}
+			if err := ve.commitIfNeeded(ctx); err != nil {
+				return err
+			}
+		case binlogdatapb.VEventType_COPY_COMPLETED:
+			ve.copyCompleted = true
+			ve.lastPKs = nil
+			ve.copiedTables = nil
+			if err := ve.commit(ctx); err != nil {
+				return err
+			}
+			ve.vr.insertLog(LogCopyEnd, fmt.Sprintf("Copy phase completed at gtid %s", ve.pos))
+			if ve.vr.source.StopAfterCopy {
+				if err := ve.vr.setState(binlogdatapb.VReplicationWorkflowState_Stopped, "Stopped after copy."); err != nil {
+					return err
+				}
+				return io.EOF
+			}
+			if err := ve.vr.setState(binlogdatapb.VReplicationWorkflowState_Running, ""); err != nil {
+				return err
+			}
+		case binlogdatapb.VEventType_HEARTBEAT, binlogdatapb.VEventType_DDL, binlogdatapb.VEventType_OTHER:
+			if event.Type == binlogdatapb.VEventType_HEARTBEAT {
+				ve.vr.stats.RecordHeartbeat(event.Timestamp)
+			}
+			if !ve.inTransaction {
+				if err := ve.commitIfNeeded(ctx); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addRowEvent adds the records of the row changes of an event to the
+// current transaction.
+func (ve *vexporter) addRowEvent(event *binlogdatapb.VEvent) error {
+	table := event.RowEvent.TableName
+	fields, ok := ve.fields[table]
+	if !ok {
+		return fmt.Errorf("unexpected row event for table %s without a field event", table)
+	}
+	for _, change := range event.RowEvent.RowChanges {
+		rec := &sink.Record{
+			Table:     table,
+			Timestamp: event.Timestamp,
+		}
+		var err error
+		if change.Before != nil {
+			if rec.Before, err = sink.RowJSON(fields, sqltypes.MakeRowTrusted(fields, change.Before)); err != nil {
+				return err
+			}
+		}
+		if change.After != nil {
+			if rec.After, err = sink.RowJSON(fields, sqltypes.MakeRowTrusted(fields, change.After)); err != nil {
+				return err
+			}
+		}
+		switch {
+		case change.Before == nil:
+			rec.Op = sink.OpInsert
+		case change.After == nil:
+			rec.Op = sink.OpDelete
+		default:
+			rec.Op = sink.OpUpdate
+		}
+		ve.records = append(ve.records, rec)
+	}
+	return nil
+}
+
+// copiedTableNames returns the sorted names of the tables whose copy
+// completed.
+func (ve *vexporter) copiedTableNames() []string {
+	tables := make([]string, 0, len(ve.copiedTables))
+	for table := range ve.copiedTables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// commitIfNeeded commits the batch if it has enough rows, or if it is old
+// enough and the stream made some progress since the last checkpoint.
+func (ve *vexporter) commitIfNeeded(ctx context.Context) error {
+	if ve.pos == "" {
+		return nil
+	}
+	if ve.writer.Rows() >= ve.maxRows {
+		return ve.commit(ctx)
+	}
+	if time.Since(ve.lastCommit) >= ve.maxInterval &&
+		(ve.writer.Rows() > 0 || ve.pos != ve.writer.Checkpoint().Position) {
+		return ve.commit(ctx)
+	}
+	return nil
+}
+
+// commit writes the batch and its checkpoint, and then records the position
+// of the checkpoint in the stream so that it can be followed by the workflow
+// commands. The checkpoint, not the stream, is where the export resumes from.
+func (ve *vexporter) commit(ctx context.Context) error {
+	var lastPKs map[string]string
+	var copiedTables []string
+	if !ve.copyCompleted {
+		copiedTables = ve.copiedTableNames()
+		lastPKs = make(map[string]string, len(ve.lastPKs))
+		for table, lastpk := range ve.lastPKs {
+			if lastpk == nil {
+				lastPKs[table] = ""
+				continue
+			}
+			buf, err := prototext.Marshal(lastpk)
+			if err != nil {
+				return err
+			}
+			lastPKs[table] = string(buf)
+		}
+	}
+	now := time.Now()
+	if err := ve.writer.Commit(ctx, ve.pos, lastPKs, copiedTables, ve.copyCompleted, now); err != nil {
+		return fmt.Errorf("failed to write batch %d: %v", ve.writer.Checkpoint().Batch+1, err)
+	}
+	ve.lastCommit = now
+	log.V(2).Infof("Export stream %d committed batch %d at %s", ve.vr.id, ve.writer.Checkpoint().Batch, ve.pos)
+
+	pos, err := replication.DecodePosition(ve.pos)
+	if err != nil {
+		return err
+	}
+	update := binlogplayer.GenerateUpdatePos(ve.vr.id, pos, now.Unix(), ve.timestamp, ve.vr.stats.CopyRowCount.Get(), ve.vr.workflowConfig.StoreCompressedGTID)
+	if _, err := ve.vr.dbClient.Execute(update); err != nil {
+		return fmt.Errorf("error %v updating position", err)
+	}
+	ve.vr.stats.SetLastPosition(pos)
+	return nil
+}
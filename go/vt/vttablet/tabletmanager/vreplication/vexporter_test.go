@@ -0,0 +1,208 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	vttablet "vitess.io/vitess/go/vt/vttablet/common"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication/sink"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// exportStreamer is a VStreamerClient that sends a fixed list of events,
+// and records where the stream started from.
+type exportStreamer struct {
+	VStreamerClient
+	events   [][]*binlogdatapb.VEvent
+	startPos string
+	tablePKs []*binlogdatapb.TableLastPK
+	options  *binlogdatapb.VStreamOptions
+}
+
+func (s *exportStreamer) VStream(ctx context.Context, startPos string, tablePKs []*binlogdatapb.TableLastPK, filter *binlogdatapb.Filter,
+	send func([]*binlogdatapb.VEvent) error, options *binlogdatapb.VStreamOptions) error {
+	s.startPos = startPos
+	s.tablePKs = tablePKs
+	s.options = options
+	for _, events := range s.events {
+		if err := send(events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestVExporter(t *testing.T) {
+	vttablet.InitVReplicationConfigDefaults()
+	ctx := context.Background()
+	const uuid = "MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3"
+
+	fields := sqltypes.MakeTestFields("id|val", "int64|varchar")
+	row := func(id int64, val string) *querypb.Row {
+		return sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(id), sqltypes.NewVarChar(val)})
+	}
+	tableRowEvent := func(table string, changes ...*binlogdatapb.RowChange) *binlogdatapb.VEvent {
+		return &binlogdatapb.VEvent{
+			Type:      binlogdatapb.VEventType_ROW,
+			Timestamp: 100,
+			RowEvent:  &binlogdatapb.RowEvent{TableName: table, RowChanges: changes},
+		}
+	}
+	rowEvent := func(changes ...*binlogdatapb.RowChange) *binlogdatapb.VEvent {
+		return tableRowEvent("t1", changes...)
+	}
+	lastPK := sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "2"))
+	t2LastPK := sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "4"))
+	copyEvents := [][]*binlogdatapb.VEvent{{
+		{Type: binlogdatapb.VEventType_BEGIN},
+		{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "t1", Fields: fields}},
+		{Type: binlogdatapb.VEventType_GTID, Gtid: uuid + ":1-5"},
+		rowEvent(&binlogdatapb.RowChange{After: row(1, "a")}),
+		rowEvent(&binlogdatapb.RowChange{After: row(2, "b")}),
+		{Type: binlogdatapb.VEventType_LASTPK, LastPKEvent: &binlogdatapb.LastPKEvent{TableLastPK: &binlogdatapb.TableLastPK{TableName: "t1", Lastpk: lastPK}}},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	}}
+	completeEvents := [][]*binlogdatapb.VEvent{{
+		{Type: binlogdatapb.VEventType_BEGIN},
+		{Type: binlogdatapb.VEventType_LASTPK, LastPKEvent: &binlogdatapb.LastPKEvent{TableLastPK: &binlogdatapb.TableLastPK{TableName: "t1"}, Completed: true}},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	}, {
+		{Type: binlogdatapb.VEventType_BEGIN},
+		{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "t2", Fields: fields}},
+		tableRowEvent("t2", &binlogdatapb.RowChange{After: row(3, "x")}),
+		tableRowEvent("t2", &binlogdatapb.RowChange{After: row(4, "y")}),
+		{Type: binlogdatapb.VEventType_LASTPK, LastPKEvent: &binlogdatapb.LastPKEvent{TableLastPK: &binlogdatapb.TableLastPK{TableName: "t2", Lastpk: t2LastPK}}},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	}}
+	replicateEvents := [][]*binlogdatapb.VEvent{{
+		{Type: binlogdatapb.VEventType_BEGIN},
+		{Type: binlogdatapb.VEventType_LASTPK, LastPKEvent: &binlogdatapb.LastPKEvent{TableLastPK: &binlogdatapb.TableLastPK{TableName: "t2"}, Completed: true}},
+		{Type: binlogdatapb.VEventType_COMMIT},
+	}, {
+		{Type: binlogdatapb.VEventType_COPY_COMPLETED},
+	}, {
+		{Type: binlogdatapb.VEventType_BEGIN},
+		{Type: binlogdatapb.VEventType_FIELD, FieldEvent: &binlogdatapb.FieldEvent{TableName: "t1", Fields: fields}},
+		rowEvent(&binlogdatapb.RowChange{Before: row(1, "a"), After: row(1, "c")}),
+		{Type: binlogdatapb.VEventType_GTID, Gtid: uuid + ":1-6"},
+		{Type: binlogdatapb.VEventType_COMMIT, Timestamp: 100},
+	}, {
+		{Type: binlogdatapb.VEventType_BEGIN},
+		rowEvent(&binlogdatapb.RowChange{Before: row(2, "b")}),
+		{Type: binlogdatapb.VEventType_GTID, Gtid: uuid + ":1-7"},
+		{Type: binlogdatapb.VEventType_COMMIT, Timestamp: 101},
+	}}
+
+	storage, err := sink.NewStorage(sink.StorageLocal, t.TempDir())
+	require.NoError(t, err)
+	settings := &binlogdatapb.SinkSettings{Storage: sink.StorageLocal, MaxBatchRows: 2}
+	dbClient := binlogplayer.NewMockDBClient(t)
+	dbClient.AddInvariant("vreplication_log", &sqltypes.Result{})
+	dbClient.AddInvariant("set state=", &sqltypes.Result{})
+	stats := binlogplayer.NewStats()
+	defer stats.Stop()
+	newExporter := func(events [][]*binlogdatapb.VEvent) (*vexporter, *exportStreamer) {
+		streamer := &exportStreamer{events: events}
+		vr := &vreplicator{
+			id:              1,
+			source:          &binlogdatapb.BinlogSource{Keyspace: "ks", Shard: "0", Sink: settings},
+			sourceVStreamer: streamer,
+			stats:           stats,
+			dbClient:        newVDBClient(dbClient, stats, 0),
+			workflowConfig:  vttablet.DefaultVReplicationConfig,
+		}
+		writer, err := sink.NewWriter(ctx, storage, sink.FormatNDJSON, "ks", "0")
+		require.NoError(t, err)
+		ve, err := newVExporter(vr, writer, settings)
+		require.NoError(t, err)
+		return ve, streamer
+	}
+
+	// The copy phase is interrupted after the first batch.
+	dbClient.ExpectRequestRE(`update _vt\.vreplication set pos='`+uuid+`:1-5', time_updated=\d+, rows_copied=2, message='' where id=1`, &sqltypes.Result{}, nil)
+	ve, streamer := newExporter(copyEvents)
+	require.NoError(t, ve.export(ctx))
+	assert.Empty(t, streamer.startPos)
+	assert.Empty(t, streamer.tablePKs)
+
+	// The copy phase resumes from the checkpoint of the first batch, and is
+	// interrupted again once the copy of the first table completed.
+	dbClient.ExpectRequestRE(`update _vt\.vreplication set pos='`+uuid+`:1-5', time_updated=\d+, rows_copied=4, message='' where id=1`, &sqltypes.Result{}, nil)
+	ve, streamer = newExporter(completeEvents)
+	require.NoError(t, ve.export(ctx))
+	assert.Equal(t, uuid+":1-5", streamer.startPos)
+	require.Len(t, streamer.tablePKs, 1)
+	assert.Equal(t, "t1", streamer.tablePKs[0].TableName)
+	assert.Equal(t, lastPK.Rows, streamer.tablePKs[0].Lastpk.Rows)
+	assert.Empty(t, streamer.options.CopiedTables)
+	cp := ve.writer.Checkpoint()
+	assert.EqualValues(t, 2, cp.Batch)
+	assert.Equal(t, []string{"t1"}, cp.CopiedTables)
+	assert.Len(t, cp.LastPKs, 1)
+	assert.Contains(t, cp.LastPKs, "t2")
+
+	// The table whose copy completed is not copied again.
+	dbClient.ExpectRequestRE(`update _vt\.vreplication set pos='`+uuid+`:1-5', .* where id=1`, &sqltypes.Result{}, nil)
+	dbClient.ExpectRequestRE(`update _vt\.vreplication set pos='`+uuid+`:1-7', time_updated=\d+, transaction_timestamp=101, .* where id=1`, &sqltypes.Result{}, nil)
+	ve, streamer = newExporter(replicateEvents)
+	require.NoError(t, ve.export(ctx))
+	assert.Equal(t, uuid+":1-5", streamer.startPos)
+	require.Len(t, streamer.tablePKs, 1)
+	assert.Equal(t, "t2", streamer.tablePKs[0].TableName)
+	assert.Equal(t, t2LastPK.Rows, streamer.tablePKs[0].Lastpk.Rows)
+	assert.Equal(t, []string{"t1"}, streamer.options.CopiedTables)
+
+	cp = ve.writer.Checkpoint()
+	assert.EqualValues(t, 4, cp.Batch)
+	assert.True(t, cp.CopyCompleted)
+	assert.Empty(t, cp.LastPKs)
+	assert.Empty(t, cp.CopiedTables)
+	require.Len(t, cp.Files, 1)
+	dir, file, _ := strings.Cut(cp.Files[0], "/batch=")
+	data, err := storage.ReadFile(ctx, dir, "batch="+strings.TrimSuffix(file, "/data.ndjson"), "data.ndjson")
+	require.NoError(t, err)
+	var records []*sink.Record
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		rec := &sink.Record{}
+		require.NoError(t, json.Unmarshal([]byte(line), rec))
+		records = append(records, rec)
+	}
+	require.Len(t, records, 2)
+	assert.Equal(t, sink.OpUpdate, records[0].Op)
+	assert.EqualValues(t, 100, records[0].Timestamp)
+	assert.JSONEq(t, `{"id":1,"val":"a"}`, string(records[0].Before))
+	assert.JSONEq(t, `{"id":1,"val":"c"}`, string(records[0].After))
+	assert.Equal(t, sink.OpDelete, records[1].Op)
+	assert.Nil(t, records[1].After)
+
+	// Once copied, the stream resumes from the position of the checkpoint.
+	ve, streamer = newExporter(nil)
+	require.NoError(t, ve.export(ctx))
+	assert.Equal(t, uuid+":1-7", streamer.startPos)
+	assert.Empty(t, streamer.tablePKs)
+}
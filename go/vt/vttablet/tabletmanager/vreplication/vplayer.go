@@ -35,6 +35,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
 )
 
 const failedToRecordHeartbeatMsg = "failed to record heartbeat"
@@ -56,6 +57,9 @@ type vplayer struct {
 	stopPos   replication.Position
 	saveStop  bool
 	copyState map[string]*sqltypes.Result
+	// copyRanges holds the primary key ranges of the tables being copied
+	// in parallel, see vcopier_range.go.
+	copyRanges map[string]*tableCopyRanges
 
 	replicatorPlan *ReplicatorPlan
 	tablePlans     map[string]*TablePlan
@@ -99,6 +103,19 @@ type vplayer struct {
 	// foreignKeyChecksStateInitialized is set to true once we have initialized the foreignKeyChecksEnabled.
 	// The initialization is done on the first row event that this vplayer sees.
 	foreignKeyChecksStateInitialized bool
+
+	// The fields below are used by the streams of bidirectional workflows, see
+	// bidirectional.go.
+
+	// internalFields holds the fields of the sidecar tables being streamed.
+	internalFields map[string][]*querypb.Field
+	// skipTransaction is set if the current source transaction was applied by
+	// the peer workflow.
+	skipTransaction bool
+	// transactionMarked is set once the current target transaction is marked.
+	transactionMarked bool
+	// conflictTables caches whether the conflicts of a table are detected.
+	conflictTables map[string]bool
 }
 
 // NoForeignKeyCheckFlagBitmask is the bitmask for the 2nd bit (least significant) of the flags in a binlog row event.
@@ -116,10 +133,15 @@ const NoForeignKeyCheckFlagBitmask uint32 = 1 << 1
 //	of being copied. If copyState is non-nil, the plans generated make sure that
 //	replication is only applied to parts that have been copied so far.
 //
+// copyRanges: if set, contains the tables being copied in primary key ranges. The
+//
+//	changes to their rows are only applied to the parts of the ranges that have
+//	been copied so far.
+//
 // pausePos: if set, replication will stop at that position without updating the state to "Stopped".
 //
 //	This is used by the fastForward function during copying.
-func newVPlayer(vr *vreplicator, settings binlogplayer.VRSettings, copyState map[string]*sqltypes.Result, pausePos replication.Position, phase string) *vplayer {
+func newVPlayer(vr *vreplicator, settings binlogplayer.VRSettings, copyState map[string]*sqltypes.Result, copyRanges map[string]*tableCopyRanges, pausePos replication.Position, phase string) *vplayer {
 	saveStop := true
 	if !pausePos.IsZero() {
 		settings.StopPos = pausePos
@@ -134,8 +156,13 @@ func newVPlayer(vr *vreplicator, settings binlogplayer.VRSettings, copyState map
 	commitFunc := func() error {
 		return vr.dbClient.Commit()
 	}
-	// We only do batching in the running/replicating phase.
-	batchMode := len(copyState) == 0 && vr.workflowConfig.ExperimentalFlags&vttablet.VReplicationExperimentalFlagVPlayerBatching != 0
+	// We only do batching in the running/replicating phase, and not for
+	// bidirectional workflows, which read the target rows to detect conflicts,
+	// nor for workflows with min or max aggregates, which read the target rows
+	// to recompute them, see TablePlan.recomputeMinMax.
+	batchMode := len(copyState) == 0 && len(copyRanges) == 0 && vr.source.Bidirectional == nil &&
+		vr.workflowConfig.ExperimentalFlags&vttablet.VReplicationExperimentalFlagVPlayerBatching != 0 &&
+		!filterHasMinMax(vr.source.Filter, vr.vre.env.Parser())
 
 	if batchMode {
 		// relayLogMaxSize is effectively the limit used when not batching.
@@ -174,6 +201,7 @@ func newVPlayer(vr *vreplicator, settings binlogplayer.VRSettings, copyState map
 		stopPos:          settings.StopPos,
 		saveStop:         saveStop,
 		copyState:        copyState,
+		copyRanges:       copyRanges,
 		timeLastSaved:    time.Now(),
 		tablePlans:       make(map[string]*TablePlan),
 		phase:            phase,
@@ -181,6 +209,8 @@ func newVPlayer(vr *vreplicator, settings binlogplayer.VRSettings, copyState map
 		query:            queryFunc,
 		commit:           commitFunc,
 		batchMode:        batchMode,
+		internalFields:   make(map[string][]*querypb.Field),
+		conflictTables:   make(map[string]bool),
 	}
 }
 
@@ -274,6 +304,11 @@ func (vp *vplayer) fetchAndApply(ctx context.Context) (err error) {
 		vstreamOptions := &binlogdatapb.VStreamOptions{
 			ConfigOverrides: vp.vr.workflowConfig.Overrides,
 		}
+		if vp.vr.source.Bidirectional != nil {
+			// The updates of the peer workflow streams mark the transactions
+			// it applied.
+			vstreamOptions.InternalTables = []string{vreplicationTableName}
+		}
 		streamErr <- vp.vr.sourceVStreamer.VStream(ctx, replication.EncodePosition(vp.startPos), nil,
 			vp.replicatorPlan.VStreamFilter, func(events []*binlogdatapb.VEvent) error {
 				return relay.Send(events)
@@ -369,16 +404,43 @@ func (vp *vplayer) applyRowEvent(ctx context.Context, rowEvent *binlogdatapb.Row
 		// If we're done with the copy phase then we will be replicating all INSERTS
 		// regardless of the PK value and can use a single INSERT statment with
 		// multiple VALUES clauses.
-		if len(vp.copyState) == 0 && (rowEvent.RowChanges[0].Before == nil && rowEvent.RowChanges[0].After != nil) {
+		if len(vp.copyState) == 0 && len(vp.copyRanges) == 0 && (rowEvent.RowChanges[0].Before == nil && rowEvent.RowChanges[0].After != nil) {
 			_, err := tplan.applyBulkInsertChanges(rowEvent.RowChanges, applyFunc, vp.vr.dbClient.maxBatchSize)
 			return err
 		}
 	}
 
+	copyRanges := vp.copyRanges[tplan.TargetName]
 	for _, change := range rowEvent.RowChanges {
+		if copyRanges != nil {
+			var err error
+			if change, err = copyRanges.filterChange(tplan.Fields, change, vp.pos); err != nil {
+				return err
+			}
+			if change == nil {
+				continue
+			}
+		}
+		if vp.vr.source.Bidirectional != nil && len(vp.copyState) == 0 && len(vp.copyRanges) == 0 {
+			var err error
+			if change, err = vp.resolveConflict(ctx, tplan, change); err != nil {
+				return err
+			}
+			if change == nil {
+				continue
+			}
+		}
 		if _, err := tplan.applyChange(change, applyFunc); err != nil {
 			return err
 		}
+		if tplan.MinMax != nil {
+			vstreamOptions := &binlogdatapb.VStreamOptions{
+				ConfigOverrides: vp.vr.workflowConfig.Overrides,
+			}
+			if err := tplan.recomputeMinMax(ctx, change, applyFunc, vp.vr.sourceVStreamer, vstreamOptions); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -658,6 +720,7 @@ func (vp *vplayer) applyEvent(ctx context.Context, event *binlogdatapb.VEvent, m
 		}
 	case binlogdatapb.VEventType_BEGIN:
 		// No-op: begin is called as needed.
+		vp.skipTransaction = false
 	case binlogdatapb.VEventType_COMMIT:
 		if mustSave {
 			if err := vp.vr.dbClient.Begin(); err != nil {
@@ -677,10 +740,15 @@ func (vp *vplayer) applyEvent(ctx context.Context, event *binlogdatapb.VEvent, m
 		if err := vp.commit(); err != nil {
 			return err
 		}
+		vp.transactionMarked = false
 		if posReached {
 			return io.EOF
 		}
 	case binlogdatapb.VEventType_FIELD:
+		if event.FieldEvent.IsInternalTable {
+			vp.internalFields[event.FieldEvent.TableName] = event.FieldEvent.Fields
+			return nil
+		}
 		if err := vp.vr.dbClient.Begin(); err != nil {
 			return err
 		}
@@ -714,10 +782,25 @@ func (vp *vplayer) applyEvent(ctx context.Context, event *binlogdatapb.VEvent, m
 			}
 		}
 	case binlogdatapb.VEventType_ROW:
+		if event.RowEvent.IsInternalTable {
+			if vp.vr.source.Bidirectional != nil && vp.isPeerTransaction(event.RowEvent) {
+				vp.skipTransaction = true
+			}
+			return nil
+		}
+		if vp.skipTransaction {
+			return nil
+		}
 		// This player is configured for row based replication
 		if err := vp.vr.dbClient.Begin(); err != nil {
 			return err
 		}
+		if vp.vr.source.Bidirectional != nil && !vp.transactionMarked {
+			if err := vp.markTransaction(ctx, event.Timestamp); err != nil {
+				return err
+			}
+			vp.transactionMarked = true
+		}
 		if err := vp.applyRowEvent(ctx, event.RowEvent); err != nil {
 			log.Infof("Error applying row event: %s", err.Error())
 			return err
@@ -804,6 +887,52 @@ func (vp *vplayer) applyEvent(ctx context.Context, event *binlogdatapb.VEvent, m
 			if posReached {
 				return io.EOF
 			}
+		case binlogdatapb.OnDDLAction_RECONCILE:
+			statements, err := vp.reconcileDDL(ctx, event.Statement)
+			var driftErr *schemaDriftError
+			if errors.As(err, &driftErr) {
+				// As with STOP, the DDL is skipped so that the workflow
+				// can be restarted once the target table is fixed.
+				if err := vp.vr.dbClient.Begin(); err != nil {
+					return err
+				}
+				if _, err := vp.updatePos(ctx, event.Timestamp); err != nil {
+					return err
+				}
+				if err := vp.vr.setState(binlogdatapb.VReplicationWorkflowState_Stopped, fmt.Sprintf("Stopped at DDL %s: %v", event.Statement, driftErr)); err != nil {
+					return err
+				}
+				if err := vp.commit(); err != nil {
+					return err
+				}
+				return io.EOF
+			}
+			if err != nil {
+				return err
+			}
+			// As with EXEC, the statements are applied before saving the
+			// position.
+			for _, statement := range statements {
+				if _, err := vp.query(ctx, statement); err != nil {
+					return err
+				}
+				if stats != nil {
+					stats.Send(statement)
+				}
+			}
+			if len(statements) > 0 {
+				vp.vr.insertLog(LogDDLReconciled, fmt.Sprintf("Reconciled DDL %s with: %s", event.Statement, strings.Join(statements, "; ")))
+				if err := vp.reloadReplicatorPlan(ctx); err != nil {
+					return err
+				}
+			}
+			posReached, err := vp.updatePos(ctx, event.Timestamp)
+			if err != nil {
+				return err
+			}
+			if posReached {
+				return io.EOF
+			}
 		}
 	case binlogdatapb.VEventType_JOURNAL:
 		if vp.vr.dbClient.InTransaction {
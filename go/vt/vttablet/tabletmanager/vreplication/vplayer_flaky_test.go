@@ -1521,6 +1521,75 @@ func TestPlayerRowMove(t *testing.T) {
 	validateQueryCountStat(t, "replicate", 3)
 }
 
+// TestPlayerMinMaxBatching tests that the transactions of a workflow with min
+// and max aggregates are not batched, even when batching is enabled, as the
+// aggregates are recomputed by reading the target row after the row change.
+func TestPlayerMinMaxBatching(t *testing.T) {
+	oldVreplicationExperimentalFlags := vttablet.DefaultVReplicationConfig.ExperimentalFlags
+	vttablet.DefaultVReplicationConfig.ExperimentalFlags = vttablet.VReplicationExperimentalFlagVPlayerBatching
+	defer func() {
+		vttablet.DefaultVReplicationConfig.ExperimentalFlags = oldVreplicationExperimentalFlags
+	}()
+
+	defer deleteTablet(addTablet(100))
+	execStatements(t, []string{
+		"create table src(id int, val1 int, val2 int, primary key(id))",
+		fmt.Sprintf("create table %s.dst(val1 int, mn int, mx int, rcount int, primary key(val1))", vrepldb),
+	})
+	defer execStatements(t, []string{
+		"drop table src",
+		fmt.Sprintf("drop table %s.dst", vrepldb),
+	})
+
+	filter := &binlogdatapb.Filter{
+		Rules: []*binlogdatapb.Rule{{
+			Match:  "dst",
+			Filter: "select val1, min(val2) as mn, max(val2) as mx, count(*) as rcount from src group by val1",
+		}},
+	}
+	bls := &binlogdatapb.BinlogSource{
+		Keyspace: env.KeyspaceName,
+		Shard:    env.ShardName,
+		Filter:   filter,
+		OnDdl:    binlogdatapb.OnDDLAction_IGNORE,
+	}
+	cancel, _ := startVReplication(t, bls, "")
+	defer cancel()
+
+	// The statements are sent one at a time rather than in a single batch.
+	execStatements(t, []string{
+		"insert into src values(1, 1, 1), (2, 1, 5), (3, 1, 3)",
+	})
+	expectDBClientQueries(t, qh.Expect(
+		"begin",
+		"/insert into dst",
+		"/insert into dst",
+		"/insert into dst",
+		"/update _vt.vreplication set pos=",
+		"commit",
+	))
+	expectData(t, "dst", [][]string{
+		{"1", "1", "5", "3"},
+	})
+
+	// Deleting the row holding the max recomputes it from the remaining rows,
+	// once the row has been removed from the group.
+	execStatements(t, []string{
+		"delete from src where id=2",
+	})
+	expectDBClientQueries(t, qh.Expect(
+		"begin",
+		"/update dst set .*rcount=rcount-1 where val1=1",
+		"/select 1 from dst where val1=1 and",
+		"update dst set mn=1, mx=3 where val1=1",
+		"/update _vt.vreplication set pos=",
+		"commit",
+	))
+	expectData(t, "dst", [][]string{
+		{"1", "1", "3", "2"},
+	})
+}
+
 // TestPlayerPartialImages tests the behavior of the vplayer when modifying
 // a table with BLOB and JSON columns, including when modifying the Primary
 // Key for a row, when we have partial binlog images, meaning that
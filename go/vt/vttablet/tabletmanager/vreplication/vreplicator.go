@@ -185,8 +185,15 @@ func newVReplicator(id int32, source *binlogdatapb.BinlogSource, sourceVStreamer
 // row representation of a read (during copy) and a binlog event are identical.
 // However, there are some subtle differences, explained in the plan builder
 // code.
+// The streams of an Export workflow, whose source has a sink, do not go
+// through these phases: their rows are exported to the sink instead.
 func (vr *vreplicator) Replicate(ctx context.Context) error {
-	err := vr.replicate(ctx)
+	var err error
+	if vr.source.Sink != nil {
+		err = vr.export(ctx)
+	} else {
+		err = vr.replicate(ctx)
+	}
 	if err == nil {
 		return nil
 	}
@@ -338,7 +345,7 @@ func (vr *vreplicator) replicate(ctx context.Context) error {
 				vr.stats.ErrorCounts.Add([]string{"Replicate"}, 1)
 				return err
 			}
-			return newVPlayer(vr, settings, nil, replication.Position{}, "replicate").play(ctx)
+			return newVPlayer(vr, settings, nil, nil, replication.Position{}, "replicate").play(ctx)
 		}
 	}
 }
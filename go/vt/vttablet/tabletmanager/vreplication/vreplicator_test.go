@@ -861,7 +861,7 @@ func TestThrottlerAppNames(t *testing.T) {
 	assert.NotContains(t, throttlerAppName, "vcopier")
 	assert.NotContains(t, throttlerAppName, "vplayer")
 
-	vp := newVPlayer(vr, settings, nil, replication.Position{}, "")
+	vp := newVPlayer(vr, settings, nil, nil, replication.Position{}, "")
 	assert.Contains(t, vp.throttlerAppName, "test_workflow")
 	assert.Contains(t, vp.throttlerAppName, "vreplication")
 	assert.Contains(t, vp.throttlerAppName, "vplayer")
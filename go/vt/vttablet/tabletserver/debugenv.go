@@ -29,6 +29,7 @@ import (
 
 	"vitess.io/vitess/go/acl"
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl"
 )
 
 var (
@@ -163,6 +164,14 @@ func handlePost(tsv *TabletServer, w http.ResponseWriter, r *http.Request) {
 		err = setDurationVal(func(d time.Duration) { tsv.Config().Healthcheck.UnhealthyThreshold = d })
 	case "ThrottleMetricThreshold":
 		err = setFloat64Val(tsv.SetThrottleMetricThreshold)
+	case "BackupReadRateLimit":
+		err = setInt64Val(mysqlctl.SetBackupReadRateLimit)
+	case "BackupWriteRateLimit":
+		err = setInt64Val(mysqlctl.SetBackupWriteRateLimit)
+	case "RestoreReadRateLimit":
+		err = setInt64Val(mysqlctl.SetRestoreReadRateLimit)
+	case "RestoreWriteRateLimit":
+		err = setInt64Val(mysqlctl.SetRestoreWriteRateLimit)
 	case "Consolidator":
 		tsv.SetConsolidatorMode(value)
 		msg = fmt.Sprintf("Setting %v to: %v", varname, value)
@@ -202,6 +211,10 @@ func getVars(tsv *TabletServer) []envValue {
 	vars = addVar(vars, "RowStreamerMaxMySQLReplLagSecs", func() int64 { return tsv.Config().RowStreamer.MaxMySQLReplLagSecs })
 	vars = addVar(vars, "UnhealthyThreshold", func() time.Duration { return tsv.Config().Healthcheck.UnhealthyThreshold })
 	vars = addVar(vars, "ThrottleMetricThreshold", tsv.ThrottleMetricThreshold)
+	vars = addVar(vars, "BackupReadRateLimit", mysqlctl.GetBackupReadRateLimit)
+	vars = addVar(vars, "BackupWriteRateLimit", mysqlctl.GetBackupWriteRateLimit)
+	vars = addVar(vars, "RestoreReadRateLimit", mysqlctl.GetRestoreReadRateLimit)
+	vars = addVar(vars, "RestoreWriteRateLimit", mysqlctl.GetRestoreWriteRateLimit)
 	vars = append(vars, envValue{
 		Name:  "Consolidator",
 		Value: tsv.ConsolidatorMode(),
@@ -72,6 +72,7 @@ const (
 	RowStreamerName       Name = "rowstreamer"
 	ExternalConnectorName Name = "external-connector"
 	ReplicaConnectorName  Name = "replica-connector"
+	VDiffRepairName       Name = "vdiff-repair"
 
 	BinlogWatcherName Name = "binlog-watcher"
 	MessagerName      Name = "messager"
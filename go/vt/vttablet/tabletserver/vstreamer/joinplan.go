@@ -0,0 +1,506 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamer
+
+import (
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// joinLookupMaxRows is the maximum number of joined rows that can be
+// looked up for the row changes of a single binlog event.
+const joinLookupMaxRows = 1000000
+
+// joinPlan is the plan for a filter whose FROM clause is an inner join of
+// tables in the source keyspace, as used by Materialize workflows that
+// maintain a denormalized table.
+//
+// During the copy phase the rowstreamer runs the join as a whole, ordered
+// by the primary keys of all the joined tables. In the binlog, a change to a
+// row of any joined table can affect many joined rows. For every primary key
+// found in the before and after images of a row event, the vstreamer sends
+// a delete of the joined rows containing that key, followed by the joined
+// rows for that key as currently found in MySQL. Those rows are at least as
+// recent as the event, and the rows of any later change are sent again with
+// that change, so applying the events in order converges on the join result.
+type joinPlan struct {
+	// sel is the query that is sent to MySQL. The in_keyrange constructs
+	// of the filter are removed from its WHERE clause, and the columns they
+	// reference are appended to its select list.
+	sel *sqlparser.Select
+
+	// tables are the joined tables in FROM clause order.
+	tables []*joinTable
+
+	// output applies the in_keyrange constructs of the filter to the rows
+	// returned by sel and projects the columns requested by the filter.
+	// Its Table describes the columns of sel.
+	output *Plan
+}
+
+// joinTable is a table of a joinPlan.
+type joinTable struct {
+	name      string
+	qualifier sqlparser.TableName
+
+	// pkExprs are the primary key columns of the table as referenced by the
+	// filter, and pkColumns are their positions in the select list.
+	pkExprs   []*sqlparser.ColName
+	pkColumns []int
+}
+
+// joinSide is the part of a join plan that handles the binlog events of
+// one of the joined tables.
+type joinSide struct {
+	*joinPlan
+	table *joinTable
+
+	// pkColumns are the positions of the primary key columns of the table
+	// in its row images.
+	pkColumns []int
+}
+
+// isJoin returns true if the FROM clause of sel joins multiple tables.
+func isJoin(sel *sqlparser.Select) bool {
+	if len(sel.From) > 1 {
+		return true
+	}
+	_, ok := sel.From[0].(*sqlparser.AliasedTableExpr)
+	return !ok
+}
+
+// buildJoinPlan builds a joinPlan for the query. It returns nil if the query
+// is not a select from a join.
+func buildJoinPlan(env *vtenv.Environment, vschema *localVSchema, query string) (*joinPlan, error) {
+	statement, err := env.Parser().Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := statement.(*sqlparser.Select)
+	if !ok || len(sel.From) == 0 || !isJoin(sel) {
+		return nil, nil
+	}
+	if sel.Distinct || sel.GroupBy != nil || sel.Having != nil || sel.OrderBy != nil || sel.Limit != nil || sel.With != nil ||
+		sqlparser.ContainsAggregation(sel.SelectExprs) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: join with distinct, aggregation, grouping, ordering or limit: %v", sqlparser.String(sel))
+	}
+	jp := &joinPlan{
+		sel: &sqlparser.Select{
+			From:        sel.From,
+			SelectExprs: &sqlparser.SelectExprs{},
+		},
+		output: &Plan{
+			env:   env,
+			Table: &Table{},
+		},
+	}
+	if err := jp.analyzeTableExprs(sel.From); err != nil {
+		return nil, err
+	}
+	jp.output.Table.Name = jp.tables[0].name
+
+	names := make(map[string]bool)
+	for _, expr := range sel.GetColumns() {
+		aliased, ok := expr.(*sqlparser.AliasedExpr)
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: %v in a join, the columns must be listed", sqlparser.String(expr))
+		}
+		name := aliased.As
+		if name.IsEmpty() {
+			col, ok := aliased.Expr.(*sqlparser.ColName)
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: expression %v in a join must have an alias", sqlparser.String(aliased.Expr))
+			}
+			name = col.Name
+		}
+		if names[name.Lowered()] {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate column %v in join", name)
+		}
+		names[name.Lowered()] = true
+		jp.addColumn(aliased.Expr, name)
+	}
+	jp.output.ColExprs = make([]ColExpr, len(jp.output.Table.Fields))
+	for i, field := range jp.output.Table.Fields {
+		jp.output.ColExprs[i] = ColExpr{ColNum: i, Field: field}
+	}
+
+	if sel.Where == nil {
+		return jp, nil
+	}
+	var conditions []sqlparser.Expr
+	for _, expr := range sqlparser.SplitAndExpression(nil, sel.Where.Expr) {
+		funcExpr, ok := expr.(*sqlparser.FuncExpr)
+		if !ok || !funcExpr.Name.EqualString("in_keyrange") {
+			conditions = append(conditions, expr)
+			continue
+		}
+		if err := jp.analyzeInKeyRange(vschema, funcExpr.Exprs); err != nil {
+			return nil, err
+		}
+	}
+	if len(conditions) > 0 {
+		jp.sel.Where = sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.AndExpressions(conditions...))
+	}
+	return jp, nil
+}
+
+// analyzeTableExprs adds the tables of an inner join to the plan.
+func (jp *joinPlan) analyzeTableExprs(exprs []sqlparser.TableExpr) error {
+	for _, expr := range exprs {
+		switch expr := expr.(type) {
+		case *sqlparser.AliasedTableExpr:
+			tableName, ok := expr.Expr.(sqlparser.TableName)
+			if !ok || !tableName.Qualifier.IsEmpty() {
+				return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: %v in a join, only tables of the source keyspace can be joined", sqlparser.String(expr))
+			}
+			qualifier := tableName
+			if !expr.As.IsEmpty() {
+				qualifier = sqlparser.TableName{Name: expr.As}
+			}
+			if jp.findTable(tableName.Name.String()) != nil {
+				return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: table %v is joined more than once", tableName.Name)
+			}
+			jp.tables = append(jp.tables, &joinTable{
+				name:      tableName.Name.String(),
+				qualifier: qualifier,
+			})
+		case *sqlparser.JoinTableExpr:
+			if expr.Join != sqlparser.NormalJoinType && expr.Join != sqlparser.StraightJoinType {
+				return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: %s, only inner joins are supported", expr.Join.ToString())
+			}
+			if err := jp.analyzeTableExprs([]sqlparser.TableExpr{expr.LeftExpr, expr.RightExpr}); err != nil {
+				return err
+			}
+		case *sqlparser.ParenTableExpr:
+			if err := jp.analyzeTableExprs(expr.Exprs); err != nil {
+				return err
+			}
+		default:
+			return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: %v in a join", sqlparser.String(expr))
+		}
+	}
+	return nil
+}
+
+// analyzeInKeyRange adds the columns of an in_keyrange construct to the
+// select list as hidden columns, and adds its filter to the output plan.
+func (jp *joinPlan) analyzeInKeyRange(vschema *localVSchema, exprs []sqlparser.Expr) error {
+	if len(exprs) < 3 {
+		return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: in_keyrange in a join must specify the columns, the vindex and the key range")
+	}
+	args := make([]sqlparser.Expr, 0, len(exprs))
+	for _, expr := range exprs[:len(exprs)-2] {
+		col, ok := expr.(*sqlparser.ColName)
+		if !ok {
+			return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: in_keyrange argument %v in a join", sqlparser.String(expr))
+		}
+		name := sqlparser.NewIdentifierCI(fmt.Sprintf("vt_keyrange_%d", len(jp.output.Table.Fields)))
+		jp.addColumn(col, name)
+		args = append(args, &sqlparser.ColName{Name: name})
+	}
+	args = append(args, exprs[len(exprs)-2:]...)
+	return jp.output.analyzeInKeyRange(vschema, args)
+}
+
+// addColumn adds an expression to the select list of the query sent to MySQL.
+func (jp *joinPlan) addColumn(expr sqlparser.Expr, name sqlparser.IdentifierCI) {
+	selExpr := &sqlparser.AliasedExpr{Expr: expr}
+	if col, ok := expr.(*sqlparser.ColName); !ok || !col.Name.Equal(name) {
+		selExpr.As = name
+	}
+	jp.sel.AddSelectExpr(selExpr)
+	jp.output.Table.Fields = append(jp.output.Table.Fields, &querypb.Field{Name: name.String()})
+}
+
+func (jp *joinPlan) findTable(name string) *joinTable {
+	for _, table := range jp.tables {
+		if table.name == name {
+			return table
+		}
+	}
+	return nil
+}
+
+// tableNames returns the names of the joined tables.
+func (jp *joinPlan) tableNames() []string {
+	names := make([]string, 0, len(jp.tables))
+	for _, table := range jp.tables {
+		names = append(names, table.name)
+	}
+	return names
+}
+
+// setPrimaryKey records the primary key columns of a joined table. They must
+// all be selected by the filter because the target deletes and copies the
+// joined rows by the primary keys of the joined tables.
+func (jp *joinPlan) setPrimaryKey(tableName string, pkColumns []string) error {
+	table := jp.findTable(tableName)
+	if table == nil {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "table %s is not part of the join", tableName)
+	}
+	if len(pkColumns) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported: joined table %s has no primary key", tableName)
+	}
+	table.pkExprs = nil
+	table.pkColumns = nil
+	for _, pkColumn := range pkColumns {
+		pos := -1
+		for i, colExpr := range jp.output.ColExprs {
+			col, ok := jp.sel.SelectExprs.Exprs[colExpr.ColNum].(*sqlparser.AliasedExpr).Expr.(*sqlparser.ColName)
+			if ok && col.Name.EqualString(pkColumn) && col.Qualifier.Name.String() == table.qualifier.Name.String() {
+				pos = i
+				break
+			}
+		}
+		if pos == -1 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the join must select the primary key column %s of table %s as %v.%s",
+				pkColumn, tableName, table.qualifier.Name, pkColumn)
+		}
+		table.pkExprs = append(table.pkExprs, jp.sel.SelectExprs.Exprs[pos].(*sqlparser.AliasedExpr).Expr.(*sqlparser.ColName))
+		table.pkColumns = append(table.pkColumns, pos)
+	}
+	return nil
+}
+
+// pkColumns returns the positions in the select list of the primary key
+// columns of all the joined tables.
+func (jp *joinPlan) pkColumns() []int {
+	var pkColumns []int
+	for _, table := range jp.tables {
+		pkColumns = append(pkColumns, table.pkColumns...)
+	}
+	return pkColumns
+}
+
+// setFields sets the fields returned by MySQL for the select list.
+func (jp *joinPlan) setFields(fields []*querypb.Field) error {
+	if len(fields) != len(jp.output.Table.Fields) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "expected %d fields for the join, got %d", len(jp.output.Table.Fields), len(fields))
+	}
+	for i, field := range fields {
+		field = field.CloneVT()
+		field.Name = jp.output.Table.Fields[i].Name
+		jp.output.Table.Fields[i] = field
+	}
+	for i := range jp.output.ColExprs {
+		jp.output.ColExprs[i].Field = jp.output.Table.Fields[jp.output.ColExprs[i].ColNum]
+	}
+	return nil
+}
+
+// charsets returns the collations of the columns of the select list.
+func (jp *joinPlan) charsets() []collations.ID {
+	charsets := make([]collations.ID, len(jp.output.Table.Fields))
+	for i, field := range jp.output.Table.Fields {
+		charsets[i] = collations.ID(field.Charset)
+	}
+	return charsets
+}
+
+// fieldsQuery returns a query that returns the fields of the join and no rows.
+func (jp *joinPlan) fieldsQuery() string {
+	sel := sqlparser.CloneRefOfSelect(jp.sel)
+	sel.AddWhere(sqlparser.NewComparisonExpr(sqlparser.NotEqualOp, sqlparser.NewIntLiteral("1"), sqlparser.NewIntLiteral("1"), nil))
+	return sqlparser.String(sel)
+}
+
+// copyQuery returns the query that streams the joined rows after lastpk, in
+// the order of the primary keys of the joined tables.
+func (jp *joinPlan) copyQuery(lastpk []sqltypes.Value, comments string) (string, error) {
+	sel := sqlparser.CloneRefOfSelect(jp.sel)
+	if comments != "" {
+		sel.Comments = sqlparser.Comments{comments}.Parsed()
+	}
+	var pkExprs []sqlparser.Expr
+	for _, table := range jp.tables {
+		for _, pkExpr := range table.pkExprs {
+			pkExprs = append(pkExprs, pkExpr)
+		}
+	}
+	bindVars := make(map[string]*querypb.BindVariable, len(lastpk))
+	if len(lastpk) != 0 {
+		if len(lastpk) != len(pkExprs) {
+			return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the lastpk value %v does not match the %d primary key columns of the join", lastpk, len(pkExprs))
+		}
+		// As for a single table, (col1 = 1 and col2 > 2) or (col1 > 1) is
+		// used instead of (col1, col2) > (1, 2).
+		var cond sqlparser.Expr
+		for lastcol := len(pkExprs) - 1; lastcol >= 0; lastcol-- {
+			var conjuncts []sqlparser.Expr
+			for i := 0; i <= lastcol; i++ {
+				name := fmt.Sprintf("lastpk_%d", i)
+				bindVars[name] = sqltypes.ValueBindVariable(lastpk[i])
+				op := sqlparser.EqualOp
+				if i == lastcol {
+					op = sqlparser.GreaterThanOp
+				}
+				conjuncts = append(conjuncts, sqlparser.NewComparisonExpr(op, pkExprs[i], sqlparser.NewArgument(name), nil))
+			}
+			if cond == nil {
+				cond = sqlparser.AndExpressions(conjuncts...)
+			} else {
+				cond = &sqlparser.OrExpr{Left: cond, Right: sqlparser.AndExpressions(conjuncts...)}
+			}
+		}
+		sel.AddWhere(cond)
+	}
+	for _, pkExpr := range pkExprs {
+		sel.AddOrder(sqlparser.NewOrder(pkExpr, sqlparser.AscOrder))
+	}
+	return sqlparser.NewParsedQuery(sel).GenerateQuery(bindVars, nil)
+}
+
+// lookupQuery returns the query that fetches the joined rows for the
+// primary keys of the table.
+func (jp *joinPlan) lookupQuery(table *joinTable, keys [][]sqltypes.Value) (string, error) {
+	sel := sqlparser.CloneRefOfSelect(jp.sel)
+	left := make(sqlparser.ValTuple, 0, len(table.pkExprs))
+	for _, pkExpr := range table.pkExprs {
+		left = append(left, pkExpr)
+	}
+	bindVars := make(map[string]*querypb.BindVariable, len(keys)*len(table.pkExprs))
+	right := make(sqlparser.ValTuple, 0, len(keys))
+	for i, key := range keys {
+		tuple := make(sqlparser.ValTuple, 0, len(key))
+		for j, value := range key {
+			name := fmt.Sprintf("key_%d_%d", i, j)
+			bindVars[name] = sqltypes.ValueBindVariable(value)
+			tuple = append(tuple, sqlparser.NewArgument(name))
+		}
+		right = append(right, tuple)
+	}
+	sel.AddWhere(sqlparser.NewComparisonExpr(sqlparser.InOp, left, right, nil))
+	return sqlparser.NewParsedQuery(sel).GenerateQuery(bindVars, nil)
+}
+
+// fields returns the fields of the join with only the given primary key
+// columns flagged as such. The target deletes joined rows by those columns.
+func (jp *joinPlan) fields(pkColumns []int) []*querypb.Field {
+	fields := jp.output.fields()
+	for _, field := range fields {
+		field.Flags &^= uint32(querypb.MySqlFlag_PRI_KEY_FLAG)
+	}
+	for _, pos := range pkColumns {
+		fields[pos].Flags |= uint32(querypb.MySqlFlag_PRI_KEY_FLAG)
+	}
+	return fields
+}
+
+// fields returns the fields of the join as sent in the FIELD event of the
+// table. Only the primary key columns of the table are flagged as such: the
+// target uses them to delete the joined rows of a changed row.
+func (js *joinSide) fields() []*querypb.Field {
+	return js.joinPlan.fields(js.table.pkColumns)
+}
+
+// keys returns the distinct primary keys of the given row images.
+func (js *joinSide) keys(images [][]sqltypes.Value) [][]sqltypes.Value {
+	var keys [][]sqltypes.Value
+	seen := make(map[string]bool)
+	for _, image := range images {
+		key := make([]sqltypes.Value, len(js.pkColumns))
+		var buf strings.Builder
+		for i, pos := range js.pkColumns {
+			key[i] = image[pos]
+			key[i].EncodeSQL(&buf)
+			buf.WriteByte(',')
+		}
+		if seen[buf.String()] {
+			continue
+		}
+		seen[buf.String()] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// keyRow returns a joined row that only holds the primary key of the table.
+// It is sent as the before image of a delete of the joined rows of that key.
+func (js *joinSide) keyRow(key []sqltypes.Value) []sqltypes.Value {
+	row := make([]sqltypes.Value, len(js.output.ColExprs))
+	for i, pos := range js.table.pkColumns {
+		row[pos] = key[i]
+	}
+	return row
+}
+
+// rowChanges returns the row changes that replace the joined rows of the
+// given primary keys with the rows returned by the lookup.
+func (js *joinSide) rowChanges(keys [][]sqltypes.Value, lookup *sqltypes.Result) ([]*binlogdatapb.RowChange, error) {
+	rowChanges := make([]*binlogdatapb.RowChange, 0, len(keys)+len(lookup.Rows))
+	for _, key := range keys {
+		rowChanges = append(rowChanges, &binlogdatapb.RowChange{Before: sqltypes.RowToProto3(js.keyRow(key))})
+	}
+	charsets := js.charsets()
+	filtered := make([]sqltypes.Value, len(js.output.ColExprs))
+	for _, row := range lookup.Rows {
+		ok, err := js.output.filter(row, filtered, charsets)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			rowChanges = append(rowChanges, &binlogdatapb.RowChange{After: sqltypes.RowToProto3(filtered)})
+		}
+	}
+	return rowChanges, nil
+}
+
+// buildJoinTablePlan builds the plan of a table of a join. The plan passes
+// the row images of the table through unchanged: the joined rows are looked
+// up in MySQL by the vstreamer.
+func buildJoinTablePlan(env *vtenv.Environment, ti *Table, jp *joinPlan) (*Plan, error) {
+	table := jp.findTable(ti.Name)
+	if table == nil {
+		return nil, fmt.Errorf("unsupported: select expression tables %v do not include the table entry name %s", jp.tableNames(), ti.Name)
+	}
+	plan := &Plan{
+		Table: ti,
+		env:   env,
+		join: &joinSide{
+			joinPlan: jp,
+			table:    table,
+		},
+	}
+	plan.ColExprs = make([]ColExpr, len(ti.Fields))
+	for i, col := range ti.Fields {
+		plan.ColExprs[i].ColNum = i
+		plan.ColExprs[i].Field = col
+	}
+	return plan, nil
+}
+
+// initJoinLookupConn prepares a connection used to look up joined rows so
+// that values are returned the same way as in the binlog.
+func initJoinLookupConn(conn *mysql.Conn) error {
+	for _, query := range []string{"set names 'binary'", "set @@session.time_zone = '+00:00'"} {
+		if _, err := conn.ExecuteFetch(query, 1, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
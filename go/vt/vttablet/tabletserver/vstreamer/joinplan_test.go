@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestBuildJoinPlan(t *testing.T) {
+	testcases := []struct {
+		query   string
+		sel     string
+		columns []string
+		tables  []string
+		filters int
+		err     string
+	}{{
+		query: "select id from t1",
+	}, {
+		query:   "select o.id as order_id, c.id as customer_id, c.name, o.total from orders as o join customers as c on o.customer_id = c.id where o.total > 10",
+		sel:     "select o.id as order_id, c.id as customer_id, c.`name`, o.total from orders as o join customers as c on o.customer_id = c.id where o.total > 10",
+		columns: []string{"order_id", "customer_id", "name", "total"},
+		tables:  []string{"orders", "customers"},
+	}, {
+		query:   "select orders.id, customers.id as cid from orders, customers where orders.customer_id = customers.id and in_keyrange(customers.id, 'hash', '-80')",
+		sel:     "select orders.id, customers.id as cid, customers.id as vt_keyrange_2 from orders, customers where orders.customer_id = customers.id",
+		columns: []string{"id", "cid", "vt_keyrange_2"},
+		tables:  []string{"orders", "customers"},
+		filters: 1,
+	}, {
+		query: "select * from orders join customers on orders.customer_id = customers.id",
+		err:   "unsupported: * in a join, the columns must be listed",
+	}, {
+		query: "select o.id, c.id from orders as o join customers as c on o.customer_id = c.id",
+		err:   "duplicate column id in join",
+	}, {
+		query: "select o.id, o.total + 1 from orders as o join customers as c on o.customer_id = c.id",
+		err:   "unsupported: expression o.total + 1 in a join must have an alias",
+	}, {
+		query: "select o.id, c.id as cid from orders as o left join customers as c on o.customer_id = c.id",
+		err:   "unsupported: left join, only inner joins are supported",
+	}, {
+		query: "select a.id, b.id as bid from orders as a join orders as b on a.id = b.parent_id",
+		err:   "unsupported: table orders is joined more than once",
+	}, {
+		query: "select o.id, c.id as cid from orders as o join (select id from customers) as c on o.customer_id = c.id",
+		err:   "only tables of the source keyspace can be joined",
+	}, {
+		query: "select c.id, count(*) as cnt from orders as o join customers as c on o.customer_id = c.id group by c.id",
+		err:   "unsupported: join with distinct, aggregation, grouping, ordering or limit",
+	}, {
+		query: "select o.id, c.id as cid from orders as o join customers as c on o.customer_id = c.id where in_keyrange('-80')",
+		err:   "unsupported: in_keyrange in a join must specify the columns, the vindex and the key range",
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			jp, err := buildJoinPlan(vtenv.NewTestEnv(), testLocalVSchema, tcase.query)
+			if tcase.err != "" {
+				require.ErrorContains(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			if tcase.sel == "" {
+				require.Nil(t, jp)
+				return
+			}
+			require.NotNil(t, jp)
+			assert.Equal(t, tcase.sel, sqlparser.String(jp.sel))
+			var columns []string
+			for _, field := range jp.output.Table.Fields {
+				columns = append(columns, field.Name)
+			}
+			assert.Equal(t, tcase.columns, columns)
+			assert.Equal(t, tcase.tables, jp.tableNames())
+			assert.Len(t, jp.output.Filters, tcase.filters)
+		})
+	}
+}
+
+func TestJoinPlanQueries(t *testing.T) {
+	jp, err := buildJoinPlan(vtenv.NewTestEnv(), testLocalVSchema,
+		"select o.id as order_id, o.line, c.id as customer_id, c.name from orders as o join customers as c on o.customer_id = c.id")
+	require.NoError(t, err)
+
+	err = jp.setPrimaryKey("orders", []string{"id", "region"})
+	require.ErrorContains(t, err, "the join must select the primary key column region of table orders as o.region")
+	require.NoError(t, jp.setPrimaryKey("orders", []string{"id", "line"}))
+	require.NoError(t, jp.setPrimaryKey("customers", []string{"id"}))
+	assert.Equal(t, []int{0, 1, 2}, jp.pkColumns())
+
+	assert.Equal(t, "select o.id as order_id, o.line, c.id as customer_id, c.`name` from orders as o join customers as c on o.customer_id = c.id where 1 != 1",
+		jp.fieldsQuery())
+
+	query, err := jp.copyQuery(nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, "select o.id as order_id, o.line, c.id as customer_id, c.`name` from orders as o join customers as c on o.customer_id = c.id order by o.id asc, o.line asc, c.id asc",
+		query)
+
+	query, err = jp.copyQuery([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2), sqltypes.NewInt64(3)}, "/*+ MAX_EXECUTION_TIME(3600000) */")
+	require.NoError(t, err)
+	assert.Equal(t, "select /*+ MAX_EXECUTION_TIME(3600000) */ o.id as order_id, o.line, c.id as customer_id, c.`name` from orders as o join customers as c on o.customer_id = c.id "+
+		"where o.id = 1 and o.line = 2 and c.id > 3 or o.id = 1 and o.line > 2 or o.id > 1 order by o.id asc, o.line asc, c.id asc",
+		query)
+
+	_, err = jp.copyQuery([]sqltypes.Value{sqltypes.NewInt64(1)}, "")
+	require.ErrorContains(t, err, "does not match the 3 primary key columns of the join")
+
+	query, err = jp.lookupQuery(jp.findTable("orders"), [][]sqltypes.Value{
+		{sqltypes.NewInt64(1), sqltypes.NewInt64(2)},
+		{sqltypes.NewInt64(3), sqltypes.NewInt64(4)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "select o.id as order_id, o.line, c.id as customer_id, c.`name` from orders as o join customers as c on o.customer_id = c.id where (o.id, o.line) in ((1, 2), (3, 4))",
+		query)
+}
+
+func TestJoinSide(t *testing.T) {
+	jp, err := buildJoinPlan(vtenv.NewTestEnv(), testLocalVSchema,
+		"select o.id as order_id, c.id as customer_id, c.name from orders as o join customers as c on o.customer_id = c.id where in_keyrange(c.id, 'hash', '-80')")
+	require.NoError(t, err)
+	require.NoError(t, jp.setPrimaryKey("orders", []string{"id"}))
+	require.NoError(t, jp.setPrimaryKey("customers", []string{"id"}))
+	require.NoError(t, jp.setFields([]*querypb.Field{
+		{Name: "order_id", Type: sqltypes.Int64, Flags: uint32(querypb.MySqlFlag_PRI_KEY_FLAG)},
+		{Name: "customer_id", Type: sqltypes.Int64, Flags: uint32(querypb.MySqlFlag_PRI_KEY_FLAG)},
+		{Name: "name", Type: sqltypes.VarChar},
+		{Name: "customer_id", Type: sqltypes.Int64},
+	}))
+
+	ti := &Table{
+		Name: "customers",
+		Fields: []*querypb.Field{
+			{Name: "name", Type: sqltypes.VarChar},
+			{Name: "id", Type: sqltypes.Int64},
+		},
+	}
+	plan, err := buildJoinTablePlan(vtenv.NewTestEnv(), ti, jp)
+	require.NoError(t, err)
+	side := plan.join
+	side.pkColumns = []int{1}
+
+	fields := plan.fields()
+	require.Len(t, fields, 3)
+	assert.Equal(t, "vt_keyrange_3", jp.output.Table.Fields[3].Name)
+	assert.Zero(t, fields[0].Flags&uint32(querypb.MySqlFlag_PRI_KEY_FLAG))
+	assert.NotZero(t, fields[1].Flags&uint32(querypb.MySqlFlag_PRI_KEY_FLAG))
+	assert.Zero(t, fields[2].Flags&uint32(querypb.MySqlFlag_PRI_KEY_FLAG))
+
+	// The key of an update that did not change the primary key is only sent once.
+	keys := side.keys([][]sqltypes.Value{
+		{sqltypes.NewVarChar("a"), sqltypes.NewInt64(1)},
+		{sqltypes.NewVarChar("b"), sqltypes.NewInt64(1)},
+		{sqltypes.NewVarChar("c"), sqltypes.NewInt64(4)},
+	})
+	assert.Equal(t, [][]sqltypes.Value{{sqltypes.NewInt64(1)}, {sqltypes.NewInt64(4)}}, keys)
+
+	// hash(1) is in -80 and hash(4) is in 80-.
+	lookup := sqltypes.MakeTestResult(sqltypes.MakeTestFields("order_id|customer_id|name|vt_keyrange_3", "int64|int64|varchar|int64"),
+		"10|1|b|1",
+		"11|1|b|1",
+		"12|4|c|4",
+	)
+	rowChanges, err := side.rowChanges(keys, lookup)
+	require.NoError(t, err)
+	require.Len(t, rowChanges, 4)
+	assert.Equal(t, sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NULL, sqltypes.NewInt64(1), sqltypes.NULL}), rowChanges[0].Before)
+	assert.Nil(t, rowChanges[0].After)
+	assert.Equal(t, sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NULL, sqltypes.NewInt64(4), sqltypes.NULL}), rowChanges[1].Before)
+	assert.Nil(t, rowChanges[2].Before)
+	assert.Equal(t, sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(10), sqltypes.NewInt64(1), sqltypes.NewVarChar("b")}), rowChanges[2].After)
+	assert.Equal(t, sqltypes.RowToProto3([]sqltypes.Value{sqltypes.NewInt64(11), sqltypes.NewInt64(1), sqltypes.NewVarChar("b")}), rowChanges[3].After)
+
+	_, err = buildJoinTablePlan(vtenv.NewTestEnv(), &Table{Name: "t1"}, jp)
+	require.ErrorContains(t, err, "do not include the table entry name t1")
+}
@@ -77,6 +77,9 @@ type Plan struct {
 
 	// IsInternal is set to true if the plan is for a sidecar table.
 	IsInternal bool
+
+	// join is set if the table is one of the tables of a join filter.
+	join *joinSide
 }
 
 // Opcode enumerates the operators supported in a where clause
@@ -164,6 +167,9 @@ func (ta *Table) FindColumn(name sqlparser.IdentifierCI) int {
 
 // fields returns the fields for the plan.
 func (plan *Plan) fields() []*querypb.Field {
+	if plan.join != nil {
+		return plan.join.fields()
+	}
 	fields := make([]*querypb.Field, len(plan.ColExprs))
 	for i, ce := range plan.ColExprs {
 		fields[i] = ce.Field.CloneVT()
@@ -487,6 +493,14 @@ func buildREPlan(env *vtenv.Environment, ti *Table, vschema *localVSchema, filte
 // BuildTablePlan handles cases where a specific table name is specified.
 // The filter must be a select statement.
 func buildTablePlan(env *vtenv.Environment, ti *Table, vschema *localVSchema, query string) (*Plan, error) {
+	jp, err := buildJoinPlan(env, vschema, query)
+	if err != nil {
+		log.Errorf("%s", err.Error())
+		return nil, err
+	}
+	if jp != nil {
+		return buildJoinTablePlan(env, ti, jp)
+	}
 	sel, fromTable, err := analyzeSelect(query, env.Parser())
 	if err != nil {
 		log.Errorf("%s", err.Error())
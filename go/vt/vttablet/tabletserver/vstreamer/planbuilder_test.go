@@ -685,11 +685,15 @@ func TestPlanBuilder(t *testing.T) {
 	}, {
 		inTable: t1,
 		inRule:  &binlogdatapb.Rule{Match: "t1", Filter: "select * from t1, t2"},
-		outErr:  `unsupported: select * from t1, t2`,
+		outErr:  `unsupported: * in a join, the columns must be listed`,
 	}, {
 		inTable: t1,
 		inRule:  &binlogdatapb.Rule{Match: "t1", Filter: "select * from t1 join t2"},
-		outErr:  `unsupported: select * from t1 join t2`,
+		outErr:  `unsupported: * in a join, the columns must be listed`,
+	}, {
+		inTable: t1,
+		inRule:  &binlogdatapb.Rule{Match: "t1", Filter: "select t1.id, t2.id as id2 from t1 left join t2 on t1.id = t2.id"},
+		outErr:  `unsupported: left join, only inner joins are supported`,
 	}, {
 		inTable: t1,
 		inRule:  &binlogdatapb.Rule{Match: "t1", Filter: "select * from a.t1"},
@@ -76,7 +76,7 @@ func (rs *resultStreamer) Stream() error {
 		return err
 	}
 	defer conn.Close()
-	gtid, rotatedLog, err := conn.streamWithSnapshot(rs.ctx, rs.tableName.String(), rs.query)
+	gtid, rotatedLog, err := conn.streamWithSnapshot(rs.ctx, []string{rs.tableName.String()}, rs.query)
 	if rotatedLog {
 		rs.vse.vstreamerFlushedBinlogs.Add(1)
 	}
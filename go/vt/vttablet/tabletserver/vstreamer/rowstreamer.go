@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -73,6 +74,7 @@ type rowStreamer struct {
 	vschema *localVSchema
 
 	plan          *Plan
+	join          *joinPlan
 	pkColumns     []int
 	ukColumnNames []string
 	sendQuery     string
@@ -147,6 +149,13 @@ func (rs *rowStreamer) Stream() error {
 }
 
 func (rs *rowStreamer) buildPlan() error {
+	join, err := buildJoinPlan(rs.se.Environment(), rs.vschema, rs.query)
+	if err != nil {
+		return err
+	}
+	if join != nil {
+		return rs.buildJoinPlan(join)
+	}
 	// This pre-parsing is required to extract the table name
 	// and create its metadata.
 	sel, fromTable, err := analyzeSelect(rs.query, rs.se.Environment().Parser())
@@ -201,6 +210,34 @@ func (rs *rowStreamer) buildPlan() error {
 	return err
 }
 
+// buildJoinPlan builds the plan for a filter that joins multiple tables.
+// The joined rows are streamed in the order of the primary keys of the
+// joined tables, which together act as the primary key of the join.
+func (rs *rowStreamer) buildJoinPlan(join *joinPlan) error {
+	for _, table := range join.tables {
+		st, err := rs.se.GetTableForPos(rs.ctx, sqlparser.NewIdentifierCS(table.name), "")
+		if err != nil {
+			return err
+		}
+		var pkNames []string
+		for _, pk := range st.PKColumns {
+			if pk >= int64(len(st.Fields)) {
+				return fmt.Errorf("primary key %d refers to non-existent column", pk)
+			}
+			pkNames = append(pkNames, st.Fields[pk].Name)
+		}
+		if err := join.setPrimaryKey(table.name, pkNames); err != nil {
+			return err
+		}
+	}
+	var err error
+	rs.join = join
+	rs.plan = join.output
+	rs.pkColumns = join.pkColumns()
+	rs.sendQuery, err = join.copyQuery(rs.lastpk, strings.TrimSpace(GetVReplicationMaxExecutionTimeQueryHint(rs.config.CopyPhaseDuration)))
+	return err
+}
+
 // buildPKColumnsFromUniqueKey assumes a unique key is indicated,
 func (rs *rowStreamer) buildPKColumnsFromUniqueKey() ([]int, error) {
 	var pkColumns = make([]int, 0)
@@ -350,7 +387,11 @@ func (rs *rowStreamer) streamQuery(send func(*binlogdatapb.VStreamRowsResponse)
 	)
 	log.Infof("Streaming query: %v\n", rs.sendQuery)
 	if rs.mode == RowStreamerModeSingleTable {
-		gtid, rotatedLog, err = rs.conn.streamWithSnapshot(rs.ctx, rs.plan.Table.Name, rs.sendQuery)
+		tables := []string{rs.plan.Table.Name}
+		if rs.join != nil {
+			tables = rs.join.tableNames()
+		}
+		gtid, rotatedLog, err = rs.conn.streamWithSnapshot(rs.ctx, tables, rs.sendQuery)
 		if err != nil {
 			return err
 		}
@@ -359,7 +400,21 @@ func (rs *rowStreamer) streamQuery(send func(*binlogdatapb.VStreamRowsResponse)
 		}
 	} else {
 		// Comes here when we stream all tables. The snapshot is created just once at the start.
-		if err := rs.conn.ExecuteStreamFetch(rs.query); err != nil {
+		query := rs.query
+		if rs.join != nil {
+			query = rs.sendQuery
+		}
+		if err := rs.conn.ExecuteStreamFetch(query); err != nil {
+			return err
+		}
+	}
+	if rs.join != nil {
+		// The fields of a join are only known once MySQL returns them.
+		fields, err := rs.conn.Fields()
+		if err != nil {
+			return err
+		}
+		if err := rs.join.setFields(fields); err != nil {
 			return err
 		}
 	}
@@ -379,8 +434,12 @@ func (rs *rowStreamer) streamQuery(send func(*binlogdatapb.VStreamRowsResponse)
 		charsets[i] = collations.ID(fld.Charset)
 	}
 
+	fields := rs.plan.fields()
+	if rs.join != nil {
+		fields = rs.join.fields(rs.pkColumns)
+	}
 	err = safeSend(&binlogdatapb.VStreamRowsResponse{
-		Fields:   rs.plan.fields(),
+		Fields:   fields,
 		Pkfields: pkfields,
 		Gtid:     gtid,
 	})
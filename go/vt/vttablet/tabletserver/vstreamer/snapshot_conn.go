@@ -65,7 +65,7 @@ func snapshotConnect(ctx context.Context, cp dbconfigs.Connector) (*snapshotConn
 
 // startSnapshot starts a streaming query with a snapshot view of the specified table.
 // It returns the GTID set from the time when the snapshot was taken.
-func (conn *snapshotConn) streamWithSnapshot(ctx context.Context, table, query string) (gtid string, rotatedLog bool, err error) {
+func (conn *snapshotConn) streamWithSnapshot(ctx context.Context, tables []string, query string) (gtid string, rotatedLog bool, err error) {
 	// Rotate the binary log if needed to limit the GTID auto positioning overhead.
 	// This may be needed as the currently open binary log (which can be up to 1G in
 	// size by default) will need to be scanned and empty events will be streamed for
@@ -82,7 +82,7 @@ func (conn *snapshotConn) streamWithSnapshot(ctx context.Context, table, query s
 			query, err)
 	}
 
-	gtid, err = conn.startSnapshot(ctx, table)
+	gtid, err = conn.startSnapshot(ctx, tables...)
 	if err != nil {
 		return "", rotatedLog, err
 	}
@@ -93,7 +93,7 @@ func (conn *snapshotConn) streamWithSnapshot(ctx context.Context, table, query s
 }
 
 // snapshot performs the snapshotting.
-func (conn *snapshotConn) startSnapshot(ctx context.Context, table string) (gtid string, err error) {
+func (conn *snapshotConn) startSnapshot(ctx context.Context, tables ...string) (gtid string, err error) {
 	lockConn, err := mysqlConnect(ctx, conn.cp)
 	if err != nil {
 		return "", err
@@ -102,14 +102,20 @@ func (conn *snapshotConn) startSnapshot(ctx context.Context, table string) (gtid
 	defer func() {
 		_, err := lockConn.ExecuteFetch("unlock tables", 0, false)
 		if err != nil {
-			log.Warning("Unlock tables (%s) failed: %v", table, err)
+			log.Warning("Unlock tables (%s) failed: %v", strings.Join(tables, ", "), err)
 		}
 		lockConn.Close()
 	}()
 
-	tableName := sqlparser.String(sqlparser.NewIdentifierCS(table))
+	// All the tables of a join are locked together so that the snapshot
+	// is consistent with the position.
+	lockedTables := make([]string, 0, len(tables))
+	for _, table := range tables {
+		lockedTables = append(lockedTables, sqlparser.String(sqlparser.NewIdentifierCS(table))+" read")
+	}
+	tableName := strings.Join(lockedTables, ", ")
 
-	if _, err := lockConn.ExecuteFetch(fmt.Sprintf("lock tables %s read", tableName), 1, false); err != nil {
+	if _, err := lockConn.ExecuteFetch(fmt.Sprintf("lock tables %s", tableName), 1, false); err != nil {
 		log.Warningf("Error locking table %s to read: %v", tableName, err)
 		return "", err
 	}
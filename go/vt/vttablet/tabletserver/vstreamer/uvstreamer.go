@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -171,6 +172,9 @@ func (uvs *uvstreamer) buildTablePlan() error {
 		if rule == nil {
 			continue
 		}
+		if slices.Contains(uvs.options.GetCopiedTables(), tableName) {
+			continue
+		}
 		plan := &tablePlan{
 			tablePK: nil,
 			rule: &binlogdatapb.Rule{
@@ -404,8 +408,9 @@ func (uvs *uvstreamer) currentPosition() (replication.Position, error) {
 // 2. TablePKs nil, startPos empty => full table copy of tables matching filter
 // 3. TablePKs not nil, startPos empty => table copy (for pks > lastPK)
 // 4. TablePKs not nil, startPos set => run catchup from startPos, then table copy  (for pks > lastPK)
+// In the last two cases, the tables listed in the CopiedTables option are not copied again.
 func (uvs *uvstreamer) init() error {
-	if uvs.startPos == "" /* full copy */ || len(uvs.inTablePKs) > 0 /* resume copy */ {
+	if uvs.startPos == "" /* full copy */ || len(uvs.inTablePKs) > 0 || len(uvs.options.GetCopiedTables()) > 0 /* resume copy */ {
 		if err := uvs.buildTablePlan(); err != nil {
 			return err
 		}
@@ -437,6 +442,11 @@ func (uvs *uvstreamer) Stream() error {
 		if err := uvs.allCopyComplete(); err != nil {
 			return err
 		}
+	} else if len(uvs.options.GetCopiedTables()) > 0 {
+		// The copy of every table already completed.
+		if err := uvs.allCopyComplete(); err != nil {
+			return err
+		}
 	}
 	vs := newVStreamer(uvs.ctx, uvs.cp, uvs.se, replication.EncodePosition(uvs.pos), replication.EncodePosition(uvs.stopPos),
 		uvs.filter, uvs.getVSchema(), uvs.throttlerApp, uvs.send, "replicate", uvs.vse, uvs.options)
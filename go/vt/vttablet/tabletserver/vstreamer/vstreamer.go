@@ -88,6 +88,10 @@ type vstreamer struct {
 	vse     *Engine
 	options *binlogdatapb.VStreamOptions
 	config  *vttablet.VReplicationConfig
+
+	// joinConn is used to look up the joined rows of join filters.
+	// It's opened on first use.
+	joinConn *mysql.Conn
 }
 
 // streamerPlan extends the original plan to also include
@@ -203,6 +207,12 @@ func (vs *vstreamer) replicate(ctx context.Context) error {
 		return wrapError(err, vs.pos, vs.vse)
 	}
 	defer conn.Close()
+	defer func() {
+		if vs.joinConn != nil {
+			vs.joinConn.Close()
+			vs.joinConn = nil
+		}
+	}()
 
 	events, errs, err := conn.StartBinlogDumpFromPosition(vs.ctx, "", vs.pos)
 	if err != nil {
@@ -814,6 +824,9 @@ func (vs *vstreamer) buildTablePlan(id uint64, tm *mysql.TableMap) (*binlogdatap
 		vs.plans[id] = nil
 		return nil, nil
 	}
+	if err := vs.initJoinPlan(plan); err != nil {
+		return nil, err
+	}
 	if err := addEnumAndSetMappingstoPlan(vs.se.Environment(), plan, cols, tm.Metadata); err != nil {
 		return nil, vterrors.Wrapf(err, "failed to build ENUM and SET column integer to string mappings")
 	}
@@ -1013,6 +1026,9 @@ nextrow:
 }
 
 func (vs *vstreamer) processRowEvent(vevents []*binlogdatapb.VEvent, plan *streamerPlan, rows mysql.Rows) ([]*binlogdatapb.VEvent, error) {
+	if plan.join != nil {
+		return vs.processJoinRowEvent(vevents, plan, rows)
+	}
 	rowChanges := make([]*binlogdatapb.RowChange, 0, len(rows.Rows))
 	for _, row := range rows.Rows {
 		// The BEFORE image does not have partial JSON values so we pass an empty bitmap.
@@ -1068,6 +1084,106 @@ func (vs *vstreamer) processRowEvent(vevents []*binlogdatapb.VEvent, plan *strea
 	return vevents, nil
 }
 
+// processJoinRowEvent converts the row changes of a table of a join filter
+// into changes of the joined rows. See joinPlan for details.
+func (vs *vstreamer) processJoinRowEvent(vevents []*binlogdatapb.VEvent, plan *streamerPlan, rows mysql.Rows) ([]*binlogdatapb.VEvent, error) {
+	images := make([][]sqltypes.Value, 0, 2*len(rows.Rows))
+	for _, row := range rows.Rows {
+		beforeOK, beforeValues, _, err := vs.extractRowAndFilter(plan, row.Identify, rows.IdentifyColumns, row.NullIdentifyColumns, mysql.Bitmap{})
+		if err != nil {
+			return nil, vterrors.Wrap(err, "failed to extract row's before values from binlog event")
+		}
+		if beforeOK {
+			images = append(images, beforeValues)
+		}
+		afterOK, afterValues, _, err := vs.extractRowAndFilter(plan, row.Data, rows.DataColumns, row.NullColumns, row.JSONPartialValues)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "failed to extract row's after values from binlog event")
+		}
+		if afterOK {
+			images = append(images, afterValues)
+		}
+	}
+	keys := plan.join.keys(images)
+	if len(keys) == 0 {
+		return vevents, nil
+	}
+	query, err := plan.join.lookupQuery(plan.join.table, keys)
+	if err != nil {
+		return nil, err
+	}
+	if err := vs.openJoinConn(); err != nil {
+		return nil, err
+	}
+	lookup, err := vs.joinConn.ExecuteFetch(query, joinLookupMaxRows, false)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to look up the joined rows of table %s", plan.Table.Name)
+	}
+	rowChanges, err := plan.join.rowChanges(keys, lookup)
+	if err != nil {
+		return nil, err
+	}
+	vevents = append(vevents, &binlogdatapb.VEvent{
+		Type: binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{
+			TableName:  plan.Table.Name,
+			RowChanges: rowChanges,
+			Keyspace:   vs.vse.keyspace,
+			Shard:      vs.vse.shard,
+			Flags:      uint32(rows.Flags),
+		},
+	})
+	return vevents, nil
+}
+
+// initJoinPlan completes the plan of a table of a join filter with the
+// primary key of the table and the fields of the join.
+func (vs *vstreamer) initJoinPlan(plan *Plan) error {
+	if plan.join == nil {
+		return nil
+	}
+	st, err := vs.se.GetTableForPos(vs.ctx, sqlparser.NewIdentifierCS(plan.Table.Name), replication.EncodePosition(vs.pos))
+	if err != nil {
+		return err
+	}
+	var pkNames []string
+	plan.join.pkColumns = nil
+	for _, pk := range st.PKColumns {
+		if pk >= int64(len(plan.Table.Fields)) {
+			return fmt.Errorf("primary key %d refers to non-existent column", pk)
+		}
+		pkNames = append(pkNames, plan.Table.Fields[pk].Name)
+		plan.join.pkColumns = append(plan.join.pkColumns, int(pk))
+	}
+	if err := plan.join.setPrimaryKey(plan.Table.Name, pkNames); err != nil {
+		return err
+	}
+	if err := vs.openJoinConn(); err != nil {
+		return err
+	}
+	qr, err := vs.joinConn.ExecuteFetch(plan.join.fieldsQuery(), 1, true)
+	if err != nil {
+		return vterrors.Wrapf(err, "failed to get the fields of the join of table %s", plan.Table.Name)
+	}
+	return plan.join.setFields(qr.Fields)
+}
+
+func (vs *vstreamer) openJoinConn() error {
+	if vs.joinConn != nil {
+		return nil
+	}
+	conn, err := mysqlConnect(vs.ctx, vs.cp)
+	if err != nil {
+		return err
+	}
+	if err := initJoinLookupConn(conn); err != nil {
+		conn.Close()
+		return err
+	}
+	vs.joinConn = conn
+	return nil
+}
+
 func (vs *vstreamer) rebuildPlans() error {
 	for id, plan := range vs.plans {
 		if plan == nil {
@@ -1082,6 +1198,9 @@ func (vs *vstreamer) rebuildPlans() error {
 		if newPlan == nil {
 			continue
 		}
+		if err := vs.initJoinPlan(newPlan); err != nil {
+			return err
+		}
 		vs.plans[id] = &streamerPlan{
 			Plan:     newPlan,
 			TableMap: plan.TableMap,
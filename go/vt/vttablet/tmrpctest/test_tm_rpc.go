@@ -1503,6 +1503,35 @@ func tmRPCTestRestoreFromBackupPanic(ctx context.Context, t *testing.T, client t
 	expectHandleRPCPanic(t, "RestoreFromBackup", true /*verbose*/, err)
 }
 
+var testBackupProgress = &tabletmanagerdatapb.BackupProgress{
+	Operation:  "restore",
+	Phase:      "copying files",
+	Running:    true,
+	BytesTotal: 200,
+	BytesDone:  50,
+	Files: []*tabletmanagerdatapb.BackupFileProgress{
+		{Name: "ibdata1", BytesTotal: 100, BytesDone: 50},
+		{Name: "ib_logfile0", BytesTotal: 100},
+	},
+}
+
+func (fra *fakeRPCTM) GetBackupProgress(ctx context.Context) (*tabletmanagerdatapb.BackupProgress, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	return testBackupProgress, nil
+}
+
+func tmRPCTestGetBackupProgress(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	progress, err := client.GetBackupProgress(ctx, tablet)
+	compareError(t, "GetBackupProgress", err, progress, testBackupProgress)
+}
+
+func tmRPCTestGetBackupProgressPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.GetBackupProgress(ctx, tablet)
+	expectHandleRPCPanic(t, "GetBackupProgress", false /*verbose*/, err)
+}
+
 func tmRPCTestCheckThrottler(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CheckThrottlerRequest) {
 	_, err := client.CheckThrottler(ctx, tablet, req)
 	expectHandleRPCPanic(t, "CheckThrottler", false /*verbose*/, err)
@@ -1596,6 +1625,7 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	// Backup / restore related methods
 	tmRPCTestBackup(ctx, t, client, tablet)
 	tmRPCTestRestoreFromBackup(ctx, t, client, tablet, restoreFromBackupRequest)
+	tmRPCTestGetBackupProgress(ctx, t, client, tablet)
 
 	// Throttler related methods
 	tmRPCTestCheckThrottler(ctx, t, client, tablet, checkThrottlerRequest)
@@ -1657,6 +1687,7 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	// Backup / restore related methods
 	tmRPCTestBackupPanic(ctx, t, client, tablet)
 	tmRPCTestRestoreFromBackupPanic(ctx, t, client, tablet, restoreFromBackupRequest)
+	tmRPCTestGetBackupProgressPanic(ctx, t, client, tablet)
 
 	client.Close()
 }
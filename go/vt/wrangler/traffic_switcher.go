@@ -1603,30 +1603,34 @@ func (ts *trafficSwitcher) changeShardRouting(ctx context.Context) error {
 		log.Errorf("%w", err2)
 		return err2
 	}
-	err := ts.ForAllSources(func(source *workflow.MigrationSource) error {
-		_, err := ts.TopoServer().UpdateShardFields(ctx, ts.SourceKeyspaceName(), source.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-			si.IsPrimaryServing = false
-			return nil
-		})
-		return err
-	})
-	if err != nil {
+	if err := topo.CheckKeyspaceLocked(ctx, ts.TargetKeyspaceName()); err != nil {
 		return err
 	}
-	err = ts.ForAllTargets(func(target *workflow.MigrationTarget) error {
-		_, err := ts.TopoServer().UpdateShardFields(ctx, ts.TargetKeyspaceName(), target.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-			si.IsPrimaryServing = true
-			return nil
-		})
-		return err
+	// The shard records and the SrvKeyspaces are updated in a single
+	// transaction, so that a failure can't leave both the source and the
+	// target shards serving, or neither of them.
+	err := ts.TopoServer().RetryTxn(ctx, func(txn *topo.Txn) error {
+		for _, source := range ts.SourceShards() {
+			if _, err := txn.UpdateShardFields(ctx, ts.SourceKeyspaceName(), source.ShardName(), func(si *topo.ShardInfo) error {
+				si.IsPrimaryServing = false
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		for _, target := range ts.TargetShards() {
+			if _, err := txn.UpdateShardFields(ctx, ts.TargetKeyspaceName(), target.ShardName(), func(si *topo.ShardInfo) error {
+				si.IsPrimaryServing = true
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return txn.MigrateServedType(ctx, ts.TargetKeyspaceName(), ts.TargetShards(), ts.SourceShards(), topodatapb.TabletType_PRIMARY, nil)
 	})
 	if err != nil {
 		return err
 	}
-	err = ts.TopoServer().MigrateServedType(ctx, ts.TargetKeyspaceName(), ts.TargetShards(), ts.SourceShards(), topodatapb.TabletType_PRIMARY, nil)
-	if err != nil {
-		return err
-	}
 	if err := ts.TopoServer().ValidateSrvKeyspace(ctx, ts.TargetKeyspaceName(), ""); err != nil {
 		err2 := vterrors.Wrapf(err, "After changing shard routes, found SrvKeyspace for %s is corrupt", ts.TargetKeyspaceName())
 		log.Errorf("%w", err2)
@@ -771,6 +771,7 @@ func expectReshardQueries(t *testing.T, tme *testShardMigraterEnv, params *VRepl
 		dbclient.addInvariant("delete from _vt.vreplication where id in (1)", noResult)
 		dbclient.addInvariant("delete from _vt.copy_state where vrepl_id in (1)", noResult)
 		dbclient.addInvariant("delete from _vt.post_copy_action where vrepl_id in (1)", noResult)
+		dbclient.addInvariant("delete from _vt.copy_range_state where vrepl_id in (1)", noResult)
 		dbclient.addInvariant("insert into _vt.vreplication (workflow, source, pos, max_tps, max_replication_lag, time_updated, transaction_timestamp, state, db_name, workflow_type, workflow_sub_type, options)", &sqltypes.Result{InsertID: uint64(1)})
 		dbclient.addInvariant("select id from _vt.vreplication where id = 1", resultid1)
 		dbclient.addInvariant("select id from _vt.vreplication where id = 2", resultid2)
@@ -805,6 +806,7 @@ func expectReshardQueries(t *testing.T, tme *testShardMigraterEnv, params *VRepl
 		dbclient.addInvariant("delete from _vt.vreplication where id in (1)", noResult)
 		dbclient.addInvariant("delete from _vt.copy_state where vrepl_id in (1)", noResult)
 		dbclient.addInvariant("delete from _vt.post_copy_action where vrepl_id in (1)", noResult)
+		dbclient.addInvariant("delete from _vt.copy_range_state where vrepl_id in (1)", noResult)
 	}
 	tme.tmeDB.AddQuery("USE `vt_ks`", noResult)
 	tme.tmeDB.AddQuery("select distinct table_name from _vt.copy_state cs, _vt.vreplication vr where vr.id = cs.vrepl_id and vr.id = 1", noResult)
@@ -845,6 +847,7 @@ func expectMoveTablesQueries(t *testing.T, tme *testMigraterEnv, params *VReplic
 		dbclient.addInvariant("delete from _vt.vreplication where id in (1)", noResult)
 		dbclient.addInvariant("delete from _vt.copy_state where vrepl_id in (1)", noResult)
 		dbclient.addInvariant("delete from _vt.post_copy_action where vrepl_id in (1)", noResult)
+		dbclient.addInvariant("delete from _vt.copy_range_state where vrepl_id in (1)", noResult)
 		dbclient.addInvariant("insert into _vt.resharding_journal", noResult)
 		dbclient.addInvariant("select val from _vt.resharding_journal", noResult)
 		dbclient.addInvariant("select id, source, message, cell, tablet_types from _vt.vreplication where workflow='test_reverse' and db_name='vt_ks1'",
@@ -867,6 +870,7 @@ func expectMoveTablesQueries(t *testing.T, tme *testMigraterEnv, params *VReplic
 		dbclient.addInvariant("delete from _vt.vreplication where id in (1)", noResult)
 		dbclient.addInvariant("delete from _vt.copy_state where vrepl_id in (1)", noResult)
 		dbclient.addInvariant("delete from _vt.post_copy_action where vrepl_id in (1)", noResult)
+		dbclient.addInvariant("delete from _vt.copy_range_state where vrepl_id in (1)", noResult)
 		dbclient.addInvariant("insert into _vt.vreplication (workflow, source, pos, max_tps, max_replication_lag, time_updated, transaction_timestamp, state, db_name, workflow_type, workflow_sub_type, options)", &sqltypes.Result{InsertID: uint64(1)})
 		dbclient.addInvariant("select * from _vt.vreplication where id = 1", runningResult(1))
 		dbclient.addInvariant("select * from _vt.vreplication where id = 2", runningResult(2))